@@ -0,0 +1,59 @@
+# Writes the result of each task on each host as a JSON line to the file named by
+# TALIS_ANSIBLE_EVENTS_FILE. Talis reads the file to report which task failed on which host.
+from __future__ import absolute_import, division, print_function
+
+__metaclass__ = type
+
+DOCUMENTATION = """
+    name: talis_events
+    type: notification
+    short_description: Writes the task results per host as JSON lines for Talis
+    description:
+      - Writes the result of each task on each host as a JSON line to the file named by the
+        TALIS_ANSIBLE_EVENTS_FILE environment variable. Nothing is written if it is not set.
+"""
+
+import json
+import os
+
+from ansible.plugins.callback import CallbackBase
+
+
+class CallbackModule(CallbackBase):
+    CALLBACK_VERSION = 2.0
+    CALLBACK_TYPE = "notification"
+    CALLBACK_NAME = "talis_events"
+    CALLBACK_NEEDS_WHITELIST = True
+    CALLBACK_NEEDS_ENABLED = True
+
+    def __init__(self):
+        super(CallbackModule, self).__init__()
+        self._path = os.environ.get("TALIS_ANSIBLE_EVENTS_FILE")
+
+    def _write(self, result, status, failed=False):
+        if not self._path:
+            return
+        event = {
+            "task": result._task.get_name(),
+            "host": result._host.get_name(),
+            "status": status,
+        }
+        if failed:
+            res = result._result
+            message = res.get("msg") or res.get("stderr") or res.get("reason")
+            if message:
+                event["message"] = str(message).strip()
+        with open(self._path, "a") as f:
+            f.write(json.dumps(event) + "\n")
+
+    def v2_runner_on_ok(self, result):
+        self._write(result, "changed" if result._result.get("changed") else "ok")
+
+    def v2_runner_on_failed(self, result, ignore_errors=False):
+        self._write(result, "ignored" if ignore_errors else "failed", failed=True)
+
+    def v2_runner_on_unreachable(self, result):
+        self._write(result, "unreachable", failed=True)
+
+    def v2_runner_on_skipped(self, result):
+        self._write(result, "skipped")
@@ -10,12 +10,23 @@ import (
 	"time"
 
 	"github.com/celestiaorg/talis/internal/types"
+	"github.com/celestiaorg/talis/pkg/db/models"
 	"github.com/spf13/cobra"
 )
 
+// Import flag names
+const (
+	flagImportProvider = "provider"
+	flagImportProject  = "project"
+	flagImportIDs      = "ids"
+	flagImportTag      = "tag"
+)
+
 func init() {
 	infraCmd.AddCommand(createInfraCmd)
 	infraCmd.AddCommand(deleteInfraCmd)
+	infraCmd.AddCommand(importInfraCmd)
+	infraCmd.AddCommand(payloadInfraCmd)
 
 	// Add flags for create command
 	createInfraCmd.Flags().StringP("file", "f", "", "JSON file containing infrastructure configuration")
@@ -24,6 +35,30 @@ func init() {
 	// Add flags for delete command
 	deleteInfraCmd.Flags().StringP("file", "f", "", "JSON file containing infrastructure configuration")
 	_ = deleteInfraCmd.MarkFlagRequired("file")
+
+	// Add flags for import command
+	addImportInfraFlags(importInfraCmd)
+
+	// Add flags for payload command
+	addPayloadInfraFlags(payloadInfraCmd)
+}
+
+// addImportInfraFlags adds the flags of the import command
+func addImportInfraFlags(cmd *cobra.Command) {
+	cmd.Flags().String(flagImportProvider, "", "Provider of the instances (e.g. do, hetzner, aws)")
+	cmd.Flags().StringP(flagImportProject, "p", "", "Project to import the instances into")
+	cmd.Flags().StringSlice(flagImportIDs, nil, "Comma separated provider instance IDs to import")
+	cmd.Flags().String(flagImportTag, "", "Import all instances carrying this provider tag")
+	_ = cmd.MarkFlagRequired(flagImportProvider)
+	_ = cmd.MarkFlagRequired(flagImportProject)
+	cmd.MarkFlagsOneRequired(flagImportIDs, flagImportTag)
+	cmd.MarkFlagsMutuallyExclusive(flagImportIDs, flagImportTag)
+}
+
+// addPayloadInfraFlags adds the flags of the payload command
+func addPayloadInfraFlags(cmd *cobra.Command) {
+	cmd.Flags().UintP(flagInstanceID, "I", 0, "Instance ID to get the payload of")
+	_ = cmd.MarkFlagRequired(flagInstanceID)
 }
 
 var infraCmd = &cobra.Command{
@@ -143,6 +178,94 @@ var deleteInfraCmd = &cobra.Command{
 	},
 }
 
+var importInfraCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import existing provider instances into a project",
+	Long: `Import machines that were created outside of Talis so they can be managed by Talis.
+Instances are selected either by their provider instance IDs or by a provider tag.
+Their IP, region, size and volumes are read from the provider.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		provider, err := cmd.Flags().GetString(flagImportProvider)
+		if err != nil {
+			return fmt.Errorf("error getting provider flag: %w", err)
+		}
+		projectName, err := cmd.Flags().GetString(flagImportProject)
+		if err != nil {
+			return fmt.Errorf("error getting project flag: %w", err)
+		}
+		ids, err := cmd.Flags().GetStringSlice(flagImportIDs)
+		if err != nil {
+			return fmt.Errorf("error getting ids flag: %w", err)
+		}
+		tag, err := cmd.Flags().GetString(flagImportTag)
+		if err != nil {
+			return fmt.Errorf("error getting tag flag: %w", err)
+		}
+
+		ownerID, err := getOwnerID(cmd)
+		if err != nil {
+			return fmt.Errorf("error getting owner_id: %w", err)
+		}
+
+		req := types.ImportInstancesRequest{
+			OwnerID:             ownerID,
+			ProjectName:         projectName,
+			Provider:            models.ProviderID(provider),
+			ProviderInstanceIDs: ids,
+			Tag:                 tag,
+		}
+		if err := req.Validate(); err != nil {
+			return fmt.Errorf("invalid import request: %w", err)
+		}
+
+		importedInstances, err := apiClient.ImportInstances(context.Background(), req)
+		if err != nil {
+			return fmt.Errorf("error importing infrastructure: %w", err)
+		}
+
+		if len(importedInstances) == 0 {
+			fmt.Println("No instances to import, all matching instances are already managed.")
+			return nil
+		}
+
+		prettyJSON, err := json.MarshalIndent(importedInstances, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error formatting response: %w", err)
+		}
+		fmt.Printf("Successfully imported %d instance(s):\n", len(importedInstances))
+		fmt.Println(string(prettyJSON))
+		return nil
+	},
+}
+
+var payloadInfraCmd = &cobra.Command{
+	Use:   "payload",
+	Short: "Show the payload status and output of an instance",
+	Long: `Show the status of the payload of an instance as it is copied and executed.
+Once the payload script ran, its exit code and the end of its standard output and standard
+error are shown as well.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		instanceID, err := cmd.Flags().GetUint(flagInstanceID)
+		if err != nil {
+			return fmt.Errorf("error getting instance-id flag: %w", err)
+		}
+
+		payload, err := apiClient.GetInstancePayload(context.Background(), instanceID)
+		if err != nil {
+			return fmt.Errorf("error getting payload of instance %d: %w", instanceID, err)
+		}
+
+		fmt.Printf("Payload status of instance %d: %s\n", instanceID, payload.Status)
+		if payload.ExitCode == nil {
+			return nil
+		}
+		fmt.Printf("Exit code: %d\n", *payload.ExitCode)
+		fmt.Printf("--- stdout ---\n%s\n", payload.Stdout)
+		fmt.Printf("--- stderr ---\n%s\n", payload.Stderr)
+		return nil
+	},
+}
+
 // GetInfraCmd returns the infrastructure command
 func GetInfraCmd() *cobra.Command {
 	return infraCmd
@@ -16,6 +16,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/celestiaorg/talis/internal/db/models"
 	"github.com/celestiaorg/talis/internal/types"
 	"github.com/celestiaorg/talis/pkg/api/v1/handlers"
 	"github.com/celestiaorg/talis/test"
@@ -27,6 +28,7 @@ func setupInfraCommand() *cobra.Command {
 		Use:   "talis",
 		Short: "Talis CLI tool",
 	}
+	cmd.PersistentFlags().StringP(flagOwnerID, "o", "", "Owner ID for resources")
 
 	// Add the infra command and its subcommands
 	infraCmd := &cobra.Command{
@@ -49,6 +51,18 @@ func setupInfraCommand() *cobra.Command {
 	_ = deleteCmd.MarkFlagRequired("file")
 	infraCmd.AddCommand(deleteCmd)
 
+	// Add import command
+	importCmd := importInfraCmd
+	importCmd.ResetFlags()
+	addImportInfraFlags(importCmd)
+	infraCmd.AddCommand(importCmd)
+
+	// Add payload command
+	payloadCmd := payloadInfraCmd
+	payloadCmd.ResetFlags()
+	addPayloadInfraFlags(payloadCmd)
+	infraCmd.AddCommand(payloadCmd)
+
 	return cmd
 }
 
@@ -387,3 +401,171 @@ func TestDeleteInfraCmd(t *testing.T) {
 		})
 	}
 }
+
+func TestImportInfraCmd(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		expectedOutput string
+		expectedError  string
+	}{
+		{
+			name:           "successful import",
+			args:           []string{"infra", "import", "--provider", "do-mock", "--project", "test-project", "--ids", "1", "--owner-id", "1"},
+			expectedOutput: "Successfully imported 1 instance(s)",
+		},
+		{
+			name:          "missing provider flag",
+			args:          []string{"infra", "import", "--project", "test-project", "--ids", "1", "--owner-id", "1"},
+			expectedError: "required flag(s) \"provider\" not set",
+		},
+		{
+			name:          "missing ids and tag",
+			args:          []string{"infra", "import", "--provider", "do-mock", "--project", "test-project", "--owner-id", "1"},
+			expectedError: "at least one of the flags in the group [ids tag] is required",
+		},
+		{
+			name:          "ids and tag",
+			args:          []string{"infra", "import", "--provider", "do-mock", "--project", "test-project", "--ids", "1", "--tag", "testnet", "--owner-id", "1"},
+			expectedError: "if any flags in the group [ids tag] are set none of the others can be",
+		},
+		{
+			name:          "missing owner id",
+			args:          []string{"infra", "import", "--provider", "do-mock", "--project", "test-project", "--ids", "1"},
+			expectedError: "error getting owner_id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suite := test.NewSuite(t)
+			defer suite.Cleanup()
+
+			originalClient := apiClient
+			apiClient = suite.APIClient
+			defer func() { apiClient = originalClient }()
+
+			_, err := suite.APIClient.CreateProject(context.Background(), handlers.ProjectCreateParams{
+				Name:        "test-project",
+				Description: "Test project for infra commands",
+				OwnerID:     1,
+			})
+			require.NoError(t, err)
+
+			// Capture the command output
+			buf := new(bytes.Buffer)
+			originalStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = io.Copy(buf, r)
+			}()
+
+			cmd := setupInfraCommand()
+			cmd.SetArgs(tt.args)
+			err = cmd.Execute()
+
+			_ = w.Close()
+			os.Stdout = originalStdout
+			wg.Wait()
+			_ = r.Close()
+
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, buf.String(), tt.expectedOutput)
+		})
+	}
+}
+
+func TestPayloadInfraCmd(t *testing.T) {
+	suite := test.NewSuite(t)
+	defer suite.Cleanup()
+
+	originalClient := apiClient
+	apiClient = suite.APIClient
+	defer func() { apiClient = originalClient }()
+
+	// Payloads are only run by the workers, the instance and its payload output are stored directly
+	instance, err := suite.InstanceRepo.Create(context.Background(), &models.Instance{
+		OwnerID:       models.AdminID,
+		ProviderID:    models.ProviderDO,
+		Status:        models.InstanceStatusReady,
+		PayloadStatus: models.PayloadStatusExecuted,
+	})
+	require.NoError(t, err)
+	exitCode := 0
+	require.NoError(t, suite.InstanceRepo.SavePayload(context.Background(), &models.InstancePayload{
+		InstanceID: instance.ID, OwnerID: instance.OwnerID, ExitCode: &exitCode, Stdout: "node started",
+	}))
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedOutput []string
+		expectedError  string
+	}{
+		{
+			name: "executed payload",
+			args: []string{"infra", "payload", "--instance-id", fmt.Sprint(instance.ID)},
+			expectedOutput: []string{
+				fmt.Sprintf("Payload status of instance %d: executed", instance.ID),
+				"Exit code: 0",
+				"--- stdout ---\nnode started",
+			},
+		},
+		{
+			name:          "missing instance id",
+			args:          []string{"infra", "payload"},
+			expectedError: "required flag(s) \"instance-id\" not set",
+		},
+		{
+			name:          "unknown instance",
+			args:          []string{"infra", "payload", "--instance-id", "99999"},
+			expectedError: "error getting payload of instance 99999",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Capture the command output
+			buf := new(bytes.Buffer)
+			originalStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = io.Copy(buf, r)
+			}()
+
+			cmd := setupInfraCommand()
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+
+			_ = w.Close()
+			os.Stdout = originalStdout
+			wg.Wait()
+			_ = r.Close()
+
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			for _, expected := range tt.expectedOutput {
+				assert.Contains(t, buf.String(), expected)
+			}
+		})
+	}
+}
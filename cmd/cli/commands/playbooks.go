@@ -0,0 +1,161 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/celestiaorg/talis/pkg/api/v1/handlers"
+	"github.com/celestiaorg/talis/pkg/db/models"
+)
+
+// Playbook flag names
+const (
+	flagPlaybookName        = "name"
+	flagPlaybookDir         = "dir"
+	flagPlaybookDescription = "description"
+)
+
+func init() {
+	playbooksCmd.AddCommand(uploadPlaybookCmd)
+	playbooksCmd.AddCommand(listPlaybooksCmd)
+	playbooksCmd.AddCommand(deletePlaybookCmd)
+
+	uploadPlaybookCmd.Flags().StringP(flagPlaybookName, "n", "", "Name instance requests reference the playbook by")
+	uploadPlaybookCmd.Flags().StringP(flagPlaybookDir, "d", "", "Directory of the playbook, with its main.yml and roles")
+	uploadPlaybookCmd.Flags().String(flagPlaybookDescription, "", "Description of the playbook")
+	_ = uploadPlaybookCmd.MarkFlagRequired(flagPlaybookName)
+	_ = uploadPlaybookCmd.MarkFlagRequired(flagPlaybookDir)
+
+	deletePlaybookCmd.Flags().StringP(flagPlaybookName, "n", "", "Name of the playbook to delete")
+	_ = deletePlaybookCmd.MarkFlagRequired(flagPlaybookName)
+}
+
+var playbooksCmd = &cobra.Command{
+	Use:   "playbooks",
+	Short: "Manage provisioning playbooks",
+	Long: `Manage the Ansible playbooks instances are provisioned with.
+Instance requests reference a playbook by name with "playbook", and can select its tags with
+"playbook_tags" and pass it variables with "extra_vars".`,
+}
+
+// GetPlaybooksCmd returns the playbooks command
+func GetPlaybooksCmd() *cobra.Command {
+	return playbooksCmd
+}
+
+var uploadPlaybookCmd = &cobra.Command{
+	Use:   "upload",
+	Short: "Upload a playbook",
+	Long:  "Upload the files of a playbook directory, which must have a main.yml, for the owner",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		name, _ := cmd.Flags().GetString(flagPlaybookName)
+		dir, _ := cmd.Flags().GetString(flagPlaybookDir)
+		description, _ := cmd.Flags().GetString(flagPlaybookDescription)
+
+		ownerID, err := getOwnerID(cmd)
+		if err != nil {
+			return fmt.Errorf("error getting owner_id: %w", err)
+		}
+
+		files, err := readPlaybookDir(dir)
+		if err != nil {
+			return err
+		}
+
+		playbook, err := apiClient.CreatePlaybook(context.Background(), handlers.PlaybookCreateParams{
+			Name:        name,
+			Description: description,
+			Files:       files,
+			OwnerID:     ownerID,
+		})
+		if err != nil {
+			return fmt.Errorf("error uploading playbook: %w", err)
+		}
+
+		fmt.Printf("Playbook %s uploaded with %d files\n", playbook.Name, len(files))
+		return nil
+	},
+}
+
+// readPlaybookDir reads the regular files of a playbook directory by their slash separated
+// path relative to the directory
+func readPlaybookDir(dir string) (models.PlaybookFiles, error) {
+	files := models.PlaybookFiles{}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		// #nosec G304 -- the path is inside the playbook directory given by the user
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = string(content)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading playbook directory: %w", err)
+	}
+	if err := files.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid playbook directory %s: %w", dir, err)
+	}
+	return files, nil
+}
+
+var listPlaybooksCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List playbooks",
+	Long:  "List the playbooks registered on the server and the playbooks uploaded by the owner",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ownerID, err := getOwnerID(cmd)
+		if err != nil {
+			return fmt.Errorf("error getting owner_id: %w", err)
+		}
+
+		playbooks, err := apiClient.ListPlaybooks(context.Background(), handlers.PlaybookListParams{OwnerID: ownerID})
+		if err != nil {
+			return fmt.Errorf("error listing playbooks: %w", err)
+		}
+
+		prettyJSON, err := json.MarshalIndent(playbooks, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error formatting response: %w", err)
+		}
+		fmt.Println(string(prettyJSON))
+		return nil
+	},
+}
+
+var deletePlaybookCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete an uploaded playbook",
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		name, _ := cmd.Flags().GetString(flagPlaybookName)
+
+		ownerID, err := getOwnerID(cmd)
+		if err != nil {
+			return fmt.Errorf("error getting owner_id: %w", err)
+		}
+
+		err = apiClient.DeletePlaybook(context.Background(), handlers.PlaybookDeleteParams{Name: name, OwnerID: ownerID})
+		if err != nil {
+			return fmt.Errorf("error deleting playbook: %w", err)
+		}
+
+		fmt.Printf("Playbook %s deleted\n", name)
+		return nil
+	},
+}
@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/celestiaorg/talis/pkg/db/models"
+)
+
+// Provider flag names
+const (
+	flagCatalogProvider = "provider"
+)
+
+func init() {
+	providersCmd.AddCommand(listProviderRegionsCmd)
+	providersCmd.AddCommand(listProviderSizesCmd)
+	providersCmd.AddCommand(listProviderImagesCmd)
+
+	addProviderCatalogFlags(providersCmd)
+}
+
+// addProviderCatalogFlags adds the flags shared by the provider catalog commands
+func addProviderCatalogFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String(flagCatalogProvider, "", "Provider to list the catalog of (e.g. do, hetzner, linode, vultr, aws, ximera)")
+	_ = cmd.MarkPersistentFlagRequired(flagCatalogProvider)
+}
+
+var providersCmd = &cobra.Command{
+	Use:   "providers",
+	Short: "Browse the regions, sizes and images offered by compute providers",
+}
+
+// GetProvidersCmd returns the providers command
+func GetProvidersCmd() *cobra.Command {
+	return providersCmd
+}
+
+var listProviderRegionsCmd = &cobra.Command{
+	Use:   "regions",
+	Short: "List the regions of a provider",
+	Long:  `List the values accepted as instance region. An empty list means the provider accepts any region.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		provider, err := getCatalogProvider(cmd)
+		if err != nil {
+			return err
+		}
+
+		regions, err := apiClient.ListProviderRegions(context.Background(), provider)
+		if err != nil {
+			return fmt.Errorf("error listing regions: %w", err)
+		}
+		return printCatalog(regions)
+	},
+}
+
+var listProviderSizesCmd = &cobra.Command{
+	Use:   "sizes",
+	Short: "List the instance sizes of a provider",
+	Long:  `List the values accepted as instance size, with their CPU, memory, disk and the regions they are offered in.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		provider, err := getCatalogProvider(cmd)
+		if err != nil {
+			return err
+		}
+
+		sizes, err := apiClient.ListProviderSizes(context.Background(), provider)
+		if err != nil {
+			return fmt.Errorf("error listing sizes: %w", err)
+		}
+		return printCatalog(sizes)
+	},
+}
+
+var listProviderImagesCmd = &cobra.Command{
+	Use:   "images",
+	Short: "List the images of a provider",
+	Long:  `List the values accepted as instance image. For Ximera the image ID is the osID to use.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		provider, err := getCatalogProvider(cmd)
+		if err != nil {
+			return err
+		}
+
+		images, err := apiClient.ListProviderImages(context.Background(), provider)
+		if err != nil {
+			return fmt.Errorf("error listing images: %w", err)
+		}
+		return printCatalog(images)
+	},
+}
+
+// getCatalogProvider returns the validated provider flag
+func getCatalogProvider(cmd *cobra.Command) (models.ProviderID, error) {
+	value, err := cmd.Flags().GetString(flagCatalogProvider)
+	if err != nil {
+		return "", fmt.Errorf("error getting provider flag: %w", err)
+	}
+	provider := models.ProviderID(value)
+	if !provider.IsValid() {
+		return "", fmt.Errorf("unknown provider: %q", value)
+	}
+	return provider, nil
+}
+
+// printCatalog pretty prints a provider catalog
+func printCatalog(catalog interface{}) error {
+	prettyJSON, err := json.MarshalIndent(catalog, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error formatting response: %w", err)
+	}
+	fmt.Println(string(prettyJSON))
+	return nil
+}
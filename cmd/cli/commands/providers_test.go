@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/talis/test"
+)
+
+// setupProvidersCommand reinitializes the flags of the providers commands for each test run
+func setupProvidersCommand() *cobra.Command {
+	providersCmd.ResetFlags()
+	for _, cmd := range providersCmd.Commands() {
+		cmd.ResetFlags()
+	}
+	addProviderCatalogFlags(providersCmd)
+
+	rootCmd := &cobra.Command{Use: "talis"}
+	rootCmd.AddCommand(providersCmd)
+	return rootCmd
+}
+
+func TestProvidersCmd(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		expectedOutput []string
+		expectedError  string
+	}{
+		{
+			name:           "list regions",
+			args:           []string{"providers", "regions", "--provider", "do-mock"},
+			expectedOutput: []string{`"id": "nyc1"`, `"id": "ams3"`},
+		},
+		{
+			name:           "list sizes",
+			args:           []string{"providers", "sizes", "--provider", "do-mock"},
+			expectedOutput: []string{`"id": "s-1vcpu-1gb"`, `"memory_mb": 1024`, `"regions": [`},
+		},
+		{
+			name:           "list images",
+			args:           []string{"providers", "images", "--provider", "do-mock"},
+			expectedOutput: []string{`"id": "ubuntu-22-04-x64"`, `"name": "debian-12-x64"`},
+		},
+		{
+			name:          "missing provider flag",
+			args:          []string{"providers", "regions"},
+			expectedError: "required flag(s) \"provider\" not set",
+		},
+		{
+			name:          "unknown provider",
+			args:          []string{"providers", "images", "--provider", "moon"},
+			expectedError: `unknown provider: "moon"`,
+		},
+		{
+			name:          "provider without catalog support",
+			args:          []string{"providers", "sizes", "--provider", "mock"},
+			expectedError: "error listing sizes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suite := test.NewSuite(t)
+			defer suite.Cleanup()
+
+			originalClient := apiClient
+			apiClient = suite.APIClient
+			defer func() { apiClient = originalClient }()
+
+			// Capture the command output
+			buf := new(bytes.Buffer)
+			originalStdout := os.Stdout
+			r, w, _ := os.Pipe()
+			os.Stdout = w
+
+			var wg sync.WaitGroup
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = io.Copy(buf, r)
+			}()
+
+			cmd := setupProvidersCommand()
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+
+			_ = w.Close()
+			os.Stdout = originalStdout
+			wg.Wait()
+			_ = r.Close()
+
+			if tt.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			for _, expected := range tt.expectedOutput {
+				assert.Contains(t, buf.String(), expected)
+			}
+		})
+	}
+}
@@ -53,6 +53,8 @@ func init() {
 	RootCmd.AddCommand(GetUsersCmd())
 	RootCmd.AddCommand(GetTasksCmd())
 	RootCmd.AddCommand(GetProjectsCmd())
+	RootCmd.AddCommand(GetProvidersCmd())
+	RootCmd.AddCommand(GetPlaybooksCmd())
 }
 
 // RootCmd represents the base command when called without any subcommands
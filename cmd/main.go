@@ -12,10 +12,12 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -24,6 +26,7 @@ import (
 	fiber "github.com/gofiber/fiber/v2"
 	"github.com/joho/godotenv"
 
+	"github.com/celestiaorg/talis/internal/compute"
 	"github.com/celestiaorg/talis/internal/db"
 	"github.com/celestiaorg/talis/internal/db/repos"
 	log "github.com/celestiaorg/talis/internal/logger"
@@ -32,7 +35,26 @@ import (
 	"github.com/celestiaorg/talis/pkg/api/v1/routes"
 )
 
+// Server modes
+const (
+	// modeAPI only serves the HTTP API
+	modeAPI = "api"
+	// modeWorker only processes tasks
+	modeWorker = "worker"
+	// modeAll serves the HTTP API and processes tasks
+	modeAll = "all"
+)
+
 func main() {
+	mode := flag.String("mode", modeAll, "what the server runs: api, worker or all")
+	flag.Parse()
+	if *mode != modeAPI && *mode != modeWorker && *mode != modeAll {
+		fmt.Printf("Invalid mode %q, must be one of %s, %s or %s\n", *mode, modeAPI, modeWorker, modeAll)
+		os.Exit(2)
+	}
+	runAPI := *mode == modeAPI || *mode == modeAll
+	runWorker := *mode == modeWorker || *mode == modeAll
+
 	// Load .env file first
 	if err := godotenv.Load(); err != nil {
 		// Use fmt.Printf here since logger isn't initialized yet
@@ -44,7 +66,7 @@ func main() {
 	log.InitializeAndConfigure()
 
 	// Log that the application is starting
-	log.Info("Starting application...")
+	log.Infof("Starting application in %s mode...", *mode)
 
 	// Set dynamic Swagger host and base path from environment variables
 	if apiHost := os.Getenv("API_HOST"); apiHost != "" {
@@ -69,14 +91,15 @@ func main() {
 	}
 
 	// Initialize database
-	DB, err := db.New(db.Options{
+	dbOptions := db.Options{
 		Host:     os.Getenv("DB_HOST"),
 		Port:     dbPort,
 		User:     os.Getenv("DB_USER"),
 		Password: os.Getenv("DB_PASSWORD"),
 		DBName:   os.Getenv("DB_NAME"),
 		// SSLEnabled: os.Getenv("DB_SSL_MODE") == "true",
-	})
+	}
+	DB, err := db.New(dbOptions)
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
@@ -88,30 +111,69 @@ func main() {
 	projectRepo := repos.NewProjectRepository(DB)
 	taskRepo := repos.NewTaskRepository(DB)
 	sshKeyRepo := repos.NewSSHKeyRepository(DB)
+	playbookRepo := repos.NewPlaybookRepository(DB)
+	driftEventRepo := repos.NewDriftEventRepository(DB)
+	webhookRepo := repos.NewWebhookRepository(DB)
 
 	// Initialize services
 	projectService := services.NewProjectService(projectRepo)
 	taskService := services.NewTaskService(taskRepo, projectService)
-	instanceService := services.NewInstanceService(instanceRepo, taskService, projectService)
+	// Compute providers are shared by all services
+	providers := compute.NewProviderRegistry()
+	instanceService := services.NewInstanceService(instanceRepo, taskService, projectService, providers)
 	userService := services.NewUserService(userRepo)
 	sshKeyService := services.NewSSHKeyService(sshKeyRepo)
+	// Uploaded playbooks run on the server, they are only enabled for trusted users or sandboxed workers
+	playbookService := services.NewPlaybookService(playbookRepo).
+		WithDirectory(os.Getenv("PLAYBOOKS_DIR")).
+		WithUploads(os.Getenv("PLAYBOOK_UPLOADS_ENABLED") == "true")
+	driftEventService := services.NewDriftEventService(driftEventRepo)
+	webhookService := services.NewWebhookService(webhookRepo, userRepo)
+
+	// Queue the status changes of tasks and instances for the webhooks of their owners
+	taskService.WithWebhooks(webhookService)
+	instanceService.WithWebhooks(webhookService)
+
+	// Resolve the playbooks instance requests reference, before queueing them and in the workers
+	instanceService.WithPlaybooks(playbookService)
+
+	// Get catalog cache TTL from environment or use default
+	catalogTTL := services.DefaultCatalogTTL
+	if ttlStr := os.Getenv("CATALOG_CACHE_TTL"); ttlStr != "" {
+		if ttl, err := time.ParseDuration(ttlStr); err == nil && ttl > 0 {
+			catalogTTL = ttl
+			log.Infof("Using configured catalog cache TTL: %s", catalogTTL)
+		} else {
+			log.Warnf("Invalid CATALOG_CACHE_TTL value: %s, using default: %s", ttlStr, catalogTTL)
+		}
+	}
+	catalogService := services.NewCatalogService(providers, catalogTTL)
+
+	// Reject instance requests with regions, sizes and images the provider does not offer
+	instanceService.WithCatalogValidator(catalogService)
 
 	// Initialize handlers
-	apiHandler := handlers.NewAPIHandler(instanceService, projectService, taskService, userService)
+	apiHandler := handlers.NewAPIHandler(instanceService, projectService, taskService, userService, driftEventService, catalogService, webhookService)
 	instanceHandler := handlers.NewInstanceHandler(apiHandler)
+	providerHandler := handlers.NewProviderHandler(apiHandler)
 	projectHandler := handlers.NewProjectHandlers(apiHandler)
 	taskHandler := handlers.NewTaskHandlers(apiHandler)
 	userHandler := handlers.NewUserHandler(apiHandler)
+	webhookHandler := handlers.NewWebhookHandler(apiHandler)
 	sshKeyHandler := &handlers.SSHKeyHandlers{
 		SSHKeyService: sshKeyService,
 	}
+	playbookHandler := &handlers.PlaybookHandlers{
+		PlaybookService: playbookService,
+	}
 
 	// Create RPC handler and assign handlers directly
 	rpcHandler := &handlers.RPCHandler{
-		ProjectHandlers: projectHandler,
-		TaskHandlers:    taskHandler,
-		UserHandlers:    userHandler,
-		SSHKeyHandlers:  sshKeyHandler,
+		ProjectHandlers:  projectHandler,
+		TaskHandlers:     taskHandler,
+		UserHandlers:     userHandler,
+		SSHKeyHandlers:   sshKeyHandler,
+		PlaybookHandlers: playbookHandler,
 	}
 
 	// Setup Fiber app
@@ -124,7 +186,7 @@ func main() {
 
 	// Register routes - no need for project and task handlers as they're handled via RPC
 	// The above comment is no longer entirely true as ListByInstanceID is a direct REST endpoint on TaskHandler
-	routes.RegisterRoutes(app, instanceHandler, rpcHandler, taskHandler)
+	routes.RegisterRoutes(app, instanceHandler, providerHandler, rpcHandler, taskHandler, webhookHandler)
 
 	// Create context that listens for the interrupt signal from the OS.
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
@@ -133,52 +195,208 @@ func main() {
 	// Create a WaitGroup to wait for goroutines to finish
 	var wg sync.WaitGroup
 
-	// Get worker count from environment or use default
-	workerCount := services.DefaultWorkerCount
-	if workerCountStr := os.Getenv("WORKER_COUNT"); workerCountStr != "" {
-		if count, err := strconv.Atoi(workerCountStr); err == nil && count > 0 {
-			workerCount = count
-			log.Infof("Using configured worker count: %d", workerCount)
-		} else if err != nil {
-			log.Warnf("Invalid WORKER_COUNT value: %s, using default: %d", workerCountStr, workerCount)
-		}
-	}
-
-	// Get high priority ratio from environment or use default
-	highPriorityRatio := services.DefaultHighPriorityRatio
-	if ratioStr := os.Getenv("HIGH_PRIORITY_RATIO"); ratioStr != "" {
-		if ratio, err := strconv.ParseFloat(ratioStr, 64); err == nil && ratio > 0 && ratio <= 1.0 {
-			highPriorityRatio = ratio
-			log.Infof("Using configured high priority ratio: %.2f", highPriorityRatio)
-		} else if err != nil {
-			log.Warnf("Invalid HIGH_PRIORITY_RATIO value: %s, using default: %.2f", ratioStr, highPriorityRatio)
+	// Launch the worker pool and the reconciler, unless only the API is served
+	if runWorker {
+		// Get worker count from environment or use default
+		workerCount := services.DefaultWorkerCount
+		if workerCountStr := os.Getenv("WORKER_COUNT"); workerCountStr != "" {
+			if count, err := strconv.Atoi(workerCountStr); err == nil && count > 0 {
+				workerCount = count
+				log.Infof("Using configured worker count: %d", workerCount)
+			} else if err != nil {
+				log.Warnf("Invalid WORKER_COUNT value: %s, using default: %d", workerCountStr, workerCount)
+			}
+		}
+
+		// Get high priority ratio from environment or use default
+		highPriorityRatio := services.DefaultHighPriorityRatio
+		if ratioStr := os.Getenv("HIGH_PRIORITY_RATIO"); ratioStr != "" {
+			if ratio, err := strconv.ParseFloat(ratioStr, 64); err == nil && ratio > 0 && ratio <= 1.0 {
+				highPriorityRatio = ratio
+				log.Infof("Using configured high priority ratio: %.2f", highPriorityRatio)
+			} else if err != nil {
+				log.Warnf("Invalid HIGH_PRIORITY_RATIO value: %s, using default: %.2f", ratioStr, highPriorityRatio)
+			} else {
+				log.Warnf("HIGH_PRIORITY_RATIO must be between 0 and 1, using default: %.2f", highPriorityRatio)
+			}
+		}
+
+		// Launch worker pool with the cancellable context and WaitGroup
+		wg.Add(1) // Increment counter before launching goroutine
+		workerPool := services.NewWorkerPool(instanceService, projectService, taskService, userService, sshKeyService, services.DefaultBackoff)
+		workerPool.WithWorkerCount(workerCount).WithHighPriorityRatio(highPriorityRatio)
+
+		// Cap the tasks processed at the same time per provider, owner and project, 0 means no cap
+		var limits services.ConcurrencyLimits
+		if limitsStr := os.Getenv("PROVIDER_CONCURRENCY_LIMITS"); limitsStr != "" {
+			if providerLimits, err := services.ParseProviderLimits(limitsStr); err == nil {
+				limits.Providers = providerLimits
+				log.Infof("Using configured provider concurrency limits: %v", providerLimits)
+			} else {
+				log.Warnf("Invalid PROVIDER_CONCURRENCY_LIMITS value: %v, providers are not capped", err)
+			}
+		}
+		for name, limit := range map[string]*int{
+			"OWNER_CONCURRENCY_LIMIT":   &limits.PerOwner,
+			"PROJECT_CONCURRENCY_LIMIT": &limits.PerProject,
+		} {
+			if limitStr := os.Getenv(name); limitStr != "" {
+				if value, err := strconv.Atoi(limitStr); err == nil && value >= 0 {
+					*limit = value
+					log.Infof("Using configured %s: %d", name, value)
+				} else {
+					log.Warnf("Invalid %s value: %s, not capped", name, limitStr)
+				}
+			}
+		}
+		workerPool.WithConcurrencyLimits(limits)
+
+		// Retry policies of the task actions, e.g. RETRY_POLICY_CREATE_INSTANCES="attempts=5,base=30s,max=10m,jitter=0.2"
+		for action, defaultPolicy := range services.DefaultRetryPolicies {
+			name := "RETRY_POLICY_" + strings.ToUpper(string(action))
+			if policyStr := os.Getenv(name); policyStr != "" {
+				if policy, err := services.ParseRetryPolicy(policyStr, defaultPolicy); err == nil {
+					workerPool.WithRetryPolicy(action, policy)
+					log.Infof("Using configured %s: %+v", name, policy)
+				} else {
+					log.Warnf("Invalid %s value: %v, using default: %+v", name, err, defaultPolicy)
+				}
+			}
+		}
+
+		// Timeouts of the task actions and their steps, e.g. TASK_TIMEOUT_CREATE_INSTANCES="task=45m,create=15m,provision=30m"
+		for action, defaultTimeouts := range services.DefaultTaskTimeouts {
+			name := "TASK_TIMEOUT_" + strings.ToUpper(string(action))
+			if timeoutsStr := os.Getenv(name); timeoutsStr != "" {
+				if timeouts, err := services.ParseTaskTimeouts(timeoutsStr, defaultTimeouts); err == nil {
+					workerPool.WithTaskTimeouts(action, timeouts)
+					log.Infof("Using configured %s: %+v", name, timeouts)
+				} else {
+					log.Warnf("Invalid %s value: %v, using default: %+v", name, err, defaultTimeouts)
+				}
+			}
+		}
+
+		// Register the worker so that worker processes sharing the database reclaim its tasks if it stops
+		workerPool.WithFleet(services.NewFleetService(repos.NewWorkerRepository(DB)))
+
+		// Get how often workers check for terminated tasks from environment or use default
+		if intervalStr := os.Getenv("TASK_TERMINATION_POLL_INTERVAL"); intervalStr != "" {
+			if interval, err := time.ParseDuration(intervalStr); err == nil && interval > 0 {
+				workerPool.WithTerminationPollInterval(interval)
+				log.Infof("Using configured task termination poll interval: %s", interval)
+			} else {
+				log.Warnf("Invalid TASK_TERMINATION_POLL_INTERVAL value: %s, using default: %s", intervalStr, services.DefaultTerminationPollInterval)
+			}
+		}
+
+		// Dispatch tasks as soon as they are created, polling only as a safety net
+		taskPollInterval := services.DefaultTaskPollInterval
+		if intervalStr := os.Getenv("TASK_POLL_INTERVAL"); intervalStr != "" {
+			if interval, err := time.ParseDuration(intervalStr); err == nil && interval > 0 {
+				taskPollInterval = interval
+				log.Infof("Using configured task poll interval: %s", taskPollInterval)
+			} else {
+				log.Warnf("Invalid TASK_POLL_INTERVAL value: %s, using default: %s", intervalStr, taskPollInterval)
+			}
+		}
+		if notifications, err := db.ListenForTasks(ctx, dbOptions); err != nil {
+			log.Warnf("Failed to listen for task notifications, polling for tasks every %s: %v", services.DefaultBackoff, err)
 		} else {
-			log.Warnf("HIGH_PRIORITY_RATIO must be between 0 and 1, using default: %.2f", highPriorityRatio)
+			workerPool.WithTaskNotifications(notifications, taskPollInterval)
+			log.Infof("Listening for task notifications, polling for tasks every %s", taskPollInterval)
 		}
-	}
 
-	// Launch worker pool with the cancellable context and WaitGroup
-	wg.Add(1) // Increment counter before launching goroutine
-	workerPool := services.NewWorkerPool(instanceService, projectService, taskService, userService, sshKeyService, services.DefaultBackoff)
-	workerPool.WithWorkerCount(workerCount).WithHighPriorityRatio(highPriorityRatio)
+		// Recover any stale tasks before starting the worker pool
+		log.Info("Starting worker pool...")
+		go workerPool.LaunchWorkerPool(ctx, &wg)
+
+		// Get reconcile interval from environment or use default, 0 disables the reconciler
+		reconcileInterval := services.DefaultReconcileInterval
+		if intervalStr := os.Getenv("RECONCILE_INTERVAL"); intervalStr != "" {
+			if interval, err := time.ParseDuration(intervalStr); err == nil && interval >= 0 {
+				reconcileInterval = interval
+				log.Infof("Using configured reconcile interval: %s", reconcileInterval)
+			} else {
+				log.Warnf("Invalid RECONCILE_INTERVAL value: %s, using default: %s", intervalStr, reconcileInterval)
+			}
+		}
 
-	// Recover any stale tasks before starting the worker pool
-	log.Info("Starting worker pool...")
-	go workerPool.LaunchWorkerPool(ctx, &wg)
+		// Launch the reconciler that detects drift between instances and their providers. Every worker
+		// process runs one, the process holding the reconciler lock is the only one reconciling.
+		if reconcileInterval > 0 {
+			reconciler := services.NewReconciler(instanceService, driftEventService, reconcileInterval)
+			lock, err := db.NewAdvisoryLock(DB, db.ReconcilerLockKey)
+			if err != nil {
+				log.Fatalf("Failed to create the reconciler lock: %v", err)
+			}
+			reconciler.WithLock(lock)
+			wg.Add(1)
+			go reconciler.Run(ctx, &wg)
+		} else {
+			log.Info("Reconciler disabled")
+		}
 
-	// Start server in a goroutine so that it doesn't block.
-	var errChan = make(chan error)
-	go func() {
-		port := os.Getenv("SERVER_PORT")
-		if port == "" {
-			port = "8080"
+		// Launch the webhook deliverer sending the queued webhook deliveries. Deliverers of several
+		// worker processes claim deliveries from the shared outbox, so a delivery is sent by one of them.
+		webhookPollInterval := services.DefaultWebhookPollInterval
+		if intervalStr := os.Getenv("WEBHOOK_POLL_INTERVAL"); intervalStr != "" {
+			if interval, err := time.ParseDuration(intervalStr); err == nil && interval > 0 {
+				webhookPollInterval = interval
+				log.Infof("Using configured webhook poll interval: %s", webhookPollInterval)
+			} else {
+				log.Warnf("Invalid WEBHOOK_POLL_INTERVAL value: %s, using default: %s", intervalStr, webhookPollInterval)
+			}
+		}
+		deliverer := services.NewWebhookDeliverer(webhookService, webhookPollInterval)
+		// Retry policy of the webhook deliveries, e.g. RETRY_POLICY_WEBHOOKS="attempts=8,base=10s,max=1h,jitter=0.2"
+		if policyStr := os.Getenv("RETRY_POLICY_WEBHOOKS"); policyStr != "" {
+			if policy, err := services.ParseRetryPolicy(policyStr, services.DefaultWebhookRetryPolicy); err == nil {
+				deliverer.WithRetryPolicy(policy)
+				log.Infof("Using configured RETRY_POLICY_WEBHOOKS: %+v", policy)
+			} else {
+				log.Warnf("Invalid RETRY_POLICY_WEBHOOKS value: %v, using default: %+v", err, services.DefaultWebhookRetryPolicy)
+			}
+		}
+		wg.Add(1)
+		go deliverer.Run(ctx, &wg)
+	} else {
+		log.Info("Worker pool, reconciler and webhook deliverer disabled in api mode")
+	}
+
+	// Push task events to the streams served by this server as soon as any process records them
+	if runAPI {
+		if intervalStr := os.Getenv("TASK_STREAM_POLL_INTERVAL"); intervalStr != "" {
+			if interval, err := time.ParseDuration(intervalStr); err == nil && interval > 0 {
+				taskService.WithStreamPollInterval(interval)
+				log.Infof("Using configured task stream poll interval: %s", interval)
+			} else {
+				log.Warnf("Invalid TASK_STREAM_POLL_INTERVAL value: %s, using default: %s", intervalStr, services.DefaultTaskStreamPollInterval)
+			}
 		}
-		log.Info("Server starting on :" + port)
-		if err := app.Listen(":" + port); err != nil {
-			// Send error to the channel
-			errChan <- err
+		if notifications, err := db.ListenForTaskEvents(ctx, dbOptions); err != nil {
+			log.Warnf("Failed to listen for task event notifications, task streams only poll for events: %v", err)
+		} else {
+			go taskService.ForwardEventNotifications(ctx, notifications)
+			log.Info("Listening for task event notifications")
 		}
-	}()
+	}
+
+	// Start server in a goroutine so that it doesn't block.
+	var errChan = make(chan error)
+	if runAPI {
+		go func() {
+			port := os.Getenv("SERVER_PORT")
+			if port == "" {
+				port = "8080"
+			}
+			log.Info("Server starting on :" + port)
+			if err := app.Listen(":" + port); err != nil {
+				// Send error to the channel
+				errChan <- err
+			}
+		}()
+	}
 
 	// Listen for the interrupt signal or the server error.
 	select {
@@ -196,10 +414,12 @@ func main() {
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second) // 5 second timeout for shutdown
 	defer cancel()
 
-	if err := app.ShutdownWithContext(shutdownCtx); err != nil {
-		log.Errorf("Server shutdown failed: %v", err) // Use Errorf instead of Fatalf
-	} else {
-		log.Info("Server shut down gracefully")
+	if runAPI {
+		if err := app.ShutdownWithContext(shutdownCtx); err != nil {
+			log.Errorf("Server shutdown failed: %v", err) // Use Errorf instead of Fatalf
+		} else {
+			log.Info("Server shut down gracefully")
+		}
 	}
 
 	// Wait for background goroutines to finish.
@@ -63,7 +63,7 @@ const docTemplate = `{
         },
         "/instances": {
             "get": {
-                "description": "Returns a list of instances with pagination and optional filtering by status.\nThis endpoint is similar to ListInstances but with a different operation ID for client compatibility.\nYou can filter by status (pending, created, provisioning, ready, terminated) and control pagination with limit and offset.",
+                "description": "Returns a list of instances with pagination and optional filtering by status.\nThis endpoint is similar to ListInstances but with a different operation ID for client compatibility.\nYou can filter by status (pending, created, provisioning, ready, stopped, failed, terminated) and control pagination with limit and offset.",
                 "consumes": [
                     "application/json"
                 ],
@@ -99,7 +99,7 @@ const docTemplate = `{
                     {
                         "type": "string",
                         "example": "ready",
-                        "description": "Filter by instance status (pending, created, provisioning, ready, terminated)",
+                        "description": "Filter by instance status (pending, created, provisioning, ready, stopped, failed, terminated)",
                         "name": "status",
                         "in": "query"
                     }
@@ -366,6 +366,57 @@ const docTemplate = `{
                 }
             }
         },
+        "/instances/{id}/payload": {
+            "get": {
+                "description": "Returns the status of the payload of a specific instance as it is copied and executed.\nOnce the payload script ran, the response includes its exit code and the end of its standard output and standard error, up to 64 KiB each.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "instances"
+                ],
+                "summary": "Get the payload of an instance",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "example": 123,
+                        "description": "Instance ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payload status, exit code and output of the instance",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_celestiaorg_talis_internal_db_models.InstancePayload"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input - typically a non-numeric or negative instance ID",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_celestiaorg_talis_internal_types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Instance not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_celestiaorg_talis_internal_types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error - database errors",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_celestiaorg_talis_internal_types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/instances/{instance_id}/tasks": {
             "get": {
                 "description": "Returns a list of tasks for a specific instance with optional filtering and pagination",
@@ -442,6 +493,32 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "github_com_celestiaorg_talis_internal_db_models.AnsibleHostRecap": {
+            "type": "object",
+            "properties": {
+                "changed": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "ignored": {
+                    "type": "integer"
+                },
+                "ok": {
+                    "type": "integer"
+                },
+                "rescued": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "unreachable": {
+                    "type": "integer"
+                }
+            }
+        },
         "github_com_celestiaorg_talis_internal_db_models.Instance": {
             "type": "object",
             "properties": {
@@ -518,6 +595,48 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_celestiaorg_talis_internal_db_models.InstancePayload": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "exit_code": {
+                    "description": "ExitCode is the exit code of the script, nil if it did not exit",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "instance_id": {
+                    "type": "integer"
+                },
+                "owner_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "Status is the status of the payload of the instance, it is not stored with the output",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_celestiaorg_talis_internal_db_models.PayloadStatus"
+                        }
+                    ]
+                },
+                "stderr": {
+                    "type": "string"
+                },
+                "stdout": {
+                    "description": "Stdout and Stderr hold the end of the output of the script, up to MaxPayloadOutputSize each",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
         "github_com_celestiaorg_talis_internal_db_models.InstanceStatus": {
             "type": "integer",
             "enum": [
@@ -526,7 +645,9 @@ const docTemplate = `{
                 2,
                 3,
                 4,
-                5
+                5,
+                6,
+                7
             ],
             "x-enum-varnames": [
                 "InstanceStatusUnknown",
@@ -534,7 +655,9 @@ const docTemplate = `{
                 "InstanceStatusCreated",
                 "InstanceStatusProvisioning",
                 "InstanceStatusReady",
-                "InstanceStatusTerminated"
+                "InstanceStatusTerminated",
+                "InstanceStatusStopped",
+                "InstanceStatusFailed"
             ]
         },
         "github_com_celestiaorg_talis_internal_db_models.PayloadStatus": {
@@ -606,6 +729,7 @@ const docTemplate = `{
                 "hetzner",
                 "ovh",
                 "ximera",
+                "local",
                 "do-mock",
                 "digitalocean-mock",
                 "mock"
@@ -621,11 +745,60 @@ const docTemplate = `{
                 "ProviderHetzner",
                 "ProviderOVH",
                 "ProviderXimera",
+                "ProviderLocal",
                 "ProviderDOMock1",
                 "ProviderDOMock2",
                 "ProviderMock3"
             ]
         },
+        "github_com_celestiaorg_talis_internal_db_models.ProvisionTaskResult": {
+            "type": "object",
+            "properties": {
+                "host": {
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Message is what the task failed with, empty for tasks that succeeded",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/github_com_celestiaorg_talis_internal_db_models.ProvisionTaskStatus"
+                },
+                "task": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_celestiaorg_talis_internal_db_models.ProvisionTaskStatus": {
+            "type": "string",
+            "enum": [
+                "ok",
+                "changed",
+                "failed",
+                "unreachable",
+                "skipped",
+                "ignored"
+            ],
+            "x-enum-varnames": [
+                "ProvisionTaskOk",
+                "ProvisionTaskChanged",
+                "ProvisionTaskFailed",
+                "ProvisionTaskUnreachable",
+                "ProvisionTaskSkipped",
+                "ProvisionTaskIgnored"
+            ]
+        },
+        "github_com_celestiaorg_talis_internal_db_models.ProvisionerID": {
+            "type": "string",
+            "enum": [
+                "ansible",
+                "ssh"
+            ],
+            "x-enum-varnames": [
+                "ProvisionerAnsible",
+                "ProvisionerSSH"
+            ]
+        },
         "github_com_celestiaorg_talis_internal_db_models.Task": {
             "type": "object",
             "properties": {
@@ -640,6 +813,14 @@ const docTemplate = `{
                 "attempts": {
                     "type": "integer"
                 },
+                "checkpoint": {
+                    "description": "Last step completed by a worker, retries resume after it",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_celestiaorg_talis_internal_db_models.TaskStep"
+                        }
+                    ]
+                },
                 "createdAt": {
                     "type": "string"
                 },
@@ -690,22 +871,17 @@ const docTemplate = `{
                 },
                 "result": {
                     "description": "Result of the task",
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_celestiaorg_talis_internal_db_models.TaskResult"
+                        }
+                    ]
                 },
                 "status": {
                     "$ref": "#/definitions/github_com_celestiaorg_talis_internal_db_models.TaskStatus"
                 },
                 "updatedAt": {
                     "type": "string"
-                },
-                "webhook_sent": {
-                    "type": "boolean"
-                },
-                "webhook_url": {
-                    "type": "string"
                 }
             }
         },
@@ -731,6 +907,63 @@ const docTemplate = `{
                 "TaskPriorityLow"
             ]
         },
+        "github_com_celestiaorg_talis_internal_db_models.TaskResult": {
+            "type": "object",
+            "properties": {
+                "ansible_recap": {
+                    "description": "AnsibleRecap holds the PLAY RECAP counts of the provisioning playbook per host",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/github_com_celestiaorg_talis_internal_db_models.AnsibleHostRecap"
+                    }
+                },
+                "instance_id": {
+                    "type": "integer"
+                },
+                "payload_exit_code": {
+                    "description": "PayloadExitCode is the exit code of the payload script, unset if it was not executed",
+                    "type": "integer"
+                },
+                "provider_id": {
+                    "$ref": "#/definitions/github_com_celestiaorg_talis_internal_db_models.ProviderID"
+                },
+                "provider_instance_id": {
+                    "type": "integer"
+                },
+                "provision_tasks": {
+                    "description": "ProvisionTasks are the results of the provisioning tasks per host, in the order they finished",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_celestiaorg_talis_internal_db_models.ProvisionTaskResult"
+                    }
+                },
+                "public_ip": {
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "steps": {
+                    "description": "Steps are the steps of the task that ran, in order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_celestiaorg_talis_internal_db_models.TaskStepResult"
+                    }
+                },
+                "volume_details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_celestiaorg_talis_internal_db_models.VolumeDetail"
+                    }
+                },
+                "volume_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
         "github_com_celestiaorg_talis_internal_db_models.TaskStatus": {
             "type": "string",
             "enum": [
@@ -750,6 +983,38 @@ const docTemplate = `{
                 "TaskStatusTerminated"
             ]
         },
+        "github_com_celestiaorg_talis_internal_db_models.TaskStep": {
+            "type": "string",
+            "enum": [
+                "create",
+                "provision",
+                "payload",
+                "terminate"
+            ],
+            "x-enum-varnames": [
+                "TaskStepCreate",
+                "TaskStepProvision",
+                "TaskStepPayload",
+                "TaskStepTerminate"
+            ]
+        },
+        "github_com_celestiaorg_talis_internal_db_models.TaskStepResult": {
+            "type": "object",
+            "properties": {
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "step": {
+                    "$ref": "#/definitions/github_com_celestiaorg_talis_internal_db_models.TaskStep"
+                }
+            }
+        },
         "github_com_celestiaorg_talis_internal_db_models.User": {
             "type": "object",
             "properties": {
@@ -782,6 +1047,10 @@ const docTemplate = `{
                 },
                 "username": {
                     "type": "string"
+                },
+                "webhook_url": {
+                    "description": "Receives the task and instance events of the user",
+                    "type": "string"
                 }
             }
         },
@@ -895,6 +1164,11 @@ const docTemplate = `{
                     "description": "Whether to execute the payload after copying",
                     "type": "boolean"
                 },
+                "extra_vars": {
+                    "description": "Extra variables passed to the playbook",
+                    "type": "object",
+                    "additionalProperties": true
+                },
                 "image": {
                     "description": "OS image to use",
                     "type": "string"
@@ -931,6 +1205,17 @@ const docTemplate = `{
                     "description": "Local path to the payload script on the API server",
                     "type": "string"
                 },
+                "playbook": {
+                    "description": "Playbook Configs - Optional, require the ansible provisioner",
+                    "type": "string"
+                },
+                "playbook_tags": {
+                    "description": "Tags of the playbook to run, all of its tasks if empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "project_name": {
                     "description": "User Defined Configs",
                     "type": "string"
@@ -948,9 +1233,17 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "provision": {
-                    "description": "Whether to run Ansible provisioning",
+                    "description": "Whether to provision the instances",
                     "type": "boolean"
                 },
+                "provisioner": {
+                    "description": "How to provision the instances, \"ansible\" (default) or \"ssh\"",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_celestiaorg_talis_internal_db_models.ProvisionerID"
+                        }
+                    ]
+                },
                 "public_ip": {
                     "description": "Public IP address",
                     "type": "string"
@@ -2,15 +2,21 @@ package compute
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/celestiaorg/talis/internal/constants"
+	"github.com/celestiaorg/talis/internal/db/models"
 	"github.com/celestiaorg/talis/internal/logger"
 	"github.com/celestiaorg/talis/internal/types"
 )
@@ -27,12 +33,46 @@ const (
 	// pathToPlaybook is the path to the ansible main playbook
 	pathToPlaybook = ansibleDir + "/main.yml"
 
-	// ansibleKeyPath is the fixed path where the SSH key will be stored
-	ansibleKeyPath = ansibleDir + "/talis_ssh_key"
+	// callbackPluginsDir holds the callback plugins of the playbook
+	callbackPluginsDir = ansibleDir + "/callback_plugins"
+
+	// eventsCallback is the callback plugin writing the result of each task on each host to
+	// the file named by eventsFileEnv
+	eventsCallback = "talis_events"
+	eventsFileEnv  = "TALIS_ANSIBLE_EVENTS_FILE"
+
+	// workspaceKeyFile, workspaceInventoryFile, workspaceLogFile, workspaceEventsFile and
+	// workspacePayloadOutputFile are the names of the SSH key copy, the inventory, the playbook
+	// log, the task results and the output of the payload in the workspace of a provisioner
+	workspaceKeyFile           = "ssh_key"
+	workspaceInventoryFile     = "inventory.ini"
+	workspaceLogFile           = "ansible.log"
+	workspaceEventsFile        = "events.jsonl"
+	workspacePayloadOutputFile = "payload_output.json"
+
+	// payloadCopyTask is the name of the task of the setup stage copying the payload
+	payloadCopyTask = "Copy payload script to instance if present"
+
+	// ansibleStopTimeout is how long a cancelled playbook gets to stop after being interrupted
+	// before it is killed
+	ansibleStopTimeout = 30 * time.Second
+
+	// ansibleUnreachableExitCode is the exit code of ansible-playbook when hosts could not be reached
+	ansibleUnreachableExitCode = 3
 )
 
-// keyFileMutex protects access to the SSH key file to prevent race conditions
-var keyFileMutex sync.RWMutex
+var (
+	// recapLineRegex matches the line of a host in the PLAY RECAP of a playbook
+	recapLineRegex = regexp.MustCompile(`^(\S+)\s+:\s+((?:\w+=\d+\s*)+)$`)
+	// payloadExitCodeRegex matches the exit code of the payload printed by the setup stage
+	payloadExitCodeRegex = regexp.MustCompile(`talis_payload_exit_code=(-?\d+)`)
+	// payloadStatusRegex matches the status of the payload printed by the setup stage
+	payloadStatusRegex = regexp.MustCompile(`talis_payload_status=(\w+)`)
+)
+
+// PlaybookRunner runs ansible-playbook with the given arguments and environment, writing its
+// output to stdout and stderr
+type PlaybookRunner func(ctx context.Context, args, env []string, stdout, stderr io.Writer) error
 
 // AnsibleConfigurator implements the Provisioner interface
 type AnsibleConfigurator struct {
@@ -40,16 +80,84 @@ type AnsibleConfigurator struct {
 	jobID string
 	// instances keeps track of all instances to be configured
 	instances map[string]string
-	// mutex protects the instances map
+	// workspace is the temporary directory holding the files of the job, created on first use
+	workspace string
+	// playbooksDir is the temporary directory uploaded playbooks are written to, created on
+	// first use. It is kept apart from the workspace, which holds the SSH key copy.
+	playbooksDir string
+	// runPlaybook runs ansible-playbook
+	runPlaybook PlaybookRunner
+	// mutex protects the instances map and the workspace
 	mutex sync.Mutex
 }
 
-// NewAnsibleConfigurator creates a new Ansible configurator
+// NewAnsibleConfigurator creates a new Ansible configurator.
+// The configurator keeps its files in a workspace of its own, which Close removes.
 func NewAnsibleConfigurator(jobID string) *AnsibleConfigurator {
 	return &AnsibleConfigurator{
-		jobID:     jobID,
-		instances: make(map[string]string),
+		jobID:       jobID,
+		instances:   make(map[string]string),
+		runPlaybook: execPlaybook,
+	}
+}
+
+// WithPlaybookRunner replaces the runner of ansible-playbook, tests use it to fake playbook runs
+func (a *AnsibleConfigurator) WithPlaybookRunner(runner PlaybookRunner) *AnsibleConfigurator {
+	a.runPlaybook = runner
+	return a
+}
+
+// Workspace returns the workspace of the job, creating it if it does not exist yet
+func (a *AnsibleConfigurator) Workspace() (string, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.workspace == "" {
+		dir, err := os.MkdirTemp("", fmt.Sprintf("talis-%s-", a.jobID))
+		if err != nil {
+			return "", fmt.Errorf("failed to create workspace for job %s: %w", a.jobID, err)
+		}
+		a.workspace = dir
+	}
+	return a.workspace, nil
+}
+
+// playbooksWorkspace returns the directory uploaded playbooks are written to, creating it if it
+// does not exist yet
+func (a *AnsibleConfigurator) playbooksWorkspace() (string, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.playbooksDir == "" {
+		dir, err := os.MkdirTemp("", fmt.Sprintf("talis-%s-playbooks-", a.jobID))
+		if err != nil {
+			return "", fmt.Errorf("failed to create playbooks directory for job %s: %w", a.jobID, err)
+		}
+		a.playbooksDir = dir
 	}
+	return a.playbooksDir, nil
+}
+
+// Close removes the workspace of the job with the inventory, SSH key copy and logs in it, and
+// the uploaded playbooks
+func (a *AnsibleConfigurator) Close() error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.playbooksDir != "" {
+		if err := os.RemoveAll(a.playbooksDir); err != nil {
+			return fmt.Errorf("failed to remove playbooks directory of job %s: %w", a.jobID, err)
+		}
+		a.playbooksDir = ""
+	}
+	if a.workspace == "" {
+		return nil
+	}
+	if err := os.RemoveAll(a.workspace); err != nil {
+		return fmt.Errorf("failed to remove workspace of job %s: %w", a.jobID, err)
+	}
+	a.workspace = ""
+	return nil
 }
 
 // GetSSHKeyFromEnv returns the SSH key from the environment variable
@@ -57,52 +165,37 @@ func (a *AnsibleConfigurator) GetSSHKeyFromEnv() string {
 	return os.Getenv(constants.EnvTalisSSHKey)
 }
 
-// EnsureSSHKeyFile ensures the SSH key is written to a file and returns the path
-// If the file already exists with the correct content, it won't be overwritten
-// This function is goroutine-safe and optimized for concurrent reads.
+// EnsureSSHKeyFile ensures the SSH key is copied to the workspace of the job and returns the path.
+// If the file already exists with the correct content, it won't be overwritten.
 func (a *AnsibleConfigurator) EnsureSSHKeyFile() (string, error) {
-	// Get key content before acquiring any lock
 	keyContent := a.GetSSHKeyFromEnv()
 	if keyContent == "" {
 		return "", fmt.Errorf("no SSH key found in environment variable %s", constants.EnvTalisSSHKey)
 	}
 
-	// Create ansible directory with secure permissions if it doesn't exist
-	// This is safe to do without locking
-	if err := os.MkdirAll(ansibleDir, 0750); err != nil {
-		return "", fmt.Errorf("failed to create ansible directory: %w", err)
-	}
-
-	// First acquire a read lock to check if the file exists with correct content
-	keyFileMutex.RLock()
-	existingContent, err := os.ReadFile(ansibleKeyPath)
-	if err == nil && string(existingContent) == keyContent {
-		// File exists with correct content, we're done
-		keyFileMutex.RUnlock()
-		logger.Debugf("SSH key file already exists with correct content at %s", ansibleKeyPath)
-		return ansibleKeyPath, nil
+	workspace, err := a.Workspace()
+	if err != nil {
+		return "", err
 	}
-	// Release the read lock so we can acquire a write lock
-	keyFileMutex.RUnlock()
+	keyPath := filepath.Join(workspace, workspaceKeyFile)
 
-	// Acquire a write lock for writing the file
-	keyFileMutex.Lock()
-	defer keyFileMutex.Unlock()
+	// Hosts of the same job may be configured in parallel
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
 
-	// Check again in case another goroutine wrote the file while we were waiting
-	existingContent, err = os.ReadFile(ansibleKeyPath)
+	// #nosec G304 -- the key path is inside the workspace of the job
+	existingContent, err := os.ReadFile(keyPath)
 	if err == nil && string(existingContent) == keyContent {
-		logger.Debugf("SSH key file already exists with correct content at %s", ansibleKeyPath)
-		return ansibleKeyPath, nil
+		return keyPath, nil
 	}
 
 	// Write the key with secure permissions
-	if err := os.WriteFile(ansibleKeyPath, []byte(keyContent), 0600); err != nil {
+	if err := os.WriteFile(keyPath, []byte(keyContent), 0600); err != nil {
 		return "", fmt.Errorf("failed to write SSH key to file: %w", err)
 	}
 
-	logger.Debugf("Created SSH key file at %s", ansibleKeyPath)
-	return ansibleKeyPath, nil
+	logger.Debugf("Created SSH key file at %s", keyPath)
+	return keyPath, nil
 }
 
 // CreateInventory creates the inventory file directly from InstanceRequest and returns the inventory path file
@@ -120,11 +213,11 @@ func (a *AnsibleConfigurator) CreateInventory(instance *types.InstanceRequest) (
 		return "", err
 	}
 
-	// Create inventory path with base name
-	inventoryPath := filepath.Join(ansibleDir, fmt.Sprintf("inventory_%s_ansible.ini", a.jobID))
+	// The inventory is written next to the key, in the workspace of the job
+	inventoryPath := filepath.Join(filepath.Dir(keyPath), workspaceInventoryFile)
 
 	// Create inventory file with secure permissions
-	// #nosec G304 -- inventory path is constructed from validated job ID
+	// #nosec G304 -- inventory path is inside the workspace of the job
 	f, err := os.OpenFile(inventoryPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
 		return "", fmt.Errorf("failed to create inventory file: %w", err)
@@ -156,6 +249,11 @@ func (a *AnsibleConfigurator) CreateInventory(instance *types.InstanceRequest) (
 		line += fmt.Sprintf(" payload_src_path=\"%s\"", instance.PayloadPath)
 		line += fmt.Sprintf(" payload_dest_path=\"%s\"", destPath)
 		line += fmt.Sprintf(" payload_execute=%t", instance.ExecutePayload)
+		if instance.ExecutePayload {
+			// The output of the payload is written back to the workspace of the job
+			outputPath := filepath.Join(filepath.Dir(keyPath), workspacePayloadOutputFile)
+			line += fmt.Sprintf(" payload_output_path=\"%s\"", outputPath)
+		}
 	}
 
 	line += "\n"
@@ -169,10 +267,111 @@ func (a *AnsibleConfigurator) CreateInventory(instance *types.InstanceRequest) (
 	return inventoryPath, nil
 }
 
-// RunAnsiblePlaybook runs the Ansible playbook for all instances in parallel
-func (a *AnsibleConfigurator) RunAnsiblePlaybook(inventoryPath string, tags []string) error {
+// Provision implements the Provisioner interface by running the setup stage of the playbook,
+// and the volumes stage if the volumes have to be mounted, followed by the requested playbook.
+// The results of both runs are combined. The status of the payload is followed in the output
+// of the setup stage, which writes the output of the payload to the workspace.
+func (a *AnsibleConfigurator) Provision(ctx context.Context, instance *types.InstanceRequest, opts ProvisionOptions) (*ProvisionResult, error) {
+	inventoryPath, err := a.CreateInventory(instance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create inventory file: %w", err)
+	}
+	if inventoryPath == "" {
+		return nil, fmt.Errorf("no instance to provision for job %s", a.jobID)
+	}
+
+	tags := []string{"setup"}
+	if opts.MountVolumes {
+		tags = append(tags, "volumes")
+	}
+	output := opts.Output
+	var payload *payloadTracker
+	if instance.PayloadPath != "" {
+		payload = &payloadTracker{execute: instance.ExecutePayload, opts: opts, status: models.PayloadStatusPendingCopy}
+		output = payload.output(opts.Output)
+	}
+	result, err := a.RunAnsiblePlaybook(ctx, inventoryPath, tags, output)
+	if payload != nil && result != nil {
+		payload.finish(result.Tasks)
+		if instance.ExecutePayload {
+			outputPath := filepath.Join(filepath.Dir(inventoryPath), workspacePayloadOutputFile)
+			if readErr := readPayloadOutput(outputPath, result); readErr != nil {
+				logger.Warnf("Failed to read the payload output of job %s: %v", a.jobID, readErr)
+			}
+		}
+	}
+	if err != nil || opts.Playbook == nil {
+		return result, err
+	}
+
+	playbookResult, err := a.RunPlaybook(ctx, inventoryPath, opts.Playbook, opts.Output)
+	if playbookResult != nil {
+		for host, recap := range playbookResult.Recap {
+			result.Recap[host] = addRecaps(result.Recap[host], recap)
+		}
+		result.Tasks = append(result.Tasks, playbookResult.Tasks...)
+	}
+	return result, err
+}
+
+// addRecaps adds up the PLAY RECAP counts of a host over two playbook runs
+func addRecaps(a, b models.AnsibleHostRecap) models.AnsibleHostRecap {
+	return models.AnsibleHostRecap{
+		Ok:          a.Ok + b.Ok,
+		Changed:     a.Changed + b.Changed,
+		Unreachable: a.Unreachable + b.Unreachable,
+		Failed:      a.Failed + b.Failed,
+		Skipped:     a.Skipped + b.Skipped,
+		Rescued:     a.Rescued + b.Rescued,
+		Ignored:     a.Ignored + b.Ignored,
+	}
+}
+
+// RunPlaybook runs a registered or uploaded playbook on the hosts of the inventory with the
+// tags and extra variables of the playbook, like RunAnsiblePlaybook runs the main playbook.
+// Uploaded playbooks are written to a directory of the job apart from its workspace first.
+func (a *AnsibleConfigurator) RunPlaybook(ctx context.Context, inventoryPath string, playbook *Playbook, output func(stream OutputStream, line string)) (*ProvisionResult, error) {
+	workspace, err := a.Workspace()
+	if err != nil {
+		return nil, err
+	}
+	var playbooksDir string
+	if playbook.Dir == "" {
+		if playbooksDir, err = a.playbooksWorkspace(); err != nil {
+			return nil, err
+		}
+	}
+	playbookPath, extraVarsPath, err := writePlaybook(workspace, playbooksDir, playbook)
+	if err != nil {
+		return nil, err
+	}
+
+	var extraArgs []string
+	if extraVarsPath != "" {
+		extraArgs = append(extraArgs, "--extra-vars", "@"+extraVarsPath)
+	}
+	fmt.Printf("🎭 Running playbook %s...\n", playbook.Name)
+	result, err := a.runPlaybookFile(ctx, inventoryPath, playbookPath, playbook.Tags, extraArgs, output)
+	if err != nil {
+		return result, fmt.Errorf("playbook %q: %w", playbook.Name, err)
+	}
+	return result, nil
+}
+
+// RunAnsiblePlaybook runs the Ansible playbook for all instances in parallel, passing each line
+// of its output to output if it is set.
+// When ctx is cancelled the playbook is interrupted, which stops ansible-playbook and its
+// connections, and killed if it does not exit within ansibleStopTimeout.
+// The result parsed from the playbook output and the task results are returned even if the
+// playbook failed.
+func (a *AnsibleConfigurator) RunAnsiblePlaybook(ctx context.Context, inventoryPath string, tags []string, output func(stream OutputStream, line string)) (*ProvisionResult, error) {
 	fmt.Println("🎭 Running Ansible playbook...")
+	return a.runPlaybookFile(ctx, inventoryPath, pathToPlaybook, tags, nil, output)
+}
 
+// runPlaybookFile runs the playbook at playbookPath with the tags and extra arguments, as
+// described by RunAnsiblePlaybook
+func (a *AnsibleConfigurator) runPlaybookFile(ctx context.Context, inventoryPath, playbookPath string, tags, extraArgs []string, output func(stream OutputStream, line string)) (*ProvisionResult, error) {
 	// Prepare command arguments
 	args := []string{
 		"-i", inventoryPath,
@@ -186,35 +385,310 @@ func (a *AnsibleConfigurator) RunAnsiblePlaybook(inventoryPath string, tags []st
 	}
 
 	// Add playbook path
-	args = append(args, pathToPlaybook)
+	args = append(args, playbookPath)
 
 	if len(tags) > 0 {
 		tagsStr := strings.Join(tags, ",")
 		args = append(args, "--tags", tagsStr)
 	}
+	args = append(args, extraArgs...)
 
-	// Run ansible-playbook command
-	// #nosec G204 -- command arguments are constructed from validated inputs
-	cmd := exec.Command("ansible-playbook", args...)
+	// Keep the log of the run in the workspace of the job
+	workspace, err := a.Workspace()
+	if err != nil {
+		return nil, err
+	}
+	// #nosec G304 -- the log path is inside the workspace of the job
+	logFile, err := os.OpenFile(filepath.Join(workspace, workspaceLogFile), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ansible log file: %w", err)
+	}
+	defer func() {
+		if closeErr := logFile.Close(); closeErr != nil {
+			logger.Warnf("Failed to close ansible log file of job %s: %v", a.jobID, closeErr)
+		}
+	}()
+
+	// The events callback writes the task results to the workspace, they are read once the
+	// playbook exited
+	eventsPath := filepath.Join(workspace, workspaceEventsFile)
+	if err := os.Remove(eventsPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to remove ansible events of a previous run: %w", err)
+	}
 
 	// Disable host key checking and known hosts file
 	env := os.Environ()
 	env = append(env, "ANSIBLE_HOST_KEY_CHECKING=false")
 	env = append(env, "ANSIBLE_RETRY_FILES_ENABLED=false")
-	cmd.Env = env
+	env = append(env, "ANSIBLE_CALLBACK_PLUGINS="+callbackPluginsDir)
+	// Ansible 2.11 renamed the setting enabling callbacks, both are set to support older versions
+	env = append(env, "ANSIBLE_CALLBACKS_ENABLED="+eventsCallback, "ANSIBLE_CALLBACK_WHITELIST="+eventsCallback)
+	env = append(env, eventsFileEnv+"="+eventsPath)
+
+	// Redirect output to stdout, the log and output, and parse it for the result
+	parsed := newPlaybookOutput()
+	stdout := []io.Writer{os.Stdout, logFile, parsed}
+	stderr := []io.Writer{os.Stderr, logFile}
+	stdoutLines, stderrLines := outputLines(output, OutputStdout), outputLines(output, OutputStderr)
+	if output != nil {
+		stdout = append(stdout, stdoutLines)
+		stderr = append(stderr, stderrLines)
+	}
+	err = a.runPlaybook(ctx, args, env, io.MultiWriter(stdout...), io.MultiWriter(stderr...))
+	if output != nil {
+		stdoutLines.Flush()
+		stderrLines.Flush()
+	}
 
-	// Redirect output to stdout
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	result := parsed.result()
+	tasks, readErr := readPlaybookEvents(eventsPath)
+	if readErr != nil {
+		logger.Warnf("Failed to read the task results of job %s: %v", a.jobID, readErr)
+	}
+	result.Tasks = tasks
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to run ansible playbook (check output above for details): %w", err)
+	if err != nil {
+		if ctx.Err() != nil {
+			return result, fmt.Errorf("ansible playbook was stopped: %w", context.Cause(ctx))
+		}
+		details := "check output above for details"
+		if failure := describeFailure(tasks); failure != "" {
+			details = failure
+		}
+		// A playbook that failed on reachable hosts fails the same way when run again
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() != ansibleUnreachableExitCode {
+			return result, fmt.Errorf("%w: ansible playbook exited with code %d: %s", ErrProvisioningFailed, exitErr.ExitCode(), details)
+		}
+		return result, fmt.Errorf("failed to run ansible playbook (%s): %w", details, err)
 	}
 
 	fmt.Println("✅ Ansible playbook completed successfully")
+	return result, nil
+}
+
+// execPlaybook runs the ansible-playbook binary. When ctx is cancelled the playbook is
+// interrupted, and killed if it does not exit within ansibleStopTimeout.
+func execPlaybook(ctx context.Context, args, env []string, stdout, stderr io.Writer) error {
+	// #nosec G204 -- command arguments are constructed from validated inputs
+	cmd := exec.CommandContext(ctx, "ansible-playbook", args...)
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = ansibleStopTimeout
+	cmd.Env = env
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+// readPlaybookEvents reads the task results written by the events callback. A missing file
+// means that no task finished.
+func readPlaybookEvents(path string) ([]models.ProvisionTaskResult, error) {
+	// #nosec G304 -- the events path is inside the workspace of the job
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ansible events: %w", err)
+	}
+
+	var tasks []models.ProvisionTaskResult
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		var task models.ProvisionTaskResult
+		if err := json.Unmarshal([]byte(line), &task); err != nil {
+			// The last line of a playbook that was killed may be cut short
+			logger.Debugf("Skipping invalid ansible event %q: %v", line, err)
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// describeFailure describes the first task of tasks that failed or could not reach its host,
+// or returns an empty string if there is none
+func describeFailure(tasks []models.ProvisionTaskResult) string {
+	for _, task := range tasks {
+		var failure string
+		switch task.Status {
+		case models.ProvisionTaskFailed:
+			failure = fmt.Sprintf("task %q failed on %s", task.Task, task.Host)
+		case models.ProvisionTaskUnreachable:
+			failure = fmt.Sprintf("host %s was unreachable in task %q", task.Host, task.Task)
+		default:
+			continue
+		}
+		if task.Message != "" {
+			failure += ": " + task.Message
+		}
+		return failure
+	}
+	return ""
+}
+
+// payloadTracker follows the status of the payload in the output of the setup stage
+type payloadTracker struct {
+	// execute is set if the payload is executed once it is copied
+	execute bool
+	// opts are passed each status the payload advances to
+	opts   ProvisionOptions
+	status models.PayloadStatus
+}
+
+// output returns an output function following the status of the payload in each line of the
+// standard output, before passing the line to output if it is set
+func (t *payloadTracker) output(output func(stream OutputStream, line string)) func(stream OutputStream, line string) {
+	return func(stream OutputStream, line string) {
+		if stream == OutputStdout {
+			t.parseLine(line)
+		}
+		if output != nil {
+			output(stream, line)
+		}
+	}
+}
+
+// parseLine advances the status of the payload once the setup stage reports that the payload
+// was copied or exited
+func (t *payloadTracker) parseLine(line string) {
+	if match := payloadStatusRegex.FindStringSubmatch(line); match != nil {
+		if match[1] == models.PayloadStatusCopied.String() && t.status == models.PayloadStatusPendingCopy {
+			t.advance(models.PayloadStatusCopied)
+			if t.execute {
+				t.advance(models.PayloadStatusPendingExecution)
+			}
+		}
+		return
+	}
+	if match := payloadExitCodeRegex.FindStringSubmatch(line); match != nil && t.status == models.PayloadStatusPendingExecution {
+		if match[1] == "0" {
+			t.advance(models.PayloadStatusExecuted)
+		} else {
+			t.advance(models.PayloadStatusExecutionFailed)
+		}
+	}
+}
+
+// finish reports a payload that failed to copy, or that did not exit, once the setup stage
+// exited. A setup stage that failed before copying the payload leaves it pending.
+func (t *payloadTracker) finish(tasks []models.ProvisionTaskResult) {
+	switch t.status {
+	case models.PayloadStatusPendingCopy:
+		for _, task := range tasks {
+			if task.Task == payloadCopyTask && (task.Status == models.ProvisionTaskFailed || task.Status == models.ProvisionTaskUnreachable) {
+				t.advance(models.PayloadStatusCopyFailed)
+				return
+			}
+		}
+	case models.PayloadStatusPendingExecution:
+		t.advance(models.PayloadStatusExecutionFailed)
+	}
+}
+
+// advance records the status the payload advanced to and reports it
+func (t *payloadTracker) advance(status models.PayloadStatus) {
+	t.status = status
+	t.opts.reportPayload(status)
+}
+
+// readPayloadOutput reads the output of the payload written by the setup stage into result,
+// keeping the end of the output. A missing file means that the payload did not exit.
+func readPayloadOutput(path string, result *ProvisionResult) error {
+	// #nosec G304 -- the output path is inside the workspace of the job
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read payload output: %w", err)
+	}
+
+	var output struct {
+		Stdout string `json:"stdout"`
+		Stderr string `json:"stderr"`
+	}
+	if err := json.Unmarshal(data, &output); err != nil {
+		return fmt.Errorf("failed to parse payload output: %w", err)
+	}
+	result.PayloadStdout = models.TruncatePayloadOutput(output.Stdout)
+	result.PayloadStderr = models.TruncatePayloadOutput(output.Stderr)
 	return nil
 }
 
+// playbookOutput parses the output of ansible-playbook line by line as it is written
+type playbookOutput struct {
+	mu     sync.Mutex
+	lines  *lineWriter
+	parsed ProvisionResult
+}
+
+// newPlaybookOutput creates a new playbookOutput
+func newPlaybookOutput() *playbookOutput {
+	o := &playbookOutput{
+		parsed: ProvisionResult{Recap: make(map[string]models.AnsibleHostRecap)},
+	}
+	o.lines = newLineWriter(o.parseLine)
+	return o
+}
+
+// Write implements io.Writer
+func (o *playbookOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.lines.Write(p)
+}
+
+// result returns the result parsed from the output, including its last unterminated line
+func (o *playbookOutput) result() *ProvisionResult {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.lines.Flush()
+	result := o.parsed
+	return &result
+}
+
+// parseLine parses a PLAY RECAP line or the payload exit code from a line of the output
+func (o *playbookOutput) parseLine(line string) {
+	line = strings.TrimSpace(line)
+	if match := payloadExitCodeRegex.FindStringSubmatch(line); match != nil {
+		if code, err := strconv.Atoi(match[1]); err == nil {
+			o.parsed.PayloadExitCode = &code
+		}
+		return
+	}
+
+	match := recapLineRegex.FindStringSubmatch(line)
+	if match == nil {
+		return
+	}
+	var recap models.AnsibleHostRecap
+	counts := map[string]*int{
+		"ok":          &recap.Ok,
+		"changed":     &recap.Changed,
+		"unreachable": &recap.Unreachable,
+		"failed":      &recap.Failed,
+		"skipped":     &recap.Skipped,
+		"rescued":     &recap.Rescued,
+		"ignored":     &recap.Ignored,
+	}
+	for _, field := range strings.Fields(match[2]) {
+		key, value, _ := strings.Cut(field, "=")
+		count, ok := counts[key]
+		if !ok {
+			// Not a recap line
+			return
+		}
+		*count, _ = strconv.Atoi(value)
+	}
+	o.parsed.Recap[match[1]] = recap
+}
+
 // ConfigureHost implements the Provisioner interface
 //
 // NOTE: this isn't a create name since all it is really doing is ensuring SSH readiness
@@ -236,12 +710,6 @@ func (a *AnsibleConfigurator) ConfigureHost(ctx context.Context, host string) er
 	// Wait for SSH to be available
 	fmt.Printf("⏳ Waiting for SSH to be available on %s...\n", host)
 	for i := 0; i < 30; i++ {
-		select {
-		case <-ctx.Done():
-			return fmt.Errorf("context cancelled while waiting for SSH to be available on %s", host)
-		default:
-			// continue
-		}
 
 		args := []string{
 			"-i", keyPath,
@@ -253,7 +721,7 @@ func (a *AnsibleConfigurator) ConfigureHost(ctx context.Context, host string) er
 		}
 
 		// #nosec G204 -- command arguments are constructed from validated inputs
-		checkCmd := exec.Command("ssh", args...)
+		checkCmd := exec.CommandContext(ctx, "ssh", args...)
 
 		if err := checkCmd.Run(); err == nil {
 			fmt.Printf("✅ SSH connection established to %s\n", host)
@@ -265,7 +733,11 @@ func (a *AnsibleConfigurator) ConfigureHost(ctx context.Context, host string) er
 		}
 
 		fmt.Printf("  Retrying SSH connection to %s in 10 seconds... (%d/30)\n", host, i+1)
-		time.Sleep(10 * time.Second)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context cancelled while waiting for SSH to be available on %s: %w", host, context.Cause(ctx))
+		case <-time.After(10 * time.Second):
+		}
 	}
 
 	return nil
@@ -295,13 +767,13 @@ func (a *AnsibleConfigurator) ConfigureHosts(ctx context.Context, hosts []string
 	close(errChan)
 
 	// Check for any errors
-	var errors []error
+	var errs []error
 	for err := range errChan {
-		errors = append(errors, err)
+		errs = append(errs, err)
 	}
 
-	if len(errors) > 0 {
-		return fmt.Errorf("failed to ensure SSH readiness for some hosts: %v", errors)
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to ensure SSH readiness for some hosts: %v", errs)
 	}
 
 	fmt.Printf("✅ SSH readiness confirmed for all hosts.\n")
@@ -0,0 +1,262 @@
+package compute
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/talis/internal/constants"
+	"github.com/celestiaorg/talis/internal/db/models"
+	"github.com/celestiaorg/talis/internal/types"
+)
+
+func TestPlaybookOutput(t *testing.T) {
+	output := newPlaybookOutput()
+
+	// Lines are split across writes like the output of a running process
+	chunks := []string{
+		"TASK [Execute payload script if requested] ****\n",
+		"ok: [192.0.2.1]\n\nTASK [Report payload exit code] ****\nok: [192.0.2.1] => {\n",
+		"    \"msg\": \"talis_payload_exit_code=2\"\n}\n\nPLAY RECAP ****\n",
+		"192.0.2.1                  : ok=12   changed=7    unreachable=0    failed=1    skipped=3    rescued=0    ignored=1\n",
+		"192.0.2.2                  : ok=1    changed=0    unreachable=1    failed=0    skipped=0",
+	}
+	for _, chunk := range chunks {
+		n, err := output.Write([]byte(chunk))
+		require.NoError(t, err)
+		require.Equal(t, len(chunk), n)
+	}
+
+	result := output.result()
+	require.NotNil(t, result.PayloadExitCode)
+	require.Equal(t, 2, *result.PayloadExitCode)
+	require.Equal(t, map[string]models.AnsibleHostRecap{
+		"192.0.2.1": {Ok: 12, Changed: 7, Failed: 1, Skipped: 3, Ignored: 1},
+		"192.0.2.2": {Ok: 1, Unreachable: 1},
+	}, result.Recap)
+}
+
+func TestPlaybookOutput_NoPayload(t *testing.T) {
+	output := newPlaybookOutput()
+	_, err := output.Write([]byte("TASK [Gathering Facts] ****\nok: [192.0.2.1]\nfatal: [192.0.2.2]: UNREACHABLE! => {\"msg\": \"a=1\"}\n"))
+	require.NoError(t, err)
+
+	result := output.result()
+	require.Nil(t, result.PayloadExitCode)
+	require.Empty(t, result.Recap)
+}
+
+func TestAnsibleConfigurator_RunAnsiblePlaybook(t *testing.T) {
+	t.Setenv(constants.EnvTalisSSHKey, "test-ssh-key")
+
+	// The fake playbook writes its output and the task results of the events callback, then
+	// fails like ansible-playbook does when a task failed
+	runner := func(_ context.Context, _, env []string, stdout, stderr io.Writer) error {
+		var eventsPath string
+		for _, v := range env {
+			if value, ok := strings.CutPrefix(v, eventsFileEnv+"="); ok {
+				eventsPath = value
+			}
+		}
+		events := `{"task": "Gathering Facts", "host": "192.0.2.1", "status": "ok"}
+{"task": "Install required packages", "host": "192.0.2.1", "status": "failed", "message": "No package matching 'gti' is available"}
+`
+		if err := os.WriteFile(eventsPath, []byte(events), 0600); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(stdout, "TASK [Install required packages] ****\nfatal: [192.0.2.1]: FAILED!\n"); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(stderr, "[WARNING]: deprecated"); err != nil {
+			return err
+		}
+		return exec.Command("sh", "-c", "exit 2").Run()
+	}
+
+	a := NewAnsibleConfigurator("test").WithPlaybookRunner(runner)
+	defer func() { require.NoError(t, a.Close()) }()
+	inventoryPath, err := a.CreateInventory(&types.InstanceRequest{PublicIP: "192.0.2.1"})
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	var lines []string
+	result, err := a.RunAnsiblePlaybook(context.Background(), inventoryPath, []string{"setup"}, func(stream OutputStream, line string) {
+		mu.Lock()
+		defer mu.Unlock()
+		lines = append(lines, string(stream)+": "+line)
+	})
+	require.ErrorIs(t, err, ErrProvisioningFailed)
+	require.ErrorContains(t, err, `ansible playbook exited with code 2: task "Install required packages" failed on 192.0.2.1: No package matching 'gti' is available`)
+
+	require.ElementsMatch(t, []string{
+		"stdout: TASK [Install required packages] ****",
+		"stdout: fatal: [192.0.2.1]: FAILED!",
+		"stderr: [WARNING]: deprecated",
+	}, lines)
+	require.Equal(t, []models.ProvisionTaskResult{
+		{Task: "Gathering Facts", Host: "192.0.2.1", Status: models.ProvisionTaskOk},
+		{Task: "Install required packages", Host: "192.0.2.1", Status: models.ProvisionTaskFailed, Message: "No package matching 'gti' is available"},
+	}, result.Tasks)
+}
+
+func TestAnsibleConfigurator_Provision_Playbook(t *testing.T) {
+	t.Setenv(constants.EnvTalisSSHKey, "test-ssh-key")
+
+	// The fake playbook records its arguments and reads the files of the playbook it runs
+	var runs [][]string
+	var playbookPath, mainYML, extraVars string
+	runner := func(_ context.Context, args, _ []string, stdout, _ io.Writer) error {
+		runs = append(runs, args)
+		for i, arg := range args {
+			if strings.HasSuffix(arg, "/"+models.PlaybookEntrypoint) && arg != pathToPlaybook {
+				content, err := os.ReadFile(arg)
+				if err != nil {
+					return err
+				}
+				playbookPath, mainYML = arg, string(content)
+			}
+			if arg == "--extra-vars" {
+				content, err := os.ReadFile(strings.TrimPrefix(args[i+1], "@"))
+				if err != nil {
+					return err
+				}
+				extraVars = string(content)
+			}
+		}
+		_, err := io.WriteString(stdout, "192.0.2.1 : ok=2 changed=1 unreachable=0 failed=0 skipped=0 rescued=0 ignored=0\n")
+		return err
+	}
+
+	a := NewAnsibleConfigurator("test").WithPlaybookRunner(runner)
+	defer func() { require.NoError(t, a.Close()) }()
+
+	result, err := a.Provision(context.Background(), &types.InstanceRequest{PublicIP: "192.0.2.1"}, ProvisionOptions{
+		Playbook: &Playbook{
+			Name: "validator",
+			Files: models.PlaybookFiles{
+				"main.yml":                       "- hosts: all\n  roles: [validator]\n",
+				"roles/validator/tasks/main.yml": "- debug: msg=hello\n",
+			},
+			Tags:      []string{"install", "configure"},
+			ExtraVars: map[string]interface{}{"chain_id": "mocha-4", "ansible_connection": "local"},
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, runs, 2)
+	require.Contains(t, runs[0], pathToPlaybook)
+	require.Contains(t, strings.Join(runs[1], " "), "--tags install,configure --extra-vars @")
+	require.Equal(t, "- hosts: all\n  roles: [validator]\n", mainYML)
+	// Variables configuring Ansible are dropped
+	require.JSONEq(t, `{"chain_id": "mocha-4"}`, extraVars)
+	// The uploaded playbook is kept apart from the workspace and the SSH key copy in it
+	workspace, err := a.Workspace()
+	require.NoError(t, err)
+	require.NotEmpty(t, playbookPath)
+	require.False(t, strings.HasPrefix(playbookPath, workspace+string(os.PathSeparator)), "playbook %s is inside the workspace %s", playbookPath, workspace)
+	// The recaps of both runs are added up
+	require.Equal(t, models.AnsibleHostRecap{Ok: 4, Changed: 2}, result.Recap["192.0.2.1"])
+
+	// The SSH provisioner does not run playbooks
+	_, err = NewSSHProvisioner("test").Provision(context.Background(), &types.InstanceRequest{PublicIP: "192.0.2.1"}, ProvisionOptions{
+		Playbook: &Playbook{Name: "validator"},
+	})
+	require.ErrorIs(t, err, ErrProvisioningFailed)
+}
+
+func TestAnsibleConfigurator_Provision_Payload(t *testing.T) {
+	t.Setenv(constants.EnvTalisSSHKey, "test-ssh-key")
+	payloadPath := t.TempDir() + "/payload.sh"
+
+	// The fake setup stage prints the status of the payload like the setup stage does, and
+	// writes the output of a payload that exited where the inventory tells it to
+	runner := func(stdout, events string) PlaybookRunner {
+		return func(_ context.Context, args, env []string, out, _ io.Writer) error {
+			inventory, err := os.ReadFile(args[1])
+			if err != nil {
+				return err
+			}
+			_, outputPath, ok := strings.Cut(string(inventory), `payload_output_path="`)
+			if ok && strings.Contains(stdout, "talis_payload_exit_code") {
+				outputPath, _, _ = strings.Cut(outputPath, `"`)
+				if err := os.WriteFile(outputPath, []byte(`{"stdout": "synced", "stderr": "disk full"}`), 0600); err != nil {
+					return err
+				}
+			}
+			for _, v := range env {
+				if eventsPath, ok := strings.CutPrefix(v, eventsFileEnv+"="); ok {
+					if err := os.WriteFile(eventsPath, []byte(events), 0600); err != nil {
+						return err
+					}
+				}
+			}
+			if _, err := io.WriteString(out, stdout); err != nil {
+				return err
+			}
+			return exec.Command("sh", "-c", "exit 2").Run()
+		}
+	}
+
+	provision := func(runner PlaybookRunner, execute bool) (*ProvisionResult, []models.PayloadStatus) {
+		a := NewAnsibleConfigurator("test").WithPlaybookRunner(runner)
+		defer func() { require.NoError(t, a.Close()) }()
+
+		var statuses []models.PayloadStatus
+		result, err := a.Provision(context.Background(), &types.InstanceRequest{
+			PublicIP:       "192.0.2.1",
+			PayloadPath:    payloadPath,
+			ExecutePayload: execute,
+		}, ProvisionOptions{PayloadStatus: func(status models.PayloadStatus) {
+			statuses = append(statuses, status)
+		}})
+		require.ErrorIs(t, err, ErrProvisioningFailed)
+		return result, statuses
+	}
+
+	t.Run("reports a failed payload with its output", func(t *testing.T) {
+		result, statuses := provision(runner(
+			"ok: [192.0.2.1] => {\n    \"msg\": \"talis_payload_status=copied\"\n}\n"+
+				"ok: [192.0.2.1] => {\n    \"msg\": \"talis_payload_exit_code=4\"\n}\n", ""), true)
+		require.Equal(t, []models.PayloadStatus{
+			models.PayloadStatusCopied, models.PayloadStatusPendingExecution, models.PayloadStatusExecutionFailed,
+		}, statuses)
+		require.Equal(t, 4, *result.PayloadExitCode)
+		require.Equal(t, "synced", result.PayloadStdout)
+		require.Equal(t, "disk full", result.PayloadStderr)
+	})
+
+	t.Run("reports a failed payload copy", func(t *testing.T) {
+		result, statuses := provision(runner("", `{"task": "`+payloadCopyTask+`", "host": "192.0.2.1", "status": "failed"}`+"\n"), true)
+		require.Equal(t, []models.PayloadStatus{models.PayloadStatusCopyFailed}, statuses)
+		require.Nil(t, result.PayloadExitCode)
+		require.Empty(t, result.PayloadStdout)
+	})
+
+	t.Run("leaves the payload pending if the setup failed before copying it", func(t *testing.T) {
+		_, statuses := provision(runner("", `{"task": "Install required packages", "host": "192.0.2.1", "status": "failed"}`+"\n"), false)
+		require.Empty(t, statuses)
+	})
+}
+
+func TestRegisteredPlaybook(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(dir+"/validator/roles", 0700))
+	require.NoError(t, os.WriteFile(dir+"/validator/main.yml", []byte("- hosts: all\n"), 0600))
+
+	playbook, err := RegisteredPlaybook(dir, "validator")
+	require.NoError(t, err)
+	require.Equal(t, &Playbook{Name: "validator", Dir: dir + "/validator"}, playbook)
+
+	playbook, err = RegisteredPlaybook(dir, "bridge")
+	require.NoError(t, err)
+	require.Nil(t, playbook)
+
+	_, err = RegisteredPlaybook(dir, "../validator")
+	require.ErrorContains(t, err, "invalid playbook name")
+}
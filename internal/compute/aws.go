@@ -0,0 +1,928 @@
+package compute
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	computeTypes "github.com/celestiaorg/talis/internal/compute/types"
+	"github.com/celestiaorg/talis/internal/constants"
+	"github.com/celestiaorg/talis/internal/logger"
+	talisTypes "github.com/celestiaorg/talis/internal/types"
+)
+
+const (
+	// awsAccessKeyIDEnv is the environment variable containing the AWS access key ID
+	awsAccessKeyIDEnv = "AWS_ACCESS_KEY_ID"
+	// awsSecretAccessKeyEnv is the environment variable containing the AWS secret access key
+	awsSecretAccessKeyEnv = "AWS_SECRET_ACCESS_KEY"
+	// awsSessionTokenEnv optionally contains a session token for temporary credentials
+	awsSessionTokenEnv = "AWS_SESSION_TOKEN"
+	// awsRegionEnv is the environment variable containing the default AWS region
+	awsRegionEnv = "AWS_REGION"
+	// awsDefaultRegionEnv is the fallback environment variable for the default AWS region
+	awsDefaultRegionEnv = "AWS_DEFAULT_REGION"
+	// awsEC2EndpointEnv optionally overrides the EC2 endpoint, e.g. for a local EC2-compatible stand-in
+	awsEC2EndpointEnv = "AWS_ENDPOINT_URL_EC2"
+	// awsEndpointEnv is the fallback environment variable for the endpoint override
+	awsEndpointEnv = "AWS_ENDPOINT_URL"
+
+	// awsDefaultRegion is used when no region is configured
+	awsDefaultRegion = "us-east-1"
+
+	// awsRefTag is the tag that maps an EC2 instance to its ProviderInstanceID.
+	// EC2 identifies instances by string IDs (i-...) while Talis stores an integer, so every
+	// instance is tagged with the deployment ID and the ID of its Talis instance, which are
+	// unique together.
+	awsRefTag = "talis-ref"
+	// awsProjectTag is the tag containing the Talis project of an instance
+	awsProjectTag = "talis-project"
+
+	// awsVolumeType is the EBS volume type used for requested volumes
+	awsVolumeType = ec2types.VolumeTypeGp3
+	// awsStateRunning is the state of instances that are ready for use
+	awsStateRunning = ec2types.InstanceStateNameRunning
+)
+
+// awsImageAliases maps well-known image names to the owner and name pattern of their AMIs
+var awsImageAliases = map[string]struct {
+	owner string
+	name  string
+}{
+	"ubuntu-22.04": {owner: "099720109477", name: "ubuntu/images/hvm-ssd/ubuntu-jammy-22.04-amd64-server-*"},
+	"ubuntu-24.04": {owner: "099720109477", name: "ubuntu/images/hvm-ssd-gp3/ubuntu-noble-24.04-amd64-server-*"},
+	"debian-12":    {owner: "136693071363", name: "debian-12-amd64-*"},
+}
+
+// AWSProvider implements the Provider interface for AWS EC2
+type AWSProvider struct {
+	config    computeTypes.AWSConfiguration
+	newClient func(region string) computeTypes.EC2Client
+
+	mu      sync.Mutex
+	clients map[string]computeTypes.EC2Client
+}
+
+// InitAWSConfig initializes the AWS configuration from environment variables
+func InitAWSConfig() (*computeTypes.AWSConfiguration, error) {
+	config := &computeTypes.AWSConfiguration{
+		AccessKeyID:     os.Getenv(awsAccessKeyIDEnv),
+		SecretAccessKey: os.Getenv(awsSecretAccessKeyEnv),
+		SessionToken:    os.Getenv(awsSessionTokenEnv),
+		Region:          firstNonEmpty(os.Getenv(awsRegionEnv), os.Getenv(awsDefaultRegionEnv), awsDefaultRegion),
+		Endpoint:        firstNonEmpty(os.Getenv(awsEC2EndpointEnv), os.Getenv(awsEndpointEnv)),
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid AWS configuration: %w", err)
+	}
+	return config, nil
+}
+
+// NewAWSProvider creates a new AWS EC2 provider instance
+func NewAWSProvider() (*AWSProvider, error) {
+	config, err := InitAWSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := deploymentID(); err != nil {
+		return nil, err
+	}
+
+	awsConfig := aws.Config{
+		Credentials: aws.NewCredentialsCache(
+			credentials.NewStaticCredentialsProvider(config.AccessKeyID, config.SecretAccessKey, config.SessionToken)),
+	}
+	return &AWSProvider{
+		config: *config,
+		newClient: func(region string) computeTypes.EC2Client {
+			return ec2.NewFromConfig(awsConfig, func(o *ec2.Options) {
+				o.Region = region
+				if config.Endpoint != "" {
+					o.BaseEndpoint = aws.String(config.Endpoint)
+				}
+			})
+		},
+		clients: make(map[string]computeTypes.EC2Client),
+	}, nil
+}
+
+// SetClient sets the EC2 client of a region for testing
+func (p *AWSProvider) SetClient(region string, client computeTypes.EC2Client) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.clients == nil {
+		p.clients = make(map[string]computeTypes.EC2Client)
+	}
+	p.clients[region] = client
+}
+
+// client returns the EC2 client of the given region, creating it on first use
+func (p *AWSProvider) client(region string) (computeTypes.EC2Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if client, ok := p.clients[region]; ok {
+		return client, nil
+	}
+	if p.newClient == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+	if p.clients == nil {
+		p.clients = make(map[string]computeTypes.EC2Client)
+	}
+	client := p.newClient(region)
+	p.clients[region] = client
+	return client, nil
+}
+
+// ConfigureProvider is a no-op for AWS
+func (p *AWSProvider) ConfigureProvider(_ interface{}) error {
+	return nil
+}
+
+// ValidateCredentials validates the AWS credentials against the default region
+func (p *AWSProvider) ValidateCredentials() error {
+	client, err := p.client(p.config.Region)
+	if err != nil {
+		return err
+	}
+	if _, err := client.DescribeRegions(context.Background(), &ec2.DescribeRegionsInput{}); err != nil {
+		return fmt.Errorf("aws credential validation failed: %w", err)
+	}
+	return nil
+}
+
+// GetEnvironmentVars returns the environment variables needed for the provider
+func (p *AWSProvider) GetEnvironmentVars() map[string]string {
+	return map[string]string{
+		awsAccessKeyIDEnv:     os.Getenv(awsAccessKeyIDEnv),
+		awsSecretAccessKeyEnv: os.Getenv(awsSecretAccessKeyEnv),
+		awsSessionTokenEnv:    os.Getenv(awsSessionTokenEnv),
+		awsRegionEnv:          p.config.Region,
+		awsEC2EndpointEnv:     p.config.Endpoint,
+	}
+}
+
+// CreateInstance creates a new EC2 instance.
+// InstanceRequest.Size is the instance type (e.g. "t3.medium") and Image is either an
+// AMI ID, a well-known alias (e.g. "ubuntu-22.04") or an AMI name pattern. Every volume
+// is created as an EBS volume at launch and mounted at its mount point by cloud-init.
+func (p *AWSProvider) CreateInstance(ctx context.Context, config *talisTypes.InstanceRequest) error {
+	if config.InstanceID == 0 {
+		return fmt.Errorf("instance request has no instance ID to reference the instance by")
+	}
+	client, err := p.client(config.Region)
+	if err != nil {
+		return err
+	}
+
+	logger.Debugf("🚀 Creating AWS instance for project: %s", config.ProjectName)
+	logger.Debugf("  Region: %s", config.Region)
+	logger.Debugf("  Instance type: %s", config.Size)
+	logger.Debugf("  Image: %s", config.Image)
+
+	keyName, err := p.ensureKeyPair(ctx, client)
+	if err != nil {
+		logger.Errorf("❌ Failed to get SSH key: %v", err)
+		return fmt.Errorf("failed to get SSH key: %w", err)
+	}
+
+	imageID, err := p.resolveImage(ctx, client, config.Image)
+	if err != nil {
+		logger.Errorf("❌ Failed to resolve image: %v", err)
+		return fmt.Errorf("failed to resolve image %s: %w", config.Image, err)
+	}
+
+	groupID, err := p.ensureSecurityGroup(ctx, client, config.ProjectName)
+	if err != nil {
+		logger.Errorf("❌ Failed to get security group: %v", err)
+		return fmt.Errorf("failed to get security group: %w", err)
+	}
+
+	ref := int(config.InstanceID)
+	refValue, err := deploymentRef(ref)
+	if err != nil {
+		return err
+	}
+	name := awsInstanceName(config)
+	runInput := p.runInstancesInput(config, name, imageID, keyName, groupID, refValue)
+	logger.Debugf("  Sending instance creation request: %s", name)
+	output, err := client.RunInstances(ctx, runInput)
+	if err != nil {
+		logger.Errorf("❌ Failed to create instance: %v", err)
+		return fmt.Errorf("failed to create instance: %w", err)
+	}
+	if len(output.Instances) == 0 {
+		return fmt.Errorf("failed to create instance: RunInstances returned no instances")
+	}
+	instanceID := aws.ToString(output.Instances[0].InstanceId)
+
+	config.SetProviderInstanceID(ref)
+	config.VolumeIDs = []string{}
+	config.VolumeDetails = []talisTypes.VolumeDetails{}
+
+	instance, err := p.waitForInstanceRunning(ctx, client, instanceID)
+	if err != nil {
+		errMsg := fmt.Errorf("❌ Failed to get public IP for instance %s: %w", name, err)
+		logger.Error(errMsg)
+		return errMsg
+	}
+	publicIP := aws.ToString(instance.PublicIpAddress)
+	config.PublicIP = publicIP
+
+	for i, device := range runInput.BlockDeviceMappings {
+		volConfig := config.Volumes[i]
+		for _, attached := range instance.BlockDeviceMappings {
+			if aws.ToString(attached.DeviceName) != aws.ToString(device.DeviceName) || attached.Ebs == nil {
+				continue
+			}
+			volumeID := aws.ToString(attached.Ebs.VolumeId)
+			config.VolumeIDs = append(config.VolumeIDs, volumeID)
+			config.VolumeDetails = append(config.VolumeDetails, talisTypes.VolumeDetails{
+				ID:         volumeID,
+				Name:       fmt.Sprintf("%s-%s", name, volConfig.Name),
+				Region:     config.Region,
+				SizeGB:     volConfig.SizeGB,
+				MountPoint: volConfig.MountPoint,
+			})
+		}
+	}
+
+	logger.Infof("✅ AWS instance '%s' (ID: %s, ref: %d) created successfully with IP %s", name, instanceID, ref, publicIP)
+	return nil
+}
+
+// awsInstanceName returns the Name tag of the instance to create
+func awsInstanceName(config *talisTypes.InstanceRequest) string {
+	if config.Name == "" {
+		return fmt.Sprintf("%s-%s", config.ProjectName, generateRandomSuffix())
+	}
+	if config.NumberOfInstances > 1 {
+		return fmt.Sprintf("%s-%d", config.Name, config.InstanceIndex+1)
+	}
+	return config.Name
+}
+
+// runInstancesInput builds the RunInstances input for the given request
+func (p *AWSProvider) runInstancesInput(
+	config *talisTypes.InstanceRequest,
+	name, imageID, keyName, groupID string,
+	ref string,
+) *ec2.RunInstancesInput {
+	tags := []ec2types.Tag{
+		{Key: aws.String("Name"), Value: aws.String(name)},
+		{Key: aws.String(awsRefTag), Value: aws.String(ref)},
+		{Key: aws.String(awsProjectTag), Value: aws.String(config.ProjectName)},
+	}
+	for _, tag := range config.Tags {
+		tags = append(tags, ec2types.Tag{Key: aws.String(tag), Value: aws.String("")})
+	}
+
+	devices := make([]ec2types.BlockDeviceMapping, len(config.Volumes))
+	for i, vol := range config.Volumes {
+		devices[i] = ec2types.BlockDeviceMapping{
+			DeviceName: aws.String(awsDeviceName(i)),
+			Ebs: &ec2types.EbsBlockDevice{
+				VolumeSize:          aws.Int32(int32(vol.SizeGB)),
+				VolumeType:          awsVolumeType,
+				DeleteOnTermination: aws.Bool(true),
+			},
+		}
+	}
+
+	userData := fmt.Sprintf(`#!/bin/bash
+apt-get update
+apt-get install -y python3 nvme-cli
+
+# Mount volumes if specified
+%s
+`, p.generateVolumeMountScript(config.Volumes))
+
+	return &ec2.RunInstancesInput{
+		ImageId:             aws.String(imageID),
+		InstanceType:        ec2types.InstanceType(config.Size),
+		MinCount:            aws.Int32(1),
+		MaxCount:            aws.Int32(1),
+		KeyName:             aws.String(keyName),
+		SecurityGroupIds:    []string{groupID},
+		UserData:            aws.String(base64.StdEncoding.EncodeToString([]byte(userData))),
+		BlockDeviceMappings: devices,
+		TagSpecifications: []ec2types.TagSpecification{
+			{ResourceType: ec2types.ResourceTypeInstance, Tags: tags},
+			{ResourceType: ec2types.ResourceTypeVolume, Tags: tags},
+		},
+	}
+}
+
+// generateVolumeMountScript generates a bash script to mount the EBS volumes.
+// Depending on the instance family the volume attached as /dev/sdX shows up as
+// /dev/sdX, /dev/xvdX or as an NVMe device reporting the requested name in its
+// vendor specific controller data.
+func (p *AWSProvider) generateVolumeMountScript(volumes []talisTypes.VolumeConfig) string {
+	var script strings.Builder
+	for i, vol := range volumes {
+		if vol.MountPoint == "" {
+			continue
+		}
+
+		fs := vol.FileSystem
+		if fs == "" {
+			fs = "ext4"
+		}
+
+		name := strings.TrimPrefix(awsDeviceName(i), "/dev/")
+		script.WriteString(fmt.Sprintf(`
+# Mount volume %s
+mkdir -p %s
+device=""
+for i in $(seq 1 60); do
+    for candidate in /dev/%s /dev/xvd%s; do
+        [ -e "$candidate" ] && device=$candidate
+    done
+    for candidate in /dev/nvme*n1; do
+        nvme id-ctrl -v "$candidate" 2>/dev/null | grep -qw "%s" && device=$candidate
+    done
+    [ -n "$device" ] && break
+    sleep 5
+done
+if [ -n "$device" ]; then
+    blkid "$device" || mkfs.%s "$device"
+    echo "UUID=$(blkid -s UUID -o value "$device") %s %s defaults,nofail 0 2" >> /etc/fstab
+    mount %s || true
+fi
+`, vol.Name, vol.MountPoint, name, name[len(name)-1:], name, fs, vol.MountPoint, fs, vol.MountPoint))
+	}
+
+	return script.String()
+}
+
+// ensureKeyPair returns the name of the Talis key pair in the region of the client.
+// Key pairs are regional, so the public key of the Talis private key is imported
+// when no key pair with the configured name exists yet.
+func (p *AWSProvider) ensureKeyPair(ctx context.Context, client computeTypes.EC2Client) (string, error) {
+	keyName := os.Getenv(constants.EnvTalisSSHKeyName)
+	if keyName == "" {
+		return "", fmt.Errorf("environment variable %s not set, Talis SSH key name is required", constants.EnvTalisSSHKeyName)
+	}
+
+	logger.Debugf("🔑 Looking up key pair: %s", keyName)
+	output, err := client.DescribeKeyPairs(ctx, &ec2.DescribeKeyPairsInput{
+		Filters: []ec2types.Filter{ec2Filter("key-name", keyName)},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list key pairs: %w", err)
+	}
+	if len(output.KeyPairs) > 0 {
+		logger.Debugf("✅ Found key pair '%s'", keyName)
+		return keyName, nil
+	}
+
+	publicKey, err := talisPublicKey()
+	if err != nil {
+		return "", fmt.Errorf("key pair '%s' not found and could not be imported: %w", keyName, err)
+	}
+
+	logger.Debugf("🔑 Importing key pair '%s'", keyName)
+	_, err = client.ImportKeyPair(ctx, &ec2.ImportKeyPairInput{
+		KeyName:           aws.String(keyName),
+		PublicKeyMaterial: []byte(publicKey),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to import key pair '%s': %w", keyName, err)
+	}
+	return keyName, nil
+}
+
+// resolveImage returns the AMI ID for the requested image. AMI IDs are used as is,
+// aliases and name patterns resolve to the most recent matching AMI.
+func (p *AWSProvider) resolveImage(ctx context.Context, client computeTypes.EC2Client, image string) (string, error) {
+	if strings.HasPrefix(image, "ami-") {
+		return image, nil
+	}
+
+	var owners []string
+	namePattern := image
+	if alias, ok := awsImageAliases[image]; ok {
+		owners = []string{alias.owner}
+		namePattern = alias.name
+	}
+
+	output, err := client.DescribeImages(ctx, &ec2.DescribeImagesInput{
+		Owners:  owners,
+		Filters: []ec2types.Filter{ec2Filter("name", namePattern), ec2Filter("state", "available")},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe images: %w", err)
+	}
+	images := output.Images
+	if len(images) == 0 {
+		return "", fmt.Errorf("no AMI matches %s", namePattern)
+	}
+
+	// Creation dates are ISO 8601 timestamps and sort lexicographically
+	sort.Slice(images, func(i, j int) bool {
+		return aws.ToString(images[i].CreationDate) > aws.ToString(images[j].CreationDate)
+	})
+	imageID := aws.ToString(images[0].ImageId)
+	logger.Debugf("📀 Resolved image %s to %s (%s)", image, imageID, aws.ToString(images[0].Name))
+	return imageID, nil
+}
+
+// ensureSecurityGroup returns the ID of the security group of the project, creating it
+// with SSH access when it does not exist yet
+func (p *AWSProvider) ensureSecurityGroup(ctx context.Context, client computeTypes.EC2Client, projectName string) (string, error) {
+	groupName := awsSecurityGroupName(projectName)
+	output, err := client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
+		Filters: []ec2types.Filter{ec2Filter("group-name", groupName)},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe security groups: %w", err)
+	}
+	if len(output.SecurityGroups) > 0 {
+		groupID := aws.ToString(output.SecurityGroups[0].GroupId)
+		logger.Debugf("🔒 Reusing security group %s (%s)", groupName, groupID)
+		return groupID, nil
+	}
+
+	logger.Debugf("🔒 Creating security group %s", groupName)
+	created, err := client.CreateSecurityGroup(ctx, &ec2.CreateSecurityGroupInput{
+		GroupName:   aws.String(groupName),
+		Description: aws.String(fmt.Sprintf("Talis instances of project %s", projectName)),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create security group %s: %w", groupName, err)
+	}
+	groupID := aws.ToString(created.GroupId)
+	_, err = client.AuthorizeSecurityGroupIngress(ctx, &ec2.AuthorizeSecurityGroupIngressInput{
+		GroupId: aws.String(groupID),
+		IpPermissions: []ec2types.IpPermission{{
+			IpProtocol: aws.String("tcp"),
+			FromPort:   aws.Int32(22),
+			ToPort:     aws.Int32(22),
+			IpRanges:   []ec2types.IpRange{{CidrIp: aws.String("0.0.0.0/0")}},
+		}},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to allow SSH access in security group %s: %w", groupName, err)
+	}
+	return groupID, nil
+}
+
+// waitForInstanceRunning waits for an instance to be running with a public IPv4 address
+func (p *AWSProvider) waitForInstanceRunning(ctx context.Context, client computeTypes.EC2Client, instanceID string) (*ec2types.Instance, error) {
+	logger.Debug("⏳ Waiting for instance to be running...")
+	maxRetries := 30
+	interval := 10 * time.Second
+
+	for i := 0; i < maxRetries; i++ {
+		instances, err := describeInstances(ctx, client, ec2Filter("instance-id", instanceID))
+		if err != nil {
+			logger.Errorf("❌ Failed to get instance details: %v", err)
+		} else if len(instances) > 0 && ec2InstanceState(&instances[0]) == awsStateRunning && instances[0].PublicIpAddress != nil {
+			logger.Debugf("📍 Found public IP for instance: %s", aws.ToString(instances[0].PublicIpAddress))
+			return &instances[0], nil
+		}
+
+		logger.Debugf("⏳ Instance not running yet, retrying in 10 seconds (attempt %d/%d)...", i+1, maxRetries)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+
+	return nil, fmt.Errorf("instance created but not running after %d retries", maxRetries)
+}
+
+// DeleteInstance terminates an EC2 instance. Its EBS volumes are deleted on termination.
+// The instance is looked up through the reference tag like in GetInstance.
+func (p *AWSProvider) DeleteInstance(ctx context.Context, providerInstanceID int) error {
+	logger.Debugf("🗑️ Deleting AWS instance with ref: %d", providerInstanceID)
+
+	client, instance, region, err := p.getRefInstance(ctx, providerInstanceID)
+	if err != nil {
+		return err
+	}
+	if ec2Terminated(instance) {
+		return fmt.Errorf("instance with ref %d: %w", providerInstanceID, talisTypes.ErrProviderInstanceNotFound)
+	}
+
+	instanceID := aws.ToString(instance.InstanceId)
+	logger.Debugf("🗑️ Terminating instance %s in region %s", instanceID, region)
+	if _, err := client.TerminateInstances(ctx, &ec2.TerminateInstancesInput{InstanceIds: []string{instanceID}}); err != nil {
+		return fmt.Errorf("failed to delete instance: %w", err)
+	}
+
+	logger.Debugf("✅ Deleted AWS instance with ref: %d", providerInstanceID)
+	return nil
+}
+
+// GetInstance returns the current state of an EC2 instance.
+// The instance is looked up through the reference tag set on creation.
+func (p *AWSProvider) GetInstance(ctx context.Context, providerInstanceID int) (*talisTypes.ProviderInstance, error) {
+	_, ec2Instance, region, err := p.getRefInstance(ctx, providerInstanceID)
+	if err != nil {
+		return nil, err
+	}
+	instance := ec2ToProviderInstance(ec2Instance, region)
+	return &instance, nil
+}
+
+// getRefInstance looks up the EC2 instance tagged with the given reference in all regions,
+// starting with the default region and the regions this provider created instances in, and
+// returns it together with the client of its region.
+// A failed creation attempt may have left a terminated instance with the same reference behind,
+// so live instances are preferred. More than one live tagged instance means the reference is not
+// unique, and none of them is returned rather than acting on an instance that may belong to
+// someone else.
+func (p *AWSProvider) getRefInstance(
+	ctx context.Context,
+	providerInstanceID int,
+) (computeTypes.EC2Client, *ec2types.Instance, string, error) {
+	ref, err := deploymentRef(providerInstanceID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	regions, err := p.candidateRegions(ctx)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	type match struct {
+		client   computeTypes.EC2Client
+		instance *ec2types.Instance
+		region   string
+	}
+	var live []match
+	var terminated *match
+	for _, region := range regions {
+		client, err := p.client(region)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		instances, err := describeInstances(ctx, client, ec2Filter("tag:"+awsRefTag, ref))
+		if err != nil {
+			return nil, nil, "", fmt.Errorf("failed to look up instance in region %s: %w", region, err)
+		}
+		for i := range instances {
+			m := match{client: client, instance: &instances[i], region: region}
+			if ec2Terminated(m.instance) {
+				terminated = &m
+				continue
+			}
+			live = append(live, m)
+		}
+	}
+
+	switch {
+	case len(live) > 1:
+		return nil, nil, "", fmt.Errorf("%d instances are tagged with %s=%s, refusing to act on ref %d", len(live), awsRefTag, ref, providerInstanceID)
+	case len(live) == 1:
+		return live[0].client, live[0].instance, live[0].region, nil
+	case terminated != nil:
+		return terminated.client, terminated.instance, terminated.region, nil
+	default:
+		return nil, nil, "", fmt.Errorf("instance with ref %d: %w", providerInstanceID, talisTypes.ErrProviderInstanceNotFound)
+	}
+}
+
+// ListInstances returns the instances of all regions that are not terminated
+func (p *AWSProvider) ListInstances(ctx context.Context) ([]talisTypes.ProviderInstance, error) {
+	regions, err := p.candidateRegions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []talisTypes.ProviderInstance
+	for _, region := range regions {
+		client, err := p.client(region)
+		if err != nil {
+			return nil, err
+		}
+		instances, err := describeInstances(ctx, client, ec2Filter("instance-state-name", "pending", "running", "stopping", "stopped"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list instances in region %s: %w", region, err)
+		}
+		for i := range instances {
+			result = append(result, ec2ToProviderInstance(&instances[i], region))
+		}
+	}
+	return result, nil
+}
+
+// ImportInstance returns an existing EC2 instance with its attached EBS volumes, except the root volume
+func (p *AWSProvider) ImportInstance(ctx context.Context, nativeID string) (*talisTypes.ProviderInstance, error) {
+	client, ec2Instance, region, err := p.findInstance(ctx, nativeID)
+	if err != nil {
+		return nil, err
+	}
+	return p.importEC2Instance(ctx, client, ec2Instance, region)
+}
+
+// ReferenceInstance tags an existing EC2 instance with the given reference
+func (p *AWSProvider) ReferenceInstance(ctx context.Context, nativeID string, providerInstanceID int) error {
+	ref, err := deploymentRef(providerInstanceID)
+	if err != nil {
+		return err
+	}
+	client, _, _, err := p.findInstance(ctx, nativeID)
+	if err != nil {
+		return err
+	}
+	_, err = client.CreateTags(ctx, &ec2.CreateTagsInput{
+		Resources: []string{nativeID},
+		Tags:      []ec2types.Tag{{Key: aws.String(awsRefTag), Value: aws.String(ref)}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to tag instance: %w", err)
+	}
+	return nil
+}
+
+// findInstance looks up an EC2 instance by its instance ID in all regions and returns it
+// together with the client of its region
+func (p *AWSProvider) findInstance(ctx context.Context, nativeID string) (computeTypes.EC2Client, *ec2types.Instance, string, error) {
+	regions, err := p.candidateRegions(ctx)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	for _, region := range regions {
+		client, err := p.client(region)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		instances, err := describeInstances(ctx, client, ec2Filter("instance-id", nativeID))
+		if err != nil {
+			return nil, nil, "", fmt.Errorf("failed to look up instance in region %s: %w", region, err)
+		}
+		if len(instances) > 0 {
+			return client, &instances[0], region, nil
+		}
+	}
+
+	return nil, nil, "", fmt.Errorf("instance %s: %w", nativeID, talisTypes.ErrProviderInstanceNotFound)
+}
+
+// importEC2Instance collects the volumes of an instance
+func (p *AWSProvider) importEC2Instance(
+	ctx context.Context,
+	client computeTypes.EC2Client,
+	ec2Instance *ec2types.Instance,
+	region string,
+) (*talisTypes.ProviderInstance, error) {
+	instance := ec2ToProviderInstance(ec2Instance, region)
+
+	var volumeIDs []string
+	deviceNames := make(map[string]string)
+	for _, device := range ec2Instance.BlockDeviceMappings {
+		if device.Ebs == nil || device.Ebs.VolumeId == nil || aws.ToString(device.DeviceName) == aws.ToString(ec2Instance.RootDeviceName) {
+			continue
+		}
+		volumeID := aws.ToString(device.Ebs.VolumeId)
+		volumeIDs = append(volumeIDs, volumeID)
+		deviceNames[volumeID] = aws.ToString(device.DeviceName)
+	}
+	if len(volumeIDs) == 0 {
+		return &instance, nil
+	}
+
+	output, err := client.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{VolumeIds: volumeIDs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe volumes: %w", err)
+	}
+	for _, volume := range output.Volumes {
+		volumeID := aws.ToString(volume.VolumeId)
+		instance.Volumes = append(instance.Volumes, talisTypes.VolumeDetails{
+			ID:     volumeID,
+			Name:   deviceNames[volumeID],
+			Region: region,
+			SizeGB: int(aws.ToInt32(volume.Size)),
+		})
+	}
+	return &instance, nil
+}
+
+// ListRegions returns the regions enabled for the account
+func (p *AWSProvider) ListRegions(ctx context.Context) ([]talisTypes.ProviderRegion, error) {
+	client, err := p.client(p.config.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := describeRegions(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe regions: %w", err)
+	}
+
+	regions := make([]talisTypes.ProviderRegion, 0, len(names))
+	for _, name := range names {
+		regions = append(regions, talisTypes.ProviderRegion{ID: name, Name: name})
+	}
+	return regions, nil
+}
+
+// ListSizes returns the instance types offered in the default region
+func (p *AWSProvider) ListSizes(ctx context.Context) ([]talisTypes.ProviderSize, error) {
+	client, err := p.client(p.config.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	var sizes []talisTypes.ProviderSize
+	paginator := ec2.NewDescribeInstanceTypesPaginator(client, &ec2.DescribeInstanceTypesInput{MaxResults: aws.Int32(100)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe instance types: %w", err)
+		}
+		for _, instanceType := range page.InstanceTypes {
+			size := talisTypes.ProviderSize{ID: string(instanceType.InstanceType)}
+			if instanceType.VCpuInfo != nil {
+				size.CPU = int(aws.ToInt32(instanceType.VCpuInfo.DefaultVCpus))
+			}
+			if instanceType.MemoryInfo != nil {
+				size.MemoryMB = int(aws.ToInt64(instanceType.MemoryInfo.SizeInMiB))
+			}
+			if instanceType.InstanceStorageInfo != nil {
+				size.DiskGB = int(aws.ToInt64(instanceType.InstanceStorageInfo.TotalSizeInGB))
+			}
+			sizes = append(sizes, size)
+		}
+	}
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i].ID < sizes[j].ID })
+	return sizes, nil
+}
+
+// ListImages returns the well-known image aliases. AMI IDs and AMI name patterns are
+// accepted as well, see MatchesImage.
+func (p *AWSProvider) ListImages(_ context.Context) ([]talisTypes.ProviderImage, error) {
+	images := make([]talisTypes.ProviderImage, 0, len(awsImageAliases))
+	for alias, image := range awsImageAliases {
+		images = append(images, talisTypes.ProviderImage{
+			ID:           alias,
+			Name:         image.name,
+			Distribution: strings.SplitN(alias, "-", 2)[0],
+		})
+	}
+	sort.Slice(images, func(i, j int) bool { return images[i].ID < images[j].ID })
+	return images, nil
+}
+
+// MatchesImage accepts every image, AMI IDs and name patterns are resolved on creation
+func (p *AWSProvider) MatchesImage(_ string) bool {
+	return true
+}
+
+// ec2ToProviderInstance converts an EC2 instance into its provider-independent representation.
+// Instances without a reference tag of this deployment were not created by it and get a zero
+// ProviderInstanceID.
+func ec2ToProviderInstance(instance *ec2types.Instance, region string) talisTypes.ProviderInstance {
+	ref, _ := parseDeploymentRef(ec2Tag(instance, awsRefTag))
+	result := talisTypes.ProviderInstance{
+		ProviderInstanceID: ref,
+		NativeID:           aws.ToString(instance.InstanceId),
+		Name:               ec2Tag(instance, "Name"),
+		PublicIP:           aws.ToString(instance.PublicIpAddress),
+		Region:             region,
+		Size:               string(instance.InstanceType),
+		Image:              aws.ToString(instance.ImageId),
+	}
+	for _, tag := range instance.Tags {
+		result.Tags = append(result.Tags, labelTag(aws.ToString(tag.Key), aws.ToString(tag.Value)))
+	}
+
+	switch ec2InstanceState(instance) {
+	case ec2types.InstanceStateNamePending:
+		result.Status = talisTypes.ProviderInstanceStatusPending
+	case ec2types.InstanceStateNameRunning:
+		result.Status = talisTypes.ProviderInstanceStatusRunning
+	case ec2types.InstanceStateNameStopping, ec2types.InstanceStateNameStopped:
+		result.Status = talisTypes.ProviderInstanceStatusStopped
+	case ec2types.InstanceStateNameShuttingDown, ec2types.InstanceStateNameTerminated:
+		result.Status = talisTypes.ProviderInstanceStatusTerminated
+	default:
+		result.Status = talisTypes.ProviderInstanceStatusUnknown
+	}
+	return result
+}
+
+// ec2InstanceState returns the state of an EC2 instance
+func ec2InstanceState(instance *ec2types.Instance) ec2types.InstanceStateName {
+	if instance.State == nil {
+		return ""
+	}
+	return instance.State.Name
+}
+
+// ec2Terminated reports whether an EC2 instance is shutting down or terminated
+func ec2Terminated(instance *ec2types.Instance) bool {
+	state := ec2InstanceState(instance)
+	return state == ec2types.InstanceStateNameShuttingDown || state == ec2types.InstanceStateNameTerminated
+}
+
+// ec2Tag returns the value of the tag of an EC2 instance with the given key
+func ec2Tag(instance *ec2types.Instance, key string) string {
+	for _, tag := range instance.Tags {
+		if aws.ToString(tag.Key) == key {
+			return aws.ToString(tag.Value)
+		}
+	}
+	return ""
+}
+
+// ec2Filter builds a filter for EC2 describe calls
+func ec2Filter(name string, values ...string) ec2types.Filter {
+	return ec2types.Filter{Name: aws.String(name), Values: values}
+}
+
+// describeInstances returns the instances matching the filters, following pagination
+func describeInstances(ctx context.Context, client computeTypes.EC2Client, filters ...ec2types.Filter) ([]ec2types.Instance, error) {
+	var instances []ec2types.Instance
+	paginator := ec2.NewDescribeInstancesPaginator(client, &ec2.DescribeInstancesInput{Filters: filters})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, reservation := range page.Reservations {
+			instances = append(instances, reservation.Instances...)
+		}
+	}
+	return instances, nil
+}
+
+// describeRegions returns the names of the regions enabled for the account
+func describeRegions(ctx context.Context, client computeTypes.EC2Client) ([]string, error) {
+	output, err := client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(output.Regions))
+	for _, region := range output.Regions {
+		names = append(names, aws.ToString(region.RegionName))
+	}
+	return names, nil
+}
+
+// candidateRegions returns the regions to search for an instance, most likely first
+func (p *AWSProvider) candidateRegions(ctx context.Context) ([]string, error) {
+	regions := []string{p.config.Region}
+	seen := map[string]bool{p.config.Region: true}
+
+	p.mu.Lock()
+	for region := range p.clients {
+		if !seen[region] {
+			seen[region] = true
+			regions = append(regions, region)
+		}
+	}
+	p.mu.Unlock()
+
+	client, err := p.client(p.config.Region)
+	if err != nil {
+		return nil, err
+	}
+	all, err := describeRegions(ctx, client)
+	if err != nil {
+		logger.Warnf("⚠️ Warning: Failed to list regions: %v", err)
+		return regions, nil
+	}
+	for _, region := range all {
+		if !seen[region] {
+			seen[region] = true
+			regions = append(regions, region)
+		}
+	}
+	return regions, nil
+}
+
+// awsSecurityGroupName returns the name of the security group shared by a project's instances
+func awsSecurityGroupName(projectName string) string {
+	return "talis-" + projectName
+}
+
+// awsDeviceName returns the device name of the i-th volume: /dev/sdf, /dev/sdg, ...
+func awsDeviceName(i int) string {
+	return fmt.Sprintf("/dev/sd%c", 'f'+i)
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,372 @@
+package compute
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/celestiaorg/talis/internal/constants"
+	"github.com/celestiaorg/talis/internal/types"
+	"github.com/celestiaorg/talis/test/mocks"
+)
+
+// newTestAWSProvider creates a new AWSProvider talking to a fake EC2 server through the endpoint override
+func newTestAWSProvider(t *testing.T) (*AWSProvider, *mocks.FakeEC2Server) {
+	server := mocks.NewFakeEC2Server()
+	t.Cleanup(server.Close)
+
+	t.Setenv(awsAccessKeyIDEnv, mocks.DefaultEC2AccessKeyID)
+	t.Setenv(awsSecretAccessKeyEnv, mocks.DefaultEC2SecretAccessKey)
+	t.Setenv(awsRegionEnv, mocks.DefaultEC2Region)
+	t.Setenv(awsEC2EndpointEnv, server.URL)
+	t.Setenv(constants.EnvTalisSSHKeyName, mocks.DefaultEC2KeyName)
+	t.Setenv(constants.EnvTalisDeploymentID, "test")
+
+	provider, err := NewAWSProvider()
+	require.NoError(t, err)
+	return provider, server
+}
+
+func TestAWSProvider(t *testing.T) {
+	t.Setenv(constants.EnvTalisDeploymentID, "test")
+
+	t.Run("NewAWSProvider_MissingCredentials", func(t *testing.T) {
+		t.Setenv(awsAccessKeyIDEnv, "")
+		t.Setenv(awsSecretAccessKeyEnv, "secret")
+		provider, err := NewAWSProvider()
+		assert.Error(t, err)
+		assert.Nil(t, provider)
+	})
+
+	t.Run("NewAWSProvider_InvalidDeploymentID", func(t *testing.T) {
+		t.Setenv(awsAccessKeyIDEnv, "key")
+		t.Setenv(awsSecretAccessKeyEnv, "secret")
+		t.Setenv(constants.EnvTalisDeploymentID, "Test_1")
+		provider, err := NewAWSProvider()
+		assert.ErrorContains(t, err, constants.EnvTalisDeploymentID)
+		assert.Nil(t, provider)
+	})
+
+	t.Run("NewComputeProvider", func(t *testing.T) {
+		t.Setenv(awsAccessKeyIDEnv, "key")
+		t.Setenv(awsSecretAccessKeyEnv, "secret")
+		t.Setenv(awsRegionEnv, "")
+		t.Setenv(awsDefaultRegionEnv, "eu-west-1")
+		provider, err := NewComputeProvider("aws")
+		require.NoError(t, err)
+		require.IsType(t, &AWSProvider{}, provider)
+		assert.Equal(t, "eu-west-1", provider.(*AWSProvider).config.Region)
+	})
+
+	t.Run("ValidateCredentials", func(t *testing.T) {
+		provider, server := newTestAWSProvider(t)
+		assert.NoError(t, provider.ValidateCredentials())
+
+		server.Fail("DescribeRegions", http.StatusUnauthorized, "AuthFailure")
+		err := provider.ValidateCredentials()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "AuthFailure")
+	})
+
+	t.Run("CreateInstance_Success", func(t *testing.T) {
+		provider, server := newTestAWSProvider(t)
+
+		config := &types.InstanceRequest{
+			InstanceID:        7,
+			ProjectName:       "test-project",
+			Name:              "node",
+			Region:            mocks.DefaultEC2Region,
+			Size:              mocks.DefaultEC2InstanceType,
+			Image:             "ubuntu-22.04",
+			NumberOfInstances: 1,
+			Tags:              []string{"validator"},
+			Volumes: []types.VolumeConfig{
+				{Name: "data", SizeGB: 40, MountPoint: "/mnt/data"},
+				{Name: "logs", SizeGB: 10, MountPoint: "/var/log/app", FileSystem: "xfs"},
+			},
+		}
+
+		err := provider.CreateInstance(context.Background(), config)
+		require.NoError(t, err)
+		assert.Equal(t, mocks.DefaultEC2InstanceIP, config.PublicIP)
+		assert.Equal(t, 7, config.ProviderInstanceID)
+
+		// The alias resolves to the most recent matching AMI
+		params := server.LastRunInstances
+		assert.Equal(t, mocks.DefaultEC2ImageID, params.Get("ImageId"))
+		assert.Equal(t, mocks.DefaultEC2InstanceType, params.Get("InstanceType"))
+		assert.Equal(t, mocks.DefaultEC2KeyName, params.Get("KeyName"))
+		assert.Equal(t, "/dev/sdf", params.Get("BlockDeviceMapping.1.DeviceName"))
+		assert.Equal(t, "40", params.Get("BlockDeviceMapping.1.Ebs.VolumeSize"))
+		assert.Equal(t, "gp3", params.Get("BlockDeviceMapping.1.Ebs.VolumeType"))
+		assert.Equal(t, "/dev/sdg", params.Get("BlockDeviceMapping.2.DeviceName"))
+		assert.Contains(t, server.LastUserData, "python3")
+		assert.Contains(t, server.LastUserData, "/mnt/data")
+		assert.Contains(t, server.LastUserData, "mkfs.xfs")
+
+		groups := server.SecurityGroups(mocks.DefaultEC2Region)
+		require.Len(t, groups, 1)
+		assert.Equal(t, "talis-test-project", groups[0].GroupName)
+		assert.Equal(t, groups[0].GroupID, params.Get("SecurityGroupId.1"))
+		assert.Equal(t, []string{"tcp:22:0.0.0.0/0"}, server.Ingress(groups[0].GroupID))
+
+		instances := server.Instances(mocks.DefaultEC2Region)
+		require.Len(t, instances, 1)
+		assert.Equal(t, "node", instances[0].Tag("Name"))
+		assert.Equal(t, "test-7", instances[0].Tag(awsRefTag))
+		assert.Equal(t, "test-project", instances[0].Tag(awsProjectTag))
+
+		require.Len(t, config.VolumeDetails, 2)
+		volumes := server.Volumes()
+		require.Len(t, volumes, 2)
+		assert.Equal(t, []string{volumes[0].VolumeID, volumes[1].VolumeID}, config.VolumeIDs)
+		assert.Equal(t, "/var/log/app", config.VolumeDetails[1].MountPoint)
+		assert.Equal(t, 10, config.VolumeDetails[1].SizeGB)
+	})
+
+	t.Run("CreateInstance_ReusesSecurityGroup", func(t *testing.T) {
+		provider, server := newTestAWSProvider(t)
+
+		for i := 1; i <= 2; i++ {
+			err := provider.CreateInstance(context.Background(), &types.InstanceRequest{
+				InstanceID:  uint(i),
+				ProjectName: "test-project",
+				Region:      mocks.DefaultEC2Region,
+				Size:        mocks.DefaultEC2InstanceType,
+				Image:       mocks.DefaultEC2ImageID,
+			})
+			require.NoError(t, err)
+		}
+
+		assert.Len(t, server.SecurityGroups(mocks.DefaultEC2Region), 1)
+		assert.Len(t, server.Instances(mocks.DefaultEC2Region), 2)
+	})
+
+	t.Run("CreateInstance_ImportsKeyPair", func(t *testing.T) {
+		provider, server := newTestAWSProvider(t)
+
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		block, err := ssh.MarshalPrivateKey(privateKey, "")
+		require.NoError(t, err)
+		t.Setenv(constants.EnvTalisSSHKey, string(pem.EncodeToMemory(block)))
+
+		// Key pairs are regional, the default key only exists in the default region
+		err = provider.CreateInstance(context.Background(), &types.InstanceRequest{
+			InstanceID:  1,
+			ProjectName: "test-project",
+			Region:      "eu-central-1",
+			Size:        mocks.DefaultEC2InstanceType,
+			Image:       mocks.DefaultEC2ImageID,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{mocks.DefaultEC2KeyName}, server.KeyPairs("eu-central-1"))
+		assert.Len(t, server.Instances("eu-central-1"), 1)
+	})
+
+	t.Run("CreateInstance_UnknownImage", func(t *testing.T) {
+		provider, _ := newTestAWSProvider(t)
+
+		err := provider.CreateInstance(context.Background(), &types.InstanceRequest{
+			InstanceID:  1,
+			ProjectName: "test-project",
+			Region:      mocks.DefaultEC2Region,
+			Size:        mocks.DefaultEC2InstanceType,
+			Image:       "windows-*",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to resolve image")
+
+		err = provider.CreateInstance(context.Background(), &types.InstanceRequest{
+			ProjectName: "test-project",
+			Region:      mocks.DefaultEC2Region,
+			Size:        mocks.DefaultEC2InstanceType,
+			Image:       mocks.DefaultEC2ImageID,
+		})
+		assert.ErrorContains(t, err, "no instance ID")
+	})
+
+	t.Run("DeleteInstance", func(t *testing.T) {
+		provider, server := newTestAWSProvider(t)
+
+		config := &types.InstanceRequest{
+			InstanceID:  1,
+			ProjectName: "test-project",
+			Region:      mocks.DefaultEC2Region,
+			Size:        mocks.DefaultEC2InstanceType,
+			Image:       mocks.DefaultEC2ImageID,
+			Volumes:     []types.VolumeConfig{{Name: "data", SizeGB: 40}},
+		}
+		require.NoError(t, provider.CreateInstance(context.Background(), config))
+		require.Len(t, server.Volumes(), 1)
+
+		// A fresh provider has to find the instance by its reference tag
+		provider, err := NewAWSProvider()
+		require.NoError(t, err)
+		require.NoError(t, provider.DeleteInstance(context.Background(), config.ProviderInstanceID))
+		assert.Len(t, server.TerminatedInstances, 1)
+		assert.Empty(t, server.Volumes())
+
+		err = provider.DeleteInstance(context.Background(), config.ProviderInstanceID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+
+		// A new attempt reuses the reference, the terminated instance is ignored
+		require.NoError(t, provider.CreateInstance(context.Background(), config))
+		instance, err := provider.GetInstance(context.Background(), config.ProviderInstanceID)
+		require.NoError(t, err)
+		assert.Equal(t, types.ProviderInstanceStatusRunning, instance.Status)
+	})
+
+	t.Run("DeleteInstance_OtherRegion", func(t *testing.T) {
+		provider, server := newTestAWSProvider(t)
+
+		config := &types.InstanceRequest{
+			InstanceID:  1,
+			ProjectName: "test-project",
+			Region:      mocks.DefaultEC2Region,
+			Size:        mocks.DefaultEC2InstanceType,
+			Image:       mocks.DefaultEC2ImageID,
+		}
+		require.NoError(t, provider.CreateInstance(context.Background(), config))
+
+		// Move the default region so the instance is only found through DescribeRegions
+		t.Setenv(awsRegionEnv, "eu-central-1")
+		provider, err := NewAWSProvider()
+		require.NoError(t, err)
+		require.NoError(t, provider.DeleteInstance(context.Background(), config.ProviderInstanceID))
+		assert.Len(t, server.TerminatedInstances, 1)
+	})
+
+	t.Run("DeleteInstance_OtherDeployment", func(t *testing.T) {
+		provider, server := newTestAWSProvider(t)
+
+		t.Setenv(constants.EnvTalisDeploymentID, "staging")
+		config := &types.InstanceRequest{
+			InstanceID:  1,
+			ProjectName: "test-project",
+			Region:      mocks.DefaultEC2Region,
+			Size:        mocks.DefaultEC2InstanceType,
+			Image:       mocks.DefaultEC2ImageID,
+		}
+		require.NoError(t, provider.CreateInstance(context.Background(), config))
+
+		// The instance of the same Talis instance ID of another deployment is left alone
+		t.Setenv(constants.EnvTalisDeploymentID, "test")
+		_, err := provider.GetInstance(context.Background(), 1)
+		assert.ErrorIs(t, err, types.ErrProviderInstanceNotFound)
+		assert.Error(t, provider.DeleteInstance(context.Background(), 1))
+		assert.Empty(t, server.TerminatedInstances)
+
+		instances, err := provider.ListInstances(context.Background())
+		require.NoError(t, err)
+		require.Len(t, instances, 1)
+		assert.Zero(t, instances[0].ProviderInstanceID)
+	})
+
+	t.Run("DeleteInstance_Ambiguous", func(t *testing.T) {
+		provider, server := newTestAWSProvider(t)
+		server.AddInstance(mocks.DefaultEC2Region, []mocks.FakeEC2Tag{{Key: awsRefTag, Value: "test-7"}})
+		server.AddInstance("eu-central-1", []mocks.FakeEC2Tag{{Key: awsRefTag, Value: "test-7"}})
+
+		err := provider.DeleteInstance(context.Background(), 7)
+		assert.ErrorContains(t, err, "2 instances are tagged with talis-ref=test-7")
+		assert.Empty(t, server.TerminatedInstances)
+
+		_, err = provider.GetInstance(context.Background(), 7)
+		assert.ErrorContains(t, err, "refusing to act")
+	})
+
+	t.Run("GetInstance_ListInstances", func(t *testing.T) {
+		provider, _ := newTestAWSProvider(t)
+
+		config := &types.InstanceRequest{
+			InstanceID:  1,
+			ProjectName: "test-project",
+			Name:        "node",
+			Region:      mocks.DefaultEC2Region,
+			Size:        mocks.DefaultEC2InstanceType,
+			Image:       mocks.DefaultEC2ImageID,
+		}
+		require.NoError(t, provider.CreateInstance(context.Background(), config))
+
+		instance, err := provider.GetInstance(context.Background(), config.ProviderInstanceID)
+		require.NoError(t, err)
+		assert.Equal(t, config.ProviderInstanceID, instance.ProviderInstanceID)
+		assert.Equal(t, "node", instance.Name)
+		assert.Equal(t, types.ProviderInstanceStatusRunning, instance.Status)
+		assert.Equal(t, config.PublicIP, instance.PublicIP)
+		assert.Equal(t, mocks.DefaultEC2Region, instance.Region)
+
+		instances, err := provider.ListInstances(context.Background())
+		require.NoError(t, err)
+		require.Len(t, instances, 1)
+		assert.Equal(t, *instance, instances[0])
+
+		_, err = provider.GetInstance(context.Background(), config.ProviderInstanceID+1)
+		assert.ErrorIs(t, err, types.ErrProviderInstanceNotFound)
+	})
+
+	t.Run("ImportInstance", func(t *testing.T) {
+		provider, server := newTestAWSProvider(t)
+
+		id := server.AddInstance("eu-central-1", []mocks.FakeEC2Tag{{Key: "Name", Value: "manual"}}, 50)
+
+		instance, err := provider.ImportInstance(context.Background(), id)
+		require.NoError(t, err)
+		assert.Zero(t, instance.ProviderInstanceID)
+		assert.Equal(t, "manual", instance.Name)
+		assert.Equal(t, "eu-central-1", instance.Region)
+		assert.Equal(t, mocks.DefaultEC2InstanceIP, instance.PublicIP)
+		require.Len(t, instance.Volumes, 1)
+		assert.Equal(t, "/dev/sdf", instance.Volumes[0].Name)
+		assert.Equal(t, 50, instance.Volumes[0].SizeGB)
+
+		require.NoError(t, provider.ReferenceInstance(context.Background(), id, 42))
+		found, err := provider.GetInstance(context.Background(), 42)
+		require.NoError(t, err)
+		assert.Equal(t, id, found.NativeID)
+		again, err := provider.ImportInstance(context.Background(), id)
+		require.NoError(t, err)
+		assert.Equal(t, 42, again.ProviderInstanceID)
+
+		_, err = provider.ImportInstance(context.Background(), "i-missing")
+		assert.ErrorIs(t, err, types.ErrProviderInstanceNotFound)
+		assert.ErrorIs(t, provider.ReferenceInstance(context.Background(), "i-missing", 43), types.ErrProviderInstanceNotFound)
+	})
+
+	t.Run("Catalog", func(t *testing.T) {
+		provider, server := newTestAWSProvider(t)
+
+		regions, err := provider.ListRegions(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []types.ProviderRegion{
+			{ID: mocks.DefaultEC2Region, Name: mocks.DefaultEC2Region},
+			{ID: "eu-central-1", Name: "eu-central-1"},
+		}, regions)
+
+		sizes, err := provider.ListSizes(context.Background())
+		require.NoError(t, err)
+		require.Len(t, sizes, 3)
+		assert.Equal(t, types.ProviderSize{ID: "m5d.large", CPU: 2, MemoryMB: 8192, DiskGB: 75}, sizes[0])
+		assert.Equal(t, mocks.DefaultEC2InstanceType, sizes[1].ID)
+
+		images, err := provider.ListImages(context.Background())
+		require.NoError(t, err)
+		require.Len(t, images, len(awsImageAliases))
+		assert.Equal(t, "debian-12", images[0].ID)
+		assert.Equal(t, "ubuntu", images[1].Distribution)
+		assert.True(t, provider.MatchesImage(mocks.DefaultEC2ImageID))
+
+		server.Fail("DescribeInstanceTypes", http.StatusUnauthorized, "AuthFailure")
+		_, err = provider.ListSizes(context.Background())
+		assert.Error(t, err)
+	})
+}
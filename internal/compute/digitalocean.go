@@ -7,9 +7,11 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -56,6 +58,42 @@ func (c *DefaultDOClient) DeleteInstance(ctx context.Context, dropletID int) err
 	return provider.DeleteInstance(ctx, dropletID)
 }
 
+// GetInstance returns the current state of a droplet
+func (c *DefaultDOClient) GetInstance(ctx context.Context, dropletID int) (*talisTypes.ProviderInstance, error) {
+	provider := &DigitalOceanProvider{doClient: c}
+	return provider.GetInstance(ctx, dropletID)
+}
+
+// ListInstances returns all droplets of the account
+func (c *DefaultDOClient) ListInstances(ctx context.Context) ([]talisTypes.ProviderInstance, error) {
+	provider := &DigitalOceanProvider{doClient: c}
+	return provider.ListInstances(ctx)
+}
+
+// ImportInstance returns an existing droplet with its volumes
+func (c *DefaultDOClient) ImportInstance(ctx context.Context, nativeID string) (*talisTypes.ProviderInstance, error) {
+	provider := &DigitalOceanProvider{doClient: c}
+	return provider.ImportInstance(ctx, nativeID)
+}
+
+// ListRegions returns the available DigitalOcean regions
+func (c *DefaultDOClient) ListRegions(ctx context.Context) ([]talisTypes.ProviderRegion, error) {
+	provider := &DigitalOceanProvider{doClient: c}
+	return provider.ListRegions(ctx)
+}
+
+// ListSizes returns the available droplet sizes
+func (c *DefaultDOClient) ListSizes(ctx context.Context) ([]talisTypes.ProviderSize, error) {
+	provider := &DigitalOceanProvider{doClient: c}
+	return provider.ListSizes(ctx)
+}
+
+// ListImages returns the images available to the account
+func (c *DefaultDOClient) ListImages(ctx context.Context) ([]talisTypes.ProviderImage, error) {
+	provider := &DigitalOceanProvider{doClient: c}
+	return provider.ListImages(ctx)
+}
+
 // Droplets returns the droplet service
 func (c *DefaultDOClient) Droplets() computeTypes.DropletService {
 	return &DefaultDropletService{service: c.client.Droplets}
@@ -74,6 +112,15 @@ func (c *DefaultDOClient) Storage() computeTypes.StorageService {
 	}
 }
 
+// Catalog returns the catalog service
+func (c *DefaultDOClient) Catalog() computeTypes.CatalogService {
+	return &DefaultCatalogService{
+		regions: c.client.Regions,
+		sizes:   c.client.Sizes,
+		images:  c.client.Images,
+	}
+}
+
 // NewDOClient creates a new DigitalOcean client
 func NewDOClient(token string) computeTypes.DOClient {
 	client := godo.NewFromToken(token)
@@ -107,6 +154,28 @@ func (s *DefaultDropletService) List(ctx context.Context, opt *godo.ListOptions)
 	return s.service.List(ctx, opt)
 }
 
+// DefaultCatalogService adapts the godo region, size and image services to our CatalogService interface
+type DefaultCatalogService struct {
+	regions godo.RegionsService
+	sizes   godo.SizesService
+	images  godo.ImagesService
+}
+
+// ListRegions lists all regions
+func (s *DefaultCatalogService) ListRegions(ctx context.Context, opt *godo.ListOptions) ([]godo.Region, *godo.Response, error) {
+	return s.regions.List(ctx, opt)
+}
+
+// ListSizes lists all droplet sizes
+func (s *DefaultCatalogService) ListSizes(ctx context.Context, opt *godo.ListOptions) ([]godo.Size, *godo.Response, error) {
+	return s.sizes.List(ctx, opt)
+}
+
+// ListImages lists all public and private images
+func (s *DefaultCatalogService) ListImages(ctx context.Context, opt *godo.ListOptions) ([]godo.Image, *godo.Response, error) {
+	return s.images.List(ctx, opt)
+}
+
 // DefaultKeyService adapts godo.KeyService to our KeyService interface
 type DefaultKeyService struct {
 	service godo.KeysService
@@ -156,7 +225,9 @@ func (s *DefaultStorageService) waitForVolumeAction(
 	actionType string,
 ) (*godo.Response, error) {
 	// Wait a few seconds before checking the action status
-	time.Sleep(5 * time.Second)
+	if err := sleepContext(ctx, 5*time.Second); err != nil {
+		return nil, fmt.Errorf("stopped waiting for volume %s action: %w", actionType, err)
+	}
 
 	maxRetries := 10
 	for i := 0; i < maxRetries; i++ {
@@ -164,7 +235,9 @@ func (s *DefaultStorageService) waitForVolumeAction(
 		if err != nil {
 			// If we get a 404, wait and retry
 			if resp != nil && resp.StatusCode == 404 {
-				time.Sleep(5 * time.Second)
+				if err := sleepContext(ctx, 5*time.Second); err != nil {
+					return resp, fmt.Errorf("stopped waiting for volume %s action: %w", actionType, err)
+				}
 				continue
 			}
 			return resp, fmt.Errorf("failed to get volume action status: %w", err)
@@ -178,7 +251,9 @@ func (s *DefaultStorageService) waitForVolumeAction(
 			return resp, fmt.Errorf("volume %s action errored", actionType)
 		}
 
-		time.Sleep(5 * time.Second)
+		if err := sleepContext(ctx, 5*time.Second); err != nil {
+			return resp, fmt.Errorf("stopped waiting for volume %s action: %w", actionType, err)
+		}
 	}
 
 	return nil, fmt.Errorf("volume %s action did not complete after %d retries", actionType, maxRetries)
@@ -238,6 +313,12 @@ func (p *DigitalOceanProvider) GetEnvironmentVars() map[string]string {
 	}
 }
 
+// MountsVolumesOnProvision reports that volumes are attached to droplets without being mounted,
+// provisioning mounts them
+func (p *DigitalOceanProvider) MountsVolumesOnProvision() bool {
+	return true
+}
+
 // waitForPublicIP waits for a droplet to get a public IP address
 func (p *DigitalOceanProvider) waitForPublicIP(ctx context.Context, dropletID int) (string, error) {
 	if p.doClient == nil {
@@ -252,7 +333,9 @@ func (p *DigitalOceanProvider) waitForPublicIP(ctx context.Context, dropletID in
 		d, _, err := p.doClient.Droplets().Get(ctx, dropletID)
 		if err != nil {
 			logger.Errorf("❌ Failed to get droplet details: %v", err)
-			time.Sleep(interval)
+			if err := sleepContext(ctx, interval); err != nil {
+				return "", fmt.Errorf("stopped waiting for the IP of droplet %d: %w", dropletID, err)
+			}
 			continue
 		}
 
@@ -266,7 +349,9 @@ func (p *DigitalOceanProvider) waitForPublicIP(ctx context.Context, dropletID in
 		}
 
 		logger.Debugf("⏳ IP not assigned yet, retrying in 10 seconds (attempt %d/%d)...", i+1, maxRetries)
-		time.Sleep(interval)
+		if err := sleepContext(ctx, interval); err != nil {
+			return "", fmt.Errorf("stopped waiting for the IP of droplet %d: %w", dropletID, err)
+		}
 	}
 
 	return "", fmt.Errorf("droplet created but no public IP found after %d retries", maxRetries)
@@ -364,7 +449,7 @@ func (p *DigitalOceanProvider) createSingleDroplet(
 	}
 
 	// Initialize instance info
-	config.ProviderInstanceID = droplet.ID
+	config.SetProviderInstanceID(droplet.ID)
 	config.VolumeIDs = []string{}
 	config.VolumeDetails = []talisTypes.VolumeDetails{}
 
@@ -519,7 +604,9 @@ func (p *DigitalOceanProvider) createAndAttachVolumes(
 
 		// Wait for volume to be ready
 		logger.Debugf("⏳ Waiting for volume to be ready...")
-		time.Sleep(10 * time.Second)
+		if err := sleepContext(ctx, 10*time.Second); err != nil {
+			return nil, nil, fmt.Errorf("stopped waiting for volume %s: %w", volName, err)
+		}
 
 		// Verify volume exists and is ready
 		vol, _, err := p.doClient.Storage().GetVolume(ctx, volume.ID)
@@ -546,7 +633,9 @@ func (p *DigitalOceanProvider) createAndAttachVolumes(
 
 		// Wait for volume to be attached
 		logger.Debugf("⏳ Waiting for volume to be attached...")
-		time.Sleep(10 * time.Second)
+		if err := sleepContext(ctx, 10*time.Second); err != nil {
+			return nil, nil, fmt.Errorf("stopped waiting for volume %s: %w", volName, err)
+		}
 
 		logger.Debugf("✅ Successfully created and attached volume %s (%s)", vol.Name, volume.ID)
 	}
@@ -555,6 +644,18 @@ func (p *DigitalOceanProvider) createAndAttachVolumes(
 	return volumeIDs, volumeDetails, nil
 }
 
+// sleepContext waits for d. It returns the cause of the cancellation if ctx is cancelled first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	case <-t.C:
+		return nil
+	}
+}
+
 // generateRandomSuffix generates a random 6-character string
 func generateRandomSuffix() string {
 	bytes := make([]byte, 3) // 3 bytes = 6 hex characters
@@ -620,6 +721,225 @@ func (p *DigitalOceanProvider) DeleteInstance(ctx context.Context, dropletID int
 	return nil
 }
 
+// GetInstance returns the current state of a DigitalOcean droplet
+func (p *DigitalOceanProvider) GetInstance(ctx context.Context, dropletID int) (*talisTypes.ProviderInstance, error) {
+	if p.doClient == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	droplet, resp, err := p.doClient.Droplets().Get(ctx, dropletID)
+	if err != nil {
+		if isDONotFound(resp, err) {
+			return nil, fmt.Errorf("droplet %d: %w", dropletID, talisTypes.ErrProviderInstanceNotFound)
+		}
+		return nil, fmt.Errorf("failed to get droplet details: %w", err)
+	}
+
+	instance := dropletToProviderInstance(droplet)
+	return &instance, nil
+}
+
+// ListInstances returns all droplets of the DigitalOcean account
+func (p *DigitalOceanProvider) ListInstances(ctx context.Context) ([]talisTypes.ProviderInstance, error) {
+	if p.doClient == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	instances := []talisTypes.ProviderInstance{}
+	opt := &godo.ListOptions{Page: 1, PerPage: 200}
+	for {
+		droplets, resp, err := p.doClient.Droplets().List(ctx, opt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list droplets: %w", err)
+		}
+		for i := range droplets {
+			instances = append(instances, dropletToProviderInstance(&droplets[i]))
+		}
+		if resp == nil || resp.Links == nil || resp.Links.IsLastPage() {
+			return instances, nil
+		}
+		opt.Page++
+	}
+}
+
+// ImportInstance returns an existing DigitalOcean droplet with its attached volumes.
+// Droplet IDs are used as provider instance IDs, so the droplet is not modified.
+func (p *DigitalOceanProvider) ImportInstance(ctx context.Context, nativeID string) (*talisTypes.ProviderInstance, error) {
+	if p.doClient == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	dropletID, err := strconv.Atoi(nativeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid droplet ID %q: %w", nativeID, err)
+	}
+
+	droplet, resp, err := p.doClient.Droplets().Get(ctx, dropletID)
+	if err != nil {
+		if isDONotFound(resp, err) {
+			return nil, fmt.Errorf("droplet %d: %w", dropletID, talisTypes.ErrProviderInstanceNotFound)
+		}
+		return nil, fmt.Errorf("failed to get droplet details: %w", err)
+	}
+
+	instance := dropletToProviderInstance(droplet)
+	for _, volumeID := range droplet.VolumeIDs {
+		volume, _, err := p.doClient.Storage().GetVolume(ctx, volumeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get volume %s: %w", volumeID, err)
+		}
+		detail := talisTypes.VolumeDetails{
+			ID:     volume.ID,
+			Name:   volume.Name,
+			SizeGB: int(volume.SizeGigaBytes),
+		}
+		if volume.Region != nil {
+			detail.Region = volume.Region.Slug
+		}
+		instance.Volumes = append(instance.Volumes, detail)
+	}
+	return &instance, nil
+}
+
+// ListRegions returns the DigitalOcean regions droplets can currently be created in
+func (p *DigitalOceanProvider) ListRegions(ctx context.Context) ([]talisTypes.ProviderRegion, error) {
+	if p.doClient == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	regions, err := listDOPages(ctx, p.doClient.Catalog().ListRegions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list regions: %w", err)
+	}
+
+	result := make([]talisTypes.ProviderRegion, 0, len(regions))
+	for _, region := range regions {
+		if !region.Available {
+			continue
+		}
+		result = append(result, talisTypes.ProviderRegion{ID: region.Slug, Name: region.Name})
+	}
+	return result, nil
+}
+
+// ListSizes returns the droplet sizes that can currently be created
+func (p *DigitalOceanProvider) ListSizes(ctx context.Context) ([]talisTypes.ProviderSize, error) {
+	if p.doClient == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	sizes, err := listDOPages(ctx, p.doClient.Catalog().ListSizes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sizes: %w", err)
+	}
+
+	result := make([]talisTypes.ProviderSize, 0, len(sizes))
+	for _, size := range sizes {
+		if !size.Available {
+			continue
+		}
+		result = append(result, talisTypes.ProviderSize{
+			ID:       size.Slug,
+			CPU:      size.Vcpus,
+			MemoryMB: size.Memory,
+			DiskGB:   size.Disk,
+			Regions:  size.Regions,
+		})
+	}
+	return result, nil
+}
+
+// ListImages returns the public images and the private images of the account.
+// Images are identified by their slug, images without a slug by their numeric ID.
+func (p *DigitalOceanProvider) ListImages(ctx context.Context) ([]talisTypes.ProviderImage, error) {
+	if p.doClient == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	images, err := listDOPages(ctx, p.doClient.Catalog().ListImages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images: %w", err)
+	}
+
+	result := make([]talisTypes.ProviderImage, 0, len(images))
+	for _, image := range images {
+		id := image.Slug
+		if id == "" {
+			id = strconv.Itoa(image.ID)
+		}
+		result = append(result, talisTypes.ProviderImage{ID: id, Name: image.Name, Distribution: image.Distribution})
+	}
+	return result, nil
+}
+
+// MatchesImage accepts numeric image IDs, which droplets can be created from
+// even for images that are listed by their slug
+func (p *DigitalOceanProvider) MatchesImage(image string) bool {
+	_, err := strconv.Atoi(image)
+	return err == nil
+}
+
+// listDOPages collects all pages of a DigitalOcean list call
+func listDOPages[T any](ctx context.Context, list func(context.Context, *godo.ListOptions) ([]T, *godo.Response, error)) ([]T, error) {
+	var items []T
+	opt := &godo.ListOptions{Page: 1, PerPage: 200}
+	for {
+		page, resp, err := list(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+		if resp == nil || resp.Links == nil || resp.Links.IsLastPage() {
+			return items, nil
+		}
+		opt.Page++
+	}
+}
+
+// dropletToProviderInstance converts a droplet into its provider-independent representation
+func dropletToProviderInstance(droplet *godo.Droplet) talisTypes.ProviderInstance {
+	instance := talisTypes.ProviderInstance{
+		ProviderInstanceID: droplet.ID,
+		NativeID:           fmt.Sprint(droplet.ID),
+		Name:               droplet.Name,
+		Size:               droplet.SizeSlug,
+		Tags:               droplet.Tags,
+	}
+	instance.PublicIP, _ = droplet.PublicIPv4()
+	if droplet.Region != nil {
+		instance.Region = droplet.Region.Slug
+	}
+	if instance.Size == "" && droplet.Size != nil {
+		instance.Size = droplet.Size.Slug
+	}
+	if droplet.Image != nil {
+		instance.Image = droplet.Image.Slug
+	}
+
+	switch droplet.Status {
+	case "new":
+		instance.Status = talisTypes.ProviderInstanceStatusPending
+	case "active":
+		instance.Status = talisTypes.ProviderInstanceStatusRunning
+	case "off":
+		instance.Status = talisTypes.ProviderInstanceStatusStopped
+	case "archive":
+		instance.Status = talisTypes.ProviderInstanceStatusTerminated
+	default:
+		instance.Status = talisTypes.ProviderInstanceStatusUnknown
+	}
+	return instance
+}
+
+// isDONotFound reports whether a DigitalOcean API call failed because the resource does not exist
+func isDONotFound(resp *godo.Response, err error) bool {
+	if resp != nil && resp.Response != nil && resp.StatusCode == http.StatusNotFound {
+		return true
+	}
+	var errResp *godo.ErrorResponse
+	return errors.As(err, &errResp) && errResp.Response != nil && errResp.Response.StatusCode == http.StatusNotFound
+}
+
 // NewDigitalOceanProvider creates a new DigitalOcean provider instance
 func NewDigitalOceanProvider() (*DigitalOceanProvider, error) {
 	token := os.Getenv("DIGITALOCEAN_TOKEN")
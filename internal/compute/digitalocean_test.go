@@ -467,4 +467,76 @@ func TestDigitalOceanProvider(t *testing.T) {
 			assert.NoError(t, err)
 		})
 	})
+
+	t.Run("GetInstance", func(t *testing.T) {
+		mockClient := mocks.NewMockDOClient()
+		provider := &DigitalOceanProvider{doClient: mockClient}
+
+		t.Run("droplet not found", func(t *testing.T) {
+			mockClient.SimulateNotFound()
+
+			_, err := provider.GetInstance(context.Background(), 123)
+			assert.ErrorIs(t, err, types.ErrProviderInstanceNotFound)
+		})
+
+		t.Run("successful get", func(t *testing.T) {
+			mockClient.ResetToStandard()
+
+			instance, err := provider.GetInstance(context.Background(), 123)
+			require.NoError(t, err)
+			assert.Equal(t, types.ProviderInstanceStatusRunning, instance.Status)
+
+			instances, err := provider.ListInstances(context.Background())
+			require.NoError(t, err)
+			assert.NotEmpty(t, instances)
+		})
+
+		t.Run("import with volumes", func(t *testing.T) {
+			mockClient.ResetToStandard()
+			mockClient.MockDropletService.GetFunc = func(_ context.Context, id int) (*godo.Droplet, *godo.Response, error) {
+				droplet := *mockClient.StandardResponses.Droplets.DefaultDroplet
+				droplet.ID = id
+				droplet.Tags = []string{"testnet"}
+				droplet.VolumeIDs = []string{"test-volume-id"}
+				return &droplet, nil, nil
+			}
+
+			instance, err := provider.ImportInstance(context.Background(), "456")
+			require.NoError(t, err)
+			assert.Equal(t, 456, instance.ProviderInstanceID)
+			assert.Equal(t, []string{"testnet"}, instance.Tags)
+			require.Len(t, instance.Volumes, 1)
+			assert.Equal(t, "test-volume", instance.Volumes[0].Name)
+			assert.Equal(t, 100, instance.Volumes[0].SizeGB)
+
+			_, err = provider.ImportInstance(context.Background(), "droplet")
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("Catalog", func(t *testing.T) {
+		mockClient := mocks.NewMockDOClient()
+		provider := &DigitalOceanProvider{doClient: mockClient}
+
+		regions, err := provider.ListRegions(context.Background())
+		require.NoError(t, err)
+		require.Len(t, regions, len(mocks.DefaultCatalogRegions))
+		assert.Equal(t, mocks.DefaultCatalogRegions[0], regions[0].ID)
+
+		sizes, err := provider.ListSizes(context.Background())
+		require.NoError(t, err)
+		require.Len(t, sizes, len(mocks.DefaultCatalogSizes))
+		assert.ElementsMatch(t, mocks.DefaultCatalogRegions, sizes[0].Regions)
+
+		images, err := provider.ListImages(context.Background())
+		require.NoError(t, err)
+		require.Len(t, images, len(mocks.DefaultCatalogImages))
+		assert.Equal(t, mocks.DefaultCatalogImages[0], images[0].ID)
+		assert.True(t, provider.MatchesImage("123456"))
+		assert.False(t, provider.MatchesImage("ubuntu-18-04-x64"))
+
+		mockClient.SimulateRateLimit()
+		_, err = provider.ListImages(context.Background())
+		assert.Error(t, err)
+	})
 }
@@ -0,0 +1,674 @@
+package compute
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+
+	computeTypes "github.com/celestiaorg/talis/internal/compute/types"
+	"github.com/celestiaorg/talis/internal/constants"
+	"github.com/celestiaorg/talis/internal/logger"
+	talisTypes "github.com/celestiaorg/talis/internal/types"
+)
+
+const (
+	// hetznerTokenEnv is the environment variable containing the Hetzner Cloud API token
+	hetznerTokenEnv = "HCLOUD_TOKEN"
+	// hetznerEndpointEnv optionally overrides the Hetzner Cloud API endpoint
+	hetznerEndpointEnv = "HCLOUD_ENDPOINT"
+)
+
+// DefaultHetznerClient is the default implementation of HetznerClient
+type DefaultHetznerClient struct {
+	client *hcloud.Client
+}
+
+// NewHetznerClient creates a new Hetzner Cloud client. An empty endpoint uses the public API.
+func NewHetznerClient(token, endpoint string) computeTypes.HetznerClient {
+	opts := []hcloud.ClientOption{
+		hcloud.WithToken(token),
+		hcloud.WithApplication("talis", ""),
+	}
+	if endpoint != "" {
+		opts = append(opts, hcloud.WithEndpoint(endpoint))
+	}
+	return &DefaultHetznerClient{client: hcloud.NewClient(opts...)}
+}
+
+// ValidateCredentials validates the provider credentials by performing a cheap read-only call
+func (c *DefaultHetznerClient) ValidateCredentials(ctx context.Context) error {
+	_, _, err := c.client.Location.List(ctx, hcloud.LocationListOpts{})
+	return err
+}
+
+// Servers returns the server service
+func (c *DefaultHetznerClient) Servers() computeTypes.HetznerServerService {
+	return &DefaultHetznerServerService{service: &c.client.Server}
+}
+
+// SSHKeys returns the SSH key service
+func (c *DefaultHetznerClient) SSHKeys() computeTypes.HetznerSSHKeyService {
+	return &c.client.SSHKey
+}
+
+// Volumes returns the volume service
+func (c *DefaultHetznerClient) Volumes() computeTypes.HetznerVolumeService {
+	return &c.client.Volume
+}
+
+// Actions returns the action service
+func (c *DefaultHetznerClient) Actions() computeTypes.HetznerActionService {
+	return &c.client.Action
+}
+
+// Catalog returns the catalog service
+func (c *DefaultHetznerClient) Catalog() computeTypes.HetznerCatalogService {
+	return &DefaultHetznerCatalogService{client: c.client}
+}
+
+// DefaultHetznerCatalogService lists locations, server types and images with hcloud-go
+type DefaultHetznerCatalogService struct {
+	client *hcloud.Client
+}
+
+// Locations lists all locations
+func (s *DefaultHetznerCatalogService) Locations(ctx context.Context) ([]*hcloud.Location, error) {
+	return s.client.Location.All(ctx)
+}
+
+// ServerTypes lists all server types
+func (s *DefaultHetznerCatalogService) ServerTypes(ctx context.Context) ([]*hcloud.ServerType, error) {
+	return s.client.ServerType.All(ctx)
+}
+
+// Images lists the available system and app images
+func (s *DefaultHetznerCatalogService) Images(ctx context.Context) ([]*hcloud.Image, error) {
+	return s.client.Image.AllWithOpts(ctx, hcloud.ImageListOpts{
+		Type:   []hcloud.ImageType{hcloud.ImageTypeSystem, hcloud.ImageTypeApp},
+		Status: []hcloud.ImageStatus{hcloud.ImageStatusAvailable},
+	})
+}
+
+// DefaultHetznerServerService adapts hcloud.ServerClient to our HetznerServerService interface
+type DefaultHetznerServerService struct {
+	service *hcloud.ServerClient
+}
+
+// Create creates a new server
+func (s *DefaultHetznerServerService) Create(ctx context.Context, opts hcloud.ServerCreateOpts) (hcloud.ServerCreateResult, *hcloud.Response, error) {
+	return s.service.Create(ctx, opts)
+}
+
+// GetByID gets a server by its ID
+func (s *DefaultHetznerServerService) GetByID(ctx context.Context, id int64) (*hcloud.Server, *hcloud.Response, error) {
+	return s.service.GetByID(ctx, id)
+}
+
+// All lists all servers
+func (s *DefaultHetznerServerService) All(ctx context.Context) ([]*hcloud.Server, error) {
+	return s.service.All(ctx)
+}
+
+// Delete deletes a server
+func (s *DefaultHetznerServerService) Delete(ctx context.Context, server *hcloud.Server) (*hcloud.ServerDeleteResult, *hcloud.Response, error) {
+	return s.service.DeleteWithResult(ctx, server)
+}
+
+// HetznerProvider implements the Provider interface for Hetzner Cloud
+type HetznerProvider struct {
+	client computeTypes.HetznerClient
+}
+
+// NewHetznerProvider creates a new Hetzner Cloud provider instance
+func NewHetznerProvider() (*HetznerProvider, error) {
+	token := os.Getenv(hetznerTokenEnv)
+	if token == "" {
+		return nil, fmt.Errorf("%s environment variable is not set", hetznerTokenEnv)
+	}
+
+	return &HetznerProvider{
+		client: NewHetznerClient(token, os.Getenv(hetznerEndpointEnv)),
+	}, nil
+}
+
+// SetClient sets the Hetzner client for testing
+func (p *HetznerProvider) SetClient(client computeTypes.HetznerClient) {
+	p.client = client
+}
+
+// ConfigureProvider is a no-op for Hetzner
+func (p *HetznerProvider) ConfigureProvider(_ interface{}) error {
+	return nil
+}
+
+// ValidateCredentials validates the Hetzner Cloud credentials
+func (p *HetznerProvider) ValidateCredentials() error {
+	if p.client == nil {
+		return fmt.Errorf("client not initialized")
+	}
+	if err := p.client.ValidateCredentials(context.Background()); err != nil {
+		return fmt.Errorf("hetzner credential validation failed: %w", err)
+	}
+	return nil
+}
+
+// GetEnvironmentVars returns the environment variables needed for the provider
+func (p *HetznerProvider) GetEnvironmentVars() map[string]string {
+	return map[string]string{
+		hetznerTokenEnv:    os.Getenv(hetznerTokenEnv),
+		hetznerEndpointEnv: os.Getenv(hetznerEndpointEnv),
+	}
+}
+
+// CreateInstance creates a new Hetzner server.
+// Volumes are created first in the requested location so they can be attached
+// when the server is created and mounted by the cloud-init script.
+func (p *HetznerProvider) CreateInstance(ctx context.Context, config *talisTypes.InstanceRequest) error {
+	if p.client == nil {
+		return fmt.Errorf("client not initialized")
+	}
+
+	logger.Debugf("🚀 Creating Hetzner server for project: %s", config.ProjectName)
+	logger.Debugf("  Location: %s", config.Region)
+	logger.Debugf("  Server type: %s", config.Size)
+	logger.Debugf("  Image: %s", config.Image)
+
+	sshKey, err := p.getSSHKey(ctx)
+	if err != nil {
+		logger.Errorf("❌ Failed to get SSH key: %v", err)
+		return fmt.Errorf("failed to get SSH key: %w", err)
+	}
+
+	config.VolumeIDs = []string{}
+	config.VolumeDetails = []talisTypes.VolumeDetails{}
+
+	volumes, err := p.createVolumes(ctx, config)
+	if err != nil {
+		return fmt.Errorf("failed to create volumes: %w", err)
+	}
+
+	opts := p.createServerOpts(config, sshKey, volumes)
+	logger.Debugf("  Sending server creation request: %s", opts.Name)
+	result, _, err := p.client.Servers().Create(ctx, opts)
+	if err != nil {
+		logger.Errorf("❌ Failed to create server: %v", err)
+		p.deleteVolumes(ctx, volumes)
+		return fmt.Errorf("failed to create server: %w", err)
+	}
+
+	config.SetProviderInstanceID(int(result.Server.ID))
+
+	if err := p.client.Actions().WaitFor(ctx, hetznerActions(result.Action, result.NextActions)...); err != nil {
+		return fmt.Errorf("failed waiting for server %s to be created: %w", opts.Name, err)
+	}
+
+	ip, err := p.waitForPublicIP(ctx, result.Server.ID)
+	if err != nil {
+		errMsg := fmt.Errorf("❌ Failed to get public IP for server %s: %w", opts.Name, err)
+		logger.Error(errMsg)
+		return errMsg
+	}
+	config.PublicIP = ip
+
+	for i, volume := range volumes {
+		config.VolumeIDs = append(config.VolumeIDs, strconv.FormatInt(volume.ID, 10))
+		config.VolumeDetails = append(config.VolumeDetails, talisTypes.VolumeDetails{
+			ID:         strconv.FormatInt(volume.ID, 10),
+			Name:       volume.Name,
+			Region:     config.Region,
+			SizeGB:     config.Volumes[i].SizeGB,
+			MountPoint: config.Volumes[i].MountPoint,
+		})
+	}
+
+	logger.Infof("✅ Server '%s' (ID: %d) created successfully with IP %s", opts.Name, result.Server.ID, ip)
+	return nil
+}
+
+// createServerOpts builds the ServerCreateOpts for the given request
+func (p *HetznerProvider) createServerOpts(
+	config *talisTypes.InstanceRequest,
+	sshKey *hcloud.SSHKey,
+	volumes []*hcloud.Volume,
+) hcloud.ServerCreateOpts {
+	var serverName string
+	if config.Name != "" {
+		if config.NumberOfInstances > 1 {
+			serverName = fmt.Sprintf("%s-%d", config.Name, config.InstanceIndex+1)
+		} else {
+			serverName = config.Name
+		}
+	} else {
+		serverName = fmt.Sprintf("%s-%s", config.ProjectName, generateRandomSuffix())
+	}
+
+	automount := false
+	return hcloud.ServerCreateOpts{
+		Name:       serverName,
+		ServerType: &hcloud.ServerType{Name: config.Size},
+		Image:      &hcloud.Image{Name: config.Image},
+		Location:   &hcloud.Location{Name: config.Region},
+		SSHKeys:    []*hcloud.SSHKey{sshKey},
+		Volumes:    volumes,
+		Automount:  &automount,
+		Labels:     map[string]string{"talis-project": hetznerLabelValue(config.ProjectName)},
+		UserData: fmt.Sprintf(`#!/bin/bash
+apt-get update
+apt-get install -y python3
+
+# Mount volumes if specified
+%s
+`, p.generateVolumeMountScript(volumes, config.Volumes)),
+	}
+}
+
+// generateVolumeMountScript generates a bash script to mount the attached volumes.
+// Hetzner exposes attached volumes as /dev/disk/by-id/scsi-0HC_Volume_<id>.
+func (p *HetznerProvider) generateVolumeMountScript(volumes []*hcloud.Volume, configs []talisTypes.VolumeConfig) string {
+	if len(volumes) == 0 {
+		return ""
+	}
+
+	var script strings.Builder
+	for i, volume := range volumes {
+		vol := configs[i]
+		if vol.MountPoint == "" {
+			continue
+		}
+
+		fs := vol.FileSystem
+		if fs == "" {
+			fs = hcloud.VolumeFormatExt4
+		}
+
+		script.WriteString(fmt.Sprintf(`
+# Mount volume %s
+mkdir -p %s
+device=/dev/disk/by-id/scsi-0HC_Volume_%d
+for i in $(seq 1 30); do [ -e "$device" ] && break; sleep 2; done
+if [ -e "$device" ]; then
+    echo "$device %s %s defaults,nofail 0 2" >> /etc/fstab
+    mount %s || true
+fi
+`, vol.Name, vol.MountPoint, volume.ID, vol.MountPoint, fs, vol.MountPoint))
+	}
+
+	return script.String()
+}
+
+// getSSHKey looks up the Talis SSH key registered with Hetzner
+func (p *HetznerProvider) getSSHKey(ctx context.Context) (*hcloud.SSHKey, error) {
+	keyName := os.Getenv(constants.EnvTalisSSHKeyName)
+	if keyName == "" {
+		return nil, fmt.Errorf("environment variable %s not set, Talis SSH key name is required", constants.EnvTalisSSHKeyName)
+	}
+
+	logger.Debugf("🔑 Looking up SSH key: %s", keyName)
+	key, _, err := p.client.SSHKeys().GetByName(ctx, keyName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up SSH key: %w", err)
+	}
+	if key == nil {
+		return nil, fmt.Errorf("SSH key '%s' not found", keyName)
+	}
+
+	logger.Debugf("✅ Found SSH key '%s' with ID: %d", keyName, key.ID)
+	return key, nil
+}
+
+// createVolumes creates the requested volumes in the instance location.
+// On failure any volume created so far is deleted again.
+func (p *HetznerProvider) createVolumes(ctx context.Context, config *talisTypes.InstanceRequest) ([]*hcloud.Volume, error) {
+	volumes := make([]*hcloud.Volume, 0, len(config.Volumes))
+
+	for _, volConfig := range config.Volumes {
+		fs := volConfig.FileSystem
+		if fs == "" {
+			fs = hcloud.VolumeFormatExt4
+		}
+
+		volName := fmt.Sprintf("%s-%s-%s", config.ProjectName, volConfig.Name, generateRandomSuffix())
+		logger.Debugf("📦 Creating volume %s (%d GB)", volName, volConfig.SizeGB)
+		result, _, err := p.client.Volumes().Create(ctx, hcloud.VolumeCreateOpts{
+			Name:     volName,
+			Size:     volConfig.SizeGB,
+			Location: &hcloud.Location{Name: config.Region},
+			Format:   &fs,
+			Labels:   map[string]string{"talis-project": hetznerLabelValue(config.ProjectName)},
+		})
+		if err != nil {
+			logger.Errorf("❌ Failed to create volume: %v", err)
+			p.deleteVolumes(ctx, volumes)
+			return nil, fmt.Errorf("failed to create volume %s: %w", volName, err)
+		}
+
+		if err := p.client.Actions().WaitFor(ctx, hetznerActions(result.Action, result.NextActions)...); err != nil {
+			p.deleteVolumes(ctx, append(volumes, result.Volume))
+			return nil, fmt.Errorf("failed waiting for volume %s: %w", volName, err)
+		}
+
+		logger.Debugf("✅ Volume created successfully: %s (ID: %d)", volName, result.Volume.ID)
+		volumes = append(volumes, result.Volume)
+	}
+
+	return volumes, nil
+}
+
+// deleteVolumes deletes the given volumes, logging instead of failing on errors
+func (p *HetznerProvider) deleteVolumes(ctx context.Context, volumes []*hcloud.Volume) {
+	for _, volume := range volumes {
+		logger.Debugf("🗑️ Deleting volume %d", volume.ID)
+		if _, err := p.client.Volumes().Delete(ctx, volume); err != nil {
+			logger.Warnf("⚠️ Warning: Failed to delete volume %d: %v", volume.ID, err)
+		}
+	}
+}
+
+// waitForPublicIP waits for a server to get a public IPv4 address
+func (p *HetznerProvider) waitForPublicIP(ctx context.Context, serverID int64) (string, error) {
+	logger.Debug("⏳ Waiting for server to get an IP address...")
+	maxRetries := 10
+	interval := 10 * time.Second
+
+	for i := 0; i < maxRetries; i++ {
+		server, _, err := p.client.Servers().GetByID(ctx, serverID)
+		if err != nil {
+			logger.Errorf("❌ Failed to get server details: %v", err)
+		} else if server != nil && server.PublicNet.IPv4.IP != nil && !server.PublicNet.IPv4.IP.IsUnspecified() {
+			ip := server.PublicNet.IPv4.IP.String()
+			logger.Debugf("📍 Found public IP for server: %s", ip)
+			return ip, nil
+		}
+
+		logger.Debugf("⏳ IP not assigned yet, retrying in 10 seconds (attempt %d/%d)...", i+1, maxRetries)
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+
+	return "", fmt.Errorf("server created but no public IP found after %d retries", maxRetries)
+}
+
+// DeleteInstance deletes a Hetzner server and its attached volumes
+func (p *HetznerProvider) DeleteInstance(ctx context.Context, providerInstanceID int) error {
+	if p.client == nil {
+		return fmt.Errorf("client not initialized")
+	}
+
+	logger.Debugf("🗑️ Deleting Hetzner server: %d", providerInstanceID)
+
+	server, _, err := p.client.Servers().GetByID(ctx, int64(providerInstanceID))
+	if err != nil {
+		return fmt.Errorf("failed to get server details: %w", err)
+	}
+	if server == nil {
+		return fmt.Errorf("server %d not found", providerInstanceID)
+	}
+
+	// Volumes must be detached before they can be deleted
+	for _, volume := range server.Volumes {
+		logger.Debugf("🗑️ Detaching and deleting volume: %d", volume.ID)
+		action, _, err := p.client.Volumes().Detach(ctx, volume)
+		if err != nil {
+			logger.Warnf("⚠️ Warning: Failed to detach volume %d: %v", volume.ID, err)
+			continue
+		}
+		if err := p.client.Actions().WaitFor(ctx, action); err != nil {
+			logger.Warnf("⚠️ Warning: Failed waiting for volume %d to detach: %v", volume.ID, err)
+			continue
+		}
+		if _, err := p.client.Volumes().Delete(ctx, volume); err != nil {
+			logger.Warnf("⚠️ Warning: Failed to delete volume %d: %v", volume.ID, err)
+		}
+	}
+
+	result, _, err := p.client.Servers().Delete(ctx, server)
+	if err != nil {
+		if hcloud.IsError(err, hcloud.ErrorCodeNotFound) {
+			return fmt.Errorf("server %d not found: %w", providerInstanceID, err)
+		}
+		return fmt.Errorf("failed to delete server: %w", err)
+	}
+	if result != nil && result.Action != nil {
+		if err := p.client.Actions().WaitFor(ctx, result.Action); err != nil {
+			return fmt.Errorf("failed waiting for server %d to be deleted: %w", providerInstanceID, err)
+		}
+	}
+
+	logger.Debugf("✅ Deleted server: %d", providerInstanceID)
+	return nil
+}
+
+// GetInstance returns the current state of a Hetzner server
+func (p *HetznerProvider) GetInstance(ctx context.Context, providerInstanceID int) (*talisTypes.ProviderInstance, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	server, _, err := p.client.Servers().GetByID(ctx, int64(providerInstanceID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server details: %w", err)
+	}
+	if server == nil {
+		return nil, fmt.Errorf("server %d: %w", providerInstanceID, talisTypes.ErrProviderInstanceNotFound)
+	}
+
+	instance := hetznerServerToProviderInstance(server)
+	return &instance, nil
+}
+
+// ListInstances returns all servers of the Hetzner Cloud project
+func (p *HetznerProvider) ListInstances(ctx context.Context) ([]talisTypes.ProviderInstance, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	servers, err := p.client.Servers().All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list servers: %w", err)
+	}
+
+	instances := make([]talisTypes.ProviderInstance, 0, len(servers))
+	for _, server := range servers {
+		instances = append(instances, hetznerServerToProviderInstance(server))
+	}
+	return instances, nil
+}
+
+// ImportInstance returns an existing Hetzner server with its attached volumes.
+// Server IDs are used as provider instance IDs, so the server is not modified.
+func (p *HetznerProvider) ImportInstance(ctx context.Context, nativeID string) (*talisTypes.ProviderInstance, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	serverID, err := strconv.ParseInt(nativeID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server ID %q: %w", nativeID, err)
+	}
+
+	server, _, err := p.client.Servers().GetByID(ctx, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get server details: %w", err)
+	}
+	if server == nil {
+		return nil, fmt.Errorf("server %d: %w", serverID, talisTypes.ErrProviderInstanceNotFound)
+	}
+
+	instance := hetznerServerToProviderInstance(server)
+	// The server only references its volumes by ID
+	for _, ref := range server.Volumes {
+		volume, _, err := p.client.Volumes().GetByID(ctx, ref.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get volume %d: %w", ref.ID, err)
+		}
+		if volume == nil {
+			return nil, fmt.Errorf("volume %d not found", ref.ID)
+		}
+		detail := talisTypes.VolumeDetails{
+			ID:     strconv.FormatInt(volume.ID, 10),
+			Name:   volume.Name,
+			SizeGB: volume.Size,
+		}
+		if volume.Location != nil {
+			detail.Region = volume.Location.Name
+		}
+		instance.Volumes = append(instance.Volumes, detail)
+	}
+	return &instance, nil
+}
+
+// ListRegions returns the Hetzner locations
+func (p *HetznerProvider) ListRegions(ctx context.Context) ([]talisTypes.ProviderRegion, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	locations, err := p.client.Catalog().Locations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list locations: %w", err)
+	}
+
+	regions := make([]talisTypes.ProviderRegion, 0, len(locations))
+	for _, location := range locations {
+		regions = append(regions, talisTypes.ProviderRegion{ID: location.Name, Name: location.Description})
+	}
+	return regions, nil
+}
+
+// ListSizes returns the server types that are not deprecated, with the locations they are priced in
+func (p *HetznerProvider) ListSizes(ctx context.Context) ([]talisTypes.ProviderSize, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	serverTypes, err := p.client.Catalog().ServerTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list server types: %w", err)
+	}
+
+	sizes := make([]talisTypes.ProviderSize, 0, len(serverTypes))
+	for _, serverType := range serverTypes {
+		if serverType.IsDeprecated() {
+			continue
+		}
+		size := talisTypes.ProviderSize{
+			ID:       serverType.Name,
+			CPU:      serverType.Cores,
+			MemoryMB: int(serverType.Memory * 1024),
+			DiskGB:   serverType.Disk,
+		}
+		for _, pricing := range serverType.Pricings {
+			if pricing.Location != nil {
+				size.Regions = append(size.Regions, pricing.Location.Name)
+			}
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes, nil
+}
+
+// ListImages returns the available system and app images by name
+func (p *HetznerProvider) ListImages(ctx context.Context) ([]talisTypes.ProviderImage, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	hcloudImages, err := p.client.Catalog().Images(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images: %w", err)
+	}
+
+	images := make([]talisTypes.ProviderImage, 0, len(hcloudImages))
+	seen := make(map[string]bool, len(hcloudImages))
+	for _, image := range hcloudImages {
+		// Images are listed once per architecture
+		if image.Name == "" || seen[image.Name] {
+			continue
+		}
+		seen[image.Name] = true
+		images = append(images, talisTypes.ProviderImage{ID: image.Name, Name: image.Description, Distribution: image.OSFlavor})
+	}
+	return images, nil
+}
+
+// MatchesImage accepts numeric image IDs, which are used for snapshots and backups
+func (p *HetznerProvider) MatchesImage(image string) bool {
+	_, err := strconv.ParseInt(image, 10, 64)
+	return err == nil
+}
+
+// hetznerServerToProviderInstance converts a server into its provider-independent representation
+func hetznerServerToProviderInstance(server *hcloud.Server) talisTypes.ProviderInstance {
+	instance := talisTypes.ProviderInstance{
+		ProviderInstanceID: int(server.ID),
+		NativeID:           strconv.FormatInt(server.ID, 10),
+		Name:               server.Name,
+	}
+	if ip := server.PublicNet.IPv4.IP; ip != nil {
+		instance.PublicIP = ip.String()
+	}
+	if server.Datacenter != nil && server.Datacenter.Location != nil {
+		instance.Region = server.Datacenter.Location.Name
+	}
+	if server.ServerType != nil {
+		instance.Size = server.ServerType.Name
+	}
+	if server.Image != nil {
+		instance.Image = server.Image.Name
+	}
+	instance.Tags = labelTags(server.Labels)
+
+	switch server.Status {
+	case hcloud.ServerStatusInitializing, hcloud.ServerStatusStarting, hcloud.ServerStatusRebuilding, hcloud.ServerStatusMigrating:
+		instance.Status = talisTypes.ProviderInstanceStatusPending
+	case hcloud.ServerStatusRunning:
+		instance.Status = talisTypes.ProviderInstanceStatusRunning
+	case hcloud.ServerStatusStopping, hcloud.ServerStatusOff:
+		instance.Status = talisTypes.ProviderInstanceStatusStopped
+	case hcloud.ServerStatusDeleting:
+		instance.Status = talisTypes.ProviderInstanceStatusTerminated
+	default:
+		instance.Status = talisTypes.ProviderInstanceStatusUnknown
+	}
+	return instance
+}
+
+// hetznerActions collects the non-nil actions returned by a create call
+func hetznerActions(action *hcloud.Action, next []*hcloud.Action) []*hcloud.Action {
+	actions := make([]*hcloud.Action, 0, len(next)+1)
+	if action != nil {
+		actions = append(actions, action)
+	}
+	for _, a := range next {
+		if a != nil {
+			actions = append(actions, a)
+		}
+	}
+	return actions
+}
+
+// hetznerLabelValue converts a string into a valid Hetzner label value
+func hetznerLabelValue(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	value := strings.Trim(b.String(), "-_.")
+	if len(value) > 63 {
+		value = strings.Trim(value[:63], "-_.")
+	}
+	return value
+}
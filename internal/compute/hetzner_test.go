@@ -0,0 +1,231 @@
+package compute
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/talis/internal/constants"
+	"github.com/celestiaorg/talis/internal/types"
+	"github.com/celestiaorg/talis/test/mocks"
+)
+
+// newTestHetznerProvider creates a new HetznerProvider with a mock client for testing
+func newTestHetznerProvider() (*HetznerProvider, *mocks.MockHetznerClient) {
+	mockClient := mocks.NewMockHetznerClient()
+	provider := &HetznerProvider{}
+	provider.SetClient(mockClient)
+	return provider, mockClient
+}
+
+func TestHetznerProvider(t *testing.T) {
+	t.Run("NewHetznerProvider_MissingToken", func(t *testing.T) {
+		t.Setenv(hetznerTokenEnv, "")
+		provider, err := NewHetznerProvider()
+		assert.Error(t, err)
+		assert.Nil(t, provider)
+	})
+
+	t.Run("NewComputeProvider", func(t *testing.T) {
+		t.Setenv(hetznerTokenEnv, "test-token")
+		provider, err := NewComputeProvider("hetzner")
+		require.NoError(t, err)
+		assert.IsType(t, &HetznerProvider{}, provider)
+	})
+
+	t.Run("ValidateCredentials", func(t *testing.T) {
+		provider, mockClient := newTestHetznerProvider()
+		assert.NoError(t, provider.ValidateCredentials())
+
+		mockClient.SimulateAuthenticationFailure()
+		err := provider.ValidateCredentials()
+		assert.Error(t, err)
+		assert.True(t, hcloud.IsError(err, hcloud.ErrorCodeUnauthorized))
+
+		provider = &HetznerProvider{}
+		err = provider.ValidateCredentials()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "client not initialized")
+	})
+
+	t.Run("GetEnvironmentVars", func(t *testing.T) {
+		t.Setenv(hetznerTokenEnv, "test-token")
+		provider, _ := newTestHetznerProvider()
+		assert.Equal(t, "test-token", provider.GetEnvironmentVars()[hetznerTokenEnv])
+	})
+
+	t.Run("CreateInstance_Success", func(t *testing.T) {
+		t.Setenv(constants.EnvTalisSSHKeyName, mocks.DefaultHetznerKeyName)
+		provider, mockClient := newTestHetznerProvider()
+
+		config := &types.InstanceRequest{
+			ProjectName:       "test-project",
+			Name:              "node",
+			Region:            "fsn1",
+			Size:              "cx22",
+			Image:             "ubuntu-22.04",
+			NumberOfInstances: 2,
+			InstanceIndex:     1,
+			Volumes: []types.VolumeConfig{
+				{Name: "data", SizeGB: 20, MountPoint: "/mnt/data"},
+			},
+		}
+
+		err := provider.CreateInstance(context.Background(), config)
+		require.NoError(t, err)
+		assert.Equal(t, mocks.DefaultHetznerServerIP, config.PublicIP)
+		assert.Equal(t, int(mocks.DefaultHetznerServerID), config.ProviderInstanceID)
+
+		// The volume is created up front and attached through the create request
+		opts := mockClient.MockServerService.LastCreateOpts
+		assert.Equal(t, "node-2", opts.Name)
+		assert.Equal(t, "cx22", opts.ServerType.Name)
+		assert.Equal(t, "ubuntu-22.04", opts.Image.Name)
+		assert.Equal(t, "fsn1", opts.Location.Name)
+		assert.Equal(t, mocks.DefaultHetznerKeyID, opts.SSHKeys[0].ID)
+		require.Len(t, opts.Volumes, 1)
+		assert.Contains(t, opts.UserData, "scsi-0HC_Volume_"+strconv.FormatInt(mocks.DefaultHetznerVolumeID, 10))
+		assert.Contains(t, opts.UserData, "/mnt/data")
+
+		require.Len(t, config.VolumeDetails, 1)
+		assert.Equal(t, strconv.FormatInt(mocks.DefaultHetznerVolumeID, 10), config.VolumeIDs[0])
+		assert.Equal(t, 20, config.VolumeDetails[0].SizeGB)
+		assert.Equal(t, "/mnt/data", config.VolumeDetails[0].MountPoint)
+	})
+
+	t.Run("CreateInstance_SSHKey_NotFound", func(t *testing.T) {
+		t.Setenv(constants.EnvTalisSSHKeyName, "not-existing-key")
+		provider, _ := newTestHetznerProvider()
+
+		err := provider.CreateInstance(context.Background(), &types.InstanceRequest{
+			ProjectName: "test-project",
+			Region:      "fsn1",
+			Size:        "cx22",
+			Image:       "ubuntu-22.04",
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get SSH key")
+	})
+
+	t.Run("CreateInstance_ServerFailure_CleansUpVolumes", func(t *testing.T) {
+		t.Setenv(constants.EnvTalisSSHKeyName, mocks.DefaultHetznerKeyName)
+		provider, mockClient := newTestHetznerProvider()
+		mockClient.MockServerService.CreateFunc = func(_ context.Context, _ hcloud.ServerCreateOpts) (hcloud.ServerCreateResult, *hcloud.Response, error) {
+			return hcloud.ServerCreateResult{}, nil, mocks.ErrHetznerRateLimit
+		}
+
+		err := provider.CreateInstance(context.Background(), &types.InstanceRequest{
+			ProjectName: "test-project",
+			Region:      "fsn1",
+			Size:        "cx22",
+			Image:       "ubuntu-22.04",
+			Volumes:     []types.VolumeConfig{{Name: "data", SizeGB: 10}},
+		})
+		assert.Error(t, err)
+		assert.Equal(t, []int64{mocks.DefaultHetznerVolumeID}, mockClient.MockVolumeService.Deleted)
+	})
+
+	t.Run("DeleteInstance_WithVolumes", func(t *testing.T) {
+		provider, mockClient := newTestHetznerProvider()
+		mockClient.MockServerService.GetByIDFunc = func(_ context.Context, id int64) (*hcloud.Server, *hcloud.Response, error) {
+			server := mocks.NewDefaultHetznerServer("node")
+			server.ID = id
+			server.Volumes = []*hcloud.Volume{{ID: 7}, {ID: 8}}
+			return server, nil, nil
+		}
+
+		err := provider.DeleteInstance(context.Background(), int(mocks.DefaultHetznerServerID))
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{7, 8}, mockClient.MockVolumeService.Deleted)
+	})
+
+	t.Run("DeleteInstance_NotFound", func(t *testing.T) {
+		provider, mockClient := newTestHetznerProvider()
+		mockClient.SimulateNotFound()
+
+		err := provider.DeleteInstance(context.Background(), 1)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("GetInstance_ListInstances", func(t *testing.T) {
+		provider, mockClient := newTestHetznerProvider()
+
+		instance, err := provider.GetInstance(context.Background(), int(mocks.DefaultHetznerServerID))
+		require.NoError(t, err)
+		assert.Equal(t, int(mocks.DefaultHetznerServerID), instance.ProviderInstanceID)
+		assert.Equal(t, types.ProviderInstanceStatusRunning, instance.Status)
+		assert.Equal(t, mocks.DefaultHetznerServerIP, instance.PublicIP)
+
+		instances, err := provider.ListInstances(context.Background())
+		require.NoError(t, err)
+		require.Len(t, instances, 1)
+
+		mockClient.SimulateNotFound()
+		_, err = provider.GetInstance(context.Background(), 1)
+		assert.ErrorIs(t, err, types.ErrProviderInstanceNotFound)
+	})
+
+	t.Run("ImportInstance", func(t *testing.T) {
+		provider, mockClient := newTestHetznerProvider()
+		mockClient.MockServerService.GetByIDFunc = func(_ context.Context, id int64) (*hcloud.Server, *hcloud.Response, error) {
+			server := mocks.NewDefaultHetznerServer("manual")
+			server.ID = id
+			server.Labels = map[string]string{"env": "test", "manual": ""}
+			server.Volumes = []*hcloud.Volume{{ID: 7}}
+			return server, nil, nil
+		}
+
+		instance, err := provider.ImportInstance(context.Background(), "4711")
+		require.NoError(t, err)
+		assert.Equal(t, 4711, instance.ProviderInstanceID)
+		assert.Equal(t, []string{"env=test", "manual"}, instance.Tags)
+		require.Len(t, instance.Volumes, 1)
+		assert.Equal(t, "7", instance.Volumes[0].ID)
+		assert.Equal(t, mocks.DefaultHetznerLocation, instance.Volumes[0].Region)
+
+		_, err = provider.ImportInstance(context.Background(), "not-a-number")
+		assert.Error(t, err)
+	})
+
+	t.Run("Catalog", func(t *testing.T) {
+		provider, mockClient := newTestHetznerProvider()
+
+		regions, err := provider.ListRegions(context.Background())
+		require.NoError(t, err)
+		require.Len(t, regions, 2)
+		assert.Equal(t, mocks.DefaultHetznerLocation, regions[0].ID)
+
+		// Deprecated server types are skipped
+		sizes, err := provider.ListSizes(context.Background())
+		require.NoError(t, err)
+		require.Len(t, sizes, 1)
+		assert.Equal(t, mocks.DefaultHetznerServerType, sizes[0].ID)
+		assert.Equal(t, 4096, sizes[0].MemoryMB)
+		assert.ElementsMatch(t, []string{"fsn1", "nbg1"}, sizes[0].Regions)
+
+		// Images of both architectures are listed once
+		images, err := provider.ListImages(context.Background())
+		require.NoError(t, err)
+		require.Len(t, images, 1)
+		assert.Equal(t, mocks.DefaultHetznerImage, images[0].ID)
+
+		assert.True(t, provider.MatchesImage("12345"))
+		assert.False(t, provider.MatchesImage("ubuntu-18.04"))
+
+		mockClient.SimulateAuthenticationFailure()
+		_, err = provider.ListSizes(context.Background())
+		assert.Error(t, err)
+	})
+}
+
+func TestHetznerLabelValue(t *testing.T) {
+	assert.Equal(t, "my-project", hetznerLabelValue("my project"))
+	assert.Equal(t, "abc", hetznerLabelValue("--abc--"))
+	assert.Len(t, hetznerLabelValue(strings.Repeat("a", 100)), 63)
+}
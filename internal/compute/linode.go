@@ -0,0 +1,616 @@
+package compute
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/linode/linodego"
+
+	computeTypes "github.com/celestiaorg/talis/internal/compute/types"
+	"github.com/celestiaorg/talis/internal/constants"
+	"github.com/celestiaorg/talis/internal/logger"
+	talisTypes "github.com/celestiaorg/talis/internal/types"
+)
+
+const (
+	// linodeTokenEnv is the environment variable containing the Linode API token
+	linodeTokenEnv = "LINODE_TOKEN"
+	// linodeURLEnv optionally overrides the Linode API base URL
+	linodeURLEnv = "LINODE_URL"
+
+	// linodeVolumeLabelMaxLen is the maximum length of a Linode volume label
+	linodeVolumeLabelMaxLen = 32
+	// linodeInstanceLabelMaxLen is the maximum length of a Linode instance label
+	linodeInstanceLabelMaxLen = 64
+)
+
+// NewLinodeClient creates a new Linode API client. An empty baseURL uses the public API.
+func NewLinodeClient(token, baseURL string) computeTypes.LinodeClient {
+	client := linodego.NewClient(&http.Client{Timeout: 60 * time.Second})
+	client.SetToken(token)
+	client.SetUserAgent("talis " + linodego.DefaultUserAgent)
+	if baseURL != "" {
+		client.SetBaseURL(baseURL)
+	}
+	return &client
+}
+
+// LinodeProvider implements the Provider interface for Linode
+type LinodeProvider struct {
+	client computeTypes.LinodeClient
+}
+
+// NewLinodeProvider creates a new Linode provider instance
+func NewLinodeProvider() (*LinodeProvider, error) {
+	token := os.Getenv(linodeTokenEnv)
+	if token == "" {
+		return nil, fmt.Errorf("%s environment variable is not set", linodeTokenEnv)
+	}
+
+	return &LinodeProvider{
+		client: NewLinodeClient(token, os.Getenv(linodeURLEnv)),
+	}, nil
+}
+
+// SetClient sets the Linode client for testing
+func (p *LinodeProvider) SetClient(client computeTypes.LinodeClient) {
+	p.client = client
+}
+
+// ConfigureProvider is a no-op for Linode
+func (p *LinodeProvider) ConfigureProvider(_ interface{}) error {
+	return nil
+}
+
+// ValidateCredentials validates the Linode credentials
+func (p *LinodeProvider) ValidateCredentials() error {
+	if p.client == nil {
+		return fmt.Errorf("client not initialized")
+	}
+	if _, err := p.client.GetProfile(context.Background()); err != nil {
+		return fmt.Errorf("linode credential validation failed: %w", err)
+	}
+	return nil
+}
+
+// GetEnvironmentVars returns the environment variables needed for the provider
+func (p *LinodeProvider) GetEnvironmentVars() map[string]string {
+	return map[string]string{
+		linodeTokenEnv: os.Getenv(linodeTokenEnv),
+		linodeURLEnv:   os.Getenv(linodeURLEnv),
+	}
+}
+
+// CreateInstance creates a new Linode instance.
+// InstanceRequest.Region, Size and Image map onto the Linode region, type and image IDs
+// (e.g. "us-east", "g6-standard-2" and "linode/ubuntu22.04").
+func (p *LinodeProvider) CreateInstance(ctx context.Context, config *talisTypes.InstanceRequest) error {
+	if p.client == nil {
+		return fmt.Errorf("client not initialized")
+	}
+
+	logger.Debugf("🚀 Creating Linode instance for project: %s", config.ProjectName)
+	logger.Debugf("  Region: %s", config.Region)
+	logger.Debugf("  Type: %s", config.Size)
+	logger.Debugf("  Image: %s", config.Image)
+
+	authorizedKey, err := p.getAuthorizedKey(ctx)
+	if err != nil {
+		logger.Errorf("❌ Failed to get SSH key: %v", err)
+		return fmt.Errorf("failed to get SSH key: %w", err)
+	}
+
+	rootPass, err := generateLinodeRootPass()
+	if err != nil {
+		return fmt.Errorf("failed to generate root password: %w", err)
+	}
+
+	opts := p.createInstanceOpts(config, authorizedKey, rootPass)
+	logger.Debugf("  Sending instance creation request: %s", opts.Label)
+	instance, err := p.client.CreateInstance(ctx, opts)
+	if err != nil {
+		logger.Errorf("❌ Failed to create instance: %v", err)
+		return fmt.Errorf("failed to create instance: %w", err)
+	}
+
+	config.SetProviderInstanceID(instance.ID)
+	config.VolumeIDs = []string{}
+	config.VolumeDetails = []talisTypes.VolumeDetails{}
+
+	ip, err := p.waitForPublicIP(ctx, instance.ID)
+	if err != nil {
+		errMsg := fmt.Errorf("❌ Failed to get public IP for instance %s: %w", opts.Label, err)
+		logger.Error(errMsg)
+		return errMsg
+	}
+	config.PublicIP = ip
+
+	if len(config.Volumes) > 0 {
+		volumeIDs, volumeDetails, err := p.createAndAttachVolumes(ctx, instance.ID, config)
+		if err != nil {
+			logger.Errorf("❌ Failed to create/attach volumes for instance %s: %v", opts.Label, err)
+			return fmt.Errorf("failed to create/attach volumes for instance %s: %w", opts.Label, err)
+		}
+		config.VolumeIDs = volumeIDs
+		config.VolumeDetails = volumeDetails
+	}
+
+	logger.Infof("✅ Linode '%s' (ID: %d) created successfully with IP %s", opts.Label, instance.ID, ip)
+	return nil
+}
+
+// createInstanceOpts builds the InstanceCreateOptions for the given request
+func (p *LinodeProvider) createInstanceOpts(
+	config *talisTypes.InstanceRequest,
+	authorizedKey string,
+	rootPass string,
+) linodego.InstanceCreateOptions {
+	var name string
+	if config.Name != "" {
+		if config.NumberOfInstances > 1 {
+			name = fmt.Sprintf("%s-%d", config.Name, config.InstanceIndex+1)
+		} else {
+			name = config.Name
+		}
+	} else {
+		name = fmt.Sprintf("%s-%s", config.ProjectName, generateRandomSuffix())
+	}
+	label := linodeLabel(name, linodeInstanceLabelMaxLen)
+
+	volumeLabels := make([]string, len(config.Volumes))
+	for i, vol := range config.Volumes {
+		volumeLabels[i] = linodeVolumeLabel(label, vol.Name)
+	}
+
+	userData := fmt.Sprintf(`#!/bin/bash
+apt-get update
+apt-get install -y python3
+
+# Mount volumes if specified
+%s
+`, p.generateVolumeMountScript(config.Volumes, volumeLabels))
+
+	return linodego.InstanceCreateOptions{
+		Label:          label,
+		Region:         config.Region,
+		Type:           config.Size,
+		Image:          config.Image,
+		RootPass:       rootPass,
+		AuthorizedKeys: []string{authorizedKey},
+		Tags:           append([]string{label}, config.Tags...),
+		Metadata: &linodego.InstanceMetadataOptions{
+			UserData: base64.StdEncoding.EncodeToString([]byte(userData)),
+		},
+	}
+}
+
+// generateVolumeMountScript generates a bash script to mount volumes.
+// Linode exposes attached volumes as /dev/disk/by-id/scsi-0Linode_Volume_<label>.
+func (p *LinodeProvider) generateVolumeMountScript(volumes []talisTypes.VolumeConfig, labels []string) string {
+	var script strings.Builder
+	for i, vol := range volumes {
+		if vol.MountPoint == "" {
+			continue
+		}
+
+		fs := vol.FileSystem
+		if fs == "" {
+			fs = "ext4"
+		}
+
+		script.WriteString(fmt.Sprintf(`
+# Mount volume %s
+mkdir -p %s
+device=/dev/disk/by-id/scsi-0Linode_Volume_%s
+for i in $(seq 1 60); do [ -e "$device" ] && break; sleep 5; done
+if [ -e "$device" ]; then
+    blkid "$device" || mkfs.%s "$device"
+    echo "$device %s %s defaults,nofail 0 2" >> /etc/fstab
+    mount %s || true
+fi
+`, vol.Name, vol.MountPoint, labels[i], fs, vol.MountPoint, fs, vol.MountPoint))
+	}
+
+	return script.String()
+}
+
+// getAuthorizedKey returns the public key of the Talis SSH key registered in the Linode profile
+func (p *LinodeProvider) getAuthorizedKey(ctx context.Context) (string, error) {
+	keyName := os.Getenv(constants.EnvTalisSSHKeyName)
+	if keyName == "" {
+		return "", fmt.Errorf("environment variable %s not set, Talis SSH key name is required", constants.EnvTalisSSHKeyName)
+	}
+
+	logger.Debugf("🔑 Looking up SSH key: %s", keyName)
+	keys, err := p.client.ListSSHKeys(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to list SSH keys: %w", err)
+	}
+
+	for _, key := range keys {
+		if key.Label == keyName {
+			logger.Debugf("✅ Found SSH key '%s' with ID: %d", keyName, key.ID)
+			return strings.TrimSpace(key.SSHKey), nil
+		}
+	}
+
+	return "", fmt.Errorf("SSH key '%s' not found", keyName)
+}
+
+// waitForPublicIP waits for a Linode to get a public IPv4 address
+func (p *LinodeProvider) waitForPublicIP(ctx context.Context, linodeID int) (string, error) {
+	logger.Debug("⏳ Waiting for instance to get an IP address...")
+	maxRetries := 10
+	interval := 10 * time.Second
+
+	for i := 0; i < maxRetries; i++ {
+		instance, err := p.client.GetInstance(ctx, linodeID)
+		if err != nil {
+			logger.Errorf("❌ Failed to get instance details: %v", err)
+		} else {
+			for _, ip := range instance.IPv4 {
+				if ip != nil && !ip.IsPrivate() && !ip.IsUnspecified() {
+					logger.Debugf("📍 Found public IP for instance: %s", ip.String())
+					return ip.String(), nil
+				}
+			}
+		}
+
+		logger.Debugf("⏳ IP not assigned yet, retrying in 10 seconds (attempt %d/%d)...", i+1, maxRetries)
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+
+	return "", fmt.Errorf("instance created but no public IP found after %d retries", maxRetries)
+}
+
+// createAndAttachVolumes creates block storage volumes attached to the given Linode
+func (p *LinodeProvider) createAndAttachVolumes(
+	ctx context.Context,
+	linodeID int,
+	config *talisTypes.InstanceRequest,
+) ([]string, []talisTypes.VolumeDetails, error) {
+	instance, err := p.client.GetInstance(ctx, linodeID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get instance details: %w", err)
+	}
+
+	volumeIDs := make([]string, 0, len(config.Volumes))
+	volumeDetails := make([]talisTypes.VolumeDetails, 0, len(config.Volumes))
+
+	for _, volConfig := range config.Volumes {
+		label := linodeVolumeLabel(instance.Label, volConfig.Name)
+		logger.Debugf("📦 Creating volume %s (%d GB) for instance %d", label, volConfig.SizeGB, linodeID)
+
+		volume, err := p.client.CreateVolume(ctx, linodego.VolumeCreateOptions{
+			Label:    label,
+			Size:     volConfig.SizeGB,
+			LinodeID: linodeID,
+			Tags:     []string{instance.Label},
+		})
+		if err != nil {
+			logger.Errorf("❌ Failed to create volume: %v", err)
+			return nil, nil, fmt.Errorf("failed to create volume %s: %w", label, err)
+		}
+
+		if err := p.waitForVolumeActive(ctx, volume.ID); err != nil {
+			return nil, nil, err
+		}
+
+		logger.Debugf("✅ Volume %s (ID: %d) attached to instance %d", label, volume.ID, linodeID)
+		volumeIDs = append(volumeIDs, strconv.Itoa(volume.ID))
+		volumeDetails = append(volumeDetails, talisTypes.VolumeDetails{
+			ID:         strconv.Itoa(volume.ID),
+			Name:       volume.Label,
+			Region:     volume.Region,
+			SizeGB:     volConfig.SizeGB,
+			MountPoint: volConfig.MountPoint,
+		})
+	}
+
+	return volumeIDs, volumeDetails, nil
+}
+
+// waitForVolumeActive waits for a volume to become active
+func (p *LinodeProvider) waitForVolumeActive(ctx context.Context, volumeID int) error {
+	maxRetries := 30
+	interval := 5 * time.Second
+
+	for i := 0; i < maxRetries; i++ {
+		volume, err := p.client.GetVolume(ctx, volumeID)
+		if err != nil {
+			return fmt.Errorf("failed to get volume %d status: %w", volumeID, err)
+		}
+		if volume.Status == linodego.VolumeActive {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+
+	return fmt.Errorf("volume %d did not become active after %d retries", volumeID, maxRetries)
+}
+
+// waitForVolumeDetached waits until a volume is no longer attached to any Linode
+func (p *LinodeProvider) waitForVolumeDetached(ctx context.Context, volumeID int) error {
+	maxRetries := 30
+	interval := 5 * time.Second
+
+	for i := 0; i < maxRetries; i++ {
+		volume, err := p.client.GetVolume(ctx, volumeID)
+		if err != nil {
+			return fmt.Errorf("failed to get volume %d status: %w", volumeID, err)
+		}
+		if volume.LinodeID == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+
+	return fmt.Errorf("volume %d was not detached after %d retries", volumeID, maxRetries)
+}
+
+// DeleteInstance deletes a Linode and its attached volumes.
+// Deleting the Linode detaches its volumes, which are then deleted as well.
+func (p *LinodeProvider) DeleteInstance(ctx context.Context, providerInstanceID int) error {
+	if p.client == nil {
+		return fmt.Errorf("client not initialized")
+	}
+
+	logger.Debugf("🗑️ Deleting Linode instance: %d", providerInstanceID)
+
+	volumes, err := p.client.ListInstanceVolumes(ctx, providerInstanceID, nil)
+	if err != nil {
+		if linodego.IsNotFound(err) {
+			return fmt.Errorf("instance %d not found: %w", providerInstanceID, err)
+		}
+		logger.Warnf("⚠️ Warning: Failed to list volumes: %v", err)
+	}
+
+	if err := p.client.DeleteInstance(ctx, providerInstanceID); err != nil {
+		if linodego.IsNotFound(err) {
+			return fmt.Errorf("instance %d not found: %w", providerInstanceID, err)
+		}
+		return fmt.Errorf("failed to delete instance: %w", err)
+	}
+
+	for _, volume := range volumes {
+		logger.Debugf("🗑️ Deleting volume: %d", volume.ID)
+		if err := p.waitForVolumeDetached(ctx, volume.ID); err != nil {
+			logger.Warnf("⚠️ Warning: %v", err)
+			continue
+		}
+		if err := p.client.DeleteVolume(ctx, volume.ID); err != nil {
+			logger.Warnf("⚠️ Warning: Failed to delete volume %d: %v", volume.ID, err)
+		}
+	}
+
+	logger.Debugf("✅ Deleted Linode instance: %d", providerInstanceID)
+	return nil
+}
+
+// GetInstance returns the current state of a Linode
+func (p *LinodeProvider) GetInstance(ctx context.Context, providerInstanceID int) (*talisTypes.ProviderInstance, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	instance, err := p.client.GetInstance(ctx, providerInstanceID)
+	if err != nil {
+		if linodego.IsNotFound(err) {
+			return nil, fmt.Errorf("instance %d: %w", providerInstanceID, talisTypes.ErrProviderInstanceNotFound)
+		}
+		return nil, fmt.Errorf("failed to get instance details: %w", err)
+	}
+
+	result := linodeToProviderInstance(instance)
+	return &result, nil
+}
+
+// ListInstances returns all Linodes of the account
+func (p *LinodeProvider) ListInstances(ctx context.Context) ([]talisTypes.ProviderInstance, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	linodes, err := p.client.ListInstances(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list instances: %w", err)
+	}
+
+	instances := make([]talisTypes.ProviderInstance, 0, len(linodes))
+	for i := range linodes {
+		instances = append(instances, linodeToProviderInstance(&linodes[i]))
+	}
+	return instances, nil
+}
+
+// ImportInstance returns an existing Linode with its attached volumes.
+// Linode IDs are used as provider instance IDs, so the Linode is not modified.
+func (p *LinodeProvider) ImportInstance(ctx context.Context, nativeID string) (*talisTypes.ProviderInstance, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	linodeID, err := strconv.Atoi(nativeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid linode ID %q: %w", nativeID, err)
+	}
+
+	result, err := p.GetInstance(ctx, linodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	volumes, err := p.client.ListInstanceVolumes(ctx, linodeID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list instance volumes: %w", err)
+	}
+	for _, volume := range volumes {
+		result.Volumes = append(result.Volumes, talisTypes.VolumeDetails{
+			ID:     strconv.Itoa(volume.ID),
+			Name:   volume.Label,
+			Region: volume.Region,
+			SizeGB: volume.Size,
+		})
+	}
+	return result, nil
+}
+
+// ListRegions returns the Linode regions
+func (p *LinodeProvider) ListRegions(ctx context.Context) ([]talisTypes.ProviderRegion, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	linodeRegions, err := p.client.ListRegions(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list regions: %w", err)
+	}
+
+	regions := make([]talisTypes.ProviderRegion, 0, len(linodeRegions))
+	for _, region := range linodeRegions {
+		regions = append(regions, talisTypes.ProviderRegion{ID: region.ID, Name: region.Label})
+	}
+	return regions, nil
+}
+
+// ListSizes returns the Linode types. Every type is offered in every region.
+func (p *LinodeProvider) ListSizes(ctx context.Context) ([]talisTypes.ProviderSize, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	linodeTypes, err := p.client.ListTypes(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list types: %w", err)
+	}
+
+	sizes := make([]talisTypes.ProviderSize, 0, len(linodeTypes))
+	for _, linodeType := range linodeTypes {
+		sizes = append(sizes, talisTypes.ProviderSize{
+			ID:       linodeType.ID,
+			CPU:      linodeType.VCPUs,
+			MemoryMB: linodeType.Memory,
+			DiskGB:   linodeType.Disk / 1024,
+		})
+	}
+	return sizes, nil
+}
+
+// ListImages returns the available public and private Linode images, skipping deprecated ones
+func (p *LinodeProvider) ListImages(ctx context.Context) ([]talisTypes.ProviderImage, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	linodeImages, err := p.client.ListImages(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list images: %w", err)
+	}
+
+	images := make([]talisTypes.ProviderImage, 0, len(linodeImages))
+	for _, image := range linodeImages {
+		if image.Deprecated || image.Status != linodego.ImageStatusAvailable {
+			continue
+		}
+		images = append(images, talisTypes.ProviderImage{
+			ID:           image.ID,
+			Name:         image.Label,
+			Distribution: image.Vendor,
+		})
+	}
+	return images, nil
+}
+
+// linodeToProviderInstance converts a Linode into its provider-independent representation
+func linodeToProviderInstance(instance *linodego.Instance) talisTypes.ProviderInstance {
+	result := talisTypes.ProviderInstance{
+		ProviderInstanceID: instance.ID,
+		NativeID:           strconv.Itoa(instance.ID),
+		Name:               instance.Label,
+		Region:             instance.Region,
+		Size:               instance.Type,
+		Image:              instance.Image,
+		Tags:               instance.Tags,
+	}
+	for _, ip := range instance.IPv4 {
+		if ip != nil && !ip.IsPrivate() {
+			result.PublicIP = ip.String()
+			break
+		}
+	}
+
+	switch instance.Status {
+	case linodego.InstanceRunning:
+		result.Status = talisTypes.ProviderInstanceStatusRunning
+	case linodego.InstanceOffline, linodego.InstanceShuttingDown:
+		result.Status = talisTypes.ProviderInstanceStatusStopped
+	case linodego.InstanceDeleting:
+		result.Status = talisTypes.ProviderInstanceStatusTerminated
+	case linodego.InstanceBooting, linodego.InstanceProvisioning, linodego.InstanceRebooting,
+		linodego.InstanceMigrating, linodego.InstanceRebuilding, linodego.InstanceCloning,
+		linodego.InstanceRestoring, linodego.InstanceResizing:
+		result.Status = talisTypes.ProviderInstanceStatusPending
+	default:
+		result.Status = talisTypes.ProviderInstanceStatusUnknown
+	}
+	return result
+}
+
+// generateLinodeRootPass generates a random root password. Linode requires one when
+// deploying an image even though access happens through the authorized SSH key.
+func generateLinodeRootPass() (string, error) {
+	bytes := make([]byte, 24)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return "Tl-" + hex.EncodeToString(bytes), nil
+}
+
+// linodeVolumeLabel builds a volume label unique to the instance and volume name
+func linodeVolumeLabel(instanceLabel, volumeName string) string {
+	suffix := linodeLabel(volumeName, linodeVolumeLabelMaxLen/2)
+	prefix := linodeLabel(instanceLabel, linodeVolumeLabelMaxLen-len(suffix)-1)
+	return prefix + "-" + suffix
+}
+
+// linodeLabel converts a string into a valid Linode label of at most maxLen characters.
+// Labels may only contain alphanumerics, dashes and underscores and must start and end
+// with an alphanumeric character.
+func linodeLabel(s string, maxLen int) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	label := strings.Trim(b.String(), "-_")
+	if len(label) > maxLen {
+		label = strings.Trim(label[:maxLen], "-_")
+	}
+	return label
+}
@@ -0,0 +1,254 @@
+package compute
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/linode/linodego"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/talis/internal/constants"
+	"github.com/celestiaorg/talis/internal/types"
+	"github.com/celestiaorg/talis/test/mocks"
+)
+
+// newTestLinodeProvider creates a new LinodeProvider with a mock client for testing
+func newTestLinodeProvider() (*LinodeProvider, *mocks.MockLinodeClient) {
+	mockClient := mocks.NewMockLinodeClient()
+	provider := &LinodeProvider{}
+	provider.SetClient(mockClient)
+	return provider, mockClient
+}
+
+func TestLinodeProvider(t *testing.T) {
+	t.Run("NewLinodeProvider_MissingToken", func(t *testing.T) {
+		t.Setenv(linodeTokenEnv, "")
+		provider, err := NewLinodeProvider()
+		assert.Error(t, err)
+		assert.Nil(t, provider)
+	})
+
+	t.Run("NewComputeProvider", func(t *testing.T) {
+		t.Setenv(linodeTokenEnv, "test-token")
+		provider, err := NewComputeProvider("linode")
+		require.NoError(t, err)
+		assert.IsType(t, &LinodeProvider{}, provider)
+	})
+
+	t.Run("ValidateCredentials", func(t *testing.T) {
+		provider, mockClient := newTestLinodeProvider()
+		assert.NoError(t, provider.ValidateCredentials())
+
+		mockClient.SimulateAuthenticationFailure()
+		err := provider.ValidateCredentials()
+		assert.Error(t, err)
+		assert.True(t, linodego.ErrHasStatus(err, 401))
+
+		provider = &LinodeProvider{}
+		err = provider.ValidateCredentials()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "client not initialized")
+	})
+
+	t.Run("GetEnvironmentVars", func(t *testing.T) {
+		t.Setenv(linodeTokenEnv, "test-token")
+		provider, _ := newTestLinodeProvider()
+		assert.Equal(t, "test-token", provider.GetEnvironmentVars()[linodeTokenEnv])
+	})
+
+	t.Run("CreateInstance_Success", func(t *testing.T) {
+		t.Setenv(constants.EnvTalisSSHKeyName, mocks.DefaultLinodeKeyName)
+		provider, mockClient := newTestLinodeProvider()
+
+		config := &types.InstanceRequest{
+			ProjectName:       "test-project",
+			Name:              "node",
+			Region:            "us-east",
+			Size:              "g6-standard-2",
+			Image:             "linode/ubuntu22.04",
+			Tags:              []string{"celestia"},
+			NumberOfInstances: 2,
+			InstanceIndex:     1,
+			Volumes: []types.VolumeConfig{
+				{Name: "data", SizeGB: 20, MountPoint: "/mnt/data"},
+			},
+		}
+
+		err := provider.CreateInstance(context.Background(), config)
+		require.NoError(t, err)
+		assert.Equal(t, mocks.DefaultLinodeInstanceIP, config.PublicIP)
+		assert.Equal(t, mocks.DefaultLinodeInstanceID, config.ProviderInstanceID)
+
+		// Region, Size and Image map directly onto the Linode region, type and image
+		opts := mockClient.LastCreateOpts
+		assert.Equal(t, "node-2", opts.Label)
+		assert.Equal(t, "us-east", opts.Region)
+		assert.Equal(t, "g6-standard-2", opts.Type)
+		assert.Equal(t, "linode/ubuntu22.04", opts.Image)
+		assert.Equal(t, []string{mocks.DefaultLinodePublicKey}, opts.AuthorizedKeys)
+		assert.Equal(t, []string{"node-2", "celestia"}, opts.Tags)
+		assert.NotEmpty(t, opts.RootPass)
+
+		require.NotNil(t, opts.Metadata)
+		userData, err := base64.StdEncoding.DecodeString(opts.Metadata.UserData)
+		require.NoError(t, err)
+		assert.Contains(t, string(userData), "scsi-0Linode_Volume_node-2-data")
+		assert.Contains(t, string(userData), "/mnt/data")
+
+		require.Len(t, config.VolumeDetails, 1)
+		assert.Equal(t, strconv.Itoa(mocks.DefaultLinodeVolumeID), config.VolumeIDs[0])
+		assert.Equal(t, "node-2-data", config.VolumeDetails[0].Name)
+		assert.Equal(t, "us-east", config.VolumeDetails[0].Region)
+		assert.Equal(t, 20, config.VolumeDetails[0].SizeGB)
+		assert.Equal(t, "/mnt/data", config.VolumeDetails[0].MountPoint)
+	})
+
+	t.Run("CreateInstance_SSHKey_NotFound", func(t *testing.T) {
+		t.Setenv(constants.EnvTalisSSHKeyName, "not-existing-key")
+		provider, _ := newTestLinodeProvider()
+
+		err := provider.CreateInstance(context.Background(), &types.InstanceRequest{
+			ProjectName: "test-project",
+			Region:      "us-east",
+			Size:        "g6-standard-2",
+			Image:       "linode/ubuntu22.04",
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get SSH key")
+	})
+
+	t.Run("CreateInstance_Failure", func(t *testing.T) {
+		t.Setenv(constants.EnvTalisSSHKeyName, mocks.DefaultLinodeKeyName)
+		provider, mockClient := newTestLinodeProvider()
+		mockClient.CreateInstanceFunc = func(_ context.Context, _ linodego.InstanceCreateOptions) (*linodego.Instance, error) {
+			return nil, mocks.ErrLinodeRateLimit
+		}
+
+		err := provider.CreateInstance(context.Background(), &types.InstanceRequest{
+			ProjectName: "test-project",
+			Region:      "us-east",
+			Size:        "g6-standard-2",
+			Image:       "linode/ubuntu22.04",
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to create instance")
+	})
+
+	t.Run("DeleteInstance_WithVolumes", func(t *testing.T) {
+		t.Setenv(constants.EnvTalisSSHKeyName, mocks.DefaultLinodeKeyName)
+		provider, mockClient := newTestLinodeProvider()
+
+		config := &types.InstanceRequest{
+			ProjectName: "test-project",
+			Region:      "us-east",
+			Size:        "g6-standard-2",
+			Image:       "linode/ubuntu22.04",
+			Volumes: []types.VolumeConfig{
+				{Name: "data", SizeGB: 10},
+				{Name: "logs", SizeGB: 10},
+			},
+		}
+		require.NoError(t, provider.CreateInstance(context.Background(), config))
+		require.Len(t, config.VolumeIDs, 2)
+
+		err := provider.DeleteInstance(context.Background(), config.ProviderInstanceID)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{config.ProviderInstanceID}, mockClient.DeletedInstances)
+		assert.ElementsMatch(t, []int{mocks.DefaultLinodeVolumeID, mocks.DefaultLinodeVolumeID + 1}, mockClient.DeletedVolumes)
+	})
+
+	t.Run("DeleteInstance_NotFound", func(t *testing.T) {
+		provider, mockClient := newTestLinodeProvider()
+		mockClient.SimulateNotFound()
+
+		err := provider.DeleteInstance(context.Background(), 1)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+	t.Run("GetInstance_ListInstances", func(t *testing.T) {
+		provider, mockClient := newTestLinodeProvider()
+
+		created, err := mockClient.CreateInstance(context.Background(), linodego.InstanceCreateOptions{Label: "node"})
+		require.NoError(t, err)
+		created.Status = linodego.InstanceOffline
+
+		instance, err := provider.GetInstance(context.Background(), created.ID)
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, instance.ProviderInstanceID)
+		assert.Equal(t, types.ProviderInstanceStatusStopped, instance.Status)
+		assert.Equal(t, mocks.DefaultLinodeInstanceIP, instance.PublicIP)
+
+		instances, err := provider.ListInstances(context.Background())
+		require.NoError(t, err)
+		require.Len(t, instances, 1)
+		assert.Equal(t, *instance, instances[0])
+
+		mockClient.SimulateNotFound()
+		_, err = provider.GetInstance(context.Background(), created.ID)
+		assert.ErrorIs(t, err, types.ErrProviderInstanceNotFound)
+	})
+
+	t.Run("ImportInstance", func(t *testing.T) {
+		provider, mockClient := newTestLinodeProvider()
+
+		created, err := mockClient.CreateInstance(context.Background(), linodego.InstanceCreateOptions{Label: "manual", Tags: []string{"testnet"}})
+		require.NoError(t, err)
+		_, err = mockClient.CreateVolume(context.Background(), linodego.VolumeCreateOptions{Label: "data", Size: 20, LinodeID: created.ID})
+		require.NoError(t, err)
+
+		instance, err := provider.ImportInstance(context.Background(), strconv.Itoa(created.ID))
+		require.NoError(t, err)
+		assert.Equal(t, created.ID, instance.ProviderInstanceID)
+		assert.Equal(t, []string{"testnet"}, instance.Tags)
+		require.Len(t, instance.Volumes, 1)
+		assert.Equal(t, "data", instance.Volumes[0].Name)
+		assert.Equal(t, 20, instance.Volumes[0].SizeGB)
+
+		_, err = provider.ImportInstance(context.Background(), "linode")
+		assert.Error(t, err)
+
+		mockClient.SimulateNotFound()
+		_, err = provider.ImportInstance(context.Background(), strconv.Itoa(created.ID))
+		assert.ErrorIs(t, err, types.ErrProviderInstanceNotFound)
+	})
+
+	t.Run("Catalog", func(t *testing.T) {
+		provider, mockClient := newTestLinodeProvider()
+
+		regions, err := provider.ListRegions(context.Background())
+		require.NoError(t, err)
+		require.Len(t, regions, 3)
+		assert.Equal(t, types.ProviderRegion{ID: mocks.DefaultLinodeRegion, Name: "Newark, NJ"}, regions[0])
+
+		sizes, err := provider.ListSizes(context.Background())
+		require.NoError(t, err)
+		require.Len(t, sizes, 2)
+		assert.Equal(t, types.ProviderSize{ID: mocks.DefaultLinodeType, CPU: 2, MemoryMB: 4096, DiskGB: 80}, sizes[1])
+
+		// Deprecated images are skipped, private images are listed
+		images, err := provider.ListImages(context.Background())
+		require.NoError(t, err)
+		require.Len(t, images, 2)
+		assert.Equal(t, mocks.DefaultLinodeImage, images[0].ID)
+		assert.Equal(t, "Ubuntu", images[0].Distribution)
+		assert.Equal(t, "private/12345", images[1].ID)
+
+		mockClient.SimulateAuthenticationFailure()
+		_, err = provider.ListRegions(context.Background())
+		assert.Error(t, err)
+	})
+}
+
+func TestLinodeLabel(t *testing.T) {
+	assert.Equal(t, "my-project", linodeLabel("my project", linodeInstanceLabelMaxLen))
+	assert.Equal(t, "abc", linodeLabel("--abc--", linodeInstanceLabelMaxLen))
+	assert.Len(t, linodeLabel(strings.Repeat("a", 100), linodeInstanceLabelMaxLen), linodeInstanceLabelMaxLen)
+
+	label := linodeVolumeLabel(strings.Repeat("n", 64), "data")
+	assert.LessOrEqual(t, len(label), linodeVolumeLabelMaxLen)
+	assert.True(t, strings.HasSuffix(label, "-data"))
+}
@@ -0,0 +1,405 @@
+package compute
+
+import (
+	"context"
+	_ "embed" // embeds the local instance image Dockerfile
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	computeTypes "github.com/celestiaorg/talis/internal/compute/types"
+	"github.com/celestiaorg/talis/internal/logger"
+	talisTypes "github.com/celestiaorg/talis/internal/types"
+)
+
+const (
+	// localDockerEnv optionally overrides the docker compatible binary used by the local provider
+	localDockerEnv = "TALIS_LOCAL_DOCKER"
+	// localNetworkEnv optionally overrides the container network instances are attached to
+	localNetworkEnv = "TALIS_LOCAL_NETWORK"
+
+	// localDefaultDocker is the binary used when TALIS_LOCAL_DOCKER is not set
+	localDefaultDocker = "docker"
+	// localDefaultNetwork is the network used when TALIS_LOCAL_NETWORK is not set
+	localDefaultNetwork = "talis"
+	// LocalDefaultImage is the instance image built from local_instance.Dockerfile on first use
+	LocalDefaultImage = "talis-local-instance:latest"
+
+	// localRefLabel is the container label that maps a container to its ProviderInstanceID,
+	// the Talis instance ID
+	localRefLabel = "talis.ref"
+	// localProjectLabel is the container label containing the Talis project of an instance
+	localProjectLabel = "talis.project"
+)
+
+//go:embed local_instance.Dockerfile
+var localInstanceDockerfile []byte
+
+// LocalProvider implements the Provider interface with local containers running sshd.
+// Containers are reachable from the host through their address on a bridge network,
+// so the full create, provision and payload flow runs without a cloud account.
+type LocalProvider struct {
+	client  computeTypes.DockerClient
+	network string
+}
+
+// NewLocalProvider creates a new local container provider instance
+func NewLocalProvider() (*LocalProvider, error) {
+	binary := os.Getenv(localDockerEnv)
+	if binary == "" {
+		binary = localDefaultDocker
+	}
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return nil, fmt.Errorf("container engine %s not found: %w", binary, err)
+	}
+
+	network := os.Getenv(localNetworkEnv)
+	if network == "" {
+		network = localDefaultNetwork
+	}
+
+	return &LocalProvider{
+		client:  NewDockerCLIClient(path),
+		network: network,
+	}, nil
+}
+
+// SetClient sets the docker client for testing
+func (p *LocalProvider) SetClient(client computeTypes.DockerClient) {
+	p.client = client
+	if p.network == "" {
+		p.network = localDefaultNetwork
+	}
+}
+
+// ConfigureProvider is a no-op for the local provider
+func (p *LocalProvider) ConfigureProvider(_ interface{}) error {
+	return nil
+}
+
+// ValidateCredentials checks that the container engine is reachable
+func (p *LocalProvider) ValidateCredentials() error {
+	if p.client == nil {
+		return fmt.Errorf("client not initialized")
+	}
+	if err := p.client.Version(context.Background()); err != nil {
+		return fmt.Errorf("container engine not reachable: %w", err)
+	}
+	return nil
+}
+
+// GetEnvironmentVars returns the environment variables needed for the provider
+func (p *LocalProvider) GetEnvironmentVars() map[string]string {
+	return map[string]string{
+		localDockerEnv:  os.Getenv(localDockerEnv),
+		localNetworkEnv: p.network,
+	}
+}
+
+// CreateInstance starts a container acting as an instance.
+// InstanceRequest.Image is the container image, CPU and Memory (MB) optionally limit the
+// container and Region and Size are ignored. Every volume is a named volume mounted at
+// its mount point.
+func (p *LocalProvider) CreateInstance(ctx context.Context, config *talisTypes.InstanceRequest) error {
+	if p.client == nil {
+		return fmt.Errorf("client not initialized")
+	}
+	if config.InstanceID == 0 {
+		return fmt.Errorf("instance request has no instance ID to reference the instance by")
+	}
+
+	logger.Debugf("🚀 Creating local instance for project: %s", config.ProjectName)
+	logger.Debugf("  Image: %s", config.Image)
+	logger.Debugf("  Network: %s", p.network)
+
+	publicKey, err := talisPublicKey()
+	if err != nil {
+		logger.Errorf("❌ Failed to get SSH key: %v", err)
+		return fmt.Errorf("failed to get SSH key: %w", err)
+	}
+
+	if err := p.client.EnsureNetwork(ctx, p.network); err != nil {
+		return fmt.Errorf("failed to create network %s: %w", p.network, err)
+	}
+	if err := p.ensureImage(ctx, config.Image); err != nil {
+		return err
+	}
+
+	ref := int(config.InstanceID)
+	var name string
+	if config.Name != "" {
+		if config.NumberOfInstances > 1 {
+			name = fmt.Sprintf("%s-%d", config.Name, config.InstanceIndex+1)
+		} else {
+			name = config.Name
+		}
+	} else {
+		name = fmt.Sprintf("%s-%s", config.ProjectName, generateRandomSuffix())
+	}
+	// Container names are global, the reference keeps them unique across projects
+	containerName := fmt.Sprintf("%s-%d", name, ref)
+
+	labels := map[string]string{
+		localRefLabel:     strconv.Itoa(ref),
+		localProjectLabel: config.ProjectName,
+	}
+
+	volumeIDs := make([]string, 0, len(config.Volumes))
+	volumeDetails := make([]talisTypes.VolumeDetails, 0, len(config.Volumes))
+	mounts := make([]computeTypes.DockerMount, 0, len(config.Volumes))
+	for _, volConfig := range config.Volumes {
+		volumeName := fmt.Sprintf("%s-%s", containerName, volConfig.Name)
+		mountPoint := volConfig.MountPoint
+		if mountPoint == "" {
+			mountPoint = "/mnt/" + volConfig.Name
+		}
+
+		logger.Debugf("📦 Creating volume %s", volumeName)
+		if err := p.client.CreateVolume(ctx, volumeName, labels); err != nil {
+			p.removeVolumes(ctx, volumeIDs)
+			return fmt.Errorf("failed to create volume %s: %w", volumeName, err)
+		}
+
+		volumeIDs = append(volumeIDs, volumeName)
+		volumeDetails = append(volumeDetails, talisTypes.VolumeDetails{
+			ID:         volumeName,
+			Name:       volumeName,
+			Region:     config.Region,
+			SizeGB:     volConfig.SizeGB,
+			MountPoint: mountPoint,
+		})
+		mounts = append(mounts, computeTypes.DockerMount{Volume: volumeName, Target: mountPoint})
+	}
+
+	logger.Debugf("  Starting container: %s", containerName)
+	containerID, err := p.client.RunContainer(ctx, &computeTypes.DockerRunOptions{
+		Name:     containerName,
+		Hostname: name,
+		Image:    config.Image,
+		Network:  p.network,
+		Env:      map[string]string{"TALIS_AUTHORIZED_KEY": publicKey},
+		Labels:   labels,
+		Mounts:   mounts,
+		CPUs:     config.CPU,
+		MemoryMB: config.Memory,
+	})
+	if err != nil {
+		logger.Errorf("❌ Failed to start container: %v", err)
+		p.removeVolumes(ctx, volumeIDs)
+		return fmt.Errorf("failed to create instance: %w", err)
+	}
+
+	config.SetProviderInstanceID(ref)
+	config.VolumeIDs = volumeIDs
+	config.VolumeDetails = volumeDetails
+
+	ip, err := p.waitForContainerIP(ctx, containerID)
+	if err != nil {
+		errMsg := fmt.Errorf("❌ Failed to get IP for instance %s: %w", containerName, err)
+		logger.Error(errMsg)
+		return errMsg
+	}
+	config.PublicIP = ip
+
+	logger.Infof("✅ Local instance '%s' (ID: %.12s, ref: %d) created successfully with IP %s", containerName, containerID, ref, ip)
+	return nil
+}
+
+// ensureImage builds the default instance image when it is not available locally.
+// Other images are pulled by the container engine when the container starts.
+func (p *LocalProvider) ensureImage(ctx context.Context, image string) error {
+	if image != LocalDefaultImage {
+		return nil
+	}
+
+	exists, err := p.client.ImageExists(ctx, image)
+	if err != nil {
+		return fmt.Errorf("failed to look up image %s: %w", image, err)
+	}
+	if exists {
+		return nil
+	}
+
+	logger.Infof("🔨 Building local instance image %s", image)
+	if err := p.client.BuildImage(ctx, image, localInstanceDockerfile); err != nil {
+		return fmt.Errorf("failed to build image %s: %w", image, err)
+	}
+	return nil
+}
+
+// waitForContainerIP waits for a container to be running with an address on the provider network
+func (p *LocalProvider) waitForContainerIP(ctx context.Context, containerID string) (string, error) {
+	logger.Debug("⏳ Waiting for container to be running...")
+	maxRetries := 30
+	interval := time.Second
+
+	for i := 0; i < maxRetries; i++ {
+		container, err := p.client.InspectContainer(ctx, containerID)
+		if err != nil {
+			logger.Errorf("❌ Failed to inspect container: %v", err)
+		} else if ip := container.IPAddresses[p.network]; container.Running && ip != "" {
+			logger.Debugf("📍 Found IP for container: %s", ip)
+			return ip, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+
+	return "", fmt.Errorf("container started but not running after %d retries", maxRetries)
+}
+
+// removeVolumes removes named volumes, logging instead of failing on errors
+func (p *LocalProvider) removeVolumes(ctx context.Context, names []string) {
+	for _, name := range names {
+		logger.Debugf("🗑️ Removing volume %s", name)
+		if err := p.client.RemoveVolume(ctx, name); err != nil {
+			logger.Warnf("⚠️ Warning: Failed to remove volume %s: %v", name, err)
+		}
+	}
+}
+
+// DeleteInstance removes the container of an instance and its volumes.
+// The container is looked up through the reference label set on creation.
+func (p *LocalProvider) DeleteInstance(ctx context.Context, providerInstanceID int) error {
+	if p.client == nil {
+		return fmt.Errorf("client not initialized")
+	}
+
+	logger.Debugf("🗑️ Deleting local instance with ref: %d", providerInstanceID)
+
+	ids, err := p.client.ListContainers(ctx, map[string]string{localRefLabel: strconv.Itoa(providerInstanceID)})
+	if err != nil {
+		return fmt.Errorf("failed to look up instance: %w", err)
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("instance with ref %d not found", providerInstanceID)
+	}
+
+	for _, id := range ids {
+		var volumes []string
+		if container, err := p.client.InspectContainer(ctx, id); err != nil {
+			logger.Warnf("⚠️ Warning: Failed to inspect container %s: %v", id, err)
+		} else {
+			volumes = container.Volumes
+		}
+
+		logger.Debugf("🗑️ Removing container %.12s", id)
+		if err := p.client.RemoveContainer(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete instance: %w", err)
+		}
+		p.removeVolumes(ctx, volumes)
+	}
+
+	logger.Debugf("✅ Deleted local instance with ref: %d", providerInstanceID)
+	return nil
+}
+
+// GetInstance returns the current state of the container of an instance.
+// The container is looked up through the reference label set on creation.
+func (p *LocalProvider) GetInstance(ctx context.Context, providerInstanceID int) (*talisTypes.ProviderInstance, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	ids, err := p.client.ListContainers(ctx, map[string]string{localRefLabel: strconv.Itoa(providerInstanceID)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up instance: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("instance with ref %d: %w", providerInstanceID, talisTypes.ErrProviderInstanceNotFound)
+	}
+
+	container, err := p.client.InspectContainer(ctx, ids[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	instance := p.containerToProviderInstance(container)
+	return &instance, nil
+}
+
+// ListInstances returns the instances of all containers created by this provider
+func (p *LocalProvider) ListInstances(ctx context.Context) ([]talisTypes.ProviderInstance, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	ids, err := p.client.ListContainers(ctx, map[string]string{localRefLabel: ""})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	instances := make([]talisTypes.ProviderInstance, 0, len(ids))
+	for _, id := range ids {
+		container, err := p.client.InspectContainer(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect container %.12s: %w", id, err)
+		}
+		instances = append(instances, p.containerToProviderInstance(container))
+	}
+	return instances, nil
+}
+
+// ImportInstance returns an existing container with its named volumes.
+// Docker labels cannot be changed after a container is started, so only containers
+// started by this provider, i.e. carrying a reference label, can be imported.
+func (p *LocalProvider) ImportInstance(ctx context.Context, nativeID string) (*talisTypes.ProviderInstance, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	container, err := p.client.InspectContainer(ctx, nativeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container %s: %w", nativeID, err)
+	}
+	if _, ok := container.Labels[localRefLabel]; !ok {
+		return nil, fmt.Errorf("container %s has no %s label and cannot be imported", nativeID, localRefLabel)
+	}
+
+	instance := p.containerToProviderInstance(container)
+	for _, volume := range container.Volumes {
+		instance.Volumes = append(instance.Volumes, talisTypes.VolumeDetails{
+			ID:   volume,
+			Name: volume,
+		})
+	}
+	return &instance, nil
+}
+
+// ListRegions returns no regions, containers run on the local Docker daemon regardless of region
+func (p *LocalProvider) ListRegions(_ context.Context) ([]talisTypes.ProviderRegion, error) {
+	return nil, nil
+}
+
+// ListSizes returns no sizes, containers are not limited by size
+func (p *LocalProvider) ListSizes(_ context.Context) ([]talisTypes.ProviderSize, error) {
+	return nil, nil
+}
+
+// ListImages returns no images, any image is pulled on demand
+func (p *LocalProvider) ListImages(_ context.Context) ([]talisTypes.ProviderImage, error) {
+	return nil, nil
+}
+
+// containerToProviderInstance converts a container into its provider-independent representation
+func (p *LocalProvider) containerToProviderInstance(container *computeTypes.DockerContainer) talisTypes.ProviderInstance {
+	ref, _ := strconv.Atoi(container.Labels[localRefLabel])
+	instance := talisTypes.ProviderInstance{
+		ProviderInstanceID: ref,
+		NativeID:           container.ID,
+		Name:               container.Name,
+		PublicIP:           container.IPAddresses[p.network],
+		Status:             talisTypes.ProviderInstanceStatusStopped,
+		Tags:               labelTags(container.Labels),
+	}
+	if container.Running {
+		instance.Status = talisTypes.ProviderInstanceStatusRunning
+	}
+	return instance
+}
@@ -0,0 +1,210 @@
+package compute
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	computeTypes "github.com/celestiaorg/talis/internal/compute/types"
+)
+
+// DockerCLIClient implements DockerClient by running the docker command line client
+type DockerCLIClient struct {
+	binary string
+}
+
+// NewDockerCLIClient creates a new client running the given docker compatible binary
+func NewDockerCLIClient(binary string) *DockerCLIClient {
+	return &DockerCLIClient{binary: binary}
+}
+
+// run runs a docker command and returns its trimmed standard output
+func (c *DockerCLIClient) run(ctx context.Context, stdin []byte, args ...string) (string, error) {
+	// #nosec G204 -- the binary is configured by the operator and arguments are built by the provider
+	cmd := exec.CommandContext(ctx, c.binary, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s %s failed: %w: %s", c.binary, args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// sortedPairs returns key=value pairs of a map in a stable order
+func sortedPairs(m map[string]string) []string {
+	pairs := make([]string, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+// Version checks that the docker daemon is reachable
+func (c *DockerCLIClient) Version(ctx context.Context) error {
+	_, err := c.run(ctx, nil, "version", "--format", "{{.Server.Version}}")
+	return err
+}
+
+// ImageExists reports whether the image is available locally
+func (c *DockerCLIClient) ImageExists(ctx context.Context, image string) (bool, error) {
+	out, err := c.run(ctx, nil, "images", "--quiet", image)
+	if err != nil {
+		return false, err
+	}
+	return out != "", nil
+}
+
+// BuildImage builds an image from a Dockerfile without build context
+func (c *DockerCLIClient) BuildImage(ctx context.Context, tag string, dockerfile []byte) error {
+	_, err := c.run(ctx, dockerfile, "build", "--tag", tag, "-")
+	return err
+}
+
+// EnsureNetwork creates a bridge network unless it already exists
+func (c *DockerCLIClient) EnsureNetwork(ctx context.Context, name string) error {
+	out, err := c.run(ctx, nil, "network", "ls", "--quiet", "--filter", "name=^"+name+"$")
+	if err != nil {
+		return err
+	}
+	if out != "" {
+		return nil
+	}
+	_, err = c.run(ctx, nil, "network", "create", "--driver", "bridge", "--label", "talis=true", name)
+	return err
+}
+
+// CreateVolume creates a named volume
+func (c *DockerCLIClient) CreateVolume(ctx context.Context, name string, labels map[string]string) error {
+	args := []string{"volume", "create"}
+	for _, label := range sortedPairs(labels) {
+		args = append(args, "--label", label)
+	}
+	_, err := c.run(ctx, nil, append(args, name)...)
+	return err
+}
+
+// RemoveVolume removes a named volume
+func (c *DockerCLIClient) RemoveVolume(ctx context.Context, name string) error {
+	_, err := c.run(ctx, nil, "volume", "rm", "--force", name)
+	return err
+}
+
+// RunContainer starts a detached container and returns its ID
+func (c *DockerCLIClient) RunContainer(ctx context.Context, opts *computeTypes.DockerRunOptions) (string, error) {
+	args := []string{"run", "--detach", "--restart", "unless-stopped"}
+	if opts.Name != "" {
+		args = append(args, "--name", opts.Name)
+	}
+	if opts.Hostname != "" {
+		args = append(args, "--hostname", opts.Hostname)
+	}
+	if opts.Network != "" {
+		args = append(args, "--network", opts.Network)
+	}
+	for _, env := range sortedPairs(opts.Env) {
+		args = append(args, "--env", env)
+	}
+	for _, label := range sortedPairs(opts.Labels) {
+		args = append(args, "--label", label)
+	}
+	for _, mount := range opts.Mounts {
+		args = append(args, "--mount", fmt.Sprintf("type=volume,source=%s,target=%s", mount.Volume, mount.Target))
+	}
+	if opts.CPUs > 0 {
+		args = append(args, "--cpus", fmt.Sprint(opts.CPUs))
+	}
+	if opts.MemoryMB > 0 {
+		args = append(args, "--memory", fmt.Sprintf("%dm", opts.MemoryMB))
+	}
+	args = append(args, opts.Image)
+
+	return c.run(ctx, nil, args...)
+}
+
+// dockerInspect is the subset of `docker inspect` output used by the client
+type dockerInspect struct {
+	ID    string `json:"Id"`
+	Name  string `json:"Name"`
+	State struct {
+		Running bool `json:"Running"`
+	} `json:"State"`
+	Config struct {
+		Labels map[string]string `json:"Labels"`
+	} `json:"Config"`
+	NetworkSettings struct {
+		Networks map[string]struct {
+			IPAddress string `json:"IPAddress"`
+		} `json:"Networks"`
+	} `json:"NetworkSettings"`
+	Mounts []struct {
+		Type string `json:"Type"`
+		Name string `json:"Name"`
+	} `json:"Mounts"`
+}
+
+// InspectContainer returns the state of a container
+func (c *DockerCLIClient) InspectContainer(ctx context.Context, id string) (*computeTypes.DockerContainer, error) {
+	out, err := c.run(ctx, nil, "container", "inspect", id)
+	if err != nil {
+		return nil, err
+	}
+
+	var inspected []dockerInspect
+	if err := json.Unmarshal([]byte(out), &inspected); err != nil {
+		return nil, fmt.Errorf("error unmarshaling inspect output: %w", err)
+	}
+	if len(inspected) == 0 {
+		return nil, fmt.Errorf("container %s not found", id)
+	}
+
+	info := inspected[0]
+	container := &computeTypes.DockerContainer{
+		ID:          info.ID,
+		Name:        strings.TrimPrefix(info.Name, "/"),
+		Running:     info.State.Running,
+		Labels:      info.Config.Labels,
+		IPAddresses: make(map[string]string),
+	}
+	for network, settings := range info.NetworkSettings.Networks {
+		container.IPAddresses[network] = settings.IPAddress
+	}
+	for _, mount := range info.Mounts {
+		if mount.Type == "volume" {
+			container.Volumes = append(container.Volumes, mount.Name)
+		}
+	}
+	return container, nil
+}
+
+// ListContainers lists the IDs of all containers, running or not, carrying the labels.
+// An empty label value matches every container carrying the label key.
+func (c *DockerCLIClient) ListContainers(ctx context.Context, labels map[string]string) ([]string, error) {
+	args := []string{"ps", "--all", "--quiet", "--no-trunc"}
+	for _, label := range sortedPairs(labels) {
+		args = append(args, "--filter", "label="+strings.TrimSuffix(label, "="))
+	}
+	out, err := c.run(ctx, nil, args...)
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Fields(out), nil
+}
+
+// RemoveContainer force removes a container
+func (c *DockerCLIClient) RemoveContainer(ctx context.Context, id string) error {
+	_, err := c.run(ctx, nil, "rm", "--force", id)
+	return err
+}
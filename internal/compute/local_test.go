@@ -0,0 +1,280 @@
+package compute
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	computeTypes "github.com/celestiaorg/talis/internal/compute/types"
+	"github.com/celestiaorg/talis/internal/constants"
+	"github.com/celestiaorg/talis/internal/types"
+	"github.com/celestiaorg/talis/test/mocks"
+)
+
+// newTestLocalProvider creates a new LocalProvider with a mock client and a Talis SSH key for testing
+func newTestLocalProvider(t *testing.T) (*LocalProvider, *mocks.MockDockerClient) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(privateKey, "")
+	require.NoError(t, err)
+	t.Setenv(constants.EnvTalisSSHKey, string(pem.EncodeToMemory(block)))
+
+	mockClient := mocks.NewMockDockerClient()
+	provider := &LocalProvider{}
+	provider.SetClient(mockClient)
+	return provider, mockClient
+}
+
+func TestLocalProvider(t *testing.T) {
+	t.Run("NewLocalProvider_MissingEngine", func(t *testing.T) {
+		t.Setenv(localDockerEnv, "talis-missing-container-engine")
+		provider, err := NewLocalProvider()
+		assert.Error(t, err)
+		assert.Nil(t, provider)
+	})
+
+	t.Run("NewComputeProvider", func(t *testing.T) {
+		t.Setenv(localDockerEnv, "sh")
+		t.Setenv(localNetworkEnv, "talis-ci")
+		provider, err := NewComputeProvider("local")
+		require.NoError(t, err)
+		require.IsType(t, &LocalProvider{}, provider)
+		assert.Equal(t, "talis-ci", provider.(*LocalProvider).network)
+	})
+
+	t.Run("ValidateCredentials", func(t *testing.T) {
+		provider, mockClient := newTestLocalProvider(t)
+		assert.NoError(t, provider.ValidateCredentials())
+
+		mockClient.SimulateEngineUnreachable()
+		assert.Error(t, provider.ValidateCredentials())
+	})
+
+	t.Run("CreateInstance_Success", func(t *testing.T) {
+		provider, mockClient := newTestLocalProvider(t)
+
+		config := &types.InstanceRequest{
+			InstanceID:        7,
+			ProjectName:       "test-project",
+			Name:              "node",
+			Region:            "local",
+			Size:              "any",
+			Image:             LocalDefaultImage,
+			CPU:               2,
+			Memory:            2048,
+			NumberOfInstances: 1,
+			Volumes: []types.VolumeConfig{
+				{Name: "data", SizeGB: 10, MountPoint: "/mnt/data"},
+				{Name: "scratch", SizeGB: 10},
+			},
+		}
+
+		err := provider.CreateInstance(context.Background(), config)
+		require.NoError(t, err)
+		assert.Equal(t, mocks.DefaultDockerContainerIP, config.PublicIP)
+		assert.Equal(t, 7, config.ProviderInstanceID)
+
+		// The default image is built on first use
+		assert.Equal(t, []string{LocalDefaultImage}, mockClient.BuiltImages)
+
+		opts := mockClient.LastRunOptions
+		require.NotNil(t, opts)
+		assert.Equal(t, "node-"+strconv.Itoa(config.ProviderInstanceID), opts.Name)
+		assert.Equal(t, "node", opts.Hostname)
+		assert.Equal(t, mocks.DefaultDockerNetwork, opts.Network)
+		assert.Equal(t, 2, opts.CPUs)
+		assert.Equal(t, 2048, opts.MemoryMB)
+		assert.True(t, strings.HasPrefix(opts.Env["TALIS_AUTHORIZED_KEY"], "ssh-ed25519 "))
+		assert.Equal(t, strconv.Itoa(config.ProviderInstanceID), opts.Labels[localRefLabel])
+		assert.Equal(t, "test-project", opts.Labels[localProjectLabel])
+
+		require.Len(t, opts.Mounts, 2)
+		assert.Equal(t, "/mnt/data", opts.Mounts[0].Target)
+		assert.Equal(t, "/mnt/scratch", opts.Mounts[1].Target)
+		assert.Equal(t, config.VolumeIDs, []string{opts.Mounts[0].Volume, opts.Mounts[1].Volume})
+		assert.ElementsMatch(t, config.VolumeIDs, mockClient.Volumes())
+		assert.Equal(t, "/mnt/scratch", config.VolumeDetails[1].MountPoint)
+	})
+
+	t.Run("CreateInstance_CustomImage", func(t *testing.T) {
+		provider, mockClient := newTestLocalProvider(t)
+
+		err := provider.CreateInstance(context.Background(), &types.InstanceRequest{
+			InstanceID:  7,
+			ProjectName: "test-project",
+			Region:      "local",
+			Image:       "registry.example.com/sshd:latest",
+		})
+		require.NoError(t, err)
+		assert.Empty(t, mockClient.BuiltImages)
+		assert.Equal(t, "registry.example.com/sshd:latest", mockClient.LastRunOptions.Image)
+	})
+
+	t.Run("CreateInstance_MissingSSHKey", func(t *testing.T) {
+		provider, mockClient := newTestLocalProvider(t)
+		t.Setenv(constants.EnvTalisSSHKey, "")
+
+		err := provider.CreateInstance(context.Background(), &types.InstanceRequest{
+			InstanceID:  7,
+			ProjectName: "test-project",
+			Image:       LocalDefaultImage,
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get SSH key")
+		assert.Empty(t, mockClient.Containers())
+
+		err = provider.CreateInstance(context.Background(), &types.InstanceRequest{
+			ProjectName: "test-project",
+			Image:       LocalDefaultImage,
+		})
+		assert.ErrorContains(t, err, "no instance ID")
+	})
+
+	t.Run("CreateInstance_RunFailure_RemovesVolumes", func(t *testing.T) {
+		provider, mockClient := newTestLocalProvider(t)
+		mockClient.RunContainerFunc = func(_ context.Context, _ *computeTypes.DockerRunOptions) (string, error) {
+			return "", mocks.ErrDockerUnreachable
+		}
+
+		err := provider.CreateInstance(context.Background(), &types.InstanceRequest{
+			InstanceID:  7,
+			ProjectName: "test-project",
+			Image:       LocalDefaultImage,
+			Volumes:     []types.VolumeConfig{{Name: "data", SizeGB: 10}},
+		})
+		require.Error(t, err)
+		assert.Len(t, mockClient.RemovedVolumes, 1)
+		assert.Empty(t, mockClient.Volumes())
+	})
+
+	t.Run("DeleteInstance", func(t *testing.T) {
+		provider, mockClient := newTestLocalProvider(t)
+
+		config := &types.InstanceRequest{
+			InstanceID:  7,
+			ProjectName: "test-project",
+			Image:       LocalDefaultImage,
+			Volumes:     []types.VolumeConfig{{Name: "data", SizeGB: 10}},
+		}
+		require.NoError(t, provider.CreateInstance(context.Background(), config))
+
+		require.NoError(t, provider.DeleteInstance(context.Background(), config.ProviderInstanceID))
+		assert.Len(t, mockClient.RemovedContainers, 1)
+		assert.Equal(t, config.VolumeIDs, mockClient.RemovedVolumes)
+		assert.Empty(t, mockClient.Containers())
+
+		err := provider.DeleteInstance(context.Background(), config.ProviderInstanceID)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("GetInstance_ListInstances", func(t *testing.T) {
+		provider, _ := newTestLocalProvider(t)
+
+		config := &types.InstanceRequest{InstanceID: 7, ProjectName: "test-project", Name: "node", Image: LocalDefaultImage}
+		require.NoError(t, provider.CreateInstance(context.Background(), config))
+
+		instance, err := provider.GetInstance(context.Background(), config.ProviderInstanceID)
+		require.NoError(t, err)
+		assert.Equal(t, config.ProviderInstanceID, instance.ProviderInstanceID)
+		assert.Equal(t, types.ProviderInstanceStatusRunning, instance.Status)
+		assert.Equal(t, mocks.DefaultDockerContainerIP, instance.PublicIP)
+
+		instances, err := provider.ListInstances(context.Background())
+		require.NoError(t, err)
+		require.Len(t, instances, 1)
+		assert.Equal(t, *instance, instances[0])
+
+		require.NoError(t, provider.DeleteInstance(context.Background(), config.ProviderInstanceID))
+		_, err = provider.GetInstance(context.Background(), config.ProviderInstanceID)
+		assert.ErrorIs(t, err, types.ErrProviderInstanceNotFound)
+	})
+
+	t.Run("ImportInstance", func(t *testing.T) {
+		provider, mockClient := newTestLocalProvider(t)
+
+		config := &types.InstanceRequest{
+			InstanceID: 7, ProjectName: "test-project", Name: "node", Image: LocalDefaultImage,
+			Volumes: []types.VolumeConfig{{Name: "data", MountPoint: "/mnt/data"}},
+		}
+		require.NoError(t, provider.CreateInstance(context.Background(), config))
+		existing, err := provider.GetInstance(context.Background(), config.ProviderInstanceID)
+		require.NoError(t, err)
+
+		instance, err := provider.ImportInstance(context.Background(), existing.NativeID)
+		require.NoError(t, err)
+		assert.Equal(t, config.ProviderInstanceID, instance.ProviderInstanceID)
+		assert.Equal(t, config.VolumeIDs, []string{instance.Volumes[0].ID})
+
+		// Containers not started by the provider lack the reference label
+		id, err := mockClient.RunContainer(context.Background(), &computeTypes.DockerRunOptions{Name: "manual", Image: LocalDefaultImage})
+		require.NoError(t, err)
+		_, err = provider.ImportInstance(context.Background(), id)
+		assert.Error(t, err)
+	})
+}
+
+func TestDockerCLIClient(t *testing.T) {
+	// A fake docker binary records its arguments and answers inspect calls
+	dir := t.TempDir()
+	argsFile := filepath.Join(dir, "args")
+	script := `#!/bin/sh
+echo "$@" >> ` + argsFile + `
+case "$1" in
+  run) echo "abc123" ;;
+  container) echo '[{"Id":"abc123","Name":"/node-1","State":{"Running":true},"Config":{"Labels":{"talis.ref":"1"}},"NetworkSettings":{"Networks":{"talis":{"IPAddress":"172.30.0.5"}}},"Mounts":[{"Type":"volume","Name":"node-1-data"},{"Type":"bind","Name":""}]}]' ;;
+  ps) printf 'abc123\ndef456\n' ;;
+  rm) echo "no such container" >&2; exit 1 ;;
+esac
+`
+	binary := filepath.Join(dir, "docker")
+	require.NoError(t, os.WriteFile(binary, []byte(script), 0700)) // #nosec G306 -- test executable
+	client := NewDockerCLIClient(binary)
+	ctx := context.Background()
+
+	id, err := client.RunContainer(ctx, &computeTypes.DockerRunOptions{
+		Name:     "node-1",
+		Image:    LocalDefaultImage,
+		Network:  "talis",
+		Labels:   map[string]string{"talis.ref": "1"},
+		Mounts:   []computeTypes.DockerMount{{Volume: "node-1-data", Target: "/mnt/data"}},
+		MemoryMB: 512,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", id)
+
+	container, err := client.InspectContainer(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "node-1", container.Name)
+	assert.True(t, container.Running)
+	assert.Equal(t, "172.30.0.5", container.IPAddresses["talis"])
+	assert.Equal(t, []string{"node-1-data"}, container.Volumes)
+
+	ids, err := client.ListContainers(ctx, map[string]string{"talis.ref": "1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"abc123", "def456"}, ids)
+	_, err = client.ListContainers(ctx, map[string]string{"talis.ref": ""})
+	require.NoError(t, err)
+
+	err = client.RemoveContainer(ctx, "missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no such container")
+
+	args, err := os.ReadFile(argsFile) // #nosec G304 -- test file
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(args)), "\n")
+	assert.Equal(t, "run --detach --restart unless-stopped --name node-1 --network talis --label talis.ref=1 "+
+		"--mount type=volume,source=node-1-data,target=/mnt/data --memory 512m "+LocalDefaultImage, lines[0])
+	assert.Equal(t, "ps --all --quiet --no-trunc --filter label=talis.ref=1", lines[2])
+	assert.Equal(t, "ps --all --quiet --no-trunc --filter label=talis.ref", lines[3])
+}
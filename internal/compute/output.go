@@ -0,0 +1,61 @@
+package compute
+
+import (
+	"bytes"
+	"strings"
+)
+
+// maxOutputLineLength bounds the lines passed on by a lineWriter, longer lines are split
+const maxOutputLineLength = 8 * 1024
+
+// lineWriter calls fn with each line written to it, without its line ending. It is not safe
+// for concurrent use, each output stream gets a lineWriter of its own.
+type lineWriter struct {
+	fn      func(line string)
+	partial []byte
+}
+
+// newLineWriter creates a new lineWriter
+func newLineWriter(fn func(line string)) *lineWriter {
+	return &lineWriter{fn: fn}
+}
+
+// outputLines returns a lineWriter passing the lines of stream on to output, or nil if output is nil
+func outputLines(output func(stream OutputStream, line string), stream OutputStream) *lineWriter {
+	if output == nil {
+		return nil
+	}
+	return newLineWriter(func(line string) { output(stream, line) })
+}
+
+// Write implements io.Writer
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 || i > maxOutputLineLength {
+			if len(w.partial) < maxOutputLineLength {
+				break
+			}
+			w.emit(w.partial[:maxOutputLineLength])
+			w.partial = w.partial[maxOutputLineLength:]
+			continue
+		}
+		w.emit(w.partial[:i])
+		w.partial = w.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush passes on the last line if it was not terminated
+func (w *lineWriter) Flush() {
+	if len(w.partial) > 0 {
+		w.emit(w.partial)
+		w.partial = nil
+	}
+}
+
+// emit passes a line on, dropping what is not valid UTF-8 such as a character split by a long line
+func (w *lineWriter) emit(line []byte) {
+	w.fn(strings.ToValidUTF8(strings.TrimSuffix(string(line), "\r"), ""))
+}
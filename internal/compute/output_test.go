@@ -0,0 +1,26 @@
+package compute
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineWriter(t *testing.T) {
+	var lines []string
+	w := newLineWriter(func(line string) { lines = append(lines, line) })
+
+	// Lines are split across writes, and long lines are cut
+	for _, chunk := range []string{"first", " line\r\nsecond\n", "\n", strings.Repeat("x", maxOutputLineLength+10), "\nlast"} {
+		n, err := w.Write([]byte(chunk))
+		require.NoError(t, err)
+		require.Equal(t, len(chunk), n)
+	}
+	require.Equal(t, []string{"first line", "second", "", strings.Repeat("x", maxOutputLineLength), strings.Repeat("x", 10)}, lines)
+
+	w.Flush()
+	require.Equal(t, "last", lines[len(lines)-1])
+	w.Flush()
+	require.Len(t, lines, 6)
+}
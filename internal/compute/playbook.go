@@ -0,0 +1,103 @@
+package compute
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+	"github.com/celestiaorg/talis/internal/logger"
+)
+
+const (
+	// PlaybooksDir holds the playbooks registered on the server, one directory per playbook with
+	// its main.yml and roles
+	PlaybooksDir = ansibleDir + "/playbooks"
+
+	// workspaceExtraVarsFile is the name of the extra variables in the workspace of a provisioner
+	workspaceExtraVarsFile = "extra_vars.json"
+)
+
+// Playbook is a playbook run on an instance once it is set up, either registered on the server
+// or uploaded by its owner
+type Playbook struct {
+	// Name is the name the playbook was requested by
+	Name string
+	// Dir is the directory of a playbook registered on the server
+	Dir string
+	// Files are the files of an uploaded playbook, they are written to the workspace to run it
+	Files models.PlaybookFiles
+	// Tags selects the tasks of the playbook to run, all of them if empty
+	Tags []string
+	// ExtraVars are passed to the playbook as extra variables
+	ExtraVars map[string]interface{}
+}
+
+// RegisteredPlaybook returns the playbook registered on the server in dir under name, or nil
+// if there is none
+func RegisteredPlaybook(dir, name string) (*Playbook, error) {
+	if err := models.ValidatePlaybookName(name); err != nil {
+		return nil, err
+	}
+	playbookDir := filepath.Join(dir, name)
+	info, err := os.Stat(filepath.Join(playbookDir, models.PlaybookEntrypoint))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to access playbook %q: %w", name, err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("playbook %q has a directory as its %s", name, models.PlaybookEntrypoint)
+	}
+	return &Playbook{Name: name, Dir: playbookDir}, nil
+}
+
+// writePlaybook writes the files of an uploaded playbook to playbooksDir and its extra variables
+// to workspace. Uploaded playbooks are kept out of the workspace, which holds the SSH key copy.
+// It returns the path of the entrypoint of the playbook and of the extra variables file, which
+// is empty if the playbook has no extra variables. Variables configuring Ansible are dropped.
+func writePlaybook(workspace, playbooksDir string, playbook *Playbook) (string, string, error) {
+	dir := playbook.Dir
+	if dir == "" {
+		if err := playbook.Files.Validate(); err != nil {
+			return "", "", fmt.Errorf("invalid playbook %q: %w", playbook.Name, err)
+		}
+		dir = filepath.Join(playbooksDir, playbook.Name)
+		// Files of a previous run may have been removed from the playbook since
+		if err := os.RemoveAll(dir); err != nil {
+			return "", "", fmt.Errorf("failed to clean up playbook %q: %w", playbook.Name, err)
+		}
+		for name, content := range playbook.Files {
+			path := filepath.Join(dir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+				return "", "", fmt.Errorf("failed to create directory of playbook file %s: %w", name, err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				return "", "", fmt.Errorf("failed to write playbook file %s: %w", name, err)
+			}
+		}
+	}
+
+	extraVars := make(map[string]interface{}, len(playbook.ExtraVars))
+	for name, value := range playbook.ExtraVars {
+		if models.IsReservedPlaybookVar(name) {
+			logger.Warnf("⚠️ Dropping extra variable %q of playbook %q, ansible_ variables are not allowed", name, playbook.Name)
+			continue
+		}
+		extraVars[name] = value
+	}
+	if len(extraVars) == 0 {
+		return filepath.Join(dir, models.PlaybookEntrypoint), "", nil
+	}
+	data, err := json.Marshal(extraVars)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal extra variables of playbook %q: %w", playbook.Name, err)
+	}
+	extraVarsPath := filepath.Join(workspace, workspaceExtraVarsFile)
+	if err := os.WriteFile(extraVarsPath, data, 0600); err != nil {
+		return "", "", fmt.Errorf("failed to write extra variables of playbook %q: %w", playbook.Name, err)
+	}
+	return filepath.Join(dir, models.PlaybookEntrypoint), extraVarsPath, nil
+}
@@ -2,8 +2,15 @@ package compute
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/celestiaorg/talis/internal/constants"
 	"github.com/celestiaorg/talis/internal/db/models"
 	"github.com/celestiaorg/talis/internal/types"
 	"github.com/celestiaorg/talis/test/mocks"
@@ -20,13 +27,59 @@ type Provider interface {
 	// ConfigureProvider configures the provider with the given stack
 	ConfigureProvider(stack interface{}) error
 
-	// CreateInstance creates a new instance
+	// CreateInstance creates a new instance.
+	// req.SetProviderInstanceID is called as soon as the instance exists on the provider, before
+	// waiting for it to be ready, so that an instance whose creation failed, was cancelled or
+	// was interrupted afterwards can be deleted.
 	CreateInstance(ctx context.Context, req *types.InstanceRequest) error
 
 	// DeleteInstance deletes an instance
 	DeleteInstance(ctx context.Context, providerInstanceID int) error
+
+	// GetInstance returns the current state of an instance.
+	// It returns an error wrapping types.ErrProviderInstanceNotFound if the instance does not exist.
+	GetInstance(ctx context.Context, providerInstanceID int) (*types.ProviderInstance, error)
+
+	// ListInstances returns all instances of the account
+	ListInstances(ctx context.Context) ([]types.ProviderInstance, error)
+
+	// ImportInstance looks up an instance that may have been created outside of Talis by
+	// its provider-native ID and returns it together with its attached volumes.
+	// Instances of an InstanceReferencer that carry no reference yet have a zero ProviderInstanceID.
+	ImportInstance(ctx context.Context, nativeID string) (*types.ProviderInstance, error)
+
+	// ListRegions returns the regions instances can be created in.
+	// An empty list means the provider accepts any region.
+	ListRegions(ctx context.Context) ([]types.ProviderRegion, error)
+
+	// ListSizes returns the instance sizes that can be requested.
+	// An empty list means the provider accepts any size.
+	ListSizes(ctx context.Context) ([]types.ProviderSize, error)
+
+	// ListImages returns the images instances can be created from.
+	// An empty list means the provider accepts any image.
+	ListImages(ctx context.Context) ([]types.ProviderImage, error)
 }
 
+// ImageMatcher is implemented by providers that accept image references besides the
+// images they list, such as snapshot IDs or image name patterns
+type ImageMatcher interface {
+	// MatchesImage reports whether the image is accepted although it is not listed
+	MatchesImage(image string) bool
+}
+
+// InstanceReferencer is implemented by providers whose native instance IDs are not integers.
+// Their instances carry a reference, such as a tag, that holds the ProviderInstanceID.
+type InstanceReferencer interface {
+	// ReferenceInstance marks the instance with the given ProviderInstanceID, so that it can
+	// be addressed by it from then on
+	ReferenceInstance(ctx context.Context, nativeID string, providerInstanceID int) error
+}
+
+// ErrProvisioningFailed is returned by provisioners when the configuration of reachable hosts
+// failed, which retrying does not fix
+var ErrProvisioningFailed = errors.New("provisioning failed")
+
 // Provisioner is the interface for system configuration
 type Provisioner interface {
 	// ConfigureHost configures a single host
@@ -35,11 +88,67 @@ type Provisioner interface {
 	// ConfigureHosts configures multiple hosts in parallel, ensuring SSH readiness
 	ConfigureHosts(ctx context.Context, hosts []string) error
 
-	// CreateInventory creates an Ansible inventory file from instance info
-	CreateInventory(instance *types.InstanceRequest) (string, error)
+	// Provision sets up the instance of the request, mounts its volumes if opts.MountVolumes is
+	// set, copies and runs its payload, and runs opts.Playbook if it is set. Provisioning is
+	// stopped when ctx is cancelled. The result is returned as far as provisioning got, also when
+	// it failed.
+	Provision(ctx context.Context, instance *types.InstanceRequest, opts ProvisionOptions) (*ProvisionResult, error)
+
+	// Close releases what the provisioner holds, such as its workspace. Provisioners are not used after Close.
+	Close() error
+}
+
+// ProvisionOptions configure the provisioning of an instance
+type ProvisionOptions struct {
+	// MountVolumes formats and mounts the volumes of the instance
+	MountVolumes bool
+	// Output is called with each line of output of provisioning as it is written, if set
+	Output func(stream OutputStream, line string)
+	// Playbook is run once the instance is set up, if set. Only Ansible runs playbooks.
+	Playbook *Playbook
+	// PayloadStatus is called as the payload of the instance is copied and executed, if set.
+	// It is passed each status the payload advances to, from PayloadStatusCopied or
+	// PayloadStatusCopyFailed to PayloadStatusExecuted or PayloadStatusExecutionFailed.
+	PayloadStatus func(status models.PayloadStatus)
+}
+
+// reportPayload passes the status the payload advanced to to PayloadStatus, if set
+func (o ProvisionOptions) reportPayload(status models.PayloadStatus) {
+	if o.PayloadStatus != nil {
+		o.PayloadStatus(status)
+	}
+}
+
+// OutputStream is the stream a line of output of provisioning was written to
+type OutputStream string
+
+// Output stream constants
+const (
+	// OutputStdout is the standard output
+	OutputStdout OutputStream = "stdout"
+	// OutputStderr is the standard error
+	OutputStderr OutputStream = "stderr"
+)
+
+// ProvisionResult is the outcome of provisioning an instance
+type ProvisionResult struct {
+	// Recap holds the PLAY RECAP counts per host, it is empty for provisioners without playbooks
+	Recap map[string]models.AnsibleHostRecap
+	// Tasks are the results of the provisioning tasks per host, in the order they finished
+	Tasks []models.ProvisionTaskResult
+	// PayloadExitCode is the exit code of the payload script, nil if it was not executed
+	PayloadExitCode *int
+	// PayloadStdout and PayloadStderr hold the end of the output of the payload script, up to
+	// models.MaxPayloadOutputSize each
+	PayloadStdout string
+	PayloadStderr string
+}
 
-	// RunAnsiblePlaybook runs the Ansible playbook
-	RunAnsiblePlaybook(inventoryName string, tags []string) error
+// VolumeMounter is implemented by providers that attach volumes to instances without mounting
+// them, provisioning then formats and mounts the volumes
+type VolumeMounter interface {
+	// MountsVolumesOnProvision reports whether provisioning has to mount the volumes of instances
+	MountsVolumesOnProvision() bool
 }
 
 // NewComputeProvider creates a new compute provider based on the provider name
@@ -49,6 +158,16 @@ func NewComputeProvider(provider models.ProviderID) (Provider, error) {
 		return NewDigitalOceanProvider()
 	case models.ProviderXimera:
 		return NewXimeraProvider()
+	case models.ProviderHetzner:
+		return NewHetznerProvider()
+	case models.ProviderLinode:
+		return NewLinodeProvider()
+	case models.ProviderVultr:
+		return NewVultrProvider()
+	case models.ProviderAWS:
+		return NewAWSProvider()
+	case models.ProviderLocal:
+		return NewLocalProvider()
 	case "do-mock", "digitalocean-mock":
 		return mocks.NewMockDOClient(), nil
 	default:
@@ -56,7 +175,79 @@ func NewComputeProvider(provider models.ProviderID) (Provider, error) {
 	}
 }
 
-// NewProvisioner creates a new system provisioner
-func NewProvisioner(jobID string) Provisioner {
-	return NewAnsibleConfigurator(jobID)
+// labelTags converts key/value labels into sorted "key=value" tags, or "key" for labels without a value
+func labelTags(labels map[string]string) []string {
+	if len(labels) == 0 {
+		return nil
+	}
+	tags := make([]string, 0, len(labels))
+	for key, value := range labels {
+		tags = append(tags, labelTag(key, value))
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// labelTag formats a key/value label as "key=value" tag, or "key" if the value is empty
+func labelTag(key, value string) string {
+	if value == "" {
+		return key
+	}
+	return key + "=" + value
+}
+
+// deploymentIDPattern matches the deployment IDs that can be part of provider tags
+var deploymentIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// deploymentID returns the ID of this Talis deployment
+func deploymentID() (string, error) {
+	id := os.Getenv(constants.EnvTalisDeploymentID)
+	if id == "" {
+		return "", fmt.Errorf("environment variable %s not set, a deployment ID is required to reference instances", constants.EnvTalisDeploymentID)
+	}
+	if !deploymentIDPattern.MatchString(id) {
+		return "", fmt.Errorf("invalid %s %q: only lowercase letters, digits and hyphens are allowed", constants.EnvTalisDeploymentID, id)
+	}
+	return id, nil
+}
+
+// deploymentRef returns the reference of the Talis instance with the given ID for providers
+// that look instances up by a tag. It is prefixed with the deployment ID, so that Talis
+// deployments sharing a provider account never act on each other's instances.
+func deploymentRef(providerInstanceID int) (string, error) {
+	id, err := deploymentID()
+	if err != nil {
+		return "", err
+	}
+	return id + "-" + strconv.Itoa(providerInstanceID), nil
+}
+
+// parseDeploymentRef returns the Talis instance ID of a reference returned by deploymentRef.
+// References of other deployments are not parsed.
+func parseDeploymentRef(ref string) (int, bool) {
+	id, err := deploymentID()
+	if err != nil {
+		return 0, false
+	}
+	value, ok := strings.CutPrefix(ref, id+"-")
+	if !ok {
+		return 0, false
+	}
+	providerInstanceID, err := strconv.Atoi(value)
+	if err != nil || providerInstanceID <= 0 {
+		return 0, false
+	}
+	return providerInstanceID, true
+}
+
+// NewProvisioner creates a new system provisioner for a job, with a workspace of its own
+func NewProvisioner(provisioner models.ProvisionerID, jobID string) (Provisioner, error) {
+	switch provisioner {
+	case "", models.ProvisionerAnsible:
+		return NewAnsibleConfigurator(jobID), nil
+	case models.ProvisionerSSH:
+		return NewSSHProvisioner(jobID), nil
+	default:
+		return nil, fmt.Errorf("unsupported provisioner: %s", provisioner)
+	}
 }
@@ -0,0 +1,46 @@
+package compute
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+)
+
+// ProviderRegistry holds the compute providers of a server. Providers are created on first use
+// and shared by all the services, so that each provider and its API client only exist once.
+type ProviderRegistry struct {
+	mu        sync.Mutex
+	providers map[models.ProviderID]Provider
+}
+
+// NewProviderRegistry creates an empty provider registry
+func NewProviderRegistry() *ProviderRegistry {
+	return &ProviderRegistry{
+		providers: make(map[models.ProviderID]Provider),
+	}
+}
+
+// Get returns the compute provider for the given provider ID, creating it on first use.
+// Providers that fail to be created are not cached.
+func (r *ProviderRegistry) Get(providerID models.ProviderID) (Provider, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if provider, ok := r.providers[providerID]; ok {
+		return provider, nil
+	}
+	provider, err := NewComputeProvider(providerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create compute provider for provider %s: %w", providerID, err)
+	}
+	r.providers[providerID] = provider
+	return provider, nil
+}
+
+// Set registers the provider for the given provider ID, replacing the existing one
+func (r *ProviderRegistry) Set(providerID models.ProviderID, provider Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[providerID] = provider
+}
@@ -0,0 +1,60 @@
+package compute
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+)
+
+func TestProviderRegistry(t *testing.T) {
+	registry := NewProviderRegistry()
+	validProviderID := models.ProviderID("digitalocean-mock")
+	invalidProviderID := models.ProviderID("invalid-provider-id-for-test")
+
+	t.Run("Basic", func(t *testing.T) {
+		provider, err := registry.Get(validProviderID)
+		require.NoError(t, err)
+		require.NotNil(t, provider)
+
+		// Providers are created once and shared
+		cached, err := registry.Get(validProviderID)
+		require.NoError(t, err)
+		require.Same(t, provider, cached)
+
+		// Unsupported providers are not cached
+		provider, err = registry.Get(invalidProviderID)
+		require.ErrorContains(t, err, "unsupported provider")
+		require.Nil(t, provider)
+		_, cachedInvalid := registry.providers[invalidProviderID]
+		require.False(t, cachedInvalid)
+	})
+
+	t.Run("Set", func(t *testing.T) {
+		linode := &LinodeProvider{}
+		registry.Set(models.ProviderLinode, linode)
+		provider, err := registry.Get(models.ProviderLinode)
+		require.NoError(t, err)
+		require.Same(t, Provider(linode), provider)
+	})
+
+	t.Run("Concurrency", func(_ *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			providerID := validProviderID
+			if i%2 == 1 {
+				providerID = invalidProviderID
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					_, _ = registry.Get(providerID)
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
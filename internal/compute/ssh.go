@@ -0,0 +1,438 @@
+package compute
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/celestiaorg/talis/internal/constants"
+	"github.com/celestiaorg/talis/internal/db/models"
+	"github.com/celestiaorg/talis/internal/logger"
+	"github.com/celestiaorg/talis/internal/types"
+)
+
+const (
+	// sshUser is the user the SSH provisioner logs in as
+	sshUser = "root"
+
+	// sshPort is the port the SSH provisioner connects to
+	sshPort = 22
+
+	// sshConnectTimeout bounds a single connection attempt, including the SSH handshake
+	sshConnectTimeout = 5 * time.Second
+
+	// sshReadyAttempts and sshReadyInterval bound how long the SSH provisioner waits for an
+	// instance to accept SSH connections, 5 minutes like the playbook
+	sshReadyAttempts = 30
+	sshReadyInterval = 10 * time.Second
+
+	// sshOutputLimit is how much of the end of the output of a command is kept, as much as is
+	// stored of the output of a payload
+	sshOutputLimit = models.MaxPayloadOutputSize
+
+	// defaultVolumeMountPoint is where volumes without a mount point are mounted
+	defaultVolumeMountPoint = "/mnt/data"
+)
+
+// sshSetupScript installs the required packages and sets the hostname, like the setup stage of
+// the playbook. It is formatted with the quoted hostname.
+const sshSetupScript = `set -e
+export DEBIAN_FRONTEND=noninteractive
+# Local container instances ship the required packages and their hostname is managed by the
+# container engine
+if [ ! -f /.dockerenv ]; then
+	apt-get update -q
+	hostname %[1]s
+	echo %[1]s > /etc/hostname
+	if grep -q '^127\.0\.1\.1[[:space:]]' /etc/hosts; then
+		sed -i "s/^127\.0\.1\.1[[:space:]].*/127.0.1.1 "%[1]s"/" /etc/hosts
+	else
+		echo "127.0.1.1 "%[1]s >> /etc/hosts
+	fi
+fi
+if ! command -v curl >/dev/null || ! command -v git >/dev/null; then
+	for attempt in 1 2 3; do
+		if apt-get install -y -q curl git; then
+			break
+		fi
+		if [ "$attempt" = 3 ]; then
+			exit 1
+		fi
+		sleep 5
+	done
+fi
+echo 'Talis! 🚀' > /root/newfile.txt
+`
+
+// sshVolumesScript formats and mounts the first attached volume, like the volumes stage of the
+// playbook. It is formatted with the quoted mount point.
+const sshVolumesScript = `set -e
+device=$(lsblk -dnpo NAME,TYPE,RO | awk '$2 == "disk" && $3 == 0 && $1 !~ /^\/dev\/loop/ && $1 != "/dev/vda" { print $1; exit }')
+if [ -z "$device" ]; then
+	echo "no volume device found" >&2
+	exit 1
+fi
+mkdir -p %[1]s
+if [ -z "$(blkid -s TYPE -o value "$device")" ]; then
+	mkfs.ext4 -q "$device"
+fi
+if ! mountpoint -q %[1]s; then
+	mount "$device" %[1]s
+fi
+if ! grep -q "^$device " /etc/fstab; then
+	echo "$device "%[1]s" ext4 defaults,nofail 0 2" >> /etc/fstab
+fi
+`
+
+// SSHProvisioner implements the Provisioner interface over SSH from Talis itself, without
+// Ansible. It runs the same steps as the setup and volumes stages of the playbook.
+type SSHProvisioner struct {
+	// jobID is the unique identifier for the current job
+	jobID string
+	// port is the SSH port of the instances
+	port int
+	// readyAttempts and readyInterval bound the wait for instances to accept SSH connections
+	readyAttempts int
+	readyInterval time.Duration
+}
+
+// NewSSHProvisioner creates a new SSH provisioner
+func NewSSHProvisioner(jobID string) *SSHProvisioner {
+	return &SSHProvisioner{
+		jobID:         jobID,
+		port:          sshPort,
+		readyAttempts: sshReadyAttempts,
+		readyInterval: sshReadyInterval,
+	}
+}
+
+// ConfigureHost implements the Provisioner interface by waiting for the host to accept SSH connections
+func (p *SSHProvisioner) ConfigureHost(ctx context.Context, host string) error {
+	client, err := p.connect(ctx, host)
+	if err != nil {
+		return err
+	}
+	return client.Close()
+}
+
+// ConfigureHosts implements the Provisioner interface by waiting for the hosts to accept SSH
+// connections in parallel
+func (p *SSHProvisioner) ConfigureHosts(ctx context.Context, hosts []string) error {
+	errChan := make(chan error, len(hosts))
+	for _, host := range hosts {
+		go func() {
+			errChan <- p.ConfigureHost(ctx, host)
+		}()
+	}
+
+	var errs []error
+	for range hosts {
+		if err := <-errChan; err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Provision implements the Provisioner interface. Each command is a task of the result,
+// commands that fail on the instance fail with ErrProvisioningFailed. The output of the commands
+// is written to stdout and stderr and passed to opts.Output.
+func (p *SSHProvisioner) Provision(ctx context.Context, instance *types.InstanceRequest, opts ProvisionOptions) (*ProvisionResult, error) {
+	result := &ProvisionResult{}
+	if instance == nil || instance.PublicIP == "" {
+		return result, fmt.Errorf("no instance to provision for job %s", p.jobID)
+	}
+	if opts.Playbook != nil {
+		// Requests with playbooks are validated to use Ansible, this is not going to change on a retry
+		return result, fmt.Errorf("%w: playbook %q requires the ansible provisioner", ErrProvisioningFailed, opts.Playbook.Name)
+	}
+	host := instance.PublicIP
+
+	client, err := p.connect(ctx, host)
+	if err != nil {
+		if ctx.Err() == nil {
+			result.Tasks = append(result.Tasks, models.ProvisionTaskResult{
+				Task: "connect", Host: host, Status: models.ProvisionTaskUnreachable, Message: err.Error(),
+			})
+		}
+		return result, err
+	}
+	defer func() {
+		if err := client.Close(); err != nil {
+			logger.Debugf("Failed to close SSH connection to %s: %v", host, err)
+		}
+	}()
+	target := &sshHost{name: host, client: client, output: opts.Output, result: result}
+
+	fmt.Printf("🔧 Provisioning %s over SSH...\n", host)
+	if _, err := p.run(ctx, target, "setup", fmt.Sprintf(sshSetupScript, shellQuote(host)), nil); err != nil {
+		return result, err
+	}
+
+	if opts.MountVolumes {
+		mountPoint := defaultVolumeMountPoint
+		if len(instance.VolumeDetails) > 0 && instance.VolumeDetails[0].MountPoint != "" {
+			mountPoint = instance.VolumeDetails[0].MountPoint
+		}
+		if _, err := p.run(ctx, target, "volumes", fmt.Sprintf(sshVolumesScript, shellQuote(mountPoint)), nil); err != nil {
+			return result, err
+		}
+	}
+
+	if instance.PayloadPath != "" {
+		destPath := path.Join("/root", filepath.Base(instance.PayloadPath))
+		if err := p.upload(ctx, target, instance.PayloadPath, destPath); err != nil {
+			opts.reportPayload(models.PayloadStatusCopyFailed)
+			return result, err
+		}
+		opts.reportPayload(models.PayloadStatusCopied)
+
+		if instance.ExecutePayload {
+			opts.reportPayload(models.PayloadStatusPendingExecution)
+			output, err := p.run(ctx, target, "payload", "bash "+shellQuote(destPath), nil)
+			if output != nil {
+				result.PayloadStdout = output.stdout.String()
+				result.PayloadStderr = output.stderr.String()
+				if output.exitCode >= 0 {
+					exitCode := output.exitCode
+					result.PayloadExitCode = &exitCode
+				}
+			}
+			if err != nil {
+				opts.reportPayload(models.PayloadStatusExecutionFailed)
+				return result, err
+			}
+			opts.reportPayload(models.PayloadStatusExecuted)
+		}
+	}
+
+	fmt.Printf("✅ Provisioned %s over SSH\n", host)
+	return result, nil
+}
+
+// Close implements the Provisioner interface, the SSH provisioner keeps no files
+func (p *SSHProvisioner) Close() error {
+	return nil
+}
+
+// connect waits for the host to accept SSH connections and returns the connected client
+func (p *SSHProvisioner) connect(ctx context.Context, host string) (*ssh.Client, error) {
+	config, err := sshClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(p.port))
+
+	fmt.Printf("⏳ Waiting for SSH to be available on %s...\n", host)
+	var lastErr error
+	for i := 0; i < p.readyAttempts; i++ {
+		client, err := dialSSH(ctx, addr, config)
+		if err == nil {
+			fmt.Printf("✅ SSH connection established to %s\n", host)
+			return client, nil
+		}
+		lastErr = err
+
+		if i < p.readyAttempts-1 {
+			logger.Debugf("Retrying SSH connection to %s in %s (%d/%d): %v", host, p.readyInterval, i+1, p.readyAttempts, err)
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("context cancelled while waiting for SSH to be available on %s: %w", host, context.Cause(ctx))
+			case <-time.After(p.readyInterval):
+			}
+		}
+	}
+	return nil, fmt.Errorf("timeout waiting for SSH to be ready on %s after %d attempts: %w", host, p.readyAttempts, lastErr)
+}
+
+// sshClientConfig returns the client configuration logging in with the key in TALIS_SSH_KEY
+func sshClientConfig() (*ssh.ClientConfig, error) {
+	key := os.Getenv(constants.EnvTalisSSHKey)
+	if key == "" {
+		return nil, fmt.Errorf("no SSH key found in environment variable %s", constants.EnvTalisSSHKey)
+	}
+	signer, err := ssh.ParsePrivateKey([]byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SSH key from %s: %w", constants.EnvTalisSSHKey, err)
+	}
+
+	return &ssh.ClientConfig{
+		User: sshUser,
+		Auth: []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		// #nosec G106 -- instances are new, their host keys are not known in advance, the
+		// playbook disables host key checking as well
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         sshConnectTimeout,
+	}, nil
+}
+
+// dialSSH connects to addr, bounding the connection and the handshake by the timeout of config
+func dialSSH(ctx context.Context, addr string, config *ssh.ClientConfig) (*ssh.Client, error) {
+	dialer := net.Dialer{Timeout: config.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn.SetDeadline(time.Now().Add(config.Timeout)); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	clientConn, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	if err := conn.SetDeadline(time.Time{}); err != nil {
+		_ = clientConn.Close()
+		return nil, err
+	}
+	return ssh.NewClient(clientConn, chans, reqs), nil
+}
+
+// sshHost is a host being provisioned over SSH
+type sshHost struct {
+	name   string
+	client *ssh.Client
+	// output is passed each line of output of the commands, if set
+	output func(stream OutputStream, line string)
+	// result collects the outcome of each command as a task
+	result *ProvisionResult
+}
+
+// commandOutput is the outcome of a command run on an instance
+type commandOutput struct {
+	// stdout and stderr hold the end of the output of the command
+	stdout tailBuffer
+	stderr tailBuffer
+	// exitCode is the exit code of the command, -1 if it did not exit
+	exitCode int
+}
+
+// run runs a command on the host, feeding it stdin if set, and records it as a task of the
+// result of the host. The command is stopped when ctx is cancelled.
+func (p *SSHProvisioner) run(ctx context.Context, host *sshHost, name, command string, stdin io.Reader) (*commandOutput, error) {
+	output, err := p.runCommand(ctx, host, name, command, stdin)
+	task := models.ProvisionTaskResult{Task: name, Host: host.name, Status: models.ProvisionTaskOk}
+	if err != nil {
+		task.Status = models.ProvisionTaskFailed
+		task.Message = err.Error()
+	}
+	host.result.Tasks = append(host.result.Tasks, task)
+	return output, err
+}
+
+// runCommand runs a command on the host, see run
+func (p *SSHProvisioner) runCommand(ctx context.Context, host *sshHost, name, command string, stdin io.Reader) (*commandOutput, error) {
+	session, err := host.client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SSH session for %s: %w", name, err)
+	}
+	defer func() { _ = session.Close() }()
+
+	output := &commandOutput{
+		stdout:   tailBuffer{limit: sshOutputLimit},
+		stderr:   tailBuffer{limit: sshOutputLimit},
+		exitCode: -1,
+	}
+	session.Stdin = stdin
+	stdout := []io.Writer{os.Stdout, &output.stdout}
+	stderr := []io.Writer{os.Stderr, &output.stderr}
+	stdoutLines, stderrLines := outputLines(host.output, OutputStdout), outputLines(host.output, OutputStderr)
+	if host.output != nil {
+		stdout = append(stdout, stdoutLines)
+		stderr = append(stderr, stderrLines)
+	}
+	session.Stdout = io.MultiWriter(stdout...)
+	session.Stderr = io.MultiWriter(stderr...)
+
+	// Closing the session stops the command, servers do not have to support signals
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = session.Close()
+		case <-done:
+		}
+	}()
+
+	err = session.Run(command)
+	if host.output != nil {
+		stdoutLines.Flush()
+		stderrLines.Flush()
+	}
+	if ctx.Err() != nil {
+		return output, fmt.Errorf("%s was stopped: %w", name, context.Cause(ctx))
+	}
+	var exitErr *ssh.ExitError
+	switch {
+	case err == nil:
+		output.exitCode = 0
+		return output, nil
+	case errors.As(err, &exitErr):
+		// The instance was reachable, running the command again fails the same way
+		output.exitCode = exitErr.ExitStatus()
+		return output, fmt.Errorf("%w: %s exited with code %d: %s", ErrProvisioningFailed, name, output.exitCode, lastLine(output.stderr.String()))
+	default:
+		return output, fmt.Errorf("failed to run %s: %w", name, err)
+	}
+}
+
+// upload copies a local file to destPath on the host, readable and executable by root only
+func (p *SSHProvisioner) upload(ctx context.Context, host *sshHost, localPath, destPath string) error {
+	// #nosec G304 -- the payload path was validated with the request
+	f, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to open payload %s: %w", localPath, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	quoted := shellQuote(destPath)
+	_, err = p.run(ctx, host, "payload upload", fmt.Sprintf("umask 077 && cat > %s && chmod 0700 %s", quoted, quoted), f)
+	return err
+}
+
+// tailBuffer keeps the last limit bytes written to it
+type tailBuffer struct {
+	limit int
+	buf   []byte
+}
+
+// Write implements io.Writer
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.limit {
+		b.buf = append(b.buf[:0], b.buf[len(b.buf)-b.limit:]...)
+	}
+	return len(p), nil
+}
+
+// String returns the bytes kept
+func (b *tailBuffer) String() string {
+	return string(b.buf)
+}
+
+// lastLine returns the last non-empty line of s
+func lastLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
+
+// shellQuote quotes s as a single word for POSIX shells
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
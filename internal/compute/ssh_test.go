@@ -0,0 +1,305 @@
+package compute
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/celestiaorg/talis/internal/constants"
+	"github.com/celestiaorg/talis/internal/db/models"
+	"github.com/celestiaorg/talis/internal/types"
+)
+
+// sshCommand is a command received by the test SSH server
+type sshCommand struct {
+	command string
+	stdin   string
+}
+
+// testSSHServer is an in-process SSH server that records the commands it receives and exits
+// them with the code returned by exitCode
+type testSSHServer struct {
+	listener net.Listener
+	exitCode func(command string) uint32
+
+	mu       sync.Mutex
+	commands []sshCommand
+}
+
+// newTestSSHServer starts an SSH server accepting the key it sets in TALIS_SSH_KEY
+func newTestSSHServer(t *testing.T, exitCode func(command string) uint32) *testSSHServer {
+	t.Helper()
+
+	_, clientKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(clientKey, "")
+	require.NoError(t, err)
+	t.Setenv(constants.EnvTalisSSHKey, string(pem.EncodeToMemory(block)))
+	clientSigner, err := ssh.NewSignerFromKey(clientKey)
+	require.NoError(t, err)
+
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hostSigner, err := ssh.NewSignerFromKey(hostKey)
+	require.NoError(t, err)
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() != sshUser || string(key.Marshal()) != string(clientSigner.PublicKey().Marshal()) {
+				return nil, errors.New("unknown key")
+			}
+			return nil, nil
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	server := &testSSHServer{listener: listener, exitCode: exitCode}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn, config)
+		}
+	}()
+	return server
+}
+
+func (s *testSSHServer) serve(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "only sessions are supported")
+			continue
+		}
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go s.session(channel, requests)
+	}
+}
+
+func (s *testSSHServer) session(channel ssh.Channel, requests <-chan *ssh.Request) {
+	defer func() { _ = channel.Close() }()
+
+	for req := range requests {
+		if req.Type != "exec" {
+			_ = req.Reply(false, nil)
+			continue
+		}
+		var payload struct{ Command string }
+		if err := ssh.Unmarshal(req.Payload, &payload); err != nil {
+			_ = req.Reply(false, nil)
+			return
+		}
+		_ = req.Reply(true, nil)
+
+		stdin, _ := io.ReadAll(channel)
+		s.mu.Lock()
+		s.commands = append(s.commands, sshCommand{command: payload.Command, stdin: string(stdin)})
+		s.mu.Unlock()
+
+		code := s.exitCode(payload.Command)
+		if code != 0 {
+			_, _ = channel.Stderr().Write([]byte("something went wrong\n"))
+		} else {
+			_, _ = channel.Write([]byte("done\n"))
+		}
+		status := make([]byte, 4)
+		binary.BigEndian.PutUint32(status, code)
+		_, _ = channel.SendRequest("exit-status", false, status)
+		return
+	}
+}
+
+func (s *testSSHServer) recorded() []sshCommand {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]sshCommand(nil), s.commands...)
+}
+
+// provisioner returns an SSH provisioner connecting to the server
+func (s *testSSHServer) provisioner() *SSHProvisioner {
+	p := NewSSHProvisioner("job-test")
+	p.port = s.listener.Addr().(*net.TCPAddr).Port
+	p.readyAttempts = 2
+	p.readyInterval = 10 * time.Millisecond
+	return p
+}
+
+func TestSSHProvisioner_Provision(t *testing.T) {
+	payloadPath := filepath.Join(t.TempDir(), "payload.sh")
+	require.NoError(t, os.WriteFile(payloadPath, []byte("echo hello\n"), 0600))
+
+	t.Run("runs the setup, volumes and payload", func(t *testing.T) {
+		server := newTestSSHServer(t, func(string) uint32 { return 0 })
+
+		var lines []string
+		var statuses []models.PayloadStatus
+		result, err := server.provisioner().Provision(context.Background(), &types.InstanceRequest{
+			PublicIP:       "127.0.0.1",
+			PayloadPath:    payloadPath,
+			ExecutePayload: true,
+			VolumeDetails:  []types.VolumeDetails{{MountPoint: "/srv/it's"}},
+		}, ProvisionOptions{MountVolumes: true, Output: func(stream OutputStream, line string) {
+			lines = append(lines, string(stream)+": "+line)
+		}, PayloadStatus: func(status models.PayloadStatus) {
+			statuses = append(statuses, status)
+		}})
+		require.NoError(t, err)
+		require.NotNil(t, result.PayloadExitCode)
+		require.Equal(t, 0, *result.PayloadExitCode)
+		require.Equal(t, "done\n", result.PayloadStdout)
+		require.Empty(t, result.PayloadStderr)
+		require.Equal(t, []models.PayloadStatus{
+			models.PayloadStatusCopied, models.PayloadStatusPendingExecution, models.PayloadStatusExecuted,
+		}, statuses)
+		require.Equal(t, []string{"stdout: done", "stdout: done", "stdout: done", "stdout: done"}, lines)
+		require.Equal(t, []models.ProvisionTaskResult{
+			{Task: "setup", Host: "127.0.0.1", Status: models.ProvisionTaskOk},
+			{Task: "volumes", Host: "127.0.0.1", Status: models.ProvisionTaskOk},
+			{Task: "payload upload", Host: "127.0.0.1", Status: models.ProvisionTaskOk},
+			{Task: "payload", Host: "127.0.0.1", Status: models.ProvisionTaskOk},
+		}, result.Tasks)
+
+		commands := server.recorded()
+		require.Len(t, commands, 4)
+		require.Contains(t, commands[0].command, "hostname '127.0.0.1'")
+		require.Contains(t, commands[1].command, `mkdir -p '/srv/it'\''s'`)
+		require.Equal(t, "umask 077 && cat > '/root/payload.sh' && chmod 0700 '/root/payload.sh'", commands[2].command)
+		require.Equal(t, "echo hello\n", commands[2].stdin)
+		require.Equal(t, "bash '/root/payload.sh'", commands[3].command)
+	})
+
+	t.Run("skips the volumes and payload execution", func(t *testing.T) {
+		server := newTestSSHServer(t, func(string) uint32 { return 0 })
+
+		var statuses []models.PayloadStatus
+		result, err := server.provisioner().Provision(context.Background(), &types.InstanceRequest{
+			PublicIP:    "127.0.0.1",
+			PayloadPath: payloadPath,
+		}, ProvisionOptions{PayloadStatus: func(status models.PayloadStatus) {
+			statuses = append(statuses, status)
+		}})
+		require.NoError(t, err)
+		require.Nil(t, result.PayloadExitCode)
+		require.Equal(t, []models.PayloadStatus{models.PayloadStatusCopied}, statuses)
+
+		commands := server.recorded()
+		require.Len(t, commands, 2)
+		require.Contains(t, commands[0].command, "apt-get install")
+		require.True(t, strings.HasPrefix(commands[1].command, "umask 077"))
+	})
+
+	t.Run("records the exit code of a failed payload", func(t *testing.T) {
+		server := newTestSSHServer(t, func(command string) uint32 {
+			if strings.HasPrefix(command, "bash ") {
+				return 3
+			}
+			return 0
+		})
+
+		var statuses []models.PayloadStatus
+		result, err := server.provisioner().Provision(context.Background(), &types.InstanceRequest{
+			PublicIP:       "127.0.0.1",
+			PayloadPath:    payloadPath,
+			ExecutePayload: true,
+		}, ProvisionOptions{PayloadStatus: func(status models.PayloadStatus) {
+			statuses = append(statuses, status)
+		}})
+		require.ErrorIs(t, err, ErrProvisioningFailed)
+		require.ErrorContains(t, err, "payload exited with code 3: something went wrong")
+		require.NotNil(t, result.PayloadExitCode)
+		require.Equal(t, 3, *result.PayloadExitCode)
+		require.Equal(t, "something went wrong\n", result.PayloadStderr)
+		require.Equal(t, models.PayloadStatusExecutionFailed, statuses[len(statuses)-1])
+		failed := result.Tasks[len(result.Tasks)-1]
+		require.Equal(t, "payload", failed.Task)
+		require.Equal(t, models.ProvisionTaskFailed, failed.Status)
+		require.Equal(t, err.Error(), failed.Message)
+	})
+
+	t.Run("fails a failed setup without running the payload", func(t *testing.T) {
+		server := newTestSSHServer(t, func(string) uint32 { return 100 })
+
+		var statuses []models.PayloadStatus
+		result, err := server.provisioner().Provision(context.Background(), &types.InstanceRequest{
+			PublicIP:       "127.0.0.1",
+			PayloadPath:    payloadPath,
+			ExecutePayload: true,
+		}, ProvisionOptions{PayloadStatus: func(status models.PayloadStatus) {
+			statuses = append(statuses, status)
+		}})
+		require.ErrorIs(t, err, ErrProvisioningFailed)
+		require.Nil(t, result.PayloadExitCode)
+		require.Len(t, server.recorded(), 1)
+		require.Empty(t, statuses)
+	})
+
+	t.Run("reports a failed payload copy", func(t *testing.T) {
+		server := newTestSSHServer(t, func(command string) uint32 {
+			if strings.HasPrefix(command, "umask 077") {
+				return 1
+			}
+			return 0
+		})
+
+		var statuses []models.PayloadStatus
+		_, err := server.provisioner().Provision(context.Background(), &types.InstanceRequest{
+			PublicIP:       "127.0.0.1",
+			PayloadPath:    payloadPath,
+			ExecutePayload: true,
+		}, ProvisionOptions{PayloadStatus: func(status models.PayloadStatus) {
+			statuses = append(statuses, status)
+		}})
+		require.ErrorIs(t, err, ErrProvisioningFailed)
+		require.Equal(t, []models.PayloadStatus{models.PayloadStatusCopyFailed}, statuses)
+		require.Len(t, server.recorded(), 2)
+	})
+}
+
+func TestSSHProvisioner_ConfigureHost(t *testing.T) {
+	server := newTestSSHServer(t, func(string) uint32 { return 0 })
+	p := server.provisioner()
+	require.NoError(t, p.ConfigureHosts(context.Background(), []string{"127.0.0.1", "127.0.0.1"}))
+
+	// Nothing accepts connections once the server is closed
+	require.NoError(t, server.listener.Close())
+	err := p.ConfigureHost(context.Background(), "127.0.0.1")
+	require.ErrorContains(t, err, "timeout waiting for SSH to be ready on 127.0.0.1 after 2 attempts")
+
+	result, err := p.Provision(context.Background(), &types.InstanceRequest{PublicIP: "127.0.0.1"}, ProvisionOptions{})
+	require.Error(t, err)
+	require.Len(t, result.Tasks, 1)
+	require.Equal(t, models.ProvisionTaskUnreachable, result.Tasks[0].Status)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = p.ConfigureHost(ctx, "127.0.0.1")
+	require.ErrorIs(t, err, context.Canceled)
+}
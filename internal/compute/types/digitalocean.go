@@ -13,11 +13,18 @@ type DOClient interface {
 	Droplets() DropletService
 	Keys() KeyService
 	Storage() StorageService
+	Catalog() CatalogService
 	ValidateCredentials() error
 	GetEnvironmentVars() map[string]string
 	ConfigureProvider(stack interface{}) error
 	CreateInstance(ctx context.Context, config *types.InstanceRequest) error
 	DeleteInstance(ctx context.Context, dropletID int) error
+	GetInstance(ctx context.Context, dropletID int) (*types.ProviderInstance, error)
+	ListInstances(ctx context.Context) ([]types.ProviderInstance, error)
+	ImportInstance(ctx context.Context, nativeID string) (*types.ProviderInstance, error)
+	ListRegions(ctx context.Context) ([]types.ProviderRegion, error)
+	ListSizes(ctx context.Context) ([]types.ProviderSize, error)
+	ListImages(ctx context.Context) ([]types.ProviderImage, error)
 }
 
 // DropletService defines the interface for droplet operations
@@ -43,3 +50,10 @@ type StorageService interface {
 	AttachVolume(ctx context.Context, volumeID string, dropletID int) (*godo.Response, error)
 	DetachVolume(ctx context.Context, volumeID string, dropletID int) (*godo.Response, error)
 }
+
+// CatalogService defines the interface for listing regions, sizes and images
+type CatalogService interface {
+	ListRegions(ctx context.Context, opt *godo.ListOptions) ([]godo.Region, *godo.Response, error)
+	ListSizes(ctx context.Context, opt *godo.ListOptions) ([]godo.Size, *godo.Response, error)
+	ListImages(ctx context.Context, opt *godo.ListOptions) ([]godo.Image, *godo.Response, error)
+}
@@ -0,0 +1,55 @@
+package types
+
+import "context"
+
+// DockerClient defines the interface for the container operations used by the local provider
+type DockerClient interface {
+	// Version checks that the container engine is reachable
+	Version(ctx context.Context) error
+
+	ImageExists(ctx context.Context, image string) (bool, error)
+	BuildImage(ctx context.Context, tag string, dockerfile []byte) error
+
+	EnsureNetwork(ctx context.Context, name string) error
+
+	CreateVolume(ctx context.Context, name string, labels map[string]string) error
+	RemoveVolume(ctx context.Context, name string) error
+
+	RunContainer(ctx context.Context, opts *DockerRunOptions) (string, error)
+	InspectContainer(ctx context.Context, id string) (*DockerContainer, error)
+	// ListContainers lists the IDs of the containers carrying the labels.
+	// An empty label value matches every container carrying the label key.
+	ListContainers(ctx context.Context, labels map[string]string) ([]string, error)
+	RemoveContainer(ctx context.Context, id string) error
+}
+
+// DockerMount mounts a named volume into a container
+type DockerMount struct {
+	Volume string
+	Target string
+}
+
+// DockerRunOptions holds the options to start a detached container
+type DockerRunOptions struct {
+	Name     string
+	Hostname string
+	Image    string
+	Network  string
+	Env      map[string]string
+	Labels   map[string]string
+	Mounts   []DockerMount
+	CPUs     int // 0 means unlimited
+	MemoryMB int // 0 means unlimited
+}
+
+// DockerContainer is the state of a container
+type DockerContainer struct {
+	ID      string
+	Name    string
+	Running bool
+	Labels  map[string]string
+	// IPAddresses maps network names to the IP address of the container in that network
+	IPAddresses map[string]string
+	// Volumes lists the named volumes mounted into the container
+	Volumes []string
+}
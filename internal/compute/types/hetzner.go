@@ -0,0 +1,51 @@
+package types
+
+import (
+	"context"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+)
+
+// HetznerClient defines the interface for Hetzner Cloud client operations
+type HetznerClient interface {
+	Servers() HetznerServerService
+	SSHKeys() HetznerSSHKeyService
+	Volumes() HetznerVolumeService
+	Actions() HetznerActionService
+	Catalog() HetznerCatalogService
+	ValidateCredentials(ctx context.Context) error
+}
+
+// HetznerServerService defines the interface for Hetzner server operations
+type HetznerServerService interface {
+	Create(ctx context.Context, opts hcloud.ServerCreateOpts) (hcloud.ServerCreateResult, *hcloud.Response, error)
+	GetByID(ctx context.Context, id int64) (*hcloud.Server, *hcloud.Response, error)
+	All(ctx context.Context) ([]*hcloud.Server, error)
+	Delete(ctx context.Context, server *hcloud.Server) (*hcloud.ServerDeleteResult, *hcloud.Response, error)
+}
+
+// HetznerSSHKeyService defines the interface for Hetzner SSH key operations
+type HetznerSSHKeyService interface {
+	GetByName(ctx context.Context, name string) (*hcloud.SSHKey, *hcloud.Response, error)
+}
+
+// HetznerVolumeService defines the interface for Hetzner volume operations
+type HetznerVolumeService interface {
+	Create(ctx context.Context, opts hcloud.VolumeCreateOpts) (hcloud.VolumeCreateResult, *hcloud.Response, error)
+	GetByID(ctx context.Context, id int64) (*hcloud.Volume, *hcloud.Response, error)
+	Detach(ctx context.Context, volume *hcloud.Volume) (*hcloud.Action, *hcloud.Response, error)
+	Delete(ctx context.Context, volume *hcloud.Volume) (*hcloud.Response, error)
+}
+
+// HetznerActionService defines the interface for waiting on Hetzner actions
+type HetznerActionService interface {
+	WaitFor(ctx context.Context, actions ...*hcloud.Action) error
+}
+
+// HetznerCatalogService defines the interface for listing locations, server types and images
+type HetznerCatalogService interface {
+	Locations(ctx context.Context) ([]*hcloud.Location, error)
+	ServerTypes(ctx context.Context) ([]*hcloud.ServerType, error)
+	// Images lists the available system and app images
+	Images(ctx context.Context) ([]*hcloud.Image, error)
+}
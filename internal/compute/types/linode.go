@@ -0,0 +1,28 @@
+package types
+
+import (
+	"context"
+
+	"github.com/linode/linodego"
+)
+
+// LinodeClient defines the interface for Linode client operations.
+// It is a subset of *linodego.Client so the real client satisfies it directly.
+type LinodeClient interface {
+	GetProfile(ctx context.Context) (*linodego.Profile, error)
+	ListSSHKeys(ctx context.Context, opts *linodego.ListOptions) ([]linodego.SSHKey, error)
+
+	CreateInstance(ctx context.Context, opts linodego.InstanceCreateOptions) (*linodego.Instance, error)
+	GetInstance(ctx context.Context, linodeID int) (*linodego.Instance, error)
+	ListInstances(ctx context.Context, opts *linodego.ListOptions) ([]linodego.Instance, error)
+	DeleteInstance(ctx context.Context, linodeID int) error
+	ListInstanceVolumes(ctx context.Context, linodeID int, opts *linodego.ListOptions) ([]linodego.Volume, error)
+
+	CreateVolume(ctx context.Context, opts linodego.VolumeCreateOptions) (*linodego.Volume, error)
+	GetVolume(ctx context.Context, volumeID int) (*linodego.Volume, error)
+	DeleteVolume(ctx context.Context, volumeID int) error
+
+	ListRegions(ctx context.Context, opts *linodego.ListOptions) ([]linodego.Region, error)
+	ListTypes(ctx context.Context, opts *linodego.ListOptions) ([]linodego.LinodeType, error)
+	ListImages(ctx context.Context, opts *linodego.ListOptions) ([]linodego.Image, error)
+}
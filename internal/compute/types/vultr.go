@@ -0,0 +1,140 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// VultrClient defines the interface for Vultr API v2 operations
+type VultrClient interface {
+	ValidateCredentials(ctx context.Context) error
+
+	ListSSHKeys(ctx context.Context) ([]VultrSSHKey, error)
+	CreateSSHKey(ctx context.Context, req *VultrSSHKeyCreateRequest) (*VultrSSHKey, error)
+
+	CreateInstance(ctx context.Context, req *VultrInstanceCreateRequest) (*VultrInstance, error)
+	GetInstance(ctx context.Context, instanceID string) (*VultrInstance, error)
+	ListInstances(ctx context.Context, tag string) ([]VultrInstance, error)
+	UpdateInstanceTags(ctx context.Context, instanceID string, tags []string) error
+	DeleteInstance(ctx context.Context, instanceID string) error
+
+	CreateBlock(ctx context.Context, req *VultrBlockCreateRequest) (*VultrBlock, error)
+	GetBlock(ctx context.Context, blockID string) (*VultrBlock, error)
+	ListBlocks(ctx context.Context) ([]VultrBlock, error)
+	AttachBlock(ctx context.Context, blockID, instanceID string) error
+	DetachBlock(ctx context.Context, blockID string) error
+	DeleteBlock(ctx context.Context, blockID string) error
+
+	ListRegions(ctx context.Context) ([]VultrRegion, error)
+	ListPlans(ctx context.Context) ([]VultrPlan, error)
+	ListOS(ctx context.Context) ([]VultrOS, error)
+}
+
+// VultrSSHKey represents an SSH key registered with Vultr
+type VultrSSHKey struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	SSHKey      string `json:"ssh_key"`
+	DateCreated string `json:"date_created,omitempty"`
+}
+
+// VultrSSHKeyCreateRequest represents the request to register an SSH key
+type VultrSSHKeyCreateRequest struct {
+	Name   string `json:"name"`
+	SSHKey string `json:"ssh_key"`
+}
+
+// VultrInstance represents a Vultr instance
+type VultrInstance struct {
+	ID          string   `json:"id"`
+	Label       string   `json:"label"`
+	Region      string   `json:"region"`
+	Plan        string   `json:"plan"`
+	OS          string   `json:"os"`
+	OSID        int      `json:"os_id"`
+	ImageID     string   `json:"image_id"`
+	MainIP      string   `json:"main_ip"`
+	Status      string   `json:"status"`
+	PowerStatus string   `json:"power_status"`
+	Tags        []string `json:"tags"`
+}
+
+// VultrInstanceCreateRequest represents the request to create an instance
+type VultrInstanceCreateRequest struct {
+	Region   string   `json:"region"`
+	Plan     string   `json:"plan"`
+	OSID     int      `json:"os_id,omitempty"`
+	ImageID  string   `json:"image_id,omitempty"`
+	Label    string   `json:"label,omitempty"`
+	Hostname string   `json:"hostname,omitempty"`
+	SSHKeyID []string `json:"sshkey_id,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	UserData string   `json:"user_data,omitempty"` // base64 encoded
+	Backups  string   `json:"backups,omitempty"`
+}
+
+// VultrBlock represents a Vultr block storage volume
+type VultrBlock struct {
+	ID                 string `json:"id"`
+	Label              string `json:"label"`
+	Region             string `json:"region"`
+	SizeGB             int    `json:"size_gb"`
+	Status             string `json:"status"`
+	AttachedToInstance string `json:"attached_to_instance"`
+	MountID            string `json:"mount_id"`
+	BlockType          string `json:"block_type"`
+}
+
+// VultrBlockCreateRequest represents the request to create a block storage volume
+type VultrBlockCreateRequest struct {
+	Region    string `json:"region"`
+	SizeGB    int    `json:"size_gb"`
+	Label     string `json:"label,omitempty"`
+	BlockType string `json:"block_type,omitempty"`
+}
+
+// VultrRegion represents a Vultr region
+type VultrRegion struct {
+	ID        string   `json:"id"`
+	City      string   `json:"city"`
+	Country   string   `json:"country"`
+	Continent string   `json:"continent"`
+	Options   []string `json:"options"`
+}
+
+// VultrPlan represents a Vultr instance plan
+type VultrPlan struct {
+	ID        string   `json:"id"`
+	VCPUCount int      `json:"vcpu_count"`
+	RAM       int      `json:"ram"`  // in MB
+	Disk      int      `json:"disk"` // in GB
+	Type      string   `json:"type"`
+	Locations []string `json:"locations"`
+}
+
+// VultrOS represents an operating system that instances can be created from
+type VultrOS struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Arch   string `json:"arch"`
+	Family string `json:"family"`
+}
+
+// VultrAPIError is returned by the Vultr API client for non-2xx responses
+type VultrAPIError struct {
+	StatusCode int
+	Message    string
+}
+
+// Error implements the error interface
+func (e *VultrAPIError) Error() string {
+	return fmt.Sprintf("vultr API error: %d %s - %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// IsVultrNotFound reports whether err is a Vultr API 404 error
+func IsVultrNotFound(err error) bool {
+	var apiErr *VultrAPIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,658 @@
+package compute
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+
+	computeTypes "github.com/celestiaorg/talis/internal/compute/types"
+	"github.com/celestiaorg/talis/internal/constants"
+	"github.com/celestiaorg/talis/internal/logger"
+	talisTypes "github.com/celestiaorg/talis/internal/types"
+)
+
+const (
+	// vultrAPIKeyEnv is the environment variable containing the Vultr API key
+	vultrAPIKeyEnv = "VULTR_API_KEY"
+	// vultrAPIURLEnv optionally overrides the Vultr API base URL
+	vultrAPIURLEnv = "VULTR_API_URL"
+
+	// vultrRefTagPrefix prefixes the tag that maps a Vultr instance to its ProviderInstanceID.
+	// Vultr identifies instances by UUID while Talis stores an integer, so every instance
+	// is tagged with the deployment ID and its Talis instance ID, which are unique together,
+	// and looked up by that tag.
+	vultrRefTagPrefix = "talis-ref-"
+
+	// vultrStatusActive is the status of instances and blocks that are ready for use
+	vultrStatusActive = "active"
+)
+
+// VultrProvider implements the Provider interface for Vultr
+type VultrProvider struct {
+	client computeTypes.VultrClient
+}
+
+// NewVultrProvider creates a new Vultr provider instance
+func NewVultrProvider() (*VultrProvider, error) {
+	apiKey := os.Getenv(vultrAPIKeyEnv)
+	if apiKey == "" {
+		return nil, fmt.Errorf("%s environment variable is not set", vultrAPIKeyEnv)
+	}
+	if _, err := deploymentID(); err != nil {
+		return nil, err
+	}
+
+	client, err := NewVultrAPIClient(apiKey, os.Getenv(vultrAPIURLEnv))
+	if err != nil {
+		return nil, err
+	}
+	return &VultrProvider{client: client}, nil
+}
+
+// SetClient sets the Vultr client for testing
+func (p *VultrProvider) SetClient(client computeTypes.VultrClient) {
+	p.client = client
+}
+
+// ConfigureProvider is a no-op for Vultr
+func (p *VultrProvider) ConfigureProvider(_ interface{}) error {
+	return nil
+}
+
+// ValidateCredentials validates the Vultr credentials
+func (p *VultrProvider) ValidateCredentials() error {
+	if p.client == nil {
+		return fmt.Errorf("client not initialized")
+	}
+	if err := p.client.ValidateCredentials(context.Background()); err != nil {
+		return fmt.Errorf("vultr credential validation failed: %w", err)
+	}
+	return nil
+}
+
+// GetEnvironmentVars returns the environment variables needed for the provider
+func (p *VultrProvider) GetEnvironmentVars() map[string]string {
+	return map[string]string{
+		vultrAPIKeyEnv: os.Getenv(vultrAPIKeyEnv),
+		vultrAPIURLEnv: os.Getenv(vultrAPIURLEnv),
+	}
+}
+
+// MountsVolumesOnProvision reports that block storage is attached unformatted and unmounted,
+// provisioning mounts it
+func (p *VultrProvider) MountsVolumesOnProvision() bool {
+	return true
+}
+
+// CreateInstance creates a new Vultr instance.
+// InstanceRequest.Size is the Vultr plan (e.g. "vc2-1c-1gb") and Image is either a numeric
+// os_id (e.g. "1743" for Ubuntu 22.04) or a marketplace image_id.
+func (p *VultrProvider) CreateInstance(ctx context.Context, config *talisTypes.InstanceRequest) error {
+	if p.client == nil {
+		return fmt.Errorf("client not initialized")
+	}
+	if config.InstanceID == 0 {
+		return fmt.Errorf("instance request has no instance ID to reference the instance by")
+	}
+
+	logger.Debugf("🚀 Creating Vultr instance for project: %s", config.ProjectName)
+	logger.Debugf("  Region: %s", config.Region)
+	logger.Debugf("  Plan: %s", config.Size)
+	logger.Debugf("  Image: %s", config.Image)
+
+	sshKeyID, err := p.getSSHKeyID(ctx)
+	if err != nil {
+		logger.Errorf("❌ Failed to get SSH key: %v", err)
+		return fmt.Errorf("failed to get SSH key: %w", err)
+	}
+
+	ref := int(config.InstanceID)
+	refTag, err := vultrRefTag(ref)
+	if err != nil {
+		return err
+	}
+	createRequest := p.createInstanceRequest(config, sshKeyID, refTag)
+	logger.Debugf("  Sending instance creation request: %s", createRequest.Label)
+	instance, err := p.client.CreateInstance(ctx, createRequest)
+	if err != nil {
+		logger.Errorf("❌ Failed to create instance: %v", err)
+		return fmt.Errorf("failed to create instance: %w", err)
+	}
+
+	config.SetProviderInstanceID(ref)
+	config.VolumeIDs = []string{}
+	config.VolumeDetails = []talisTypes.VolumeDetails{}
+
+	ip, err := p.waitForInstanceActive(ctx, instance.ID)
+	if err != nil {
+		errMsg := fmt.Errorf("❌ Failed to get public IP for instance %s: %w", createRequest.Label, err)
+		logger.Error(errMsg)
+		return errMsg
+	}
+	config.PublicIP = ip
+
+	if len(config.Volumes) > 0 {
+		volumeIDs, volumeDetails, err := p.createAndAttachBlocks(ctx, instance.ID, createRequest.Label, config)
+		if err != nil {
+			logger.Errorf("❌ Failed to create/attach volumes for instance %s: %v", createRequest.Label, err)
+			return fmt.Errorf("failed to create/attach volumes for instance %s: %w", createRequest.Label, err)
+		}
+		config.VolumeIDs = volumeIDs
+		config.VolumeDetails = volumeDetails
+	}
+
+	logger.Infof("✅ Vultr instance '%s' (ID: %s, ref: %d) created successfully with IP %s", createRequest.Label, instance.ID, ref, ip)
+	return nil
+}
+
+// createInstanceRequest builds the VultrInstanceCreateRequest for the given request
+func (p *VultrProvider) createInstanceRequest(
+	config *talisTypes.InstanceRequest,
+	sshKeyID string,
+	refTag string,
+) *computeTypes.VultrInstanceCreateRequest {
+	var label string
+	if config.Name != "" {
+		if config.NumberOfInstances > 1 {
+			label = fmt.Sprintf("%s-%d", config.Name, config.InstanceIndex+1)
+		} else {
+			label = config.Name
+		}
+	} else {
+		label = fmt.Sprintf("%s-%s", config.ProjectName, generateRandomSuffix())
+	}
+
+	request := &computeTypes.VultrInstanceCreateRequest{
+		Region:   config.Region,
+		Plan:     config.Size,
+		Label:    label,
+		Hostname: label,
+		SSHKeyID: []string{sshKeyID},
+		Tags:     append([]string{label, refTag}, config.Tags...),
+		Backups:  "disabled",
+		UserData: base64.StdEncoding.EncodeToString([]byte(`#!/bin/bash
+apt-get update
+apt-get install -y python3
+`)),
+	}
+
+	if osID, err := strconv.Atoi(config.Image); err == nil {
+		request.OSID = osID
+	} else {
+		request.ImageID = config.Image
+	}
+
+	return request
+}
+
+// getSSHKeyID returns the ID of the Talis SSH key registered with Vultr.
+// If no key with the configured name exists yet, the public key of the Talis
+// private key is registered under that name.
+func (p *VultrProvider) getSSHKeyID(ctx context.Context) (string, error) {
+	keyName := os.Getenv(constants.EnvTalisSSHKeyName)
+	if keyName == "" {
+		return "", fmt.Errorf("environment variable %s not set, Talis SSH key name is required", constants.EnvTalisSSHKeyName)
+	}
+
+	logger.Debugf("🔑 Looking up SSH key: %s", keyName)
+	keys, err := p.client.ListSSHKeys(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list SSH keys: %w", err)
+	}
+
+	for _, key := range keys {
+		if key.Name == keyName {
+			logger.Debugf("✅ Found SSH key '%s' with ID: %s", keyName, key.ID)
+			return key.ID, nil
+		}
+	}
+
+	publicKey, err := talisPublicKey()
+	if err != nil {
+		return "", fmt.Errorf("SSH key '%s' not found and could not be registered: %w", keyName, err)
+	}
+
+	logger.Debugf("🔑 Registering SSH key '%s' with Vultr", keyName)
+	key, err := p.client.CreateSSHKey(ctx, &computeTypes.VultrSSHKeyCreateRequest{
+		Name:   keyName,
+		SSHKey: publicKey,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to register SSH key '%s': %w", keyName, err)
+	}
+
+	logger.Debugf("✅ Registered SSH key '%s' with ID: %s", keyName, key.ID)
+	return key.ID, nil
+}
+
+// waitForInstanceActive waits for an instance to become active with a public IPv4 address
+func (p *VultrProvider) waitForInstanceActive(ctx context.Context, instanceID string) (string, error) {
+	logger.Debug("⏳ Waiting for instance to become active...")
+	maxRetries := 30
+	interval := 10 * time.Second
+
+	for i := 0; i < maxRetries; i++ {
+		instance, err := p.client.GetInstance(ctx, instanceID)
+		if err != nil {
+			logger.Errorf("❌ Failed to get instance details: %v", err)
+		} else if instance.Status == vultrStatusActive && instance.MainIP != "" && instance.MainIP != "0.0.0.0" {
+			logger.Debugf("📍 Found public IP for instance: %s", instance.MainIP)
+			return instance.MainIP, nil
+		}
+
+		logger.Debugf("⏳ Instance not active yet, retrying in 10 seconds (attempt %d/%d)...", i+1, maxRetries)
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+
+	return "", fmt.Errorf("instance created but not active after %d retries", maxRetries)
+}
+
+// createAndAttachBlocks creates block storage volumes and attaches them to the given instance
+func (p *VultrProvider) createAndAttachBlocks(
+	ctx context.Context,
+	instanceID string,
+	label string,
+	config *talisTypes.InstanceRequest,
+) ([]string, []talisTypes.VolumeDetails, error) {
+	volumeIDs := make([]string, 0, len(config.Volumes))
+	volumeDetails := make([]talisTypes.VolumeDetails, 0, len(config.Volumes))
+
+	for _, volConfig := range config.Volumes {
+		blockLabel := fmt.Sprintf("%s-%s", label, volConfig.Name)
+		logger.Debugf("📦 Creating block storage %s (%d GB)", blockLabel, volConfig.SizeGB)
+
+		block, err := p.client.CreateBlock(ctx, &computeTypes.VultrBlockCreateRequest{
+			Region: config.Region,
+			SizeGB: volConfig.SizeGB,
+			Label:  blockLabel,
+		})
+		if err != nil {
+			logger.Errorf("❌ Failed to create block storage: %v", err)
+			return nil, nil, fmt.Errorf("failed to create volume %s: %w", blockLabel, err)
+		}
+
+		if err := p.waitForBlock(ctx, block.ID, func(b *computeTypes.VultrBlock) bool {
+			return b.Status == vultrStatusActive
+		}); err != nil {
+			p.deleteBlock(ctx, block.ID)
+			return nil, nil, err
+		}
+
+		logger.Debugf("📦 Attaching block storage %s to instance %s", block.ID, instanceID)
+		if err := p.client.AttachBlock(ctx, block.ID, instanceID); err != nil {
+			logger.Errorf("❌ Failed to attach block storage: %v", err)
+			p.deleteBlock(ctx, block.ID)
+			return nil, nil, fmt.Errorf("failed to attach volume %s: %w", blockLabel, err)
+		}
+
+		if err := p.waitForBlock(ctx, block.ID, func(b *computeTypes.VultrBlock) bool {
+			return b.AttachedToInstance == instanceID
+		}); err != nil {
+			return nil, nil, err
+		}
+
+		logger.Debugf("✅ Block storage %s (ID: %s) attached to instance %s", blockLabel, block.ID, instanceID)
+		volumeIDs = append(volumeIDs, block.ID)
+		volumeDetails = append(volumeDetails, talisTypes.VolumeDetails{
+			ID:         block.ID,
+			Name:       blockLabel,
+			Region:     config.Region,
+			SizeGB:     volConfig.SizeGB,
+			MountPoint: volConfig.MountPoint,
+		})
+	}
+
+	return volumeIDs, volumeDetails, nil
+}
+
+// waitForBlock polls a block storage volume until done reports true
+func (p *VultrProvider) waitForBlock(ctx context.Context, blockID string, done func(*computeTypes.VultrBlock) bool) error {
+	maxRetries := 30
+	interval := 5 * time.Second
+
+	for i := 0; i < maxRetries; i++ {
+		block, err := p.client.GetBlock(ctx, blockID)
+		if err != nil {
+			return fmt.Errorf("failed to get block storage %s status: %w", blockID, err)
+		}
+		if done(block) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+
+	return fmt.Errorf("block storage %s did not reach the expected state after %d retries", blockID, maxRetries)
+}
+
+// deleteBlock deletes a block storage volume, logging instead of failing on errors
+func (p *VultrProvider) deleteBlock(ctx context.Context, blockID string) {
+	logger.Debugf("🗑️ Deleting block storage %s", blockID)
+	if err := p.client.DeleteBlock(ctx, blockID); err != nil {
+		logger.Warnf("⚠️ Warning: Failed to delete block storage %s: %v", blockID, err)
+	}
+}
+
+// DeleteInstance deletes a Vultr instance and its attached block storage.
+// The instance is looked up through the reference tag set on creation.
+func (p *VultrProvider) DeleteInstance(ctx context.Context, providerInstanceID int) error {
+	if p.client == nil {
+		return fmt.Errorf("client not initialized")
+	}
+
+	logger.Debugf("🗑️ Deleting Vultr instance with ref: %d", providerInstanceID)
+
+	instance, err := p.getRefInstance(ctx, providerInstanceID)
+	if err != nil {
+		return err
+	}
+
+	blocks, err := p.client.ListBlocks(ctx)
+	if err != nil {
+		logger.Warnf("⚠️ Warning: Failed to list block storage: %v", err)
+	}
+
+	// Block storage must be detached before it can be deleted
+	var attached []string
+	for _, block := range blocks {
+		if block.AttachedToInstance != instance.ID {
+			continue
+		}
+		logger.Debugf("🗑️ Detaching block storage: %s", block.ID)
+		if err := p.client.DetachBlock(ctx, block.ID); err != nil {
+			logger.Warnf("⚠️ Warning: Failed to detach block storage %s: %v", block.ID, err)
+		}
+		attached = append(attached, block.ID)
+	}
+
+	logger.Debugf("🗑️ Deleting instance with ID: %s", instance.ID)
+	if err := p.client.DeleteInstance(ctx, instance.ID); err != nil {
+		if computeTypes.IsVultrNotFound(err) {
+			return fmt.Errorf("instance %s not found: %w", instance.ID, err)
+		}
+		return fmt.Errorf("failed to delete instance: %w", err)
+	}
+
+	for _, blockID := range attached {
+		if err := p.waitForBlock(ctx, blockID, func(b *computeTypes.VultrBlock) bool {
+			return b.AttachedToInstance == ""
+		}); err != nil {
+			logger.Warnf("⚠️ Warning: %v", err)
+			continue
+		}
+		p.deleteBlock(ctx, blockID)
+	}
+
+	logger.Debugf("✅ Deleted Vultr instance with ref: %d", providerInstanceID)
+	return nil
+}
+
+// GetInstance returns the current state of a Vultr instance.
+// The instance is looked up through the reference tag set on creation.
+func (p *VultrProvider) GetInstance(ctx context.Context, providerInstanceID int) (*talisTypes.ProviderInstance, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	vultrInstance, err := p.getRefInstance(ctx, providerInstanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	instance := vultrToProviderInstance(vultrInstance)
+	return &instance, nil
+}
+
+// getRefInstance returns the Vultr instance tagged with the given reference.
+// More than one tagged instance means the tag is not unique, and none of them is returned
+// rather than acting on an instance that may belong to someone else.
+func (p *VultrProvider) getRefInstance(ctx context.Context, providerInstanceID int) (*computeTypes.VultrInstance, error) {
+	refTag, err := vultrRefTag(providerInstanceID)
+	if err != nil {
+		return nil, err
+	}
+	instances, err := p.client.ListInstances(ctx, refTag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up instance: %w", err)
+	}
+	switch len(instances) {
+	case 0:
+		return nil, fmt.Errorf("instance with ref %d: %w", providerInstanceID, talisTypes.ErrProviderInstanceNotFound)
+	case 1:
+		return &instances[0], nil
+	default:
+		return nil, fmt.Errorf("%d instances are tagged with %s, refusing to act on ref %d", len(instances), refTag, providerInstanceID)
+	}
+}
+
+// ListInstances returns all instances of the Vultr account
+func (p *VultrProvider) ListInstances(ctx context.Context) ([]talisTypes.ProviderInstance, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	vultrInstances, err := p.client.ListInstances(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list instances: %w", err)
+	}
+
+	instances := make([]talisTypes.ProviderInstance, 0, len(vultrInstances))
+	for i := range vultrInstances {
+		instances = append(instances, vultrToProviderInstance(&vultrInstances[i]))
+	}
+	return instances, nil
+}
+
+// ImportInstance returns an existing Vultr instance with its attached block storage
+func (p *VultrProvider) ImportInstance(ctx context.Context, nativeID string) (*talisTypes.ProviderInstance, error) {
+	vultrInstance, err := p.getNativeInstance(ctx, nativeID)
+	if err != nil {
+		return nil, err
+	}
+	instance := vultrToProviderInstance(vultrInstance)
+
+	blocks, err := p.client.ListBlocks(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list blocks: %w", err)
+	}
+	for _, block := range blocks {
+		if block.AttachedToInstance != nativeID {
+			continue
+		}
+		instance.Volumes = append(instance.Volumes, talisTypes.VolumeDetails{
+			ID:     block.ID,
+			Name:   block.Label,
+			Region: block.Region,
+			SizeGB: block.SizeGB,
+		})
+	}
+	return &instance, nil
+}
+
+// ReferenceInstance adds the reference tag to an existing Vultr instance
+func (p *VultrProvider) ReferenceInstance(ctx context.Context, nativeID string, providerInstanceID int) error {
+	refTag, err := vultrRefTag(providerInstanceID)
+	if err != nil {
+		return err
+	}
+	vultrInstance, err := p.getNativeInstance(ctx, nativeID)
+	if err != nil {
+		return err
+	}
+	tags := append(append([]string{}, vultrInstance.Tags...), refTag)
+	if err := p.client.UpdateInstanceTags(ctx, nativeID, tags); err != nil {
+		return fmt.Errorf("failed to tag instance: %w", err)
+	}
+	return nil
+}
+
+// getNativeInstance returns a Vultr instance by its native ID
+func (p *VultrProvider) getNativeInstance(ctx context.Context, nativeID string) (*computeTypes.VultrInstance, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	vultrInstance, err := p.client.GetInstance(ctx, nativeID)
+	if err != nil {
+		if computeTypes.IsVultrNotFound(err) {
+			return nil, fmt.Errorf("instance %s: %w", nativeID, talisTypes.ErrProviderInstanceNotFound)
+		}
+		return nil, fmt.Errorf("failed to get instance: %w", err)
+	}
+	return vultrInstance, nil
+}
+
+// ListRegions returns the Vultr regions
+func (p *VultrProvider) ListRegions(ctx context.Context) ([]talisTypes.ProviderRegion, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	vultrRegions, err := p.client.ListRegions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list regions: %w", err)
+	}
+
+	regions := make([]talisTypes.ProviderRegion, 0, len(vultrRegions))
+	for _, region := range vultrRegions {
+		regions = append(regions, talisTypes.ProviderRegion{
+			ID:   region.ID,
+			Name: fmt.Sprintf("%s, %s", region.City, region.Country),
+		})
+	}
+	return regions, nil
+}
+
+// ListSizes returns the Vultr plans with the regions they are offered in
+func (p *VultrProvider) ListSizes(ctx context.Context) ([]talisTypes.ProviderSize, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	plans, err := p.client.ListPlans(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list plans: %w", err)
+	}
+
+	sizes := make([]talisTypes.ProviderSize, 0, len(plans))
+	for _, plan := range plans {
+		sizes = append(sizes, talisTypes.ProviderSize{
+			ID:       plan.ID,
+			CPU:      plan.VCPUCount,
+			MemoryMB: plan.RAM,
+			DiskGB:   plan.Disk,
+			Regions:  plan.Locations,
+		})
+	}
+	return sizes, nil
+}
+
+// ListImages returns the Vultr operating systems. The OS ID is the image to request.
+func (p *VultrProvider) ListImages(ctx context.Context) ([]talisTypes.ProviderImage, error) {
+	if p.client == nil {
+		return nil, fmt.Errorf("client not initialized")
+	}
+
+	systems, err := p.client.ListOS(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list operating systems: %w", err)
+	}
+
+	images := make([]talisTypes.ProviderImage, 0, len(systems))
+	for _, system := range systems {
+		images = append(images, talisTypes.ProviderImage{
+			ID:           strconv.Itoa(system.ID),
+			Name:         system.Name,
+			Distribution: system.Family,
+		})
+	}
+	return images, nil
+}
+
+// MatchesImage accepts non-numeric images, which are created from a marketplace image_id
+// rather than an OS ID
+func (p *VultrProvider) MatchesImage(image string) bool {
+	_, err := strconv.Atoi(image)
+	return err != nil
+}
+
+// vultrToProviderInstance converts a Vultr instance into its provider-independent representation.
+// Instances without a reference tag of this deployment were not created by it and get a zero
+// ProviderInstanceID.
+func vultrToProviderInstance(instance *computeTypes.VultrInstance) talisTypes.ProviderInstance {
+	result := talisTypes.ProviderInstance{
+		NativeID: instance.ID,
+		Name:     instance.Label,
+		Region:   instance.Region,
+		Size:     instance.Plan,
+		Image:    instance.OS,
+		Tags:     instance.Tags,
+	}
+	for _, tag := range instance.Tags {
+		ref, ok := strings.CutPrefix(tag, vultrRefTagPrefix)
+		if !ok {
+			continue
+		}
+		if providerInstanceID, ok := parseDeploymentRef(ref); ok {
+			result.ProviderInstanceID = providerInstanceID
+			break
+		}
+	}
+	if instance.MainIP != "0.0.0.0" {
+		result.PublicIP = instance.MainIP
+	}
+
+	switch {
+	case instance.Status == "pending":
+		result.Status = talisTypes.ProviderInstanceStatusPending
+	case instance.Status == vultrStatusActive && instance.PowerStatus == "stopped":
+		result.Status = talisTypes.ProviderInstanceStatusStopped
+	case instance.Status == vultrStatusActive:
+		result.Status = talisTypes.ProviderInstanceStatusRunning
+	case instance.Status == "suspended":
+		result.Status = talisTypes.ProviderInstanceStatusStopped
+	case instance.Status == "resizing":
+		result.Status = talisTypes.ProviderInstanceStatusPending
+	default:
+		result.Status = talisTypes.ProviderInstanceStatusUnknown
+	}
+	return result
+}
+
+// vultrRefTag returns the tag that identifies the instance with the given reference
+func vultrRefTag(providerInstanceID int) (string, error) {
+	ref, err := deploymentRef(providerInstanceID)
+	if err != nil {
+		return "", err
+	}
+	return vultrRefTagPrefix + ref, nil
+}
+
+// talisPublicKey derives the authorized_keys formatted public key from the Talis private key
+func talisPublicKey() (string, error) {
+	privateKey := os.Getenv(constants.EnvTalisSSHKey)
+	if privateKey == "" {
+		return "", fmt.Errorf("environment variable %s not set", constants.EnvTalisSSHKey)
+	}
+
+	signer, err := ssh.ParsePrivateKey([]byte(privateKey))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse private key from %s: %w", constants.EnvTalisSSHKey, err)
+	}
+
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey()))), nil
+}
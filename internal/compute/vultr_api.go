@@ -0,0 +1,340 @@
+package compute
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/vultr/govultr/v3"
+	"golang.org/x/oauth2"
+
+	computeTypes "github.com/celestiaorg/talis/internal/compute/types"
+)
+
+// vultrPageSize is the number of items requested per page when listing resources
+const vultrPageSize = 500
+
+// VultrAPIClient implements computeTypes.VultrClient on top of the govultr client
+type VultrAPIClient struct {
+	client *govultr.Client
+}
+
+// NewVultrAPIClient creates a new Vultr API client. An empty apiURL uses the public API.
+func NewVultrAPIClient(apiKey, apiURL string) (*VultrAPIClient, error) {
+	tokenSource := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: apiKey})
+	httpClient := oauth2.NewClient(context.Background(), tokenSource)
+	httpClient.Timeout = 30 * time.Second
+
+	client := govultr.NewClient(httpClient)
+	client.SetUserAgent("talis")
+	if apiURL != "" {
+		// govultr requests absolute /v2/... paths, so any path of apiURL is ignored
+		if err := client.SetBaseURL(apiURL); err != nil {
+			return nil, fmt.Errorf("invalid Vultr API URL %q: %w", apiURL, err)
+		}
+	}
+	return &VultrAPIClient{client: client}, nil
+}
+
+// vultrError converts a govultr error into a computeTypes.VultrAPIError when it comes from an
+// API response, so callers can check the status code. govultr returns the response body as
+// the error message, and no response at all for calls like Delete.
+func vultrError(resp *http.Response, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	apiErr := &computeTypes.VultrAPIError{Message: err.Error()}
+	var body struct {
+		Error  string `json:"error"`
+		Status int    `json:"status"`
+	}
+	if json.Unmarshal([]byte(err.Error()), &body) == nil {
+		if body.Error != "" {
+			apiErr.Message = body.Error
+		}
+		apiErr.StatusCode = body.Status
+	}
+	if resp != nil && (resp.StatusCode < 200 || resp.StatusCode >= 300) {
+		apiErr.StatusCode = resp.StatusCode
+	}
+	if apiErr.StatusCode == 0 {
+		return err
+	}
+	return apiErr
+}
+
+// vultrListAll fetches all pages of a cursor-paginated list call
+func vultrListAll[T any](list func(options *govultr.ListOptions) ([]T, *govultr.Meta, *http.Response, error)) ([]T, error) {
+	var all []T
+	options := &govultr.ListOptions{PerPage: vultrPageSize}
+	for {
+		items, meta, resp, err := list(options)
+		if err != nil {
+			return nil, vultrError(resp, err)
+		}
+		all = append(all, items...)
+		if meta == nil || meta.Links == nil || meta.Links.Next == "" {
+			return all, nil
+		}
+		options.Cursor = meta.Links.Next
+	}
+}
+
+// ValidateCredentials validates the API key by fetching the account details
+func (c *VultrAPIClient) ValidateCredentials(ctx context.Context) error {
+	_, resp, err := c.client.Account.Get(ctx)
+	return vultrError(resp, err)
+}
+
+// ListSSHKeys lists all SSH keys registered with the account
+func (c *VultrAPIClient) ListSSHKeys(ctx context.Context) ([]computeTypes.VultrSSHKey, error) {
+	keys, err := vultrListAll(func(options *govultr.ListOptions) ([]govultr.SSHKey, *govultr.Meta, *http.Response, error) {
+		return c.client.SSHKey.List(ctx, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]computeTypes.VultrSSHKey, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, vultrSSHKey(&key))
+	}
+	return result, nil
+}
+
+// CreateSSHKey registers a new SSH key with the account
+func (c *VultrAPIClient) CreateSSHKey(ctx context.Context, req *computeTypes.VultrSSHKeyCreateRequest) (*computeTypes.VultrSSHKey, error) {
+	key, resp, err := c.client.SSHKey.Create(ctx, &govultr.SSHKeyReq{Name: req.Name, SSHKey: req.SSHKey})
+	if err != nil {
+		return nil, vultrError(resp, err)
+	}
+	result := vultrSSHKey(key)
+	return &result, nil
+}
+
+// CreateInstance creates a new instance
+func (c *VultrAPIClient) CreateInstance(ctx context.Context, req *computeTypes.VultrInstanceCreateRequest) (*computeTypes.VultrInstance, error) {
+	instance, resp, err := c.client.Instance.Create(ctx, &govultr.InstanceCreateReq{
+		Region:   req.Region,
+		Plan:     req.Plan,
+		OsID:     req.OSID,
+		ImageID:  req.ImageID,
+		Label:    req.Label,
+		Hostname: req.Hostname,
+		SSHKeys:  req.SSHKeyID,
+		Tags:     req.Tags,
+		UserData: req.UserData,
+		Backups:  req.Backups,
+	})
+	if err != nil {
+		return nil, vultrError(resp, err)
+	}
+	result := vultrInstance(instance)
+	return &result, nil
+}
+
+// GetInstance gets an instance by ID
+func (c *VultrAPIClient) GetInstance(ctx context.Context, instanceID string) (*computeTypes.VultrInstance, error) {
+	instance, resp, err := c.client.Instance.Get(ctx, instanceID)
+	if err != nil {
+		return nil, vultrError(resp, err)
+	}
+	result := vultrInstance(instance)
+	return &result, nil
+}
+
+// ListInstances lists all instances, optionally filtered by tag
+func (c *VultrAPIClient) ListInstances(ctx context.Context, tag string) ([]computeTypes.VultrInstance, error) {
+	instances, err := vultrListAll(func(options *govultr.ListOptions) ([]govultr.Instance, *govultr.Meta, *http.Response, error) {
+		options.Tag = tag
+		return c.client.Instance.List(ctx, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]computeTypes.VultrInstance, 0, len(instances))
+	for _, instance := range instances {
+		result = append(result, vultrInstance(&instance))
+	}
+	return result, nil
+}
+
+// UpdateInstanceTags replaces the tags of an instance
+func (c *VultrAPIClient) UpdateInstanceTags(ctx context.Context, instanceID string, tags []string) error {
+	_, resp, err := c.client.Instance.Update(ctx, instanceID, &govultr.InstanceUpdateReq{Tags: tags})
+	return vultrError(resp, err)
+}
+
+// DeleteInstance deletes an instance
+func (c *VultrAPIClient) DeleteInstance(ctx context.Context, instanceID string) error {
+	return vultrError(nil, c.client.Instance.Delete(ctx, instanceID))
+}
+
+// CreateBlock creates a new block storage volume
+func (c *VultrAPIClient) CreateBlock(ctx context.Context, req *computeTypes.VultrBlockCreateRequest) (*computeTypes.VultrBlock, error) {
+	block, resp, err := c.client.BlockStorage.Create(ctx, &govultr.BlockStorageCreate{
+		Region:    req.Region,
+		SizeGB:    req.SizeGB,
+		Label:     req.Label,
+		BlockType: req.BlockType,
+	})
+	if err != nil {
+		return nil, vultrError(resp, err)
+	}
+	result := vultrBlock(block)
+	return &result, nil
+}
+
+// GetBlock gets a block storage volume by ID
+func (c *VultrAPIClient) GetBlock(ctx context.Context, blockID string) (*computeTypes.VultrBlock, error) {
+	block, resp, err := c.client.BlockStorage.Get(ctx, blockID)
+	if err != nil {
+		return nil, vultrError(resp, err)
+	}
+	result := vultrBlock(block)
+	return &result, nil
+}
+
+// ListBlocks lists all block storage volumes
+func (c *VultrAPIClient) ListBlocks(ctx context.Context) ([]computeTypes.VultrBlock, error) {
+	blocks, err := vultrListAll(func(options *govultr.ListOptions) ([]govultr.BlockStorage, *govultr.Meta, *http.Response, error) {
+		return c.client.BlockStorage.List(ctx, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]computeTypes.VultrBlock, 0, len(blocks))
+	for _, block := range blocks {
+		result = append(result, vultrBlock(&block))
+	}
+	return result, nil
+}
+
+// AttachBlock attaches a block storage volume to an instance without restarting it
+func (c *VultrAPIClient) AttachBlock(ctx context.Context, blockID, instanceID string) error {
+	live := true
+	return vultrError(nil, c.client.BlockStorage.Attach(ctx, blockID, &govultr.BlockStorageAttach{InstanceID: instanceID, Live: &live}))
+}
+
+// DetachBlock detaches a block storage volume from its instance without restarting it
+func (c *VultrAPIClient) DetachBlock(ctx context.Context, blockID string) error {
+	live := true
+	return vultrError(nil, c.client.BlockStorage.Detach(ctx, blockID, &govultr.BlockStorageDetach{Live: &live}))
+}
+
+// DeleteBlock deletes a block storage volume
+func (c *VultrAPIClient) DeleteBlock(ctx context.Context, blockID string) error {
+	return vultrError(nil, c.client.BlockStorage.Delete(ctx, blockID))
+}
+
+// ListRegions lists all regions
+func (c *VultrAPIClient) ListRegions(ctx context.Context) ([]computeTypes.VultrRegion, error) {
+	regions, err := vultrListAll(func(options *govultr.ListOptions) ([]govultr.Region, *govultr.Meta, *http.Response, error) {
+		return c.client.Region.List(ctx, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]computeTypes.VultrRegion, 0, len(regions))
+	for _, region := range regions {
+		result = append(result, computeTypes.VultrRegion{
+			ID:        region.ID,
+			City:      region.City,
+			Country:   region.Country,
+			Continent: region.Continent,
+			Options:   region.Options,
+		})
+	}
+	return result, nil
+}
+
+// ListPlans lists all instance plans
+func (c *VultrAPIClient) ListPlans(ctx context.Context) ([]computeTypes.VultrPlan, error) {
+	plans, err := vultrListAll(func(options *govultr.ListOptions) ([]govultr.Plan, *govultr.Meta, *http.Response, error) {
+		return c.client.Plan.List(ctx, "", options)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]computeTypes.VultrPlan, 0, len(plans))
+	for _, plan := range plans {
+		result = append(result, computeTypes.VultrPlan{
+			ID:        plan.ID,
+			VCPUCount: plan.VCPUCount,
+			RAM:       plan.RAM,
+			Disk:      plan.Disk,
+			Type:      plan.Type,
+			Locations: plan.Locations,
+		})
+	}
+	return result, nil
+}
+
+// ListOS lists all operating systems
+func (c *VultrAPIClient) ListOS(ctx context.Context) ([]computeTypes.VultrOS, error) {
+	systems, err := vultrListAll(func(options *govultr.ListOptions) ([]govultr.OS, *govultr.Meta, *http.Response, error) {
+		return c.client.OS.List(ctx, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]computeTypes.VultrOS, 0, len(systems))
+	for _, system := range systems {
+		result = append(result, computeTypes.VultrOS{
+			ID:     system.ID,
+			Name:   system.Name,
+			Arch:   system.Arch,
+			Family: system.Family,
+		})
+	}
+	return result, nil
+}
+
+// vultrSSHKey converts a govultr SSH key
+func vultrSSHKey(key *govultr.SSHKey) computeTypes.VultrSSHKey {
+	return computeTypes.VultrSSHKey{
+		ID:          key.ID,
+		Name:        key.Name,
+		SSHKey:      key.SSHKey,
+		DateCreated: key.DateCreated,
+	}
+}
+
+// vultrInstance converts a govultr instance
+func vultrInstance(instance *govultr.Instance) computeTypes.VultrInstance {
+	return computeTypes.VultrInstance{
+		ID:          instance.ID,
+		Label:       instance.Label,
+		Region:      instance.Region,
+		Plan:        instance.Plan,
+		OS:          instance.Os,
+		OSID:        instance.OsID,
+		ImageID:     instance.ImageID,
+		MainIP:      instance.MainIP,
+		Status:      instance.Status,
+		PowerStatus: instance.PowerStatus,
+		Tags:        instance.Tags,
+	}
+}
+
+// vultrBlock converts a govultr block storage volume
+func vultrBlock(block *govultr.BlockStorage) computeTypes.VultrBlock {
+	return computeTypes.VultrBlock{
+		ID:                 block.ID,
+		Label:              block.Label,
+		Region:             block.Region,
+		SizeGB:             block.SizeGB,
+		Status:             block.Status,
+		AttachedToInstance: block.AttachedToInstance,
+		MountID:            block.MountID,
+		BlockType:          block.BlockType,
+	}
+}
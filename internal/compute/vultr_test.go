@@ -0,0 +1,398 @@
+package compute
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	computeTypes "github.com/celestiaorg/talis/internal/compute/types"
+	"github.com/celestiaorg/talis/internal/constants"
+	"github.com/celestiaorg/talis/internal/types"
+	"github.com/celestiaorg/talis/test/mocks"
+)
+
+// newTestVultrProvider creates a new VultrProvider with a mock client for testing
+func newTestVultrProvider() (*VultrProvider, *mocks.MockVultrClient) {
+	mockClient := mocks.NewMockVultrClient()
+	provider := &VultrProvider{}
+	provider.SetClient(mockClient)
+	return provider, mockClient
+}
+
+func TestVultrProvider(t *testing.T) {
+	t.Setenv(constants.EnvTalisDeploymentID, "test")
+
+	t.Run("NewVultrProvider_MissingAPIKey", func(t *testing.T) {
+		t.Setenv(vultrAPIKeyEnv, "")
+		provider, err := NewVultrProvider()
+		assert.Error(t, err)
+		assert.Nil(t, provider)
+	})
+
+	t.Run("NewVultrProvider_MissingDeploymentID", func(t *testing.T) {
+		t.Setenv(vultrAPIKeyEnv, "test-key")
+		t.Setenv(constants.EnvTalisDeploymentID, "")
+		provider, err := NewVultrProvider()
+		assert.ErrorContains(t, err, constants.EnvTalisDeploymentID)
+		assert.Nil(t, provider)
+	})
+
+	t.Run("NewComputeProvider", func(t *testing.T) {
+		t.Setenv(vultrAPIKeyEnv, "test-key")
+		provider, err := NewComputeProvider("vultr")
+		require.NoError(t, err)
+		assert.IsType(t, &VultrProvider{}, provider)
+	})
+
+	t.Run("ValidateCredentials", func(t *testing.T) {
+		provider, mockClient := newTestVultrProvider()
+		assert.NoError(t, provider.ValidateCredentials())
+
+		mockClient.SimulateAuthenticationFailure()
+		assert.Error(t, provider.ValidateCredentials())
+
+		provider = &VultrProvider{}
+		err := provider.ValidateCredentials()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "client not initialized")
+	})
+
+	t.Run("CreateInstance_Success", func(t *testing.T) {
+		t.Setenv(constants.EnvTalisSSHKeyName, mocks.DefaultVultrKeyName)
+		provider, mockClient := newTestVultrProvider()
+
+		config := &types.InstanceRequest{
+			InstanceID:        7,
+			ProjectName:       "test-project",
+			Name:              "node",
+			Region:            "ewr",
+			Size:              "vc2-1c-1gb",
+			Image:             "1743",
+			NumberOfInstances: 1,
+			Volumes: []types.VolumeConfig{
+				{Name: "data", SizeGB: 40, MountPoint: "/mnt/data"},
+			},
+		}
+
+		err := provider.CreateInstance(context.Background(), config)
+		require.NoError(t, err)
+		assert.Equal(t, mocks.DefaultVultrInstanceIP, config.PublicIP)
+		assert.Equal(t, 7, config.ProviderInstanceID)
+
+		req := mockClient.LastCreateRequest
+		require.NotNil(t, req)
+		assert.Equal(t, "node", req.Label)
+		assert.Equal(t, "ewr", req.Region)
+		assert.Equal(t, "vc2-1c-1gb", req.Plan)
+		assert.Equal(t, 1743, req.OSID)
+		assert.Empty(t, req.ImageID)
+		assert.Equal(t, []string{mocks.DefaultVultrKeyID}, req.SSHKeyID)
+		assert.Contains(t, req.Tags, "talis-ref-test-7")
+
+		require.Len(t, config.VolumeDetails, 1)
+		blocks := mockClient.Blocks()
+		require.Len(t, blocks, 1)
+		assert.Equal(t, blocks[0].ID, config.VolumeIDs[0])
+		assert.NotEmpty(t, blocks[0].AttachedToInstance)
+		assert.Equal(t, "/mnt/data", config.VolumeDetails[0].MountPoint)
+	})
+
+	t.Run("CreateInstance_MarketplaceImage", func(t *testing.T) {
+		t.Setenv(constants.EnvTalisSSHKeyName, mocks.DefaultVultrKeyName)
+		provider, mockClient := newTestVultrProvider()
+
+		err := provider.CreateInstance(context.Background(), &types.InstanceRequest{
+			InstanceID:  7,
+			ProjectName: "test-project",
+			Region:      "ewr",
+			Size:        "vc2-1c-1gb",
+			Image:       "docker",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "docker", mockClient.LastCreateRequest.ImageID)
+		assert.Zero(t, mockClient.LastCreateRequest.OSID)
+	})
+
+	t.Run("CreateInstance_RegistersSSHKey", func(t *testing.T) {
+		_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		block, err := ssh.MarshalPrivateKey(privateKey, "")
+		require.NoError(t, err)
+
+		t.Setenv(constants.EnvTalisSSHKeyName, "new-key")
+		t.Setenv(constants.EnvTalisSSHKey, string(pem.EncodeToMemory(block)))
+		provider, mockClient := newTestVultrProvider()
+
+		err = provider.CreateInstance(context.Background(), &types.InstanceRequest{
+			InstanceID:  7,
+			ProjectName: "test-project",
+			Region:      "ewr",
+			Size:        "vc2-1c-1gb",
+			Image:       "1743",
+		})
+		require.NoError(t, err)
+
+		keys := mockClient.SSHKeys()
+		require.Len(t, keys, 2)
+		assert.Equal(t, "new-key", keys[1].Name)
+		assert.True(t, strings.HasPrefix(keys[1].SSHKey, "ssh-ed25519 "))
+		assert.Equal(t, []string{keys[1].ID}, mockClient.LastCreateRequest.SSHKeyID)
+	})
+
+	t.Run("CreateInstance_SSHKey_NotRegistrable", func(t *testing.T) {
+		t.Setenv(constants.EnvTalisSSHKeyName, "new-key")
+		t.Setenv(constants.EnvTalisSSHKey, "")
+		provider, _ := newTestVultrProvider()
+
+		err := provider.CreateInstance(context.Background(), &types.InstanceRequest{
+			InstanceID:  7,
+			ProjectName: "test-project",
+			Region:      "ewr",
+			Size:        "vc2-1c-1gb",
+			Image:       "1743",
+		})
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get SSH key")
+
+		err = provider.CreateInstance(context.Background(), &types.InstanceRequest{
+			ProjectName: "test-project",
+			Region:      "ewr",
+			Size:        "vc2-1c-1gb",
+			Image:       "1743",
+		})
+		assert.ErrorContains(t, err, "no instance ID")
+	})
+
+	t.Run("CreateInstance_AttachFailure_DeletesBlock", func(t *testing.T) {
+		t.Setenv(constants.EnvTalisSSHKeyName, mocks.DefaultVultrKeyName)
+		provider, mockClient := newTestVultrProvider()
+		mockClient.AttachBlockFunc = func(_ context.Context, _, _ string) error {
+			return mocks.ErrVultrRateLimit
+		}
+
+		err := provider.CreateInstance(context.Background(), &types.InstanceRequest{
+			InstanceID:  7,
+			ProjectName: "test-project",
+			Region:      "ewr",
+			Size:        "vc2-1c-1gb",
+			Image:       "1743",
+			Volumes:     []types.VolumeConfig{{Name: "data", SizeGB: 40}},
+		})
+		assert.Error(t, err)
+		assert.Len(t, mockClient.DeletedBlocks, 1)
+		assert.Empty(t, mockClient.Blocks())
+	})
+
+	t.Run("DeleteInstance_WithBlocks", func(t *testing.T) {
+		t.Setenv(constants.EnvTalisSSHKeyName, mocks.DefaultVultrKeyName)
+		provider, mockClient := newTestVultrProvider()
+
+		config := &types.InstanceRequest{
+			InstanceID:  7,
+			ProjectName: "test-project",
+			Region:      "ewr",
+			Size:        "vc2-1c-1gb",
+			Image:       "1743",
+			Volumes:     []types.VolumeConfig{{Name: "data", SizeGB: 40}, {Name: "logs", SizeGB: 40}},
+		}
+		require.NoError(t, provider.CreateInstance(context.Background(), config))
+
+		err := provider.DeleteInstance(context.Background(), config.ProviderInstanceID)
+		require.NoError(t, err)
+		assert.Len(t, mockClient.DeletedInstances, 1)
+		assert.ElementsMatch(t, config.VolumeIDs, mockClient.DeletedBlocks)
+	})
+
+	t.Run("DeleteInstance_NotFound", func(t *testing.T) {
+		provider, _ := newTestVultrProvider()
+
+		err := provider.DeleteInstance(context.Background(), 1)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "not found")
+	})
+
+	t.Run("DeleteInstance_OtherDeployment", func(t *testing.T) {
+		t.Setenv(constants.EnvTalisSSHKeyName, mocks.DefaultVultrKeyName)
+		provider, mockClient := newTestVultrProvider()
+
+		t.Setenv(constants.EnvTalisDeploymentID, "staging")
+		config := &types.InstanceRequest{
+			InstanceID: 7, ProjectName: "test-project", Region: "ewr", Size: "vc2-1c-1gb", Image: "1743",
+		}
+		require.NoError(t, provider.CreateInstance(context.Background(), config))
+
+		// The instance of the same Talis instance ID of another deployment is left alone
+		t.Setenv(constants.EnvTalisDeploymentID, "test")
+		_, err := provider.GetInstance(context.Background(), 7)
+		assert.ErrorIs(t, err, types.ErrProviderInstanceNotFound)
+		assert.Error(t, provider.DeleteInstance(context.Background(), 7))
+		assert.Empty(t, mockClient.DeletedInstances)
+
+		instances, err := provider.ListInstances(context.Background())
+		require.NoError(t, err)
+		require.Len(t, instances, 1)
+		assert.Zero(t, instances[0].ProviderInstanceID)
+	})
+
+	t.Run("DeleteInstance_Ambiguous", func(t *testing.T) {
+		provider, mockClient := newTestVultrProvider()
+		for i := 0; i < 2; i++ {
+			mockClient.AddInstance(computeTypes.VultrInstance{Status: "active", Tags: []string{"talis-ref-test-7"}})
+		}
+
+		err := provider.DeleteInstance(context.Background(), 7)
+		assert.ErrorContains(t, err, "2 instances are tagged with talis-ref-test-7")
+		assert.Empty(t, mockClient.DeletedInstances)
+
+		_, err = provider.GetInstance(context.Background(), 7)
+		assert.ErrorContains(t, err, "refusing to act")
+	})
+
+	t.Run("GetInstance_ListInstances", func(t *testing.T) {
+		t.Setenv(constants.EnvTalisSSHKeyName, mocks.DefaultVultrKeyName)
+		provider, _ := newTestVultrProvider()
+
+		config := &types.InstanceRequest{
+			InstanceID: 7, ProjectName: "test-project", Name: "node", Region: "ewr", Size: "vc2-1c-1gb", Image: "1743",
+		}
+		require.NoError(t, provider.CreateInstance(context.Background(), config))
+
+		instance, err := provider.GetInstance(context.Background(), config.ProviderInstanceID)
+		require.NoError(t, err)
+		assert.Equal(t, config.ProviderInstanceID, instance.ProviderInstanceID)
+		assert.Equal(t, types.ProviderInstanceStatusRunning, instance.Status)
+		assert.Equal(t, mocks.DefaultVultrInstanceIP, instance.PublicIP)
+
+		instances, err := provider.ListInstances(context.Background())
+		require.NoError(t, err)
+		require.Len(t, instances, 1)
+		assert.Equal(t, *instance, instances[0])
+
+		_, err = provider.GetInstance(context.Background(), config.ProviderInstanceID+1)
+		assert.ErrorIs(t, err, types.ErrProviderInstanceNotFound)
+	})
+
+	t.Run("ImportInstance", func(t *testing.T) {
+		provider, mockClient := newTestVultrProvider()
+
+		id := mockClient.AddInstance(computeTypes.VultrInstance{
+			Label: "manual", Region: "ewr", Plan: "vc2-1c-1gb", MainIP: mocks.DefaultVultrInstanceIP,
+			Status: "active", PowerStatus: "running", Tags: []string{"testnet"},
+		})
+		block, err := mockClient.CreateBlock(context.Background(), &computeTypes.VultrBlockCreateRequest{Region: "ewr", SizeGB: 40, Label: "data"})
+		require.NoError(t, err)
+		require.NoError(t, mockClient.AttachBlock(context.Background(), block.ID, id))
+
+		instance, err := provider.ImportInstance(context.Background(), id)
+		require.NoError(t, err)
+		assert.Zero(t, instance.ProviderInstanceID)
+		assert.Contains(t, instance.Tags, "testnet")
+		require.Len(t, instance.Volumes, 1)
+		assert.Equal(t, block.ID, instance.Volumes[0].ID)
+		assert.Equal(t, 40, instance.Volumes[0].SizeGB)
+
+		// Once referenced, the instance is addressable by its reference, and importing it again keeps it
+		require.NoError(t, provider.ReferenceInstance(context.Background(), id, 42))
+		found, err := provider.GetInstance(context.Background(), 42)
+		require.NoError(t, err)
+		assert.Equal(t, id, found.NativeID)
+		assert.Contains(t, found.Tags, "testnet")
+		again, err := provider.ImportInstance(context.Background(), id)
+		require.NoError(t, err)
+		assert.Equal(t, 42, again.ProviderInstanceID)
+
+		_, err = provider.ImportInstance(context.Background(), "missing")
+		assert.ErrorIs(t, err, types.ErrProviderInstanceNotFound)
+		assert.ErrorIs(t, provider.ReferenceInstance(context.Background(), "missing", 43), types.ErrProviderInstanceNotFound)
+	})
+
+	t.Run("Catalog", func(t *testing.T) {
+		provider, mockClient := newTestVultrProvider()
+
+		regions, err := provider.ListRegions(context.Background())
+		require.NoError(t, err)
+		require.Len(t, regions, 2)
+		assert.Equal(t, types.ProviderRegion{ID: mocks.DefaultVultrRegion, Name: "New Jersey, US"}, regions[0])
+
+		sizes, err := provider.ListSizes(context.Background())
+		require.NoError(t, err)
+		require.Len(t, sizes, 2)
+		assert.Equal(t, types.ProviderSize{
+			ID: mocks.DefaultVultrPlan, CPU: 1, MemoryMB: 1024, DiskGB: 25, Regions: []string{mocks.DefaultVultrRegion, "fra"},
+		}, sizes[0])
+
+		images, err := provider.ListImages(context.Background())
+		require.NoError(t, err)
+		require.Len(t, images, 2)
+		assert.Equal(t, "1743", images[0].ID)
+		assert.Equal(t, "ubuntu", images[0].Distribution)
+
+		// Marketplace image IDs are accepted, OS IDs have to be listed
+		assert.True(t, provider.MatchesImage("docker"))
+		assert.False(t, provider.MatchesImage("1743"))
+
+		mockClient.SimulateRateLimit()
+		_, err = provider.ListImages(context.Background())
+		assert.Error(t, err)
+	})
+}
+
+func TestVultrAPIClient(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/instances":
+			// Serve two pages to exercise cursor pagination
+			if r.URL.Query().Get("cursor") == "" {
+				assert.Equal(t, "talis-ref-7", r.URL.Query().Get("tag"))
+				_, _ = w.Write([]byte(`{"instances":[{"id":"a"}],"meta":{"links":{"next":"page2"}}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"instances":[{"id":"b"}],"meta":{"links":{"next":""}}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/instances":
+			var req computeTypes.VultrInstanceCreateRequest
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"instance":{"id":"c","label":"` + req.Label + `","status":"pending"}}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/v2/instances/missing":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"Invalid instance-id.","status":404}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewVultrAPIClient("secret", server.URL+"/v2/")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	instances, err := client.ListInstances(ctx, "talis-ref-7")
+	require.NoError(t, err)
+	require.Len(t, instances, 2)
+	assert.Equal(t, "b", instances[1].ID)
+	assert.Equal(t, "Bearer secret", gotAuth)
+
+	instance, err := client.CreateInstance(ctx, &computeTypes.VultrInstanceCreateRequest{Label: "node"})
+	require.NoError(t, err)
+	assert.Equal(t, "c", instance.ID)
+	assert.Equal(t, "node", instance.Label)
+
+	err = client.DeleteInstance(ctx, "missing")
+	require.Error(t, err)
+	assert.True(t, computeTypes.IsVultrNotFound(err))
+	assert.Contains(t, err.Error(), "Invalid instance-id.")
+
+	assert.NoError(t, client.AttachBlock(ctx, "block", "c"))
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/celestiaorg/talis/internal/constants"
 	"github.com/celestiaorg/talis/internal/logger"
@@ -28,7 +30,7 @@ func NewXimeraProvider() (*XimeraProvider, error) {
 // ValidateCredentials validates the Ximera credentials
 func (p *XimeraProvider) ValidateCredentials() error {
 	// Try to list servers as a credential check
-	_, err := p.client.ListServers()
+	_, err := p.client.ListServers(context.Background())
 	if err != nil {
 		return fmt.Errorf("ximera credential validation failed: %w", err)
 	}
@@ -52,7 +54,7 @@ func (p *XimeraProvider) ConfigureProvider(_ interface{}) error {
 }
 
 // CreateInstance creates a new instance using Ximera
-func (p *XimeraProvider) CreateInstance(_ context.Context, req *types.InstanceRequest) error {
+func (p *XimeraProvider) CreateInstance(ctx context.Context, req *types.InstanceRequest) error {
 	machineName := fmt.Sprintf("%s-%s", req.ProjectName, generateRandomSuffix())
 
 	if len(req.Volumes) == 0 {
@@ -82,6 +84,7 @@ func (p *XimeraProvider) CreateInstance(_ context.Context, req *types.InstanceRe
 
 	// Map InstanceRequest to ximera's CreateServer
 	resp, err := p.client.CreateServer(
+		ctx,
 		machineName,
 		packageID,
 		req.Volumes[0].SizeGB,
@@ -92,6 +95,8 @@ func (p *XimeraProvider) CreateInstance(_ context.Context, req *types.InstanceRe
 	if err != nil {
 		return fmt.Errorf("failed to create ximera server: %w", err)
 	}
+	// The server exists from now on, so that it is deleted if a later step fails
+	req.SetProviderInstanceID(resp.Data.ID)
 
 	// Get SSH key name from environment variable
 	sshKeyName := os.Getenv(constants.EnvTalisSSHKeyName)
@@ -102,7 +107,7 @@ func (p *XimeraProvider) CreateInstance(_ context.Context, req *types.InstanceRe
 	}
 
 	// Build the server after creation
-	buildResp, err := p.client.BuildServer(resp.Data.ID, req.Image, machineName, sshKeyName)
+	buildResp, err := p.client.BuildServer(ctx, resp.Data.ID, req.Image, machineName, sshKeyName)
 	if err != nil {
 		return fmt.Errorf("failed to build ximera server: %w", err)
 	}
@@ -111,13 +116,13 @@ func (p *XimeraProvider) CreateInstance(_ context.Context, req *types.InstanceRe
 	}
 
 	// Wait for the server to be fully created (polling with timeout)
-	err = p.client.WaitForServerCreation(buildResp.Data.ID, 120) // 120s timeout
+	err = p.client.WaitForServerCreation(ctx, buildResp.Data.ID, 120) // 120s timeout
 	if err != nil {
 		return fmt.Errorf("failed to wait for ximera server to be fully created: %w", err)
 	}
 
 	// Get the server details (extract IP here)
-	server, err := p.client.GetServer(buildResp.Data.ID)
+	server, err := p.client.GetServer(ctx, buildResp.Data.ID)
 	if err != nil {
 		return fmt.Errorf("failed to get ximera server details: %w", err)
 	}
@@ -130,6 +135,104 @@ func (p *XimeraProvider) CreateInstance(_ context.Context, req *types.InstanceRe
 }
 
 // DeleteInstance deletes an instance using Ximera
-func (p *XimeraProvider) DeleteInstance(_ context.Context, providerInstanceID int) error {
-	return p.client.DeleteServer(providerInstanceID)
+func (p *XimeraProvider) DeleteInstance(ctx context.Context, providerInstanceID int) error {
+	return p.client.DeleteServer(ctx, providerInstanceID)
+}
+
+// GetInstance returns the current state of a Ximera server
+func (p *XimeraProvider) GetInstance(ctx context.Context, providerInstanceID int) (*types.ProviderInstance, error) {
+	server, err := p.client.GetServer(ctx, providerInstanceID)
+	if err != nil {
+		if strings.Contains(err.Error(), "404") {
+			return nil, fmt.Errorf("server %d: %w", providerInstanceID, types.ErrProviderInstanceNotFound)
+		}
+		return nil, fmt.Errorf("failed to get ximera server details: %w", err)
+	}
+
+	return &types.ProviderInstance{
+		ProviderInstanceID: server.Data.ID,
+		NativeID:           strconv.Itoa(server.Data.ID),
+		Name:               server.Data.Name,
+		Status:             ximeraInstanceStatus(server.Data.State),
+		PublicIP:           server.Data.PublicIP,
+	}, nil
+}
+
+// ListInstances returns all servers of the Ximera user.
+// The server list does not contain addresses, PublicIP is left empty.
+func (p *XimeraProvider) ListInstances(ctx context.Context) ([]types.ProviderInstance, error) {
+	servers, err := p.client.ListServers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ximera servers: %w", err)
+	}
+
+	instances := make([]types.ProviderInstance, 0, len(servers.Data))
+	for _, server := range servers.Data {
+		instances = append(instances, types.ProviderInstance{
+			ProviderInstanceID: server.ID,
+			NativeID:           strconv.Itoa(server.ID),
+			Name:               server.Name,
+			Status:             ximeraInstanceStatus(server.State),
+		})
+	}
+	return instances, nil
+}
+
+// ImportInstance returns an existing Ximera server.
+// Server IDs are used as provider instance IDs, so the server is not modified.
+// Ximera servers have a single built-in disk and no separate volumes.
+func (p *XimeraProvider) ImportInstance(ctx context.Context, nativeID string) (*types.ProviderInstance, error) {
+	serverID, err := strconv.Atoi(nativeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ximera server ID %q: %w", nativeID, err)
+	}
+	return p.GetInstance(ctx, serverID)
+}
+
+// ListRegions returns no regions, Ximera servers are placed by their hypervisor group and
+// the requested region is not used
+func (p *XimeraProvider) ListRegions(_ context.Context) ([]types.ProviderRegion, error) {
+	return nil, nil
+}
+
+// ListSizes returns no sizes, Ximera servers are sized by the requested memory and CPU
+func (p *XimeraProvider) ListSizes(_ context.Context) ([]types.ProviderSize, error) {
+	return nil, nil
+}
+
+// ListImages returns the OS templates of the configured package. The template ID is the osID
+// to use as image.
+func (p *XimeraProvider) ListImages(ctx context.Context) ([]types.ProviderImage, error) {
+	templates, err := p.client.ListTemplates(ctx, p.client.config.PackageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ximera templates: %w", err)
+	}
+
+	images := []types.ProviderImage{}
+	for _, group := range templates.Data {
+		for _, template := range group.Templates {
+			images = append(images, types.ProviderImage{
+				ID:           strconv.Itoa(template.ID),
+				Name:         strings.TrimSpace(template.Name + " " + template.Version + " " + template.Variant),
+				Distribution: group.Name,
+			})
+		}
+	}
+	return images, nil
+}
+
+// ximeraInstanceStatus maps the state of a Ximera server to a provider instance status
+func ximeraInstanceStatus(state string) types.ProviderInstanceStatus {
+	switch state {
+	case "complete":
+		return types.ProviderInstanceStatusRunning
+	case "", "queued", "building":
+		return types.ProviderInstanceStatusPending
+	case "suspended":
+		return types.ProviderInstanceStatusStopped
+	case "deleting", "deleted":
+		return types.ProviderInstanceStatusTerminated
+	default:
+		return types.ProviderInstanceStatusUnknown
+	}
 }
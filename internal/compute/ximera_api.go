@@ -2,6 +2,7 @@ package compute
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -30,7 +31,7 @@ func NewXimeraAPIClient(config *computeTypes.XimeraConfiguration) *XimeraAPIClie
 }
 
 // MakeRequest makes a request to the API
-func (c *XimeraAPIClient) MakeRequest(method, endpoint string, body interface{}) ([]byte, error) {
+func (c *XimeraAPIClient) MakeRequest(ctx context.Context, method, endpoint string, body interface{}) ([]byte, error) {
 	var reqBody io.Reader
 	if body != nil {
 		jsonBody, err := json.Marshal(body)
@@ -41,7 +42,7 @@ func (c *XimeraAPIClient) MakeRequest(method, endpoint string, body interface{})
 	}
 
 	url := fmt.Sprintf("%s%s", c.config.APIURL, endpoint)
-	req, err := http.NewRequest(method, url, reqBody)
+	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("error creating request: %w", err)
 	}
@@ -76,8 +77,8 @@ func (c *XimeraAPIClient) MakeRequest(method, endpoint string, body interface{})
 }
 
 // ListServers lists all servers
-func (c *XimeraAPIClient) ListServers() (*computeTypes.XimeraServersListResponse, error) {
-	respBody, err := c.MakeRequest("GET", "/servers", nil)
+func (c *XimeraAPIClient) ListServers(ctx context.Context) (*computeTypes.XimeraServersListResponse, error) {
+	respBody, err := c.MakeRequest(ctx, "GET", "/servers", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -94,8 +95,8 @@ func (c *XimeraAPIClient) ListServers() (*computeTypes.XimeraServersListResponse
 // ServerExists checks if a server with the given name exists
 // TODO: Optimize this method if the API supports filtering servers by name
 // instead of retrieving all servers and filtering client-side
-func (c *XimeraAPIClient) ServerExists(name string) (bool, int, error) {
-	servers, err := c.ListServers()
+func (c *XimeraAPIClient) ServerExists(ctx context.Context, name string) (bool, int, error) {
+	servers, err := c.ListServers(ctx)
 	if err != nil {
 		return false, 0, err
 	}
@@ -110,7 +111,7 @@ func (c *XimeraAPIClient) ServerExists(name string) (bool, int, error) {
 }
 
 // CreateServer creates a new server
-func (c *XimeraAPIClient) CreateServer(name string, packageID int, storage, traffic, memory, cpuCores int) (*computeTypes.XimeraServerResponse, error) {
+func (c *XimeraAPIClient) CreateServer(ctx context.Context, name string, packageID int, storage, traffic, memory, cpuCores int) (*computeTypes.XimeraServerResponse, error) {
 	request := computeTypes.XimeraServerCreateRequest{
 		PackageID:    packageID,
 		UserID:       c.config.UserID,
@@ -133,7 +134,7 @@ func (c *XimeraAPIClient) CreateServer(name string, packageID int, storage, traf
 		request.CPUCores = cpuCores
 	}
 
-	respBody, err := c.MakeRequest("POST", "/servers", request)
+	respBody, err := c.MakeRequest(ctx, "POST", "/servers", request)
 	if err != nil {
 		return nil, err
 	}
@@ -148,9 +149,9 @@ func (c *XimeraAPIClient) CreateServer(name string, packageID int, storage, traf
 }
 
 // GetServer gets a server by ID
-func (c *XimeraAPIClient) GetServer(id int) (*computeTypes.XimeraServerResponse, error) {
+func (c *XimeraAPIClient) GetServer(ctx context.Context, id int) (*computeTypes.XimeraServerResponse, error) {
 	endpoint := fmt.Sprintf("/servers/%d", id)
-	respBody, err := c.MakeRequest("GET", endpoint, nil)
+	respBody, err := c.MakeRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -173,7 +174,7 @@ func (c *XimeraAPIClient) GetServer(id int) (*computeTypes.XimeraServerResponse,
 }
 
 // BuildServer builds a server with the given ID
-func (c *XimeraAPIClient) BuildServer(id int, osID, name, sshKey string) (*computeTypes.XimeraServerResponse, error) {
+func (c *XimeraAPIClient) BuildServer(ctx context.Context, id int, osID, name, sshKey string) (*computeTypes.XimeraServerResponse, error) {
 	// Validate osID and sshKey are non-empty and numeric
 	if osID == "" {
 		return nil, fmt.Errorf("osID cannot be empty")
@@ -205,7 +206,7 @@ func (c *XimeraAPIClient) BuildServer(id int, osID, name, sshKey string) (*compu
 	}
 
 	endpoint := fmt.Sprintf("/servers/%d/build", id)
-	respBody, err := c.MakeRequest("POST", endpoint, request)
+	respBody, err := c.MakeRequest(ctx, "POST", endpoint, request)
 	if err != nil {
 		return nil, err
 	}
@@ -230,9 +231,9 @@ func isNumeric(s string) bool {
 }
 
 // ListTemplates lists available OS templates for a package
-func (c *XimeraAPIClient) ListTemplates(packageID int) (*computeTypes.XimeraTemplatesResponse, error) {
+func (c *XimeraAPIClient) ListTemplates(ctx context.Context, packageID int) (*computeTypes.XimeraTemplatesResponse, error) {
 	endpoint := fmt.Sprintf("/media/templates/fromServerPackageSpec/%d", packageID)
-	respBody, err := c.MakeRequest("GET", endpoint, nil)
+	respBody, err := c.MakeRequest(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -247,14 +248,15 @@ func (c *XimeraAPIClient) ListTemplates(packageID int) (*computeTypes.XimeraTemp
 }
 
 // DeleteServer deletes a server with the given ID
-func (c *XimeraAPIClient) DeleteServer(id int) error {
+func (c *XimeraAPIClient) DeleteServer(ctx context.Context, id int) error {
 	endpoint := fmt.Sprintf("/servers/%d", id)
-	_, err := c.MakeRequest("DELETE", endpoint, nil)
+	_, err := c.MakeRequest(ctx, "DELETE", endpoint, nil)
 	return err
 }
 
-// WaitForServerCreation waits for a server to be fully created using a time ticker
-func (c *XimeraAPIClient) WaitForServerCreation(serverID int, timeoutSeconds int) error {
+// WaitForServerCreation waits for a server to be fully created using a time ticker.
+// It stops early when ctx is cancelled.
+func (c *XimeraAPIClient) WaitForServerCreation(ctx context.Context, serverID int, timeoutSeconds int) error {
 	fmt.Printf("Waiting for server creation to complete...")
 
 	interval := 5 * time.Second
@@ -266,9 +268,12 @@ func (c *XimeraAPIClient) WaitForServerCreation(serverID int, timeoutSeconds int
 
 	for {
 		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for server %d: %w", serverID, context.Cause(ctx))
+
 		case <-ticker.C:
 			// Ticker has ticked, check server status
-			server, err := c.GetServer(serverID)
+			server, err := c.GetServer(ctx, serverID)
 			if err != nil {
 				return fmt.Errorf("error getting server details: %w", err)
 			}
@@ -281,7 +286,7 @@ func (c *XimeraAPIClient) WaitForServerCreation(serverID int, timeoutSeconds int
 		case <-timeoutChan:
 			// Timeout reached
 			// Attempt to get the last known state before returning timeout error
-			server, err := c.GetServer(serverID)
+			server, err := c.GetServer(ctx, serverID)
 			lastState := "unknown"
 			if err == nil && server != nil {
 				lastState = server.Data.State
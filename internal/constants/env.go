@@ -8,4 +8,8 @@ const (
 
 	// EnvTalisSSHKeyName is the environment variable containing the name of the SSH key registered with cloud providers
 	EnvTalisSSHKeyName = "TALIS_SSH_KEY_NAME"
+
+	// EnvTalisDeploymentID is the environment variable containing the ID of the Talis deployment,
+	// which keeps the instances of deployments sharing a cloud provider account apart
+	EnvTalisDeploymentID = "TALIS_DEPLOYMENT_ID"
 )
@@ -43,12 +43,7 @@ type Options struct {
 // New creates a new database connection with the given options
 func New(opts Options) (*gorm.DB, error) {
 	opts = setDefaults(opts)
-	sslMode := "disable"
-	if opts.SSLEnabled != nil && *opts.SSLEnabled {
-		sslMode = "enable"
-	}
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s",
-		opts.Host, opts.User, opts.Password, opts.DBName, opts.Port, sslMode)
+	dsn := opts.dsn()
 
 	// Configure custom logger to ignore record not found errors
 	newLogger := logger.New(
@@ -81,6 +76,17 @@ func IsDuplicateKeyError(err error) bool {
 	return errors.Is(postgres.Dialector{}.Translate(err), gorm.ErrDuplicatedKey)
 }
 
+// dsn returns the connection string for the options, with defaults applied
+func (opts Options) dsn() string {
+	opts = setDefaults(opts)
+	sslMode := "disable"
+	if opts.SSLEnabled != nil && *opts.SSLEnabled {
+		sslMode = "enable"
+	}
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s",
+		opts.Host, opts.User, opts.Password, opts.DBName, opts.Port, sslMode)
+}
+
 func setDefaults(opts Options) Options {
 	if opts.Host == "" {
 		opts.Host = DefaultHost
@@ -108,11 +114,42 @@ func setDefaults(opts Options) Options {
 }
 
 func migrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	if err := db.AutoMigrate(
 		&models.Instance{},
 		&models.Project{},
 		&models.Task{},
 		&models.User{},
 		&models.SSHKey{},
-	)
+		&models.DriftEvent{},
+		&models.Worker{},
+		&models.TaskEvent{},
+		&models.WebhookDelivery{},
+		&models.WebhookAttempt{},
+		&models.Playbook{},
+		&models.InstancePayload{},
+	); err != nil {
+		return err
+	}
+	if err := backfillTaskProviders(db); err != nil {
+		return err
+	}
+	return createTaskNotifyTrigger(db)
+}
+
+// backfillTaskProvidersSQL records the provider of the tasks queued before tasks had one, taken
+// from the instance request of create tasks or from the instance of the other tasks
+const backfillTaskProvidersSQL = `
+UPDATE tasks SET provider_id = COALESCE(
+	NULLIF(tasks.payload->>'provider', ''),
+	(SELECT instances.provider_id FROM instances WHERE instances.id = tasks.instance_id)
+)
+WHERE tasks.provider_id IS NULL OR tasks.provider_id = ''`
+
+// backfillTaskProviders sets the provider of the tasks that have none, so that they are capped
+// by the provider concurrency limits like the others
+func backfillTaskProviders(db *gorm.DB) error {
+	if err := db.Exec(backfillTaskProvidersSQL).Error; err != nil {
+		return fmt.Errorf("failed to backfill task providers: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// ReconcilerLockKey is the advisory lock key held by the process that runs the reconciler
+const ReconcilerLockKey int64 = 0x74616c6973_01 // "talis" followed by the lock number
+
+// AdvisoryLock is a Postgres session advisory lock. The lock is held on a dedicated connection,
+// so it is released by Postgres when the process holding it dies.
+type AdvisoryLock struct {
+	db  *sql.DB
+	key int64
+
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+// NewAdvisoryLock creates an advisory lock with the given key, it is not acquired yet
+func NewAdvisoryLock(db *gorm.DB, key int64) (*AdvisoryLock, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection pool: %w", err)
+	}
+	return &AdvisoryLock{db: sqlDB, key: key}, nil
+}
+
+// TryAcquire reports whether the lock is held by this process, acquiring it when it is free.
+// A lock whose connection was lost is acquired again.
+func (l *AdvisoryLock) TryAcquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		if err := l.conn.PingContext(ctx); err == nil {
+			return true, nil
+		}
+		// Postgres released the lock with the connection
+		l.discard()
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get a connection for advisory lock %d: %w", l.key, err)
+	}
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return false, fmt.Errorf("failed to acquire advisory lock %d: %w", l.key, err)
+	}
+	if !acquired {
+		_ = conn.Close()
+		return false, nil
+	}
+	l.conn = conn
+	return true, nil
+}
+
+// Release releases the lock if it is held
+func (l *AdvisoryLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+	if _, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key); err != nil {
+		// Closing the connection releases the lock as well
+		l.discard()
+		return fmt.Errorf("failed to release advisory lock %d: %w", l.key, err)
+	}
+	err := l.conn.Close()
+	l.conn = nil
+	return err
+}
+
+// discard closes the connection of the lock instead of returning it to the pool, which
+// releases the lock if Postgres still holds it
+func (l *AdvisoryLock) discard() {
+	_ = l.conn.Raw(func(any) error { return driver.ErrBadConn })
+	_ = l.conn.Close()
+	l.conn = nil
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Field names for drift event model
+const (
+	// DriftEventCreatedAtField is the field name for drift event created at
+	DriftEventCreatedAtField = "created_at"
+)
+
+// DriftEventType describes how an instance diverged from its provider state
+type DriftEventType string
+
+// Drift event type constants
+const (
+	// DriftEventMissing indicates the instance no longer exists on its provider
+	DriftEventMissing DriftEventType = "missing"
+	// DriftEventStatusChanged indicates the instance was stopped or started outside of Talis
+	DriftEventStatusChanged DriftEventType = "status_changed"
+	// DriftEventIPChanged indicates the public IP of the instance changed
+	DriftEventIPChanged DriftEventType = "ip_changed"
+)
+
+// DriftEvent records a difference between an instance and its provider state found by the reconciler
+type DriftEvent struct {
+	gorm.Model
+	OwnerID            uint           `json:"-" gorm:"not null; index"`
+	InstanceID         uint           `json:"instance_id" gorm:"not null; index"`
+	ProviderID         ProviderID     `json:"provider_id" gorm:"not null"`
+	ProviderInstanceID int            `json:"provider_instance_id" gorm:"not null"`
+	Type               DriftEventType `json:"type" gorm:"type:varchar(32); not null; index"`
+	OldValue           string         `json:"old_value" gorm:"type:text"` // Value stored by Talis before the event
+	NewValue           string         `json:"new_value" gorm:"type:text"` // Value reported by the provider
+	CreatedAt          time.Time      `json:"created_at" gorm:"index"`
+}
+
+// MarshalJSON implements the json.Marshaler interface for DriftEvent
+func (e DriftEvent) MarshalJSON() ([]byte, error) {
+	type Alias DriftEvent // Create an alias to avoid infinite recursion
+	return json.Marshal(Alias(e))
+}
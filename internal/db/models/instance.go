@@ -37,6 +37,10 @@ const (
 	InstanceStatusReady
 	// InstanceStatusTerminated indicates the instance is terminated
 	InstanceStatusTerminated
+	// InstanceStatusStopped indicates the instance was found powered off on its provider
+	InstanceStatusStopped
+	// InstanceStatusFailed indicates the creation or termination of the instance did not complete in time
+	InstanceStatusFailed
 )
 
 // PayloadStatus represents the state of a payload operation on an instance
@@ -88,6 +92,8 @@ func (s InstanceStatus) String() string {
 		"provisioning",
 		"ready",
 		"terminated",
+		"stopped",
+		"failed",
 	}[s]
 }
 
@@ -113,6 +119,8 @@ func ParseInstanceStatus(str string) (InstanceStatus, error) {
 		"provisioning",
 		"ready",
 		"terminated",
+		"stopped",
+		"failed",
 	} {
 		if status == str {
 			return InstanceStatus(i), nil
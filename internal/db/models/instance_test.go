@@ -74,6 +74,24 @@ func TestInstanceStatus(t *testing.T) {
 			validForJSON:  true,
 			statusIndex:   5,
 		},
+		{
+			name:          "Stopped status",
+			status:        InstanceStatusStopped,
+			stringValue:   "stopped",
+			jsonValue:     `"stopped"`,
+			validForParse: true,
+			validForJSON:  true,
+			statusIndex:   6,
+		},
+		{
+			name:          "Failed status",
+			status:        InstanceStatusFailed,
+			stringValue:   "failed",
+			jsonValue:     `"failed"`,
+			validForParse: true,
+			validForJSON:  true,
+			statusIndex:   7,
+		},
 		{
 			name:          "Invalid status",
 			stringValue:   "invalid_status",
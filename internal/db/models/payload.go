@@ -0,0 +1,46 @@
+package models
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// PayloadInstanceIDColumn is the column of the instance of a payload output
+const PayloadInstanceIDColumn = "instance_id"
+
+// MaxPayloadOutputSize is how much of the standard output and of the standard error of a payload
+// script is kept, the end of longer output is kept
+const MaxPayloadOutputSize = 64 * 1024
+
+// InstancePayload is the outcome of the payload script of an instance. The status of the payload
+// is stored with the instance, the output is stored once the script ran.
+type InstancePayload struct {
+	gorm.Model
+	InstanceID uint `json:"instance_id" gorm:"not null;uniqueIndex"`
+	OwnerID    uint `json:"owner_id" gorm:"not null;index"`
+	// Status is the status of the payload of the instance, it is not stored with the output
+	Status PayloadStatus `json:"status" gorm:"-"`
+	// ExitCode is the exit code of the script, nil if it did not exit
+	ExitCode *int `json:"exit_code"`
+	// Stdout and Stderr hold the end of the output of the script, up to MaxPayloadOutputSize each
+	Stdout string `json:"stdout" gorm:"type:text"`
+	Stderr string `json:"stderr" gorm:"type:text"`
+}
+
+// BeforeSave bounds the output of the script, keeping its end
+func (p *InstancePayload) BeforeSave(_ *gorm.DB) error {
+	p.Stdout = TruncatePayloadOutput(p.Stdout)
+	p.Stderr = TruncatePayloadOutput(p.Stderr)
+	return nil
+}
+
+// TruncatePayloadOutput keeps the last MaxPayloadOutputSize bytes of the output of a payload
+// script. Invalid UTF-8, such as a character cut in half, is replaced so the output can be
+// stored as text.
+func TruncatePayloadOutput(output string) string {
+	if len(output) > MaxPayloadOutputSize {
+		output = output[len(output)-MaxPayloadOutputSize:]
+	}
+	return strings.ToValidUTF8(output, "�")
+}
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Playbook field name constants for database queries
+const (
+	PlaybookNameColumn = "name"
+)
+
+const (
+	// PlaybookEntrypoint is the file of a playbook that is run, roles are looked up in the roles
+	// directory next to it
+	PlaybookEntrypoint = "main.yml"
+	// MaxPlaybookSize is the maximum size of the files of an uploaded playbook
+	MaxPlaybookSize = 1024 * 1024 // 1MB
+)
+
+// reservedPlaybookDirs are the directories of an uploaded playbook that Ansible loads code or
+// connection settings from, which would let the playbook run code on the server
+var reservedPlaybookDirs = map[string]bool{
+	"library":      true,
+	"module_utils": true,
+	"plugins":      true,
+	"collections":  true,
+	"group_vars":   true,
+	"host_vars":    true,
+}
+
+// reservedPlaybookVarPrefix is the prefix of the variables that configure Ansible itself, such
+// as ansible_connection, which extra variables are not allowed to set
+const reservedPlaybookVarPrefix = "ansible_"
+
+// IsReservedPlaybookVar reports whether name configures Ansible rather than the playbook
+func IsReservedPlaybookVar(name string) bool {
+	return strings.HasPrefix(strings.ToLower(name), reservedPlaybookVarPrefix)
+}
+
+// playbookNameRegex matches the names of playbooks, which are also directory names
+var playbookNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// ValidatePlaybookName checks that name can name a playbook
+func ValidatePlaybookName(name string) error {
+	if !playbookNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid playbook name %q: must be 1 to 64 lowercase letters, digits, '-' or '_', starting with a letter or digit", name)
+	}
+	return nil
+}
+
+// PlaybookFiles holds the files of an uploaded playbook by their slash separated path relative
+// to the playbook directory
+type PlaybookFiles map[string]string
+
+// Value implements the driver.Valuer interface
+func (f PlaybookFiles) Value() (driver.Value, error) {
+	if f == nil {
+		return nil, nil
+	}
+	return json.Marshal(f)
+}
+
+// Scan implements the sql.Scanner interface
+func (f *PlaybookFiles) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case nil:
+		*f = nil
+		return nil
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	}
+	return json.Unmarshal(bytes, f)
+}
+
+// Validate checks that the files have an entrypoint, fit in MaxPlaybookSize and that their
+// paths stay inside the playbook directory. Modules, plugins, inventory variables and Ansible
+// configuration are refused, they are loaded by Ansible on the server.
+func (f PlaybookFiles) Validate() error {
+	if _, ok := f[PlaybookEntrypoint]; !ok {
+		return fmt.Errorf("playbook files must include %s", PlaybookEntrypoint)
+	}
+	size := 0
+	for name, content := range f {
+		if name == "." || !fs.ValidPath(name) || strings.Contains(name, "\\") {
+			return fmt.Errorf("invalid playbook file path %q: must be a clean relative path", name)
+		}
+		if err := validatePlaybookFilePath(name); err != nil {
+			return err
+		}
+		size += len(content)
+	}
+	if size > MaxPlaybookSize {
+		return fmt.Errorf("playbook files exceed the limit of %d bytes", MaxPlaybookSize)
+	}
+	return nil
+}
+
+// validatePlaybookFilePath refuses the paths of files that Ansible runs or reads its
+// configuration from on the server
+func validatePlaybookFilePath(name string) error {
+	parts := strings.Split(name, "/")
+	if strings.EqualFold(parts[len(parts)-1], "ansible.cfg") {
+		return fmt.Errorf("invalid playbook file path %q: ansible.cfg is not allowed", name)
+	}
+	for _, dir := range parts[:len(parts)-1] {
+		dir = strings.ToLower(dir)
+		if reservedPlaybookDirs[dir] || strings.HasSuffix(dir, "_plugins") {
+			return fmt.Errorf("invalid playbook file path %q: %s directories are not allowed", name, dir)
+		}
+	}
+	return nil
+}
+
+// Playbook is an Ansible playbook, with its roles, that users uploaded to provision their
+// instances with. Playbooks are kept in the database so that every worker can run them.
+type Playbook struct {
+	gorm.Model
+	Name        string        `json:"name" gorm:"index:idx_playbook_owner_name,unique"`              // Name instance requests reference the playbook by
+	OwnerID     uint          `json:"owner_id" gorm:"index:idx_playbook_owner_name,unique;not null"` // User the playbook belongs to
+	Description string        `json:"description,omitempty" gorm:"type:text"`                        // Optional description of the playbook
+	Files       PlaybookFiles `json:"files,omitempty" gorm:"type:jsonb"`                             // Files of the playbook, including main.yml and its roles
+	// Registered is set on the playbooks registered on the server, which have no files
+	Registered bool `json:"registered,omitempty" gorm:"-"`
+}
+
+// Validate checks if the Playbook model is valid before saving.
+func (p *Playbook) Validate() error {
+	if err := ValidatePlaybookName(p.Name); err != nil {
+		return err
+	}
+	return p.Files.Validate()
+}
+
+// BeforeSave GORM hook to run validation.
+func (p *Playbook) BeforeSave(_ *gorm.DB) error {
+	return p.Validate()
+}
+
+// BeforeCreate GORM hook to run validation.
+func (p *Playbook) BeforeCreate(_ *gorm.DB) error {
+	return p.Validate()
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaybookFiles_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   PlaybookFiles
+		wantErr string
+	}{
+		{
+			name:  "playbook with roles",
+			files: PlaybookFiles{"main.yml": "", "roles/node/tasks/main.yml": "", "roles/node/templates/config.toml.j2": ""},
+		},
+		{
+			name:    "missing entrypoint",
+			files:   PlaybookFiles{"site.yml": ""},
+			wantErr: "playbook files must include main.yml",
+		},
+		{
+			name:    "path outside of the playbook",
+			files:   PlaybookFiles{"main.yml": "", "../outside.yml": ""},
+			wantErr: "must be a clean relative path",
+		},
+		{
+			name:    "ansible.cfg",
+			files:   PlaybookFiles{"main.yml": "", "ansible.cfg": ""},
+			wantErr: "ansible.cfg is not allowed",
+		},
+		{
+			name:    "modules of a role",
+			files:   PlaybookFiles{"main.yml": "", "roles/node/library/shell.py": ""},
+			wantErr: "library directories are not allowed",
+		},
+		{
+			name:    "module utils",
+			files:   PlaybookFiles{"main.yml": "", "module_utils/helpers.py": ""},
+			wantErr: "module_utils directories are not allowed",
+		},
+		{
+			name:    "plugins",
+			files:   PlaybookFiles{"main.yml": "", "Lookup_Plugins/pipe.py": ""},
+			wantErr: "lookup_plugins directories are not allowed",
+		},
+		{
+			name:    "collections",
+			files:   PlaybookFiles{"main.yml": "", "collections/ansible_collections/x/y/galaxy.yml": ""},
+			wantErr: "collections directories are not allowed",
+		},
+		{
+			name:    "inventory variables",
+			files:   PlaybookFiles{"main.yml": "", "group_vars/all.yml": "ansible_connection: local\n"},
+			wantErr: "group_vars directories are not allowed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.files.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsReservedPlaybookVar(t *testing.T) {
+	assert.True(t, IsReservedPlaybookVar("ansible_connection"))
+	assert.True(t, IsReservedPlaybookVar("ANSIBLE_python_interpreter"))
+	assert.False(t, IsReservedPlaybookVar("chain_id"))
+	assert.False(t, IsReservedPlaybookVar("my_ansible_var"))
+}
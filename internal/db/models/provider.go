@@ -27,6 +27,8 @@ const (
 	ProviderOVH ProviderID = "ovh"
 	// ProviderXimera represents Ximera provider
 	ProviderXimera ProviderID = "ximera"
+	// ProviderLocal represents local containers acting as instances
+	ProviderLocal ProviderID = "local"
 
 	// Mock Providers
 	// ProviderDOMock1 represents DigitalOcean provider mock 1
@@ -73,7 +75,7 @@ func (p ProviderID) IsValid() bool {
 	switch p {
 	case ProviderAWS, ProviderGCP, ProviderAzure, ProviderDO,
 		ProviderScaleway, ProviderVultr, ProviderLinode, ProviderHetzner, ProviderOVH,
-		ProviderXimera:
+		ProviderXimera, ProviderLocal:
 		return true
 	case ProviderDOMock1, ProviderDOMock2, ProviderMock3: // mocked providers
 		return true
@@ -81,3 +83,24 @@ func (p ProviderID) IsValid() bool {
 		return false
 	}
 }
+
+// ProvisionerID identifies how instances are provisioned
+type ProvisionerID string
+
+// Provisioner constants define the supported provisioners
+const (
+	// ProvisionerAnsible provisions instances by running the Ansible playbook, the default
+	ProvisionerAnsible ProvisionerID = "ansible"
+	// ProvisionerSSH provisions instances over SSH from Talis itself, without Ansible
+	ProvisionerSSH ProvisionerID = "ssh"
+)
+
+// IsValid checks if the provisioner ID is a supported provisioner. An empty ID selects the default.
+func (p ProvisionerID) IsValid() bool {
+	switch p {
+	case "", ProvisionerAnsible, ProvisionerSSH:
+		return true
+	default:
+		return false
+	}
+}
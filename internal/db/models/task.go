@@ -1,10 +1,8 @@
 package models
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"time"
 
 	"gorm.io/gorm"
@@ -16,8 +14,16 @@ const (
 	TaskStatusField = "status"
 	// TaskIDField is the field name for task ID
 	TaskIDField = "id"
+	// TaskOwnerIDField is the field name for task owner ID
+	TaskOwnerIDField = "owner_id"
+	// TaskProjectIDField is the field name for task project ID
+	TaskProjectIDField = "project_id"
+	// TaskProviderIDField is the field name for the provider of the instance a task acts on
+	TaskProviderIDField = "provider_id"
 	// TaskCreatedAtField is the field name for task created at
 	TaskCreatedAtField = "created_at"
+	// TaskUpdatedAtField is the field name for task updated at
+	TaskUpdatedAtField = "updated_at"
 	// TaskLockedAtField is the field name for task locked at
 	TaskLockedAtField = "locked_at"
 	// TaskLockExpiryField is the field name for task lock expiry
@@ -30,11 +36,16 @@ const (
 	TaskErrorField = "error"
 	// TaskPriorityField is the field name for task priority
 	TaskPriorityField = "priority"
-
-	// WebhookTimeoutSeconds is the timeout for webhook requests in seconds
-	WebhookTimeout = 10 * time.Second
-	// WebhookContentType is the content type for webhook requests
-	WebhookContentType = "application/json"
+	// TaskNextRunAtField is the field name for the time a task is retried at
+	TaskNextRunAtField = "next_run_at"
+	// TaskPayloadField is the field name for task payload
+	TaskPayloadField = "payload"
+	// TaskCheckpointField is the field name for the last step of a task completed by a worker
+	TaskCheckpointField = "checkpoint"
+	// TaskResultField is the field name for task result
+	TaskResultField = "result"
+	// TaskWorkerIDField is the field name for the ID of the worker holding the task lock
+	TaskWorkerIDField = "worker_id"
 
 	// TaskLockTimeout is the duration after which a task lock is considered expired
 	TaskLockTimeout = 5 * time.Minute
@@ -57,6 +68,8 @@ const (
 	TaskStatusFailed TaskStatus = "failed"
 	// TaskStatusTerminated indicates the task was manually aborted
 	TaskStatusTerminated TaskStatus = "terminated"
+	// TaskStatusDead indicates the task used up its retries and waits for an admin to requeue it
+	TaskStatusDead TaskStatus = "dead"
 )
 
 // TaskAction represents the possible actions a task can perform.
@@ -94,22 +107,43 @@ func (p TaskPriority) String() string {
 // Task represents an asynchronous operation that can be tracked
 type Task struct {
 	gorm.Model
-	ProjectID   uint            `json:"project_id" gorm:"not null; index"`
-	OwnerID     uint            `json:"-" gorm:"not null; index"`
-	InstanceID  uint            `json:"instance_id,omitempty" gorm:"index"` // Link to the specific instance, if applicable
-	Action      TaskAction      `json:"action" gorm:"type:varchar(32)"`     // make sure this is long enough to handle all actions
-	Status      TaskStatus      `json:"status" gorm:"not null; index"`
-	Payload     json.RawMessage `json:"payload,omitempty" gorm:"type:jsonb"` // Data that is required for the task to be executed
-	Result      json.RawMessage `json:"result,omitempty" gorm:"type:jsonb"`  // Result of the task
-	Attempts    uint            `json:"attempts" gorm:"not null; default:0"`
-	Logs        string          `json:"logs,omitempty" gorm:"type:text"`
-	Error       string          `json:"error,omitempty" gorm:"type:text"`
-	WebhookURL  string          `json:"webhook_url,omitempty" gorm:"type:text"`
-	WebhookSent bool            `json:"webhook_sent" gorm:"not null;default:false;index"`
-	CreatedAt   time.Time       `json:"created_at" gorm:"index"`
-	LockedAt    *time.Time      `json:"locked_at,omitempty" gorm:"index"`   // When the task was locked for processing
-	LockExpiry  *time.Time      `json:"lock_expiry,omitempty" gorm:""`      // When the lock expires
-	Priority    TaskPriority    `json:"priority" gorm:"not null;default:1"` // Task priority (higher number = lower priority)
+	ProjectID  uint            `json:"project_id" gorm:"not null; index"`
+	OwnerID    uint            `json:"-" gorm:"not null; index"`
+	ProviderID ProviderID      `json:"provider_id,omitempty" gorm:"index"`
+	InstanceID uint            `json:"instance_id,omitempty" gorm:"index"` // Link to the specific instance, if applicable
+	Action     TaskAction      `json:"action" gorm:"type:varchar(32)"`     // make sure this is long enough to handle all actions
+	Status     TaskStatus      `json:"status" gorm:"not null; index"`
+	Payload    json.RawMessage `json:"payload,omitempty" gorm:"type:jsonb"` // Data that is required for the task to be executed
+	Result     *TaskResult     `json:"result,omitempty" gorm:"type:jsonb"`  // Result of the task
+	Attempts   uint            `json:"attempts" gorm:"not null; default:0"`
+	Logs       string          `json:"logs,omitempty" gorm:"type:text"`
+	Error      string          `json:"error,omitempty" gorm:"type:text"`
+	CreatedAt  time.Time       `json:"created_at" gorm:"index"`
+	LockedAt   *time.Time      `json:"locked_at,omitempty" gorm:"index"`             // When the task was locked for processing
+	LockExpiry *time.Time      `json:"lock_expiry,omitempty" gorm:""`                // When the lock expires
+	WorkerID   string          `json:"worker_id,omitempty" gorm:"index"`             // Worker holding the lock
+	Priority   TaskPriority    `json:"priority" gorm:"not null;default:1"`           // Task priority (higher number = lower priority)
+	NextRunAt  *time.Time      `json:"next_run_at,omitempty" gorm:"index"`           // When a failed task is retried, nil to run right away
+	Checkpoint TaskStep        `json:"checkpoint,omitempty" gorm:"type:varchar(32)"` // Last step completed by a worker, retries resume after it
+}
+
+// TaskSchedulingFilter excludes tasks from scheduling
+type TaskSchedulingFilter struct {
+	// ExcludeTaskIDs are tasks that were already dispatched
+	ExcludeTaskIDs []uint
+	// ExcludeOwnerIDs, ExcludeProjectIDs and ExcludeProviderIDs are owners, projects and
+	// providers that reached their concurrency limit
+	ExcludeOwnerIDs    []uint
+	ExcludeProjectIDs  []uint
+	ExcludeProviderIDs []ProviderID
+}
+
+// RunningTaskCount is the number of running tasks of an owner and project on a provider
+type RunningTaskCount struct {
+	OwnerID    uint
+	ProjectID  uint
+	ProviderID ProviderID
+	Count      int
 }
 
 // MarshalJSON implements the json.Marshaler interface for Task
@@ -123,6 +157,17 @@ func (s TaskStatus) String() string {
 	return string(s)
 }
 
+// IsFinal reports whether a task in this status is no longer processed. Dead tasks are final
+// until an admin requeues them.
+func (s TaskStatus) IsFinal() bool {
+	switch s {
+	case TaskStatusCompleted, TaskStatusFailed, TaskStatusTerminated, TaskStatusDead:
+		return true
+	default:
+		return false
+	}
+}
+
 // ParseTaskStatus converts a string to a TaskStatus type
 func ParseTaskStatus(str string) (TaskStatus, error) {
 	switch str {
@@ -138,6 +183,8 @@ func ParseTaskStatus(str string) (TaskStatus, error) {
 		return TaskStatusFailed, nil
 	case string(TaskStatusTerminated):
 		return TaskStatusTerminated, nil
+	case string(TaskStatusDead):
+		return TaskStatusDead, nil
 	default:
 		return TaskStatusUnknown, fmt.Errorf("invalid task status: %s", str)
 	}
@@ -220,53 +267,3 @@ func (t *Task) Unlock() {
 	t.LockedAt = nil
 	t.LockExpiry = nil
 }
-
-// SendWebhook sends a notification to the webhook URL if configured
-func (t *Task) SendWebhook() error {
-	if t.WebhookURL == "" {
-		return nil // No webhook configured
-	}
-
-	// Create payload
-	payload := map[string]interface{}{
-		"task_id": t.ID,
-		"status":  t.Status,
-		"action":  t.Action,
-	}
-
-	if t.Error != "" {
-		payload["error"] = t.Error
-	}
-
-	if t.Result != nil {
-		payload["result"] = t.Result
-	}
-
-	// Convert payload to JSON
-	data, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("failed to marshal webhook payload: %w", err)
-	}
-
-	// Send HTTP request
-	client := &http.Client{
-		Timeout: WebhookTimeout,
-	}
-
-	resp, err := client.Post(t.WebhookURL, WebhookContentType, bytes.NewBuffer(data))
-	if err != nil {
-		return fmt.Errorf("webhook request failed: %w", err)
-	}
-	// Check the error returned by Close, as the linter suggests.
-	closeErr := resp.Body.Close()
-	if closeErr != nil {
-		// Log the error or handle it as appropriate. Here we return it wrapped.
-		return fmt.Errorf("failed to close response body: %w", closeErr)
-	}
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("webhook returned non-success status code: %d", resp.StatusCode)
-	}
-
-	return nil
-}
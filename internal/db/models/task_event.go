@@ -0,0 +1,146 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Field names for task event model
+const (
+	// TaskEventIDField is the field name for task event ID
+	TaskEventIDField = "id"
+	// TaskEventTaskIDField is the field name for the task of a task event
+	TaskEventTaskIDField = "task_id"
+	// TaskEventOwnerIDField is the field name for the owner of a task event
+	TaskEventOwnerIDField = "owner_id"
+)
+
+// TaskEventLevel is the severity of a task event
+type TaskEventLevel string
+
+// Task event level constants
+const (
+	// TaskEventInfo reports the progress of a task
+	TaskEventInfo TaskEventLevel = "info"
+	// TaskEventWarn reports a problem the task recovered from or that needs attention
+	TaskEventWarn TaskEventLevel = "warn"
+	// TaskEventError reports a failed attempt of a task
+	TaskEventError TaskEventLevel = "error"
+)
+
+// TaskStep is the step of a task an event belongs to
+type TaskStep string
+
+// Task step constants
+const (
+	// TaskStepCreate is the creation of an instance on its provider
+	TaskStepCreate TaskStep = "create"
+	// TaskStepProvision is the provisioning of an instance
+	TaskStepProvision TaskStep = "provision"
+	// TaskStepPayload is the copy and execution of the payload of an instance
+	TaskStepPayload TaskStep = "payload"
+	// TaskStepTerminate is the termination of an instance or the rollback of a terminated task
+	TaskStepTerminate TaskStep = "terminate"
+)
+
+// TaskEventFields holds the structured fields of a task event
+type TaskEventFields map[string]interface{}
+
+// Value implements the driver.Valuer interface
+func (f TaskEventFields) Value() (driver.Value, error) {
+	if f == nil {
+		return nil, nil
+	}
+	return json.Marshal(f)
+}
+
+// Scan implements the sql.Scanner interface
+func (f *TaskEventFields) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case nil:
+		*f = nil
+		return nil
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	}
+	return json.Unmarshal(bytes, f)
+}
+
+// TaskEvent is an entry of the log of a task. Events are only ever appended.
+type TaskEvent struct {
+	ID        uint            `json:"id" gorm:"primarykey"`
+	TaskID    uint            `json:"task_id" gorm:"not null; index"`
+	OwnerID   uint            `json:"-" gorm:"not null; index"`
+	CreatedAt time.Time       `json:"created_at"`
+	Level     TaskEventLevel  `json:"level" gorm:"type:varchar(16); not null"`
+	Step      TaskStep        `json:"step,omitempty" gorm:"type:varchar(32)"`
+	Message   string          `json:"message" gorm:"type:text"`
+	Fields    TaskEventFields `json:"fields,omitempty" gorm:"type:jsonb"`
+}
+
+// String renders the event as a log line
+func (e TaskEvent) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] [%s]", e.CreatedAt.Format("2006-01-02 15:04:05"), e.Level)
+	if e.Step != "" {
+		fmt.Fprintf(&b, " [%s]", e.Step)
+	}
+	b.WriteString(" " + e.Message)
+	for _, key := range slices.Sorted(maps.Keys(e.Fields)) {
+		fmt.Fprintf(&b, " %s=%v", key, e.Fields[key])
+	}
+	return b.String()
+}
+
+// TaskLogsTruncatedLine marks the logs of a task rendered from its last events only
+const TaskLogsTruncatedLine = "... earlier events are omitted, list them from the task events endpoint"
+
+// TaskLogs renders the logs of a task: the logs written before task events were introduced,
+// followed by one line per event. Truncated logs only have the last events of the task.
+func TaskLogs(legacyLogs string, events []TaskEvent, truncated bool) string {
+	lines := make([]string, 0, len(events)+2)
+	if legacyLogs != "" {
+		lines = append(lines, legacyLogs)
+	}
+	if truncated {
+		lines = append(lines, TaskLogsTruncatedLine)
+	}
+	for _, event := range events {
+		lines = append(lines, event.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// TaskStreamMessageType is the kind of a message of a task event stream
+type TaskStreamMessageType string
+
+// Task stream message type constants
+const (
+	// TaskStreamStatus reports the status of a task
+	TaskStreamStatus TaskStreamMessageType = "status"
+	// TaskStreamEvent reports an event added to a task
+	TaskStreamEvent TaskStreamMessageType = "event"
+	// TaskStreamEnd is the last message of the stream of a task that finished
+	TaskStreamEnd TaskStreamMessageType = "end"
+	// TaskStreamError reports that the stream failed and was closed
+	TaskStreamError TaskStreamMessageType = "error"
+)
+
+// TaskStreamMessage is a message pushed to the clients following the events of tasks
+type TaskStreamMessage struct {
+	Type   TaskStreamMessageType `json:"type"`
+	TaskID uint                  `json:"task_id,omitempty"`
+	Status TaskStatus            `json:"status,omitempty"`
+	Event  *TaskEvent            `json:"event,omitempty"`
+	Error  string                `json:"error,omitempty"`
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TaskResult is the outcome of the last attempt of a task, written by the worker that processed it
+type TaskResult struct {
+	InstanceID         uint          `json:"instance_id,omitempty"`
+	ProviderID         ProviderID    `json:"provider_id,omitempty"`
+	ProviderInstanceID int           `json:"provider_instance_id,omitempty"`
+	PublicIP           string        `json:"public_ip,omitempty"`
+	Region             string        `json:"region,omitempty"`
+	VolumeIDs          []string      `json:"volume_ids,omitempty"`
+	VolumeDetails      VolumeDetails `json:"volume_details,omitempty"`
+	// Steps are the steps of the task that ran, in order
+	Steps []TaskStepResult `json:"steps,omitempty"`
+	// AnsibleRecap holds the PLAY RECAP counts of the provisioning playbook per host
+	AnsibleRecap map[string]AnsibleHostRecap `json:"ansible_recap,omitempty"`
+	// ProvisionTasks are the results of the provisioning tasks per host, in the order they finished
+	ProvisionTasks []ProvisionTaskResult `json:"provision_tasks,omitempty"`
+	// PayloadExitCode is the exit code of the payload script, unset if it was not executed
+	PayloadExitCode *int `json:"payload_exit_code,omitempty"`
+}
+
+// TaskStepResult is the outcome of a step of a task
+type TaskStepResult struct {
+	Step       TaskStep  `json:"step"`
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int64     `json:"duration_ms"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// AnsibleHostRecap are the task counts of a host in the PLAY RECAP of a playbook
+type AnsibleHostRecap struct {
+	Ok          int `json:"ok"`
+	Changed     int `json:"changed"`
+	Unreachable int `json:"unreachable"`
+	Failed      int `json:"failed"`
+	Skipped     int `json:"skipped"`
+	Rescued     int `json:"rescued"`
+	Ignored     int `json:"ignored"`
+}
+
+// ProvisionTaskStatus is the outcome of a provisioning task on a host
+type ProvisionTaskStatus string
+
+// Provision task status constants
+const (
+	// ProvisionTaskOk is a task that succeeded without changes
+	ProvisionTaskOk ProvisionTaskStatus = "ok"
+	// ProvisionTaskChanged is a task that succeeded and changed the host
+	ProvisionTaskChanged ProvisionTaskStatus = "changed"
+	// ProvisionTaskFailed is a task that failed
+	ProvisionTaskFailed ProvisionTaskStatus = "failed"
+	// ProvisionTaskUnreachable is a task that could not reach the host
+	ProvisionTaskUnreachable ProvisionTaskStatus = "unreachable"
+	// ProvisionTaskSkipped is a task that did not run on the host
+	ProvisionTaskSkipped ProvisionTaskStatus = "skipped"
+	// ProvisionTaskIgnored is a task that failed with its errors ignored
+	ProvisionTaskIgnored ProvisionTaskStatus = "ignored"
+)
+
+// ProvisionTaskResult is the outcome of a task of the provisioner on a host, such as a task of
+// the Ansible playbook
+type ProvisionTaskResult struct {
+	Task   string              `json:"task"`
+	Host   string              `json:"host"`
+	Status ProvisionTaskStatus `json:"status"`
+	// Message is what the task failed with, empty for tasks that succeeded
+	Message string `json:"message,omitempty"`
+}
+
+// SetInstance records the provider details of the instance the task acts on
+func (r *TaskResult) SetInstance(instance *Instance) {
+	r.InstanceID = instance.ID
+	r.ProviderID = instance.ProviderID
+	r.ProviderInstanceID = instance.ProviderInstanceID
+	r.PublicIP = instance.PublicIP
+	r.Region = instance.Region
+	r.VolumeIDs = instance.VolumeIDs
+	r.VolumeDetails = instance.VolumeDetails
+}
+
+// StartStep records the start of a step and returns the function that records its end with
+// the error the step failed with, if any
+func (r *TaskResult) StartStep(step TaskStep) func(err error) {
+	startedAt := time.Now()
+	return func(err error) {
+		result := TaskStepResult{
+			Step:       step,
+			StartedAt:  startedAt.UTC(),
+			DurationMs: time.Since(startedAt).Milliseconds(),
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		r.Steps = append(r.Steps, result)
+	}
+}
+
+// Value implements the driver.Valuer interface
+func (r TaskResult) Value() (driver.Value, error) {
+	return json.Marshal(r)
+}
+
+// Scan implements the sql.Scanner interface
+func (r *TaskResult) Scan(value interface{}) error {
+	var bytes []byte
+	switch v := value.(type) {
+	case nil:
+		*r = TaskResult{}
+		return nil
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("failed to unmarshal JSONB value: %v", value)
+	}
+	return json.Unmarshal(bytes, r)
+}
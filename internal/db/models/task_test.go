@@ -58,6 +58,14 @@ func TestTaskStatus(t *testing.T) {
 			validForParse: true,
 			validForJSON:  true,
 		},
+		{
+			name:          "Dead status",
+			status:        TaskStatusDead,
+			stringValue:   "dead",
+			jsonValue:     `"dead"`,
+			validForParse: true,
+			validForJSON:  true,
+		},
 		{
 			name:          "Invalid status",
 			stringValue:   "invalid_status",
@@ -112,7 +120,16 @@ func TestTaskStatus(t *testing.T) {
 
 func TestTask_Validation(t *testing.T) {
 	now := time.Now()
-	result := json.RawMessage(`{"key":"value"}`)
+	exitCode := 0
+	result := &TaskResult{
+		InstanceID: 1,
+		PublicIP:   "192.0.2.1",
+		Steps:      []TaskStepResult{{Step: TaskStepCreate, StartedAt: now.UTC(), DurationMs: 1500}},
+		AnsibleRecap: map[string]AnsibleHostRecap{
+			"192.0.2.1": {Ok: 5, Changed: 3},
+		},
+		PayloadExitCode: &exitCode,
+	}
 
 	validTask := Task{
 		Model: gorm.Model{
@@ -120,13 +137,11 @@ func TestTask_Validation(t *testing.T) {
 			CreatedAt: now,
 			UpdatedAt: now,
 		},
-		ProjectID:   1,
-		Status:      TaskStatusPending,
-		Result:      result,
-		Error:       "",
-		WebhookURL:  "https://example.com/webhook",
-		WebhookSent: false,
-		CreatedAt:   now,
+		ProjectID: 1,
+		Status:    TaskStatusPending,
+		Result:    result,
+		Error:     "",
+		CreatedAt: now,
 	}
 
 	t.Run("Valid task", func(t *testing.T) {
@@ -142,15 +157,14 @@ func TestTask_Validation(t *testing.T) {
 		assert.Equal(t, validTask.ProjectID, unmarshaledTask.ProjectID)
 		assert.Equal(t, validTask.Status, unmarshaledTask.Status)
 
-		// Compare Result by unmarshaling to map to ignore formatting differences
-		var expectedResult, actualResult map[string]interface{}
-		assert.NoError(t, json.Unmarshal(validTask.Result, &expectedResult))
-		assert.NoError(t, json.Unmarshal(unmarshaledTask.Result, &actualResult))
-		assert.Equal(t, expectedResult, actualResult)
+		assert.Equal(t, validTask.Result.PublicIP, unmarshaledTask.Result.PublicIP)
+		assert.Equal(t, validTask.Result.AnsibleRecap, unmarshaledTask.Result.AnsibleRecap)
+		assert.Equal(t, validTask.Result.PayloadExitCode, unmarshaledTask.Result.PayloadExitCode)
+		assert.Len(t, unmarshaledTask.Result.Steps, 1)
+		assert.Equal(t, TaskStepCreate, unmarshaledTask.Result.Steps[0].Step)
+		assert.Equal(t, int64(1500), unmarshaledTask.Result.Steps[0].DurationMs)
 
 		assert.Equal(t, validTask.Error, unmarshaledTask.Error)
-		assert.Equal(t, validTask.WebhookURL, unmarshaledTask.WebhookURL)
-		assert.Equal(t, validTask.WebhookSent, unmarshaledTask.WebhookSent)
 		assert.Equal(t, validTask.CreatedAt.Unix(), unmarshaledTask.CreatedAt.Unix())
 	})
 
@@ -169,15 +183,16 @@ func TestTask_Validation(t *testing.T) {
 
 	t.Run("Task with empty Result", func(t *testing.T) {
 		task := validTask
-		task.Result = json.RawMessage(`{}`)
+		task.Result = &TaskResult{}
 
 		jsonData, err := json.Marshal(task)
 		assert.NoError(t, err)
+		assert.Contains(t, string(jsonData), `"result":{}`)
 
 		var unmarshaledTask Task
 		err = json.Unmarshal(jsonData, &unmarshaledTask)
 		assert.NoError(t, err)
-		assert.Equal(t, string(json.RawMessage(`{}`)), string(unmarshaledTask.Result))
+		assert.Equal(t, &TaskResult{}, unmarshaledTask.Result)
 	})
 
 	t.Run("Task with different statuses", func(t *testing.T) {
@@ -202,3 +217,24 @@ func TestTask_Validation(t *testing.T) {
 		}
 	})
 }
+
+func TestTaskLogs(t *testing.T) {
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	events := []TaskEvent{
+		{CreatedAt: createdAt, Level: TaskEventInfo, Step: TaskStepCreate, Message: "Creating instance"},
+		{CreatedAt: createdAt, Level: TaskEventWarn, Message: "Retrying",
+			Fields: TaskEventFields{"next_run_at": "soon", "attempt": 2}},
+	}
+
+	assert.Equal(t, "", TaskLogs("", nil, false))
+	assert.Equal(t, "old logs", TaskLogs("old logs", nil, false))
+	assert.Equal(t,
+		"old logs\n"+
+			"[2025-01-02 03:04:05] [info] [create] Creating instance\n"+
+			"[2025-01-02 03:04:05] [warn] Retrying attempt=2 next_run_at=soon",
+		TaskLogs("old logs", events, false))
+	assert.Equal(t,
+		TaskLogsTruncatedLine+"\n"+
+			"[2025-01-02 03:04:05] [warn] Retrying attempt=2 next_run_at=soon",
+		TaskLogs("", events[1:], true))
+}
@@ -24,12 +24,14 @@ const (
 // User represents a user in the system
 type User struct {
 	gorm.Model
-	Username     string   `json:"username" gorm:"not null;unique"`
-	Email        string   `json:"email" gorm:""`
-	Role         UserRole `json:"role" gorm:"index"`
-	PublicSSHKey string   `json:"public_ssh_key" gorm:""`
-	CreatedAt    string   `json:"created_at" gorm:""`
-	UpdatedAt    string   `json:"updated_at" gorm:""`
+	Username      string   `json:"username" gorm:"not null;unique"`
+	Email         string   `json:"email" gorm:""`
+	Role          UserRole `json:"role" gorm:"index"`
+	PublicSSHKey  string   `json:"public_ssh_key" gorm:""`
+	WebhookURL    string   `json:"webhook_url,omitempty" gorm:"type:text"` // Receives the task and instance events of the user
+	WebhookSecret string   `json:"-" gorm:"type:text"`                     // Signs the webhook requests
+	CreatedAt     string   `json:"created_at" gorm:""`
+	UpdatedAt     string   `json:"updated_at" gorm:""`
 }
 
 // MarshalJSON implements the json.Marshaler interface for User
@@ -0,0 +1,159 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Field names for webhook delivery model
+const (
+	// WebhookDeliveryIDField is the field name for webhook delivery ID
+	WebhookDeliveryIDField = "id"
+	// WebhookDeliveryStatusField is the field name for webhook delivery status
+	WebhookDeliveryStatusField = "status"
+	// WebhookDeliveryAttemptsField is the field name for webhook delivery attempts
+	WebhookDeliveryAttemptsField = "attempts"
+	// WebhookDeliveryDeliveredAtField is the field name for the time a delivery was delivered
+	WebhookDeliveryDeliveredAtField = "delivered_at"
+	// WebhookDeliveryNextAttemptAtField is the field name for the time a delivery is attempted next
+	WebhookDeliveryNextAttemptAtField = "next_attempt_at"
+	// WebhookDeliveryLockedUntilField is the field name for the time the claim of a deliverer expires
+	WebhookDeliveryLockedUntilField = "locked_until"
+	// WebhookDeliveryCreatedAtField is the field name for webhook delivery created at
+	WebhookDeliveryCreatedAtField = "created_at"
+	// WebhookAttemptCreatedAtField is the field name for webhook attempt created at
+	WebhookAttemptCreatedAtField = "created_at"
+)
+
+// Webhook request headers
+const (
+	// WebhookEventHeader holds the event type of a webhook request
+	WebhookEventHeader = "X-Talis-Event"
+	// WebhookDeliveryHeader holds the ID of the delivery, which is the same across retries
+	WebhookDeliveryHeader = "X-Talis-Delivery"
+	// WebhookSignatureHeader holds the hex encoded HMAC-SHA256 of the request body keyed with the
+	// webhook secret of the user, prefixed with WebhookSignaturePrefix
+	WebhookSignatureHeader = "X-Talis-Signature-256"
+	// WebhookSignaturePrefix is the prefix of the signature header value
+	WebhookSignaturePrefix = "sha256="
+	// WebhookContentType is the content type for webhook requests
+	WebhookContentType = "application/json"
+	// WebhookTimeout is the timeout for webhook requests
+	WebhookTimeout = 10 * time.Second
+)
+
+// WebhookEventType is the type of event a webhook is sent for
+type WebhookEventType string
+
+// Webhook event type constants
+const (
+	// WebhookEventTaskStatusChanged is sent when a task changes status
+	WebhookEventTaskStatusChanged WebhookEventType = "task.status_changed"
+	// WebhookEventInstanceStatusChanged is sent when an instance changes status
+	WebhookEventInstanceStatusChanged WebhookEventType = "instance.status_changed"
+)
+
+// WebhookDeliveryStatus represents the state of a webhook delivery
+type WebhookDeliveryStatus string
+
+// Webhook delivery status constants
+const (
+	// WebhookDeliveryPending indicates the delivery waits for its next attempt
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliveryDelivered indicates the endpoint acknowledged the delivery
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryFailed indicates the delivery used up its attempts
+	WebhookDeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is an event waiting to be, or that was, sent to the webhook of a user.
+// Deliveries are written to the database when the event happens and sent by a background
+// deliverer, so that events are not lost if the endpoint or the server is down.
+type WebhookDelivery struct {
+	gorm.Model
+	OwnerID       uint                  `json:"-" gorm:"not null; index"`
+	Event         WebhookEventType      `json:"event" gorm:"type:varchar(32); not null"`
+	TaskID        uint                  `json:"task_id,omitempty" gorm:"index"`
+	InstanceID    uint                  `json:"instance_id,omitempty" gorm:"index"`
+	URL           string                `json:"url" gorm:"type:text; not null"`
+	Payload       json.RawMessage       `json:"payload" gorm:"type:jsonb"`
+	Status        WebhookDeliveryStatus `json:"status" gorm:"type:varchar(16); not null; index"`
+	Attempts      uint                  `json:"attempts" gorm:"not null; default:0"`
+	NextAttemptAt *time.Time            `json:"next_attempt_at,omitempty" gorm:"index"` // nil once the delivery is delivered or failed
+	DeliveredAt   *time.Time            `json:"delivered_at,omitempty"`
+	LockedUntil   *time.Time            `json:"-"` // Claim of the deliverer sending the delivery
+	CreatedAt     time.Time             `json:"created_at" gorm:"index"`
+	AttemptLog    []WebhookAttempt      `json:"attempt_log,omitempty" gorm:"foreignKey:DeliveryID"`
+}
+
+// WebhookAttempt records a single attempt to send a webhook delivery
+type WebhookAttempt struct {
+	ID         uint      `json:"id" gorm:"primarykey"`
+	DeliveryID uint      `json:"delivery_id" gorm:"not null; index"`
+	StatusCode int       `json:"status_code,omitempty"` // HTTP status code of the response, 0 if there was none
+	Error      string    `json:"error,omitempty" gorm:"type:text"`
+	DurationMs int64     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// WebhookPayload is the body of a webhook request
+type WebhookPayload struct {
+	Event     WebhookEventType `json:"event"`
+	CreatedAt time.Time        `json:"created_at"`
+	Task      *WebhookTask     `json:"task,omitempty"`
+	Instance  *WebhookInstance `json:"instance,omitempty"`
+}
+
+// WebhookTask is the state of a task sent with a webhook
+type WebhookTask struct {
+	ID         uint       `json:"id"`
+	ProjectID  uint       `json:"project_id"`
+	InstanceID uint       `json:"instance_id,omitempty"`
+	Action     TaskAction `json:"action"`
+	Status     TaskStatus `json:"status"`
+	Attempts   uint       `json:"attempts"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// WebhookInstance is the state of an instance sent with a webhook
+type WebhookInstance struct {
+	ID         uint           `json:"id"`
+	ProjectID  uint           `json:"project_id"`
+	ProviderID ProviderID     `json:"provider_id"`
+	Name       string         `json:"name,omitempty"`
+	PublicIP   string         `json:"public_ip,omitempty"`
+	Status     InstanceStatus `json:"status"`
+}
+
+// NewWebhookTask returns the state of a task sent with a webhook
+func NewWebhookTask(task *Task) *WebhookTask {
+	return &WebhookTask{
+		ID:         task.ID,
+		ProjectID:  task.ProjectID,
+		InstanceID: task.InstanceID,
+		Action:     task.Action,
+		Status:     task.Status,
+		Attempts:   task.Attempts,
+		Error:      task.Error,
+	}
+}
+
+// NewWebhookInstance returns the state of an instance sent with a webhook
+func NewWebhookInstance(instance *Instance) *WebhookInstance {
+	return &WebhookInstance{
+		ID:         instance.ID,
+		ProjectID:  instance.ProjectID,
+		ProviderID: instance.ProviderID,
+		Name:       instance.Name,
+		PublicIP:   instance.PublicIP,
+		Status:     instance.Status,
+	}
+}
+
+// MarshalJSON implements the json.Marshaler interface for WebhookDelivery
+func (d WebhookDelivery) MarshalJSON() ([]byte, error) {
+	type Alias WebhookDelivery // Create an alias to avoid infinite recursion
+	return json.Marshal(Alias(d))
+}
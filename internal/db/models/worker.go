@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+)
+
+// Field names for worker model
+const (
+	// WorkerIDField is the field name for worker ID
+	WorkerIDField = "id"
+	// WorkerHeartbeatAtField is the field name for worker heartbeat at
+	WorkerHeartbeatAtField = "heartbeat_at"
+)
+
+const (
+	// WorkerHeartbeatInterval is how often a worker records a heartbeat
+	WorkerHeartbeatInterval = 10 * time.Second
+	// WorkerHeartbeatTimeout is the duration after the last heartbeat after which a worker is considered dead
+	// and the tasks it locked are reclaimed
+	WorkerHeartbeatTimeout = time.Minute
+)
+
+// Worker is a worker process of the worker fleet. Workers record heartbeats while they are
+// running so that the tasks of crashed workers can be reclaimed by the others.
+type Worker struct {
+	ID          string    `json:"id" gorm:"primaryKey; type:varchar(128)"`
+	Hostname    string    `json:"hostname" gorm:"type:varchar(255)"`
+	PID         int       `json:"pid"`
+	StartedAt   time.Time `json:"started_at"`
+	HeartbeatAt time.Time `json:"heartbeat_at" gorm:"not null; index"`
+}
@@ -0,0 +1,141 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+
+	"github.com/celestiaorg/talis/internal/logger"
+)
+
+// TaskNotificationChannel is the Postgres channel on which a notification is sent whenever
+// a task becomes pending. The payload is the priority of the task.
+const TaskNotificationChannel = "talis_tasks"
+
+// TaskEventNotificationChannel is the Postgres channel on which a notification is sent whenever
+// an event is added to a task or the status of a task changes. The payload is the ID of the task.
+const TaskEventNotificationChannel = "talis_task_events"
+
+// Reconnect intervals of the task listener connection
+const (
+	listenerMinReconnectInterval = time.Second
+	listenerMaxReconnectInterval = time.Minute
+)
+
+// taskNotifySQL creates the trigger that notifies TaskNotificationChannel when a task is
+// created as pending or is put back to pending, e.g. by a retry or a requeue
+var taskNotifySQL = fmt.Sprintf(`
+CREATE OR REPLACE FUNCTION talis_notify_task() RETURNS trigger AS $$
+BEGIN
+	IF NEW.status = 'pending' AND (TG_OP = 'INSERT'
+		OR OLD.status IS DISTINCT FROM NEW.status
+		OR OLD.next_run_at IS DISTINCT FROM NEW.next_run_at) THEN
+		PERFORM pg_notify('%s', NEW.priority::text);
+	END IF;
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS tasks_notify ON tasks;
+CREATE TRIGGER tasks_notify AFTER INSERT OR UPDATE OF status, next_run_at ON tasks
+	FOR EACH ROW EXECUTE FUNCTION talis_notify_task();
+`, TaskNotificationChannel)
+
+// taskEventNotifySQL creates the triggers that notify TaskEventNotificationChannel when an event
+// is added to a task and when the status of a task changes
+var taskEventNotifySQL = fmt.Sprintf(`
+CREATE OR REPLACE FUNCTION talis_notify_task_event() RETURNS trigger AS $$
+BEGIN
+	IF TG_TABLE_NAME = 'task_events' THEN
+		PERFORM pg_notify('%[1]s', NEW.task_id::text);
+	ELSIF TG_OP = 'INSERT' OR OLD.status IS DISTINCT FROM NEW.status THEN
+		PERFORM pg_notify('%[1]s', NEW.id::text);
+	END IF;
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS task_events_notify ON task_events;
+CREATE TRIGGER task_events_notify AFTER INSERT ON task_events
+	FOR EACH ROW EXECUTE FUNCTION talis_notify_task_event();
+
+DROP TRIGGER IF EXISTS tasks_status_notify ON tasks;
+CREATE TRIGGER tasks_status_notify AFTER INSERT OR UPDATE OF status ON tasks
+	FOR EACH ROW EXECUTE FUNCTION talis_notify_task_event();
+`, TaskEventNotificationChannel)
+
+// createTaskNotifyTrigger installs the task and task event notification triggers
+func createTaskNotifyTrigger(db *gorm.DB) error {
+	if err := db.Exec(taskNotifySQL).Error; err != nil {
+		return fmt.Errorf("failed to create task notification trigger: %w", err)
+	}
+	if err := db.Exec(taskEventNotifySQL).Error; err != nil {
+		return fmt.Errorf("failed to create task event notification trigger: %w", err)
+	}
+	return nil
+}
+
+// ListenForTasks listens on TaskNotificationChannel until ctx is cancelled. The returned channel
+// receives the payload of every notification. Notifications sent while the connection is down
+// are lost, so an empty payload is sent once the connection is re-established.
+func ListenForTasks(ctx context.Context, opts Options) (<-chan string, error) {
+	return listen(ctx, opts, TaskNotificationChannel, "Task")
+}
+
+// ListenForTaskEvents listens on TaskEventNotificationChannel until ctx is cancelled, the same way
+// ListenForTasks does
+func ListenForTaskEvents(ctx context.Context, opts Options) (<-chan string, error) {
+	return listen(ctx, opts, TaskEventNotificationChannel, "Task event")
+}
+
+// listen listens on a Postgres channel until ctx is cancelled. name is used in the logs of the
+// listener connection.
+func listen(ctx context.Context, opts Options, channel, name string) (<-chan string, error) {
+	listener := pq.NewListener(opts.dsn(), listenerMinReconnectInterval, listenerMaxReconnectInterval,
+		func(event pq.ListenerEventType, err error) {
+			switch event {
+			case pq.ListenerEventDisconnected:
+				logger.Warnf("⚠️ %s listener disconnected: %v", name, err)
+			case pq.ListenerEventReconnected:
+				logger.Infof("🔌 %s listener reconnected", name)
+			case pq.ListenerEventConnectionAttemptFailed:
+				logger.Warnf("⚠️ %s listener failed to reconnect: %v", name, err)
+			}
+		})
+	if err := listener.Listen(channel); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("failed to listen on %s: %w", channel, err)
+	}
+
+	payloads := make(chan string, cap(listener.Notify))
+	go func() {
+		defer close(payloads)
+		defer func() {
+			if err := listener.Close(); err != nil {
+				logger.Warnf("⚠️ Failed to close %s listener: %v", strings.ToLower(name), err)
+			}
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case n := <-listener.Notify:
+				payload := ""
+				if n != nil {
+					payload = n.Extra
+				}
+				select {
+				case payloads <- payload:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return payloads, nil
+}
@@ -0,0 +1,71 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+)
+
+// DriftEventRepository handles database operations for drift events
+type DriftEventRepository struct {
+	db *gorm.DB
+}
+
+// NewDriftEventRepository creates a new instance of DriftEventRepository
+func NewDriftEventRepository(db *gorm.DB) *DriftEventRepository {
+	return &DriftEventRepository{db: db}
+}
+
+// Create creates a new drift event in the database
+func (r *DriftEventRepository) Create(ctx context.Context, event *models.DriftEvent) error {
+	if event == nil {
+		return fmt.Errorf("drift event cannot be nil")
+	}
+	if err := models.ValidateOwnerID(event.OwnerID); err != nil {
+		return fmt.Errorf("invalid owner_id: %w", err)
+	}
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+// List retrieves drift events, newest first, with pagination
+func (r *DriftEventRepository) List(ctx context.Context, ownerID uint, opts *models.ListOptions) ([]models.DriftEvent, error) {
+	return r.list(ctx, ownerID, 0, opts)
+}
+
+// ListByInstance retrieves the drift events of an instance, newest first, with pagination
+func (r *DriftEventRepository) ListByInstance(ctx context.Context, ownerID, instanceID uint, opts *models.ListOptions) ([]models.DriftEvent, error) {
+	if instanceID == 0 {
+		return nil, fmt.Errorf("instanceID cannot be zero")
+	}
+	return r.list(ctx, ownerID, instanceID, opts)
+}
+
+func (r *DriftEventRepository) list(ctx context.Context, ownerID, instanceID uint, opts *models.ListOptions) ([]models.DriftEvent, error) {
+	if err := models.ValidateOwnerID(ownerID); err != nil {
+		return nil, fmt.Errorf("invalid owner_id: %w", err)
+	}
+
+	var events []models.DriftEvent
+	query := r.db.WithContext(ctx).Where(&models.DriftEvent{InstanceID: instanceID})
+	if ownerID != models.AdminID {
+		query = query.Where(&models.DriftEvent{OwnerID: ownerID})
+	}
+
+	if opts != nil {
+		if opts.Limit > 0 {
+			query = query.Limit(opts.Limit)
+		}
+		if opts.Offset > 0 {
+			query = query.Offset(opts.Offset)
+		}
+	}
+
+	err := query.Order(models.DriftEventCreatedAtField + " DESC").Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query drift events: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,88 @@
+package repos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+)
+
+type DriftEventRepositoryTestSuite struct {
+	DBRepositoryTestSuite
+}
+
+func (s *DriftEventRepositoryTestSuite) createTestDriftEvent(instance *models.Instance, eventType models.DriftEventType) *models.DriftEvent {
+	event := &models.DriftEvent{
+		OwnerID:            instance.OwnerID,
+		InstanceID:         instance.ID,
+		ProviderID:         instance.ProviderID,
+		ProviderInstanceID: instance.ProviderInstanceID,
+		Type:               eventType,
+		OldValue:           "old",
+		NewValue:           "new",
+	}
+	s.Require().NoError(s.driftRepo.Create(s.ctx, event))
+	s.Require().NotZero(event.ID)
+	return event
+}
+
+func (s *DriftEventRepositoryTestSuite) TestCreateNil() {
+	err := s.driftRepo.Create(s.ctx, nil)
+	s.Require().Error(err)
+}
+
+func (s *DriftEventRepositoryTestSuite) TestListByInstance() {
+	instance := s.createTestInstance()
+	other := s.createTestInstanceForOwner(instance.OwnerID)
+
+	first := s.createTestDriftEvent(instance, models.DriftEventStatusChanged)
+	second := s.createTestDriftEvent(instance, models.DriftEventIPChanged)
+	s.createTestDriftEvent(other, models.DriftEventMissing)
+
+	events, err := s.driftRepo.ListByInstance(s.ctx, instance.OwnerID, instance.ID, nil)
+	s.Require().NoError(err)
+	s.Require().Len(events, 2)
+	s.Require().ElementsMatch([]uint{first.ID, second.ID}, []uint{events[0].ID, events[1].ID})
+
+	// Another owner cannot see the events
+	events, err = s.driftRepo.ListByInstance(s.ctx, instance.OwnerID+1000, instance.ID, nil)
+	s.Require().NoError(err)
+	s.Require().Empty(events)
+
+	// Admin sees all events of the instance
+	events, err = s.driftRepo.ListByInstance(s.ctx, models.AdminID, instance.ID, nil)
+	s.Require().NoError(err)
+	s.Require().Len(events, 2)
+
+	// Pagination
+	events, err = s.driftRepo.ListByInstance(s.ctx, instance.OwnerID, instance.ID, &models.ListOptions{Limit: 1})
+	s.Require().NoError(err)
+	s.Require().Len(events, 1)
+
+	_, err = s.driftRepo.ListByInstance(s.ctx, instance.OwnerID, 0, nil)
+	s.Require().Error(err)
+}
+
+func (s *DriftEventRepositoryTestSuite) TestList() {
+	instance := s.createTestInstance()
+	other := s.createTestInstance()
+
+	s.createTestDriftEvent(instance, models.DriftEventMissing)
+	s.createTestDriftEvent(other, models.DriftEventMissing)
+
+	events, err := s.driftRepo.List(s.ctx, instance.OwnerID, nil)
+	s.Require().NoError(err)
+	for _, event := range events {
+		s.Require().Equal(instance.OwnerID, event.OwnerID)
+	}
+	s.Require().NotEmpty(events)
+
+	events, err = s.driftRepo.List(s.ctx, models.AdminID, nil)
+	s.Require().NoError(err)
+	s.Require().GreaterOrEqual(len(events), 2)
+}
+
+func TestDriftEventRepository(t *testing.T) {
+	suite.Run(t, new(DriftEventRepositoryTestSuite))
+}
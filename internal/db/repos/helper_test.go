@@ -26,6 +26,9 @@ type DBRepositoryTestSuite struct {
 	userRepo     *UserRepository
 	projectRepo  *ProjectRepository
 	taskRepo     *TaskRepository
+	driftRepo    *DriftEventRepository
+	webhookRepo  *WebhookRepository
+	playbookRepo *PlaybookRepository
 }
 
 // randomOwnerID creates a random owner ID using crypto/rand
@@ -57,7 +60,7 @@ func (s *DBRepositoryTestSuite) SetupTest() {
 	require.NoError(s.T(), err, "Failed to create in-memory database")
 
 	// Run migrations
-	err = db.AutoMigrate(&models.Instance{}, &models.User{}, &models.Project{}, &models.Task{})
+	err = db.AutoMigrate(&models.Instance{}, &models.User{}, &models.Project{}, &models.Task{}, &models.DriftEvent{}, &models.Worker{}, &models.TaskEvent{}, &models.WebhookDelivery{}, &models.WebhookAttempt{}, &models.Playbook{}, &models.InstancePayload{})
 	require.NoError(s.T(), err, "Failed to run database migrations")
 
 	// Initialize repositories
@@ -66,6 +69,9 @@ func (s *DBRepositoryTestSuite) SetupTest() {
 	s.userRepo = NewUserRepository(s.db)
 	s.projectRepo = NewProjectRepository(s.db)
 	s.taskRepo = NewTaskRepository(s.db)
+	s.driftRepo = NewDriftEventRepository(s.db)
+	s.webhookRepo = NewWebhookRepository(s.db)
+	s.playbookRepo = NewPlaybookRepository(s.db)
 	s.ctx = context.Background()
 }
 
@@ -175,6 +175,24 @@ func (r *InstanceRepository) Get(ctx context.Context, ownerID, id uint) (*models
 	return &instance, nil
 }
 
+// GetActiveByProviderInstanceID retrieves the instance of any owner that is not terminated
+// and is backed by the given provider instance
+func (r *InstanceRepository) GetActiveByProviderInstanceID(
+	ctx context.Context,
+	providerID models.ProviderID,
+	providerInstanceID int,
+) (*models.Instance, error) {
+	var instance models.Instance
+	err := r.db.WithContext(ctx).
+		Where(&models.Instance{ProviderID: providerID, ProviderInstanceID: providerInstanceID}).
+		Where(models.InstanceStatusField+" != ?", models.InstanceStatusTerminated).
+		First(&instance).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get instance by provider instance ID: %w", err)
+	}
+	return &instance, nil
+}
+
 // GetByProjectIDAndInstanceIDs retrieves instances that belong to a specific project and match the given instance IDs.
 func (r *InstanceRepository) GetByProjectIDAndInstanceIDs(
 	ctx context.Context,
@@ -256,3 +274,59 @@ func (r *InstanceRepository) CreateBatch(ctx context.Context, instances []*model
 	}
 	return instances, nil
 }
+
+// DeleteBatch permanently deletes the instances with the given IDs of any owner
+func (r *InstanceRepository) DeleteBatch(ctx context.Context, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := r.db.WithContext(ctx).Unscoped().Delete(&models.Instance{}, ids).Error; err != nil {
+		return fmt.Errorf("failed to delete instances: %w", err)
+	}
+	return nil
+}
+
+// SavePayload stores the output of the payload script of an instance, replacing the output of a
+// previous run
+func (r *InstanceRepository) SavePayload(ctx context.Context, payload *models.InstancePayload) error {
+	if err := models.ValidateOwnerID(payload.OwnerID); err != nil {
+		return fmt.Errorf("invalid owner_id: %w", err)
+	}
+
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: models.PayloadInstanceIDColumn}},
+		DoUpdates: clause.AssignmentColumns([]string{"updated_at", "exit_code", "stdout", "stderr"}),
+	}).Create(payload).Error
+	if err != nil {
+		return fmt.Errorf("failed to save payload of instance %d: %w", payload.InstanceID, err)
+	}
+	return nil
+}
+
+// GetPayload retrieves the output of the payload script of an instance
+func (r *InstanceRepository) GetPayload(ctx context.Context, ownerID, instanceID uint) (*models.InstancePayload, error) {
+	if err := models.ValidateOwnerID(ownerID); err != nil {
+		return nil, fmt.Errorf("invalid owner_id: %w", err)
+	}
+
+	var payload models.InstancePayload
+	query := r.db.WithContext(ctx).Where(&models.InstancePayload{InstanceID: instanceID})
+	if ownerID != models.AdminID {
+		query = query.Where(&models.InstancePayload{OwnerID: ownerID})
+	}
+	if err := query.First(&payload).Error; err != nil {
+		return nil, fmt.Errorf("failed to get payload of instance %d: %w", instanceID, err)
+	}
+	return &payload, nil
+}
+
+// DeletePayload permanently deletes the output of the payload script of an instance, if any
+func (r *InstanceRepository) DeletePayload(ctx context.Context, instanceID uint) error {
+	err := r.db.WithContext(ctx).Unscoped().
+		Where(&models.InstancePayload{InstanceID: instanceID}).
+		Delete(&models.InstancePayload{}).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete payload of instance %d: %w", instanceID, err)
+	}
+	return nil
+}
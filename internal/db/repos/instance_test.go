@@ -2,6 +2,7 @@ package repos
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -346,6 +347,82 @@ func (s *InstanceRepositoryTestSuite) TestTerminate() {
 	s.NoError(err)
 }
 
+func (s *InstanceRepositoryTestSuite) TestGetActiveByProviderInstanceID() {
+	instance := s.randomInstance()
+	instance.ProviderInstanceID = 4242
+	_, err := s.instanceRepo.Create(s.ctx, instance)
+	s.Require().NoError(err)
+
+	found, err := s.instanceRepo.GetActiveByProviderInstanceID(s.ctx, models.ProviderDO, 4242)
+	s.Require().NoError(err)
+	s.Equal(instance.ID, found.ID)
+
+	// Other providers and terminated instances do not match
+	_, err = s.instanceRepo.GetActiveByProviderInstanceID(s.ctx, models.ProviderHetzner, 4242)
+	s.ErrorIs(err, gorm.ErrRecordNotFound)
+
+	s.Require().NoError(s.instanceRepo.Terminate(s.ctx, models.AdminID, instance.ID))
+	_, err = s.instanceRepo.GetActiveByProviderInstanceID(s.ctx, models.ProviderDO, 4242)
+	s.ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (s *InstanceRepositoryTestSuite) TestDeleteBatch() {
+	deleted := s.createTestInstance()
+	kept := s.createTestInstance()
+
+	s.Require().NoError(s.instanceRepo.DeleteBatch(s.ctx, []uint{deleted.ID}))
+	s.Require().NoError(s.instanceRepo.DeleteBatch(s.ctx, nil))
+
+	_, err := s.instanceRepo.Get(s.ctx, models.AdminID, deleted.ID)
+	s.Error(err)
+	_, err = s.instanceRepo.Get(s.ctx, models.AdminID, kept.ID)
+	s.NoError(err)
+}
+
+func (s *InstanceRepositoryTestSuite) TestPayload() {
+	instance := s.createTestInstance()
+
+	_, err := s.instanceRepo.GetPayload(s.ctx, instance.OwnerID, instance.ID)
+	s.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+
+	exitCode := 1
+	s.Require().NoError(s.instanceRepo.SavePayload(s.ctx, &models.InstancePayload{
+		InstanceID: instance.ID,
+		OwnerID:    instance.OwnerID,
+		ExitCode:   &exitCode,
+		Stdout:     strings.Repeat("a", models.MaxPayloadOutputSize) + "end",
+		Stderr:     "failed",
+	}))
+
+	payload, err := s.instanceRepo.GetPayload(s.ctx, instance.OwnerID, instance.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(1, *payload.ExitCode)
+	s.Require().Len(payload.Stdout, models.MaxPayloadOutputSize)
+	s.Require().True(strings.HasSuffix(payload.Stdout, "end"))
+	s.Require().Equal("failed", payload.Stderr)
+
+	// The output of another run replaces the stored output
+	exitCode = 0
+	s.Require().NoError(s.instanceRepo.SavePayload(s.ctx, &models.InstancePayload{
+		InstanceID: instance.ID,
+		OwnerID:    instance.OwnerID,
+		ExitCode:   &exitCode,
+		Stdout:     "done",
+	}))
+	payload, err = s.instanceRepo.GetPayload(s.ctx, models.AdminID, instance.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(0, *payload.ExitCode)
+	s.Require().Equal("done", payload.Stdout)
+	s.Require().Empty(payload.Stderr)
+
+	_, err = s.instanceRepo.GetPayload(s.ctx, instance.OwnerID+1, instance.ID)
+	s.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+
+	s.Require().NoError(s.instanceRepo.DeletePayload(s.ctx, instance.ID))
+	_, err = s.instanceRepo.GetPayload(s.ctx, instance.OwnerID, instance.ID)
+	s.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
 func (s *InstanceRepositoryTestSuite) TestGetByProjectIDAndInstanceIDs() {
 	ownerID := s.randomOwnerID()
 	project1 := s.createTestProjectForOwner(ownerID)
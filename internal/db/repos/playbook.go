@@ -0,0 +1,76 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+)
+
+// PlaybookRepository provides methods for interacting with uploaded playbooks in the database
+type PlaybookRepository struct {
+	db *gorm.DB
+}
+
+// NewPlaybookRepository creates a new PlaybookRepository
+func NewPlaybookRepository(db *gorm.DB) *PlaybookRepository {
+	return &PlaybookRepository{db: db}
+}
+
+// Create creates a new playbook in the database
+func (r *PlaybookRepository) Create(ctx context.Context, playbook *models.Playbook) error {
+	return r.db.WithContext(ctx).Create(playbook).Error
+}
+
+// Get retrieves a playbook with its files by name for a specific owner.
+// It returns gorm.ErrRecordNotFound if the owner has no playbook with that name.
+func (r *PlaybookRepository) Get(ctx context.Context, ownerID uint, name string) (*models.Playbook, error) {
+	var playbook models.Playbook
+	err := r.db.WithContext(ctx).
+		Where(&models.Playbook{
+			Name:    name,
+			OwnerID: ownerID,
+		}).
+		First(&playbook).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get playbook %q: %w", name, err)
+	}
+	return &playbook, nil
+}
+
+// List retrieves all playbooks of a specific owner, without their files
+func (r *PlaybookRepository) List(ctx context.Context, ownerID uint) ([]*models.Playbook, error) {
+	var playbooks []*models.Playbook
+	err := r.db.WithContext(ctx).
+		Omit("files").
+		Where(&models.Playbook{
+			OwnerID: ownerID,
+		}).
+		Order(models.PlaybookNameColumn).
+		Find(&playbooks).Error
+	if err != nil {
+		return nil, fmt.Errorf("database error listing playbooks: %w", err)
+	}
+	return playbooks, nil
+}
+
+// Delete permanently removes a playbook by name and owner ID, so that its name can be reused.
+// It returns gorm.ErrRecordNotFound if the owner has no playbook with that name.
+func (r *PlaybookRepository) Delete(ctx context.Context, ownerID uint, name string) error {
+	result := r.db.WithContext(ctx).
+		Unscoped().
+		Where(&models.Playbook{
+			Name:    name,
+			OwnerID: ownerID,
+		}).
+		Delete(&models.Playbook{})
+	if result.Error != nil {
+		return fmt.Errorf("database error deleting playbook: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("playbook %q not found for owner %d: %w", name, ownerID, gorm.ErrRecordNotFound)
+	}
+	return nil
+}
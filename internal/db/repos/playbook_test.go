@@ -0,0 +1,76 @@
+package repos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+)
+
+type PlaybookRepositoryTestSuite struct {
+	DBRepositoryTestSuite
+}
+
+func (s *PlaybookRepositoryTestSuite) createTestPlaybook(ownerID uint, name string) *models.Playbook {
+	playbook := &models.Playbook{
+		Name:    name,
+		OwnerID: ownerID,
+		Files: models.PlaybookFiles{
+			"main.yml":                       "- hosts: all\n  roles: [validator]\n",
+			"roles/validator/tasks/main.yml": "- debug: msg=hello\n",
+		},
+	}
+	s.Require().NoError(s.playbookRepo.Create(s.ctx, playbook))
+	s.Require().NotZero(playbook.ID)
+	return playbook
+}
+
+func (s *PlaybookRepositoryTestSuite) TestCreateAndGet() {
+	ownerID := s.randomOwnerID()
+	created := s.createTestPlaybook(ownerID, "validator")
+
+	playbook, err := s.playbookRepo.Get(s.ctx, ownerID, "validator")
+	s.Require().NoError(err)
+	s.Require().Equal(created.ID, playbook.ID)
+	s.Require().Equal(created.Files, playbook.Files)
+
+	// Names are unique per owner
+	s.Require().Error(s.playbookRepo.Create(s.ctx, &models.Playbook{Name: "validator", OwnerID: ownerID, Files: created.Files}))
+	s.createTestPlaybook(ownerID+1000, "validator")
+
+	_, err = s.playbookRepo.Get(s.ctx, ownerID, "missing")
+	s.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+}
+
+func (s *PlaybookRepositoryTestSuite) TestCreateInvalid() {
+	err := s.playbookRepo.Create(s.ctx, &models.Playbook{
+		Name:    "escape",
+		OwnerID: s.randomOwnerID(),
+		Files:   models.PlaybookFiles{"main.yml": "", "../outside.yml": ""},
+	})
+	s.Require().ErrorContains(err, "invalid playbook file path")
+}
+
+func (s *PlaybookRepositoryTestSuite) TestListAndDelete() {
+	ownerID := s.randomOwnerID()
+	s.createTestPlaybook(ownerID, "node")
+	s.createTestPlaybook(ownerID, "bridge")
+
+	playbooks, err := s.playbookRepo.List(s.ctx, ownerID)
+	s.Require().NoError(err)
+	s.Require().Len(playbooks, 2)
+	s.Require().Equal("bridge", playbooks[0].Name)
+	s.Require().Empty(playbooks[0].Files)
+
+	s.Require().NoError(s.playbookRepo.Delete(s.ctx, ownerID, "bridge"))
+	s.Require().ErrorIs(s.playbookRepo.Delete(s.ctx, ownerID, "bridge"), gorm.ErrRecordNotFound)
+
+	// The name of a deleted playbook can be reused
+	s.createTestPlaybook(ownerID, "bridge")
+}
+
+func TestPlaybookRepository(t *testing.T) {
+	suite.Run(t, new(PlaybookRepositoryTestSuite))
+}
@@ -2,6 +2,8 @@ package repos
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,7 +13,11 @@ import (
 	"github.com/celestiaorg/talis/internal/db/models"
 )
 
-const maxAttempts = 10
+// activeTaskStatuses are the statuses of tasks that have not finished yet
+var activeTaskStatuses = []interface{}{
+	models.TaskStatusPending,
+	models.TaskStatusRunning,
+}
 
 // TaskRepository handles database operations for tasks
 type TaskRepository struct {
@@ -43,11 +49,11 @@ func (r *TaskRepository) GetByID(ctx context.Context, ownerID uint, id uint) (*m
 		return nil, fmt.Errorf("invalid owner_id: %w", err)
 	}
 	var task models.Task
-	if err := r.db.WithContext(ctx).
-		Where(models.Task{
-			Model:   gorm.Model{ID: id},
-			OwnerID: ownerID,
-		}).First(&task).Error; err != nil {
+	query := r.db.WithContext(ctx).Where(models.Task{Model: gorm.Model{ID: id}})
+	if ownerID != models.AdminID {
+		query = query.Where(models.Task{OwnerID: ownerID})
+	}
+	if err := query.First(&task).Error; err != nil {
 		return nil, err
 	}
 	return &task, nil
@@ -70,6 +76,26 @@ func (r *TaskRepository) ListByProject(ctx context.Context, ownerID uint, projec
 	return tasks, err
 }
 
+// ListByProjectUpdatedSince retrieves the tasks of a project updated at or after since
+func (r *TaskRepository) ListByProjectUpdatedSince(ctx context.Context, ownerID uint, projectID uint, since time.Time) ([]models.Task, error) {
+	if err := models.ValidateOwnerID(ownerID); err != nil {
+		return nil, fmt.Errorf("invalid owner_id: %w", err)
+	}
+	var tasks []models.Task
+	err := r.db.WithContext(ctx).
+		Where(models.Task{
+			OwnerID:   ownerID,
+			ProjectID: projectID,
+		}).
+		Where(clause.Gte{Column: models.TaskUpdatedAtField, Value: since}).
+		Order(models.TaskIDField).
+		Find(&tasks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query updated tasks: %w", err)
+	}
+	return tasks, nil
+}
+
 // UpdateStatus updates the status of a task in the database
 func (r *TaskRepository) UpdateStatus(ctx context.Context, ownerID uint, id uint, status models.TaskStatus) error {
 	if err := models.ValidateOwnerID(ownerID); err != nil {
@@ -81,7 +107,34 @@ func (r *TaskRepository) UpdateStatus(ctx context.Context, ownerID uint, id uint
 	}).Update(models.TaskStatusField, status).Error
 }
 
+// UpdateCheckpoint records the last step of a task completed by a worker together with the
+// payload the step produced
+func (r *TaskRepository) UpdateCheckpoint(ctx context.Context, ownerID uint, id uint, step models.TaskStep, payload json.RawMessage) error {
+	if err := models.ValidateOwnerID(ownerID); err != nil {
+		return fmt.Errorf("invalid owner_id: %w", err)
+	}
+	return r.db.WithContext(ctx).Model(&models.Task{}).Where(models.Task{
+		Model:   gorm.Model{ID: id},
+		OwnerID: ownerID,
+	}).Updates(map[string]interface{}{
+		models.TaskCheckpointField: step,
+		models.TaskPayloadField:    payload,
+	}).Error
+}
+
+// UpdateResult updates the result of a task in the database
+func (r *TaskRepository) UpdateResult(ctx context.Context, ownerID uint, id uint, result *models.TaskResult) error {
+	if err := models.ValidateOwnerID(ownerID); err != nil {
+		return fmt.Errorf("invalid owner_id: %w", err)
+	}
+	return r.db.WithContext(ctx).Model(&models.Task{}).Where(models.Task{
+		Model:   gorm.Model{ID: id},
+		OwnerID: ownerID,
+	}).Update(models.TaskResultField, result).Error
+}
+
 // Update updates an existing task in the database.
+// Logs are not updated, task events are appended with AddEvents instead.
 func (r *TaskRepository) Update(ctx context.Context, ownerID uint, task *models.Task) error {
 	if err := models.ValidateOwnerID(ownerID); err != nil {
 		return fmt.Errorf("invalid owner_id: %w", err)
@@ -89,21 +142,42 @@ func (r *TaskRepository) Update(ctx context.Context, ownerID uint, task *models.
 	return r.db.WithContext(ctx).Model(&models.Task{}).Where(models.Task{
 		Model:   gorm.Model{ID: task.ID},
 		OwnerID: ownerID,
-	}).Updates(task).Error
+	}).Omit(models.TaskLogsField).Updates(task).Error
+}
+
+// Terminate marks a pending or running task as terminated.
+// Returns false if the task does not exist or has already finished.
+func (r *TaskRepository) Terminate(ctx context.Context, ownerID uint, id uint) (bool, error) {
+	if err := models.ValidateOwnerID(ownerID); err != nil {
+		return false, fmt.Errorf("invalid owner_id: %w", err)
+	}
+	result := r.db.WithContext(ctx).Model(&models.Task{}).
+		Where(models.Task{
+			Model:   gorm.Model{ID: id},
+			OwnerID: ownerID,
+		}).
+		Where(clause.IN{Column: models.TaskStatusField, Values: activeTaskStatuses}).
+		Update(models.TaskStatusField, models.TaskStatusTerminated)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to terminate task: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
 }
 
 // AcquireTaskLock attempts to lock a task for processing.
 // Returns true if the lock was acquired, false otherwise.
-func (r *TaskRepository) AcquireTaskLock(ctx context.Context, taskID uint) (bool, error) {
+func (r *TaskRepository) AcquireTaskLock(ctx context.Context, taskID uint, workerID string) (bool, error) {
 	now := time.Now()
 	lockExpiry := now.Add(models.TaskLockTimeout)
 
 	// Create a task model with ID for the where clause
 	taskModel := &models.Task{Model: gorm.Model{ID: taskID}}
 
-	// Attempt to acquire the lock using an atomic update
+	// Attempt to acquire the lock using an atomic update.
+	// Tasks that finished or were terminated since they were fetched are not locked.
 	result := r.db.WithContext(ctx).Model(taskModel).
 		Where(
+			clause.IN{Column: models.TaskStatusField, Values: activeTaskStatuses},
 			clause.Or(
 				clause.Eq{Column: models.TaskLockedAtField, Value: nil},
 				clause.Lt{Column: models.TaskLockExpiryField, Value: now},
@@ -112,6 +186,7 @@ func (r *TaskRepository) AcquireTaskLock(ctx context.Context, taskID uint) (bool
 		Updates(map[string]interface{}{
 			models.TaskLockedAtField:   now,
 			models.TaskLockExpiryField: lockExpiry,
+			models.TaskWorkerIDField:   workerID,
 			models.TaskStatusField:     models.TaskStatusRunning,
 		})
 
@@ -123,16 +198,40 @@ func (r *TaskRepository) AcquireTaskLock(ctx context.Context, taskID uint) (bool
 	return result.RowsAffected > 0, nil
 }
 
-// ReleaseTaskLock releases a task lock
-func (r *TaskRepository) ReleaseTaskLock(ctx context.Context, taskID uint) error {
+// RenewTaskLock extends the lock a worker holds on a task by models.TaskLockTimeout.
+// Returns false if the worker no longer holds the lock, e.g. because another worker took over
+// the task after the lock expired.
+func (r *TaskRepository) RenewTaskLock(ctx context.Context, taskID uint, workerID string) (bool, error) {
+	// Create a task model with ID for the where clause
+	taskModel := &models.Task{Model: gorm.Model{ID: taskID}}
+
+	result := r.db.WithContext(ctx).Model(taskModel).
+		Where(
+			clause.Eq{Column: models.TaskWorkerIDField, Value: workerID},
+			clause.Neq{Column: models.TaskLockedAtField, Value: nil},
+		).
+		Update(models.TaskLockExpiryField, time.Now().Add(models.TaskLockTimeout))
+
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to renew task lock: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// ReleaseTaskLock releases the lock a worker holds on a task.
+// A lock that was taken over by another worker is left untouched.
+func (r *TaskRepository) ReleaseTaskLock(ctx context.Context, taskID uint, workerID string) error {
 	// Create a task model with ID for the where clause
 	taskModel := &models.Task{Model: gorm.Model{ID: taskID}}
 
 	// Update the task to release the lock
 	result := r.db.WithContext(ctx).Model(taskModel).
+		Where(clause.Eq{Column: models.TaskWorkerIDField, Value: workerID}).
 		Updates(map[string]interface{}{
 			models.TaskLockedAtField:   nil,
 			models.TaskLockExpiryField: nil,
+			models.TaskWorkerIDField:   nil,
 		})
 
 	if result.Error != nil {
@@ -142,65 +241,134 @@ func (r *TaskRepository) ReleaseTaskLock(ctx context.Context, taskID uint) error
 	return nil
 }
 
-// RecoverStaleTasks finds tasks that were in progress when the system crashed
-// and resets them to pending status with incremented attempts
-func (r *TaskRepository) RecoverStaleTasks(ctx context.Context) (int64, error) {
+// RecoverStaleTasks finds tasks that were in progress when their worker crashed and resets them
+// to pending status. A task is stale if the worker holding its lock has not recorded a heartbeat
+// since heartbeatDeadline, or if it is not held by any worker and its lock expired.
+// The interrupted attempt was counted when the task was locked. The IDs of the recovered tasks
+// are returned.
+func (r *TaskRepository) RecoverStaleTasks(ctx context.Context, heartbeatDeadline time.Time) ([]uint, error) {
 	now := time.Now()
 
-	// Find tasks that are in running state with expired locks or no locks
-	result := r.db.WithContext(ctx).Model(&models.Task{}).
-		Where(&models.Task{Status: models.TaskStatusRunning}).
-		Where(
-			clause.Or(
-				clause.Eq{Column: models.TaskLockedAtField, Value: nil},
-				clause.Lt{Column: models.TaskLockExpiryField, Value: now},
-			),
-		).
-		Updates(map[string]interface{}{
-			models.TaskStatusField:     models.TaskStatusPending,
-			models.TaskLockedAtField:   nil,
-			models.TaskLockExpiryField: nil,
-			models.TaskAttemptsField:   gorm.Expr(fmt.Sprintf("%s + 1", models.TaskAttemptsField)),
-			models.TaskLogsField:       gorm.Expr(fmt.Sprintf("CONCAT(%s, '\n[RECOVERED] Task was in running state during system restart')", models.TaskLogsField)),
+	aliveWorkers := r.db.Model(&models.Worker{}).
+		Select(models.WorkerIDField).
+		Where(clause.Gte{Column: models.WorkerHeartbeatAtField, Value: heartbeatDeadline})
+
+	// Tasks without a worker are stale once their lock expired, tasks of dead workers right away
+	unownedExpired := r.db.
+		Where(clause.Or(
+			clause.Eq{Column: models.TaskWorkerIDField, Value: nil},
+			clause.Eq{Column: models.TaskWorkerIDField, Value: ""},
+		)).
+		Where(clause.Or(
+			clause.Eq{Column: models.TaskLockedAtField, Value: nil},
+			clause.Lt{Column: models.TaskLockExpiryField, Value: now},
+		))
+	ownedByDeadWorker := r.db.
+		Where(clause.Neq{Column: models.TaskWorkerIDField, Value: ""}).
+		Where(clause.Expr{
+			SQL:  fmt.Sprintf("%s NOT IN (?)", models.TaskWorkerIDField),
+			Vars: []interface{}{aliveWorkers},
 		})
 
-	if result.Error != nil {
-		return 0, fmt.Errorf("failed to recover stale tasks: %w", result.Error)
+	staleTasks := func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&models.Task{}).
+			Where(&models.Task{Status: models.TaskStatusRunning}).
+			Where(r.db.Where(unownedExpired).Or(ownedByDeadWorker))
 	}
 
-	return result.RowsAffected, nil
-}
+	var recovered []uint
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var stale []models.Task
+		if err := staleTasks(tx).Select(models.TaskIDField, models.TaskOwnerIDField).Find(&stale).Error; err != nil {
+			return err
+		}
+		if len(stale) == 0 {
+			return nil
+		}
 
-// GetSchedulableTasks retrieves tasks that are ready for processing,
-// ordered by priority (high first), error status (no error first), and then by creation date (oldest first).
-// It fetches tasks with statuses other than Completed or Terminated.
-func (r *TaskRepository) GetSchedulableTasks(ctx context.Context, priority models.TaskPriority, limit int) ([]models.Task, error) {
-	var tasks []models.Task
+		ids := make([]interface{}, 0, len(stale))
+		events := make([]*models.TaskEvent, 0, len(stale))
+		for _, task := range stale {
+			ids = append(ids, task.ID)
+			events = append(events, &models.TaskEvent{
+				TaskID:  task.ID,
+				OwnerID: task.OwnerID,
+				Level:   models.TaskEventWarn,
+				Message: "Task was running on a worker that stopped, it will be processed again",
+			})
+		}
 
-	// Define statuses to include
-	includeStatuses := []interface{}{
-		models.TaskStatusPending,
-		models.TaskStatusRunning,
+		result := staleTasks(tx).
+			Where(clause.IN{Column: models.TaskIDField, Values: ids}).
+			Updates(map[string]interface{}{
+				models.TaskStatusField:     models.TaskStatusPending,
+				models.TaskLockedAtField:   nil,
+				models.TaskLockExpiryField: nil,
+				models.TaskWorkerIDField:   nil,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if err := addTaskEvents(tx, events...); err != nil {
+			return err
+		}
+		for _, task := range stale {
+			recovered = append(recovered, task.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover stale tasks: %w", err)
 	}
 
+	return recovered, nil
+}
+
+// GetSchedulableTasks retrieves tasks that are ready for processing.
+// It fetches pending and running tasks that are not locked and whose retry time, if any, has passed,
+// leaving out the tasks excluded by filter. Tasks are taken round-robin across owners, the oldest
+// task of every owner first, so that an owner with many tasks does not starve the others.
+func (r *TaskRepository) GetSchedulableTasks(ctx context.Context, priority models.TaskPriority, limit int, filter *models.TaskSchedulingFilter) ([]models.Task, error) {
+	var tasks []models.Task
+
 	// Build the query
-	query := r.db.WithContext(ctx).Model(&models.Task{}).
+	now := time.Now()
+	candidates := r.db.Model(&models.Task{}).
+		// Rank the tasks of each owner by age to interleave the owners
+		Select(fmt.Sprintf("*, ROW_NUMBER() OVER (PARTITION BY %s ORDER BY %s) AS owner_rank",
+			models.TaskOwnerIDField, models.TaskIDField)).
 		Where(models.Task{
 			Priority: priority,
 		}).
 		Where(
 			clause.IN{
 				Column: models.TaskStatusField,
-				Values: includeStatuses,
+				Values: activeTaskStatuses,
 			},
-			clause.Lt{Column: models.TaskAttemptsField, Value: maxAttempts},
 			clause.Or(
 				clause.Eq{Column: models.TaskLockedAtField, Value: nil},
-				clause.Lt{Column: models.TaskLockExpiryField, Value: time.Now()},
+				clause.Lt{Column: models.TaskLockExpiryField, Value: now},
 			),
-		).
-		// Order by priority (lower number = higher priority), error presence (errors last), then by creation date (oldest first)
-		Order(clause.OrderByColumn{Column: clause.Column{Name: models.TaskPriorityField}, Desc: false}).
+			clause.Or(
+				clause.Eq{Column: models.TaskNextRunAtField, Value: nil},
+				clause.Lte{Column: models.TaskNextRunAtField, Value: now},
+			),
+		)
+	if filter != nil {
+		candidates = excludeTasks(candidates, models.TaskIDField, filter.ExcludeTaskIDs)
+		candidates = excludeTasks(candidates, models.TaskOwnerIDField, filter.ExcludeOwnerIDs)
+		candidates = excludeTasks(candidates, models.TaskProjectIDField, filter.ExcludeProjectIDs)
+		// Tasks queued before providers were recorded have no provider and are never excluded by it
+		if len(filter.ExcludeProviderIDs) > 0 {
+			candidates = candidates.Where(clause.Or(
+				clause.Eq{Column: models.TaskProviderIDField, Value: nil},
+				clause.Not(clause.IN{Column: models.TaskProviderIDField, Values: toInterfaces(filter.ExcludeProviderIDs)}),
+			))
+		}
+	}
+
+	query := r.db.WithContext(ctx).Table("(?) AS tasks", candidates).
+		Order("owner_rank").
 		Order(clause.OrderByColumn{Column: clause.Column{Name: models.TaskIDField}, Desc: false}) // faster than created_at
 
 	// Apply limit
@@ -219,6 +387,71 @@ func (r *TaskRepository) GetSchedulableTasks(ctx context.Context, priority model
 	return tasks, nil
 }
 
+// GetNextRunAt returns the earliest time a task of the priority waiting for its retry is due,
+// or nil if no task is waiting
+func (r *TaskRepository) GetNextRunAt(ctx context.Context, priority models.TaskPriority) (*time.Time, error) {
+	var tasks []models.Task
+	err := r.db.WithContext(ctx).Model(&models.Task{}).
+		Select(models.TaskNextRunAtField).
+		Where(models.Task{Priority: priority}).
+		Where(
+			clause.IN{Column: models.TaskStatusField, Values: activeTaskStatuses},
+			clause.Gt{Column: models.TaskNextRunAtField, Value: time.Now()},
+		).
+		Order(models.TaskNextRunAtField).
+		Limit(1).
+		Find(&tasks).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query next task retry: %w", err)
+	}
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+	return tasks[0].NextRunAt, nil
+}
+
+// excludeTasks leaves out the tasks whose column has one of the values
+func excludeTasks[T any](query *gorm.DB, column string, values []T) *gorm.DB {
+	if len(values) == 0 {
+		return query
+	}
+	return query.Where(clause.Not(clause.IN{Column: column, Values: toInterfaces(values)}))
+}
+
+// toInterfaces converts values to a slice of empty interfaces, as used by gorm clauses
+func toInterfaces[T any](values []T) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
+
+// CountRunningTasks counts the tasks that are being processed by workers other than excludeWorkerID,
+// grouped by owner, project and provider
+func (r *TaskRepository) CountRunningTasks(ctx context.Context, excludeWorkerID string) ([]models.RunningTaskCount, error) {
+	var counts []models.RunningTaskCount
+	err := r.db.WithContext(ctx).Model(&models.Task{}).
+		Select(fmt.Sprintf("%s, %s, %s, COUNT(*) AS count",
+			models.TaskOwnerIDField, models.TaskProjectIDField, models.TaskProviderIDField)).
+		Where(
+			clause.Eq{Column: models.TaskStatusField, Value: models.TaskStatusRunning},
+			clause.Gte{Column: models.TaskLockExpiryField, Value: time.Now()},
+			clause.Or(
+				clause.Eq{Column: models.TaskWorkerIDField, Value: nil},
+				clause.Neq{Column: models.TaskWorkerIDField, Value: excludeWorkerID},
+			),
+		).
+		Group(models.TaskOwnerIDField).
+		Group(models.TaskProjectIDField).
+		Group(models.TaskProviderIDField).
+		Scan(&counts).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count running tasks: %w", err)
+	}
+	return counts, nil
+}
+
 // IncrementAttempts atomically increments the attempts count for a task
 func (r *TaskRepository) IncrementAttempts(ctx context.Context, taskID uint) error {
 	err := r.db.WithContext(ctx).
@@ -235,6 +468,62 @@ func (r *TaskRepository) IncrementAttempts(ctx context.Context, taskID uint) err
 	return nil
 }
 
+// ListByStatus retrieves the tasks of all owners with the given status, oldest first
+func (r *TaskRepository) ListByStatus(ctx context.Context, status models.TaskStatus, opts *models.ListOptions) ([]models.Task, error) {
+	var tasks []models.Task
+	query := r.db.WithContext(ctx).
+		Where(models.Task{Status: status}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: models.TaskIDField}, Desc: false})
+	if opts != nil {
+		query = query.Limit(opts.Limit).Offset(opts.Offset)
+	}
+	if err := query.Find(&tasks).Error; err != nil {
+		return nil, fmt.Errorf("failed to list %s tasks: %w", status, err)
+	}
+	return tasks, nil
+}
+
+// Requeue resets a dead task to pending so that it is processed again with a fresh
+// set of attempts. Returns false if the task does not exist or is not dead.
+func (r *TaskRepository) Requeue(ctx context.Context, id uint) (bool, error) {
+	var requeued bool
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var task models.Task
+		err := tx.Select(models.TaskIDField, models.TaskOwnerIDField).
+			Where(&models.Task{Model: gorm.Model{ID: id}, Status: models.TaskStatusDead}).
+			Take(&task).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.Task{Model: gorm.Model{ID: id}}).
+			Where(&models.Task{Status: models.TaskStatusDead}).
+			Updates(map[string]interface{}{
+				models.TaskStatusField:     models.TaskStatusPending,
+				models.TaskAttemptsField:   0,
+				models.TaskNextRunAtField:  nil,
+				models.TaskLockedAtField:   nil,
+				models.TaskLockExpiryField: nil,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		requeued = true
+		return addTaskEvents(tx, &models.TaskEvent{
+			TaskID:  task.ID,
+			OwnerID: task.OwnerID,
+			Message: "Task was requeued by an admin",
+		})
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to requeue task: %w", err)
+	}
+	return requeued, nil
+}
+
 // ListByInstanceID retrieves all tasks for a specific instance from the database with pagination and optional action filter.
 func (r *TaskRepository) ListByInstanceID(ctx context.Context, ownerID uint, instanceID uint, actionFilter models.TaskAction, opts *models.ListOptions) ([]models.Task, error) {
 	if err := models.ValidateOwnerID(ownerID); err != nil {
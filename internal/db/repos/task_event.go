@@ -0,0 +1,160 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+)
+
+// AddEvents appends events to the logs of their tasks
+func (r *TaskRepository) AddEvents(ctx context.Context, events ...*models.TaskEvent) error {
+	return addTaskEvents(r.db.WithContext(ctx), events...)
+}
+
+// addTaskEvents appends events to the logs of their tasks using db, which may be a transaction
+func addTaskEvents(db *gorm.DB, events ...*models.TaskEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	for _, event := range events {
+		if event == nil {
+			return fmt.Errorf("task event cannot be nil")
+		}
+		if event.TaskID == 0 {
+			return fmt.Errorf("task event has no task_id")
+		}
+		if err := models.ValidateOwnerID(event.OwnerID); err != nil {
+			return fmt.Errorf("invalid owner_id: %w", err)
+		}
+		if event.Level == "" {
+			event.Level = models.TaskEventInfo
+		}
+	}
+	if err := db.Create(events).Error; err != nil {
+		return fmt.Errorf("failed to add task events: %w", err)
+	}
+	return nil
+}
+
+// ListEvents retrieves the events of a task, oldest first, with pagination.
+// Only events with an ID greater than afterID are returned, so that a reader can continue
+// where it left off.
+func (r *TaskRepository) ListEvents(ctx context.Context, ownerID, taskID, afterID uint, opts *models.ListOptions) ([]models.TaskEvent, error) {
+	if err := models.ValidateOwnerID(ownerID); err != nil {
+		return nil, fmt.Errorf("invalid owner_id: %w", err)
+	}
+	if taskID == 0 {
+		return nil, fmt.Errorf("taskID cannot be zero")
+	}
+
+	var events []models.TaskEvent
+	query := r.db.WithContext(ctx).Where(&models.TaskEvent{TaskID: taskID})
+	if ownerID != models.AdminID {
+		query = query.Where(&models.TaskEvent{OwnerID: ownerID})
+	}
+	if afterID > 0 {
+		query = query.Where(clause.Gt{Column: models.TaskEventIDField, Value: afterID})
+	}
+
+	if opts != nil {
+		if opts.Limit > 0 {
+			query = query.Limit(opts.Limit)
+		}
+		if opts.Offset > 0 {
+			query = query.Offset(opts.Offset)
+		}
+	}
+
+	err := query.Order(models.TaskEventIDField).Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task events: %w", err)
+	}
+	return events, nil
+}
+
+// ListLastEvents retrieves the last limit events of a task, oldest first
+func (r *TaskRepository) ListLastEvents(ctx context.Context, taskID uint, limit int) ([]models.TaskEvent, error) {
+	if taskID == 0 {
+		return nil, fmt.Errorf("taskID cannot be zero")
+	}
+
+	var events []models.TaskEvent
+	err := r.db.WithContext(ctx).
+		Where(&models.TaskEvent{TaskID: taskID}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: models.TaskEventIDField}, Desc: true}).
+		Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task events: %w", err)
+	}
+	slices.Reverse(events)
+	return events, nil
+}
+
+// ListProjectEvents retrieves the events of the tasks of a project after the event afterID,
+// oldest first, with pagination
+func (r *TaskRepository) ListProjectEvents(ctx context.Context, ownerID, projectID, afterID uint, opts *models.ListOptions) ([]models.TaskEvent, error) {
+	if err := models.ValidateOwnerID(ownerID); err != nil {
+		return nil, fmt.Errorf("invalid owner_id: %w", err)
+	}
+	if projectID == 0 {
+		return nil, fmt.Errorf("projectID cannot be zero")
+	}
+
+	var events []models.TaskEvent
+	query := r.projectEvents(ctx, ownerID, projectID)
+	if afterID > 0 {
+		query = query.Where(clause.Gt{Column: clause.Column{Table: taskEventsTable, Name: models.TaskEventIDField}, Value: afterID})
+	}
+
+	if opts != nil {
+		if opts.Limit > 0 {
+			query = query.Limit(opts.Limit)
+		}
+		if opts.Offset > 0 {
+			query = query.Offset(opts.Offset)
+		}
+	}
+
+	err := query.Order(clause.OrderByColumn{Column: clause.Column{Table: taskEventsTable, Name: models.TaskEventIDField}}).
+		Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query project task events: %w", err)
+	}
+	return events, nil
+}
+
+// LastProjectEventID returns the ID of the latest event of the tasks of a project, 0 if there is none
+func (r *TaskRepository) LastProjectEventID(ctx context.Context, ownerID, projectID uint) (uint, error) {
+	if err := models.ValidateOwnerID(ownerID); err != nil {
+		return 0, fmt.Errorf("invalid owner_id: %w", err)
+	}
+
+	var lastID uint
+	err := r.projectEvents(ctx, ownerID, projectID).
+		Select(fmt.Sprintf("COALESCE(MAX(%s.%s), 0)", taskEventsTable, models.TaskEventIDField)).
+		Scan(&lastID).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to query last project task event: %w", err)
+	}
+	return lastID, nil
+}
+
+// taskEventsTable is the table of the task events, used to qualify columns when joining tasks
+const taskEventsTable = "task_events"
+
+// projectEvents returns a query of the events of the tasks of a project
+func (r *TaskRepository) projectEvents(ctx context.Context, ownerID, projectID uint) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.TaskEvent{}).
+		Joins("JOIN tasks ON tasks.id = task_events.task_id").
+		Where("tasks.project_id = ?", projectID)
+	if ownerID != models.AdminID {
+		query = query.Where(clause.Eq{Column: clause.Column{Table: taskEventsTable, Name: models.TaskEventOwnerIDField}, Value: ownerID})
+	}
+	return query
+}
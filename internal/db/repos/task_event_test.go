@@ -0,0 +1,89 @@
+package repos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+)
+
+type TaskEventRepositoryTestSuite struct {
+	DBRepositoryTestSuite
+}
+
+func (s *TaskEventRepositoryTestSuite) TestAddEvents() {
+	task := s.createTestTask()
+
+	event := &models.TaskEvent{TaskID: task.ID, OwnerID: task.OwnerID, Message: "no level"}
+	s.Require().NoError(s.taskRepo.AddEvents(s.ctx, event))
+	s.Require().NotZero(event.ID)
+	s.Require().Equal(models.TaskEventInfo, event.Level)
+
+	// Nothing to add
+	s.Require().NoError(s.taskRepo.AddEvents(s.ctx))
+
+	s.Require().Error(s.taskRepo.AddEvents(s.ctx, nil))
+	s.Require().Error(s.taskRepo.AddEvents(s.ctx, &models.TaskEvent{OwnerID: task.OwnerID}))
+}
+
+func (s *TaskEventRepositoryTestSuite) TestListEvents() {
+	task := s.createTestTask()
+	other := s.createTestTask()
+
+	events := []*models.TaskEvent{
+		{TaskID: task.ID, OwnerID: task.OwnerID, Step: models.TaskStepCreate, Message: "first"},
+		{TaskID: task.ID, OwnerID: task.OwnerID, Level: models.TaskEventError, Message: "second",
+			Fields: models.TaskEventFields{"attempt": 1}},
+		{TaskID: other.ID, OwnerID: other.OwnerID, Message: "other"},
+		{TaskID: task.ID, OwnerID: task.OwnerID, Message: "third"},
+	}
+	s.Require().NoError(s.taskRepo.AddEvents(s.ctx, events...))
+
+	listed, err := s.taskRepo.ListEvents(s.ctx, task.OwnerID, task.ID, 0, nil)
+	s.Require().NoError(err)
+	s.Require().Len(listed, 3)
+	s.Require().Equal("first", listed[0].Message)
+	s.Require().Equal(models.TaskStepCreate, listed[0].Step)
+	s.Require().Equal(models.TaskEventError, listed[1].Level)
+	s.Require().EqualValues(1, listed[1].Fields["attempt"])
+	s.Require().Equal("third", listed[2].Message)
+
+	// Continue after the first event
+	listed, err = s.taskRepo.ListEvents(s.ctx, task.OwnerID, task.ID, events[0].ID, nil)
+	s.Require().NoError(err)
+	s.Require().Len(listed, 2)
+	s.Require().Equal("second", listed[0].Message)
+
+	// Pagination
+	listed, err = s.taskRepo.ListEvents(s.ctx, task.OwnerID, task.ID, 0, &models.ListOptions{Limit: 1, Offset: 2})
+	s.Require().NoError(err)
+	s.Require().Len(listed, 1)
+	s.Require().Equal("third", listed[0].Message)
+
+	// Another owner cannot see the events
+	listed, err = s.taskRepo.ListEvents(s.ctx, task.OwnerID+1000, task.ID, 0, nil)
+	s.Require().NoError(err)
+	s.Require().Empty(listed)
+
+	// Admin sees all events of the task
+	listed, err = s.taskRepo.ListEvents(s.ctx, models.AdminID, task.ID, 0, nil)
+	s.Require().NoError(err)
+	s.Require().Len(listed, 3)
+
+	_, err = s.taskRepo.ListEvents(s.ctx, task.OwnerID, 0, 0, nil)
+	s.Require().Error(err)
+
+	last, err := s.taskRepo.ListLastEvents(s.ctx, task.ID, 2)
+	s.Require().NoError(err)
+	s.Require().Len(last, 2)
+	s.Require().Equal("second", last[0].Message)
+	s.Require().Equal("third", last[1].Message)
+
+	_, err = s.taskRepo.ListLastEvents(s.ctx, 0, 2)
+	s.Require().Error(err)
+}
+
+func TestTaskEventRepository(t *testing.T) {
+	suite.Run(t, new(TaskEventRepositoryTestSuite))
+}
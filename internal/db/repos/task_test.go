@@ -140,6 +140,191 @@ func (s *TaskRepositoryTestSuite) TestUpdateTaskStatus() {
 	s.Require().NoError(err) // Expect no error even if owner ID is invalid
 }
 
+func (s *TaskRepositoryTestSuite) TestTerminateTask() {
+	// Create a pending test task
+	task := s.createTestTask()
+
+	// Terminate the task
+	terminated, err := s.taskRepo.Terminate(s.ctx, task.OwnerID, task.ID)
+	s.Require().NoError(err)
+	s.Require().True(terminated)
+
+	updatedTask, err := s.taskRepo.GetByID(s.ctx, task.OwnerID, task.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(models.TaskStatusTerminated, updatedTask.Status)
+
+	// A terminated task can no longer be locked
+	locked, err := s.taskRepo.AcquireTaskLock(s.ctx, task.ID, "worker-1")
+	s.Require().NoError(err)
+	s.Require().False(locked)
+
+	// Finished tasks are not terminated again
+	terminated, err = s.taskRepo.Terminate(s.ctx, task.OwnerID, task.ID)
+	s.Require().NoError(err)
+	s.Require().False(terminated)
+
+	completedTask := s.createTestTask()
+	s.Require().NoError(s.taskRepo.UpdateStatus(s.ctx, completedTask.OwnerID, completedTask.ID, models.TaskStatusCompleted))
+	terminated, err = s.taskRepo.Terminate(s.ctx, completedTask.OwnerID, completedTask.ID)
+	s.Require().NoError(err)
+	s.Require().False(terminated)
+
+	completedTask, err = s.taskRepo.GetByID(s.ctx, completedTask.OwnerID, completedTask.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(models.TaskStatusCompleted, completedTask.Status)
+}
+
+func (s *TaskRepositoryTestSuite) TestRenewTaskLock() {
+	task := s.createTestTask()
+
+	locked, err := s.taskRepo.AcquireTaskLock(s.ctx, task.ID, "worker-1")
+	s.Require().NoError(err)
+	s.Require().True(locked)
+
+	// The lock holder extends its lease, other workers cannot
+	s.Require().NoError(s.db.Model(task).Update(models.TaskLockExpiryField, time.Now().Add(time.Second)).Error)
+	renewed, err := s.taskRepo.RenewTaskLock(s.ctx, task.ID, "worker-1")
+	s.Require().NoError(err)
+	s.Require().True(renewed)
+	renewed, err = s.taskRepo.RenewTaskLock(s.ctx, task.ID, "worker-2")
+	s.Require().NoError(err)
+	s.Require().False(renewed)
+
+	lockedTask, err := s.taskRepo.GetByID(s.ctx, task.OwnerID, task.ID)
+	s.Require().NoError(err)
+	s.Require().WithinDuration(time.Now().Add(models.TaskLockTimeout), *lockedTask.LockExpiry, time.Minute)
+
+	// A renewed lock is not taken over
+	locked, err = s.taskRepo.AcquireTaskLock(s.ctx, task.ID, "worker-2")
+	s.Require().NoError(err)
+	s.Require().False(locked)
+
+	// Once the lease expired another worker takes over and the first worker loses the lock
+	s.Require().NoError(s.db.Model(task).Update(models.TaskLockExpiryField, time.Now().Add(-time.Second)).Error)
+	locked, err = s.taskRepo.AcquireTaskLock(s.ctx, task.ID, "worker-2")
+	s.Require().NoError(err)
+	s.Require().True(locked)
+	renewed, err = s.taskRepo.RenewTaskLock(s.ctx, task.ID, "worker-1")
+	s.Require().NoError(err)
+	s.Require().False(renewed)
+
+	// Releasing a lost lock leaves the lock of the new holder in place
+	s.Require().NoError(s.taskRepo.ReleaseTaskLock(s.ctx, task.ID, "worker-1"))
+	lockedTask, err = s.taskRepo.GetByID(s.ctx, task.OwnerID, task.ID)
+	s.Require().NoError(err)
+	s.Require().Equal("worker-2", lockedTask.WorkerID)
+	s.Require().NotNil(lockedTask.LockedAt)
+
+	s.Require().NoError(s.taskRepo.ReleaseTaskLock(s.ctx, task.ID, "worker-2"))
+	lockedTask, err = s.taskRepo.GetByID(s.ctx, task.OwnerID, task.ID)
+	s.Require().NoError(err)
+	s.Require().Empty(lockedTask.WorkerID)
+	s.Require().Nil(lockedTask.LockedAt)
+
+	// A released lock cannot be renewed
+	renewed, err = s.taskRepo.RenewTaskLock(s.ctx, task.ID, "worker-2")
+	s.Require().NoError(err)
+	s.Require().False(renewed)
+}
+
+func (s *TaskRepositoryTestSuite) TestRecoverStaleTasks() {
+	project := s.createTestProject()
+	workerRepo := NewWorkerRepository(s.db)
+	now := time.Now()
+	expiredLock := now.Add(-time.Minute)
+	heldLock := now.Add(time.Hour)
+
+	// One worker is alive, the other one stopped sending heartbeats
+	s.Require().NoError(workerRepo.Heartbeat(s.ctx, &models.Worker{ID: "alive"}))
+	s.Require().NoError(workerRepo.Heartbeat(s.ctx, &models.Worker{ID: "dead"}))
+	s.Require().NoError(s.db.Model(&models.Worker{ID: "dead"}).
+		Update(models.WorkerHeartbeatAtField, now.Add(-time.Hour)).Error)
+
+	runningTask := func(workerID string, lockExpiry time.Time) *models.Task {
+		task := s.randomTask(project.OwnerID, project.ID, 1)
+		task.Status = models.TaskStatusRunning
+		task.WorkerID = workerID
+		task.LockedAt = &now
+		task.LockExpiry = &lockExpiry
+		s.Require().NoError(s.taskRepo.Create(s.ctx, task))
+		return task
+	}
+	aliveTask := runningTask("alive", heldLock)
+	aliveExpiredTask := runningTask("alive", expiredLock)
+	deadTask := runningTask("dead", heldLock)
+	unknownTask := runningTask("unregistered", heldLock)
+	unownedTask := runningTask("", expiredLock)
+	unownedHeldTask := runningTask("", heldLock)
+
+	recoveredIDs, err := s.taskRepo.RecoverStaleTasks(s.ctx, now.Add(-models.WorkerHeartbeatTimeout))
+	s.Require().NoError(err)
+	s.Require().ElementsMatch([]uint{deadTask.ID, unknownTask.ID, unownedTask.ID}, recoveredIDs)
+
+	for _, task := range []*models.Task{deadTask, unknownTask, unownedTask} {
+		recovered, err := s.taskRepo.GetByID(s.ctx, task.OwnerID, task.ID)
+		s.Require().NoError(err)
+		s.Require().Equal(models.TaskStatusPending, recovered.Status, "task of worker %q", task.WorkerID)
+		s.Require().Empty(recovered.WorkerID)
+		s.Require().Nil(recovered.LockedAt)
+
+		events, err := s.taskRepo.ListEvents(s.ctx, task.OwnerID, task.ID, 0, nil)
+		s.Require().NoError(err)
+		s.Require().Len(events, 1)
+		s.Require().Equal(models.TaskEventWarn, events[0].Level)
+	}
+	// Tasks of live workers are kept even if their lock expired
+	for _, task := range []*models.Task{aliveTask, aliveExpiredTask, unownedHeldTask} {
+		kept, err := s.taskRepo.GetByID(s.ctx, task.OwnerID, task.ID)
+		s.Require().NoError(err)
+		s.Require().Equal(models.TaskStatusRunning, kept.Status, "task of worker %q", task.WorkerID)
+		s.Require().Equal(task.WorkerID, kept.WorkerID)
+	}
+}
+
+func (s *TaskRepositoryTestSuite) TestListAndRequeueDeadTasks() {
+	project := s.createTestProject()
+	retryAt := time.Now().Add(time.Hour)
+
+	deadTask := s.randomTask(project.OwnerID, project.ID, 1)
+	deadTask.Status = models.TaskStatusDead
+	deadTask.Attempts = 5
+	deadTask.NextRunAt = &retryAt
+	deadTask.Logs = "failed"
+	s.Require().NoError(s.taskRepo.Create(s.ctx, deadTask))
+	pendingTask := s.createTestTaskForProject(project.OwnerID, project.ID, 2)
+
+	deadTasks, err := s.taskRepo.ListByStatus(s.ctx, models.TaskStatusDead, nil)
+	s.Require().NoError(err)
+	s.Require().Len(deadTasks, 1)
+	s.Require().Equal(deadTask.ID, deadTasks[0].ID)
+
+	// Requeue the dead task
+	requeued, err := s.taskRepo.Requeue(s.ctx, deadTask.ID)
+	s.Require().NoError(err)
+	s.Require().True(requeued)
+
+	requeuedTask, err := s.taskRepo.GetByID(s.ctx, deadTask.OwnerID, deadTask.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(models.TaskStatusPending, requeuedTask.Status)
+	s.Require().Zero(requeuedTask.Attempts)
+	s.Require().Nil(requeuedTask.NextRunAt)
+	s.Require().Equal("failed", requeuedTask.Logs)
+
+	events, err := s.taskRepo.ListEvents(s.ctx, deadTask.OwnerID, deadTask.ID, 0, nil)
+	s.Require().NoError(err)
+	s.Require().Len(events, 1)
+	s.Require().Contains(events[0].Message, "requeued by an admin")
+
+	// Only dead tasks can be requeued
+	requeued, err = s.taskRepo.Requeue(s.ctx, pendingTask.ID)
+	s.Require().NoError(err)
+	s.Require().False(requeued)
+
+	deadTasks, err = s.taskRepo.ListByStatus(s.ctx, models.TaskStatusDead, nil)
+	s.Require().NoError(err)
+	s.Require().Empty(deadTasks)
+}
+
 func (s *TaskRepositoryTestSuite) TestUpdateTask() {
 	// Create a test task
 	task := s.createTestTask()
@@ -148,7 +333,7 @@ func (s *TaskRepositoryTestSuite) TestUpdateTask() {
 	task.Logs = "Updated logs"
 	task.Status = models.TaskStatusRunning
 	task.Error = "Test error"
-	task.Result = []byte(`{"key":"value"}`)
+	task.Result = &models.TaskResult{InstanceID: 1, PublicIP: "192.0.2.1"}
 
 	// Test updating the task
 	err := s.taskRepo.Update(s.ctx, task.OwnerID, task)
@@ -157,9 +342,10 @@ func (s *TaskRepositoryTestSuite) TestUpdateTask() {
 	// Verify task was updated
 	updatedTask, err := s.taskRepo.GetByID(s.ctx, task.OwnerID, task.ID)
 	s.Require().NoError(err)
-	s.Require().Equal(task.Logs, updatedTask.Logs)
+	s.Require().Empty(updatedTask.Logs, "logs are only written as task events")
 	s.Require().Equal(task.Status, updatedTask.Status)
 	s.Require().Equal(task.Error, updatedTask.Error)
+	s.Require().Equal(task.Result, updatedTask.Result)
 
 	// Test with invalid owner ID
 	invalidOwnerID := uint(999)
@@ -180,6 +366,7 @@ func (s *TaskRepositoryTestSuite) TestGetSchedulableTasks() {
 	project := s.createTestProject() // Use a common project for these tasks
 	ownerID := project.OwnerID
 	now := time.Now()
+	retryAt := now.Add(time.Hour)
 
 	// Seed tasks with different statuses, error presence, and creation times
 	tasksToCreate := []models.Task{
@@ -193,8 +380,10 @@ func (s *TaskRepositoryTestSuite) TestGetSchedulableTasks() {
 		// Should be included - With Error, ordered by CreatedAt ASC
 		{ProjectID: project.ID, OwnerID: ownerID, Status: models.TaskStatusFailed, Error: "Some error", CreatedAt: now.Add(-5 * time.Minute), Action: models.TaskActionCreateInstances, Priority: models.TaskPriorityHigh},    // Expected 4th
 		{ProjectID: project.ID, OwnerID: ownerID, Status: models.TaskStatusFailed, Error: "Another error", CreatedAt: now.Add(-4 * time.Minute), Action: models.TaskActionCreateInstances, Priority: models.TaskPriorityHigh}, // Expected 5th (if limit allows)
-		// Should be excluded - Exceeds maxAttempts
-		{ProjectID: project.ID, OwnerID: ownerID, Status: models.TaskStatusFailed, Error: "Too many attempts", Attempts: maxAttempts, CreatedAt: now.Add(-3 * time.Minute), Action: models.TaskActionCreateInstances, Priority: models.TaskPriorityHigh},
+		// Should be excluded - Used up its retries
+		{ProjectID: project.ID, OwnerID: ownerID, Status: models.TaskStatusDead, Error: "Too many attempts", Attempts: 10, CreatedAt: now.Add(-3 * time.Minute), Action: models.TaskActionCreateInstances, Priority: models.TaskPriorityHigh},
+		// Should be excluded - Waiting for its retry
+		{ProjectID: project.ID, OwnerID: ownerID, Status: models.TaskStatusPending, Error: "Retryable error", Attempts: 1, NextRunAt: &retryAt, CreatedAt: now.Add(-2 * time.Minute), Action: models.TaskActionCreateInstances, Priority: models.TaskPriorityHigh},
 	}
 
 	createdTasksWithIDs := make([]models.Task, 0, len(tasksToCreate))
@@ -208,7 +397,7 @@ func (s *TaskRepositoryTestSuite) TestGetSchedulableTasks() {
 
 	// --- Test Case 1: Limit = 4 ---
 	limit := 4
-	schedulableTasks, err := s.taskRepo.GetSchedulableTasks(s.ctx, models.TaskPriorityHigh, limit)
+	schedulableTasks, err := s.taskRepo.GetSchedulableTasks(s.ctx, models.TaskPriorityHigh, limit, nil)
 	s.Require().NoError(err)
 	s.Require().Len(schedulableTasks, 3, "Expected 3 schedulable tasks for limit 4")
 	if len(schedulableTasks) > 1 {
@@ -217,7 +406,7 @@ func (s *TaskRepositoryTestSuite) TestGetSchedulableTasks() {
 
 	// --- Test Case 2: Limit = 2 (Testing limit and no-error ordering) ---
 	limit = 2
-	schedulableTasks, err = s.taskRepo.GetSchedulableTasks(s.ctx, models.TaskPriorityHigh, limit)
+	schedulableTasks, err = s.taskRepo.GetSchedulableTasks(s.ctx, models.TaskPriorityHigh, limit, nil)
 	s.Require().NoError(err)
 	s.Require().Len(schedulableTasks, 2, "Expected 2 schedulable tasks for limit 2")
 	if len(schedulableTasks) == 2 {
@@ -228,7 +417,7 @@ func (s *TaskRepositoryTestSuite) TestGetSchedulableTasks() {
 
 	// --- Test Case 3: Limit = 10 (Testing retrieval of all eligible tasks) ---
 	limit = 10 // Higher than eligible tasks (3 are eligible: all with no error)
-	schedulableTasks, err = s.taskRepo.GetSchedulableTasks(s.ctx, models.TaskPriorityHigh, limit)
+	schedulableTasks, err = s.taskRepo.GetSchedulableTasks(s.ctx, models.TaskPriorityHigh, limit, nil)
 	s.Require().NoError(err)
 	s.Require().Len(schedulableTasks, 3, "Expected all 3 eligible schedulable tasks")
 	// All tasks should have no error since TaskStatusFailed tasks are no longer included
@@ -236,21 +425,139 @@ func (s *TaskRepositoryTestSuite) TestGetSchedulableTasks() {
 		s.Assert().Empty(task.Error, "All tasks should have no error")
 	}
 
-	// --- Test Case 4: Verify task with maxAttempts is excluded ---
-	// This is implicitly tested if count for limit 10 is 5 (as task-max-attempts is not one of them).
-	foundMaxAttemptsTask := false
+	// --- Test Case 4: Verify dead tasks and tasks waiting for their retry are excluded ---
 	for _, task := range schedulableTasks {
-		if task.Attempts >= maxAttempts { // Check actual name if needed, but this check is more direct
-			// Find the original task with maxAttempts to compare ID if necessary
-			for _, originalTask := range createdTasksWithIDs {
-				if originalTask.Attempts >= maxAttempts && task.ID == originalTask.ID {
-					foundMaxAttemptsTask = true
-					break
-				}
-			}
+		s.Assert().NotEqual(models.TaskStatusDead, task.Status, "Dead tasks should be excluded")
+		s.Assert().Nil(task.NextRunAt, "Tasks waiting for their retry should be excluded")
+	}
+
+	// A task becomes schedulable again once its retry time has passed
+	retryTask := createdTasksWithIDs[len(createdTasksWithIDs)-1]
+	dueAt := time.Now().Add(-time.Second)
+	retryTask.NextRunAt = &dueAt
+	s.Require().NoError(s.taskRepo.Update(s.ctx, retryTask.OwnerID, &retryTask))
+	schedulableTasks, err = s.taskRepo.GetSchedulableTasks(s.ctx, models.TaskPriorityHigh, limit, nil)
+	s.Require().NoError(err)
+	s.Require().Len(schedulableTasks, 4, "Expected the due retry to be schedulable")
+}
+
+func (s *TaskRepositoryTestSuite) TestGetSchedulableTasks_RoundRobin() {
+	// One owner queued many tasks before two others queued theirs
+	busyOwner := s.createTestProject()
+	otherOwner := s.createTestProject()
+	thirdOwner := s.createTestProject()
+
+	createTasks := func(project *models.Project, provider models.ProviderID, n int) []uint {
+		ids := make([]uint, 0, n)
+		for i := 0; i < n; i++ {
+			task := s.randomTask(project.OwnerID, project.ID, 1)
+			task.ProviderID = provider
+			task.Priority = models.TaskPriorityHigh
+			s.Require().NoError(s.taskRepo.Create(s.ctx, task))
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+	busyTasks := createTasks(busyOwner, models.ProviderDO, 5)
+	otherTasks := createTasks(otherOwner, models.ProviderDO, 2)
+	thirdTasks := createTasks(thirdOwner, models.ProviderLinode, 1)
+
+	taskIDs := func(tasks []models.Task) []uint {
+		ids := make([]uint, 0, len(tasks))
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
 		}
+		return ids
+	}
+
+	// The oldest task of every owner comes first, then the second oldest and so on
+	tasks, err := s.taskRepo.GetSchedulableTasks(s.ctx, models.TaskPriorityHigh, 5, nil)
+	s.Require().NoError(err)
+	s.Require().Equal([]uint{busyTasks[0], otherTasks[0], thirdTasks[0], busyTasks[1], otherTasks[1]}, taskIDs(tasks))
+
+	// Excluded tasks, owners, projects and providers are left out
+	tasks, err = s.taskRepo.GetSchedulableTasks(s.ctx, models.TaskPriorityHigh, 10, &models.TaskSchedulingFilter{
+		ExcludeTaskIDs:  []uint{busyTasks[0]},
+		ExcludeOwnerIDs: []uint{otherOwner.OwnerID},
+	})
+	s.Require().NoError(err)
+	s.Require().Equal([]uint{busyTasks[1], thirdTasks[0], busyTasks[2], busyTasks[3], busyTasks[4]}, taskIDs(tasks))
+
+	tasks, err = s.taskRepo.GetSchedulableTasks(s.ctx, models.TaskPriorityHigh, 10, &models.TaskSchedulingFilter{
+		ExcludeProjectIDs:  []uint{thirdOwner.ID},
+		ExcludeProviderIDs: []models.ProviderID{models.ProviderDO},
+	})
+	s.Require().NoError(err)
+	s.Require().Empty(tasks)
+
+	// Tasks without a provider are not left out by the provider exclusions
+	legacyTask := s.randomTask(thirdOwner.OwnerID, thirdOwner.ID, 1)
+	legacyTask.Priority = models.TaskPriorityHigh
+	s.Require().NoError(s.taskRepo.Create(s.ctx, legacyTask))
+	s.Require().NoError(s.db.Model(legacyTask).Update(models.TaskProviderIDField, nil).Error)
+	tasks, err = s.taskRepo.GetSchedulableTasks(s.ctx, models.TaskPriorityHigh, 10, &models.TaskSchedulingFilter{
+		ExcludeOwnerIDs:    []uint{busyOwner.OwnerID, otherOwner.OwnerID},
+		ExcludeProviderIDs: []models.ProviderID{models.ProviderLinode},
+	})
+	s.Require().NoError(err)
+	s.Require().Equal([]uint{legacyTask.ID}, taskIDs(tasks))
+}
+
+func (s *TaskRepositoryTestSuite) TestGetNextRunAt() {
+	project := s.createTestProject()
+
+	nextRunAt, err := s.taskRepo.GetNextRunAt(s.ctx, models.TaskPriorityHigh)
+	s.Require().NoError(err)
+	s.Require().Nil(nextRunAt, "no task is waiting for a retry")
+
+	now := time.Now()
+	retryTask := func(priority models.TaskPriority, status models.TaskStatus, runAt time.Time) {
+		task := s.randomTask(project.OwnerID, project.ID, 1)
+		task.Priority = priority
+		task.Status = status
+		task.NextRunAt = &runAt
+		s.Require().NoError(s.taskRepo.Create(s.ctx, task))
+	}
+	retryTask(models.TaskPriorityHigh, models.TaskStatusPending, now.Add(time.Hour))
+	retryTask(models.TaskPriorityHigh, models.TaskStatusPending, now.Add(time.Minute))
+	// Not waiting: another priority, a finished task and a retry that is due already
+	retryTask(models.TaskPriorityLow, models.TaskStatusPending, now.Add(time.Second))
+	retryTask(models.TaskPriorityHigh, models.TaskStatusDead, now.Add(time.Second))
+	retryTask(models.TaskPriorityHigh, models.TaskStatusPending, now.Add(-time.Minute))
+
+	nextRunAt, err = s.taskRepo.GetNextRunAt(s.ctx, models.TaskPriorityHigh)
+	s.Require().NoError(err)
+	s.Require().NotNil(nextRunAt)
+	s.Require().WithinDuration(now.Add(time.Minute), *nextRunAt, time.Second)
+}
+
+func (s *TaskRepositoryTestSuite) TestCountRunningTasks() {
+	project := s.createTestProject()
+	now := time.Now()
+
+	runningTask := func(workerID string, provider models.ProviderID, lockExpiry time.Time) {
+		task := s.randomTask(project.OwnerID, project.ID, 1)
+		task.Status = models.TaskStatusRunning
+		task.ProviderID = provider
+		task.WorkerID = workerID
+		task.LockedAt = &now
+		task.LockExpiry = &lockExpiry
+		s.Require().NoError(s.taskRepo.Create(s.ctx, task))
 	}
-	s.Assert().False(foundMaxAttemptsTask, "Task with max attempts should be excluded")
+	runningTask("worker-1", models.ProviderDO, now.Add(time.Minute))
+	runningTask("worker-2", models.ProviderDO, now.Add(time.Minute))
+	runningTask("worker-2", models.ProviderLinode, now.Add(time.Minute))
+	// Not counted: expired lock, the excluded worker and a pending task
+	runningTask("worker-2", models.ProviderDO, now.Add(-time.Minute))
+	runningTask("self", models.ProviderDO, now.Add(time.Minute))
+	s.createTestTaskForProject(project.OwnerID, project.ID, 1)
+
+	counts, err := s.taskRepo.CountRunningTasks(s.ctx, "self")
+	s.Require().NoError(err)
+	s.Require().ElementsMatch([]models.RunningTaskCount{
+		{OwnerID: project.OwnerID, ProjectID: project.ID, ProviderID: models.ProviderDO, Count: 2},
+		{OwnerID: project.OwnerID, ProjectID: project.ID, ProviderID: models.ProviderLinode, Count: 1},
+	}, counts)
 }
 
 func (s *TaskRepositoryTestSuite) TestListByInstanceID() {
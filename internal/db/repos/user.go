@@ -90,6 +90,24 @@ func (r *UserRepository) GetUsers(ctx context.Context, opts *models.ListOptions)
 	return users, err
 }
 
+// UpdateWebhook sets the webhook URL and secret of a user
+// Returns ErrRecordNotFound if the user doesn't exist
+func (r *UserRepository) UpdateWebhook(ctx context.Context, userID uint, url, secret string) error {
+	result := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ?", userID).
+		Updates(map[string]interface{}{
+			"webhook_url":    url,
+			"webhook_secret": secret,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update user webhook: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user not found: %w", gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
 // DeleteUser deletes a user
 func (r *UserRepository) DeleteUser(ctx context.Context, userID uint) error {
 	var user models.User
@@ -115,3 +115,28 @@ func (s *UserRepositoryTestSuite) TestGetUserByID() {
 	s.Error(err)
 	s.Contains(err.Error(), "user not found")
 }
+
+func (s *UserRepositoryTestSuite) TestUpdateWebhook() {
+	user := s.createTestUser()
+
+	err := s.userRepo.UpdateWebhook(s.ctx, user.ID, "https://example.com/hook", "secret")
+	s.NoError(err)
+
+	found, err := s.userRepo.GetUserByID(s.ctx, user.ID)
+	s.NoError(err)
+	s.Equal("https://example.com/hook", found.WebhookURL)
+	s.Equal("secret", found.WebhookSecret)
+
+	// Test disabling the webhook
+	err = s.userRepo.UpdateWebhook(s.ctx, user.ID, "", "")
+	s.NoError(err)
+	found, err = s.userRepo.GetUserByID(s.ctx, user.ID)
+	s.NoError(err)
+	s.Empty(found.WebhookURL)
+	s.Empty(found.WebhookSecret)
+
+	// Test updating a non-existent user
+	err = s.userRepo.UpdateWebhook(s.ctx, 999999, "https://example.com/hook", "secret")
+	s.Error(err)
+	s.Contains(err.Error(), "user not found")
+}
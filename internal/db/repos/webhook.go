@@ -0,0 +1,138 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+)
+
+// WebhookRepository handles database operations for the webhook delivery outbox
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepository
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// CreateDelivery adds a delivery to the outbox
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	if delivery == nil {
+		return fmt.Errorf("webhook delivery cannot be nil")
+	}
+	if err := models.ValidateOwnerID(delivery.OwnerID); err != nil {
+		return fmt.Errorf("invalid owner_id: %w", err)
+	}
+	if delivery.Status == "" {
+		delivery.Status = models.WebhookDeliveryPending
+	}
+	return r.db.WithContext(ctx).Create(delivery).Error
+}
+
+// ClaimDue claims up to limit pending deliveries whose next attempt is due at now, oldest first.
+// A claimed delivery is not returned to other deliverers until lease has passed, so that a
+// delivery claimed by a deliverer that stopped is sent again.
+func (r *WebhookRepository) ClaimDue(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	due := func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&models.WebhookDelivery{}).
+			Where(
+				clause.Eq{Column: models.WebhookDeliveryStatusField, Value: models.WebhookDeliveryPending},
+				clause.Lte{Column: models.WebhookDeliveryNextAttemptAtField, Value: now},
+				clause.Or(
+					clause.Eq{Column: models.WebhookDeliveryLockedUntilField, Value: nil},
+					clause.Lt{Column: models.WebhookDeliveryLockedUntilField, Value: now},
+				),
+			)
+	}
+
+	var candidates []models.WebhookDelivery
+	err := due(r.db.WithContext(ctx)).
+		Order(models.WebhookDeliveryNextAttemptAtField).
+		Limit(limit).
+		Find(&candidates).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due webhook deliveries: %w", err)
+	}
+
+	// Another deliverer may claim a candidate between the query and the update,
+	// only the deliveries this deliverer updated are returned
+	lockedUntil := now.Add(lease)
+	claimed := make([]models.WebhookDelivery, 0, len(candidates))
+	for _, delivery := range candidates {
+		result := due(r.db.WithContext(ctx)).
+			Where(clause.Eq{Column: models.WebhookDeliveryIDField, Value: delivery.ID}).
+			Update(models.WebhookDeliveryLockedUntilField, lockedUntil)
+		if result.Error != nil {
+			return nil, fmt.Errorf("failed to claim webhook delivery %d: %w", delivery.ID, result.Error)
+		}
+		if result.RowsAffected > 0 {
+			delivery.LockedUntil = &lockedUntil
+			claimed = append(claimed, delivery)
+		}
+	}
+	return claimed, nil
+}
+
+// RecordAttempt stores an attempt to send a delivery together with the resulting state of the
+// delivery, and releases the claim on the delivery
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery, attempt *models.WebhookAttempt) error {
+	attempt.DeliveryID = delivery.ID
+	delivery.LockedUntil = nil
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(attempt).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.WebhookDelivery{Model: gorm.Model{ID: delivery.ID}}).
+			Updates(map[string]interface{}{
+				models.WebhookDeliveryStatusField:        delivery.Status,
+				models.WebhookDeliveryAttemptsField:      delivery.Attempts,
+				models.WebhookDeliveryNextAttemptAtField: delivery.NextAttemptAt,
+				models.WebhookDeliveryDeliveredAtField:   delivery.DeliveredAt,
+				models.WebhookDeliveryLockedUntilField:   nil,
+			}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record attempt of webhook delivery %d: %w", delivery.ID, err)
+	}
+	return nil
+}
+
+// List retrieves the deliveries of an owner with their attempts, newest first, with pagination.
+// Deliveries are filtered by task and instance when taskID or instanceID are set.
+func (r *WebhookRepository) List(ctx context.Context, ownerID, taskID, instanceID uint, opts *models.ListOptions) ([]models.WebhookDelivery, error) {
+	if err := models.ValidateOwnerID(ownerID); err != nil {
+		return nil, fmt.Errorf("invalid owner_id: %w", err)
+	}
+
+	var deliveries []models.WebhookDelivery
+	query := r.db.WithContext(ctx).
+		Where(&models.WebhookDelivery{TaskID: taskID, InstanceID: instanceID}).
+		Preload("AttemptLog", func(db *gorm.DB) *gorm.DB {
+			return db.Order(models.WebhookAttemptCreatedAtField)
+		})
+	if ownerID != models.AdminID {
+		query = query.Where(&models.WebhookDelivery{OwnerID: ownerID})
+	}
+
+	if opts != nil {
+		if opts.Limit > 0 {
+			query = query.Limit(opts.Limit)
+		}
+		if opts.Offset > 0 {
+			query = query.Offset(opts.Offset)
+		}
+	}
+
+	err := query.Order(models.WebhookDeliveryCreatedAtField + " DESC").Find(&deliveries).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
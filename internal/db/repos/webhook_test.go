@@ -0,0 +1,136 @@
+package repos
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+)
+
+type WebhookRepositoryTestSuite struct {
+	DBRepositoryTestSuite
+}
+
+func (s *WebhookRepositoryTestSuite) createTestDelivery(ownerID, taskID uint, nextAttemptAt time.Time) *models.WebhookDelivery {
+	delivery := &models.WebhookDelivery{
+		OwnerID:       ownerID,
+		Event:         models.WebhookEventTaskStatusChanged,
+		TaskID:        taskID,
+		URL:           "https://example.com/hook",
+		Payload:       json.RawMessage(`{}`),
+		NextAttemptAt: &nextAttemptAt,
+	}
+	s.Require().NoError(s.webhookRepo.CreateDelivery(s.ctx, delivery))
+	s.Require().NotZero(delivery.ID)
+	s.Require().Equal(models.WebhookDeliveryPending, delivery.Status)
+	return delivery
+}
+
+func (s *WebhookRepositoryTestSuite) TestCreateDeliveryNil() {
+	err := s.webhookRepo.CreateDelivery(s.ctx, nil)
+	s.Require().Error(err)
+}
+
+func (s *WebhookRepositoryTestSuite) TestClaimDue() {
+	now := time.Now()
+	ownerID := s.randomOwnerID()
+
+	due := s.createTestDelivery(ownerID, 1, now.Add(-time.Minute))
+	s.createTestDelivery(ownerID, 2, now.Add(time.Hour))
+
+	claimed, err := s.webhookRepo.ClaimDue(s.ctx, now, 10, time.Minute)
+	s.Require().NoError(err)
+	s.Require().Len(claimed, 1)
+	s.Require().Equal(due.ID, claimed[0].ID)
+	s.Require().NotNil(claimed[0].LockedUntil)
+
+	// A claimed delivery is not claimed again until its lease passed
+	claimed, err = s.webhookRepo.ClaimDue(s.ctx, now, 10, time.Minute)
+	s.Require().NoError(err)
+	s.Require().Empty(claimed)
+
+	claimed, err = s.webhookRepo.ClaimDue(s.ctx, now.Add(2*time.Minute), 10, time.Minute)
+	s.Require().NoError(err)
+	s.Require().Len(claimed, 1)
+	s.Require().Equal(due.ID, claimed[0].ID)
+}
+
+func (s *WebhookRepositoryTestSuite) TestRecordAttempt() {
+	now := time.Now()
+	ownerID := s.randomOwnerID()
+	delivery := s.createTestDelivery(ownerID, 1, now.Add(-time.Minute))
+
+	claimed, err := s.webhookRepo.ClaimDue(s.ctx, now, 10, time.Minute)
+	s.Require().NoError(err)
+	s.Require().Len(claimed, 1)
+
+	// A failed attempt schedules the next one and releases the claim
+	retryAt := now.Add(-time.Second)
+	delivery.Attempts = 1
+	delivery.NextAttemptAt = &retryAt
+	err = s.webhookRepo.RecordAttempt(s.ctx, delivery, &models.WebhookAttempt{StatusCode: 500, Error: "boom"})
+	s.Require().NoError(err)
+
+	claimed, err = s.webhookRepo.ClaimDue(s.ctx, now, 10, time.Minute)
+	s.Require().NoError(err)
+	s.Require().Len(claimed, 1)
+	s.Require().Equal(uint(1), claimed[0].Attempts)
+
+	// A delivered delivery is not claimed anymore
+	delivery.Attempts = 2
+	delivery.Status = models.WebhookDeliveryDelivered
+	delivery.NextAttemptAt = nil
+	delivery.DeliveredAt = &now
+	err = s.webhookRepo.RecordAttempt(s.ctx, delivery, &models.WebhookAttempt{StatusCode: 204})
+	s.Require().NoError(err)
+
+	claimed, err = s.webhookRepo.ClaimDue(s.ctx, now.Add(time.Hour), 10, time.Minute)
+	s.Require().NoError(err)
+	s.Require().Empty(claimed)
+
+	deliveries, err := s.webhookRepo.List(s.ctx, ownerID, 0, 0, nil)
+	s.Require().NoError(err)
+	s.Require().Len(deliveries, 1)
+	s.Require().Equal(models.WebhookDeliveryDelivered, deliveries[0].Status)
+	s.Require().NotNil(deliveries[0].DeliveredAt)
+	s.Require().Len(deliveries[0].AttemptLog, 2)
+	s.Require().Equal(500, deliveries[0].AttemptLog[0].StatusCode)
+	s.Require().Equal("boom", deliveries[0].AttemptLog[0].Error)
+	s.Require().Equal(204, deliveries[0].AttemptLog[1].StatusCode)
+}
+
+func (s *WebhookRepositoryTestSuite) TestList() {
+	now := time.Now()
+	ownerID := s.randomOwnerID()
+
+	first := s.createTestDelivery(ownerID, 1, now)
+	s.createTestDelivery(ownerID, 2, now)
+	s.createTestDelivery(ownerID+1000, 1, now)
+
+	deliveries, err := s.webhookRepo.List(s.ctx, ownerID, 0, 0, nil)
+	s.Require().NoError(err)
+	s.Require().Len(deliveries, 2)
+
+	// Filter by task
+	deliveries, err = s.webhookRepo.List(s.ctx, ownerID, 1, 0, nil)
+	s.Require().NoError(err)
+	s.Require().Len(deliveries, 1)
+	s.Require().Equal(first.ID, deliveries[0].ID)
+
+	// Admin sees the deliveries of all owners
+	deliveries, err = s.webhookRepo.List(s.ctx, models.AdminID, 1, 0, nil)
+	s.Require().NoError(err)
+	s.Require().Len(deliveries, 2)
+
+	// Pagination
+	deliveries, err = s.webhookRepo.List(s.ctx, ownerID, 0, 0, &models.ListOptions{Limit: 1})
+	s.Require().NoError(err)
+	s.Require().Len(deliveries, 1)
+}
+
+func TestWebhookRepository(t *testing.T) {
+	suite.Run(t, new(WebhookRepositoryTestSuite))
+}
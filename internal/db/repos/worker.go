@@ -0,0 +1,78 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+)
+
+// WorkerRepository handles database operations for the workers of the worker fleet
+type WorkerRepository struct {
+	db *gorm.DB
+}
+
+// NewWorkerRepository creates a new instance of WorkerRepository
+func NewWorkerRepository(db *gorm.DB) *WorkerRepository {
+	return &WorkerRepository{db: db}
+}
+
+// Heartbeat records a heartbeat of the worker, registering the worker if it is not registered yet
+func (r *WorkerRepository) Heartbeat(ctx context.Context, worker *models.Worker) error {
+	if worker == nil || worker.ID == "" {
+		return fmt.Errorf("worker ID cannot be empty")
+	}
+	worker.HeartbeatAt = time.Now()
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: models.WorkerIDField}},
+		DoUpdates: clause.AssignmentColumns([]string{models.WorkerHeartbeatAtField}),
+	}).Create(worker).Error
+	if err != nil {
+		return fmt.Errorf("failed to record heartbeat of worker %s: %w", worker.ID, err)
+	}
+	return nil
+}
+
+// Get retrieves a worker by ID
+func (r *WorkerRepository) Get(ctx context.Context, id string) (*models.Worker, error) {
+	var worker models.Worker
+	if err := r.db.WithContext(ctx).Where(&models.Worker{ID: id}).First(&worker).Error; err != nil {
+		return nil, err
+	}
+	return &worker, nil
+}
+
+// List retrieves all registered workers, the most recent heartbeat first
+func (r *WorkerRepository) List(ctx context.Context) ([]models.Worker, error) {
+	var workers []models.Worker
+	err := r.db.WithContext(ctx).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: models.WorkerHeartbeatAtField}, Desc: true}).
+		Find(&workers).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workers: %w", err)
+	}
+	return workers, nil
+}
+
+// Deregister removes a worker that is shutting down
+func (r *WorkerRepository) Deregister(ctx context.Context, id string) error {
+	if err := r.db.WithContext(ctx).Delete(&models.Worker{ID: id}).Error; err != nil {
+		return fmt.Errorf("failed to deregister worker %s: %w", id, err)
+	}
+	return nil
+}
+
+// DeleteExpired removes the workers whose last heartbeat is older than deadline
+func (r *WorkerRepository) DeleteExpired(ctx context.Context, deadline time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where(clause.Lt{Column: models.WorkerHeartbeatAtField, Value: deadline}).
+		Delete(&models.Worker{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired workers: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
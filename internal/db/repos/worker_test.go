@@ -0,0 +1,72 @@
+package repos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+)
+
+type WorkerRepositoryTestSuite struct {
+	DBRepositoryTestSuite
+	workerRepo *WorkerRepository
+}
+
+func (s *WorkerRepositoryTestSuite) SetupTest() {
+	s.DBRepositoryTestSuite.SetupTest()
+	s.workerRepo = NewWorkerRepository(s.db)
+}
+
+func (s *WorkerRepositoryTestSuite) TestHeartbeat() {
+	startedAt := time.Now().Add(-time.Hour)
+	worker := &models.Worker{ID: "host-1-abcd", Hostname: "host", PID: 1, StartedAt: startedAt}
+	s.Require().NoError(s.workerRepo.Heartbeat(s.ctx, worker))
+
+	registered, err := s.workerRepo.Get(s.ctx, worker.ID)
+	s.Require().NoError(err)
+	s.Require().Equal("host", registered.Hostname)
+	s.Require().WithinDuration(startedAt, registered.StartedAt, time.Second)
+	firstHeartbeat := registered.HeartbeatAt
+
+	// Further heartbeats only move the heartbeat time
+	time.Sleep(10 * time.Millisecond)
+	s.Require().NoError(s.workerRepo.Heartbeat(s.ctx, &models.Worker{ID: worker.ID}))
+	registered, err = s.workerRepo.Get(s.ctx, worker.ID)
+	s.Require().NoError(err)
+	s.Require().Equal("host", registered.Hostname)
+	s.Require().True(registered.HeartbeatAt.After(firstHeartbeat))
+
+	workers, err := s.workerRepo.List(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(workers, 1)
+
+	s.Require().Error(s.workerRepo.Heartbeat(s.ctx, &models.Worker{}))
+}
+
+func (s *WorkerRepositoryTestSuite) TestDeregisterAndDeleteExpired() {
+	for _, id := range []string{"stopping", "alive", "expired"} {
+		s.Require().NoError(s.workerRepo.Heartbeat(s.ctx, &models.Worker{ID: id}))
+	}
+	s.Require().NoError(s.db.Model(&models.Worker{ID: "expired"}).
+		Update(models.WorkerHeartbeatAtField, time.Now().Add(-time.Hour)).Error)
+
+	s.Require().NoError(s.workerRepo.Deregister(s.ctx, "stopping"))
+	_, err := s.workerRepo.Get(s.ctx, "stopping")
+	s.Require().ErrorIs(err, gorm.ErrRecordNotFound)
+
+	deleted, err := s.workerRepo.DeleteExpired(s.ctx, time.Now().Add(-models.WorkerHeartbeatTimeout))
+	s.Require().NoError(err)
+	s.Require().Equal(int64(1), deleted)
+
+	workers, err := s.workerRepo.List(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(workers, 1)
+	s.Require().Equal("alive", workers[0].ID)
+}
+
+func TestWorkerRepository(t *testing.T) {
+	suite.Run(t, new(WorkerRepositoryTestSuite))
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/celestiaorg/talis/internal/compute"
+	"github.com/celestiaorg/talis/internal/db/models"
+	"github.com/celestiaorg/talis/internal/logger"
+	"github.com/celestiaorg/talis/internal/types"
+)
+
+// DefaultCatalogTTL is the default time a provider catalog is cached for
+const DefaultCatalogTTL = time.Hour
+
+// catalogValidationTimeout bounds the catalog lookups of a single instance request validation
+const catalogValidationTimeout = 30 * time.Second
+
+// catalogKey identifies a cached catalog
+type catalogKey struct {
+	provider models.ProviderID
+	kind     types.CatalogKind
+}
+
+// catalogEntry is a cached catalog and the time it was fetched at
+type catalogEntry struct {
+	values    interface{}
+	fetchedAt time.Time
+}
+
+// Catalog provides the regions, sizes and images offered by the compute providers.
+// Catalogs are fetched from the provider on first use and cached for the configured TTL.
+// Catalog implements types.CatalogValidator so that instance requests with unknown values
+// are rejected before they are queued.
+type Catalog struct {
+	ttl time.Duration
+
+	providers *compute.ProviderRegistry
+	entries   map[catalogKey]catalogEntry
+	mu        sync.Mutex
+}
+
+// NewCatalogService creates a new Catalog caching the catalogs of the providers for ttl
+func NewCatalogService(providers *compute.ProviderRegistry, ttl time.Duration) *Catalog {
+	if ttl <= 0 {
+		ttl = DefaultCatalogTTL
+	}
+	return &Catalog{
+		ttl:       ttl,
+		providers: providers,
+		entries:   make(map[catalogKey]catalogEntry),
+	}
+}
+
+// Regions returns the regions offered by the provider
+func (c *Catalog) Regions(ctx context.Context, providerID models.ProviderID) ([]types.ProviderRegion, error) {
+	return cachedCatalog(ctx, c, providerID, types.CatalogRegions, compute.Provider.ListRegions)
+}
+
+// Sizes returns the instance sizes offered by the provider
+func (c *Catalog) Sizes(ctx context.Context, providerID models.ProviderID) ([]types.ProviderSize, error) {
+	return cachedCatalog(ctx, c, providerID, types.CatalogSizes, compute.Provider.ListSizes)
+}
+
+// Images returns the images offered by the provider
+func (c *Catalog) Images(ctx context.Context, providerID models.ProviderID) ([]types.ProviderImage, error) {
+	return cachedCatalog(ctx, c, providerID, types.CatalogImages, compute.Provider.ListImages)
+}
+
+// ValidateCatalogValues checks that the provider offers the region, size and image.
+// Empty values and empty catalogs are not checked. If a catalog cannot be fetched the
+// value is accepted, so that a provider API outage does not block instance requests.
+func (c *Catalog) ValidateCatalogValues(ctx context.Context, providerID models.ProviderID, region, size, image string) error {
+	ctx, cancel := context.WithTimeout(ctx, catalogValidationTimeout)
+	defer cancel()
+
+	if region != "" {
+		regions, err := c.Regions(ctx, providerID)
+		if err != nil {
+			logger.Warnf("⚠️ Skipping region validation for provider %s: %v", providerID, err)
+		} else if len(regions) > 0 && !containsCatalogID(regions, region, func(r types.ProviderRegion) string { return r.ID }) {
+			return fmt.Errorf("unknown region %q, list the available regions with 'talis providers regions --provider %s'", region, providerID)
+		}
+	}
+
+	if size != "" {
+		sizes, err := c.Sizes(ctx, providerID)
+		if err != nil {
+			logger.Warnf("⚠️ Skipping size validation for provider %s: %v", providerID, err)
+		} else if err := validateSize(sizes, size, region, providerID); err != nil {
+			return err
+		}
+	}
+
+	if image != "" {
+		if err := c.validateImage(ctx, providerID, image); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateImage checks that the provider lists or otherwise accepts the image
+func (c *Catalog) validateImage(ctx context.Context, providerID models.ProviderID, image string) error {
+	provider, err := c.providers.Get(providerID)
+	if err != nil {
+		logger.Warnf("⚠️ Skipping image validation for provider %s: %v", providerID, err)
+		return nil
+	}
+	if matcher, ok := provider.(compute.ImageMatcher); ok && matcher.MatchesImage(image) {
+		return nil
+	}
+
+	images, err := c.Images(ctx, providerID)
+	if err != nil {
+		logger.Warnf("⚠️ Skipping image validation for provider %s: %v", providerID, err)
+		return nil
+	}
+	if len(images) > 0 && !containsCatalogID(images, image, func(i types.ProviderImage) string { return i.ID }) {
+		return fmt.Errorf("unknown image %q, list the available images with 'talis providers images --provider %s'", image, providerID)
+	}
+	return nil
+}
+
+// validateSize checks that the size is listed and, if the size is restricted to some
+// regions, that it is offered in the region
+func validateSize(sizes []types.ProviderSize, size, region string, providerID models.ProviderID) error {
+	if len(sizes) == 0 {
+		return nil
+	}
+	for _, s := range sizes {
+		if s.ID != size {
+			continue
+		}
+		if region != "" && len(s.Regions) > 0 && !slices.Contains(s.Regions, region) {
+			return fmt.Errorf("size %q is not available in region %q", size, region)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown size %q, list the available sizes with 'talis providers sizes --provider %s'", size, providerID)
+}
+
+// cachedCatalog returns the cached catalog of the given kind, fetching it with list if it
+// is missing or expired. Failed fetches are not cached.
+func cachedCatalog[T any](
+	ctx context.Context,
+	c *Catalog,
+	providerID models.ProviderID,
+	kind types.CatalogKind,
+	list func(compute.Provider, context.Context) ([]T, error),
+) ([]T, error) {
+	key := catalogKey{provider: providerID, kind: kind}
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < c.ttl {
+		return entry.values.([]T), nil
+	}
+
+	provider, err := c.providers.Get(providerID)
+	if err != nil {
+		return nil, err
+	}
+	values, err := list(provider, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s of provider %s: %w", kind, providerID, err)
+	}
+	if values == nil {
+		values = []T{}
+	}
+
+	c.mu.Lock()
+	c.entries[key] = catalogEntry{values: values, fetchedAt: time.Now()}
+	c.mu.Unlock()
+	logger.Debugf("📚 Cached %d %s of provider %s", len(values), kind, providerID)
+	return values, nil
+}
+
+// containsCatalogID reports whether one of the catalog values has the given ID
+func containsCatalogID[T any](values []T, id string, idOf func(T) string) bool {
+	for _, v := range values {
+		if idOf(v) == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/linode/linodego"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/celestiaorg/talis/internal/compute"
+	"github.com/celestiaorg/talis/internal/constants"
+	"github.com/celestiaorg/talis/internal/db/models"
+	"github.com/celestiaorg/talis/internal/types"
+	"github.com/celestiaorg/talis/test/mocks"
+)
+
+// newTestCatalog creates a Catalog with mocked Linode and Vultr providers
+func newTestCatalog() (*Catalog, *mocks.MockLinodeClient, *mocks.MockVultrClient) {
+	catalog := NewCatalogService(compute.NewProviderRegistry(), DefaultCatalogTTL)
+
+	linodeClient := mocks.NewMockLinodeClient()
+	linodeProvider := &compute.LinodeProvider{}
+	linodeProvider.SetClient(linodeClient)
+	catalog.providers.Set(models.ProviderLinode, linodeProvider)
+
+	vultrClient := mocks.NewMockVultrClient()
+	vultrProvider := &compute.VultrProvider{}
+	vultrProvider.SetClient(vultrClient)
+	catalog.providers.Set(models.ProviderVultr, vultrProvider)
+
+	return catalog, linodeClient, vultrClient
+}
+
+func TestCatalog_Caching(t *testing.T) {
+	catalog, linodeClient, _ := newTestCatalog()
+	ctx := context.Background()
+
+	calls := 0
+	listRegions := linodeClient.ListRegionsFunc
+	linodeClient.ListRegionsFunc = func(ctx context.Context, opts *linodego.ListOptions) ([]linodego.Region, error) {
+		calls++
+		return listRegions(ctx, opts)
+	}
+
+	regions, err := catalog.Regions(ctx, models.ProviderLinode)
+	require.NoError(t, err)
+	assert.Len(t, regions, 3)
+	_, err = catalog.Regions(ctx, models.ProviderLinode)
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls, "regions should be served from the cache")
+
+	// Expired catalogs are fetched again
+	key := catalogKey{provider: models.ProviderLinode, kind: types.CatalogRegions}
+	catalog.entries[key] = catalogEntry{values: regions, fetchedAt: time.Now().Add(-2 * DefaultCatalogTTL)}
+	_, err = catalog.Regions(ctx, models.ProviderLinode)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	// Failed fetches are not cached
+	linodeClient.SimulateRateLimit()
+	_, err = catalog.Images(ctx, models.ProviderLinode)
+	assert.Error(t, err)
+	linodeClient.ResetToStandard()
+	images, err := catalog.Images(ctx, models.ProviderLinode)
+	require.NoError(t, err)
+	assert.NotEmpty(t, images)
+
+	_, err = catalog.Sizes(ctx, models.ProviderMock3)
+	assert.Error(t, err)
+}
+
+func TestCatalog_ValidateCatalogValues(t *testing.T) {
+	catalog, linodeClient, _ := newTestCatalog()
+
+	tests := []struct {
+		name     string
+		provider models.ProviderID
+		region   string
+		size     string
+		image    string
+		errMsg   string
+	}{
+		{
+			name:     "valid linode request",
+			provider: models.ProviderLinode,
+			region:   mocks.DefaultLinodeRegion, size: mocks.DefaultLinodeType, image: mocks.DefaultLinodeImage,
+		},
+		{
+			name:     "empty values are not checked",
+			provider: models.ProviderLinode,
+		},
+		{
+			name:     "unknown region",
+			provider: models.ProviderLinode,
+			region:   "mars-1", size: mocks.DefaultLinodeType, image: mocks.DefaultLinodeImage,
+			errMsg: `unknown region "mars-1"`,
+		},
+		{
+			name:     "unknown size",
+			provider: models.ProviderLinode,
+			region:   mocks.DefaultLinodeRegion, size: "g6-huge", image: mocks.DefaultLinodeImage,
+			errMsg: `unknown size "g6-huge"`,
+		},
+		{
+			name:     "deprecated image",
+			provider: models.ProviderLinode,
+			region:   mocks.DefaultLinodeRegion, size: mocks.DefaultLinodeType, image: "linode/ubuntu16.04lts",
+			errMsg: `unknown image "linode/ubuntu16.04lts"`,
+		},
+		{
+			name:     "size not offered in region",
+			provider: models.ProviderVultr,
+			region:   "fra", size: "vc2-2c-4gb", image: "1743",
+			errMsg: `size "vc2-2c-4gb" is not available in region "fra"`,
+		},
+		{
+			name:     "unknown os id",
+			provider: models.ProviderVultr,
+			region:   mocks.DefaultVultrRegion, size: mocks.DefaultVultrPlan, image: "9999",
+			errMsg: `unknown image "9999"`,
+		},
+		{
+			name:     "image accepted by the provider without being listed",
+			provider: models.ProviderVultr,
+			region:   mocks.DefaultVultrRegion, size: mocks.DefaultVultrPlan, image: "docker",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := catalog.ValidateCatalogValues(context.Background(), tt.provider, tt.region, tt.size, tt.image)
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+
+	t.Run("provider errors are ignored", func(t *testing.T) {
+		catalog, linodeClient, _ = newTestCatalog()
+		linodeClient.SimulateRateLimit()
+		assert.NoError(t, catalog.ValidateCatalogValues(context.Background(), models.ProviderLinode, "mars-1", "g6-huge", "windows"))
+		assert.NoError(t, catalog.ValidateCatalogValues(context.Background(), models.ProviderMock3, "mock", "mock", "mock"))
+	})
+}
+
+func TestCatalog_RejectsInstanceRequestBeforeQueuing(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+	t.Setenv(constants.EnvTalisSSHKeyName, "test-key")
+
+	ts.InstanceService.providers.Set(models.ProviderDO, mocks.NewMockDOClient())
+	ts.InstanceService.WithCatalogValidator(NewCatalogService(ts.InstanceService.providers, DefaultCatalogTTL))
+
+	project := &models.Project{OwnerID: 1, Name: "catalog-project", Model: gorm.Model{ID: 20}}
+	require.NoError(t, ts.ProjectRepo.Create(ts.ctx, project))
+
+	req := types.InstanceRequest{
+		OwnerID: 1, ProjectName: project.Name, Provider: models.ProviderDO,
+		Region: "mars1", Size: "s-1vcpu-1gb", Image: "ubuntu-22-04-x64",
+		NumberOfInstances: 1, Action: "create",
+		Volumes: []types.VolumeConfig{{Name: "vol1", SizeGB: 10, MountPoint: "/mnt/vol1"}},
+	}
+	_, err := ts.InstanceService.CreateInstance(ts.ctx, []types.InstanceRequest{req})
+	require.ErrorIs(t, err, ErrInvalidInstanceRequest)
+	assert.Contains(t, err.Error(), `unknown region "mars1"`)
+
+	tasks, err := ts.TaskRepo.ListByProject(ts.ctx, 1, project.ID, nil)
+	require.NoError(t, err)
+	assert.Empty(t, tasks, "no task should be queued for an invalid request")
+
+	req.Region = "nyc3"
+	created, err := ts.InstanceService.CreateInstance(ts.ctx, []types.InstanceRequest{req})
+	require.NoError(t, err)
+	assert.Len(t, created, 1)
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+)
+
+// ConcurrencyLimits caps the number of tasks that are processed at the same time across all
+// workers sharing the database. Zero means no limit.
+type ConcurrencyLimits struct {
+	// Providers caps the tasks of each provider, providers without a limit are not capped
+	Providers map[models.ProviderID]int
+	// PerOwner caps the tasks of each owner
+	PerOwner int
+	// PerProject caps the tasks of each project
+	PerProject int
+}
+
+// ParseProviderLimits parses provider concurrency limits of the form "do=20,linode=10"
+func ParseProviderLimits(s string) (map[models.ProviderID]int, error) {
+	limits := make(map[models.ProviderID]int)
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		provider, limitStr, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid provider limit %q, expected <provider>=<limit>", entry)
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(limitStr))
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid limit of provider %s: %q", provider, limitStr)
+		}
+		limits[models.ProviderID(strings.TrimSpace(provider))] = limit
+	}
+	return limits, nil
+}
+
+// enabled reports whether any limit is set
+func (l ConcurrencyLimits) enabled() bool {
+	if l.PerOwner > 0 || l.PerProject > 0 {
+		return true
+	}
+	for _, limit := range l.Providers {
+		if limit > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// admits reports whether one more task of the group fits within the limits
+func (l ConcurrencyLimits) admits(counts taskCounts, group taskGroup) bool {
+	if l.PerOwner > 0 && counts.owners[group.ownerID] >= l.PerOwner {
+		return false
+	}
+	if l.PerProject > 0 && counts.projects[group.projectID] >= l.PerProject {
+		return false
+	}
+	if limit := l.Providers[group.providerID]; limit > 0 && counts.providers[group.providerID] >= limit {
+		return false
+	}
+	return true
+}
+
+// exclusions returns a filter that leaves out the owners, projects and providers that reached their limit
+func (l ConcurrencyLimits) exclusions(counts taskCounts) models.TaskSchedulingFilter {
+	var filter models.TaskSchedulingFilter
+	if l.PerOwner > 0 {
+		filter.ExcludeOwnerIDs = atLimit(counts.owners, func(uint) int { return l.PerOwner })
+	}
+	if l.PerProject > 0 {
+		filter.ExcludeProjectIDs = atLimit(counts.projects, func(uint) int { return l.PerProject })
+	}
+	filter.ExcludeProviderIDs = atLimit(counts.providers, func(id models.ProviderID) int { return l.Providers[id] })
+	return filter
+}
+
+// atLimit returns the keys whose count reached their limit, sorted for stable queries
+func atLimit[K uint | models.ProviderID](counts map[K]int, limit func(K) int) []K {
+	var keys []K
+	for key, count := range counts {
+		if l := limit(key); l > 0 && count >= l {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// taskGroup is the owner, project and provider a task counts towards
+type taskGroup struct {
+	ownerID    uint
+	projectID  uint
+	providerID models.ProviderID
+}
+
+// groupOf returns the group of a task. Tasks queued before tasks recorded their provider take it
+// from their instance request, so that they are not exempt from the provider limits.
+func groupOf(task *models.Task) taskGroup {
+	group := taskGroup{ownerID: task.OwnerID, projectID: task.ProjectID, providerID: task.ProviderID}
+	if group.providerID == "" && task.Action == models.TaskActionCreateInstances {
+		var req struct {
+			Provider models.ProviderID `json:"provider"`
+		}
+		if err := json.Unmarshal(task.Payload, &req); err == nil {
+			group.providerID = req.Provider
+		}
+	}
+	return group
+}
+
+// taskCounts counts tasks by owner, project and provider
+type taskCounts struct {
+	owners    map[uint]int
+	projects  map[uint]int
+	providers map[models.ProviderID]int
+}
+
+// newTaskCounts counts the running tasks and the tasks of the groups
+func newTaskCounts(running []models.RunningTaskCount, groups ...taskGroup) taskCounts {
+	counts := taskCounts{
+		owners:    make(map[uint]int),
+		projects:  make(map[uint]int),
+		providers: make(map[models.ProviderID]int),
+	}
+	for _, r := range running {
+		counts.add(taskGroup{ownerID: r.OwnerID, projectID: r.ProjectID, providerID: r.ProviderID}, r.Count)
+	}
+	for _, group := range groups {
+		counts.add(group, 1)
+	}
+	return counts
+}
+
+// add counts n tasks of the group. Tasks whose provider is still unknown, such as running tasks of
+// other workers queued before tasks recorded their provider, only count towards their owner and project.
+func (c taskCounts) add(group taskGroup, n int) {
+	c.owners[group.ownerID] += n
+	c.projects[group.projectID] += n
+	if group.providerID != "" {
+		c.providers[group.providerID] += n
+	}
+}
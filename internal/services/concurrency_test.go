@@ -0,0 +1,67 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+)
+
+func TestParseProviderLimits(t *testing.T) {
+	limits, err := ParseProviderLimits("do=20, linode = 10,,vultr=0")
+	require.NoError(t, err)
+	require.Equal(t, map[models.ProviderID]int{
+		models.ProviderDO:     20,
+		models.ProviderLinode: 10,
+		models.ProviderVultr:  0,
+	}, limits)
+
+	limits, err = ParseProviderLimits("")
+	require.NoError(t, err)
+	require.Empty(t, limits)
+
+	for _, invalid := range []string{"do", "do=many", "do=-1"} {
+		_, err := ParseProviderLimits(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestConcurrencyLimits(t *testing.T) {
+	limits := ConcurrencyLimits{
+		Providers:  map[models.ProviderID]int{models.ProviderDO: 2, models.ProviderLinode: 0},
+		PerOwner:   3,
+		PerProject: 1,
+	}
+	require.True(t, limits.enabled())
+	require.False(t, ConcurrencyLimits{Providers: map[models.ProviderID]int{models.ProviderDO: 0}}.enabled())
+
+	counts := newTaskCounts(
+		[]models.RunningTaskCount{{OwnerID: 1, ProjectID: 10, ProviderID: models.ProviderDO, Count: 2}},
+		taskGroup{ownerID: 2, projectID: 20, providerID: models.ProviderLinode},
+	)
+
+	// Project 10 and DigitalOcean are at their limit, owner 1 is not
+	require.False(t, limits.admits(counts, taskGroup{ownerID: 1, projectID: 11, providerID: models.ProviderDO}))
+	require.False(t, limits.admits(counts, taskGroup{ownerID: 1, projectID: 10, providerID: models.ProviderLinode}))
+	require.True(t, limits.admits(counts, taskGroup{ownerID: 1, projectID: 11, providerID: models.ProviderLinode}))
+	require.True(t, limits.admits(counts, taskGroup{ownerID: 3, projectID: 30}))
+
+	require.Equal(t, models.TaskSchedulingFilter{
+		ExcludeProjectIDs:  []uint{10, 20},
+		ExcludeProviderIDs: []models.ProviderID{models.ProviderDO},
+	}, limits.exclusions(counts))
+}
+
+func TestGroupOf(t *testing.T) {
+	task := &models.Task{OwnerID: 1, ProjectID: 10, ProviderID: models.ProviderLinode, Action: models.TaskActionCreateInstances}
+	require.Equal(t, taskGroup{ownerID: 1, projectID: 10, providerID: models.ProviderLinode}, groupOf(task))
+
+	// Tasks without a provider take it from their instance request
+	task.ProviderID = ""
+	task.Payload = []byte(`{"provider":"do","region":"nyc3"}`)
+	require.Equal(t, taskGroup{ownerID: 1, projectID: 10, providerID: models.ProviderDO}, groupOf(task))
+
+	task.Payload = []byte(`not json`)
+	require.Equal(t, taskGroup{ownerID: 1, projectID: 10}, groupOf(task))
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+	"github.com/celestiaorg/talis/internal/db/repos"
+)
+
+// DriftEvent handles drift event related operations
+type DriftEvent struct {
+	repo *repos.DriftEventRepository
+}
+
+// NewDriftEventService creates a new instance of DriftEvent service
+func NewDriftEventService(repo *repos.DriftEventRepository) *DriftEvent {
+	return &DriftEvent{repo: repo}
+}
+
+// Create records a new drift event
+func (s *DriftEvent) Create(ctx context.Context, event *models.DriftEvent) error {
+	return s.repo.Create(ctx, event)
+}
+
+// List retrieves drift events with pagination
+func (s *DriftEvent) List(ctx context.Context, ownerID uint, opts *models.ListOptions) ([]models.DriftEvent, error) {
+	return s.repo.List(ctx, ownerID, opts)
+}
+
+// ListByInstance retrieves the drift events of an instance with pagination
+func (s *DriftEvent) ListByInstance(ctx context.Context, ownerID, instanceID uint, opts *models.ListOptions) ([]models.DriftEvent, error) {
+	return s.repo.ListByInstance(ctx, ownerID, instanceID, opts)
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+	"github.com/celestiaorg/talis/internal/db/repos"
+)
+
+// Fleet keeps track of the worker processes sharing the database
+type Fleet struct {
+	repo *repos.WorkerRepository
+}
+
+// NewFleetService creates a new instance of Fleet service
+func NewFleetService(repo *repos.WorkerRepository) *Fleet {
+	return &Fleet{repo: repo}
+}
+
+// Heartbeat records that the worker is alive, registering it on its first heartbeat
+func (s *Fleet) Heartbeat(ctx context.Context, worker *models.Worker) error {
+	return s.repo.Heartbeat(ctx, worker)
+}
+
+// Deregister removes a worker that is shutting down
+func (s *Fleet) Deregister(ctx context.Context, workerID string) error {
+	return s.repo.Deregister(ctx, workerID)
+}
+
+// List retrieves the registered workers
+func (s *Fleet) List(ctx context.Context) ([]models.Worker, error) {
+	return s.repo.List(ctx)
+}
+
+// DeleteExpired removes the workers whose last heartbeat is older than deadline
+func (s *Fleet) DeleteExpired(ctx context.Context, deadline time.Time) (int64, error) {
+	return s.repo.DeleteExpired(ctx, deadline)
+}
+
+// newWorker describes the worker process of a worker pool. The ID is unique across hosts and restarts.
+func newWorker() *models.Worker {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+
+	return &models.Worker{
+		ID:        fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), hex.EncodeToString(suffix)),
+		Hostname:  hostname,
+		PID:       os.Getpid(),
+		StartedAt: time.Now(),
+	}
+}
@@ -4,30 +4,67 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
+	"gorm.io/gorm"
+
+	"github.com/celestiaorg/talis/internal/compute"
 	"github.com/celestiaorg/talis/internal/db/models"
 	"github.com/celestiaorg/talis/internal/db/repos"
 	"github.com/celestiaorg/talis/internal/logger"
 	"github.com/celestiaorg/talis/internal/types"
 )
 
+// ErrInvalidInstanceRequest is returned when an instance request is rejected before it is queued
+var ErrInvalidInstanceRequest = errors.New("invalid instance request")
+
 // Instance provides business logic for instance operations
 type Instance struct {
 	repo           *repos.InstanceRepository
 	taskService    *Task
 	projectService *Project
+
+	// providers are the compute providers shared with the workers and the reconciler
+	providers *compute.ProviderRegistry
+	// catalog rejects regions, sizes and images the providers do not offer, nil to not check them
+	catalog types.CatalogValidator
+	// webhooks queues the status changes of instances for the webhooks of their owners, may be nil
+	webhooks *Webhook
+	// playbooks resolves the playbooks instance requests reference, shared with the workers.
+	// Requests with a playbook are rejected if it is nil.
+	playbooks *Playbook
 }
 
 // NewInstanceService creates a new instance service instance
-func NewInstanceService(repo *repos.InstanceRepository, taskService *Task, projectService *Project) *Instance {
+func NewInstanceService(repo *repos.InstanceRepository, taskService *Task, projectService *Project, providers *compute.ProviderRegistry) *Instance {
 	return &Instance{
 		repo:           repo,
 		taskService:    taskService,
 		projectService: projectService,
+		providers:      providers,
 	}
 }
 
+// WithCatalogValidator sets the validator rejecting instance requests with regions, sizes and
+// images their provider does not offer
+func (s *Instance) WithCatalogValidator(catalog types.CatalogValidator) *Instance {
+	s.catalog = catalog
+	return s
+}
+
+// WithWebhooks sends the status changes of instances to the webhooks of their owners
+func (s *Instance) WithWebhooks(webhooks *Webhook) *Instance {
+	s.webhooks = webhooks
+	return s
+}
+
+// WithPlaybooks sets the service resolving the playbooks instance requests reference
+func (s *Instance) WithPlaybooks(playbooks *Playbook) *Instance {
+	s.playbooks = playbooks
+	return s
+}
+
 // ListInstances retrieves a paginated list of instances
 func (s *Instance) ListInstances(ctx context.Context, ownerID uint, opts *models.ListOptions) ([]models.Instance, error) {
 	return s.repo.List(ctx, ownerID, opts)
@@ -41,8 +78,8 @@ func (s *Instance) CreateInstance(ctx context.Context, instances []types.Instanc
 
 	for _, i := range instances {
 		// Validate the instance request
-		if err := i.Validate(); err != nil {
-			return nil, fmt.Errorf("invalid instance request: %w", err)
+		if err := s.validateInstanceRequest(ctx, &i); err != nil {
+			return nil, err
 		}
 
 		// Get the project
@@ -78,11 +115,12 @@ func (s *Instance) CreateInstance(ctx context.Context, instances []types.Instanc
 			}
 
 			tasksToCreate = append(tasksToCreate, &models.Task{
-				OwnerID:   i.OwnerID,
-				ProjectID: project.ID,
-				Status:    models.TaskStatusPending,
-				Action:    models.TaskActionCreateInstances,
-				Payload:   payload,
+				OwnerID:    i.OwnerID,
+				ProjectID:  project.ID,
+				ProviderID: i.Provider,
+				Status:     models.TaskStatusPending,
+				Action:     models.TaskActionCreateInstances,
+				Payload:    payload,
 			})
 
 			// Determine initial payload status
@@ -143,6 +181,9 @@ func (s *Instance) CreateInstance(ctx context.Context, instances []types.Instanc
 		return nil, err
 	}
 
+	for _, instance := range createdInstances {
+		s.notifyWebhooks(ctx, instance)
+	}
 	return createdInstances, nil
 }
 
@@ -153,7 +194,12 @@ func (s *Instance) GetInstance(ctx context.Context, ownerID, id uint) (*models.I
 
 // MarkAsTerminated marks an instance as terminated
 func (s *Instance) MarkAsTerminated(ctx context.Context, ownerID, instanceID uint) error {
-	return s.repo.Terminate(ctx, ownerID, instanceID)
+	previous := s.statusBeforeUpdate(ctx, ownerID, instanceID, models.InstanceStatusTerminated)
+	if err := s.repo.Terminate(ctx, ownerID, instanceID); err != nil {
+		return err
+	}
+	s.notifyStatusChange(ctx, instanceID, previous, models.InstanceStatusTerminated)
+	return nil
 }
 
 // Terminate handles the termination of instances for a given project name and instance IDs.
@@ -203,6 +249,7 @@ func (s *Instance) Terminate(ctx context.Context, ownerID uint, projectName stri
 			OwnerID:    ownerID,
 			ProjectID:  project.ID,
 			InstanceID: instance.ID,
+			ProviderID: instance.ProviderID,
 			Status:     models.TaskStatusPending,
 			Action:     models.TaskActionTerminateInstances,
 			Payload:    taskPayload,
@@ -227,35 +274,326 @@ func uniqueRequestedIDs(ids []uint) []uint {
 	return result
 }
 
-// addTaskLogs appends logs to the task record.
-func (s *Instance) addTaskLogs(ctx context.Context, ownerID uint, task *models.Task, logs string) {
-	if task == nil {
-		logger.Warnf("Attempted to add logs to a nil task: %s", logs)
+// Get retrieves an instance by ID
+func (s *Instance) Get(ctx context.Context, ownerID uint, instanceID uint) (*models.Instance, error) {
+	return s.repo.Get(ctx, ownerID, instanceID)
+}
+
+// Update updates an instance by ID. Changing the status of the instance notifies the webhook
+// of its owner.
+func (s *Instance) Update(ctx context.Context, ownerID uint, instanceID uint, instance *models.Instance) error {
+	previous := s.statusBeforeUpdate(ctx, ownerID, instanceID, instance.Status)
+	if err := s.repo.Update(ctx, ownerID, instanceID, instance); err != nil {
+		return err
+	}
+	s.notifyStatusChange(ctx, instanceID, previous, instance.Status)
+	return nil
+}
+
+// UpdatePayloadStatus updates the status of the payload of an instance
+func (s *Instance) UpdatePayloadStatus(ctx context.Context, ownerID, instanceID uint, status models.PayloadStatus) error {
+	return s.repo.Update(ctx, ownerID, instanceID, &models.Instance{PayloadStatus: status})
+}
+
+// ResetPayload sets the payload of an instance that is provisioned again back to pending copy
+// and deletes the output of its previous run
+func (s *Instance) ResetPayload(ctx context.Context, ownerID, instanceID uint) error {
+	if err := s.UpdatePayloadStatus(ctx, ownerID, instanceID, models.PayloadStatusPendingCopy); err != nil {
+		return err
+	}
+	return s.repo.DeletePayload(ctx, instanceID)
+}
+
+// SavePayloadOutput stores the exit code and the output of the payload script of an instance
+func (s *Instance) SavePayloadOutput(ctx context.Context, payload *models.InstancePayload) error {
+	return s.repo.SavePayload(ctx, payload)
+}
+
+// GetPayload returns the status of the payload of an instance with the exit code and the
+// output of its script. The exit code and output are empty until the script ran.
+func (s *Instance) GetPayload(ctx context.Context, ownerID, instanceID uint) (*models.InstancePayload, error) {
+	instance, err := s.repo.Get(ctx, ownerID, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := s.repo.GetPayload(ctx, ownerID, instanceID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		payload = &models.InstancePayload{InstanceID: instance.ID, OwnerID: instance.OwnerID}
+	} else if err != nil {
+		return nil, err
+	}
+	payload.Status = instance.PayloadStatus
+	return payload, nil
+}
+
+// statusBeforeUpdate returns the stored status of an instance that is about to be updated to
+// status, or InstanceStatusUnknown if webhooks are disabled or the status is not updated
+func (s *Instance) statusBeforeUpdate(ctx context.Context, ownerID, instanceID uint, status models.InstanceStatus) models.InstanceStatus {
+	if s.webhooks == nil || status == models.InstanceStatusUnknown {
+		return models.InstanceStatusUnknown
+	}
+	instance, err := s.repo.Get(ctx, ownerID, instanceID)
+	if err != nil {
+		// The update reports the error
+		return models.InstanceStatusUnknown
+	}
+	return instance.Status
+}
+
+// notifyStatusChange notifies the webhook of the owner of an instance whose status was updated
+// from previous to status
+func (s *Instance) notifyStatusChange(ctx context.Context, instanceID uint, previous, status models.InstanceStatus) {
+	if previous == models.InstanceStatusUnknown || previous == status {
 		return
 	}
-	currentTask, err := s.taskService.Get(ctx, ownerID, task.ID)
+	instance, err := s.repo.Get(ctx, models.AdminID, instanceID)
 	if err != nil {
-		logger.Errorf("failed to get task %d before adding logs: %v", task.ID, err)
-		// Attempt to update with potentially stale task object anyway
-		task.Logs += fmt.Sprintf("\n%s", logs)
-		if updateErr := s.taskService.Update(ctx, ownerID, task); updateErr != nil {
-			logger.Errorf("failed to update task %d with new logs: %v", task.ID, updateErr)
-		}
+		logger.Errorf("failed to get instance %d to queue its webhook: %v", instanceID, err)
 		return
 	}
+	s.notifyWebhooks(ctx, instance)
+}
 
-	currentTask.Logs += fmt.Sprintf("\n%s", logs)
-	if err := s.taskService.Update(ctx, ownerID, currentTask); err != nil {
-		logger.Errorf("failed to update task %d with new logs: %v", task.ID, err)
+// notifyWebhooks queues the status of an instance for the webhook of its owner. Failing to
+// queue the event does not fail the status change, the error is only logged.
+func (s *Instance) notifyWebhooks(ctx context.Context, instance *models.Instance) {
+	if s.webhooks == nil {
+		return
+	}
+	if err := s.webhooks.EnqueueInstanceEvent(ctx, instance); err != nil {
+		logger.Errorf("failed to queue webhook for instance %d: %v", instance.ID, err)
 	}
 }
 
-// Get retrieves an instance by ID
-func (s *Instance) Get(ctx context.Context, ownerID uint, instanceID uint) (*models.Instance, error) {
-	return s.repo.Get(ctx, ownerID, instanceID)
+// ImportInstances adopts instances that already exist on a provider into a project.
+// The instances are either given by their provider-native IDs or selected by a tag.
+// Instances are stored with the IP, region, size and volumes reported by the provider.
+// When selecting by tag, instances that are already managed by Talis are skipped.
+func (s *Instance) ImportInstances(ctx context.Context, req types.ImportInstancesRequest) ([]*models.Instance, error) {
+	if err := req.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid import request: %w", err)
+	}
+
+	project, err := s.projectService.GetByName(ctx, req.OwnerID, req.ProjectName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	if req.OwnerID != project.OwnerID {
+		return nil, fmt.Errorf("instance owner_id does not match project owner_id")
+	}
+
+	provider, err := s.providers.Get(req.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	nativeIDs := req.ProviderInstanceIDs
+	if req.Tag != "" {
+		nativeIDs, err = s.taggedInstanceIDs(ctx, provider, req.Provider, req.Tag)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	referencer, _ := provider.(compute.InstanceReferencer)
+	instancesToCreate := make([]*models.Instance, 0, len(nativeIDs))
+	// Native IDs of the instances to reference once they are stored, by their index
+	unreferenced := make(map[int]string)
+	seen := make(map[int]string, len(nativeIDs))
+	seenNative := make(map[string]bool, len(nativeIDs))
+	for _, nativeID := range nativeIDs {
+		providerInstance, err := provider.ImportInstance(ctx, nativeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import instance %s: %w", nativeID, err)
+		}
+
+		if providerInstance.ProviderInstanceID == 0 {
+			if referencer == nil {
+				return nil, fmt.Errorf("instance %s has no provider instance ID and cannot be imported", nativeID)
+			}
+			if seenNative[providerInstance.NativeID] {
+				return nil, fmt.Errorf("instance %s is listed more than once", nativeID)
+			}
+			seenNative[providerInstance.NativeID] = true
+			unreferenced[len(instancesToCreate)] = providerInstance.NativeID
+		} else {
+			if other, ok := seen[providerInstance.ProviderInstanceID]; ok {
+				return nil, fmt.Errorf("instance %s is listed more than once (also as %s)", nativeID, other)
+			}
+			seen[providerInstance.ProviderInstanceID] = nativeID
+
+			existing, err := s.repo.GetActiveByProviderInstanceID(ctx, req.Provider, providerInstance.ProviderInstanceID)
+			if err == nil {
+				return nil, fmt.Errorf("instance %s is already managed as instance %d", nativeID, existing.ID)
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("failed to check for existing instance %s: %w", nativeID, err)
+			}
+		}
+
+		instance, err := importedInstance(req, project.ID, providerInstance)
+		if err != nil {
+			return nil, err
+		}
+		instancesToCreate = append(instancesToCreate, instance)
+	}
+
+	if len(instancesToCreate) == 0 {
+		return []*models.Instance{}, nil
+	}
+
+	createdInstances, err := s.repo.CreateBatch(ctx, instancesToCreate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to add imported instances to database: %w", err)
+	}
+	if err := s.referenceInstances(ctx, referencer, createdInstances, unreferenced); err != nil {
+		return nil, err
+	}
+	for _, instance := range createdInstances {
+		s.notifyWebhooks(ctx, instance)
+	}
+	logger.Infof("📥 Imported %d %s instance(s) into project %s", len(createdInstances), req.Provider, req.ProjectName)
+	return createdInstances, nil
 }
 
-// Update updates an instance by ID
-func (s *Instance) Update(ctx context.Context, ownerID uint, instanceID uint, instance *models.Instance) error {
-	return s.repo.Update(ctx, ownerID, instanceID, instance)
+// referenceInstances marks the given provider instances with the ID of the instance they were
+// imported as, which is unique and used as their ProviderInstanceID. The instances are stored
+// before, so that a reference always belongs to an existing instance. If an instance cannot be
+// referenced, the instances of the import are deleted again. References written until then stay
+// on their provider instances, but as instance IDs are never reused they cannot clash with others.
+func (s *Instance) referenceInstances(
+	ctx context.Context,
+	referencer compute.InstanceReferencer,
+	instances []*models.Instance,
+	nativeIDs map[int]string,
+) error {
+	for i, nativeID := range nativeIDs {
+		instance := instances[i]
+		ref := int(instance.ID)
+		err := referencer.ReferenceInstance(ctx, nativeID, ref)
+		if err == nil {
+			err = s.repo.Update(ctx, models.AdminID, instance.ID, &models.Instance{ProviderInstanceID: ref})
+		}
+		if err != nil {
+			ids := make([]uint, len(instances))
+			for j, created := range instances {
+				ids[j] = created.ID
+			}
+			if deleteErr := s.repo.DeleteBatch(context.WithoutCancel(ctx), ids); deleteErr != nil {
+				logger.Errorf("failed to delete instances %v of a failed import: %v", ids, deleteErr)
+			}
+			return fmt.Errorf("failed to reference instance %s: %w", nativeID, err)
+		}
+		instance.ProviderInstanceID = ref
+	}
+	return nil
+}
+
+// taggedInstanceIDs returns the native IDs of the live provider instances carrying the given tag
+// that are not yet managed by Talis
+func (s *Instance) taggedInstanceIDs(ctx context.Context, provider compute.Provider, providerID models.ProviderID, tag string) ([]string, error) {
+	providerInstances, err := provider.ListInstances(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s instances: %w", providerID, err)
+	}
+
+	matched := 0
+	nativeIDs := []string{}
+	for _, providerInstance := range providerInstances {
+		if !providerInstance.HasTag(tag) || providerInstance.Status == types.ProviderInstanceStatusTerminated {
+			continue
+		}
+		matched++
+
+		if providerInstance.ProviderInstanceID != 0 {
+			existing, err := s.repo.GetActiveByProviderInstanceID(ctx, providerID, providerInstance.ProviderInstanceID)
+			if err == nil {
+				logger.Debugf("Skipping instance %s, already managed as instance %d", providerInstance.NativeID, existing.ID)
+				continue
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("failed to check for existing instance %s: %w", providerInstance.NativeID, err)
+			}
+		}
+		nativeIDs = append(nativeIDs, providerInstance.NativeID)
+	}
+
+	if matched == 0 {
+		return nil, fmt.Errorf("no %s instances found with tag %q", providerID, tag)
+	}
+	return nativeIDs, nil
+}
+
+// importedInstance builds the instance record for an imported provider instance
+func importedInstance(req types.ImportInstancesRequest, projectID uint, providerInstance *types.ProviderInstance) (*models.Instance, error) {
+	var status models.InstanceStatus
+	switch providerInstance.Status {
+	case types.ProviderInstanceStatusRunning:
+		status = models.InstanceStatusReady
+	case types.ProviderInstanceStatusStopped:
+		status = models.InstanceStatusStopped
+	default:
+		return nil, fmt.Errorf("instance %s is %s and cannot be imported", providerInstance.NativeID, providerInstance.Status)
+	}
+
+	volumeIDs := make([]string, 0, len(providerInstance.Volumes))
+	volumeDetails := make(models.VolumeDetails, 0, len(providerInstance.Volumes))
+	for _, vd := range providerInstance.Volumes {
+		volumeIDs = append(volumeIDs, vd.ID)
+		volumeDetails = append(volumeDetails, models.VolumeDetail{
+			ID:         vd.ID,
+			Name:       vd.Name,
+			Region:     vd.Region,
+			SizeGB:     vd.SizeGB,
+			MountPoint: vd.MountPoint,
+		})
+	}
+
+	tags := providerInstance.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return &models.Instance{
+		OwnerID:            req.OwnerID,
+		ProjectID:          projectID,
+		Name:               providerInstance.Name,
+		ProviderID:         req.Provider,
+		ProviderInstanceID: providerInstance.ProviderInstanceID,
+		PublicIP:           providerInstance.PublicIP,
+		Region:             providerInstance.Region,
+		Size:               providerInstance.Size,
+		Image:              providerInstance.Image,
+		Tags:               tags,
+		Status:             status,
+		VolumeIDs:          volumeIDs,
+		VolumeDetails:      volumeDetails,
+		PayloadStatus:      models.PayloadStatusNone,
+	}, nil
+}
+
+// validateInstanceRequest checks the instance request, including that its playbook exists and
+// its region, size and image against the catalog of its provider
+func (s *Instance) validateInstanceRequest(ctx context.Context, req *types.InstanceRequest) error {
+	if err := req.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidInstanceRequest, err)
+	}
+	if req.Playbook != "" {
+		if s.playbooks == nil {
+			return fmt.Errorf("%w: playbooks are not available on this server", ErrInvalidInstanceRequest)
+		}
+		if _, err := s.playbooks.Resolve(ctx, req.OwnerID, req.Playbook); err != nil {
+			if errors.Is(err, ErrPlaybookNotFound) {
+				return fmt.Errorf("%w: %w", ErrInvalidInstanceRequest, err)
+			}
+			return fmt.Errorf("failed to resolve playbook: %w", err)
+		}
+	}
+	if s.catalog == nil {
+		return nil
+	}
+	if err := s.catalog.ValidateCatalogValues(ctx, req.Provider, req.Region, req.Size, req.Image); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidInstanceRequest, err)
+	}
+	return nil
 }
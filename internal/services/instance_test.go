@@ -4,17 +4,23 @@ import (
 	"context"
 	"encoding/json"
 	"os"
+	"strconv"
 	"testing"
 
+	"github.com/linode/linodego"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
+	"github.com/celestiaorg/talis/internal/compute"
+	computeTypes "github.com/celestiaorg/talis/internal/compute/types"
 	"github.com/celestiaorg/talis/internal/constants"
 	"github.com/celestiaorg/talis/internal/db/models"
 	"github.com/celestiaorg/talis/internal/db/repos"
 	"github.com/celestiaorg/talis/internal/types"
+	"github.com/celestiaorg/talis/test/mocks"
 )
 
 // TestSetup sets up an in-memory database and repositories for testing
@@ -43,6 +49,13 @@ func NewTestSetup(t *testing.T) *TestSetup {
 		&models.User{},
 		&models.Project{},
 		&models.Task{},
+		&models.DriftEvent{},
+		&models.Worker{},
+		&models.TaskEvent{},
+		&models.WebhookDelivery{},
+		&models.WebhookAttempt{},
+		&models.Playbook{},
+		&models.InstancePayload{},
 	)
 	assert.NoError(t, err, "Failed to run migrations")
 
@@ -54,7 +67,7 @@ func NewTestSetup(t *testing.T) *TestSetup {
 	// Create real services
 	projectService := NewProjectService(projectRepo)
 	taskService := NewTaskService(taskRepo, projectService)
-	instanceService := NewInstanceService(instanceRepo, taskService, projectService)
+	instanceService := NewInstanceService(instanceRepo, taskService, projectService, compute.NewProviderRegistry())
 
 	return &TestSetup{
 		DB:              db,
@@ -209,3 +222,188 @@ func TestInstanceService_Terminate_SetsTaskInstanceID(t *testing.T) {
 		assert.Equal(t, task.InstanceID, payload.InstanceID)
 	}
 }
+
+func TestInstanceService_ImportInstances(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	mockClient := mocks.NewMockLinodeClient()
+	provider := &compute.LinodeProvider{}
+	provider.SetClient(mockClient)
+	ts.InstanceService.providers.Set(models.ProviderLinode, provider)
+
+	ownerID := uint(1)
+	projectName := "import-project"
+	project := &models.Project{OwnerID: ownerID, Name: projectName}
+	assert.NoError(t, ts.ProjectRepo.Create(ts.ctx, project))
+
+	newLinode := func(label string, tags ...string) *linodego.Instance {
+		linode, err := mockClient.CreateInstance(ts.ctx, linodego.InstanceCreateOptions{
+			Label: label, Region: "us-east", Type: "g6-standard-1", Image: "linode/ubuntu22.04", Tags: tags,
+		})
+		require.NoError(t, err)
+		return linode
+	}
+
+	manual := newLinode("manual")
+	_, err := mockClient.CreateVolume(ts.ctx, linodego.VolumeCreateOptions{Label: "data", Size: 20, LinodeID: manual.ID})
+	require.NoError(t, err)
+	offline := newLinode("offline", "testnet")
+	offline.Status = linodego.InstanceOffline
+	tagged := newLinode("tagged", "testnet")
+	newLinode("untagged")
+
+	t.Run("by id", func(t *testing.T) {
+		imported, err := ts.InstanceService.ImportInstances(ts.ctx, types.ImportInstancesRequest{
+			OwnerID:             ownerID,
+			ProjectName:         projectName,
+			Provider:            models.ProviderLinode,
+			ProviderInstanceIDs: []string{strconv.Itoa(manual.ID)},
+		})
+		require.NoError(t, err)
+		require.Len(t, imported, 1)
+
+		instance, err := ts.InstanceRepo.Get(ts.ctx, ownerID, imported[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, project.ID, instance.ProjectID)
+		assert.Equal(t, "manual", instance.Name)
+		assert.Equal(t, manual.ID, instance.ProviderInstanceID)
+		assert.Equal(t, models.InstanceStatusReady, instance.Status)
+		assert.Equal(t, mocks.DefaultLinodeInstanceIP, instance.PublicIP)
+		assert.Equal(t, "us-east", instance.Region)
+		assert.Equal(t, "g6-standard-1", instance.Size)
+		require.Len(t, instance.VolumeDetails, 1)
+		assert.Equal(t, "data", instance.VolumeDetails[0].Name)
+		assert.Equal(t, 20, instance.VolumeDetails[0].SizeGB)
+		assert.ElementsMatch(t, []string{instance.VolumeDetails[0].ID}, instance.VolumeIDs)
+	})
+
+	t.Run("already managed", func(t *testing.T) {
+		_, err := ts.InstanceService.ImportInstances(ts.ctx, types.ImportInstancesRequest{
+			OwnerID:             ownerID,
+			ProjectName:         projectName,
+			Provider:            models.ProviderLinode,
+			ProviderInstanceIDs: []string{strconv.Itoa(manual.ID)},
+		})
+		assert.ErrorContains(t, err, "already managed")
+	})
+
+	t.Run("by tag", func(t *testing.T) {
+		req := types.ImportInstancesRequest{
+			OwnerID:     ownerID,
+			ProjectName: projectName,
+			Provider:    models.ProviderLinode,
+			Tag:         "testnet",
+		}
+		imported, err := ts.InstanceService.ImportInstances(ts.ctx, req)
+		require.NoError(t, err)
+		require.Len(t, imported, 2)
+
+		statuses := map[int]models.InstanceStatus{}
+		for _, instance := range imported {
+			statuses[instance.ProviderInstanceID] = instance.Status
+		}
+		assert.Equal(t, map[int]models.InstanceStatus{
+			offline.ID: models.InstanceStatusStopped,
+			tagged.ID:  models.InstanceStatusReady,
+		}, statuses)
+
+		// Instances that are already managed are skipped
+		imported, err = ts.InstanceService.ImportInstances(ts.ctx, req)
+		require.NoError(t, err)
+		assert.Empty(t, imported)
+	})
+
+	t.Run("unknown tag", func(t *testing.T) {
+		_, err := ts.InstanceService.ImportInstances(ts.ctx, types.ImportInstancesRequest{
+			OwnerID:     ownerID,
+			ProjectName: projectName,
+			Provider:    models.ProviderLinode,
+			Tag:         "mainnet",
+		})
+		assert.ErrorContains(t, err, "no linode instances found")
+	})
+
+	t.Run("wrong owner", func(t *testing.T) {
+		_, err := ts.InstanceService.ImportInstances(ts.ctx, types.ImportInstancesRequest{
+			OwnerID:             ownerID + 1,
+			ProjectName:         projectName,
+			Provider:            models.ProviderLinode,
+			ProviderInstanceIDs: []string{strconv.Itoa(tagged.ID)},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestInstanceService_ImportInstances_Reference(t *testing.T) {
+	t.Setenv(constants.EnvTalisDeploymentID, "test")
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	mockClient := mocks.NewMockVultrClient()
+	provider := &compute.VultrProvider{}
+	provider.SetClient(mockClient)
+	ts.InstanceService.providers.Set(models.ProviderVultr, provider)
+
+	ownerID := uint(1)
+	projectName := "import-project"
+	project := &models.Project{OwnerID: ownerID, Name: projectName}
+	assert.NoError(t, ts.ProjectRepo.Create(ts.ctx, project))
+
+	newInstance := func(label string) string {
+		return mockClient.AddInstance(computeTypes.VultrInstance{
+			Label: label, Region: "ewr", Plan: "vc2-1c-1gb", MainIP: mocks.DefaultVultrInstanceIP,
+			Status: "active", PowerStatus: "running",
+		})
+	}
+	importInstances := func(nativeIDs ...string) ([]*models.Instance, error) {
+		return ts.InstanceService.ImportInstances(ts.ctx, types.ImportInstancesRequest{
+			OwnerID:             ownerID,
+			ProjectName:         projectName,
+			Provider:            models.ProviderVultr,
+			ProviderInstanceIDs: nativeIDs,
+		})
+	}
+
+	t.Run("referenced with the instance ID", func(t *testing.T) {
+		id := newInstance("manual")
+		imported, err := importInstances(id)
+		require.NoError(t, err)
+		require.Len(t, imported, 1)
+		assert.Equal(t, int(imported[0].ID), imported[0].ProviderInstanceID)
+
+		instance, err := ts.InstanceRepo.Get(ts.ctx, ownerID, imported[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, int(instance.ID), instance.ProviderInstanceID)
+
+		found, err := provider.GetInstance(ts.ctx, instance.ProviderInstanceID)
+		require.NoError(t, err)
+		assert.Equal(t, id, found.NativeID)
+
+		_, err = importInstances(id)
+		assert.ErrorContains(t, err, "already managed")
+	})
+
+	t.Run("listed twice", func(t *testing.T) {
+		id := newInstance("twice")
+		_, err := importInstances(id, id)
+		assert.ErrorContains(t, err, "more than once")
+	})
+
+	t.Run("reference fails", func(t *testing.T) {
+		id := newInstance("failing")
+		before, err := ts.InstanceRepo.List(ts.ctx, ownerID, nil)
+		require.NoError(t, err)
+
+		mockClient.UpdateTagsFunc = func(_ context.Context, _ string, _ []string) error {
+			return mocks.ErrVultrRateLimit
+		}
+		_, err = importInstances(id)
+		assert.ErrorIs(t, err, mocks.ErrVultrRateLimit)
+
+		// The instances of the failed import are removed again
+		after, err := ts.InstanceRepo.List(ts.ctx, ownerID, nil)
+		require.NoError(t, err)
+		assert.Len(t, after, len(before))
+	})
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"gorm.io/gorm"
+
+	"github.com/celestiaorg/talis/internal/compute"
+	"github.com/celestiaorg/talis/internal/db/models"
+	"github.com/celestiaorg/talis/internal/db/repos"
+)
+
+// ErrPlaybookNotFound is returned when a playbook is neither registered on the server nor
+// uploaded by its owner
+var ErrPlaybookNotFound = errors.New("playbook not found")
+
+// ErrPlaybookExists is returned when a playbook is uploaded with the name of a registered
+// playbook or of another playbook of its owner
+var ErrPlaybookExists = errors.New("playbook already exists")
+
+// ErrPlaybookUploadsDisabled is returned when a playbook is uploaded to a server that does not
+// run uploaded playbooks
+var ErrPlaybookUploadsDisabled = errors.New("uploaded playbooks are disabled on this server")
+
+// Playbook provides logic for managing the playbooks instances are provisioned with. Playbooks
+// are either registered on the server, as directories of the playbooks directory, or uploaded
+// by their owner. Registered playbooks take precedence.
+//
+// Ansible runs playbooks on the server, so an uploaded playbook can run any code there, for
+// example with a local connection or a pipe lookup. Uploaded playbooks are only accepted and
+// run once they are enabled with WithUploads, on servers whose users are trusted or whose
+// workers run sandboxed.
+type Playbook struct {
+	repo *repos.PlaybookRepository
+	// dir is the directory of the playbooks registered on the server
+	dir string
+	// uploads enables uploading and running the playbooks of owners
+	uploads bool
+}
+
+// NewPlaybookService creates a new playbook service, with the playbooks registered on the server
+// in compute.PlaybooksDir
+func NewPlaybookService(repo *repos.PlaybookRepository) *Playbook {
+	return &Playbook{
+		repo: repo,
+		dir:  compute.PlaybooksDir,
+	}
+}
+
+// WithDirectory sets the directory of the playbooks registered on the server
+func (s *Playbook) WithDirectory(dir string) *Playbook {
+	if dir != "" {
+		s.dir = dir
+	}
+	return s
+}
+
+// WithUploads enables or disables uploading and running the playbooks of owners
+func (s *Playbook) WithUploads(enabled bool) *Playbook {
+	s.uploads = enabled
+	return s
+}
+
+// CreatePlaybook uploads a playbook for its owner. The name of a playbook registered on the
+// server can not be used, since the registered playbook would be run instead.
+func (s *Playbook) CreatePlaybook(ctx context.Context, playbook *models.Playbook) error {
+	if !s.uploads {
+		return ErrPlaybookUploadsDisabled
+	}
+	if err := playbook.Validate(); err != nil {
+		return err
+	}
+	registered, err := compute.RegisteredPlaybook(s.dir, playbook.Name)
+	if err != nil {
+		return err
+	}
+	if registered != nil {
+		return fmt.Errorf("%w: %q is registered on the server", ErrPlaybookExists, playbook.Name)
+	}
+	_, err = s.repo.Get(ctx, playbook.OwnerID, playbook.Name)
+	if err == nil {
+		return fmt.Errorf("%w: %q", ErrPlaybookExists, playbook.Name)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return s.repo.Create(ctx, playbook)
+}
+
+// ListPlaybooks lists the playbooks registered on the server followed by the playbooks uploaded
+// by the owner, without their files
+func (s *Playbook) ListPlaybooks(ctx context.Context, ownerID uint) ([]*models.Playbook, error) {
+	playbooks, err := s.registeredPlaybooks()
+	if err != nil {
+		return nil, err
+	}
+	uploaded, err := s.repo.List(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	return append(playbooks, uploaded...), nil
+}
+
+// registeredPlaybooks lists the playbooks registered on the server
+func (s *Playbook) registeredPlaybooks() ([]*models.Playbook, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return []*models.Playbook{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list registered playbooks: %w", err)
+	}
+
+	playbooks := []*models.Playbook{}
+	for _, entry := range entries {
+		if !entry.IsDir() || models.ValidatePlaybookName(entry.Name()) != nil {
+			continue
+		}
+		registered, err := compute.RegisteredPlaybook(s.dir, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		if registered != nil {
+			playbooks = append(playbooks, &models.Playbook{Name: registered.Name, Registered: true})
+		}
+	}
+	return playbooks, nil
+}
+
+// DeletePlaybook deletes a playbook uploaded by the owner. Registered playbooks can not be deleted.
+func (s *Playbook) DeletePlaybook(ctx context.Context, ownerID uint, name string) error {
+	return s.repo.Delete(ctx, ownerID, name)
+}
+
+// Resolve returns the playbook an instance request of the owner references by name, registered
+// on the server or uploaded by the owner. It returns ErrPlaybookNotFound if there is none, and
+// for uploaded playbooks while uploads are disabled.
+func (s *Playbook) Resolve(ctx context.Context, ownerID uint, name string) (*compute.Playbook, error) {
+	registered, err := compute.RegisteredPlaybook(s.dir, name)
+	if err != nil {
+		return nil, err
+	}
+	if registered != nil {
+		return registered, nil
+	}
+	if !s.uploads {
+		return nil, fmt.Errorf("%w: %q is not registered on the server and %w", ErrPlaybookNotFound, name, ErrPlaybookUploadsDisabled)
+	}
+
+	uploaded, err := s.repo.Get(ctx, ownerID, name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: %q", ErrPlaybookNotFound, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &compute.Playbook{Name: uploaded.Name, Files: uploaded.Files}, nil
+}
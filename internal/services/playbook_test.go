@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/celestiaorg/talis/internal/compute"
+	"github.com/celestiaorg/talis/internal/db/models"
+	"github.com/celestiaorg/talis/internal/db/repos"
+	"github.com/celestiaorg/talis/internal/types"
+)
+
+// newTestPlaybookService creates a playbook service with the "validator" playbook registered
+func newTestPlaybookService(t *testing.T, ts *TestSetup) *Playbook {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "validator", "roles"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "validator", "main.yml"), []byte("- hosts: all\n"), 0600))
+	return NewPlaybookService(repos.NewPlaybookRepository(ts.DB)).WithDirectory(dir).WithUploads(true)
+}
+
+func TestPlaybookService(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+	playbooks := newTestPlaybookService(t, ts)
+	ownerID := uint(7)
+	files := models.PlaybookFiles{"main.yml": "- hosts: all\n  roles: [bridge]\n"}
+
+	require.NoError(t, playbooks.CreatePlaybook(ts.ctx, &models.Playbook{Name: "bridge", OwnerID: ownerID, Files: files}))
+	err := playbooks.CreatePlaybook(ts.ctx, &models.Playbook{Name: "validator", OwnerID: ownerID, Files: files})
+	require.ErrorIs(t, err, ErrPlaybookExists)
+	require.ErrorContains(t, err, `"validator" is registered on the server`)
+	err = playbooks.CreatePlaybook(ts.ctx, &models.Playbook{Name: "bridge", OwnerID: ownerID, Files: files})
+	require.ErrorIs(t, err, ErrPlaybookExists)
+
+	list, err := playbooks.ListPlaybooks(ts.ctx, ownerID)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, "validator", list[0].Name)
+	require.True(t, list[0].Registered)
+	require.Equal(t, "bridge", list[1].Name)
+	require.False(t, list[1].Registered)
+
+	registered, err := playbooks.Resolve(ts.ctx, ownerID, "validator")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(playbooks.dir, "validator"), registered.Dir)
+
+	uploaded, err := playbooks.Resolve(ts.ctx, ownerID, "bridge")
+	require.NoError(t, err)
+	require.Equal(t, &compute.Playbook{Name: "bridge", Files: files}, uploaded)
+
+	// Uploaded playbooks are only visible to their owner
+	_, err = playbooks.Resolve(ts.ctx, ownerID+1, "bridge")
+	require.ErrorIs(t, err, ErrPlaybookNotFound)
+
+	require.NoError(t, playbooks.DeletePlaybook(ts.ctx, ownerID, "bridge"))
+	require.ErrorIs(t, playbooks.DeletePlaybook(ts.ctx, ownerID, "validator"), gorm.ErrRecordNotFound)
+	_, err = playbooks.Resolve(ts.ctx, ownerID, "bridge")
+	require.ErrorIs(t, err, ErrPlaybookNotFound)
+}
+
+func TestPlaybookService_UploadsDisabled(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+	playbooks := newTestPlaybookService(t, ts)
+	ownerID := uint(7)
+	files := models.PlaybookFiles{"main.yml": "- hosts: all\n"}
+	require.NoError(t, playbooks.CreatePlaybook(ts.ctx, &models.Playbook{Name: "bridge", OwnerID: ownerID, Files: files}))
+
+	// Once uploads are disabled, uploaded playbooks can neither be added nor run
+	playbooks.WithUploads(false)
+	err := playbooks.CreatePlaybook(ts.ctx, &models.Playbook{Name: "light", OwnerID: ownerID, Files: files})
+	require.ErrorIs(t, err, ErrPlaybookUploadsDisabled)
+	_, err = playbooks.Resolve(ts.ctx, ownerID, "bridge")
+	require.ErrorIs(t, err, ErrPlaybookNotFound)
+	require.ErrorIs(t, err, ErrPlaybookUploadsDisabled)
+
+	// Registered playbooks still run
+	registered, err := playbooks.Resolve(ts.ctx, ownerID, "validator")
+	require.NoError(t, err)
+	require.Equal(t, "validator", registered.Name)
+}
+
+func TestInstanceService_CreateInstance_Playbook(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	ownerID := uint(8)
+	require.NoError(t, ts.ProjectRepo.Create(ts.ctx, &models.Project{OwnerID: ownerID, Name: "playbook-project"}))
+	request := func(playbook string) []types.InstanceRequest {
+		return []types.InstanceRequest{{
+			OwnerID: ownerID, ProjectName: "playbook-project", Provider: models.ProviderDO,
+			Region: "nyc1", Size: "s-1vcpu-1gb", Image: "ubuntu-20-04-x64",
+			NumberOfInstances: 1, Action: "create", Provision: true,
+			Volumes:  []types.VolumeConfig{{Name: "vol1", SizeGB: 10, MountPoint: "/mnt/vol1"}},
+			Playbook: playbook, ExtraVars: map[string]interface{}{"moniker": "validator-0"},
+		}}
+	}
+
+	// Without the playbook service no playbook can be referenced
+	_, err := ts.InstanceService.CreateInstance(ts.ctx, request("validator"))
+	require.ErrorIs(t, err, ErrInvalidInstanceRequest)
+
+	ts.InstanceService.WithPlaybooks(newTestPlaybookService(t, ts))
+	_, err = ts.InstanceService.CreateInstance(ts.ctx, request("bridge"))
+	require.ErrorIs(t, err, ErrInvalidInstanceRequest)
+	require.ErrorIs(t, err, ErrPlaybookNotFound)
+
+	instances, err := ts.InstanceService.CreateInstance(ts.ctx, request("validator"))
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+}
+
+// recordingProvisioner records the options it provisions with
+type recordingProvisioner struct {
+	compute.Provisioner
+	opts compute.ProvisionOptions
+}
+
+func (p *recordingProvisioner) Provision(_ context.Context, _ *types.InstanceRequest, opts compute.ProvisionOptions) (*compute.ProvisionResult, error) {
+	p.opts = opts
+	return &compute.ProvisionResult{}, nil
+}
+
+func (p *recordingProvisioner) Close() error {
+	return nil
+}
+
+func TestWorker_provisionInstance_Playbook(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+	playbooks := newTestPlaybookService(t, ts)
+	ts.InstanceService.WithPlaybooks(playbooks)
+
+	req := types.InstanceRequest{
+		OwnerID: 9, ProjectName: "test-project-playbook", Provider: models.ProviderDO,
+		Region: "nyc1", Size: "s-1vcpu-1gb", Image: "ubuntu-22-04-x64",
+		NumberOfInstances: 1, Action: "create", Provision: true,
+		Volumes:      []types.VolumeConfig{{Name: "data", SizeGB: 10, MountPoint: "/mnt/data"}},
+		Playbook:     "bridge",
+		PlaybookTags: []string{"install"},
+		ExtraVars:    map[string]interface{}{"network": "mocha"},
+	}
+	files := models.PlaybookFiles{"main.yml": "- hosts: all\n"}
+	require.NoError(t, playbooks.CreatePlaybook(ts.ctx, &models.Playbook{Name: "bridge", OwnerID: req.OwnerID, Files: files}))
+	require.NoError(t, ts.ProjectRepo.Create(ts.ctx, &models.Project{OwnerID: req.OwnerID, Name: req.ProjectName}))
+	created, err := ts.InstanceService.CreateInstance(ts.ctx, []types.InstanceRequest{req})
+	require.NoError(t, err)
+	tasks, err := ts.TaskService.ListTasksByInstanceID(ts.ctx, req.OwnerID, created[0].ID, models.TaskActionCreateInstances, nil)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+
+	w := NewWorkerPool(ts.InstanceService, ts.ProjectService, ts.TaskService, nil, nil, time.Millisecond*10)
+	ts.InstanceService.providers.Set(req.Provider, &countingProvider{})
+	provisioner := &recordingProvisioner{}
+	w.newProvisioner = func(models.ProvisionerID, string) (compute.Provisioner, error) {
+		return provisioner, nil
+	}
+
+	instance := created[0]
+	instance.PublicIP = "192.0.2.1"
+	instanceReq := req
+	instanceReq.InstanceID = instance.ID
+	instanceReq.PublicIP = instance.PublicIP
+	require.NoError(t, w.provisionInstance(ts.ctx, &tasks[0], instance, &instanceReq, &models.TaskResult{}))
+	require.Equal(t, &compute.Playbook{
+		Name:      "bridge",
+		Files:     files,
+		Tags:      []string{"install"},
+		ExtraVars: map[string]interface{}{"network": "mocha"},
+	}, provisioner.opts.Playbook)
+
+	// A playbook deleted after the request was queued fails the task without retries
+	require.NoError(t, playbooks.DeletePlaybook(ts.ctx, req.OwnerID, "bridge"))
+	err = w.provisionInstance(ts.ctx, &tasks[0], instance, &instanceReq, &models.TaskResult{})
+	require.ErrorIs(t, err, ErrPlaybookNotFound)
+	require.False(t, isRetryable(err))
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+	"github.com/celestiaorg/talis/internal/logger"
+	"github.com/celestiaorg/talis/internal/types"
+)
+
+// DefaultReconcileInterval is the default interval between two reconciliation passes
+const DefaultReconcileInterval = 5 * time.Minute
+
+// Reconciler periodically compares instances with the state reported by their providers.
+// Instances that disappeared are marked as terminated, instances that were stopped or
+// started outside of Talis change status and changed public IPs are updated. Every
+// difference is recorded as a drift event.
+type Reconciler struct {
+	instanceService   *Instance
+	driftEventService *DriftEvent
+
+	interval time.Duration
+	lock     Locker
+}
+
+// Locker is a lock shared by the processes using the same database
+type Locker interface {
+	// TryAcquire reports whether the lock is held by this process, acquiring it when it is free
+	TryAcquire(ctx context.Context) (bool, error)
+	// Release releases the lock if it is held
+	Release(ctx context.Context) error
+}
+
+// NewReconciler creates a new Reconciler
+func NewReconciler(instanceService *Instance, driftEventService *DriftEvent, interval time.Duration) *Reconciler {
+	return &Reconciler{
+		instanceService:   instanceService,
+		driftEventService: driftEventService,
+		interval:          interval,
+	}
+}
+
+// WithLock makes the reconciler only run while it holds the lock, so that a single process
+// reconciles instances when several share the database
+func (r *Reconciler) WithLock(lock Locker) *Reconciler {
+	r.lock = lock
+	return r
+}
+
+// Run reconciles instances every interval until the context is cancelled
+func (r *Reconciler) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	logger.Infof("🔄 Reconciler started with interval %s", r.interval)
+	t := time.NewTicker(r.interval)
+	defer t.Stop()
+	if r.lock != nil {
+		defer func() {
+			// The context is cancelled already
+			if err := r.lock.Release(context.Background()); err != nil {
+				logger.Warnf("Failed to release the reconciler lock: %v", err)
+			}
+		}()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Reconciler received shutdown signal, stopping...")
+			return
+		case <-t.C:
+		}
+
+		if !r.holdsLock(ctx) {
+			continue
+		}
+		if err := r.Reconcile(ctx); err != nil {
+			logger.Errorf("❌ Reconciliation failed: %v", err)
+		}
+	}
+}
+
+// holdsLock reports whether this reconciler may run a pass, i.e. it has no lock or holds it
+func (r *Reconciler) holdsLock(ctx context.Context) bool {
+	if r.lock == nil {
+		return true
+	}
+	held, err := r.lock.TryAcquire(ctx)
+	if err != nil {
+		logger.Warnf("Failed to acquire the reconciler lock, skipping reconciliation: %v", err)
+		return false
+	}
+	if !held {
+		logger.Debug("Reconciler lock held by another process, skipping reconciliation")
+	}
+	return held
+}
+
+// Reconcile runs a single reconciliation pass over all ready and stopped instances.
+// Instances that are still being created or provisioned are left to the workers.
+func (r *Reconciler) Reconcile(ctx context.Context) error {
+	instances, err := r.instanceService.ListInstances(ctx, models.AdminID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list instances: %w", err)
+	}
+
+	byProvider := make(map[models.ProviderID][]models.Instance)
+	for _, instance := range instances {
+		if instance.ProviderInstanceID == 0 {
+			continue
+		}
+		if instance.Status != models.InstanceStatusReady && instance.Status != models.InstanceStatusStopped {
+			continue
+		}
+		byProvider[instance.ProviderID] = append(byProvider[instance.ProviderID], instance)
+	}
+
+	for providerID, instances := range byProvider {
+		if err := r.reconcileProvider(ctx, providerID, instances); err != nil {
+			// A failing provider must not prevent the others from being reconciled
+			logger.Warnf("⚠️ Warning: Failed to reconcile %s instances: %v", providerID, err)
+		}
+	}
+	return nil
+}
+
+// reconcileProvider reconciles the instances of a single provider
+func (r *Reconciler) reconcileProvider(ctx context.Context, providerID models.ProviderID, instances []models.Instance) error {
+	provider, err := r.instanceService.providers.Get(providerID)
+	if err != nil {
+		return err
+	}
+
+	listed, err := provider.ListInstances(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list provider instances: %w", err)
+	}
+	byID := make(map[int]types.ProviderInstance, len(listed))
+	for _, providerInstance := range listed {
+		if providerInstance.ProviderInstanceID != 0 {
+			byID[providerInstance.ProviderInstanceID] = providerInstance
+		}
+	}
+
+	for i := range instances {
+		instance := &instances[i]
+		providerInstance, ok := byID[instance.ProviderInstanceID]
+		if !ok {
+			// Listings can lag behind, confirm the instance is gone before acting on it
+			found, err := provider.GetInstance(ctx, instance.ProviderInstanceID)
+			if err != nil && !errors.Is(err, types.ErrProviderInstanceNotFound) {
+				logger.Warnf("⚠️ Warning: Failed to get instance %d from %s: %v", instance.ID, providerID, err)
+				continue
+			}
+			if found == nil {
+				providerInstance = types.ProviderInstance{Status: types.ProviderInstanceStatusTerminated}
+			} else {
+				providerInstance = *found
+			}
+		}
+
+		if err := r.reconcileInstance(ctx, instance, &providerInstance); err != nil {
+			logger.Warnf("⚠️ Warning: Failed to reconcile instance %d: %v", instance.ID, err)
+		}
+	}
+	return nil
+}
+
+// reconcileInstance applies the provider state to an instance and records the drift
+func (r *Reconciler) reconcileInstance(ctx context.Context, instance *models.Instance, providerInstance *types.ProviderInstance) error {
+	if providerInstance.Status == types.ProviderInstanceStatusTerminated {
+		logger.Infof("🔍 Instance %d no longer exists on %s, marking as terminated", instance.ID, instance.ProviderID)
+		if err := r.instanceService.MarkAsTerminated(ctx, models.AdminID, instance.ID); err != nil {
+			return fmt.Errorf("failed to mark instance as terminated: %w", err)
+		}
+		return r.recordDrift(ctx, instance, models.DriftEventMissing, instance.Status.String(), models.InstanceStatusTerminated.String())
+	}
+
+	update := &models.Instance{}
+	var events []*models.DriftEvent
+
+	status := instance.Status
+	switch providerInstance.Status {
+	case types.ProviderInstanceStatusRunning:
+		status = models.InstanceStatusReady
+	case types.ProviderInstanceStatusStopped:
+		status = models.InstanceStatusStopped
+	}
+	if status != instance.Status {
+		logger.Infof("🔍 Instance %d changed from %s to %s on %s", instance.ID, instance.Status, status, instance.ProviderID)
+		update.Status = status
+		events = append(events, r.driftEvent(instance, models.DriftEventStatusChanged, instance.Status.String(), status.String()))
+	}
+
+	if providerInstance.PublicIP != "" && providerInstance.PublicIP != instance.PublicIP {
+		logger.Infof("🔍 Instance %d changed IP from %s to %s on %s", instance.ID, instance.PublicIP, providerInstance.PublicIP, instance.ProviderID)
+		update.PublicIP = providerInstance.PublicIP
+		events = append(events, r.driftEvent(instance, models.DriftEventIPChanged, instance.PublicIP, providerInstance.PublicIP))
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+	if err := r.instanceService.Update(ctx, models.AdminID, instance.ID, update); err != nil {
+		return fmt.Errorf("failed to update instance: %w", err)
+	}
+	for _, event := range events {
+		if err := r.driftEventService.Create(ctx, event); err != nil {
+			return fmt.Errorf("failed to record drift event: %w", err)
+		}
+	}
+	return nil
+}
+
+// recordDrift records a single drift event for an instance
+func (r *Reconciler) recordDrift(ctx context.Context, instance *models.Instance, eventType models.DriftEventType, oldValue, newValue string) error {
+	if err := r.driftEventService.Create(ctx, r.driftEvent(instance, eventType, oldValue, newValue)); err != nil {
+		return fmt.Errorf("failed to record drift event: %w", err)
+	}
+	return nil
+}
+
+// driftEvent builds a drift event for an instance
+func (r *Reconciler) driftEvent(instance *models.Instance, eventType models.DriftEventType, oldValue, newValue string) *models.DriftEvent {
+	return &models.DriftEvent{
+		OwnerID:            instance.OwnerID,
+		InstanceID:         instance.ID,
+		ProviderID:         instance.ProviderID,
+		ProviderInstanceID: instance.ProviderInstanceID,
+		Type:               eventType,
+		OldValue:           oldValue,
+		NewValue:           newValue,
+	}
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/linode/linodego"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/talis/internal/compute"
+	"github.com/celestiaorg/talis/internal/db/models"
+	"github.com/celestiaorg/talis/internal/db/repos"
+	"github.com/celestiaorg/talis/test/mocks"
+)
+
+func TestReconciler_Reconcile(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	mockClient := mocks.NewMockLinodeClient()
+	provider := &compute.LinodeProvider{}
+	provider.SetClient(mockClient)
+
+	driftEventService := NewDriftEventService(repos.NewDriftEventRepository(ts.DB))
+	r := NewReconciler(ts.InstanceService, driftEventService, DefaultReconcileInterval)
+	ts.InstanceService.providers.Set(models.ProviderLinode, provider)
+
+	newLinode := func() *linodego.Instance {
+		linode, err := mockClient.CreateInstance(ts.ctx, linodego.InstanceCreateOptions{Label: "linode"})
+		require.NoError(t, err)
+		return linode
+	}
+	newInstance := func(providerInstanceID int, status models.InstanceStatus) *models.Instance {
+		instance, err := ts.InstanceRepo.Create(ts.ctx, &models.Instance{
+			OwnerID:            1,
+			ProjectID:          1,
+			ProviderID:         models.ProviderLinode,
+			ProviderInstanceID: providerInstanceID,
+			PublicIP:           mocks.DefaultLinodeInstanceIP,
+			Status:             status,
+		})
+		require.NoError(t, err)
+		return instance
+	}
+
+	unchanged := newInstance(newLinode().ID, models.InstanceStatusReady)
+
+	stoppedLinode := newLinode()
+	stoppedLinode.Status = linodego.InstanceOffline
+	stopped := newInstance(stoppedLinode.ID, models.InstanceStatusReady)
+
+	startedLinode := newLinode()
+	started := newInstance(startedLinode.ID, models.InstanceStatusStopped)
+
+	movedLinode := newLinode()
+	newIP := net.ParseIP("192.0.2.99")
+	movedLinode.IPv4 = []*net.IP{&newIP}
+	moved := newInstance(movedLinode.ID, models.InstanceStatusReady)
+
+	provisioning := newInstance(newLinode().ID+1000, models.InstanceStatusProvisioning)
+	missing := newInstance(newLinode().ID+1000, models.InstanceStatusReady)
+	mockClient.GetInstanceFunc = func(_ context.Context, _ int) (*linodego.Instance, error) {
+		return nil, mocks.ErrLinodeNotFound
+	}
+
+	require.NoError(t, r.Reconcile(ts.ctx))
+
+	tests := []struct {
+		name     string
+		instance *models.Instance
+		status   models.InstanceStatus
+		publicIP string
+		events   []models.DriftEventType
+	}{
+		{"unchanged", unchanged, models.InstanceStatusReady, mocks.DefaultLinodeInstanceIP, nil},
+		{"stopped", stopped, models.InstanceStatusStopped, mocks.DefaultLinodeInstanceIP, []models.DriftEventType{models.DriftEventStatusChanged}},
+		{"started", started, models.InstanceStatusReady, mocks.DefaultLinodeInstanceIP, []models.DriftEventType{models.DriftEventStatusChanged}},
+		{"moved", moved, models.InstanceStatusReady, "192.0.2.99", []models.DriftEventType{models.DriftEventIPChanged}},
+		{"provisioning", provisioning, models.InstanceStatusProvisioning, mocks.DefaultLinodeInstanceIP, nil},
+		{"missing", missing, models.InstanceStatusTerminated, mocks.DefaultLinodeInstanceIP, []models.DriftEventType{models.DriftEventMissing}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instance, err := ts.InstanceService.Get(ts.ctx, models.AdminID, tt.instance.ID)
+			require.NoError(t, err)
+			require.Equal(t, tt.status, instance.Status)
+			require.Equal(t, tt.publicIP, instance.PublicIP)
+
+			events, err := driftEventService.ListByInstance(ts.ctx, instance.OwnerID, instance.ID, nil)
+			require.NoError(t, err)
+			eventTypes := make([]models.DriftEventType, 0, len(events))
+			for _, event := range events {
+				eventTypes = append(eventTypes, event.Type)
+			}
+			require.ElementsMatch(t, tt.events, eventTypes)
+		})
+	}
+
+	// A second pass finds nothing new
+	require.NoError(t, r.Reconcile(ts.ctx))
+	events, err := driftEventService.List(ts.ctx, models.AdminID, nil)
+	require.NoError(t, err)
+	require.Len(t, events, 4)
+}
+
+// fakeLock is a Locker whose state is set by the test
+type fakeLock struct {
+	held     atomic.Bool
+	attempts atomic.Int32
+	released atomic.Bool
+}
+
+func (l *fakeLock) TryAcquire(context.Context) (bool, error) {
+	l.attempts.Add(1)
+	return l.held.Load(), nil
+}
+
+func (l *fakeLock) Release(context.Context) error {
+	l.released.Store(true)
+	return nil
+}
+
+func TestReconciler_Lock(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	lock := &fakeLock{}
+	r := NewReconciler(ts.InstanceService, NewDriftEventService(repos.NewDriftEventRepository(ts.DB)), 10*time.Millisecond).WithLock(lock)
+	require.False(t, r.holdsLock(ts.ctx), "the lock is held by another process")
+	lock.held.Store(true)
+	require.True(t, r.holdsLock(ts.ctx))
+	require.True(t, NewReconciler(ts.InstanceService, nil, DefaultReconcileInterval).holdsLock(ts.ctx), "reconcilers without a lock always run")
+
+	// The lock is tried on every pass and released on shutdown
+	ctx, cancel := context.WithCancel(ts.ctx)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go r.Run(ctx, &wg)
+	require.Eventually(t, func() bool { return lock.attempts.Load() > 3 }, time.Second, 5*time.Millisecond)
+	cancel()
+	wg.Wait()
+	require.True(t, lock.released.Load())
+}
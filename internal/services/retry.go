@@ -0,0 +1,146 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/celestiaorg/talis/internal/compute"
+	"github.com/celestiaorg/talis/internal/db/models"
+)
+
+// RetryPolicy defines how often and how fast a failed task is retried
+type RetryPolicy struct {
+	// MaxAttempts is the number of times a task is processed before it is marked dead
+	MaxAttempts uint
+	// BaseDelay is the delay before the first retry, it doubles with every further retry
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between two attempts
+	MaxDelay time.Duration
+	// Jitter is the fraction of the delay that is randomized, between 0 and 1
+	Jitter float64
+}
+
+// DefaultRetryPolicy is the retry policy of task actions without a policy of their own
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   30 * time.Second,
+	MaxDelay:    10 * time.Minute,
+	Jitter:      0.2,
+}
+
+// DefaultRetryPolicies are the retry policies of the task actions.
+// Deleting an instance is idempotent, so terminate tasks are retried more often and sooner.
+var DefaultRetryPolicies = map[models.TaskAction]RetryPolicy{
+	models.TaskActionCreateInstances: DefaultRetryPolicy,
+	models.TaskActionTerminateInstances: {
+		MaxAttempts: 10,
+		BaseDelay:   10 * time.Second,
+		MaxDelay:    5 * time.Minute,
+		Jitter:      0.2,
+	},
+}
+
+// ParseRetryPolicy parses a retry policy of the form "attempts=5,base=30s,max=10m,jitter=0.2".
+// Settings that are left out keep their value in base.
+func ParseRetryPolicy(s string, base RetryPolicy) (RetryPolicy, error) {
+	policy := base
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return RetryPolicy{}, fmt.Errorf("invalid retry setting %q, expected <setting>=<value>", entry)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		var err error
+		switch key {
+		case "attempts":
+			var attempts uint64
+			attempts, err = strconv.ParseUint(value, 10, 32)
+			if err == nil && attempts == 0 {
+				err = fmt.Errorf("at least one attempt is required")
+			}
+			policy.MaxAttempts = uint(attempts)
+		case "base":
+			policy.BaseDelay, err = time.ParseDuration(value)
+			if err == nil && policy.BaseDelay < 0 {
+				err = fmt.Errorf("the delay cannot be negative")
+			}
+		case "max":
+			policy.MaxDelay, err = time.ParseDuration(value)
+			if err == nil && policy.MaxDelay < 0 {
+				err = fmt.Errorf("the delay cannot be negative")
+			}
+		case "jitter":
+			policy.Jitter, err = strconv.ParseFloat(value, 64)
+			if err == nil && (policy.Jitter < 0 || policy.Jitter > 1) {
+				err = fmt.Errorf("the jitter must be between 0 and 1")
+			}
+		default:
+			return RetryPolicy{}, fmt.Errorf("unknown retry setting %q", key)
+		}
+		if err != nil {
+			return RetryPolicy{}, fmt.Errorf("invalid retry setting %s=%q: %w", key, value, err)
+		}
+	}
+	return policy, nil
+}
+
+// Delay returns the delay before the next attempt of a task that failed its attempt-th attempt
+func (p RetryPolicy) Delay(attempt uint) time.Duration {
+	delay := p.BaseDelay
+	for i := uint(1); i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		// Spread the delay evenly over [delay*(1-Jitter), delay*(1+Jitter)]
+		// #nosec G404 -- jitter does not need a cryptographically secure source
+		delay += time.Duration((rand.Float64()*2 - 1) * p.Jitter * float64(delay))
+	}
+	return delay
+}
+
+// permanentError marks a task error that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+// Error returns the message of the wrapped error
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// permanent marks err as an error that retrying the task cannot fix
+func permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// isRetryable reports whether a task that failed with err may succeed when retried.
+// Errors are retryable unless they were marked as permanent, such as invalid payloads
+// or missing records, or provisioning of a reachable instance failed.
+func isRetryable(err error) bool {
+	var p *permanentError
+	if errors.As(err, &p) {
+		return false
+	}
+	return !errors.Is(err, compute.ErrProvisioningFailed)
+}
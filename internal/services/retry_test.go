@@ -0,0 +1,67 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/talis/internal/compute"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+	tests := []struct {
+		attempt uint
+		want    time.Duration
+	}{
+		{attempt: 1, want: time.Second},
+		{attempt: 2, want: 2 * time.Second},
+		{attempt: 3, want: 4 * time.Second},
+		{attempt: 4, want: 8 * time.Second},
+		{attempt: 5, want: 10 * time.Second},
+		{attempt: 100, want: 10 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("attempt %d", tt.attempt), func(t *testing.T) {
+			require.Equal(t, tt.want, policy.Delay(tt.attempt))
+		})
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		delay := policy.Delay(2)
+		require.GreaterOrEqual(t, delay, time.Second)
+		require.LessOrEqual(t, delay, 3*time.Second)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	err := errors.New("connection reset")
+	require.True(t, isRetryable(err))
+	require.False(t, isRetryable(permanent(err)))
+	require.False(t, isRetryable(fmt.Errorf("worker: %w", permanent(err))))
+	require.Nil(t, permanent(nil))
+
+	// Playbooks that failed on a reachable instance fail again
+	require.False(t, isRetryable(fmt.Errorf("worker: failed to run ansible playbook: %w", compute.ErrProvisioningFailed)))
+}
+
+func TestParseRetryPolicy(t *testing.T) {
+	policy, err := ParseRetryPolicy("attempts=3, base=1s,max = 1m,jitter=0", DefaultRetryPolicy)
+	require.NoError(t, err)
+	require.Equal(t, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: time.Minute}, policy)
+
+	// Settings that are left out keep their default
+	policy, err = ParseRetryPolicy("attempts=8", DefaultRetryPolicy)
+	require.NoError(t, err)
+	require.Equal(t, uint(8), policy.MaxAttempts)
+	require.Equal(t, DefaultRetryPolicy.BaseDelay, policy.BaseDelay)
+
+	for _, invalid := range []string{"attempts", "attempts=0", "attempts=-1", "base=soon", "max=-1s", "jitter=2", "retries=3"} {
+		_, err := ParseRetryPolicy(invalid, DefaultRetryPolicy)
+		require.Error(t, err, invalid)
+	}
+}
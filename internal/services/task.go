@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/celestiaorg/talis/internal/db/models"
@@ -11,28 +13,74 @@ import (
 	"github.com/celestiaorg/talis/internal/logger"
 )
 
+// DerivedLogsEventLimit is the number of events the logs of a task are rendered from
+const DerivedLogsEventLimit = 200
+
+// ErrTaskTerminated is the cancellation cause of a task that was terminated while being processed
+var ErrTaskTerminated = errors.New("task was terminated")
+
+// ErrTaskLeaseLost is the cancellation cause of a task whose lock was lost while being processed,
+// e.g. because its lock expired and another worker took it over
+var ErrTaskLeaseLost = errors.New("task lock lease was lost")
+
+// ErrTaskFinished is returned when terminating a task that has already finished
+var ErrTaskFinished = errors.New("task has already finished")
+
+// ErrTaskNotDead is returned when requeueing a task that does not exist or is not dead
+var ErrTaskNotDead = errors.New("task not found or not dead")
+
 // Task handles task-related operations
 type Task struct {
 	repo           *repos.TaskRepository
 	projectService *Project
+
+	// running holds the cancel functions of the tasks processed by the workers of this server
+	running   map[uint]context.CancelCauseFunc
+	runningMU sync.Mutex
+
+	// streams wakes up the task event streams served by this server
+	streams            *taskEventHub
+	streamPollInterval time.Duration
+
+	// webhooks queues the status changes of tasks for the webhooks of their owners, may be nil
+	webhooks *Webhook
 }
 
 // NewTaskService creates a new instance of TaskService
 func NewTaskService(repo *repos.TaskRepository, projectService *Project) *Task {
 	return &Task{
-		repo:           repo,
-		projectService: projectService,
+		repo:               repo,
+		projectService:     projectService,
+		running:            make(map[uint]context.CancelCauseFunc),
+		streams:            newTaskEventHub(),
+		streamPollInterval: DefaultTaskStreamPollInterval,
 	}
 }
 
+// WithWebhooks sends the status changes of tasks to the webhooks of their owners
+func (s *Task) WithWebhooks(webhooks *Webhook) *Task {
+	s.webhooks = webhooks
+	return s
+}
+
 // Create creates a new task
 func (s *Task) Create(ctx context.Context, task *models.Task) error {
-	return s.repo.Create(ctx, task)
+	if err := s.repo.Create(ctx, task); err != nil {
+		return err
+	}
+	s.notifyWebhooks(ctx, task)
+	return nil
 }
 
 // CreateBatch creates a batch of tasks
 func (s *Task) CreateBatch(ctx context.Context, tasks []*models.Task) error {
-	return s.repo.CreateBatch(ctx, tasks)
+	if err := s.repo.CreateBatch(ctx, tasks); err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		s.notifyWebhooks(ctx, task)
+	}
+	return nil
 }
 
 // Get retrieves a task by ID
@@ -51,116 +99,262 @@ func (s *Task) ListByProject(ctx context.Context, ownerID uint, projectName stri
 
 // UpdateStatus updates the status of a task
 func (s *Task) UpdateStatus(ctx context.Context, ownerID uint, taskID uint, status models.TaskStatus) error {
-	return s.repo.UpdateStatus(ctx, ownerID, taskID, status)
+	if err := s.repo.UpdateStatus(ctx, ownerID, taskID, status); err != nil {
+		return err
+	}
+	s.streams.publish(taskID)
+	s.notifyWebhooksByID(ctx, taskID)
+	return nil
 }
 
 // Update updates an existing task.
 func (s *Task) Update(ctx context.Context, ownerID uint, task *models.Task) error {
-	return s.repo.Update(ctx, ownerID, task)
+	if err := s.repo.Update(ctx, ownerID, task); err != nil {
+		return err
+	}
+	s.streams.publish(task.ID)
+	return nil
 }
 
 // UpdateFailed updates a task as failed
 func (s *Task) UpdateFailed(ctx context.Context, task *models.Task, errMsg, logMsg string) error {
 	task.Status = models.TaskStatusFailed
 	task.Error += fmt.Sprintf("\n%s", errMsg)
-	task.Logs += fmt.Sprintf("\n%s", logMsg)
-	return s.repo.Update(ctx, task.OwnerID, task)
+	// The event is added first, so that streams ending on the final status include it
+	if err := s.AddEvent(ctx, newTaskEvent(task, models.TaskEventError, "", logMsg, nil)); err != nil {
+		return err
+	}
+	return s.updateAndNotify(ctx, task)
+}
+
+// ScheduleRetry puts a failed task back to pending so that it is processed again at nextRunAt
+func (s *Task) ScheduleRetry(ctx context.Context, task *models.Task, nextRunAt time.Time, errMsg, logMsg string) error {
+	task.Status = models.TaskStatusPending
+	task.NextRunAt = &nextRunAt
+	task.Error += fmt.Sprintf("\n%s", errMsg)
+	err := s.AddEvent(ctx, newTaskEvent(task, models.TaskEventWarn, "", logMsg, models.TaskEventFields{
+		"attempt":     task.Attempts,
+		"next_run_at": nextRunAt.UTC().Format(time.RFC3339),
+	}))
+	if err != nil {
+		return err
+	}
+	return s.updateAndNotify(ctx, task)
 }
 
-// AddLogs appends logs to a task
-func (s *Task) AddLogs(ctx context.Context, ownerID uint, taskID uint, logs string) error {
-	task, err := s.Get(ctx, ownerID, taskID)
+// MarkDead marks a task that used up its retries as dead
+func (s *Task) MarkDead(ctx context.Context, task *models.Task, errMsg, logMsg string) error {
+	task.Status = models.TaskStatusDead
+	task.Error += fmt.Sprintf("\n%s", errMsg)
+	err := s.AddEvent(ctx, newTaskEvent(task, models.TaskEventError, "", logMsg, models.TaskEventFields{
+		"attempt": task.Attempts,
+	}))
 	if err != nil {
-		return fmt.Errorf("failed to get task: %w", err)
+		return err
 	}
+	return s.updateAndNotify(ctx, task)
+}
 
-	// Append new logs with timestamp
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	updatedLogs := fmt.Sprintf("%s\n[%s] %s", task.Logs, timestamp, logs)
+// updateAndNotify updates a task whose status changed and notifies the webhook of its owner
+func (s *Task) updateAndNotify(ctx context.Context, task *models.Task) error {
+	if err := s.Update(ctx, task.OwnerID, task); err != nil {
+		return err
+	}
+	s.notifyWebhooks(ctx, task)
+	return nil
+}
 
-	task.Logs = updatedLogs
-	return s.repo.Update(ctx, ownerID, task)
+// ListDead retrieves the dead tasks of all owners with pagination
+func (s *Task) ListDead(ctx context.Context, opts *models.ListOptions) ([]models.Task, error) {
+	return s.repo.ListByStatus(ctx, models.TaskStatusDead, opts)
 }
 
-// SetResult updates a task with results data
-func (s *Task) SetResult(ctx context.Context, ownerID uint, taskID uint, result json.RawMessage) error {
-	task, err := s.Get(ctx, ownerID, taskID)
+// Requeue puts a dead task back to pending with a fresh set of attempts
+func (s *Task) Requeue(ctx context.Context, taskID uint) error {
+	requeued, err := s.repo.Requeue(ctx, taskID)
 	if err != nil {
-		return fmt.Errorf("failed to get task: %w", err)
+		return err
 	}
+	if !requeued {
+		return fmt.Errorf("task %d: %w", taskID, ErrTaskNotDead)
+	}
+	s.streams.publish(taskID)
+	s.notifyWebhooksByID(ctx, taskID)
+	logger.Infof("🔁 Requeued dead task %d", taskID)
+	return nil
+}
 
-	task.Result = result
-	return s.repo.Update(ctx, ownerID, task)
+// AddLogs appends logs to a task as an info event
+func (s *Task) AddLogs(ctx context.Context, ownerID uint, taskID uint, logs string) error {
+	return s.AddEvent(ctx, &models.TaskEvent{
+		TaskID:  taskID,
+		OwnerID: ownerID,
+		Level:   models.TaskEventInfo,
+		Message: logs,
+	})
 }
 
-// SetError updates a task with error information
-func (s *Task) SetError(ctx context.Context, ownerID uint, taskID uint, errMsg string) error {
-	task, err := s.Get(ctx, ownerID, taskID)
-	if err != nil {
-		return fmt.Errorf("failed to get task: %w", err)
+// AddEvent appends an event to the log of a task
+func (s *Task) AddEvent(ctx context.Context, event *models.TaskEvent) error {
+	if err := s.repo.AddEvents(ctx, event); err != nil {
+		return err
 	}
+	s.streams.publish(event.TaskID)
+	return nil
+}
 
-	task.Error = errMsg
-	task.Status = models.TaskStatusFailed
+// ListEvents retrieves the events of a task after the event afterID, oldest first, with pagination
+func (s *Task) ListEvents(ctx context.Context, ownerID, taskID, afterID uint, opts *models.ListOptions) ([]models.TaskEvent, error) {
+	return s.repo.ListEvents(ctx, ownerID, taskID, afterID, opts)
+}
 
-	if err := s.repo.Update(ctx, ownerID, task); err != nil {
+// DeriveLogs sets the Logs of a task from its last DerivedLogsEventLimit events, after the logs
+// written before task events were introduced. Older events are only listed by ListEvents.
+func (s *Task) DeriveLogs(ctx context.Context, task *models.Task) error {
+	// One more event tells whether older events are left out
+	events, err := s.repo.ListLastEvents(ctx, task.ID, DerivedLogsEventLimit+1)
+	if err != nil {
 		return err
 	}
+	truncated := len(events) > DerivedLogsEventLimit
+	if truncated {
+		events = events[1:]
+	}
+	task.Logs = models.TaskLogs(task.Logs, events, truncated)
+	return nil
+}
 
-	// Send webhook notification if configured
-	if task.WebhookURL != "" && !task.WebhookSent {
-		if err := task.SendWebhook(); err != nil {
-			// Log webhook error but don't fail the operation
-			return nil
-		}
+// recordEvent appends an event to the log of a task being processed. Failing to record an event
+// does not fail the task, the error is only logged.
+func (s *Task) recordEvent(ctx context.Context, task *models.Task, level models.TaskEventLevel, step models.TaskStep, message string, fields models.TaskEventFields) {
+	if task == nil {
+		logger.Warnf("Attempted to add an event to a nil task: %s", message)
+		return
+	}
+	if err := s.AddEvent(ctx, newTaskEvent(task, level, step, message, fields)); err != nil {
+		logger.Errorf("failed to add event to task %d: %v", task.ID, err)
+	}
+}
+
+// notifyWebhooks queues the status of a task for the webhook of its owner. Failing to queue
+// the event does not fail the status change, the error is only logged.
+func (s *Task) notifyWebhooks(ctx context.Context, task *models.Task) {
+	if s.webhooks == nil {
+		return
+	}
+	if err := s.webhooks.EnqueueTaskEvent(ctx, task); err != nil {
+		logger.Errorf("failed to queue webhook for task %d: %v", task.ID, err)
+	}
+}
 
-		// Mark webhook as sent
-		task.WebhookSent = true
-		return s.repo.Update(ctx, ownerID, task)
+// notifyWebhooksByID queues the status of a task that changed in the database for the webhook
+// of its owner
+func (s *Task) notifyWebhooksByID(ctx context.Context, taskID uint) {
+	if s.webhooks == nil {
+		return
 	}
+	task, err := s.repo.GetByID(ctx, models.AdminID, taskID)
+	if err != nil {
+		logger.Errorf("failed to get task %d to queue its webhook: %v", taskID, err)
+		return
+	}
+	s.notifyWebhooks(ctx, task)
+}
 
-	return nil
+// newTaskEvent creates an event of the task
+func newTaskEvent(task *models.Task, level models.TaskEventLevel, step models.TaskStep, message string, fields models.TaskEventFields) *models.TaskEvent {
+	return &models.TaskEvent{
+		TaskID:  task.ID,
+		OwnerID: task.OwnerID,
+		Level:   level,
+		Step:    step,
+		Message: message,
+		Fields:  fields,
+	}
 }
 
-// CompleteTask marks a task as completed and sends webhook if configured
-func (s *Task) CompleteTask(ctx context.Context, ownerID uint, taskID uint, result json.RawMessage) error {
-	task, err := s.Get(ctx, ownerID, taskID)
+// Checkpoint records the last step of a task completed by a worker with the payload it produced,
+// so that retries resume after the step instead of repeating it
+func (s *Task) Checkpoint(ctx context.Context, task *models.Task, step models.TaskStep, payload interface{}) error {
+	data, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("failed to get task: %w", err)
+		return fmt.Errorf("failed to marshal payload of task %d: %w", task.ID, err)
+	}
+	if err := s.repo.UpdateCheckpoint(ctx, task.OwnerID, task.ID, step, data); err != nil {
+		return fmt.Errorf("failed to checkpoint task %d: %w", task.ID, err)
 	}
+	task.Checkpoint = step
+	task.Payload = data
+	return nil
+}
 
-	task.Status = models.TaskStatusCompleted
-	if result != nil {
-		task.Result = result
+// SetResult records the result of the last attempt of a task
+func (s *Task) SetResult(ctx context.Context, task *models.Task, result *models.TaskResult) error {
+	if err := s.repo.UpdateResult(ctx, task.OwnerID, task.ID, result); err != nil {
+		return fmt.Errorf("failed to update result of task %d: %w", task.ID, err)
 	}
+	task.Result = result
+	return nil
+}
 
-	if err := s.repo.Update(ctx, ownerID, task); err != nil {
+// Terminate marks a pending or running task as terminated and cancels its processing.
+// Tasks processed by the workers of this server are cancelled right away, workers of other
+// servers notice the terminated status when they next check it.
+func (s *Task) Terminate(ctx context.Context, ownerID uint, taskID uint) error {
+	task, err := s.Get(ctx, ownerID, taskID)
+	if err != nil {
 		return err
 	}
 
-	// Send webhook notification if configured
-	if task.WebhookURL != "" && !task.WebhookSent {
-		if err := task.SendWebhook(); err != nil {
-			// Log webhook error but don't fail the operation
-			return nil
+	terminated, err := s.repo.Terminate(ctx, ownerID, taskID)
+	if err != nil {
+		return err
+	}
+	if !terminated {
+		// The task finished between the lookup and the update, report its final status
+		if task, err = s.Get(ctx, ownerID, taskID); err != nil {
+			return err
 		}
+		return fmt.Errorf("task %d is %s: %w", taskID, task.Status, ErrTaskFinished)
+	}
+
+	s.streams.publish(taskID)
+	s.notifyWebhooksByID(ctx, taskID)
 
-		// Mark webhook as sent
-		task.WebhookSent = true
-		return s.repo.Update(ctx, ownerID, task)
+	s.runningMU.Lock()
+	cancel, ok := s.running[taskID]
+	s.runningMU.Unlock()
+	if ok {
+		cancel(ErrTaskTerminated)
 	}
 
+	logger.Infof("🛑 Terminated %s task %d", task.Action, taskID)
 	return nil
 }
 
+// trackRunning registers the cancel function of a task being processed so that
+// Terminate can cancel it
+func (s *Task) trackRunning(taskID uint, cancel context.CancelCauseFunc) {
+	s.runningMU.Lock()
+	defer s.runningMU.Unlock()
+	s.running[taskID] = cancel
+}
+
+// untrackRunning removes the cancel function of a task once it has been processed
+func (s *Task) untrackRunning(taskID uint) {
+	s.runningMU.Lock()
+	defer s.runningMU.Unlock()
+	delete(s.running, taskID)
+}
+
 // ErrTaskLockNotAcquired is returned when a task lock could not be acquired
 var ErrTaskLockNotAcquired = fmt.Errorf("task lock could not be acquired")
 
-// AcquireTaskLock attempts to lock a task for processing
+// AcquireTaskLock attempts to lock a task for processing by the given worker
 // Returns nil if the lock was acquired, ErrTaskLockNotAcquired if the lock was not acquired,
 // or another error if there was a problem with the database operation
-func (s *Task) AcquireTaskLock(ctx context.Context, taskID uint) error {
-	locked, err := s.repo.AcquireTaskLock(ctx, taskID)
+func (s *Task) AcquireTaskLock(ctx context.Context, taskID uint, workerID string) error {
+	locked, err := s.repo.AcquireTaskLock(ctx, taskID, workerID)
 	if err != nil {
 		return fmt.Errorf("failed to acquire task lock: %w", err)
 	}
@@ -169,32 +363,63 @@ func (s *Task) AcquireTaskLock(ctx context.Context, taskID uint) error {
 		return ErrTaskLockNotAcquired
 	}
 
+	// Locking a task marks it as running
+	s.streams.publish(taskID)
+	s.notifyWebhooksByID(ctx, taskID)
 	return nil
 }
 
-// ReleaseTaskLock releases a task lock
-func (s *Task) ReleaseTaskLock(ctx context.Context, taskID uint) error {
-	return s.repo.ReleaseTaskLock(ctx, taskID)
+// RenewTaskLock extends the lock the given worker holds on a task.
+// Returns ErrTaskLeaseLost if the worker no longer holds the lock.
+func (s *Task) RenewTaskLock(ctx context.Context, taskID uint, workerID string) error {
+	renewed, err := s.repo.RenewTaskLock(ctx, taskID, workerID)
+	if err != nil {
+		return err
+	}
+	if !renewed {
+		return fmt.Errorf("task %d: %w", taskID, ErrTaskLeaseLost)
+	}
+	return nil
 }
 
-// RecoverStaleTasks finds tasks that were in progress when the system crashed
-// and resets them to pending status with incremented attempts
-func (s *Task) RecoverStaleTasks(ctx context.Context) (int64, error) {
-	count, err := s.repo.RecoverStaleTasks(ctx)
+// ReleaseTaskLock releases the lock the given worker holds on a task
+func (s *Task) ReleaseTaskLock(ctx context.Context, taskID uint, workerID string) error {
+	return s.repo.ReleaseTaskLock(ctx, taskID, workerID)
+}
+
+// RecoverStaleTasks finds tasks locked by workers without a heartbeat since heartbeatDeadline
+// and resets them to pending status
+func (s *Task) RecoverStaleTasks(ctx context.Context, heartbeatDeadline time.Time) (int64, error) {
+	recovered, err := s.repo.RecoverStaleTasks(ctx, heartbeatDeadline)
 	if err != nil {
 		return 0, err
 	}
 
-	if count > 0 {
-		logger.Infof("Recovered %d stale tasks that were in progress during system restart", count)
+	for _, taskID := range recovered {
+		s.notifyWebhooksByID(ctx, taskID)
+	}
+	if len(recovered) > 0 {
+		logger.Infof("♻️ Recovered %d stale tasks of stopped workers", len(recovered))
 	}
 
-	return count, nil
+	return int64(len(recovered)), nil
+}
+
+// GetSchedulableTasks retrieves tasks ready for the worker to process, round-robin across owners.
+// Tasks excluded by filter are left out, filter may be nil.
+func (s *Task) GetSchedulableTasks(ctx context.Context, priority models.TaskPriority, limit int, filter *models.TaskSchedulingFilter) ([]models.Task, error) {
+	return s.repo.GetSchedulableTasks(ctx, priority, limit, filter)
+}
+
+// GetNextRunAt returns the earliest time a task of the priority waiting for its retry is due,
+// or nil if no task is waiting
+func (s *Task) GetNextRunAt(ctx context.Context, priority models.TaskPriority) (*time.Time, error) {
+	return s.repo.GetNextRunAt(ctx, priority)
 }
 
-// GetSchedulableTasks retrieves tasks ready for the worker to process.
-func (s *Task) GetSchedulableTasks(ctx context.Context, priority models.TaskPriority, limit int) ([]models.Task, error) {
-	return s.repo.GetSchedulableTasks(ctx, priority, limit)
+// CountRunningTasks counts the tasks processed by workers other than excludeWorkerID
+func (s *Task) CountRunningTasks(ctx context.Context, excludeWorkerID string) ([]models.RunningTaskCount, error) {
+	return s.repo.CountRunningTasks(ctx, excludeWorkerID)
 }
 
 // IncrementAttempts atomically increments the attempts count for a task
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+)
+
+// DefaultTaskStreamPollInterval is how often a task event stream checks its tasks when no
+// notification woke it up, e.g. because the task is processed by a worker of another server
+// and task event notifications are not available
+const DefaultTaskStreamPollInterval = 5 * time.Second
+
+// taskStreamBatchSize is the number of events a stream fetches at once
+const taskStreamBatchSize = 100
+
+// taskStreamClockSkew is how far back a project stream looks for updated tasks, so that tasks
+// updated by servers with a slightly different clock are not missed
+const taskStreamClockSkew = 5 * time.Second
+
+// TaskStreamFilter selects the tasks followed by a task event stream
+type TaskStreamFilter struct {
+	// OwnerID is the owner of the tasks
+	OwnerID uint
+	// TaskID follows a single task, the stream ends once the task is finished
+	TaskID uint
+	// ProjectID follows all the tasks of a project, the stream only ends when it is cancelled
+	ProjectID uint
+}
+
+// WithStreamPollInterval sets how often task event streams check their tasks when no
+// notification woke them up
+func (s *Task) WithStreamPollInterval(interval time.Duration) *Task {
+	if interval > 0 {
+		s.streamPollInterval = interval
+	}
+	return s
+}
+
+// ForwardEventNotifications wakes up the task event streams of this server on every task event
+// notification until ctx is cancelled or notifications is closed. The payload of a notification
+// is the ID of the task, streams of all tasks are woken up if it is missing.
+func (s *Task) ForwardEventNotifications(ctx context.Context, notifications <-chan string) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case n, ok := <-notifications:
+			if !ok {
+				return
+			}
+			taskID, err := strconv.ParseUint(n, 10, 64)
+			if err != nil {
+				taskID = 0
+			}
+			s.streams.publish(uint(taskID))
+		}
+	}
+}
+
+// LastProjectEventID returns the ID of the latest event of the tasks of a project
+func (s *Task) LastProjectEventID(ctx context.Context, ownerID, projectID uint) (uint, error) {
+	return s.repo.LastProjectEventID(ctx, ownerID, projectID)
+}
+
+// StreamEvents calls send with the events and the status changes of the tasks selected by the
+// filter, as they happen, until ctx is cancelled or send fails. Events after the event afterID
+// are sent first. The stream of a single task sends its status right away and ends with a
+// models.TaskStreamEnd message once the task is finished.
+func (s *Task) StreamEvents(ctx context.Context, filter TaskStreamFilter, afterID uint, send func(models.TaskStreamMessage) error) error {
+	if (filter.TaskID == 0) == (filter.ProjectID == 0) {
+		return fmt.Errorf("a task event stream follows either a task or a project")
+	}
+
+	wakeup, unsubscribe := s.streams.subscribe(filter.TaskID)
+	defer unsubscribe()
+	ticker := time.NewTicker(s.streamPollInterval)
+	defer ticker.Stop()
+
+	statuses := make(map[uint]models.TaskStatus)
+	updatedSince := time.Now().Add(-taskStreamClockSkew)
+	for {
+		// Tasks are fetched before their events: events are added before the status of a task
+		// changes, so all the events of a finished task are sent before the stream ends
+		checkedAt := time.Now()
+		tasks, err := s.streamedTasks(ctx, filter, updatedSince)
+		if err != nil {
+			return err
+		}
+		updatedSince = checkedAt.Add(-taskStreamClockSkew)
+
+		if afterID, err = s.sendEvents(ctx, filter, afterID, send); err != nil {
+			return err
+		}
+
+		for _, task := range tasks {
+			if statuses[task.ID] == task.Status {
+				continue
+			}
+			statuses[task.ID] = task.Status
+			err := send(models.TaskStreamMessage{
+				Type:   models.TaskStreamStatus,
+				TaskID: task.ID,
+				Status: task.Status,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if filter.TaskID != 0 && tasks[0].Status.IsFinal() {
+			return send(models.TaskStreamMessage{
+				Type:   models.TaskStreamEnd,
+				TaskID: filter.TaskID,
+				Status: tasks[0].Status,
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-wakeup:
+		case <-ticker.C:
+		}
+	}
+}
+
+// streamedTasks returns the task followed by a stream, or the tasks of the followed project
+// updated since the given time
+func (s *Task) streamedTasks(ctx context.Context, filter TaskStreamFilter, updatedSince time.Time) ([]models.Task, error) {
+	if filter.TaskID != 0 {
+		task, err := s.Get(ctx, filter.OwnerID, filter.TaskID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get task: %w", err)
+		}
+		return []models.Task{*task}, nil
+	}
+	return s.repo.ListByProjectUpdatedSince(ctx, filter.OwnerID, filter.ProjectID, updatedSince)
+}
+
+// sendEvents sends the events of the streamed tasks after the event afterID and returns the ID
+// of the last event sent
+func (s *Task) sendEvents(ctx context.Context, filter TaskStreamFilter, afterID uint, send func(models.TaskStreamMessage) error) (uint, error) {
+	opts := &models.ListOptions{Limit: taskStreamBatchSize}
+	for {
+		var events []models.TaskEvent
+		var err error
+		if filter.TaskID != 0 {
+			events, err = s.repo.ListEvents(ctx, filter.OwnerID, filter.TaskID, afterID, opts)
+		} else {
+			events, err = s.repo.ListProjectEvents(ctx, filter.OwnerID, filter.ProjectID, afterID, opts)
+		}
+		if err != nil {
+			return afterID, err
+		}
+
+		for i := range events {
+			err := send(models.TaskStreamMessage{
+				Type:   models.TaskStreamEvent,
+				TaskID: events[i].TaskID,
+				Event:  &events[i],
+			})
+			if err != nil {
+				return afterID, err
+			}
+			afterID = events[i].ID
+		}
+		if len(events) < taskStreamBatchSize {
+			return afterID, nil
+		}
+	}
+}
+
+// taskEventHub wakes up the task event streams of this server when a task changes
+type taskEventHub struct {
+	mu          sync.Mutex
+	subscribers map[*taskSubscription]struct{}
+}
+
+// taskSubscription is a stream waiting for changes of a task, or of any task if taskID is 0
+type taskSubscription struct {
+	taskID uint
+	wakeup chan struct{}
+}
+
+// newTaskEventHub creates a hub without subscribers
+func newTaskEventHub() *taskEventHub {
+	return &taskEventHub{
+		subscribers: make(map[*taskSubscription]struct{}),
+	}
+}
+
+// subscribe returns a channel signaled when the task changes, or when any task changes if taskID
+// is 0, and the function that removes the subscription
+func (h *taskEventHub) subscribe(taskID uint) (<-chan struct{}, func()) {
+	sub := &taskSubscription{
+		taskID: taskID,
+		wakeup: make(chan struct{}, 1),
+	}
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+
+	return sub.wakeup, func() {
+		h.mu.Lock()
+		delete(h.subscribers, sub)
+		h.mu.Unlock()
+	}
+}
+
+// publish wakes up the streams of a task without blocking, or all streams if taskID is 0.
+// A pending signal already wakes up a stream, so signals are never queued.
+func (h *taskEventHub) publish(taskID uint) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subscribers {
+		if taskID != 0 && sub.taskID != 0 && sub.taskID != taskID {
+			continue
+		}
+		select {
+		case sub.wakeup <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+)
+
+// createStreamedTask creates a task with the given status and events
+func createStreamedTask(t *testing.T, ts *TestSetup, status models.TaskStatus, messages ...string) (*models.Task, []*models.TaskEvent) {
+	task := &models.Task{
+		OwnerID:   1,
+		ProjectID: 1,
+		Action:    models.TaskActionCreateInstances,
+		Status:    status,
+	}
+	require.NoError(t, ts.TaskRepo.Create(ts.ctx, task))
+
+	events := make([]*models.TaskEvent, 0, len(messages))
+	for _, message := range messages {
+		event := &models.TaskEvent{TaskID: task.ID, OwnerID: task.OwnerID, Message: message}
+		require.NoError(t, ts.TaskService.AddEvent(ts.ctx, event))
+		events = append(events, event)
+	}
+	return task, events
+}
+
+func TestTaskService_StreamEvents_FinishedTask(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	task, events := createStreamedTask(t, ts, models.TaskStatusCompleted, "first", "second")
+
+	var messages []models.TaskStreamMessage
+	err := ts.TaskService.StreamEvents(ts.ctx, TaskStreamFilter{OwnerID: 1, TaskID: task.ID}, 0, func(msg models.TaskStreamMessage) error {
+		messages = append(messages, msg)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// Events come first, the final status and the end of the stream last
+	require.Len(t, messages, 4)
+	assert.Equal(t, models.TaskStreamEvent, messages[0].Type)
+	assert.Equal(t, events[0].ID, messages[0].Event.ID)
+	assert.Equal(t, models.TaskStreamEvent, messages[1].Type)
+	assert.Equal(t, events[1].ID, messages[1].Event.ID)
+	assert.Equal(t, models.TaskStreamMessage{Type: models.TaskStreamStatus, TaskID: task.ID, Status: models.TaskStatusCompleted}, messages[2])
+	assert.Equal(t, models.TaskStreamMessage{Type: models.TaskStreamEnd, TaskID: task.ID, Status: models.TaskStatusCompleted}, messages[3])
+}
+
+func TestTaskService_StreamEvents_ResumeAndFollow(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	task, events := createStreamedTask(t, ts, models.TaskStatusRunning, "first", "second", "third")
+
+	ctx, cancel := context.WithTimeout(ts.ctx, 10*time.Second)
+	defer cancel()
+
+	received := make(chan models.TaskStreamMessage, 10)
+	done := make(chan error, 1)
+	go func() {
+		defer close(received)
+		done <- ts.TaskService.StreamEvents(ctx, TaskStreamFilter{OwnerID: 1, TaskID: task.ID}, events[0].ID, func(msg models.TaskStreamMessage) error {
+			received <- msg
+			return nil
+		})
+	}()
+
+	// Only the events after the one the stream resumes from are sent, then the current status
+	for _, want := range events[1:] {
+		msg := <-received
+		require.Equal(t, models.TaskStreamEvent, msg.Type)
+		assert.Equal(t, want.ID, msg.Event.ID)
+	}
+	msg := <-received
+	assert.Equal(t, models.TaskStreamMessage{Type: models.TaskStreamStatus, TaskID: task.ID, Status: models.TaskStatusRunning}, msg)
+
+	// Events and status changes are pushed as they happen
+	last := &models.TaskEvent{TaskID: task.ID, OwnerID: task.OwnerID, Message: "fourth"}
+	require.NoError(t, ts.TaskService.AddEvent(ts.ctx, last))
+	msg = <-received
+	require.Equal(t, models.TaskStreamEvent, msg.Type)
+	assert.Equal(t, last.ID, msg.Event.ID)
+
+	require.NoError(t, ts.TaskService.UpdateStatus(ts.ctx, task.OwnerID, task.ID, models.TaskStatusCompleted))
+	msg = <-received
+	assert.Equal(t, models.TaskStreamMessage{Type: models.TaskStreamStatus, TaskID: task.ID, Status: models.TaskStatusCompleted}, msg)
+	msg = <-received
+	assert.Equal(t, models.TaskStreamEnd, msg.Type)
+
+	require.NoError(t, <-done)
+	_, open := <-received
+	assert.False(t, open, "no message should follow the end of the stream")
+}
+
+func TestTaskService_StreamEvents_Project(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	first, _ := createStreamedTask(t, ts, models.TaskStatusRunning, "old event")
+	lastID, err := ts.TaskService.LastProjectEventID(ts.ctx, 1, 1)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(ts.ctx, 10*time.Second)
+	defer cancel()
+
+	received := make(chan models.TaskStreamMessage, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- ts.TaskService.StreamEvents(ctx, TaskStreamFilter{OwnerID: 1, ProjectID: 1}, lastID, func(msg models.TaskStreamMessage) error {
+			received <- msg
+			return nil
+		})
+	}()
+
+	// The project stream reports the tasks updated recently, not the events it started after
+	msg := <-received
+	assert.Equal(t, models.TaskStreamMessage{Type: models.TaskStreamStatus, TaskID: first.ID, Status: models.TaskStatusRunning}, msg)
+
+	// The stream may wake up between the creation of the task and its event, so the order of
+	// the two messages is not fixed
+	second, events := createStreamedTask(t, ts, models.TaskStatusPending, "new event")
+	var gotEvent, gotStatus bool
+	for i := 0; i < 2; i++ {
+		msg = <-received
+		switch msg.Type {
+		case models.TaskStreamEvent:
+			assert.Equal(t, events[0].ID, msg.Event.ID)
+			gotEvent = true
+		case models.TaskStreamStatus:
+			assert.Equal(t, models.TaskStreamMessage{Type: models.TaskStreamStatus, TaskID: second.ID, Status: models.TaskStatusPending}, msg)
+			gotStatus = true
+		}
+	}
+	assert.True(t, gotEvent, "the event of the new task should be streamed")
+	assert.True(t, gotStatus, "the status of the new task should be streamed")
+
+	// Project streams only end when they are cancelled
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestTaskService_StreamEvents_InvalidFilter(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	send := func(models.TaskStreamMessage) error { return nil }
+	assert.Error(t, ts.TaskService.StreamEvents(ts.ctx, TaskStreamFilter{OwnerID: 1}, 0, send))
+	assert.Error(t, ts.TaskService.StreamEvents(ts.ctx, TaskStreamFilter{OwnerID: 1, TaskID: 1, ProjectID: 1}, 0, send))
+}
+
+func TestTaskService_WithStreamPollInterval(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	ts.TaskService.WithStreamPollInterval(0)
+	assert.Equal(t, DefaultTaskStreamPollInterval, ts.TaskService.streamPollInterval)
+	ts.TaskService.WithStreamPollInterval(-time.Second)
+	assert.Equal(t, DefaultTaskStreamPollInterval, ts.TaskService.streamPollInterval)
+	ts.TaskService.WithStreamPollInterval(time.Second)
+	assert.Equal(t, time.Second, ts.TaskService.streamPollInterval)
+}
+
+func TestTaskEventHub(t *testing.T) {
+	hub := newTaskEventHub()
+	task1, unsubscribe1 := hub.subscribe(1)
+	defer unsubscribe1()
+	all, unsubscribeAll := hub.subscribe(0)
+
+	signaled := func(wakeup <-chan struct{}) bool {
+		select {
+		case <-wakeup:
+			return true
+		default:
+			return false
+		}
+	}
+
+	// Changes of another task only wake up the streams of all tasks
+	hub.publish(2)
+	assert.False(t, signaled(task1))
+	assert.True(t, signaled(all))
+
+	// Signals are coalesced
+	hub.publish(1)
+	hub.publish(1)
+	assert.True(t, signaled(task1))
+	assert.False(t, signaled(task1))
+	assert.True(t, signaled(all))
+
+	// Unknown tasks wake up every stream
+	hub.publish(0)
+	assert.True(t, signaled(task1))
+	assert.True(t, signaled(all))
+
+	unsubscribeAll()
+	hub.publish(0)
+	assert.False(t, signaled(all))
+}
+
+func TestTaskService_ForwardEventNotifications(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	wakeup, unsubscribe := ts.TaskService.streams.subscribe(7)
+	defer unsubscribe()
+
+	notifications := make(chan string, 2)
+	notifications <- "3"
+	notifications <- "7"
+	close(notifications)
+	ts.TaskService.ForwardEventNotifications(ts.ctx, notifications)
+
+	select {
+	case <-wakeup:
+	default:
+		t.Fatal("the notification of task 7 should wake up its stream")
+	}
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+)
+
+// ErrTaskTimeout is the cancellation cause of a task or a step of a task that ran longer than
+// its timeout
+var ErrTaskTimeout = errors.New("task timed out")
+
+// TaskTimeouts bound how long a task and its steps may run. Zero timeouts do not bound.
+type TaskTimeouts struct {
+	// Task bounds an attempt of the task as a whole
+	Task time.Duration
+	// Steps bound the steps of the task that call out to providers and provisioners
+	Steps map[models.TaskStep]time.Duration
+}
+
+// DefaultTaskTimeouts are the timeouts of the task actions.
+// Provisioning installs packages and runs the payload, so it is given the most time.
+var DefaultTaskTimeouts = map[models.TaskAction]TaskTimeouts{
+	models.TaskActionCreateInstances: {
+		Task: 45 * time.Minute,
+		Steps: map[models.TaskStep]time.Duration{
+			models.TaskStepCreate:    15 * time.Minute,
+			models.TaskStepProvision: 30 * time.Minute,
+		},
+	},
+	models.TaskActionTerminateInstances: {
+		Task: 15 * time.Minute,
+		Steps: map[models.TaskStep]time.Duration{
+			models.TaskStepTerminate: 10 * time.Minute,
+		},
+	},
+}
+
+// ParseTaskTimeouts parses task timeouts of the form "task=45m,create=15m,provision=30m",
+// where the keys besides "task" are steps. Timeouts that are left out keep their value in base.
+func ParseTaskTimeouts(s string, base TaskTimeouts) (TaskTimeouts, error) {
+	timeouts := TaskTimeouts{Task: base.Task, Steps: make(map[models.TaskStep]time.Duration, len(base.Steps))}
+	for step, timeout := range base.Steps {
+		timeouts.Steps[step] = timeout
+	}
+
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return TaskTimeouts{}, fmt.Errorf("invalid timeout %q, expected <task|step>=<duration>", entry)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		timeout, err := time.ParseDuration(value)
+		if err == nil && timeout < 0 {
+			err = fmt.Errorf("the timeout cannot be negative")
+		}
+		if err != nil {
+			return TaskTimeouts{}, fmt.Errorf("invalid timeout %q: %w", key, err)
+		}
+
+		switch step := models.TaskStep(key); step {
+		case "task":
+			timeouts.Task = timeout
+		case models.TaskStepCreate, models.TaskStepProvision, models.TaskStepTerminate:
+			timeouts.Steps[step] = timeout
+		default:
+			return TaskTimeouts{}, fmt.Errorf("unknown timeout %q", key)
+		}
+	}
+	return timeouts, nil
+}
+
+// withTimeout returns a context that is cancelled with a cause wrapping ErrTaskTimeout after
+// timeout, or ctx itself if timeout is zero
+func withTimeout(ctx context.Context, timeout time.Duration, what string) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeoutCause(ctx, timeout, fmt.Errorf("%w: %s exceeded %s", ErrTaskTimeout, what, timeout))
+}
+
+// timeoutError returns err annotated with the timeout that caused it, if ctx timed out.
+// Timeouts are permanent, an attempt that ran out of time is not retried.
+func timeoutError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	cause := context.Cause(ctx)
+	if !errors.Is(cause, ErrTaskTimeout) {
+		return err
+	}
+	if errors.Is(err, ErrTaskTimeout) {
+		return permanent(err)
+	}
+	return permanent(fmt.Errorf("%w: %w", cause, err))
+}
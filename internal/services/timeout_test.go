@@ -0,0 +1,31 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+)
+
+func TestParseTaskTimeouts(t *testing.T) {
+	base := DefaultTaskTimeouts[models.TaskActionCreateInstances]
+	timeouts, err := ParseTaskTimeouts("task=1h, provision = 40m,create=0", base)
+	require.NoError(t, err)
+	require.Equal(t, TaskTimeouts{
+		Task: time.Hour,
+		Steps: map[models.TaskStep]time.Duration{
+			models.TaskStepCreate:    0,
+			models.TaskStepProvision: 40 * time.Minute,
+		},
+	}, timeouts)
+
+	// The defaults are not changed
+	require.Equal(t, 15*time.Minute, base.Steps[models.TaskStepCreate])
+
+	for _, invalid := range []string{"task", "task=soon", "provision=-1m", "payload=1m", "attempts=3"} {
+		_, err := ParseTaskTimeouts(invalid, base)
+		require.Error(t, err, invalid)
+	}
+}
@@ -2,7 +2,11 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"net/url"
 
 	"github.com/celestiaorg/talis/internal/db/models"
 	"github.com/celestiaorg/talis/internal/db/repos"
@@ -17,8 +21,12 @@ type User struct {
 var (
 	ErrUserNotFound     = errors.New("user not found")
 	ErrUserCreateFailed = errors.New("failed to create user")
+	ErrInvalidWebhook   = errors.New("invalid webhook URL")
 )
 
+// webhookSecretBytes is the number of random bytes of a webhook secret
+const webhookSecretBytes = 32
+
 // NewUserService creates a new user service instance
 func NewUserService(repo *repos.UserRepository) *User {
 	return &User{
@@ -61,3 +69,29 @@ func (s User) GetAllUsers(ctx context.Context, opts *models.ListOptions) ([]mode
 func (s User) DeleteUser(ctx context.Context, userID uint) error {
 	return s.repo.DeleteUser(ctx, userID)
 }
+
+// SetWebhook sets the URL the task and instance events of a user are sent to and returns the
+// new secret the requests are signed with. An empty URL disables the webhook.
+func (s User) SetWebhook(ctx context.Context, userID uint, webhookURL string) (string, error) {
+	if webhookURL == "" {
+		return "", s.repo.UpdateWebhook(ctx, userID, "", "")
+	}
+
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return "", errors.Join(ErrInvalidWebhook, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("%w: an http or https URL is required", ErrInvalidWebhook)
+	}
+
+	secret := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	encoded := hex.EncodeToString(secret)
+	if err := s.repo.UpdateWebhook(ctx, userID, webhookURL, encoded); err != nil {
+		return "", err
+	}
+	return encoded, nil
+}
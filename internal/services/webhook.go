@@ -0,0 +1,322 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+	"github.com/celestiaorg/talis/internal/db/repos"
+	"github.com/celestiaorg/talis/internal/logger"
+)
+
+const (
+	// DefaultWebhookPollInterval is the default interval between two checks for due webhook deliveries
+	DefaultWebhookPollInterval = 5 * time.Second
+
+	// webhookBatchSize is the number of deliveries claimed at once
+	webhookBatchSize = 20
+	// webhookClaimLease is how long claimed deliveries are reserved for a deliverer. It covers a
+	// whole batch of requests timing out, so that deliveries are not sent twice at the same time.
+	webhookClaimLease = 2 * webhookBatchSize * models.WebhookTimeout
+)
+
+// ErrWebhookAddressNotAllowed is returned when a webhook resolves to an address of the private
+// network of the server, such as a loopback, private or link-local address
+var ErrWebhookAddressNotAllowed = errors.New("webhook address is not allowed")
+
+// webhookBlockedPrefixes are the ranges that are not reachable by webhooks on top of the
+// loopback, private, link-local, multicast and unspecified addresses
+var webhookBlockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "This" network
+	netip.MustParsePrefix("100.64.0.0/10"), // Carrier-grade NAT, also used by cloud metadata services
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // Benchmarking
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64, embeds IPv4 addresses
+}
+
+// DefaultWebhookRetryPolicy is the retry policy of webhook deliveries
+var DefaultWebhookRetryPolicy = RetryPolicy{
+	MaxAttempts: 8,
+	BaseDelay:   10 * time.Second,
+	MaxDelay:    time.Hour,
+	Jitter:      0.2,
+}
+
+// Webhook writes the task and instance events of users with a webhook to the delivery outbox
+type Webhook struct {
+	repo     *repos.WebhookRepository
+	userRepo *repos.UserRepository
+}
+
+// NewWebhookService creates a new instance of Webhook service
+func NewWebhookService(repo *repos.WebhookRepository, userRepo *repos.UserRepository) *Webhook {
+	return &Webhook{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+// EnqueueTaskEvent queues the current status of a task for delivery to the webhook of its owner
+func (s *Webhook) EnqueueTaskEvent(ctx context.Context, task *models.Task) error {
+	return s.enqueue(ctx, task.OwnerID, task.ID, 0, models.WebhookPayload{
+		Event: models.WebhookEventTaskStatusChanged,
+		Task:  models.NewWebhookTask(task),
+	})
+}
+
+// EnqueueInstanceEvent queues the current status of an instance for delivery to the webhook of its owner
+func (s *Webhook) EnqueueInstanceEvent(ctx context.Context, instance *models.Instance) error {
+	return s.enqueue(ctx, instance.OwnerID, 0, instance.ID, models.WebhookPayload{
+		Event:    models.WebhookEventInstanceStatusChanged,
+		Instance: models.NewWebhookInstance(instance),
+	})
+}
+
+// enqueue adds a delivery of the payload to the outbox if the owner has a webhook
+func (s *Webhook) enqueue(ctx context.Context, ownerID, taskID, instanceID uint, payload models.WebhookPayload) error {
+	user, err := s.userRepo.GetUserByID(ctx, ownerID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Resources of the admin placeholder and of deleted users have no webhook
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.WebhookURL == "" {
+		return nil
+	}
+
+	now := time.Now()
+	payload.CreatedAt = now.UTC()
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	return s.repo.CreateDelivery(ctx, &models.WebhookDelivery{
+		OwnerID:       ownerID,
+		Event:         payload.Event,
+		TaskID:        taskID,
+		InstanceID:    instanceID,
+		URL:           user.WebhookURL,
+		Payload:       body,
+		Status:        models.WebhookDeliveryPending,
+		NextAttemptAt: &now,
+	})
+}
+
+// ListDeliveries retrieves the webhook deliveries of an owner with their attempts, optionally
+// filtered by task or instance, with pagination
+func (s *Webhook) ListDeliveries(ctx context.Context, ownerID, taskID, instanceID uint, opts *models.ListOptions) ([]models.WebhookDelivery, error) {
+	return s.repo.List(ctx, ownerID, taskID, instanceID, opts)
+}
+
+// SignWebhook returns the value of the signature header of a webhook request with the body,
+// the hex encoded HMAC-SHA256 of the body keyed with the webhook secret of the user
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return models.WebhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookDeliverer sends the deliveries of the outbox to the webhooks of their owners.
+// Deliveries that are not acknowledged with a 2xx response are retried with exponential
+// backoff until the retry policy is used up. Several deliverers may share the database.
+type WebhookDeliverer struct {
+	service *Webhook
+
+	interval    time.Duration
+	retryPolicy RetryPolicy
+	client      *http.Client
+}
+
+// NewWebhookDeliverer creates a new WebhookDeliverer
+func NewWebhookDeliverer(service *Webhook, interval time.Duration) *WebhookDeliverer {
+	return &WebhookDeliverer{
+		service:     service,
+		interval:    interval,
+		retryPolicy: DefaultWebhookRetryPolicy,
+		client:      newWebhookHTTPClient(),
+	}
+}
+
+// newWebhookHTTPClient returns a client that refuses to connect to the private network of the
+// server. Addresses are checked when connecting rather than when the URL is set, so that DNS
+// rebinding and redirects cannot get around the check.
+func newWebhookHTTPClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: models.WebhookTimeout,
+		Control: webhookDialControl,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would connect to the webhook on our behalf, past the check
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   models.WebhookTimeout,
+		Transport: transport,
+	}
+}
+
+// webhookDialControl refuses the connections of webhook requests to addresses that are not allowed
+func webhookDialControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrWebhookAddressNotAllowed, err)
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrWebhookAddressNotAllowed, err)
+	}
+	if !webhookAddressAllowed(addr) {
+		return fmt.Errorf("%w: %s", ErrWebhookAddressNotAllowed, addr)
+	}
+	return nil
+}
+
+// webhookAddressAllowed reports whether webhook requests may be sent to the address
+func webhookAddressAllowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range webhookBlockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// WithRetryPolicy sets the retry policy of the deliveries
+func (d *WebhookDeliverer) WithRetryPolicy(policy RetryPolicy) *WebhookDeliverer {
+	d.retryPolicy = policy
+	return d
+}
+
+// WithHTTPClient sets the client the webhook requests are sent with. The client replaces the
+// default one, along with its check of the addresses webhooks connect to.
+func (d *WebhookDeliverer) WithHTTPClient(client *http.Client) *WebhookDeliverer {
+	d.client = client
+	return d
+}
+
+// Run sends the due deliveries every interval until the context is cancelled
+func (d *WebhookDeliverer) Run(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	logger.Infof("📨 Webhook deliverer started with interval %s", d.interval)
+	t := time.NewTicker(d.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Webhook deliverer received shutdown signal, stopping...")
+			return
+		case <-t.C:
+		}
+
+		if err := d.DeliverDue(ctx); err != nil {
+			logger.Errorf("❌ Webhook delivery failed: %v", err)
+		}
+	}
+}
+
+// DeliverDue sends the deliveries whose next attempt is due, until none is left
+func (d *WebhookDeliverer) DeliverDue(ctx context.Context) error {
+	for {
+		deliveries, err := d.service.repo.ClaimDue(ctx, time.Now(), webhookBatchSize, webhookClaimLease)
+		if err != nil {
+			return err
+		}
+		for i := range deliveries {
+			if err := d.deliver(ctx, &deliveries[i]); err != nil {
+				return err
+			}
+		}
+		if len(deliveries) < webhookBatchSize {
+			return nil
+		}
+	}
+}
+
+// deliver makes one attempt to send a delivery and records its outcome
+func (d *WebhookDeliverer) deliver(ctx context.Context, delivery *models.WebhookDelivery) error {
+	start := time.Now()
+	statusCode, sendErr := d.send(ctx, delivery)
+	attempt := &models.WebhookAttempt{
+		StatusCode: statusCode,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+
+	delivery.Attempts++
+	switch {
+	case sendErr == nil:
+		now := time.Now()
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+	case delivery.Attempts >= d.retryPolicy.MaxAttempts:
+		attempt.Error = sendErr.Error()
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		logger.Warnf("⚠️ Webhook delivery %d to %s failed after %d attempts: %v", delivery.ID, delivery.URL, delivery.Attempts, sendErr)
+	default:
+		attempt.Error = sendErr.Error()
+		nextAttemptAt := time.Now().Add(d.retryPolicy.Delay(delivery.Attempts))
+		delivery.NextAttemptAt = &nextAttemptAt
+		logger.Debugf("Webhook delivery %d to %s failed, retrying at %s: %v", delivery.ID, delivery.URL, nextAttemptAt.Format(time.RFC3339), sendErr)
+	}
+
+	return d.service.repo.RecordAttempt(ctx, delivery, attempt)
+}
+
+// send sends a delivery signed with the current secret of its owner and returns the status
+// code of the response, or 0 if there was none
+func (d *WebhookDeliverer) send(ctx context.Context, delivery *models.WebhookDelivery) (int, error) {
+	user, err := d.service.userRepo.GetUserByID(ctx, delivery.OwnerID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get webhook owner: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", models.WebhookContentType)
+	req.Header.Set(models.WebhookEventHeader, string(delivery.Event))
+	req.Header.Set(models.WebhookDeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(models.WebhookSignatureHeader, SignWebhook(user.WebhookSecret, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("webhook request failed: %w", err)
+	}
+	// The body is drained so that the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if err := resp.Body.Close(); err != nil {
+		logger.Debugf("failed to close webhook response body: %v", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook returned non-success status code: %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
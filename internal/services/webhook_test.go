@@ -0,0 +1,292 @@
+package services
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+	"github.com/celestiaorg/talis/internal/db/repos"
+)
+
+// webhookRequest is a request received by the test webhook endpoint
+type webhookRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// webhookEndpoint is a test webhook endpoint failing the first failures requests
+type webhookEndpoint struct {
+	mu       sync.Mutex
+	failures int
+	requests []webhookRequest
+}
+
+func (e *webhookEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.requests = append(e.requests, webhookRequest{header: r.Header.Clone(), body: body})
+	if len(e.requests) <= e.failures {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (e *webhookEndpoint) received() []webhookRequest {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]webhookRequest(nil), e.requests...)
+}
+
+// loopbackWebhookClient sends the requests of the tests to their endpoints, which listen on the
+// loopback interface that the default client refuses to connect to
+var loopbackWebhookClient = &http.Client{Timeout: models.WebhookTimeout}
+
+// setupWebhooks creates a user with a webhook pointing to endpoint and enables webhooks on the services
+func setupWebhooks(t *testing.T, ts *TestSetup, endpoint http.Handler) (*models.User, string, *Webhook) {
+	server := httptest.NewServer(endpoint)
+	t.Cleanup(server.Close)
+
+	userRepo := repos.NewUserRepository(ts.DB)
+	user := &models.User{Username: t.Name()}
+	require.NoError(t, userRepo.CreateUser(ts.ctx, user))
+	secret, err := NewUserService(userRepo).SetWebhook(ts.ctx, user.ID, server.URL)
+	require.NoError(t, err)
+	require.NotEmpty(t, secret)
+
+	webhooks := NewWebhookService(repos.NewWebhookRepository(ts.DB), userRepo)
+	ts.TaskService.WithWebhooks(webhooks)
+	ts.InstanceService.WithWebhooks(webhooks)
+	return user, secret, webhooks
+}
+
+// retryRightAway is a retry policy without delay between the attempts
+func retryRightAway(attempts uint) RetryPolicy {
+	return RetryPolicy{MaxAttempts: attempts}
+}
+
+func TestWebhookDeliverer_SignsAndRetries(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	endpoint := &webhookEndpoint{failures: 2}
+	user, secret, webhooks := setupWebhooks(t, ts, endpoint)
+
+	task := &models.Task{
+		OwnerID:   user.ID,
+		ProjectID: 1,
+		Action:    models.TaskActionCreateInstances,
+		Status:    models.TaskStatusPending,
+	}
+	require.NoError(t, ts.TaskService.Create(ts.ctx, task))
+
+	deliverer := NewWebhookDeliverer(webhooks, time.Second).WithRetryPolicy(retryRightAway(5)).WithHTTPClient(loopbackWebhookClient)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, deliverer.DeliverDue(ts.ctx))
+	}
+
+	requests := endpoint.received()
+	require.Len(t, requests, 3, "two failed attempts and the successful one")
+	for _, req := range requests {
+		require.Equal(t, string(models.WebhookEventTaskStatusChanged), req.header.Get(models.WebhookEventHeader))
+		require.Equal(t, SignWebhook(secret, req.body), req.header.Get(models.WebhookSignatureHeader))
+		require.Equal(t, requests[0].header.Get(models.WebhookDeliveryHeader), req.header.Get(models.WebhookDeliveryHeader))
+		require.Equal(t, requests[0].body, req.body, "retries send the same body")
+	}
+
+	var payload models.WebhookPayload
+	require.NoError(t, json.Unmarshal(requests[0].body, &payload))
+	require.NotNil(t, payload.Task)
+	require.Equal(t, task.ID, payload.Task.ID)
+	require.Equal(t, models.TaskStatusPending, payload.Task.Status)
+
+	deliveries, err := webhooks.ListDeliveries(ts.ctx, user.ID, task.ID, 0, nil)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, strconv.FormatUint(uint64(deliveries[0].ID), 10), requests[0].header.Get(models.WebhookDeliveryHeader))
+	require.Equal(t, models.WebhookDeliveryDelivered, deliveries[0].Status)
+	require.Equal(t, uint(3), deliveries[0].Attempts)
+	require.Len(t, deliveries[0].AttemptLog, 3)
+	require.Equal(t, http.StatusServiceUnavailable, deliveries[0].AttemptLog[0].StatusCode)
+	require.NotEmpty(t, deliveries[0].AttemptLog[0].Error)
+	require.Equal(t, http.StatusNoContent, deliveries[0].AttemptLog[2].StatusCode)
+	require.Empty(t, deliveries[0].AttemptLog[2].Error)
+
+	// Delivered deliveries are not sent again
+	require.NoError(t, deliverer.DeliverDue(ts.ctx))
+	require.Len(t, endpoint.received(), 3)
+}
+
+func TestWebhookDeliverer_FailsAfterMaxAttempts(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	endpoint := &webhookEndpoint{failures: 100}
+	user, _, webhooks := setupWebhooks(t, ts, endpoint)
+
+	task := &models.Task{OwnerID: user.ID, ProjectID: 1, Action: models.TaskActionTerminateInstances}
+	require.NoError(t, ts.TaskService.Create(ts.ctx, task))
+
+	deliverer := NewWebhookDeliverer(webhooks, time.Second).WithRetryPolicy(retryRightAway(2)).WithHTTPClient(loopbackWebhookClient)
+	for i := 0; i < 4; i++ {
+		require.NoError(t, deliverer.DeliverDue(ts.ctx))
+	}
+	require.Len(t, endpoint.received(), 2)
+
+	deliveries, err := webhooks.ListDeliveries(ts.ctx, user.ID, task.ID, 0, nil)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, models.WebhookDeliveryFailed, deliveries[0].Status)
+	require.Nil(t, deliveries[0].NextAttemptAt)
+	require.Len(t, deliveries[0].AttemptLog, 2)
+}
+
+func TestWebhookDeliverer_BacksOff(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	endpoint := &webhookEndpoint{failures: 1}
+	user, _, webhooks := setupWebhooks(t, ts, endpoint)
+
+	task := &models.Task{OwnerID: user.ID, ProjectID: 1, Action: models.TaskActionCreateInstances}
+	require.NoError(t, ts.TaskService.Create(ts.ctx, task))
+
+	deliverer := NewWebhookDeliverer(webhooks, time.Second).WithRetryPolicy(RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour}).WithHTTPClient(loopbackWebhookClient)
+	require.NoError(t, deliverer.DeliverDue(ts.ctx))
+	// The retry is not due yet
+	require.NoError(t, deliverer.DeliverDue(ts.ctx))
+	require.Len(t, endpoint.received(), 1)
+
+	deliveries, err := webhooks.ListDeliveries(ts.ctx, user.ID, task.ID, 0, nil)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, models.WebhookDeliveryPending, deliveries[0].Status)
+	require.NotNil(t, deliveries[0].NextAttemptAt)
+	require.WithinDuration(t, time.Now().Add(time.Hour), *deliveries[0].NextAttemptAt, time.Minute)
+}
+
+func TestWebhookDeliverer_RefusesPrivateAddresses(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	endpoint := &webhookEndpoint{}
+	user, _, webhooks := setupWebhooks(t, ts, endpoint)
+
+	task := &models.Task{OwnerID: user.ID, ProjectID: 1, Action: models.TaskActionCreateInstances}
+	require.NoError(t, ts.TaskService.Create(ts.ctx, task))
+
+	// The default client does not connect to the loopback endpoint
+	deliverer := NewWebhookDeliverer(webhooks, time.Second).WithRetryPolicy(retryRightAway(1))
+	require.NoError(t, deliverer.DeliverDue(ts.ctx))
+	require.Empty(t, endpoint.received())
+
+	deliveries, err := webhooks.ListDeliveries(ts.ctx, user.ID, task.ID, 0, nil)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	require.Equal(t, models.WebhookDeliveryFailed, deliveries[0].Status)
+	require.Len(t, deliveries[0].AttemptLog, 1)
+	require.Contains(t, deliveries[0].AttemptLog[0].Error, ErrWebhookAddressNotAllowed.Error())
+}
+
+func TestWebhookAddressAllowed(t *testing.T) {
+	for _, tc := range []struct {
+		addr    string
+		allowed bool
+	}{
+		{addr: "203.0.113.10", allowed: true},
+		{addr: "8.8.8.8", allowed: true},
+		{addr: "2001:4860:4860::8888", allowed: true},
+		{addr: "127.0.0.1"},
+		{addr: "10.1.2.3"},
+		{addr: "172.16.0.1"},
+		{addr: "192.168.1.1"},
+		{addr: "169.254.169.254"},
+		{addr: "100.100.100.200"},
+		{addr: "0.0.0.0"},
+		{addr: "0.1.2.3"},
+		{addr: "255.255.255.255"},
+		{addr: "224.0.0.1"},
+		{addr: "::"},
+		{addr: "::1"},
+		{addr: "fe80::1"},
+		{addr: "fd00:ec2::254"},
+		{addr: "::ffff:127.0.0.1"},
+		{addr: "::ffff:169.254.169.254"},
+		{addr: "64:ff9b::a9fe:a9fe"},
+	} {
+		t.Run(tc.addr, func(t *testing.T) {
+			require.Equal(t, tc.allowed, webhookAddressAllowed(netip.MustParseAddr(tc.addr)))
+			err := webhookDialControl("tcp", netip.AddrPortFrom(netip.MustParseAddr(tc.addr), 443).String(), nil)
+			if tc.allowed {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrWebhookAddressNotAllowed)
+			}
+		})
+	}
+}
+
+func TestWebhook_QueuesStatusChanges(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	user, _, webhooks := setupWebhooks(t, ts, &webhookEndpoint{})
+
+	task := &models.Task{OwnerID: user.ID, ProjectID: 1, Action: models.TaskActionCreateInstances}
+	require.NoError(t, ts.TaskService.Create(ts.ctx, task))
+	require.NoError(t, ts.TaskService.AcquireTaskLock(ts.ctx, task.ID, "worker"))
+	require.NoError(t, ts.TaskService.UpdateStatus(ts.ctx, user.ID, task.ID, models.TaskStatusCompleted))
+
+	deliveries, err := webhooks.ListDeliveries(ts.ctx, user.ID, task.ID, 0, nil)
+	require.NoError(t, err)
+	statuses := make([]models.TaskStatus, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		var payload models.WebhookPayload
+		require.NoError(t, json.Unmarshal(delivery.Payload, &payload))
+		statuses = append(statuses, payload.Task.Status)
+	}
+	require.ElementsMatch(t, []models.TaskStatus{
+		models.TaskStatusPending,
+		models.TaskStatusRunning,
+		models.TaskStatusCompleted,
+	}, statuses)
+
+	instance := &models.Instance{
+		OwnerID:    user.ID,
+		ProjectID:  1,
+		ProviderID: models.ProviderDO,
+		Status:     models.InstanceStatusPending,
+	}
+	_, err = ts.InstanceRepo.Create(ts.ctx, instance)
+	require.NoError(t, err)
+
+	// Updates that keep the status are not sent
+	require.NoError(t, ts.InstanceService.Update(ts.ctx, user.ID, instance.ID, &models.Instance{PublicIP: "192.0.2.1"}))
+	require.NoError(t, ts.InstanceService.Update(ts.ctx, user.ID, instance.ID, &models.Instance{Status: models.InstanceStatusPending}))
+	require.NoError(t, ts.InstanceService.Update(ts.ctx, user.ID, instance.ID, &models.Instance{Status: models.InstanceStatusReady}))
+	require.NoError(t, ts.InstanceService.MarkAsTerminated(ts.ctx, user.ID, instance.ID))
+
+	deliveries, err = webhooks.ListDeliveries(ts.ctx, user.ID, 0, instance.ID, nil)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	for _, delivery := range deliveries {
+		require.Equal(t, models.WebhookEventInstanceStatusChanged, delivery.Event)
+	}
+
+	// Owners without a webhook get no deliveries
+	other := &models.Task{OwnerID: user.ID + 1, ProjectID: 1, Action: models.TaskActionCreateInstances}
+	require.NoError(t, ts.TaskService.Create(ts.ctx, other))
+	deliveries, err = webhooks.ListDeliveries(ts.ctx, models.AdminID, other.ID, 0, nil)
+	require.NoError(t, err)
+	require.Empty(t, deliveries)
+}
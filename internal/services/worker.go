@@ -5,19 +5,19 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/celestiaorg/talis/internal/compute"
 	"github.com/celestiaorg/talis/internal/db/models"
 	"github.com/celestiaorg/talis/internal/logger"
 	"github.com/celestiaorg/talis/internal/types"
-)
-
-// Global variable to ensure RecoverStaleTasks only runs once across all worker pool instances
-var (
-	staleTaskRecoveryOnce sync.Once
+	"gorm.io/gorm"
 )
 
 // DefaultBackoff is the default backoff time for the worker
@@ -32,6 +32,19 @@ const (
 
 	// QueueSize is the size of the task queue
 	QueueSize = 100
+
+	// DefaultTerminationPollInterval is how often a worker checks whether the task it is processing was terminated
+	DefaultTerminationPollInterval = 5 * time.Second
+
+	// DefaultTaskPollInterval is how often dispatchers poll for tasks when they are woken up by
+	// task notifications, as a safety net for notifications that were lost
+	DefaultTaskPollInterval = 30 * time.Second
+
+	// DefaultLeaseRenewInterval is how often a worker extends the lock of the task it is processing
+	DefaultLeaseRenewInterval = models.TaskLockTimeout / 5
+
+	// taskCleanupTimeout bounds the rollback of a terminated task
+	taskCleanupTimeout = 5 * time.Minute
 )
 
 // WorkerPool is a struct that contains the worker pool's dependencies
@@ -43,19 +56,45 @@ type WorkerPool struct {
 	userService     *User
 	sshKeyService   *SSHKeyService
 
-	// Providers & Provisioners
-	providers    map[models.ProviderID]compute.Provider
-	provisioners map[models.ProviderID]compute.Provisioner
-	computeMU    sync.RWMutex
+	// Fleet registration, tasks are locked to the worker of the pool
+	fleet             *Fleet
+	worker            *models.Worker
+	heartbeatInterval time.Duration
+	heartbeatTimeout  time.Duration
+
+	// newProvisioner creates the provisioner of a task, compute providers are shared through the
+	// instance service
+	newProvisioner func(provisioner models.ProvisionerID, jobID string) (compute.Provisioner, error)
 
 	// Config
-	backoff           time.Duration
-	workerCount       int
-	highPriorityRatio float64
+	backoff                 time.Duration
+	workerCount             int
+	highPriorityRatio       float64
+	terminationPollInterval time.Duration
+	leaseRenewInterval      time.Duration
+	retryPolicies           map[models.TaskAction]RetryPolicy
+	taskTimeouts            map[models.TaskAction]TaskTimeouts
+
+	// Task notifications wake up the dispatchers, polling is then only a safety net
+	taskNotifications <-chan string
+	taskPollInterval  time.Duration
+	wakeups           map[models.TaskPriority]chan struct{}
+
+	// Concurrency limits are enforced on the tasks dispatched by the pool and the tasks running on
+	// other workers. Dispatched tasks are tracked until they have been processed.
+	limits     ConcurrencyLimits
+	dispatched map[uint]taskGroup
+	dispatchMU sync.Mutex
+	throttled  atomic.Bool
 
 	// Task queues
 	highPriorityQueue chan *models.Task
 	lowPriorityQueue  chan *models.Task
+
+	// beforeCheckpoint and afterCheckpoint are called around each checkpoint of a create task,
+	// tests use them to stop tasks between steps
+	beforeCheckpoint func(step models.TaskStep) error
+	afterCheckpoint  func(step models.TaskStep) error
 }
 
 // NewWorkerPool creates a new WorkerPool
@@ -66,13 +105,25 @@ func NewWorkerPool(instanceService *Instance, projectService *Project, taskServi
 		taskService:       taskService,
 		userService:       userService,
 		sshKeyService:     sshKeyService,
-		providers:         make(map[models.ProviderID]compute.Provider),
-		provisioners:      make(map[models.ProviderID]compute.Provisioner),
+		worker:            newWorker(),
+		heartbeatInterval: models.WorkerHeartbeatInterval,
+		heartbeatTimeout:  models.WorkerHeartbeatTimeout,
+		newProvisioner:    compute.NewProvisioner,
 		backoff:           backoff,
 		workerCount:       DefaultWorkerCount,
 		highPriorityRatio: DefaultHighPriorityRatio,
 		highPriorityQueue: make(chan *models.Task, QueueSize),
 		lowPriorityQueue:  make(chan *models.Task, QueueSize),
+
+		terminationPollInterval: DefaultTerminationPollInterval,
+		leaseRenewInterval:      DefaultLeaseRenewInterval,
+		retryPolicies:           maps.Clone(DefaultRetryPolicies),
+		taskTimeouts:            maps.Clone(DefaultTaskTimeouts),
+		dispatched:              make(map[uint]taskGroup),
+		wakeups: map[models.TaskPriority]chan struct{}{
+			models.TaskPriorityHigh: make(chan struct{}, 1),
+			models.TaskPriorityLow:  make(chan struct{}, 1),
+		},
 	}
 }
 
@@ -92,22 +143,113 @@ func (w *WorkerPool) WithHighPriorityRatio(ratio float64) *WorkerPool {
 	return w
 }
 
+// WithTerminationPollInterval sets how often workers check whether their task was terminated
+func (w *WorkerPool) WithTerminationPollInterval(interval time.Duration) *WorkerPool {
+	if interval > 0 {
+		w.terminationPollInterval = interval
+	}
+	return w
+}
+
+// WithLeaseRenewInterval sets how often workers extend the lock of the task they are processing.
+// The interval has to be well below models.TaskLockTimeout, otherwise other workers take over
+// tasks that are still being processed.
+func (w *WorkerPool) WithLeaseRenewInterval(interval time.Duration) *WorkerPool {
+	if interval > 0 && interval < models.TaskLockTimeout {
+		w.leaseRenewInterval = interval
+	}
+	return w
+}
+
+// WithConcurrencyLimits caps the number of tasks processed at the same time per provider, owner
+// and project
+func (w *WorkerPool) WithConcurrencyLimits(limits ConcurrencyLimits) *WorkerPool {
+	w.limits = limits
+	return w
+}
+
+// WithFleet registers the worker pool in the worker fleet. The pool then records heartbeats and
+// reclaims the tasks of workers whose heartbeat expired, so that many worker processes can share
+// one database.
+func (w *WorkerPool) WithFleet(fleet *Fleet) *WorkerPool {
+	w.fleet = fleet
+	return w
+}
+
+// WorkerID returns the ID of the worker the tasks processed by the pool are locked to
+func (w *WorkerPool) WorkerID() string {
+	return w.worker.ID
+}
+
+// WithTaskNotifications makes the dispatchers fetch tasks as soon as a task notification is
+// received. Each notification carries the priority of the task that became pending, or is empty
+// if any priority may have pending tasks. Dispatchers then only poll every pollInterval.
+func (w *WorkerPool) WithTaskNotifications(notifications <-chan string, pollInterval time.Duration) *WorkerPool {
+	w.taskNotifications = notifications
+	w.taskPollInterval = pollInterval
+	if w.taskPollInterval <= 0 {
+		w.taskPollInterval = DefaultTaskPollInterval
+	}
+	return w
+}
+
+// WithRetryPolicy sets the retry policy of tasks with the given action
+func (w *WorkerPool) WithRetryPolicy(action models.TaskAction, policy RetryPolicy) *WorkerPool {
+	if policy.MaxAttempts > 0 {
+		w.retryPolicies[action] = policy
+	}
+	return w
+}
+
+// retryPolicy returns the retry policy of tasks with the given action
+func (w *WorkerPool) retryPolicy(action models.TaskAction) RetryPolicy {
+	if policy, ok := w.retryPolicies[action]; ok {
+		return policy
+	}
+	return DefaultRetryPolicy
+}
+
+// WithTaskTimeouts sets the timeouts of tasks with the given action
+func (w *WorkerPool) WithTaskTimeouts(action models.TaskAction, timeouts TaskTimeouts) *WorkerPool {
+	w.taskTimeouts[action] = timeouts
+	return w
+}
+
+// stepContext returns the context of a step of a task, bounded by the timeout of the step
+func (w *WorkerPool) stepContext(ctx context.Context, task *models.Task, step models.TaskStep) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, w.taskTimeouts[task.Action].Steps[step], fmt.Sprintf("%s step", step))
+}
+
 // LaunchWorkerPool launches a task dispatcher and worker pool to process tasks
 func (w *WorkerPool) LaunchWorkerPool(ctx context.Context, wg *sync.WaitGroup) {
 	defer wg.Done()
 	const taskLimit = 10
 
-	// Recover stale tasks from previous runs
-	w.recoverStaleTasks(ctx)
-
 	// Create a wait group for the workers
 	var workersWg sync.WaitGroup
 
+	// Register the worker before recovering stale tasks so that its own tasks are not reclaimed,
+	// then keep reclaiming the tasks of workers that stop sending heartbeats
+	if w.fleet != nil {
+		if err := w.fleet.Heartbeat(ctx, w.worker); err != nil {
+			logger.Errorf("Failed to register worker %s: %v", w.worker.ID, err)
+		}
+		workersWg.Add(1)
+		go w.runHeartbeats(ctx, &workersWg)
+	}
+
+	// Recover stale tasks from previous runs
+	w.recoverStaleTasks(ctx)
+
 	// Launch task dispatchers for each priority level
 	dispatcherCtx, cancelDispatcher := context.WithCancel(ctx)
 	workersWg.Add(2) // One for each priority dispatcher
 	go w.taskDispatcher(dispatcherCtx, &workersWg, models.TaskPriorityHigh, taskLimit)
 	go w.taskDispatcher(dispatcherCtx, &workersWg, models.TaskPriorityLow, taskLimit)
+	if w.taskNotifications != nil {
+		workersWg.Add(1)
+		go w.forwardTaskNotifications(dispatcherCtx, &workersWg)
+	}
 
 	// Calculate worker distribution
 	highPriorityWorkers := int(float64(w.workerCount) * w.highPriorityRatio)
@@ -127,8 +269,8 @@ func (w *WorkerPool) LaunchWorkerPool(ctx context.Context, wg *sync.WaitGroup) {
 		go w.lowPriorityTaskProcessor(ctx, &workersWg, workerID)
 	}
 
-	logger.Infof("Worker pool started with %d workers (%d high priority, %d low priority)",
-		w.workerCount, highPriorityWorkers, lowPriorityWorkers)
+	logger.Infof("Worker pool %s started with %d workers (%d high priority, %d low priority)",
+		w.worker.ID, w.workerCount, highPriorityWorkers, lowPriorityWorkers)
 
 	// Wait for context cancellation
 	<-ctx.Done()
@@ -140,24 +282,58 @@ func (w *WorkerPool) LaunchWorkerPool(ctx context.Context, wg *sync.WaitGroup) {
 	close(w.highPriorityQueue)
 	close(w.lowPriorityQueue)
 
+	if w.fleet != nil {
+		deregisterCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		if err := w.fleet.Deregister(deregisterCtx, w.worker.ID); err != nil {
+			logger.Errorf("Failed to deregister worker %s: %v", w.worker.ID, err)
+		}
+		cancel()
+	}
+
 	logger.Info("Worker pool shutdown complete")
 }
 
-// recoverStaleTasks finds and resets tasks that were in progress when the system crashed.
-// This method uses sync.Once to ensure it only runs once across all worker pool instances,
-// preventing race conditions when multiple worker pools are started concurrently.
+// recoverStaleTasks resets the tasks that were in progress on workers that stopped. Without a
+// fleet the pool is the only worker, so all tasks left running are stale.
 func (w *WorkerPool) recoverStaleTasks(ctx context.Context) {
-	staleTaskRecoveryOnce.Do(func() {
-		count, err := w.taskService.RecoverStaleTasks(ctx)
-		if err != nil {
-			logger.Errorf("Failed to recover stale tasks: %v", err)
+	heartbeatDeadline := time.Now()
+	if w.fleet != nil {
+		heartbeatDeadline = heartbeatDeadline.Add(-w.heartbeatTimeout)
+	}
+
+	if _, err := w.taskService.RecoverStaleTasks(ctx, heartbeatDeadline); err != nil {
+		logger.Errorf("Failed to recover stale tasks: %v", err)
+	}
+}
+
+// runHeartbeats records a heartbeat of the worker every heartbeat interval, reclaims the tasks
+// of workers whose heartbeat expired and removes these workers from the fleet
+func (w *WorkerPool) runHeartbeats(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	t := time.NewTicker(w.heartbeatInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
 			return
+		case <-t.C:
+		}
+
+		if err := w.fleet.Heartbeat(ctx, w.worker); err != nil {
+			logger.Errorf("Failed to record heartbeat of worker %s: %v", w.worker.ID, err)
+			continue
 		}
+		w.recoverStaleTasks(ctx)
 
-		if count > 0 {
-			logger.Infof("Recovered %d stale tasks that were in progress during previous run", count)
+		deleted, err := w.fleet.DeleteExpired(ctx, time.Now().Add(-w.heartbeatTimeout))
+		if err != nil {
+			logger.Errorf("Failed to remove expired workers: %v", err)
+		} else if deleted > 0 {
+			logger.Infof("💔 Removed %d workers without a heartbeat for %s", deleted, w.heartbeatTimeout)
 		}
-	})
+	}
 }
 
 // taskDispatcher fetches tasks from database and puts them in the appropriate queue
@@ -168,6 +344,13 @@ func (w *WorkerPool) taskDispatcher(ctx context.Context, wg *sync.WaitGroup, pri
 	t := time.NewTicker(time.Second)
 	defer t.Stop()
 
+	// Poll slowly if notifications wake up the dispatcher when there are new tasks
+	pollInterval := w.backoff
+	if w.taskNotifications != nil {
+		pollInterval = w.taskPollInterval
+	}
+	wakeup := w.wakeups[priority]
+
 	// Determine which queue to use based on priority
 	var queue chan *models.Task
 	priorityName := priority.String()
@@ -186,10 +369,12 @@ func (w *WorkerPool) taskDispatcher(ctx context.Context, wg *sync.WaitGroup, pri
 			logger.Infof("%s priority task dispatcher received shutdown signal, stopping...", priorityName)
 			return
 		case <-t.C:
+		case <-wakeup:
+			logger.Debugf("%s priority task dispatcher woken up by a task notification", priorityName)
 		}
 
 		// Fetch schedulable tasks for this priority level
-		tasks, err := w.taskService.GetSchedulableTasks(ctx, priority, taskLimit)
+		tasks, err := w.dispatchableTasks(ctx, priority, taskLimit)
 		if err != nil {
 			logger.Errorf("%s priority task dispatcher error fetching tasks: %v", priorityName, err)
 			// Wait before retrying to avoid spamming logs on persistent DB errors
@@ -200,10 +385,17 @@ func (w *WorkerPool) taskDispatcher(ctx context.Context, wg *sync.WaitGroup, pri
 		if len(tasks) == 0 {
 			logger.Debugf("%s priority task dispatcher: No tasks to process", priorityName)
 			// Wait before retrying to give time for tasks to be created
-			t.Reset(w.backoff)
+			t.Reset(w.nextPollDelay(ctx, priority, pollInterval))
 			continue
 		}
 
+		// A full batch means more tasks are likely waiting, check again soon
+		if len(tasks) == taskLimit {
+			t.Reset(w.backoff)
+		} else {
+			t.Reset(w.nextPollDelay(ctx, priority, pollInterval))
+		}
+
 		// Distribute tasks to workers through the queue
 		for i := range tasks {
 			select {
@@ -217,6 +409,122 @@ func (w *WorkerPool) taskDispatcher(ctx context.Context, wg *sync.WaitGroup, pri
 	}
 }
 
+// nextPollDelay returns how long the dispatcher of the priority waits before polling for tasks:
+// the poll interval, or until the next retry is due if that comes first. Notifications are sent
+// when a retry is scheduled, not when it comes due.
+func (w *WorkerPool) nextPollDelay(ctx context.Context, priority models.TaskPriority, pollInterval time.Duration) time.Duration {
+	nextRunAt, err := w.taskService.GetNextRunAt(ctx, priority)
+	if err != nil {
+		logger.Warnf("%s priority task dispatcher failed to get the next retry: %v", priority, err)
+		return pollInterval
+	}
+	if nextRunAt == nil {
+		return pollInterval
+	}
+	return min(time.Until(*nextRunAt), pollInterval)
+}
+
+// dispatchableTasks fetches the next tasks of the priority that fit within the concurrency limits
+// and records them as dispatched. Tasks that were dispatched but not processed yet are not fetched again.
+func (w *WorkerPool) dispatchableTasks(ctx context.Context, priority models.TaskPriority, limit int) ([]models.Task, error) {
+	var running []models.RunningTaskCount
+	if w.limits.enabled() {
+		var err error
+		running, err = w.taskService.CountRunningTasks(ctx, w.worker.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Leave out the owners, projects and providers that are already at their limit
+	w.dispatchMU.Lock()
+	filter := w.limits.exclusions(w.countTasks(running))
+	filter.ExcludeTaskIDs = slices.Sorted(maps.Keys(w.dispatched))
+	w.dispatchMU.Unlock()
+	if len(filter.ExcludeOwnerIDs) > 0 || len(filter.ExcludeProjectIDs) > 0 || len(filter.ExcludeProviderIDs) > 0 {
+		w.throttled.Store(true)
+	}
+
+	tasks, err := w.taskService.GetSchedulableTasks(ctx, priority, limit, &filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Admit the tasks in order while they fit, the other dispatcher may have used up capacity meanwhile
+	w.dispatchMU.Lock()
+	defer w.dispatchMU.Unlock()
+	counts := w.countTasks(running)
+	admitted := tasks[:0]
+	for _, task := range tasks {
+		group := groupOf(&task)
+		if !w.limits.admits(counts, group) {
+			w.throttled.Store(true)
+			continue
+		}
+		counts.add(group, 1)
+		w.dispatched[task.ID] = group
+		admitted = append(admitted, task)
+	}
+	return admitted, nil
+}
+
+// countTasks counts the running tasks of other workers and the tasks dispatched by the pool.
+// The caller must hold dispatchMU.
+func (w *WorkerPool) countTasks(running []models.RunningTaskCount) taskCounts {
+	return newTaskCounts(running, slices.Collect(maps.Values(w.dispatched))...)
+}
+
+// finishDispatch frees the concurrency slot of a dispatched task once it has been processed
+func (w *WorkerPool) finishDispatch(task *models.Task) {
+	w.dispatchMU.Lock()
+	delete(w.dispatched, task.ID)
+	w.dispatchMU.Unlock()
+
+	// Tasks held back by the concurrency limits may fit now
+	if w.throttled.Swap(false) {
+		for _, wakeup := range w.wakeups {
+			w.wakeUp(wakeup)
+		}
+	}
+}
+
+// forwardTaskNotifications wakes up the dispatcher of the priority of each task notification
+func (w *WorkerPool) forwardTaskNotifications(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for {
+		var payload string
+		select {
+		case <-ctx.Done():
+			return
+		case n, ok := <-w.taskNotifications:
+			if !ok {
+				// The listener stopped, which only happens on shutdown
+				return
+			}
+			payload = n
+		}
+
+		priority, err := strconv.Atoi(payload)
+		if wakeup, ok := w.wakeups[models.TaskPriority(priority)]; err == nil && ok {
+			w.wakeUp(wakeup)
+			continue
+		}
+		// Unknown or missing priority, wake up all dispatchers
+		for _, wakeup := range w.wakeups {
+			w.wakeUp(wakeup)
+		}
+	}
+}
+
+// wakeUp signals a dispatcher without blocking, a pending signal already wakes it up
+func (w *WorkerPool) wakeUp(wakeup chan struct{}) {
+	select {
+	case wakeup <- struct{}{}:
+	default:
+	}
+}
+
 // highPriorityTaskProcessor processes tasks from the high priority queue
 func (w *WorkerPool) highPriorityTaskProcessor(ctx context.Context, wg *sync.WaitGroup, workerID int) {
 	defer wg.Done()
@@ -237,6 +545,7 @@ func (w *WorkerPool) highPriorityTaskProcessor(ctx context.Context, wg *sync.Wai
 
 			// Process the task
 			w.processTask(ctx, workerID, task)
+			w.finishDispatch(task)
 		}
 	}
 }
@@ -261,6 +570,7 @@ func (w *WorkerPool) lowPriorityTaskProcessor(ctx context.Context, wg *sync.Wait
 
 			// Process the task
 			w.processTask(ctx, workerID, task)
+			w.finishDispatch(task)
 		}
 	}
 }
@@ -270,9 +580,22 @@ func (w *WorkerPool) processTask(ctx context.Context, workerID int, task *models
 	priorityName := task.Priority.String()
 	logger.Debugf("%s priority worker %d attempting to process task %d", priorityName, workerID, task.ID)
 
-	// Atomically increment attempts count before trying to acquire the lock
-	// This ensures we track all processing attempts, even if lock acquisition fails
-	err := w.taskService.IncrementAttempts(ctx, task.ID)
+	// Try to acquire a lock on the task
+	err := w.taskService.AcquireTaskLock(ctx, task.ID, w.worker.ID)
+	if err != nil {
+		if errors.Is(err, ErrTaskLockNotAcquired) {
+			logger.Debugf("%s priority worker %d could not acquire lock for task %d, skipping",
+				priorityName, workerID, task.ID)
+		} else {
+			logger.Errorf("%s priority worker %d failed to acquire lock for task %d: %v",
+				priorityName, workerID, task.ID, err)
+		}
+		return
+	}
+
+	// Count the attempt once the task is locked, so that workers competing for the same
+	// task do not use up its retries
+	err = w.taskService.IncrementAttempts(ctx, task.ID)
 	if err != nil {
 		logger.Errorf("%s priority worker %d failed to increment attempts for task %d: %v",
 			priorityName, workerID, task.ID, err)
@@ -282,112 +605,158 @@ func (w *WorkerPool) processTask(ctx context.Context, workerID int, task *models
 		task.Attempts++
 	}
 
-	// Try to acquire a lock on the task
-	err = w.taskService.AcquireTaskLock(ctx, task.ID)
-	if err != nil {
-		if errors.Is(err, ErrTaskLockNotAcquired) {
-			logger.Debugf("%s priority worker %d could not acquire lock for task %d, skipping",
-				priorityName, workerID, task.ID)
-		} else {
-			logger.Errorf("%s priority worker %d failed to acquire lock for task %d: %v",
-				priorityName, workerID, task.ID, err)
+	// Tasks whose attempts were interrupted, e.g. by a crash, are not retried forever
+	policy := w.retryPolicy(task.Action)
+	if task.Attempts > policy.MaxAttempts {
+		logMsg := fmt.Sprintf("💀 Task %d exceeded its %d attempts", task.ID, policy.MaxAttempts)
+		logger.Warn(logMsg)
+		if err := w.taskService.MarkDead(ctx, task, "retries exhausted", logMsg); err != nil {
+			logger.Errorf("%s priority worker %d: Failed to update task: %v", priorityName, workerID, err)
 		}
+		w.releaseTaskLock(ctx, workerID, task)
 		return
 	}
 
 	logger.Debugf("%s priority worker %d processing task %d", priorityName, workerID, task.ID)
 
+	// Process the task with its own context so that terminating the task cancels the in-flight work
+	taskCtx, cancel := context.WithCancelCause(ctx)
+	w.taskService.trackRunning(task.ID, cancel)
+	go w.watchTermination(taskCtx, cancel, task)
+	go w.renewLease(taskCtx, cancel, task)
+
+	// The attempt is bounded by the timeout of the task action
+	actionCtx, cancelTimeout := withTimeout(taskCtx, w.taskTimeouts[task.Action].Task, fmt.Sprintf("%s task", task.Action))
+
 	// Process the task based on its action
 	var processErr error
+	var actionName string
+	result := &models.TaskResult{}
 	switch task.Action {
 	case models.TaskActionCreateInstances:
-		processErr = w.processCreateInstanceTask(ctx, task)
-		if processErr != nil {
-			logMsg := fmt.Sprintf("❌ %s priority worker %d failed to process create instance task %d: %v",
-				priorityName, workerID, task.ID, processErr)
-			logger.Error(logMsg)
-			task.Logs += fmt.Sprintf("\n%s", logMsg)
-			err = w.taskService.UpdateFailed(ctx, task, processErr.Error(), logMsg)
-			if err != nil {
-				logger.Errorf("%s priority worker %d: Failed to update task: %v", priorityName, workerID, err)
-			}
-		} else {
-			err = w.taskService.UpdateStatus(ctx, task.OwnerID, task.ID, models.TaskStatusCompleted)
-			if err != nil {
-				logger.Errorf("%s priority worker %d: Failed to update task status: %v", priorityName, workerID, err)
-			}
-		}
+		actionName = "create instance"
+		processErr = w.processCreateInstanceTask(actionCtx, task, result)
 	case models.TaskActionTerminateInstances:
-		processErr = w.processTerminateInstanceTask(ctx, task)
-		if processErr != nil {
-			logMsg := fmt.Sprintf("❌ %s priority worker %d failed to process terminate instance task %d: %v",
-				priorityName, workerID, task.ID, processErr)
-			logger.Error(logMsg)
-			task.Logs += fmt.Sprintf("\n%s", logMsg)
-			err = w.taskService.UpdateFailed(ctx, task, processErr.Error(), logMsg)
-			if err != nil {
-				logger.Errorf("%s priority worker %d: Failed to update task: %v", priorityName, workerID, err)
-			}
-		} else {
-			err = w.taskService.UpdateStatus(ctx, task.OwnerID, task.ID, models.TaskStatusCompleted)
-			if err != nil {
-				logger.Errorf("%s priority worker %d: Failed to update task status: %v", priorityName, workerID, err)
-			}
-		}
+		actionName = "terminate instance"
+		processErr = w.processTerminateInstanceTask(actionCtx, task, result)
 	default:
 		logger.Errorf("%s priority worker %d: Unknown task action %s for task %d",
 			priorityName, workerID, task.Action, task.ID)
 	}
+	processErr = timeoutError(actionCtx, processErr)
+	cancelTimeout()
+
+	cause := context.Cause(taskCtx)
+	terminated := errors.Is(cause, ErrTaskTerminated)
+	leaseLost := errors.Is(cause, ErrTaskLeaseLost)
+	w.taskService.untrackRunning(task.ID)
+	cancel(nil)
+
+	// Record the outcome of the attempt before its status, so that clients seeing the final
+	// status find the result
+	if actionName != "" && !leaseLost {
+		if err := w.taskService.SetResult(context.WithoutCancel(ctx), task, result); err != nil {
+			logger.Errorf("%s priority worker %d: Failed to record task result: %v", priorityName, workerID, err)
+		}
+	}
+
+	switch {
+	case actionName == "":
+		// Unknown action, nothing was processed
+	case leaseLost:
+		// Another worker may be processing the task by now, leave its status to that worker
+		logger.Warnf("⚠️ %s priority worker %d aborted %s task %d, its lock was lost: %v",
+			priorityName, workerID, actionName, task.ID, processErr)
+	case processErr != nil && terminated:
+		logger.Infof("🛑 %s priority worker %d stopped %s task %d, the task was terminated",
+			priorityName, workerID, actionName, task.ID)
+		w.finishTerminatedTask(ctx, task, processErr)
+	case processErr != nil:
+		logMsg := fmt.Sprintf("❌ %s priority worker %d failed to process %s task %d (attempt %d/%d): %v",
+			priorityName, workerID, actionName, task.ID, task.Attempts, policy.MaxAttempts, processErr)
+		logger.Error(logMsg)
+		if errors.Is(processErr, ErrTaskTimeout) {
+			w.failTimedOutInstance(ctx, task, processErr)
+		}
+		err = w.handleTaskFailure(ctx, task, policy, processErr, logMsg)
+		if err != nil {
+			logger.Errorf("%s priority worker %d: Failed to update task: %v", priorityName, workerID, err)
+		}
+	default:
+		// The work completed before the termination took effect, record what actually happened
+		if terminated {
+			w.taskService.recordEvent(ctx, task, models.TaskEventWarn, "", "Task completed before it could be terminated", nil)
+		}
+		err = w.taskService.UpdateStatus(ctx, task.OwnerID, task.ID, models.TaskStatusCompleted)
+		if err != nil {
+			logger.Errorf("%s priority worker %d: Failed to update task status: %v", priorityName, workerID, err)
+		}
+	}
 
 	// Release the lock regardless of success or failure
-	if err := w.taskService.ReleaseTaskLock(ctx, task.ID); err != nil {
+	w.releaseTaskLock(ctx, workerID, task)
+}
+
+// releaseTaskLock releases the lock a worker holds on a task
+func (w *WorkerPool) releaseTaskLock(ctx context.Context, workerID int, task *models.Task) {
+	if err := w.taskService.ReleaseTaskLock(ctx, task.ID, w.worker.ID); err != nil {
 		logger.Errorf("%s priority worker %d failed to release lock for task %d: %v",
-			priorityName, workerID, task.ID, err)
+			task.Priority.String(), workerID, task.ID, err)
 	}
 }
 
-// processCreateInstanceTask processes a create instance task. It will handle the instance creation, provisioning, and status updates for the instance.
-func (w *WorkerPool) processCreateInstanceTask(ctx context.Context, task *models.Task) error {
-	err := w.taskService.UpdateStatus(ctx, task.OwnerID, task.ID, models.TaskStatusRunning)
-	if err != nil {
-		return fmt.Errorf("worker: failed to update task status: %w", err)
+// handleTaskFailure records a failed attempt of a task. Retryable failures are scheduled
+// again after the backoff of the retry policy, permanent failures fail the task and tasks
+// that used up their attempts are marked dead.
+func (w *WorkerPool) handleTaskFailure(ctx context.Context, task *models.Task, policy RetryPolicy, processErr error, logMsg string) error {
+	switch {
+	case !isRetryable(processErr):
+		return w.taskService.UpdateFailed(ctx, task, processErr.Error(), logMsg)
+	case task.Attempts >= policy.MaxAttempts:
+		logMsg += fmt.Sprintf("\n💀 Task %d used up its %d attempts", task.ID, policy.MaxAttempts)
+		return w.taskService.MarkDead(ctx, task, processErr.Error(), logMsg)
+	default:
+		delay := policy.Delay(task.Attempts)
+		logMsg += fmt.Sprintf("\n🔁 Retrying task %d in %s", task.ID, delay.Round(time.Second))
+		return w.taskService.ScheduleRetry(ctx, task, time.Now().Add(delay), processErr.Error(), logMsg)
 	}
+}
+
+// processCreateInstanceTask processes a create instance task. It will handle the instance creation, provisioning, and status updates for the instance.
+// The instance, the steps that ran and the provisioning outcome are recorded in result.
+func (w *WorkerPool) processCreateInstanceTask(ctx context.Context, task *models.Task, result *models.TaskResult) error {
 	logger.Debugf("Creating instance for task %d", task.ID)
 
 	// Unmarshal the task payload
 	var instanceReq types.InstanceRequest
-	err = json.Unmarshal(task.Payload, &instanceReq)
+	err := json.Unmarshal(task.Payload, &instanceReq)
 	if err != nil {
-		return fmt.Errorf("worker: failed to unmarshal task payload for task %d: %w", task.ID, err)
+		return permanent(fmt.Errorf("worker: failed to unmarshal task payload for task %d: %w", task.ID, err))
 	}
 
 	// Check the instance status
 	instance, err := w.instanceService.Get(ctx, instanceReq.OwnerID, instanceReq.InstanceID)
 	if err != nil {
-		return fmt.Errorf("worker: failed to get instance: %w", err)
+		return instanceLookupError(err)
 	}
 	if instance == nil {
-		return fmt.Errorf("worker: instance %d not found", instanceReq.InstanceID)
+		return permanent(fmt.Errorf("worker: instance %d not found", instanceReq.InstanceID))
 	}
+	// The result reflects the instance as far as the task got
+	defer result.SetInstance(instance)
 
 	switch instance.Status {
 	case models.InstanceStatusPending:
-		logger.Debugf("Instance ID %d is in status %s, creating", instance.ID, instance.Status)
-		w.instanceService.addTaskLogs(ctx, instanceReq.OwnerID, task, fmt.Sprintf("Creating instance ID %d", instance.ID))
-		// Create the instance
-		// NOTE: need to understand if server creation via the hypervisor is atomic or if we need to understand how to pick up where we left off
-
-		// Get the compute provider or create a new one
-		provider, err := w.getProvider(instanceReq.Provider)
-		if err != nil {
-			return fmt.Errorf("worker: failed to get compute provider for provider %s: %w", instanceReq.Provider, err)
-		}
-
-		// Create the instance
-		// NOTE: since the instance request type is now being updated during the create instance process we might need to update the task payload to include the updates. This is more of a concern if we want to support resuming from a failed task.
-		err = provider.CreateInstance(ctx, &instanceReq)
-		if err != nil {
-			return fmt.Errorf("worker: failed to create instance: %w", err)
+		if task.Checkpoint == "" {
+			if err := w.createInstance(ctx, task, instance, &instanceReq, result); err != nil {
+				return err
+			}
+		} else {
+			// The instance was created by a previous attempt that stopped before recording it,
+			// the payload holds what the provider returned
+			logger.Debugf("Instance ID %d was created by a previous attempt of task %d, resuming", instance.ID, task.ID)
+			w.taskService.recordEvent(ctx, task, models.TaskEventInfo, models.TaskStepCreate, fmt.Sprintf("Instance ID %d was created by a previous attempt, resuming", instance.ID),
+				models.TaskEventFields{"instance_id": instance.ID, "provider_instance_id": instanceReq.ProviderInstanceID})
 		}
 
 		// Update instance DB with IP and volume info
@@ -416,7 +785,8 @@ func (w *WorkerPool) processCreateInstanceTask(ctx context.Context, task *models
 		fallthrough
 	case models.InstanceStatusCreated:
 		logger.Debugf("Instance ID %d is in status %s, determine if provisioning is needed", instance.ID, instance.Status)
-		w.instanceService.addTaskLogs(ctx, instanceReq.OwnerID, task, fmt.Sprintf("Instance ID %d created, determining if provisioning is needed", instance.ID))
+		w.taskService.recordEvent(ctx, task, models.TaskEventInfo, models.TaskStepCreate, fmt.Sprintf("Instance ID %d created, determining if provisioning is needed", instance.ID),
+			models.TaskEventFields{"instance_id": instance.ID, "provider_instance_id": instance.ProviderInstanceID, "public_ip": instance.PublicIP})
 
 		// Check if the instance needs to be provisioned
 		if !instanceReq.Provision {
@@ -428,7 +798,7 @@ func (w *WorkerPool) processCreateInstanceTask(ctx context.Context, task *models
 				return fmt.Errorf("worker: failed to update instance ID %d: %w", instance.ID, err)
 			}
 			logger.Debugf("✅ Instance ID %d is ready", instance.ID)
-			w.instanceService.addTaskLogs(ctx, instanceReq.OwnerID, task, fmt.Sprintf("Instance ID %d is ready", instance.ID))
+			w.taskService.recordEvent(ctx, task, models.TaskEventInfo, models.TaskStepCreate, fmt.Sprintf("Instance ID %d is ready", instance.ID), nil)
 			return nil
 		}
 
@@ -442,48 +812,15 @@ func (w *WorkerPool) processCreateInstanceTask(ctx context.Context, task *models
 
 		fallthrough
 	case models.InstanceStatusProvisioning:
-		logger.Debugf("Instance ID %d is in status %s, provisioning", instance.ID, instance.Status)
-		w.instanceService.addTaskLogs(ctx, instanceReq.OwnerID, task, fmt.Sprintf("Provisioning instance ID %d", instance.ID))
-
-		// Get provisioning tasks
-		provisioner, err := w.getProvisioner(instanceReq.Provider)
-		if err != nil {
-			return fmt.Errorf("worker: failed to get provisioner for provider %s: %w", instanceReq.Provider, err)
-		}
-
-		// Verify that the instance IP is available
-		if instance.PublicIP == "" {
-			return fmt.Errorf("worker: instance ID %d has no public IP, can't provision", instance.ID)
-		}
-
-		// TODO: Validate inputs
-
-		// create a hosts file with the instance IP to provision.
-		inventoryPath, err := provisioner.CreateInventory(&instanceReq)
-		if err != nil {
-			return fmt.Errorf("worker: failed to create inventory file for instance ID %d: %w", instance.ID, err)
-		}
-
-		if inventoryPath == "" {
-			// Should not happen if hosts were provided and valid, but handle defensively
-			logger.Warnf("Worker: Inventory path empty for instance ID %d, skipping playbook run", instance.ID)
+		if task.Checkpoint == models.TaskStepProvision {
+			logger.Debugf("Instance ID %d was provisioned by a previous attempt of task %d, resuming", instance.ID, task.ID)
 		} else {
-			// tags depending on the provisioner
-			tags := []string{}
-			if instanceReq.Provider == models.ProviderXimera {
-				tags = []string{"setup"}
+			if err := w.provisionInstance(ctx, task, instance, &instanceReq, result); err != nil {
+				return err
 			}
-			if instanceReq.Provider == models.ProviderDO {
-				tags = []string{"setup", "volumes"}
-			}
-
-			if err := provisioner.RunAnsiblePlaybook(inventoryPath, tags); err != nil {
-				return fmt.Errorf("worker: failed to run ansible playbook for instance ID %d: %w", instance.ID, err)
+			if err := w.checkpoint(ctx, task, models.TaskStepProvision, &instanceReq); err != nil {
+				return err
 			}
-			// Optionally remove inventory file after successful run
-			// if err := os.Remove(inventoryPath); err != nil {
-			// 	 logger.Warnf("Worker: Failed to remove inventory file %s: %v", inventoryPath, err)
-			// }
 		}
 
 		// Update status to Ready
@@ -493,42 +830,228 @@ func (w *WorkerPool) processCreateInstanceTask(ctx context.Context, task *models
 			return fmt.Errorf("worker: failed to update instance ID %d to ready: %w", instance.ID, err)
 		}
 		logger.Debugf("✅ Instance ID %d successfully provisioned, marking as ready", instance.ID)
-		w.instanceService.addTaskLogs(ctx, instanceReq.OwnerID, task, fmt.Sprintf("Instance ID %d successfully provisioned and is ready", instance.ID))
+		w.taskService.recordEvent(ctx, task, models.TaskEventInfo, models.TaskStepProvision, fmt.Sprintf("Instance ID %d successfully provisioned and is ready", instance.ID), nil)
 
-	case models.InstanceStatusReady, models.InstanceStatusTerminated:
+	case models.InstanceStatusReady, models.InstanceStatusTerminated, models.InstanceStatusStopped, models.InstanceStatusFailed:
 		// Instance is already in a final state for this task
-		logger.Debugf("Instance ID %d is already ready or terminated, nothing to do for create task.", instance.ID)
-		w.instanceService.addTaskLogs(ctx, instanceReq.OwnerID, task, fmt.Sprintf("Instance ID %d already in final state (%s)", instance.ID, instance.Status))
+		logger.Debugf("Instance ID %d is already ready, stopped, failed or terminated, nothing to do for create task.", instance.ID)
+		w.taskService.recordEvent(ctx, task, models.TaskEventInfo, "", fmt.Sprintf("Instance ID %d already in final state (%s)", instance.ID, instance.Status), nil)
 		return nil
 	default:
-		return fmt.Errorf("worker: instance ID %d is in an unknown state %s", instance.ID, instance.Status)
+		return permanent(fmt.Errorf("worker: instance ID %d is in an unknown state %s", instance.ID, instance.Status))
 	}
 	return nil
 }
 
-// processTerminateInstanceTask processes a terminate instance task. It will handle the infrastructure deletion and status updates for the instance.
-func (w *WorkerPool) processTerminateInstanceTask(ctx context.Context, task *models.Task) error {
-	err := w.taskService.UpdateStatus(ctx, task.OwnerID, task.ID, models.TaskStatusRunning)
+// createInstance creates the instance of a create task on its provider and checkpoints the
+// request with the provider instance ID, IP and volumes, before they are recorded on the instance
+func (w *WorkerPool) createInstance(ctx context.Context, task *models.Task, instance *models.Instance, instanceReq *types.InstanceRequest, result *models.TaskResult) error {
+	logger.Debugf("Instance ID %d is in status %s, creating", instance.ID, instance.Status)
+	w.taskService.recordEvent(ctx, task, models.TaskEventInfo, models.TaskStepCreate, fmt.Sprintf("Creating instance ID %d", instance.ID),
+		models.TaskEventFields{"instance_id": instance.ID, "provider": instanceReq.Provider, "region": instanceReq.Region})
+
+	// Get the compute provider or create a new one
+	provider, err := w.getProvider(instanceReq.Provider)
+	if err != nil {
+		return permanent(fmt.Errorf("worker: failed to get compute provider for provider %s: %w", instanceReq.Provider, err))
+	}
+
+	// Remove what a previous failed attempt left behind on the provider before creating again
+	if instance.ProviderInstanceID != 0 {
+		if err := w.deleteLeftoverInstance(ctx, provider, instance); err != nil {
+			return err
+		}
+	}
+
+	// Record the provider instance as soon as it exists, so that it is deleted by the next attempt
+	// if this one fails or stops before the create checkpoint, instead of being left behind
+	instanceReq.OnProviderInstanceCreated = func(providerInstanceID int) {
+		instance.ProviderInstanceID = providerInstanceID
+		if err := w.instanceService.Update(context.WithoutCancel(ctx), instanceReq.OwnerID, instance.ID, instance); err != nil {
+			logger.Errorf("worker: failed to record provider instance %d of instance ID %d: %v", providerInstanceID, instance.ID, err)
+		}
+	}
+	defer func() { instanceReq.OnProviderInstanceCreated = nil }()
+
+	stepCtx, cancelStep := w.stepContext(ctx, task, models.TaskStepCreate)
+	endStep := result.StartStep(models.TaskStepCreate)
+	err = timeoutError(stepCtx, provider.CreateInstance(stepCtx, instanceReq))
+	endStep(err)
+	cancelStep()
+	if err != nil {
+		return fmt.Errorf("worker: failed to create instance: %w", err)
+	}
+
+	return w.checkpoint(ctx, task, models.TaskStepCreate, instanceReq)
+}
+
+// provisionInstance provisions the instance of a create task with the requested provisioner
+func (w *WorkerPool) provisionInstance(ctx context.Context, task *models.Task, instance *models.Instance, instanceReq *types.InstanceRequest, result *models.TaskResult) error {
+	logger.Debugf("Instance ID %d is in status %s, provisioning", instance.ID, instance.Status)
+	w.taskService.recordEvent(ctx, task, models.TaskEventInfo, models.TaskStepProvision, fmt.Sprintf("Provisioning instance ID %d", instance.ID), nil)
+
+	// Verify that the instance IP is available
+	if instance.PublicIP == "" {
+		return fmt.Errorf("worker: instance ID %d has no public IP, can't provision", instance.ID)
+	}
+
+	provider, err := w.getProvider(instanceReq.Provider)
+	if err != nil {
+		return permanent(fmt.Errorf("worker: failed to get compute provider for provider %s: %w", instanceReq.Provider, err))
+	}
+	// Mounting volumes picks the first extra disk, only providers that declare it mount them
+	var opts compute.ProvisionOptions
+	if mounter, ok := provider.(compute.VolumeMounter); ok {
+		opts.MountVolumes = mounter.MountsVolumesOnProvision()
+	}
+
+	// The playbook is resolved when it is run, an uploaded playbook may have been replaced since
+	// the request was validated
+	if instanceReq.Playbook != "" {
+		playbook, err := w.getPlaybook(ctx, instanceReq)
+		if err != nil {
+			return err
+		}
+		opts.Playbook = playbook
+	}
+
+	// Each task provisions from a workspace of its own, so that concurrent tasks do not share
+	// files, and the workspace is removed once the task is done with it
+	provisioner, err := w.newProvisioner(instanceReq.Provisioner, fmt.Sprintf("task-%d", task.ID))
 	if err != nil {
-		return fmt.Errorf("worker: failed to update task status: %w", err)
+		// The provisioner was validated with the request, a retry would fail the same way
+		return permanent(fmt.Errorf("worker: failed to create provisioner for instance ID %d: %w", instance.ID, err))
+	}
+	defer func() {
+		if err := provisioner.Close(); err != nil {
+			logger.Warnf("Worker: Failed to clean up provisioning of instance ID %d: %v", instance.ID, err)
+		}
+	}()
+
+	// The output of provisioning is recorded as events of the task as it is written, also
+	// while the task is being cancelled
+	eventCtx := context.WithoutCancel(ctx)
+	opts.Output = func(stream compute.OutputStream, line string) {
+		if strings.TrimSpace(line) == "" {
+			return
+		}
+		level := models.TaskEventInfo
+		if stream == compute.OutputStderr {
+			level = models.TaskEventWarn
+		}
+		w.taskService.recordEvent(eventCtx, task, level, models.TaskStepProvision, line,
+			models.TaskEventFields{"instance_id": instance.ID, "stream": stream})
 	}
+
+	// Every provisioning attempt copies the payload again, the payload of a retry starts over
+	if instanceReq.PayloadPath != "" {
+		if err := w.instanceService.ResetPayload(ctx, instance.OwnerID, instance.ID); err != nil {
+			return fmt.Errorf("worker: failed to reset payload of instance ID %d: %w", instance.ID, err)
+		}
+		opts.PayloadStatus = func(status models.PayloadStatus) {
+			w.updatePayloadStatus(eventCtx, task, instance, status)
+		}
+	}
+
+	stepCtx, cancelStep := w.stepContext(ctx, task, models.TaskStepProvision)
+	endStep := result.StartStep(models.TaskStepProvision)
+	provisionResult, err := provisioner.Provision(stepCtx, instanceReq, opts)
+	err = timeoutError(stepCtx, err)
+	endStep(err)
+	cancelStep()
+	if provisionResult != nil {
+		result.AnsibleRecap = provisionResult.Recap
+		result.ProvisionTasks = provisionResult.Tasks
+		result.PayloadExitCode = provisionResult.PayloadExitCode
+		w.recordProvisionFailures(eventCtx, task, instance, provisionResult.Tasks)
+		if provisionResult.PayloadExitCode != nil {
+			err := w.instanceService.SavePayloadOutput(eventCtx, &models.InstancePayload{
+				InstanceID: instance.ID,
+				OwnerID:    instance.OwnerID,
+				ExitCode:   provisionResult.PayloadExitCode,
+				Stdout:     provisionResult.PayloadStdout,
+				Stderr:     provisionResult.PayloadStderr,
+			})
+			if err != nil {
+				logger.Errorf("worker: failed to save payload output of instance ID %d: %v", instance.ID, err)
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("worker: failed to provision instance ID %d: %w", instance.ID, err)
+	}
+	return nil
+}
+
+// updatePayloadStatus stores the status the payload of an instance advanced to and records it
+// as an event of the payload step of the task
+func (w *WorkerPool) updatePayloadStatus(ctx context.Context, task *models.Task, instance *models.Instance, status models.PayloadStatus) {
+	if err := w.instanceService.UpdatePayloadStatus(ctx, instance.OwnerID, instance.ID, status); err != nil {
+		logger.Errorf("worker: failed to update payload status of instance ID %d to %s: %v", instance.ID, status, err)
+	}
+	instance.PayloadStatus = status
+
+	level := models.TaskEventInfo
+	if status == models.PayloadStatusCopyFailed || status == models.PayloadStatusExecutionFailed {
+		level = models.TaskEventError
+	}
+	w.taskService.recordEvent(ctx, task, level, models.TaskStepPayload,
+		fmt.Sprintf("Payload of instance ID %d is %s", instance.ID, status),
+		models.TaskEventFields{"instance_id": instance.ID, "payload_status": status})
+}
+
+// recordProvisionFailures records an error event for each provisioning task that failed or
+// could not reach its host, naming the task and the host
+func (w *WorkerPool) recordProvisionFailures(ctx context.Context, task *models.Task, instance *models.Instance, tasks []models.ProvisionTaskResult) {
+	for _, t := range tasks {
+		if t.Status != models.ProvisionTaskFailed && t.Status != models.ProvisionTaskUnreachable {
+			continue
+		}
+		w.taskService.recordEvent(ctx, task, models.TaskEventError, models.TaskStepProvision,
+			fmt.Sprintf("Provisioning task %q %s on %s", t.Task, t.Status, t.Host),
+			models.TaskEventFields{"instance_id": instance.ID, "host": t.Host, "task": t.Task, "status": t.Status, "message": t.Message})
+	}
+}
+
+// checkpoint records that a step of a create task completed, with the instance request as it
+// stands after the step, so that a retry resumes after the step. The checkpoint is recorded
+// even if the task is being cancelled, since the step already changed the provider.
+func (w *WorkerPool) checkpoint(ctx context.Context, task *models.Task, step models.TaskStep, instanceReq *types.InstanceRequest) error {
+	if w.beforeCheckpoint != nil {
+		if err := w.beforeCheckpoint(step); err != nil {
+			return err
+		}
+	}
+	if err := w.taskService.Checkpoint(context.WithoutCancel(ctx), task, step, instanceReq); err != nil {
+		return fmt.Errorf("worker: %w", err)
+	}
+	if w.afterCheckpoint != nil {
+		return w.afterCheckpoint(step)
+	}
+	return nil
+}
+
+// processTerminateInstanceTask processes a terminate instance task. It will handle the infrastructure deletion and status updates for the instance.
+// The deleted instance and the steps that ran are recorded in result.
+func (w *WorkerPool) processTerminateInstanceTask(ctx context.Context, task *models.Task, result *models.TaskResult) error {
 	logger.Debugf("Terminating instance for task %d", task.ID)
 
 	// Unmarshal the task payload
 	var deleteReq types.DeleteInstanceRequest
-	err = json.Unmarshal(task.Payload, &deleteReq)
+	err := json.Unmarshal(task.Payload, &deleteReq)
 	if err != nil {
-		return fmt.Errorf("worker: failed to unmarshal task payload for task %d: %w", task.ID, err)
+		return permanent(fmt.Errorf("worker: failed to unmarshal task payload for task %d: %w", task.ID, err))
 	}
 
 	// Get the instance
 	instance, err := w.instanceService.Get(ctx, task.OwnerID, deleteReq.InstanceID)
 	if err != nil {
-		return fmt.Errorf("worker: failed to get instance: %w", err)
+		return instanceLookupError(err)
 	}
 	if instance == nil {
-		return fmt.Errorf("worker: instance %d not found", deleteReq.InstanceID)
+		return permanent(fmt.Errorf("worker: instance %d not found", deleteReq.InstanceID))
 	}
+	result.SetInstance(instance)
 
 	// Confirm the instance is not already terminated
 	if instance.Status == models.InstanceStatusTerminated {
@@ -539,20 +1062,28 @@ func (w *WorkerPool) processTerminateInstanceTask(ctx context.Context, task *mod
 	// Get the compute provider or create a new one
 	provider, err := w.getProvider(instance.ProviderID)
 	if err != nil {
-		return fmt.Errorf("worker: failed to get compute provider for provider %s: %w", instance.ProviderID, err)
+		return permanent(fmt.Errorf("worker: failed to get compute provider for provider %s: %w", instance.ProviderID, err))
 	}
 
 	// Delete the instance
 	logger.Infof("🗑️ Deleting %v droplet ID: %d in region %v", instance.ProviderID, instance.ProviderInstanceID, instance.Region)
-	err = provider.DeleteInstance(ctx, instance.ProviderInstanceID)
+	w.taskService.recordEvent(ctx, task, models.TaskEventInfo, models.TaskStepTerminate, fmt.Sprintf("Deleting instance ID %d", instance.ID),
+		models.TaskEventFields{"instance_id": instance.ID, "provider": instance.ProviderID, "provider_instance_id": instance.ProviderInstanceID})
+	stepCtx, cancelStep := w.stepContext(ctx, task, models.TaskStepTerminate)
+	endStep := result.StartStep(models.TaskStepTerminate)
+	err = timeoutError(stepCtx, provider.DeleteInstance(stepCtx, instance.ProviderInstanceID))
+	cancelStep()
 	if err != nil {
 		if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "not found") {
+			endStep(nil)
 			logger.Warnf("⚠️ Warning: Instance %v was already deleted", instance.ProviderInstanceID)
 			// Make sure the instance is marked as terminated in the database
 			return w.instanceService.MarkAsTerminated(ctx, instance.OwnerID, instance.ID)
 		}
+		endStep(err)
 		return fmt.Errorf("failed to delete instance %v: %w", instance.ProviderInstanceID, err)
 	}
+	endStep(nil)
 	logger.Debugf("✅ Successfully deleted instance: %v", instance.ProviderInstanceID)
 
 	// Update database
@@ -562,47 +1093,209 @@ func (w *WorkerPool) processTerminateInstanceTask(ctx context.Context, task *mod
 	return nil
 }
 
-// getProvider returns the compute provider for the given instance
-func (w *WorkerPool) getProvider(providerID models.ProviderID) (compute.Provider, error) {
-	w.computeMU.RLock()
+// watchTermination cancels the processing of a task once the task is terminated. Tasks terminated
+// through this server are cancelled directly by Task.Terminate, polling the task status covers
+// tasks terminated through other servers.
+func (w *WorkerPool) watchTermination(ctx context.Context, cancel context.CancelCauseFunc, task *models.Task) {
+	t := time.NewTicker(w.terminationPollInterval)
+	defer t.Stop()
 
-	provider, ok := w.providers[providerID]
-	if !ok {
-		w.computeMU.RUnlock()
-		w.computeMU.Lock()
-		var err error
-		provider, err = compute.NewComputeProvider(providerID)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		current, err := w.taskService.Get(ctx, task.OwnerID, task.ID)
 		if err != nil {
-			w.computeMU.Unlock()
-			return nil, fmt.Errorf("worker: failed to create compute provider for provider %s: %w", providerID, err)
+			if ctx.Err() == nil {
+				logger.Warnf("⚠️ Failed to check the status of task %d: %v", task.ID, err)
+			}
+			continue
+		}
+		if current.Status == models.TaskStatusTerminated {
+			cancel(ErrTaskTerminated)
+			return
 		}
+	}
+}
 
-		// Provider initialization is complete
+// renewLease extends the lock of a task every lease renew interval while it is being processed.
+// The task is cancelled with ErrTaskLeaseLost once another worker took over the lock, or once the
+// lock may have expired because it could not be renewed in time.
+func (w *WorkerPool) renewLease(ctx context.Context, cancel context.CancelCauseFunc, task *models.Task) {
+	t := time.NewTicker(w.leaseRenewInterval)
+	defer t.Stop()
 
-		w.providers[providerID] = provider
-		w.computeMU.Unlock()
-		w.computeMU.RLock()
+	lockExpiry := time.Now().Add(models.TaskLockTimeout)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		renewedAt := time.Now()
+		err := w.taskService.RenewTaskLock(ctx, task.ID, w.worker.ID)
+		switch {
+		case err == nil:
+			lockExpiry = renewedAt.Add(models.TaskLockTimeout)
+			continue
+		case errors.Is(err, ErrTaskLeaseLost):
+			logger.Warnf("⚠️ Lock of task %d is no longer held by worker %s", task.ID, w.worker.ID)
+			cancel(err)
+			return
+		case ctx.Err() != nil:
+			return
+		}
+
+		logger.Warnf("⚠️ Failed to renew the lock of task %d: %v", task.ID, err)
+		if !time.Now().Before(lockExpiry) {
+			logger.Warnf("⚠️ Lock of task %d expired before it could be renewed", task.ID)
+			cancel(fmt.Errorf("task %d: lock expired: %w", task.ID, ErrTaskLeaseLost))
+			return
+		}
 	}
-	w.computeMU.RUnlock()
+}
 
-	return provider, nil
+// finishTerminatedTask cleans up after a task that was terminated while being processed and records
+// the outcome on the task. The task status was already set to terminated by Task.Terminate.
+func (w *WorkerPool) finishTerminatedTask(ctx context.Context, task *models.Task, processErr error) {
+	// The cleanup has to run even if the worker pool is shutting down
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), taskCleanupTimeout)
+	defer cancel()
+
+	w.taskService.recordEvent(ctx, task, models.TaskEventWarn, models.TaskStepTerminate,
+		fmt.Sprintf("🛑 Task terminated while processing: %v", processErr), nil)
+	if task.Action == models.TaskActionCreateInstances {
+		if err := w.rollbackCreateInstance(ctx, task); err != nil {
+			logger.Errorf("❌ Failed to roll back terminated task %d: %v", task.ID, err)
+			w.taskService.recordEvent(ctx, task, models.TaskEventError, models.TaskStepTerminate,
+				fmt.Sprintf("❌ Failed to roll back the instance: %v", err), nil)
+		} else {
+			w.taskService.recordEvent(ctx, task, models.TaskEventInfo, models.TaskStepTerminate, "Rolled back the instance", nil)
+		}
+	}
+
+	if err := w.taskService.UpdateStatus(ctx, task.OwnerID, task.ID, models.TaskStatusTerminated); err != nil {
+		logger.Errorf("Failed to update status of terminated task %d: %v", task.ID, err)
+	}
+}
+
+// failTimedOutInstance moves the instance of a task that ran out of time to the failed state.
+// What the task created on the provider is kept, so that the instance can be inspected and
+// terminated like any other.
+func (w *WorkerPool) failTimedOutInstance(ctx context.Context, task *models.Task, processErr error) {
+	ctx = context.WithoutCancel(ctx)
+	if task.InstanceID == 0 {
+		return
+	}
+	instance, err := w.instanceService.Get(ctx, task.OwnerID, task.InstanceID)
+	if err != nil {
+		logger.Errorf("Failed to get instance ID %d of timed out task %d: %v", task.InstanceID, task.ID, err)
+		return
+	}
+	if instance.Status == models.InstanceStatusTerminated {
+		return
+	}
+
+	instance.Status = models.InstanceStatusFailed
+	if err := w.instanceService.Update(ctx, task.OwnerID, instance.ID, instance); err != nil {
+		logger.Errorf("Failed to mark instance ID %d of timed out task %d as failed: %v", instance.ID, task.ID, err)
+		return
+	}
+	w.taskService.recordEvent(ctx, task, models.TaskEventError, "", fmt.Sprintf("⏰ Instance ID %d failed: %v", instance.ID, processErr),
+		models.TaskEventFields{"instance_id": instance.ID, "provider_instance_id": instance.ProviderInstanceID})
 }
 
-// getProvisioner returns the provisioner for the given instance
-func (w *WorkerPool) getProvisioner(providerID models.ProviderID) (compute.Provisioner, error) {
-	w.computeMU.RLock()
+// rollbackCreateInstance deletes what a terminated create instance task created on the provider
+// and marks the instance as terminated
+func (w *WorkerPool) rollbackCreateInstance(ctx context.Context, task *models.Task) error {
+	var instanceReq types.InstanceRequest
+	if err := json.Unmarshal(task.Payload, &instanceReq); err != nil {
+		return fmt.Errorf("failed to unmarshal task payload for task %d: %w", task.ID, err)
+	}
 
-	provisioner, ok := w.provisioners[providerID]
-	if !ok {
-		w.computeMU.RUnlock()
-		w.computeMU.Lock()
-		jobID := fmt.Sprintf("job-%s", time.Now().Format("20060102-150405"))
-		provisioner = compute.NewProvisioner(jobID)
-		w.provisioners[providerID] = provisioner
-		w.computeMU.Unlock()
-		w.computeMU.RLock()
+	instance, err := w.instanceService.Get(ctx, instanceReq.OwnerID, instanceReq.InstanceID)
+	if err != nil {
+		return fmt.Errorf("failed to get instance: %w", err)
+	}
+	if instance.Status == models.InstanceStatusTerminated {
+		return nil
+	}
+	// An instance created right before the termination may only be recorded in the checkpoint
+	if instance.ProviderInstanceID == 0 && task.Checkpoint != "" {
+		instance.ProviderInstanceID = instanceReq.ProviderInstanceID
 	}
-	w.computeMU.RUnlock()
 
-	return provisioner, nil
+	if instance.ProviderInstanceID != 0 {
+		provider, err := w.getProvider(instance.ProviderID)
+		if err != nil {
+			return err
+		}
+		logger.Infof("🗑️ Rolling back %v instance %d of terminated task %d", instance.ProviderID, instance.ProviderInstanceID, task.ID)
+		err = provider.DeleteInstance(ctx, instance.ProviderInstanceID)
+		if err != nil && !errors.Is(err, types.ErrProviderInstanceNotFound) &&
+			!strings.Contains(err.Error(), "404") && !strings.Contains(err.Error(), "not found") {
+			return fmt.Errorf("failed to delete instance %d: %w", instance.ProviderInstanceID, err)
+		}
+	}
+
+	if err := w.instanceService.MarkAsTerminated(ctx, instance.OwnerID, instance.ID); err != nil {
+		return fmt.Errorf("failed to terminate instance ID %d in database: %w", instance.ID, err)
+	}
+	return nil
+}
+
+// deleteLeftoverInstance deletes the provider instance a failed create attempt left behind.
+// The recorded provider instance ID is replaced once the instance is created again, deleting an
+// instance that is already gone is not an error.
+func (w *WorkerPool) deleteLeftoverInstance(ctx context.Context, provider compute.Provider, instance *models.Instance) error {
+	logger.Infof("🗑️ Deleting %v instance %d left behind by a failed attempt for instance ID %d", instance.ProviderID, instance.ProviderInstanceID, instance.ID)
+	err := provider.DeleteInstance(ctx, instance.ProviderInstanceID)
+	if err != nil && !errors.Is(err, types.ErrProviderInstanceNotFound) &&
+		!strings.Contains(err.Error(), "404") && !strings.Contains(err.Error(), "not found") {
+		return fmt.Errorf("worker: failed to delete leftover instance %d: %w", instance.ProviderInstanceID, err)
+	}
+	instance.ProviderInstanceID = 0
+	instance.PublicIP = ""
+	return nil
+}
+
+// instanceLookupError classifies an error from looking up the instance of a task. A missing
+// instance will not appear on a retry.
+func instanceLookupError(err error) error {
+	err = fmt.Errorf("worker: failed to get instance: %w", err)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return permanent(err)
+	}
+	return err
+}
+
+// getPlaybook resolves the playbook of an instance request with the tags and extra variables
+// of the request. A playbook that no longer exists fails the task permanently.
+func (w *WorkerPool) getPlaybook(ctx context.Context, instanceReq *types.InstanceRequest) (*compute.Playbook, error) {
+	if w.instanceService.playbooks == nil {
+		return nil, permanent(fmt.Errorf("worker: playbooks are not available, can't run playbook %q", instanceReq.Playbook))
+	}
+	playbook, err := w.instanceService.playbooks.Resolve(ctx, instanceReq.OwnerID, instanceReq.Playbook)
+	if errors.Is(err, ErrPlaybookNotFound) {
+		return nil, permanent(fmt.Errorf("worker: %w", err))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("worker: %w", err)
+	}
+	playbook.Tags = instanceReq.PlaybookTags
+	playbook.ExtraVars = instanceReq.ExtraVars
+	return playbook, nil
+}
+
+// getProvider returns the compute provider for the given instance
+func (w *WorkerPool) getProvider(providerID models.ProviderID) (compute.Provider, error) {
+	provider, err := w.instanceService.providers.Get(providerID)
+	if err != nil {
+		return nil, fmt.Errorf("worker: %w", err)
+	}
+	return provider, nil
 }
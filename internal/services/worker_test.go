@@ -1,161 +1,1223 @@
 package services
 
 import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
 
+	"github.com/celestiaorg/talis/internal/compute"
+	"github.com/celestiaorg/talis/internal/constants"
 	"github.com/celestiaorg/talis/internal/db/models"
+	"github.com/celestiaorg/talis/internal/db/repos"
+	"github.com/celestiaorg/talis/internal/types"
+	"github.com/celestiaorg/talis/test/mocks"
 )
 
-func TestWorker_getProvider(t *testing.T) {
-	// Create a worker with nil services as they are not used by getProvider
-	// Use a short backoff for testing purposes if needed, though not relevant here.
-	w := NewWorkerPool(nil, nil, nil, nil, nil, time.Millisecond*10)
-
-	// Define provider IDs for testing
-	// Assuming "digitalocean-mock" is a valid provider ID that compute.NewComputeProvider can handle
-	validProviderID := models.ProviderID("digitalocean-mock")
-	// Assuming this ID will cause compute.NewComputeProvider to return an error
-	invalidProviderID := models.ProviderID("invalid-provider-id-for-test")
-
-	t.Run("Basic", func(t *testing.T) {
-		provider, err := w.getProvider(validProviderID)
-		require.NoError(t, err, "Getting a valid provider should not return an error")
-		require.NotNil(t, provider, "Provider instance should not be nil for a valid ID")
-
-		// Store the instance for comparison in the next test
-		firstProviderInstance := provider
-
-		// Check internal state
-		w.computeMU.RLock()
-		cachedProvider, exists := w.providers[validProviderID]
-		w.computeMU.RUnlock()
-		require.True(t, exists, "Provider should be cached after first retrieval")
-		require.Same(t, firstProviderInstance, cachedProvider, "Cached provider instance should match the returned one")
-
-		provider, err = w.getProvider(validProviderID)
-		require.NoError(t, err, "Getting a cached valid provider should not return an error")
-		require.NotNil(t, provider, "Cached provider instance should not be nil")
-
-		// Crucial check: Ensure it's the *same* instance, not a new one
-		require.Same(t, firstProviderInstance, provider, "Second call for the same provider ID should return the identical cached instance")
-
-		// Get the invalid provider
-		provider, err = w.getProvider(invalidProviderID)
-
-		// Check that an error occurred
-		require.Error(t, err, "Getting an invalid provider should return an error")
-		require.Nil(t, provider, "Provider instance should be nil when an error occurs")
-
-		// Check if the error message indicates an unsupported provider (adjust based on actual error)
-		// This depends on the error returned by compute.NewComputeProvider for invalid IDs
-		require.Contains(t, err.Error(), "unsupported provider", "Error message should indicate an unsupported provider")
-
-		// Check internal state
-		w.computeMU.RLock()
-		_, exists = w.providers[invalidProviderID]
-		w.computeMU.RUnlock()
-		require.False(t, exists, "Invalid provider should not be added to the cache")
-	})
+func TestWorker_provisionInstance_Concurrent(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+	t.Setenv(constants.EnvTalisSSHKey, "test-ssh-key")
+
+	const numInstances = 20
+	req := types.InstanceRequest{
+		OwnerID: 1, ProjectName: "test-project-concurrent", Provider: models.ProviderDO,
+		Region: "nyc1", Size: "s-1vcpu-1gb", Image: "ubuntu-22-04-x64",
+		NumberOfInstances: numInstances, Action: "create", Provision: true,
+		Volumes: []types.VolumeConfig{{Name: "data", SizeGB: 10, MountPoint: "/mnt/data"}},
+	}
+	require.NoError(t, ts.ProjectRepo.Create(ts.ctx, &models.Project{OwnerID: req.OwnerID, Name: req.ProjectName}))
+	created, err := ts.InstanceService.CreateInstance(ts.ctx, []types.InstanceRequest{req})
+	require.NoError(t, err)
+	require.Len(t, created, numInstances)
+
+	// The fake playbook reads its inventory twice while the other tasks write theirs, and
+	// records the workspace it ran in
+	var mu sync.Mutex
+	workspaces := make(map[string]string)
+	runner := func(_ context.Context, args, _ []string, stdout, _ io.Writer) error {
+		inventoryPath := args[slices.Index(args, "-i")+1]
+		inventory, err := os.ReadFile(inventoryPath)
+		if err != nil {
+			return err
+		}
+		time.Sleep(10 * time.Millisecond)
+		again, err := os.ReadFile(inventoryPath)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(inventory, again) {
+			return fmt.Errorf("inventory %s changed while the playbook ran", inventoryPath)
+		}
+
+		workspace := filepath.Dir(inventoryPath)
+		host := strings.Fields(strings.SplitN(string(inventory), "[all]\n", 2)[1])[0]
+		if !strings.Contains(string(inventory), "ansible_ssh_private_key_file="+filepath.Join(workspace, "ssh_key")) {
+			return fmt.Errorf("inventory of %s does not use the key in its workspace", host)
+		}
+
+		mu.Lock()
+		workspaces[workspace] = host
+		mu.Unlock()
+		_, err = fmt.Fprintf(stdout, "PLAY RECAP ****\n%s : ok=1 changed=0 unreachable=0 failed=0 skipped=0 rescued=0 ignored=0\n", host)
+		return err
+	}
+
+	w := NewWorkerPool(ts.InstanceService, ts.ProjectService, ts.TaskService, nil, nil, time.Millisecond*10)
+	ts.InstanceService.providers.Set(req.Provider, &countingProvider{})
+	w.newProvisioner = func(_ models.ProvisionerID, jobID string) (compute.Provisioner, error) {
+		return compute.NewAnsibleConfigurator(jobID).WithPlaybookRunner(runner), nil
+	}
+
+	// Provision all instances at the same time, each from its own task
+	results := make([]*models.TaskResult, numInstances)
+	errs := make([]error, numInstances)
+	var wg sync.WaitGroup
+	for i, instance := range created {
+		tasks, err := ts.TaskService.ListTasksByInstanceID(ts.ctx, req.OwnerID, instance.ID, models.TaskActionCreateInstances, nil)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
 
-	// Test concurrent access to the provider cache, rapidly accessing the provider map
-	t.Run("Concurrency", func(_ *testing.T) {
-		// Test concurrent access to the provider cache
-		numGoroutines := 10
-		wg := sync.WaitGroup{}
-		wg.Add(numGoroutines)
-		done := make(chan struct{})
+		instance.PublicIP = fmt.Sprintf("198.51.100.%d", i+1)
+		instanceReq := req
+		instanceReq.InstanceID = instance.ID
+		instanceReq.PublicIP = instance.PublicIP
+		results[i] = &models.TaskResult{}
+
+		wg.Add(1)
 		go func() {
-			time.Sleep(time.Second)
-			close(done)
+			defer wg.Done()
+			errs[i] = w.provisionInstance(ts.ctx, &tasks[0], instance, &instanceReq, results[i])
 		}()
-		for i := 0; i < numGoroutines; i++ {
-			providerID := validProviderID
-			if i >= numGoroutines/2 {
-				providerID = invalidProviderID
-			}
-			go func(done chan struct{}, providerID models.ProviderID) {
-				defer wg.Done()
-				for {
-					select {
-					case <-done:
-						return
-					default:
-						// We are only testing the concurrency of the provider cache, not the correctness of the provider
-						_, _ = w.getProvider(providerID)
-					}
-				}
-			}(done, providerID)
+	}
+	wg.Wait()
+
+	// Each task provisioned its own instance from a workspace of its own
+	for i, instance := range created {
+		require.NoError(t, errs[i])
+		require.Equal(t, map[string]models.AnsibleHostRecap{instance.PublicIP: {Ok: 1}}, results[i].AnsibleRecap)
+	}
+	require.Len(t, workspaces, numInstances)
+
+	// The workspaces were removed
+	for workspace := range workspaces {
+		require.NoDirExists(t, workspace)
+	}
+}
+
+// failingProvisioner writes output lines and fails on the instance like a playbook whose task failed
+type failingProvisioner struct {
+	compute.Provisioner
+}
+
+func (p *failingProvisioner) Provision(_ context.Context, instance *types.InstanceRequest, opts compute.ProvisionOptions) (*compute.ProvisionResult, error) {
+	opts.Output(compute.OutputStdout, "TASK [Install required packages] ****")
+	opts.Output(compute.OutputStdout, "")
+	opts.Output(compute.OutputStderr, "[WARNING]: deprecated")
+	return &compute.ProvisionResult{Tasks: []models.ProvisionTaskResult{
+		{Task: "Gathering Facts", Host: instance.PublicIP, Status: models.ProvisionTaskOk},
+		{Task: "Install required packages", Host: instance.PublicIP, Status: models.ProvisionTaskFailed, Message: "No package matching 'gti'"},
+	}}, fmt.Errorf("%w: ansible playbook exited with code 2", compute.ErrProvisioningFailed)
+}
+
+func (p *failingProvisioner) Close() error {
+	return nil
+}
+
+func TestWorker_provisionInstance_Events(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	req := types.InstanceRequest{
+		OwnerID: 1, ProjectName: "test-project-events", Provider: models.ProviderDO,
+		Region: "nyc1", Size: "s-1vcpu-1gb", Image: "ubuntu-22-04-x64",
+		NumberOfInstances: 1, Action: "create", Provision: true,
+		Volumes: []types.VolumeConfig{{Name: "data", SizeGB: 10, MountPoint: "/mnt/data"}},
+	}
+	require.NoError(t, ts.ProjectRepo.Create(ts.ctx, &models.Project{OwnerID: req.OwnerID, Name: req.ProjectName}))
+	created, err := ts.InstanceService.CreateInstance(ts.ctx, []types.InstanceRequest{req})
+	require.NoError(t, err)
+	tasks, err := ts.TaskService.ListTasksByInstanceID(ts.ctx, req.OwnerID, created[0].ID, models.TaskActionCreateInstances, nil)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+
+	w := NewWorkerPool(ts.InstanceService, ts.ProjectService, ts.TaskService, nil, nil, time.Millisecond*10)
+	ts.InstanceService.providers.Set(req.Provider, &countingProvider{})
+	w.newProvisioner = func(models.ProvisionerID, string) (compute.Provisioner, error) {
+		return &failingProvisioner{}, nil
+	}
+
+	instance := created[0]
+	instance.PublicIP = "192.0.2.1"
+	instanceReq := req
+	instanceReq.InstanceID = instance.ID
+	instanceReq.PublicIP = instance.PublicIP
+	result := &models.TaskResult{}
+	err = w.provisionInstance(ts.ctx, &tasks[0], instance, &instanceReq, result)
+	require.ErrorIs(t, err, compute.ErrProvisioningFailed)
+	require.Len(t, result.ProvisionTasks, 2)
+
+	// The output lines and the failed task are recorded on the task, blank lines are left out
+	events, err := ts.TaskService.ListEvents(ts.ctx, req.OwnerID, tasks[0].ID, 0, nil)
+	require.NoError(t, err)
+	var provisionEvents []models.TaskEvent
+	for _, event := range events {
+		if event.Step == models.TaskStepProvision && event.Message != fmt.Sprintf("Provisioning instance ID %d", instance.ID) {
+			provisionEvents = append(provisionEvents, event)
 		}
-		wg.Wait()
+	}
+	require.Len(t, provisionEvents, 3)
+	require.Equal(t, models.TaskEventInfo, provisionEvents[0].Level)
+	require.Equal(t, "TASK [Install required packages] ****", provisionEvents[0].Message)
+	require.Equal(t, "stdout", provisionEvents[0].Fields["stream"])
+	require.Equal(t, models.TaskEventWarn, provisionEvents[1].Level)
+	require.Equal(t, "[WARNING]: deprecated", provisionEvents[1].Message)
+	require.Equal(t, models.TaskEventError, provisionEvents[2].Level)
+	require.Equal(t, `Provisioning task "Install required packages" failed on 192.0.2.1`, provisionEvents[2].Message)
+	require.Equal(t, "192.0.2.1", provisionEvents[2].Fields["host"])
+	require.Equal(t, "No package matching 'gti'", provisionEvents[2].Fields["message"])
+}
+
+// payloadProvisioner copies and executes the payload, reporting its statuses, and fails it with
+// the exit code 3
+type payloadProvisioner struct {
+	compute.Provisioner
+	// statuses holds the stored payload status as of each reported status
+	statuses []models.PayloadStatus
+	get      func() models.PayloadStatus
+}
+
+func (p *payloadProvisioner) Provision(_ context.Context, _ *types.InstanceRequest, opts compute.ProvisionOptions) (*compute.ProvisionResult, error) {
+	for _, status := range []models.PayloadStatus{
+		models.PayloadStatusCopied, models.PayloadStatusPendingExecution, models.PayloadStatusExecutionFailed,
+	} {
+		opts.PayloadStatus(status)
+		p.statuses = append(p.statuses, p.get())
+	}
+	exitCode := 3
+	return &compute.ProvisionResult{PayloadExitCode: &exitCode, PayloadStdout: "syncing", PayloadStderr: "disk full"},
+		fmt.Errorf("%w: payload exited with code 3", compute.ErrProvisioningFailed)
+}
+
+func (p *payloadProvisioner) Close() error {
+	return nil
+}
+
+func TestWorker_provisionInstance_Payload(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	payloadPath := filepath.Join(t.TempDir(), "payload.sh")
+	require.NoError(t, os.WriteFile(payloadPath, []byte("echo syncing\n"), 0600))
+	req := types.InstanceRequest{
+		OwnerID: 1, ProjectName: "test-project-payload", Provider: models.ProviderDO,
+		Region: "nyc1", Size: "s-1vcpu-1gb", Image: "ubuntu-22-04-x64",
+		NumberOfInstances: 1, Action: "create", Provision: true,
+		Volumes:     []types.VolumeConfig{{Name: "data", SizeGB: 10, MountPoint: "/mnt/data"}},
+		PayloadPath: payloadPath, ExecutePayload: true,
+	}
+	require.NoError(t, ts.ProjectRepo.Create(ts.ctx, &models.Project{OwnerID: req.OwnerID, Name: req.ProjectName}))
+	created, err := ts.InstanceService.CreateInstance(ts.ctx, []types.InstanceRequest{req})
+	require.NoError(t, err)
+	tasks, err := ts.TaskService.ListTasksByInstanceID(ts.ctx, req.OwnerID, created[0].ID, models.TaskActionCreateInstances, nil)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+
+	instance := created[0]
+	payload, err := ts.InstanceService.GetPayload(ts.ctx, req.OwnerID, instance.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.PayloadStatusPendingCopy, payload.Status)
+	require.Nil(t, payload.ExitCode)
+
+	w := NewWorkerPool(ts.InstanceService, ts.ProjectService, ts.TaskService, nil, nil, time.Millisecond*10)
+	ts.InstanceService.providers.Set(req.Provider, &countingProvider{})
+	provisioner := &payloadProvisioner{get: func() models.PayloadStatus {
+		stored, err := ts.InstanceService.Get(ts.ctx, req.OwnerID, instance.ID)
+		require.NoError(t, err)
+		return stored.PayloadStatus
+	}}
+	w.newProvisioner = func(models.ProvisionerID, string) (compute.Provisioner, error) {
+		return provisioner, nil
+	}
+
+	instance.PublicIP = "192.0.2.1"
+	instanceReq := req
+	instanceReq.InstanceID = instance.ID
+	instanceReq.PublicIP = instance.PublicIP
+	err = w.provisionInstance(ts.ctx, &tasks[0], instance, &instanceReq, &models.TaskResult{})
+	require.ErrorIs(t, err, compute.ErrProvisioningFailed)
+
+	// Each status is stored as it is reported
+	require.Equal(t, []models.PayloadStatus{
+		models.PayloadStatusCopied, models.PayloadStatusPendingExecution, models.PayloadStatusExecutionFailed,
+	}, provisioner.statuses)
+	payload, err = ts.InstanceService.GetPayload(ts.ctx, req.OwnerID, instance.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.PayloadStatusExecutionFailed, payload.Status)
+	require.Equal(t, 3, *payload.ExitCode)
+	require.Equal(t, "syncing", payload.Stdout)
+	require.Equal(t, "disk full", payload.Stderr)
+
+	events, err := ts.TaskService.ListEvents(ts.ctx, req.OwnerID, tasks[0].ID, 0, nil)
+	require.NoError(t, err)
+	var failed *models.TaskEvent
+	for i := range events {
+		if events[i].Message == fmt.Sprintf("Payload of instance ID %d is execution_failed", instance.ID) {
+			failed = &events[i]
+		}
+	}
+	require.NotNil(t, failed)
+	require.Equal(t, models.TaskEventError, failed.Level)
+	require.Equal(t, models.TaskStepPayload, failed.Step)
+
+	// A retry starts the payload over
+	provisioner.statuses = nil
+	provisioner.get = func() models.PayloadStatus {
+		stored, err := ts.InstanceService.GetPayload(ts.ctx, req.OwnerID, instance.ID)
+		require.NoError(t, err)
+		if stored.Status == models.PayloadStatusCopied {
+			// The output of the previous run was deleted
+			require.Nil(t, stored.ExitCode)
+		}
+		return stored.Status
+	}
+	err = w.provisionInstance(ts.ctx, &tasks[0], instance, &instanceReq, &models.TaskResult{})
+	require.ErrorIs(t, err, compute.ErrProvisioningFailed)
+	require.Equal(t, models.PayloadStatusCopied, provisioner.statuses[0])
+}
+
+// processInstanceLifecycle creates an instance through processCreateInstanceTask using the given
+// provider, then terminates it through processTerminateInstanceTask. It returns the instance as
+// stored after creation and after termination.
+func processInstanceLifecycle(
+	t *testing.T,
+	provider compute.Provider,
+	req types.InstanceRequest,
+) (*models.Instance, *models.Instance) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	err := ts.ProjectRepo.Create(ts.ctx, &models.Project{
+		OwnerID: req.OwnerID,
+		Name:    req.ProjectName,
 	})
+	require.NoError(t, err)
+
+	// Inject the provider so the worker does not build a real one
+	w := NewWorkerPool(ts.InstanceService, ts.ProjectService, ts.TaskService, nil, nil, time.Millisecond*10)
+	ts.InstanceService.providers.Set(req.Provider, provider)
+
+	created, err := ts.InstanceService.CreateInstance(ts.ctx, []types.InstanceRequest{req})
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	instanceID := created[0].ID
+
+	tasks, err := ts.TaskService.ListTasksByInstanceID(ts.ctx, req.OwnerID, instanceID, models.TaskActionCreateInstances, nil)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	createResult := &models.TaskResult{}
+	require.NoError(t, w.processCreateInstanceTask(ts.ctx, &tasks[0], createResult))
+
+	ready, err := ts.InstanceService.Get(ts.ctx, req.OwnerID, instanceID)
+	require.NoError(t, err)
+
+	// The result describes the created instance
+	require.Equal(t, instanceID, createResult.InstanceID)
+	require.Equal(t, ready.ProviderInstanceID, createResult.ProviderInstanceID)
+	require.Equal(t, ready.PublicIP, createResult.PublicIP)
+	require.Equal(t, ready.VolumeDetails, createResult.VolumeDetails)
+	require.Len(t, createResult.Steps, 1)
+	require.Equal(t, models.TaskStepCreate, createResult.Steps[0].Step)
+	require.Empty(t, createResult.Steps[0].Error)
+
+	require.NoError(t, ts.InstanceService.Terminate(ts.ctx, req.OwnerID, req.ProjectName, []uint{instanceID}))
+	tasks, err = ts.TaskService.ListTasksByInstanceID(ts.ctx, req.OwnerID, instanceID, models.TaskActionTerminateInstances, nil)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	terminateResult := &models.TaskResult{}
+	require.NoError(t, w.processTerminateInstanceTask(ts.ctx, &tasks[0], terminateResult))
+	require.Equal(t, ready.ProviderInstanceID, terminateResult.ProviderInstanceID)
+	require.Len(t, terminateResult.Steps, 1)
+	require.Equal(t, models.TaskStepTerminate, terminateResult.Steps[0].Step)
+
+	terminated, err := ts.InstanceService.Get(ts.ctx, req.OwnerID, instanceID)
+	require.NoError(t, err)
+	return ready, terminated
 }
 
-func TestWorker_getProvisioner(t *testing.T) {
-	// Create a worker with nil services as they are not used by getProvisioner
-	w := NewWorkerPool(nil, nil, nil, nil, nil, time.Millisecond*10)
+func TestWorker_processInstanceTasks_Linode(t *testing.T) {
+	t.Setenv(constants.EnvTalisSSHKeyName, mocks.DefaultLinodeKeyName)
+	mockClient := mocks.NewMockLinodeClient()
+	provider := &compute.LinodeProvider{}
+	provider.SetClient(mockClient)
 
-	// Define a provider ID for testing. getProvisioner works with any valid ProviderID.
-	// We'll use the same mock ID as in the provider test for consistency.
-	providerID := models.ProviderID("digitalocean-mock")
+	ready, terminated := processInstanceLifecycle(t, provider, types.InstanceRequest{
+		OwnerID: 1, ProjectName: "test-project-linode", Provider: models.ProviderLinode,
+		Region: "us-east", Size: "g6-standard-2", Image: "linode/ubuntu22.04",
+		NumberOfInstances: 1, Action: "create",
+		Volumes: []types.VolumeConfig{{Name: "data", SizeGB: 10, MountPoint: "/mnt/data"}},
+	})
 
-	t.Run("Basic", func(t *testing.T) {
-		// --- Get provisioner for the first time ---
-		provisioner, err := w.getProvisioner(providerID)
-		require.NoError(t, err, "Getting a valid provisioner should not return an error")
-		require.NotNil(t, provisioner, "Provisioner instance should not be nil")
+	require.Equal(t, models.InstanceStatusReady, ready.Status)
+	require.Equal(t, mocks.DefaultLinodeInstanceIP, ready.PublicIP)
+	require.Equal(t, mocks.DefaultLinodeInstanceID, ready.ProviderInstanceID)
+	require.Len(t, ready.VolumeDetails, 1)
+	require.Equal(t, "/mnt/data", ready.VolumeDetails[0].MountPoint)
 
-		// Store the instance for comparison
-		firstProvisionerInstance := provisioner
+	require.Equal(t, models.InstanceStatusTerminated, terminated.Status)
+	require.Equal(t, []int{mocks.DefaultLinodeInstanceID}, mockClient.DeletedInstances)
+	require.Equal(t, []int{mocks.DefaultLinodeVolumeID}, mockClient.DeletedVolumes)
+}
+
+func TestWorker_processInstanceTasks_Vultr(t *testing.T) {
+	t.Setenv(constants.EnvTalisSSHKeyName, mocks.DefaultVultrKeyName)
+	t.Setenv(constants.EnvTalisDeploymentID, "test")
+	mockClient := mocks.NewMockVultrClient()
+	provider := &compute.VultrProvider{}
+	provider.SetClient(mockClient)
 
-		// Check internal state
-		w.computeMU.RLock()
-		cachedProvisioner, exists := w.provisioners[providerID]
-		w.computeMU.RUnlock()
-		require.True(t, exists, "Provisioner should be cached after first retrieval")
-		require.Same(t, firstProvisionerInstance, cachedProvisioner, "Cached provisioner instance should match the returned one")
+	ready, terminated := processInstanceLifecycle(t, provider, types.InstanceRequest{
+		OwnerID: 1, ProjectName: "test-project-vultr", Provider: models.ProviderVultr,
+		Region: "ewr", Size: "vc2-1c-1gb", Image: "1743",
+		NumberOfInstances: 1, Action: "create",
+		Volumes: []types.VolumeConfig{{Name: "data", SizeGB: 40, MountPoint: "/mnt/data"}},
+	})
 
-		// --- Get the same provisioner again (should return cached instance) ---
-		provisioner, err = w.getProvisioner(providerID)
-		require.NoError(t, err, "Getting a cached valid provisioner should not return an error")
-		require.NotNil(t, provisioner, "Cached provisioner instance should not be nil")
+	require.Equal(t, models.InstanceStatusReady, ready.Status)
+	require.Equal(t, mocks.DefaultVultrInstanceIP, ready.PublicIP)
+	require.Equal(t, int(ready.ID), ready.ProviderInstanceID)
+	require.Len(t, ready.VolumeIDs, 1)
 
-		// Crucial check: Ensure it's the *same* instance, not a new one
-		require.Same(t, firstProvisionerInstance, provisioner, "Second call for the same provider ID should return the identical cached instance")
+	require.Equal(t, models.InstanceStatusTerminated, terminated.Status)
+	require.Len(t, mockClient.DeletedInstances, 1)
+	require.Equal(t, []string(ready.VolumeIDs), mockClient.DeletedBlocks)
+	require.Empty(t, mockClient.Blocks())
+}
+
+func TestWorker_processInstanceTasks_AWS(t *testing.T) {
+	server := mocks.NewFakeEC2Server()
+	defer server.Close()
+
+	// The provider talks to the local EC2 stand-in through the endpoint override
+	t.Setenv("AWS_ACCESS_KEY_ID", mocks.DefaultEC2AccessKeyID)
+	t.Setenv("AWS_SECRET_ACCESS_KEY", mocks.DefaultEC2SecretAccessKey)
+	t.Setenv("AWS_REGION", mocks.DefaultEC2Region)
+	t.Setenv("AWS_ENDPOINT_URL_EC2", server.URL)
+	t.Setenv(constants.EnvTalisSSHKeyName, mocks.DefaultEC2KeyName)
+	t.Setenv(constants.EnvTalisDeploymentID, "test")
+	provider, err := compute.NewAWSProvider()
+	require.NoError(t, err)
+
+	ready, terminated := processInstanceLifecycle(t, provider, types.InstanceRequest{
+		OwnerID: 1, ProjectName: "test-project-aws", Provider: models.ProviderAWS,
+		Region: mocks.DefaultEC2Region, Size: mocks.DefaultEC2InstanceType, Image: "ubuntu-22.04",
+		NumberOfInstances: 1, Action: "create",
+		Volumes: []types.VolumeConfig{{Name: "data", SizeGB: 40, MountPoint: "/mnt/data"}},
 	})
 
-	// Test concurrent access to the provisioner cache, rapidly accessing the map
-	t.Run("Concurrency", func(_ *testing.T) {
-		numGoroutines := 10
-		wg := sync.WaitGroup{}
-		wg.Add(numGoroutines)
-		done := make(chan struct{}) // Signal channel to stop goroutines
+	require.Equal(t, models.InstanceStatusReady, ready.Status)
+	require.Equal(t, mocks.DefaultEC2InstanceIP, ready.PublicIP)
+	require.Equal(t, int(ready.ID), ready.ProviderInstanceID)
+	require.Len(t, ready.VolumeIDs, 1)
 
-		// Goroutine to close the done channel after a short delay
-		go func() {
-			time.Sleep(time.Second) // Let goroutines run for a bit
-			close(done)
-		}()
+	require.Equal(t, models.InstanceStatusTerminated, terminated.Status)
+	require.Len(t, server.TerminatedInstances, 1)
+	require.Empty(t, server.Volumes())
+}
+
+func TestWorker_processInstanceTasks_Local(t *testing.T) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(privateKey, "")
+	require.NoError(t, err)
+	t.Setenv(constants.EnvTalisSSHKey, string(pem.EncodeToMemory(block)))
+
+	mockClient := mocks.NewMockDockerClient()
+	provider := &compute.LocalProvider{}
+	provider.SetClient(mockClient)
+
+	ready, terminated := processInstanceLifecycle(t, provider, types.InstanceRequest{
+		OwnerID: 1, ProjectName: "test-project-local", Provider: models.ProviderLocal,
+		Region: "local", Size: "local", Image: compute.LocalDefaultImage,
+		NumberOfInstances: 1, Action: "create",
+		Volumes: []types.VolumeConfig{{Name: "data", SizeGB: 10, MountPoint: "/mnt/data"}},
+	})
+
+	require.Equal(t, models.InstanceStatusReady, ready.Status)
+	require.Equal(t, mocks.DefaultDockerContainerIP, ready.PublicIP)
+	require.Equal(t, int(ready.ID), ready.ProviderInstanceID)
+	require.Len(t, ready.VolumeIDs, 1)
+
+	require.Equal(t, models.InstanceStatusTerminated, terminated.Status)
+	require.Len(t, mockClient.RemovedContainers, 1)
+	require.Empty(t, mockClient.Volumes())
+}
+
+// blockingProvider is a compute provider whose instance creation creates the instance and then
+// blocks until it is cancelled
+type blockingProvider struct {
+	compute.Provider
+	providerInstanceID int
+	created            chan struct{}
+	deleted            []int
+}
+
+func (p *blockingProvider) CreateInstance(ctx context.Context, req *types.InstanceRequest) error {
+	req.SetProviderInstanceID(p.providerInstanceID)
+	close(p.created)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func (p *blockingProvider) DeleteInstance(_ context.Context, providerInstanceID int) error {
+	p.deleted = append(p.deleted, providerInstanceID)
+	return nil
+}
+
+func TestWorker_processTask_Terminated(t *testing.T) {
+	tests := []struct {
+		name      string
+		terminate func(ts *TestSetup, task *models.Task) error
+	}{
+		{
+			name: "terminated through this server",
+			terminate: func(ts *TestSetup, task *models.Task) error {
+				return ts.TaskService.Terminate(ts.ctx, task.OwnerID, task.ID)
+			},
+		},
+		{
+			name: "terminated through another server",
+			terminate: func(ts *TestSetup, task *models.Task) error {
+				// Only the status is updated, the worker has to notice it
+				_, err := ts.TaskRepo.Terminate(ts.ctx, task.OwnerID, task.ID)
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := NewTestSetup(t)
+			defer ts.CleanUp()
+
+			req := types.InstanceRequest{
+				OwnerID: 1, ProjectName: "test-project-terminate", Provider: models.ProviderDO,
+				Region: "nyc1", Size: "s-1vcpu-1gb", Image: "ubuntu-22-04-x64",
+				NumberOfInstances: 1, Action: "create",
+				Volumes: []types.VolumeConfig{{Name: "data", SizeGB: 10, MountPoint: "/mnt/data"}},
+			}
+			require.NoError(t, ts.ProjectRepo.Create(ts.ctx, &models.Project{OwnerID: req.OwnerID, Name: req.ProjectName}))
+
+			provider := &blockingProvider{providerInstanceID: 4242, created: make(chan struct{})}
+			w := NewWorkerPool(ts.InstanceService, ts.ProjectService, ts.TaskService, nil, nil, time.Millisecond*10).
+				WithTerminationPollInterval(10 * time.Millisecond)
+			ts.InstanceService.providers.Set(req.Provider, provider)
+
+			created, err := ts.InstanceService.CreateInstance(ts.ctx, []types.InstanceRequest{req})
+			require.NoError(t, err)
+			require.Len(t, created, 1)
+			instanceID := created[0].ID
+
+			tasks, err := ts.TaskService.ListTasksByInstanceID(ts.ctx, req.OwnerID, instanceID, models.TaskActionCreateInstances, nil)
+			require.NoError(t, err)
+			require.Len(t, tasks, 1)
+			task := tasks[0]
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				w.processTask(ts.ctx, 1, &task)
+			}()
+
+			select {
+			case <-provider.created:
+			case <-time.After(5 * time.Second):
+				t.Fatal("instance creation did not start")
+			}
+			require.NoError(t, tt.terminate(ts, &task))
+
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("task processing was not cancelled")
+			}
+
+			// The half-created instance is deleted from the provider
+			require.Equal(t, []int{provider.providerInstanceID}, provider.deleted)
+
+			instance, err := ts.InstanceService.Get(ts.ctx, req.OwnerID, instanceID)
+			require.NoError(t, err)
+			require.Equal(t, models.InstanceStatusTerminated, instance.Status)
+
+			terminatedTask, err := ts.TaskService.Get(ts.ctx, req.OwnerID, task.ID)
+			require.NoError(t, err)
+			require.Equal(t, models.TaskStatusTerminated, terminatedTask.Status)
+			require.NoError(t, ts.TaskService.DeriveLogs(ts.ctx, terminatedTask))
+			require.Contains(t, terminatedTask.Logs, "Task terminated while processing")
+			require.Contains(t, terminatedTask.Logs, "Rolled back the instance")
+			require.Nil(t, terminatedTask.LockedAt)
+
+			// A finished task cannot be terminated again
+			err = ts.TaskService.Terminate(ts.ctx, req.OwnerID, task.ID)
+			require.ErrorIs(t, err, ErrTaskFinished)
+		})
+	}
+}
+
+// failingProvider is a compute provider whose instance creation creates an instance and then fails
+type failingProvider struct {
+	compute.Provider
+	created []int
+	deleted []int
+}
+
+func (p *failingProvider) CreateInstance(_ context.Context, req *types.InstanceRequest) error {
+	req.SetProviderInstanceID(100 + len(p.created))
+	p.created = append(p.created, req.ProviderInstanceID)
+	return errors.New("provider is unavailable")
+}
+
+func (p *failingProvider) DeleteInstance(_ context.Context, providerInstanceID int) error {
+	p.deleted = append(p.deleted, providerInstanceID)
+	return nil
+}
+
+func TestWorker_processTask_Retry(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	req := types.InstanceRequest{
+		OwnerID: 1, ProjectName: "test-project-retry", Provider: models.ProviderDO,
+		Region: "nyc1", Size: "s-1vcpu-1gb", Image: "ubuntu-22-04-x64",
+		NumberOfInstances: 1, Action: "create",
+		Volumes: []types.VolumeConfig{{Name: "data", SizeGB: 10, MountPoint: "/mnt/data"}},
+	}
+	require.NoError(t, ts.ProjectRepo.Create(ts.ctx, &models.Project{OwnerID: req.OwnerID, Name: req.ProjectName}))
 
-		// Launch goroutines that repeatedly call getProvisioner
-		for i := 0; i < numGoroutines; i++ {
-			go func(stop <-chan struct{}) {
-				defer wg.Done()
-				for {
-					select {
-					case <-stop:
-						return // Stop signal received
-					default:
-						// Repeatedly get the provisioner to test concurrent map access
-						_, _ = w.getProvisioner(providerID)
-					}
+	provider := &failingProvider{}
+	w := NewWorkerPool(ts.InstanceService, ts.ProjectService, ts.TaskService, nil, nil, time.Millisecond*10).
+		WithRetryPolicy(models.TaskActionCreateInstances, RetryPolicy{MaxAttempts: 2, BaseDelay: time.Hour, MaxDelay: time.Hour})
+	ts.InstanceService.providers.Set(req.Provider, provider)
+
+	created, err := ts.InstanceService.CreateInstance(ts.ctx, []types.InstanceRequest{req})
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+	tasks, err := ts.TaskService.ListTasksByInstanceID(ts.ctx, req.OwnerID, created[0].ID, models.TaskActionCreateInstances, nil)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	taskID := tasks[0].ID
+
+	// The first failure schedules a retry after the backoff
+	w.processTask(ts.ctx, 1, &tasks[0])
+	task, err := ts.TaskService.Get(ts.ctx, req.OwnerID, taskID)
+	require.NoError(t, err)
+	require.Equal(t, models.TaskStatusPending, task.Status)
+	require.Equal(t, uint(1), task.Attempts)
+	require.NotNil(t, task.NextRunAt)
+	require.WithinDuration(t, time.Now().Add(time.Hour), *task.NextRunAt, time.Minute)
+	require.Contains(t, task.Error, "provider is unavailable")
+	require.NotNil(t, task.Result)
+	require.Equal(t, 100, task.Result.ProviderInstanceID)
+	require.Len(t, task.Result.Steps, 1)
+	require.Equal(t, models.TaskStepCreate, task.Result.Steps[0].Step)
+	require.Contains(t, task.Result.Steps[0].Error, "provider is unavailable")
+
+	schedulable, err := ts.TaskService.GetSchedulableTasks(ts.ctx, task.Priority, 10, nil)
+	require.NoError(t, err)
+	require.Empty(t, schedulable)
+
+	// The last attempt deletes the instance left behind by the first one and marks the task dead
+	w.processTask(ts.ctx, 1, task)
+	require.Equal(t, []int{100}, provider.deleted)
+	task, err = ts.TaskService.Get(ts.ctx, req.OwnerID, taskID)
+	require.NoError(t, err)
+	require.Equal(t, models.TaskStatusDead, task.Status)
+	require.Equal(t, uint(2), task.Attempts)
+
+	dead, err := ts.TaskService.ListDead(ts.ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	require.Equal(t, taskID, dead[0].ID)
+
+	// A requeued task starts over with fresh attempts
+	require.NoError(t, ts.TaskService.Requeue(ts.ctx, taskID))
+	task, err = ts.TaskService.Get(ts.ctx, req.OwnerID, taskID)
+	require.NoError(t, err)
+	require.Equal(t, models.TaskStatusPending, task.Status)
+	require.Zero(t, task.Attempts)
+	require.Nil(t, task.NextRunAt)
+	require.ErrorIs(t, ts.TaskService.Requeue(ts.ctx, taskID), ErrTaskNotDead)
+
+	// Permanent errors fail the task without retrying
+	task.Payload = []byte("not json")
+	w.processTask(ts.ctx, 1, task)
+	task, err = ts.TaskService.Get(ts.ctx, req.OwnerID, taskID)
+	require.NoError(t, err)
+	require.Equal(t, models.TaskStatusFailed, task.Status)
+}
+
+func TestWorker_processTask_Timeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeouts TaskTimeouts
+		wantErr  string
+	}{
+		{
+			name:     "step timeout",
+			timeouts: TaskTimeouts{Task: time.Minute, Steps: map[models.TaskStep]time.Duration{models.TaskStepCreate: 50 * time.Millisecond}},
+			wantErr:  "task timed out: create step exceeded 50ms",
+		},
+		{
+			name:     "task timeout",
+			timeouts: TaskTimeouts{Task: 50 * time.Millisecond},
+			wantErr:  "task timed out: create_instances task exceeded 50ms",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := NewTestSetup(t)
+			defer ts.CleanUp()
+
+			req := types.InstanceRequest{
+				OwnerID: 1, ProjectName: "test-project-timeout", Provider: models.ProviderDO,
+				Region: "nyc1", Size: "s-1vcpu-1gb", Image: "ubuntu-22-04-x64",
+				NumberOfInstances: 1, Action: "create",
+				Volumes: []types.VolumeConfig{{Name: "data", SizeGB: 10, MountPoint: "/mnt/data"}},
+			}
+			require.NoError(t, ts.ProjectRepo.Create(ts.ctx, &models.Project{OwnerID: req.OwnerID, Name: req.ProjectName}))
+
+			provider := &blockingProvider{providerInstanceID: 4242, created: make(chan struct{})}
+			w := NewWorkerPool(ts.InstanceService, ts.ProjectService, ts.TaskService, nil, nil, time.Millisecond*10).
+				WithTaskTimeouts(models.TaskActionCreateInstances, tt.timeouts)
+			ts.InstanceService.providers.Set(req.Provider, provider)
+
+			created, err := ts.InstanceService.CreateInstance(ts.ctx, []types.InstanceRequest{req})
+			require.NoError(t, err)
+			require.Len(t, created, 1)
+			tasks, err := ts.TaskService.ListTasksByInstanceID(ts.ctx, req.OwnerID, created[0].ID, models.TaskActionCreateInstances, nil)
+			require.NoError(t, err)
+			require.Len(t, tasks, 1)
+
+			// The timeout fails the task without a retry
+			w.processTask(ts.ctx, 1, &tasks[0])
+			task, err := ts.TaskService.Get(ts.ctx, req.OwnerID, tasks[0].ID)
+			require.NoError(t, err)
+			require.Equal(t, models.TaskStatusFailed, task.Status)
+			require.Contains(t, task.Error, tt.wantErr)
+			require.NotNil(t, task.Result)
+			require.Len(t, task.Result.Steps, 1)
+			require.Contains(t, task.Result.Steps[0].Error, tt.wantErr)
+
+			// The instance failed and keeps its provider instance, so that it can be terminated
+			require.Empty(t, provider.deleted)
+			instance, err := ts.InstanceService.Get(ts.ctx, req.OwnerID, created[0].ID)
+			require.NoError(t, err)
+			require.Equal(t, models.InstanceStatusFailed, instance.Status)
+			require.Equal(t, provider.providerInstanceID, instance.ProviderInstanceID)
+		})
+	}
+}
+
+// countingProvider is a compute provider that records the instances it creates and deletes.
+// It returns crash, if set, once an instance exists and before it is ready.
+type countingProvider struct {
+	compute.Provider
+	created []int
+	deleted []int
+	crash   error
+}
+
+func (p *countingProvider) CreateInstance(_ context.Context, req *types.InstanceRequest) error {
+	req.SetProviderInstanceID(200 + len(p.created))
+	p.created = append(p.created, req.ProviderInstanceID)
+	if p.crash != nil {
+		return p.crash
+	}
+	req.PublicIP = "192.0.2." + strconv.Itoa(len(p.created))
+	req.VolumeIDs = []string{"volume-" + strconv.Itoa(req.ProviderInstanceID)}
+	return nil
+}
+
+func (p *countingProvider) DeleteInstance(_ context.Context, providerInstanceID int) error {
+	p.deleted = append(p.deleted, providerInstanceID)
+	return nil
+}
+
+// countingProvisioner is a provisioner that records the hosts it provisions instead of provisioning them
+type countingProvisioner struct {
+	compute.Provisioner
+	hosts []string
+}
+
+func (p *countingProvisioner) Provision(_ context.Context, instance *types.InstanceRequest, _ compute.ProvisionOptions) (*compute.ProvisionResult, error) {
+	p.hosts = append(p.hosts, instance.PublicIP)
+	return &compute.ProvisionResult{}, nil
+}
+
+func (p *countingProvisioner) Close() error {
+	return nil
+}
+
+func TestWorker_processCreateInstanceTask_Checkpoints(t *testing.T) {
+	errCrash := errors.New("worker crashed")
+
+	tests := []struct {
+		name string
+		// The worker stops inside CreateInstance, once the instance exists and before it is ready
+		crashInCreate bool
+		// The worker stops right before or after the step is checkpointed
+		crashBefore, crashAfter models.TaskStep
+		wantCheckpoint          models.TaskStep
+		// The instance the task ends up with
+		wantProviderInstanceID int
+		wantIP                 string
+	}{
+		{
+			name:                   "inside create",
+			crashInCreate:          true,
+			wantProviderInstanceID: 201,
+			wantIP:                 "192.0.2.2",
+		},
+		{
+			name:                   "before create checkpoint",
+			crashBefore:            models.TaskStepCreate,
+			wantProviderInstanceID: 201,
+			wantIP:                 "192.0.2.2",
+		},
+		{
+			name:                   "after create checkpoint",
+			crashAfter:             models.TaskStepCreate,
+			wantCheckpoint:         models.TaskStepCreate,
+			wantProviderInstanceID: 200,
+			wantIP:                 "192.0.2.1",
+		},
+		{
+			name:                   "after provision checkpoint",
+			crashAfter:             models.TaskStepProvision,
+			wantCheckpoint:         models.TaskStepProvision,
+			wantProviderInstanceID: 200,
+			wantIP:                 "192.0.2.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := NewTestSetup(t)
+			defer ts.CleanUp()
+
+			req := types.InstanceRequest{
+				OwnerID: 1, ProjectName: "test-project-checkpoint", Provider: models.ProviderDO,
+				Region: "nyc1", Size: "s-1vcpu-1gb", Image: "ubuntu-22-04-x64",
+				NumberOfInstances: 1, Action: "create", Provision: true,
+				Volumes: []types.VolumeConfig{{Name: "data", SizeGB: 10, MountPoint: "/mnt/data"}},
+			}
+			require.NoError(t, ts.ProjectRepo.Create(ts.ctx, &models.Project{OwnerID: req.OwnerID, Name: req.ProjectName}))
+
+			provider := &countingProvider{}
+			provisioner := &countingProvisioner{}
+			w := NewWorkerPool(ts.InstanceService, ts.ProjectService, ts.TaskService, nil, nil, time.Millisecond*10)
+			ts.InstanceService.providers.Set(req.Provider, provider)
+			w.newProvisioner = func(models.ProvisionerID, string) (compute.Provisioner, error) { return provisioner, nil }
+
+			created, err := ts.InstanceService.CreateInstance(ts.ctx, []types.InstanceRequest{req})
+			require.NoError(t, err)
+			require.Len(t, created, 1)
+			tasks, err := ts.TaskService.ListTasksByInstanceID(ts.ctx, req.OwnerID, created[0].ID, models.TaskActionCreateInstances, nil)
+			require.NoError(t, err)
+			require.Len(t, tasks, 1)
+
+			if tt.crashInCreate {
+				provider.crash = errCrash
+			}
+			w.beforeCheckpoint = func(step models.TaskStep) error {
+				if step == tt.crashBefore {
+					return errCrash
+				}
+				return nil
+			}
+			w.afterCheckpoint = func(step models.TaskStep) error {
+				if step == tt.crashAfter {
+					return errCrash
 				}
-			}(done) // Pass the done channel to the goroutine
+				return nil
+			}
+			err = w.processCreateInstanceTask(ts.ctx, &tasks[0], &models.TaskResult{})
+			require.ErrorIs(t, err, errCrash)
+
+			// The provider instance was recorded as soon as it existed
+			instance, err := ts.InstanceService.Get(ts.ctx, req.OwnerID, created[0].ID)
+			require.NoError(t, err)
+			require.Equal(t, 200, instance.ProviderInstanceID)
+
+			// Another worker picks up the task as stored in the database
+			provider.crash = nil
+			w.beforeCheckpoint = nil
+			w.afterCheckpoint = nil
+			task, err := ts.TaskService.Get(ts.ctx, req.OwnerID, tasks[0].ID)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantCheckpoint, task.Checkpoint)
+			require.NoError(t, w.processCreateInstanceTask(ts.ctx, task, &models.TaskResult{}))
+
+			// An instance created before the create checkpoint is deleted before another one is
+			// created, an instance that was checkpointed is kept. Either way the provider is left
+			// with the single instance of the task.
+			remaining := []int{}
+			for _, id := range provider.created {
+				if !slices.Contains(provider.deleted, id) {
+					remaining = append(remaining, id)
+				}
+			}
+			require.Equal(t, []int{tt.wantProviderInstanceID}, remaining)
+			require.Equal(t, []string{tt.wantIP}, provisioner.hosts)
+
+			instance, err = ts.InstanceService.Get(ts.ctx, req.OwnerID, created[0].ID)
+			require.NoError(t, err)
+			require.Equal(t, models.InstanceStatusReady, instance.Status)
+			require.Equal(t, tt.wantProviderInstanceID, instance.ProviderInstanceID)
+			require.Equal(t, tt.wantIP, instance.PublicIP)
+			require.Equal(t, []string{"volume-" + strconv.Itoa(tt.wantProviderInstanceID)}, []string(instance.VolumeIDs))
+
+			task, err = ts.TaskService.Get(ts.ctx, req.OwnerID, tasks[0].ID)
+			require.NoError(t, err)
+			require.Equal(t, models.TaskStepProvision, task.Checkpoint)
+		})
+	}
+}
+
+func TestWorker_processTask_LeaseLost(t *testing.T) {
+	tests := []struct {
+		name       string
+		takeOver   func(ts *TestSetup, task *models.Task) error
+		wantStatus models.TaskStatus
+		wantWorker string
+	}{
+		{
+			name: "lock taken over by another worker",
+			takeOver: func(ts *TestSetup, task *models.Task) error {
+				// The lock expired, e.g. because the database was unreachable, and another
+				// worker picked up the task
+				expired := time.Now().Add(-time.Second)
+				if err := ts.DB.Model(task).Update(models.TaskLockExpiryField, expired).Error; err != nil {
+					return err
+				}
+				return ts.TaskService.AcquireTaskLock(ts.ctx, task.ID, "other-worker")
+			},
+			wantStatus: models.TaskStatusRunning,
+			wantWorker: "other-worker",
+		},
+		{
+			name: "task recovered from a stopped worker",
+			takeOver: func(ts *TestSetup, task *models.Task) error {
+				// The worker has not been registered in the fleet, so it is considered stopped
+				_, err := ts.TaskService.RecoverStaleTasks(ts.ctx, time.Now())
+				return err
+			},
+			wantStatus: models.TaskStatusPending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := NewTestSetup(t)
+			defer ts.CleanUp()
+
+			req := types.InstanceRequest{
+				OwnerID: 1, ProjectName: "test-project-lease", Provider: models.ProviderDO,
+				Region: "nyc1", Size: "s-1vcpu-1gb", Image: "ubuntu-22-04-x64",
+				NumberOfInstances: 1, Action: "create",
+				Volumes: []types.VolumeConfig{{Name: "data", SizeGB: 10, MountPoint: "/mnt/data"}},
+			}
+			require.NoError(t, ts.ProjectRepo.Create(ts.ctx, &models.Project{OwnerID: req.OwnerID, Name: req.ProjectName}))
+
+			provider := &blockingProvider{providerInstanceID: 4242, created: make(chan struct{})}
+			w := NewWorkerPool(ts.InstanceService, ts.ProjectService, ts.TaskService, nil, nil, time.Millisecond*10).
+				WithLeaseRenewInterval(10 * time.Millisecond)
+			ts.InstanceService.providers.Set(req.Provider, provider)
+
+			created, err := ts.InstanceService.CreateInstance(ts.ctx, []types.InstanceRequest{req})
+			require.NoError(t, err)
+			require.Len(t, created, 1)
+			tasks, err := ts.TaskService.ListTasksByInstanceID(ts.ctx, req.OwnerID, created[0].ID, models.TaskActionCreateInstances, nil)
+			require.NoError(t, err)
+			require.Len(t, tasks, 1)
+			task := tasks[0]
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				w.processTask(ts.ctx, 1, &task)
+			}()
+
+			select {
+			case <-provider.created:
+			case <-time.After(5 * time.Second):
+				t.Fatal("instance creation did not start")
+			}
+
+			// While the worker is processing the task its lease is renewed
+			time.Sleep(50 * time.Millisecond)
+			locked, err := ts.TaskService.Get(ts.ctx, req.OwnerID, task.ID)
+			require.NoError(t, err)
+			require.Equal(t, w.WorkerID(), locked.WorkerID)
+			require.WithinDuration(t, time.Now().Add(models.TaskLockTimeout), *locked.LockExpiry, time.Second)
+
+			require.NoError(t, tt.takeOver(ts, &task))
+
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("task processing was not aborted")
+			}
+
+			// The worker neither records an outcome nor releases the lock of the new holder
+			aborted, err := ts.TaskService.Get(ts.ctx, req.OwnerID, task.ID)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantStatus, aborted.Status)
+			require.Equal(t, tt.wantWorker, aborted.WorkerID)
+			require.Equal(t, tt.wantWorker != "", aborted.LockedAt != nil)
+			require.Empty(t, aborted.Error)
+			require.Equal(t, uint(1), aborted.Attempts)
+			require.Empty(t, provider.deleted)
+		})
+	}
+}
+
+func TestWorker_taskDispatcher_Notifications(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	notifications := make(chan string, 1)
+	w := NewWorkerPool(ts.InstanceService, ts.ProjectService, ts.TaskService, nil, nil, time.Millisecond*10).
+		WithTaskNotifications(notifications, time.Hour)
+
+	ctx, cancel := context.WithCancel(ts.ctx)
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go w.taskDispatcher(ctx, &wg, models.TaskPriorityHigh, 10)
+	go w.forwardTaskNotifications(ctx, &wg)
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	// Let the first poll find nothing, the next one is only due in an hour
+	time.Sleep(1200 * time.Millisecond)
+
+	createTask := func() *models.Task {
+		task := &models.Task{
+			OwnerID: 1, ProjectID: 1, Action: models.TaskActionCreateInstances,
+			Status: models.TaskStatusPending, Priority: models.TaskPriorityHigh,
+		}
+		require.NoError(t, ts.TaskRepo.Create(ts.ctx, task))
+		return task
+	}
+	expectQueued := func(taskID uint) {
+		select {
+		case queued := <-w.highPriorityQueue:
+			require.Equal(t, taskID, queued.ID)
+		case <-time.After(time.Second):
+			t.Fatalf("task %d was not dispatched", taskID)
 		}
+	}
 
-		wg.Wait() // Wait for all goroutines to finish
-	})
+	// A notification for the priority dispatches the task right away
+	task := createTask()
+	notifications <- strconv.Itoa(int(models.TaskPriorityHigh))
+	expectQueued(task.ID)
+
+	// Without a notification the task waits for the next poll
+	require.NoError(t, ts.TaskRepo.UpdateStatus(ts.ctx, task.OwnerID, task.ID, models.TaskStatusCompleted))
+	task = createTask()
+	select {
+	case queued := <-w.highPriorityQueue:
+		t.Fatalf("task %d was dispatched without a notification", queued.ID)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// An empty notification, sent after reconnecting, wakes up all dispatchers
+	notifications <- ""
+	expectQueued(task.ID)
+}
+
+func TestWorker_taskDispatcher_DueRetry(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	// Notifications never come, the poll interval is too long for the test
+	w := NewWorkerPool(ts.InstanceService, ts.ProjectService, ts.TaskService, nil, nil, time.Millisecond*10).
+		WithTaskNotifications(make(chan string), time.Hour)
+
+	nextRunAt := time.Now().Add(1500 * time.Millisecond)
+	task := &models.Task{
+		OwnerID: 1, ProjectID: 1, Action: models.TaskActionCreateInstances,
+		Status: models.TaskStatusPending, Priority: models.TaskPriorityHigh, Attempts: 1, NextRunAt: &nextRunAt,
+	}
+	require.NoError(t, ts.TaskRepo.Create(ts.ctx, task))
+	require.InDelta(t, time.Until(nextRunAt), w.nextPollDelay(ts.ctx, models.TaskPriorityHigh, time.Hour), float64(100*time.Millisecond))
+	require.Equal(t, time.Second, w.nextPollDelay(ts.ctx, models.TaskPriorityHigh, time.Second))
+	require.Equal(t, time.Hour, w.nextPollDelay(ts.ctx, models.TaskPriorityLow, time.Hour))
+
+	ctx, cancel := context.WithCancel(ts.ctx)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go w.taskDispatcher(ctx, &wg, models.TaskPriorityHigh, 10)
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	// The first poll is too early, the dispatcher polls again when the retry is due
+	select {
+	case queued := <-w.highPriorityQueue:
+		require.Equal(t, task.ID, queued.ID)
+		require.False(t, time.Now().Before(nextRunAt), "the retry was dispatched before it was due")
+	case <-time.After(3 * time.Second):
+		t.Fatal("the retry was not dispatched when it came due")
+	}
+}
+
+func TestWorker_dispatchableTasks_ConcurrencyLimits(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	w := NewWorkerPool(ts.InstanceService, ts.ProjectService, ts.TaskService, nil, nil, time.Millisecond*10).
+		WithConcurrencyLimits(ConcurrencyLimits{
+			Providers: map[models.ProviderID]int{models.ProviderDO: 3},
+			PerOwner:  2,
+		})
+
+	createTask := func(ownerID uint, provider models.ProviderID) *models.Task {
+		task := &models.Task{
+			OwnerID: ownerID, ProjectID: ownerID, ProviderID: provider, Action: models.TaskActionCreateInstances,
+			Status: models.TaskStatusPending, Priority: models.TaskPriorityHigh,
+		}
+		require.NoError(t, ts.TaskRepo.Create(ts.ctx, task))
+		return task
+	}
+	dispatch := func() []uint {
+		tasks, err := w.dispatchableTasks(ts.ctx, models.TaskPriorityHigh, 10)
+		require.NoError(t, err)
+		ids := make([]uint, 0, len(tasks))
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+
+	// Owner 1 queued many tasks, owners 2 and 3 a few
+	var owner1 []*models.Task
+	for i := 0; i < 4; i++ {
+		owner1 = append(owner1, createTask(1, models.ProviderDO))
+	}
+	owner2 := []*models.Task{createTask(2, models.ProviderDO), createTask(2, models.ProviderDO)}
+	owner3 := createTask(3, models.ProviderLinode)
+
+	// Owners take turns until DigitalOcean is at its limit, Linode is not capped
+	require.Equal(t, []uint{owner1[0].ID, owner2[0].ID, owner3.ID, owner1[1].ID}, dispatch())
+	require.True(t, w.throttled.Load())
+
+	// Dispatched tasks are not dispatched again and nothing else fits
+	require.Empty(t, dispatch())
+
+	// A finished task frees its slot and wakes up the dispatchers
+	finish := func(task *models.Task) {
+		require.NoError(t, ts.TaskRepo.UpdateStatus(ts.ctx, task.OwnerID, task.ID, models.TaskStatusCompleted))
+		w.finishDispatch(task)
+	}
+	finish(owner1[0])
+	require.Len(t, w.wakeups[models.TaskPriorityHigh], 1)
+	require.False(t, w.throttled.Load())
+	require.Equal(t, []uint{owner1[2].ID}, dispatch())
+
+	// Tasks running on other workers count towards the limits
+	for _, task := range []*models.Task{owner1[1], owner1[2], owner2[0], owner3} {
+		finish(task)
+	}
+	for i := 0; i < 2; i++ {
+		running := createTask(4, models.ProviderDO)
+		require.NoError(t, ts.TaskService.AcquireTaskLock(ts.ctx, running.ID, "other-worker"))
+	}
+	require.Equal(t, []uint{owner1[3].ID}, dispatch())
+}
+
+func TestWorker_LaunchWorkerPool_Fleet(t *testing.T) {
+	ts := NewTestSetup(t)
+	defer ts.CleanUp()
+
+	fleet := NewFleetService(repos.NewWorkerRepository(ts.DB))
+	w := NewWorkerPool(ts.InstanceService, ts.ProjectService, ts.TaskService, nil, nil, time.Hour).
+		WithWorkerCount(1).
+		WithFleet(fleet)
+	w.heartbeatInterval = 20 * time.Millisecond
+
+	// Another worker crashed while it was processing a task and a third one is still running its task
+	now := time.Now()
+	lockExpiry := now.Add(time.Hour)
+	require.NoError(t, fleet.Heartbeat(ts.ctx, &models.Worker{ID: "crashed"}))
+	require.NoError(t, ts.DB.Model(&models.Worker{ID: "crashed"}).
+		Update(models.WorkerHeartbeatAtField, now.Add(-time.Hour)).Error)
+	require.NoError(t, fleet.Heartbeat(ts.ctx, &models.Worker{ID: "running"}))
+
+	runningTask := func(workerID string) *models.Task {
+		task := &models.Task{
+			OwnerID: 1, ProjectID: 1, Action: models.TaskActionCreateInstances,
+			Status: models.TaskStatusRunning, Priority: models.TaskPriorityLow,
+			WorkerID: workerID, LockedAt: &now, LockExpiry: &lockExpiry,
+		}
+		require.NoError(t, ts.TaskRepo.Create(ts.ctx, task))
+		return task
+	}
+	crashedTask := runningTask("crashed")
+	keptTask := runningTask("running")
+
+	ctx, cancel := context.WithCancel(ts.ctx)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go w.LaunchWorkerPool(ctx, &wg)
+
+	// The pool registers itself and reclaims the task of the crashed worker, once that worker's
+	// heartbeat expired it is removed from the fleet
+	require.Eventually(t, func() bool {
+		workers, err := fleet.List(ts.ctx)
+		require.NoError(t, err)
+		ids := make([]string, 0, len(workers))
+		for _, worker := range workers {
+			ids = append(ids, worker.ID)
+		}
+		return len(ids) == 2 && slices.Contains(ids, w.WorkerID()) && slices.Contains(ids, "running")
+	}, 5*time.Second, 10*time.Millisecond)
+
+	task, err := ts.TaskService.Get(ts.ctx, crashedTask.OwnerID, crashedTask.ID)
+	require.NoError(t, err)
+	require.NotEqual(t, models.TaskStatusRunning, task.Status)
+	require.NotEqual(t, "crashed", task.WorkerID)
+
+	task, err = ts.TaskService.Get(ts.ctx, keptTask.OwnerID, keptTask.ID)
+	require.NoError(t, err)
+	require.Equal(t, models.TaskStatusRunning, task.Status)
+	require.Equal(t, "running", task.WorkerID)
+
+	// The pool deregisters on shutdown
+	cancel()
+	wg.Wait()
+	workers, err := fleet.List(ts.ctx)
+	require.NoError(t, err)
+	require.Len(t, workers, 1)
+	require.Equal(t, "running", workers[0].ID)
 }
@@ -0,0 +1,58 @@
+package types
+
+import (
+	"context"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+)
+
+// CatalogKind is the kind of values listed in a provider catalog
+type CatalogKind string
+
+// Catalog kind constants
+const (
+	// CatalogRegions lists the values accepted as InstanceRequest.Region
+	CatalogRegions CatalogKind = "regions"
+	// CatalogSizes lists the values accepted as InstanceRequest.Size
+	CatalogSizes CatalogKind = "sizes"
+	// CatalogImages lists the values accepted as InstanceRequest.Image
+	CatalogImages CatalogKind = "images"
+)
+
+// IsValid reports whether the catalog kind is known
+func (k CatalogKind) IsValid() bool {
+	switch k {
+	case CatalogRegions, CatalogSizes, CatalogImages:
+		return true
+	default:
+		return false
+	}
+}
+
+// ProviderRegion is a region offered by a compute provider
+type ProviderRegion struct {
+	ID   string `json:"id"`   // Value to use as the instance region
+	Name string `json:"name"` // Human readable name or location
+}
+
+// ProviderSize is an instance size, plan or type offered by a compute provider
+type ProviderSize struct {
+	ID       string   `json:"id"`                // Value to use as the instance size
+	CPU      int      `json:"cpu"`               // Number of virtual CPUs
+	MemoryMB int      `json:"memory_mb"`         // Memory in megabytes
+	DiskGB   int      `json:"disk_gb"`           // Size of the built-in disk in gigabytes, 0 if the size has no disk
+	Regions  []string `json:"regions,omitempty"` // Regions the size is available in, empty if the provider does not restrict it
+}
+
+// ProviderImage is an operating system image offered by a compute provider
+type ProviderImage struct {
+	ID           string `json:"id"`                     // Value to use as the instance image
+	Name         string `json:"name"`                   // Human readable name
+	Distribution string `json:"distribution,omitempty"` // Operating system distribution, if reported
+}
+
+// CatalogValidator checks the region, size and image of an instance request against the
+// catalog of its provider
+type CatalogValidator interface {
+	ValidateCatalogValues(ctx context.Context, provider models.ProviderID, region, size, image string) error
+}
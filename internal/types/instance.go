@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/celestiaorg/talis/internal/db/models"
 	"github.com/celestiaorg/talis/internal/validation"
@@ -34,13 +35,19 @@ type InstanceRequest struct {
 	VolumeDetails []VolumeDetails `json:"volume_details,omitempty"` // Detailed information about attached volumes
 
 	// User Defined Configs
-	ProjectName       string         `json:"project_name"`
-	Name              string         `json:"name,omitempty"`            // Optional name for the instance(s). If multiple instances, will be suffixed with index
-	NumberOfInstances int            `json:"number_of_instances"`       // Number of instances to create
-	Provision         bool           `json:"provision"`                 // Whether to run Ansible provisioning
-	PayloadPath       string         `json:"payload_path,omitempty"`    // Local path to the payload script on the API server
-	ExecutePayload    bool           `json:"execute_payload,omitempty"` // Whether to execute the payload after copying
-	Volumes           []VolumeConfig `json:"volumes"`                   // Optional volumes to attach
+	ProjectName       string               `json:"project_name"`
+	Name              string               `json:"name,omitempty"`            // Optional name for the instance(s). If multiple instances, will be suffixed with index
+	NumberOfInstances int                  `json:"number_of_instances"`       // Number of instances to create
+	Provision         bool                 `json:"provision"`                 // Whether to provision the instances
+	Provisioner       models.ProvisionerID `json:"provisioner,omitempty"`     // How to provision the instances, "ansible" (default) or "ssh"
+	PayloadPath       string               `json:"payload_path,omitempty"`    // Local path to the payload script on the API server
+	ExecutePayload    bool                 `json:"execute_payload,omitempty"` // Whether to execute the payload after copying
+	Volumes           []VolumeConfig       `json:"volumes"`                   // Optional volumes to attach
+
+	// Playbook Configs - Optional, require the ansible provisioner
+	Playbook     string                 `json:"playbook,omitempty"`      // Name of a playbook registered on the server or uploaded by the owner, run once the instances are set up
+	PlaybookTags []string               `json:"playbook_tags,omitempty"` // Tags of the playbook to run, all of its tasks if empty
+	ExtraVars    map[string]interface{} `json:"extra_vars,omitempty"`    // Extra variables passed to the playbook
 
 	// Internal Configs - Used during processing
 	InstanceIndex int `json:"instance_index,omitempty"` // Index of this instance when creating multiple instances
@@ -53,6 +60,10 @@ type InstanceRequest struct {
 	Action             string `json:"action"`
 	ProviderInstanceID int    `json:"provider_instance_id"` // Provider-specific instance ID
 	LastTaskID         uint   `json:"last_task_id"`         // ID of the last task
+
+	// OnProviderInstanceCreated is called by SetProviderInstanceID, if set, so that the instance
+	// is recorded before the provider waits for it to be ready
+	OnProviderInstanceCreated func(providerInstanceID int) `json:"-"`
 }
 
 // DeleteInstanceRequest represents the request body for deleting a single instance
@@ -69,6 +80,54 @@ type DeleteInstancesRequest struct {
 	InstanceIDs []uint `json:"instance_ids" validate:"required,min=1"` // Instances to delete
 }
 
+// ImportInstancesRequest represents the request body for importing instances that were created outside of Talis
+// swagger:model
+// Example: {"owner_id":1,"project_name":"my-testnet","provider":"do","provider_instance_ids":["412345678","412345679"]}
+type ImportInstancesRequest struct {
+	OwnerID             uint              `json:"owner_id"`                        // Owner ID
+	ProjectName         string            `json:"project_name"`                    // Project the instances are added to
+	Provider            models.ProviderID `json:"provider"`                        // Cloud provider of the instances
+	ProviderInstanceIDs []string          `json:"provider_instance_ids,omitempty"` // Provider-native IDs of the instances to import
+	Tag                 string            `json:"tag,omitempty"`                   // Import all instances carrying this tag instead
+}
+
+// Validate validates the import request
+func (r *ImportInstancesRequest) Validate() error {
+	if r.ProjectName == "" {
+		return fmt.Errorf("project_name is required")
+	}
+	if r.OwnerID == 0 {
+		return fmt.Errorf("owner_id is required")
+	}
+	if r.Provider == "" {
+		return fmt.Errorf("provider is required")
+	}
+	if !r.Provider.IsValid() {
+		return fmt.Errorf("unsupported provider: %s", r.Provider)
+	}
+	if len(r.ProviderInstanceIDs) == 0 && r.Tag == "" {
+		return fmt.Errorf("either provider_instance_ids or tag is required")
+	}
+	if len(r.ProviderInstanceIDs) > 0 && r.Tag != "" {
+		return fmt.Errorf("provider_instance_ids and tag are mutually exclusive")
+	}
+	for i, id := range r.ProviderInstanceIDs {
+		if id == "" {
+			return fmt.Errorf("provider_instance_ids[%d] is empty", i)
+		}
+	}
+	return nil
+}
+
+// SetProviderInstanceID is called by compute providers with the ID of the instance as soon as
+// it exists on the provider, before they wait for it to be ready
+func (i *InstanceRequest) SetProviderInstanceID(providerInstanceID int) {
+	i.ProviderInstanceID = providerInstanceID
+	if i.OnProviderInstanceCreated != nil {
+		i.OnProviderInstanceCreated(providerInstanceID)
+	}
+}
+
 // GetMemory returns the memory value for validation
 func (i *InstanceRequest) GetMemory() int {
 	return i.Memory
@@ -161,11 +220,19 @@ func (i *InstanceRequest) Validate() error {
 		return fmt.Errorf("payload_path is required when execute_payload is true")
 	}
 
+	if !i.Provisioner.IsValid() {
+		return fmt.Errorf("unsupported provisioner: %s", i.Provisioner)
+	}
+
 	// If payload_path is provided, provision must be true
 	if i.PayloadPath != "" && !i.Provision {
 		return fmt.Errorf("provision must be true when payload_path is provided")
 	}
 
+	if err := i.validatePlaybook(); err != nil {
+		return err
+	}
+
 	// Confirm an action is provided
 	if i.Action == "" {
 		return fmt.Errorf("action is required")
@@ -173,3 +240,34 @@ func (i *InstanceRequest) Validate() error {
 
 	return nil
 }
+
+// validatePlaybook validates the playbook configs. Whether the playbook exists is checked by
+// the instance service.
+func (i *InstanceRequest) validatePlaybook() error {
+	if i.Playbook == "" {
+		if len(i.PlaybookTags) > 0 || len(i.ExtraVars) > 0 {
+			return fmt.Errorf("playbook is required when playbook_tags or extra_vars are provided")
+		}
+		return nil
+	}
+	if err := models.ValidatePlaybookName(i.Playbook); err != nil {
+		return err
+	}
+	if !i.Provision {
+		return fmt.Errorf("provision must be true when playbook is provided")
+	}
+	if i.Provisioner == models.ProvisionerSSH {
+		return fmt.Errorf("playbook requires the %s provisioner", models.ProvisionerAnsible)
+	}
+	for j, tag := range i.PlaybookTags {
+		if tag == "" || strings.ContainsAny(tag, ", \t\n") {
+			return fmt.Errorf("invalid playbook tag at index %d: %q", j, tag)
+		}
+	}
+	for name := range i.ExtraVars {
+		if models.IsReservedPlaybookVar(name) {
+			return fmt.Errorf("invalid extra variable %q: ansible_ variables configure Ansible and are not allowed", name)
+		}
+	}
+	return nil
+}
@@ -269,6 +269,90 @@ func TestInstanceRequest_Validate(t *testing.T) {
 			errMsg:  "provision must be true when payload_path is provided",
 		},
 
+		// --- Provisioner Validation ---
+		{
+			name:    "Error: unsupported provisioner",
+			request: func() InstanceRequest { r := baseReq; r.Provisioner = "chef"; return r }(),
+			wantErr: true,
+			errMsg:  "unsupported provisioner: chef",
+		},
+		{
+			name:    "Valid: SSH provisioner",
+			request: func() InstanceRequest { r := baseReq; r.Provisioner = models.ProvisionerSSH; return r }(),
+			wantErr: false,
+		},
+
+		// --- Playbook Validation ---
+		{
+			name: "Valid: playbook with tags and extra vars",
+			request: func() InstanceRequest {
+				r := baseReq
+				r.Playbook = "validator"
+				r.PlaybookTags = []string{"install", "configure"}
+				r.ExtraVars = map[string]interface{}{"chain_id": "mocha-4"}
+				return r
+			}(),
+			wantErr: false,
+		},
+		{
+			name:    "Error: invalid playbook name",
+			request: func() InstanceRequest { r := baseReq; r.Playbook = "../validator"; return r }(),
+			wantErr: true,
+			errMsg:  `invalid playbook name "../validator"`,
+		},
+		{
+			name: "Error: playbook with Provision=false",
+			request: func() InstanceRequest {
+				r := baseReq
+				r.Playbook = "validator"
+				r.PayloadPath = ""
+				r.Provision = false
+				return r
+			}(),
+			wantErr: true,
+			errMsg:  "provision must be true when playbook is provided",
+		},
+		{
+			name: "Error: playbook with SSH provisioner",
+			request: func() InstanceRequest {
+				r := baseReq
+				r.Playbook = "validator"
+				r.Provisioner = models.ProvisionerSSH
+				return r
+			}(),
+			wantErr: true,
+			errMsg:  "playbook requires the ansible provisioner",
+		},
+		{
+			name:    "Error: playbook tags without playbook",
+			request: func() InstanceRequest { r := baseReq; r.PlaybookTags = []string{"install"}; return r }(),
+			wantErr: true,
+			errMsg:  "playbook is required when playbook_tags or extra_vars are provided",
+		},
+		{
+			name: "Error: invalid playbook tag",
+			request: func() InstanceRequest {
+				r := baseReq
+				r.Playbook = "validator"
+				r.PlaybookTags = []string{"a,b"}
+				return r
+			}(),
+			wantErr: true,
+			errMsg:  `invalid playbook tag at index 0: "a,b"`,
+		},
+
+		{
+			name: "Error: reserved extra variable",
+			request: func() InstanceRequest {
+				r := baseReq
+				r.Playbook = "validator"
+				r.ExtraVars = map[string]interface{}{"ansible_connection": "local"}
+				return r
+			}(),
+			wantErr: true,
+			errMsg:  `invalid extra variable "ansible_connection"`,
+		},
+
 		// --- Action Validation ---
 		{
 			name:    "Error: missing action",
@@ -0,0 +1,50 @@
+package types
+
+import (
+	"errors"
+)
+
+// ErrProviderInstanceNotFound is returned by compute providers when an instance does not exist
+var ErrProviderInstanceNotFound = errors.New("instance not found on provider")
+
+// ProviderInstanceStatus is the state of an instance as reported by its compute provider,
+// normalized across providers
+type ProviderInstanceStatus string
+
+// Provider instance status constants
+const (
+	// ProviderInstanceStatusUnknown indicates the provider state could not be mapped
+	ProviderInstanceStatusUnknown ProviderInstanceStatus = "unknown"
+	// ProviderInstanceStatusPending indicates the instance is being created or started
+	ProviderInstanceStatusPending ProviderInstanceStatus = "pending"
+	// ProviderInstanceStatusRunning indicates the instance is powered on
+	ProviderInstanceStatusRunning ProviderInstanceStatus = "running"
+	// ProviderInstanceStatusStopped indicates the instance exists but is powered off
+	ProviderInstanceStatusStopped ProviderInstanceStatus = "stopped"
+	// ProviderInstanceStatusTerminated indicates the instance is being or has been destroyed
+	ProviderInstanceStatusTerminated ProviderInstanceStatus = "terminated"
+)
+
+// ProviderInstance describes an instance as seen by its compute provider
+type ProviderInstance struct {
+	ProviderInstanceID int                    `json:"provider_instance_id"` // ID stored as Instance.ProviderInstanceID, 0 if the instance was not created by Talis
+	NativeID           string                 `json:"native_id"`            // Provider's own identifier of the instance
+	Name               string                 `json:"name"`                 // Name or label of the instance
+	Status             ProviderInstanceStatus `json:"status"`               // Normalized provider state
+	PublicIP           string                 `json:"public_ip"`            // Public IPv4 address, empty if none is assigned
+	Region             string                 `json:"region"`               // Region the instance runs in
+	Size               string                 `json:"size"`                 // Size, plan or instance type
+	Image              string                 `json:"image"`                // Image the instance was created from
+	Tags               []string               `json:"tags,omitempty"`       // Tags or labels, "key=value" for providers with key/value labels
+	Volumes            []VolumeDetails        `json:"volumes,omitempty"`    // Attached volumes, only filled in by Provider.ImportInstance
+}
+
+// HasTag reports whether the instance carries the given tag
+func (i *ProviderInstance) HasTag(tag string) bool {
+	for _, t := range i.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -88,6 +88,39 @@ type TaskListResponse struct {
 	Pagination PaginationResponse `json:"pagination"`
 }
 
+// TaskEventListResponse represents a response containing a list of task events
+// swagger:model
+// Example: {"rows":[{"id":1,"task_id":1,"level":"info","step":"create","message":"Creating instance"}],"pagination":{"total":1,"page":1,"limit":10,"offset":0}}
+type TaskEventListResponse struct {
+	// Array of task event objects
+	Rows []interface{} `json:"rows"`
+
+	// Pagination information for the result set
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+// DriftEventListResponse represents a response containing a list of drift events
+// swagger:model
+// Example: {"rows":[{"instance_id":1,"type":"ip_changed","old_value":"192.0.2.1","new_value":"192.0.2.2"}],"pagination":{"total":1,"page":1,"limit":10,"offset":0}}
+type DriftEventListResponse struct {
+	// Array of drift event objects
+	Rows []interface{} `json:"rows"`
+
+	// Pagination information for the result set
+	Pagination PaginationResponse `json:"pagination"`
+}
+
+// WebhookDeliveryListResponse represents a response containing a list of webhook deliveries
+// swagger:model
+// Example: {"rows":[{"id":1,"event":"task.status_changed","task_id":1,"status":"delivered","attempts":1,"attempt_log":[{"status_code":200,"duration_ms":42}]}],"pagination":{"total":1,"page":1,"limit":10,"offset":0}}
+type WebhookDeliveryListResponse struct {
+	// Array of webhook delivery objects
+	Rows []interface{} `json:"rows"`
+
+	// Pagination information for the result set
+	Pagination PaginationResponse `json:"pagination"`
+}
+
 // ErrorResponse represents an error response
 // swagger:model
 // Example: {"error":"Invalid input parameter","details":{"field":"region","message":"Region is required"}}
@@ -20,6 +20,13 @@ type CreateUserResponse struct {
 	UserID uint `json:"id"`
 }
 
+// SetWebhookResponse represents the response after setting the webhook of a user
+type SetWebhookResponse struct {
+	WebhookURL string `json:"webhook_url"`
+	// WebhookSecret signs the webhook requests, it is only returned when it is generated
+	WebhookSecret string `json:"webhook_secret,omitempty"`
+}
+
 // UserResponse is a flexible response type for both single and multiple user scenarios
 type UserResponse struct {
 	// This can be a single user or null when returning multiple users
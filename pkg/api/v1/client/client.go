@@ -23,12 +23,15 @@
 package client
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	fiber "github.com/gofiber/fiber/v2"
@@ -43,6 +46,9 @@ import (
 // DefaultTimeout is the default timeout for API requests
 const DefaultTimeout = 30 * time.Second
 
+// maxEventSize is the largest task stream message the client accepts
+const maxEventSize = 1 << 20
+
 // Client is the interface for the Talis API client. It provides methods for interacting
 // with all aspects of the Talis API, organized into logical categories:
 // - Admin operations (for administrative access)
@@ -69,6 +75,20 @@ type Client interface {
 	// Returns a slice of Instance pointers containing only metadata fields and any error encountered.
 	AdminGetInstancesMetadata(ctx context.Context) ([]*models.Instance, error)
 
+	// AdminListDriftEvents retrieves the drift events of all instances, newest first.
+	// Drift events are recorded when an instance diverged from the state reported by its provider.
+	// Returns a slice of DriftEvent pointers and any error encountered.
+	AdminListDriftEvents(ctx context.Context, opts *models.ListOptions) ([]*models.DriftEvent, error)
+
+	// AdminListDeadTasks retrieves the tasks of all owners that used up their retries.
+	// Dead tasks are not processed again unless they are requeued.
+	// Returns a slice of Task pointers and any error encountered.
+	AdminListDeadTasks(ctx context.Context, opts *models.ListOptions) ([]*models.Task, error)
+
+	// AdminRequeueTask puts a dead task back to pending with a fresh set of attempts.
+	// Returns an error if the task does not exist or is not dead.
+	AdminRequeueTask(ctx context.Context, taskID uint) error
+
 	// Health Check
 
 	// HealthCheck performs a health check against the API.
@@ -98,17 +118,44 @@ type Client interface {
 	// Returns the Instance and any error encountered.
 	GetInstance(ctx context.Context, id string) (models.Instance, error)
 
+	// GetInstancePayload retrieves the status of the payload of an instance, with the exit code
+	// and the end of the output of the payload script once it ran.
+	// Returns the InstancePayload and any error encountered.
+	GetInstancePayload(ctx context.Context, instanceID uint) (*models.InstancePayload, error)
+
 	// CreateInstance creates new instances based on the provided specifications.
 	// The req parameter is a slice of InstanceRequest objects, each describing
 	// an instance to be created.
 	// Returns a slice of the created Instance pointers and any error encountered.
 	CreateInstance(ctx context.Context, req []types.InstanceRequest) ([]*models.Instance, error)
 
+	// ImportInstances adopts instances that already exist on a provider into a project.
+	// Instances are selected by their provider instance IDs or by a provider tag.
+	// Returns a slice of the imported Instance pointers and any error encountered.
+	ImportInstances(ctx context.Context, req types.ImportInstancesRequest) ([]*models.Instance, error)
+
 	// DeleteInstances terminates the specified instances for a project.
 	// The req parameter contains the project name and instance IDs to delete.
 	// Returns an error if the operation fails.
 	DeleteInstances(ctx context.Context, req types.DeleteInstancesRequest) error
 
+	// ListInstanceDriftEvents retrieves the drift events of a specific instance, newest first.
+	// Returns a slice of DriftEvent pointers and any error encountered.
+	ListInstanceDriftEvents(ctx context.Context, instanceID uint, opts *models.ListOptions) ([]*models.DriftEvent, error)
+
+	// Provider Endpoints - Methods for browsing the catalogs of compute providers
+
+	// ListProviderRegions retrieves the regions instances of the provider can be created in.
+	// An empty list means the provider accepts any region.
+	ListProviderRegions(ctx context.Context, provider models.ProviderID) ([]types.ProviderRegion, error)
+
+	// ListProviderSizes retrieves the instance sizes offered by the provider.
+	// An empty list means the provider accepts any size.
+	ListProviderSizes(ctx context.Context, provider models.ProviderID) ([]types.ProviderSize, error)
+
+	// ListProviderImages retrieves the images instances of the provider can be created from.
+	ListProviderImages(ctx context.Context, provider models.ProviderID) ([]types.ProviderImage, error)
+
 	// User Endpoints - Methods for managing users
 
 	// GetUserByID retrieves a user by their ID.
@@ -127,6 +174,14 @@ type Client interface {
 	// Returns an error if the operation fails.
 	DeleteUser(ctx context.Context, params handlers.DeleteUserParams) error
 
+	// SetUserWebhook sets the URL the task and instance status changes of a user are sent to.
+	// Returns the new secret the webhook requests are signed with, an empty URL disables the webhook.
+	SetUserWebhook(ctx context.Context, params handlers.UserSetWebhookParams) (types.SetWebhookResponse, error)
+
+	// ListWebhookDeliveries retrieves the webhook deliveries of an owner with their attempts,
+	// newest first. Deliveries are filtered by task and instance when taskID or instanceID are set.
+	ListWebhookDeliveries(ctx context.Context, ownerID, taskID, instanceID uint, opts *models.ListOptions) ([]*models.WebhookDelivery, error)
+
 	// Project methods - Methods for managing projects
 
 	// CreateProject creates a new project with the provided parameters.
@@ -159,6 +214,22 @@ type Client interface {
 	// Returns a slice of Task pointers and any error encountered.
 	ListTasks(ctx context.Context, params handlers.TaskListParams) ([]*models.Task, error)
 
+	// ListTaskEvents retrieves the events of a task, oldest first.
+	// Only events with an ID greater than afterID are returned, pass the ID of the last
+	// event received to get the events recorded since.
+	ListTaskEvents(ctx context.Context, taskID, afterID uint, opts *models.ListOptions) ([]*models.TaskEvent, error)
+
+	// StreamTaskEvents follows the events and status changes of a task as they happen.
+	// Events with an ID greater than afterID are sent first. The returned channel is closed
+	// after the TaskStreamEnd message once the task is finished, after a TaskStreamError
+	// message if the stream fails, or when ctx is cancelled.
+	StreamTaskEvents(ctx context.Context, taskID, afterID uint) (<-chan models.TaskStreamMessage, error)
+
+	// StreamProjectTaskEvents follows the events and status changes of all the tasks of a
+	// project until ctx is cancelled. Only events recorded after the stream started are sent,
+	// unless afterID is set.
+	StreamProjectTaskEvents(ctx context.Context, ownerID uint, projectName string, afterID uint) (<-chan models.TaskStreamMessage, error)
+
 	// ListTasksByInstanceID retrieves tasks for a specific instance ID.
 	// Parameters:
 	// - ownerID: The ID of the user who owns the instance
@@ -189,6 +260,20 @@ type Client interface {
 	// DeleteSSHKey deletes an SSH key.
 	// Returns an error if the operation fails.
 	DeleteSSHKey(ctx context.Context, params handlers.SSHKeyDeleteParams) error
+
+	// Playbook methods - Methods for managing provisioning playbooks
+
+	// CreatePlaybook uploads a playbook with its roles.
+	// Returns the created playbook, without its files, and any error encountered.
+	CreatePlaybook(ctx context.Context, params handlers.PlaybookCreateParams) (internalmodels.Playbook, error)
+
+	// ListPlaybooks lists the playbooks registered on the server and the playbooks of a specific owner.
+	// Returns a slice of playbook pointers, without their files, and any error encountered.
+	ListPlaybooks(ctx context.Context, params handlers.PlaybookListParams) ([]*internalmodels.Playbook, error)
+
+	// DeletePlaybook deletes an uploaded playbook.
+	// Returns an error if the operation fails.
+	DeletePlaybook(ctx context.Context, params handlers.PlaybookDeleteParams) error
 }
 
 var _ Client = &APIClient{}
@@ -538,6 +623,50 @@ func (c *APIClient) AdminGetInstancesMetadata(ctx context.Context) ([]*models.In
 	return response.Rows, nil
 }
 
+// AdminListDriftEvents retrieves the drift events of all instances
+func (c *APIClient) AdminListDriftEvents(ctx context.Context, opts *models.ListOptions) ([]*models.DriftEvent, error) {
+	endpoint := routes.AdminListDriftEventsURL(paginationQuery(opts))
+	return c.listDriftEvents(ctx, endpoint)
+}
+
+// AdminListDeadTasks retrieves the dead tasks of all owners
+func (c *APIClient) AdminListDeadTasks(ctx context.Context, opts *models.ListOptions) ([]*models.Task, error) {
+	endpoint := routes.AdminListDeadTasksURL(paginationQuery(opts))
+	var slugResp types.SlugResponse
+	if err := c.executeRequest(ctx, http.MethodGet, endpoint, nil, &slugResp); err != nil {
+		return nil, fmt.Errorf("failed to execute request for dead tasks: %w", err)
+	}
+	if slugResp.Slug != types.SuccessSlug {
+		return nil, fmt.Errorf("API error on dead tasks (%s): %s", slugResp.Slug, slugResp.Error)
+	}
+	if slugResp.Data == nil {
+		return nil, fmt.Errorf("API response for dead tasks missing data")
+	}
+
+	var listResponse types.ListResponse[models.Task]
+	jsonData, err := json.Marshal(slugResp.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal dead tasks data: %w", err)
+	}
+	if err := json.Unmarshal(jsonData, &listResponse); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal dead tasks: %w", err)
+	}
+	return listResponse.Rows, nil
+}
+
+// AdminRequeueTask puts a dead task back to pending
+func (c *APIClient) AdminRequeueTask(ctx context.Context, taskID uint) error {
+	endpoint := routes.AdminRequeueTaskURL(strconv.FormatUint(uint64(taskID), 10))
+	var slugResp types.SlugResponse
+	if err := c.executeRequest(ctx, http.MethodPost, endpoint, nil, &slugResp); err != nil {
+		return fmt.Errorf("failed to requeue task %d: %w", taskID, err)
+	}
+	if slugResp.Slug != types.SuccessSlug {
+		return fmt.Errorf("API error on requeue task %d (%s): %s", taskID, slugResp.Slug, slugResp.Error)
+	}
+	return nil
+}
+
 // Health check implementation
 
 // HealthCheck checks the health of the API
@@ -612,6 +741,10 @@ func getQueryParams(opts *models.ListOptions) (url.Values, error) {
 			statusStr = "ready"
 		case models.InstanceStatusTerminated:
 			statusStr = "terminated"
+		case models.InstanceStatusStopped:
+			statusStr = "stopped"
+		case models.InstanceStatusFailed:
+			statusStr = "failed"
 		default:
 			// Use %v for the underlying int type
 			return nil, fmt.Errorf("invalid instance status: %v", status)
@@ -677,6 +810,16 @@ func (c *APIClient) GetInstance(ctx context.Context, id string) (models.Instance
 	return response, nil
 }
 
+// GetInstancePayload retrieves the payload of an instance by ID
+func (c *APIClient) GetInstancePayload(ctx context.Context, instanceID uint) (*models.InstancePayload, error) {
+	endpoint := routes.GetInstancePayloadURL(strconv.FormatUint(uint64(instanceID), 10))
+	var response models.InstancePayload
+	if err := c.executeRequest(ctx, http.MethodGet, endpoint, nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
 // CreateInstance creates new instances
 func (c *APIClient) CreateInstance(ctx context.Context, req []types.InstanceRequest) ([]*models.Instance, error) {
 	endpoint := routes.CreateInstanceURL()
@@ -704,6 +847,32 @@ func (c *APIClient) CreateInstance(ctx context.Context, req []types.InstanceRequ
 	return createdInstances, nil
 }
 
+// ImportInstances imports existing provider instances into a project
+func (c *APIClient) ImportInstances(ctx context.Context, req types.ImportInstancesRequest) ([]*models.Instance, error) {
+	endpoint := routes.ImportInstancesURL()
+	var slugResp types.SlugResponse
+
+	if err := c.executeRequest(ctx, fiber.MethodPost, endpoint, req, &slugResp); err != nil {
+		return nil, err
+	}
+
+	if slugResp.Slug != types.SuccessSlug {
+		return nil, fmt.Errorf("API error (%s): %s", slugResp.Slug, slugResp.Error)
+	}
+
+	var importedInstances []*models.Instance
+	jsonData, err := json.Marshal(slugResp.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal slugResp.Data for ImportInstances: %w", err)
+	}
+
+	if err := json.Unmarshal(jsonData, &importedInstances); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal imported instances from slugResp.Data: %w", err)
+	}
+
+	return importedInstances, nil
+}
+
 // DeleteInstances deletes specified instances for a project
 func (c *APIClient) DeleteInstances(ctx context.Context, req types.DeleteInstancesRequest) error {
 	endpoint := routes.TerminateInstancesURL()
@@ -744,6 +913,48 @@ func (c *APIClient) DeleteUser(ctx context.Context, params handlers.DeleteUserPa
 	return c.executeRPC(ctx, handlers.UserDelete, params, nil)
 }
 
+// SetUserWebhook sets the webhook of a user and returns its signing secret
+func (c *APIClient) SetUserWebhook(ctx context.Context, params handlers.UserSetWebhookParams) (types.SetWebhookResponse, error) {
+	var response types.SetWebhookResponse
+	if err := c.executeRPC(ctx, handlers.UserSetWebhook, params, &response); err != nil {
+		return types.SetWebhookResponse{}, err
+	}
+	return response, nil
+}
+
+// ListWebhookDeliveries retrieves the webhook deliveries of an owner
+func (c *APIClient) ListWebhookDeliveries(ctx context.Context, ownerID, taskID, instanceID uint, opts *models.ListOptions) ([]*models.WebhookDelivery, error) {
+	query := paginationQuery(opts)
+	query.Set("owner_id", strconv.FormatUint(uint64(ownerID), 10))
+	if taskID > 0 {
+		query.Set("task_id", strconv.FormatUint(uint64(taskID), 10))
+	}
+	if instanceID > 0 {
+		query.Set("instance_id", strconv.FormatUint(uint64(instanceID), 10))
+	}
+
+	var slugResp types.SlugResponse
+	if err := c.executeRequest(ctx, http.MethodGet, routes.ListWebhookDeliveriesURL(query), nil, &slugResp); err != nil {
+		return nil, fmt.Errorf("failed to execute request for webhook deliveries: %w", err)
+	}
+	if slugResp.Slug != types.SuccessSlug {
+		return nil, fmt.Errorf("API error on webhook deliveries (%s): %s", slugResp.Slug, slugResp.Error)
+	}
+	if slugResp.Data == nil {
+		return nil, fmt.Errorf("API response for webhook deliveries missing data")
+	}
+
+	var listResponse types.ListResponse[models.WebhookDelivery]
+	jsonData, err := json.Marshal(slugResp.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webhook deliveries data: %w", err)
+	}
+	if err := json.Unmarshal(jsonData, &listResponse); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webhook deliveries: %w", err)
+	}
+	return listResponse.Rows, nil
+}
+
 // CreateProject creates a new project
 func (c *APIClient) CreateProject(ctx context.Context, params handlers.ProjectCreateParams) (models.Project, error) {
 	var project models.Project // Use pkg/models.Project
@@ -805,6 +1016,209 @@ func (c *APIClient) ListTasks(ctx context.Context, params handlers.TaskListParam
 	return listResponse.Rows, nil
 }
 
+// ListTaskEvents retrieves the events of a task
+func (c *APIClient) ListTaskEvents(ctx context.Context, taskID, afterID uint, opts *models.ListOptions) ([]*models.TaskEvent, error) {
+	query := paginationQuery(opts)
+	if afterID > 0 {
+		query.Set("after_id", strconv.FormatUint(uint64(afterID), 10))
+	}
+	endpoint := routes.ListTaskEventsURL(strconv.FormatUint(uint64(taskID), 10), query)
+
+	var slugResp types.SlugResponse
+	if err := c.executeRequest(ctx, http.MethodGet, endpoint, nil, &slugResp); err != nil {
+		return nil, fmt.Errorf("failed to execute request for task events: %w", err)
+	}
+	if slugResp.Slug != types.SuccessSlug {
+		return nil, fmt.Errorf("API error on task events (%s): %s", slugResp.Slug, slugResp.Error)
+	}
+	if slugResp.Data == nil {
+		return nil, fmt.Errorf("API response for task events missing data")
+	}
+
+	var listResponse types.ListResponse[models.TaskEvent]
+	jsonData, err := json.Marshal(slugResp.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal task events data: %w", err)
+	}
+	if err := json.Unmarshal(jsonData, &listResponse); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task events: %w", err)
+	}
+	return listResponse.Rows, nil
+}
+
+// StreamTaskEvents follows the events and status changes of a task
+func (c *APIClient) StreamTaskEvents(ctx context.Context, taskID, afterID uint) (<-chan models.TaskStreamMessage, error) {
+	query := url.Values{}
+	if afterID > 0 {
+		query.Set("after_id", strconv.FormatUint(uint64(afterID), 10))
+	}
+	return c.streamEvents(ctx, routes.StreamTaskEventsURL(strconv.FormatUint(uint64(taskID), 10), query))
+}
+
+// StreamProjectTaskEvents follows the events and status changes of the tasks of a project
+func (c *APIClient) StreamProjectTaskEvents(ctx context.Context, ownerID uint, projectName string, afterID uint) (<-chan models.TaskStreamMessage, error) {
+	query := url.Values{}
+	query.Set("owner_id", strconv.FormatUint(uint64(ownerID), 10))
+	if afterID > 0 {
+		query.Set("after_id", strconv.FormatUint(uint64(afterID), 10))
+	}
+	return c.streamEvents(ctx, routes.StreamProjectTaskEventsURL(projectName, query))
+}
+
+// streamEvents opens a Server-Sent Events stream of task messages.
+//
+// Streams last as long as the tasks they follow, so unlike other requests they are sent
+// without the client timeout, and with net/http as the Fiber Agent buffers whole responses.
+// The messages are read in the background until the stream ends or ctx is cancelled.
+func (c *APIClient) streamEvents(ctx context.Context, endpoint string) (<-chan models.TaskStreamMessage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event stream request: %w", err)
+	}
+	req.Header.Set("Accept", handlers.EventStreamContentType)
+	if c.APIKey != "" {
+		req.Header.Set("apikey", c.APIKey)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending event stream request: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		return nil, &fiber.Error{
+			Code:    resp.StatusCode,
+			Message: string(body),
+		}
+	}
+
+	messages := make(chan models.TaskStreamMessage)
+	go func() {
+		defer close(messages)
+		defer func() { _ = resp.Body.Close() }()
+
+		deliver := func(msg models.TaskStreamMessage) bool {
+			select {
+			case messages <- msg:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		// Events are separated by a blank line, only their data matters as it holds the
+		// message type. Comments and the other fields are ignored.
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 64*1024), maxEventSize)
+		var data strings.Builder
+		for scanner.Scan() {
+			line := scanner.Text()
+			if value, ok := strings.CutPrefix(line, "data:"); ok {
+				data.WriteString(strings.TrimPrefix(value, " "))
+				continue
+			}
+			if line != "" || data.Len() == 0 {
+				continue
+			}
+
+			var msg models.TaskStreamMessage
+			if err := json.Unmarshal([]byte(data.String()), &msg); err != nil {
+				msg = models.TaskStreamMessage{Type: models.TaskStreamError, Error: fmt.Sprintf("failed to decode task stream message: %v", err)}
+			}
+			data.Reset()
+			if !deliver(msg) || msg.Type == models.TaskStreamEnd || msg.Type == models.TaskStreamError {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil && ctx.Err() == nil {
+			deliver(models.TaskStreamMessage{Type: models.TaskStreamError, Error: fmt.Sprintf("task event stream failed: %v", err)})
+		}
+	}()
+	return messages, nil
+}
+
+// ListInstanceDriftEvents retrieves the drift events of a specific instance
+func (c *APIClient) ListInstanceDriftEvents(ctx context.Context, instanceID uint, opts *models.ListOptions) ([]*models.DriftEvent, error) {
+	endpoint := routes.ListInstanceDriftEventsURL(strconv.FormatUint(uint64(instanceID), 10), paginationQuery(opts))
+	return c.listDriftEvents(ctx, endpoint)
+}
+
+// listDriftEvents retrieves drift events from a list endpoint returning a types.SlugResponse
+func (c *APIClient) listDriftEvents(ctx context.Context, endpoint string) ([]*models.DriftEvent, error) {
+	var slugResp types.SlugResponse
+	if err := c.executeRequest(ctx, http.MethodGet, endpoint, nil, &slugResp); err != nil {
+		return nil, fmt.Errorf("failed to execute request for drift events: %w", err)
+	}
+	if slugResp.Slug != types.SuccessSlug {
+		return nil, fmt.Errorf("API error on drift events (%s): %s", slugResp.Slug, slugResp.Error)
+	}
+	if slugResp.Data == nil {
+		return nil, fmt.Errorf("API response for drift events missing data")
+	}
+
+	var listResponse types.ListResponse[models.DriftEvent]
+	jsonData, err := json.Marshal(slugResp.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal drift events data: %w", err)
+	}
+	if err := json.Unmarshal(jsonData, &listResponse); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal drift events: %w", err)
+	}
+	return listResponse.Rows, nil
+}
+
+// ListProviderRegions retrieves the regions of a provider
+func (c *APIClient) ListProviderRegions(ctx context.Context, provider models.ProviderID) ([]types.ProviderRegion, error) {
+	return getProviderCatalog[types.ProviderRegion](ctx, c, routes.GetProviderRegionsURL(provider.String()), "regions")
+}
+
+// ListProviderSizes retrieves the instance sizes of a provider
+func (c *APIClient) ListProviderSizes(ctx context.Context, provider models.ProviderID) ([]types.ProviderSize, error) {
+	return getProviderCatalog[types.ProviderSize](ctx, c, routes.GetProviderSizesURL(provider.String()), "sizes")
+}
+
+// ListProviderImages retrieves the images of a provider
+func (c *APIClient) ListProviderImages(ctx context.Context, provider models.ProviderID) ([]types.ProviderImage, error) {
+	return getProviderCatalog[types.ProviderImage](ctx, c, routes.GetProviderImagesURL(provider.String()), "images")
+}
+
+// getProviderCatalog retrieves a provider catalog from an endpoint returning a types.SlugResponse
+func getProviderCatalog[T any](ctx context.Context, c *APIClient, endpoint, kind string) ([]T, error) {
+	var slugResp types.SlugResponse
+	if err := c.executeRequest(ctx, http.MethodGet, endpoint, nil, &slugResp); err != nil {
+		return nil, fmt.Errorf("failed to execute request for provider %s: %w", kind, err)
+	}
+	if slugResp.Slug != types.SuccessSlug {
+		return nil, fmt.Errorf("API error on provider %s (%s): %s", kind, slugResp.Slug, slugResp.Error)
+	}
+
+	values := []T{}
+	jsonData, err := json.Marshal(slugResp.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal provider %s data: %w", kind, err)
+	}
+	if err := json.Unmarshal(jsonData, &values); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal provider %s: %w", kind, err)
+	}
+	return values, nil
+}
+
+// paginationQuery returns the limit and offset query parameters of the list options
+func paginationQuery(opts *models.ListOptions) url.Values {
+	q := url.Values{}
+	if opts == nil {
+		return q
+	}
+	if opts.Limit > 0 {
+		q.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		q.Set("offset", strconv.Itoa(opts.Offset))
+	}
+	return q
+}
+
 // ListTasksByInstanceID retrieves tasks for a specific instance ID, with optional action and pagination.
 func (c *APIClient) ListTasksByInstanceID(ctx context.Context, ownerID uint, instanceID uint, actionFilter string, opts *models.ListOptions) ([]*models.Task, error) {
 	queryParams := url.Values{}
@@ -885,3 +1299,24 @@ func (c *APIClient) ListSSHKeys(ctx context.Context, params handlers.SSHKeyListP
 func (c *APIClient) DeleteSSHKey(ctx context.Context, params handlers.SSHKeyDeleteParams) error {
 	return c.executeRPC(ctx, handlers.SSHKeyDelete, params, nil)
 }
+
+// Playbook methods implementation
+
+// CreatePlaybook uploads a playbook with its roles
+func (c *APIClient) CreatePlaybook(ctx context.Context, params handlers.PlaybookCreateParams) (internalmodels.Playbook, error) {
+	var playbook internalmodels.Playbook
+	err := c.executeRPC(ctx, handlers.PlaybookCreate, params, &playbook)
+	return playbook, err
+}
+
+// ListPlaybooks lists the playbooks registered on the server and the playbooks of a specific owner
+func (c *APIClient) ListPlaybooks(ctx context.Context, params handlers.PlaybookListParams) ([]*internalmodels.Playbook, error) {
+	var playbooks []*internalmodels.Playbook
+	err := c.executeRPC(ctx, handlers.PlaybookList, params, &playbooks)
+	return playbooks, err
+}
+
+// DeletePlaybook deletes an uploaded playbook
+func (c *APIClient) DeletePlaybook(ctx context.Context, params handlers.PlaybookDeleteParams) error {
+	return c.executeRPC(ctx, handlers.PlaybookDelete, params, nil)
+}
@@ -4,18 +4,32 @@ import "github.com/celestiaorg/talis/internal/services"
 
 // APIHandler is a handler for the API
 type APIHandler struct {
-	instance *services.Instance
-	project  *services.Project
-	task     *services.Task
-	user     *services.User
+	instance   *services.Instance
+	project    *services.Project
+	task       *services.Task
+	user       *services.User
+	driftEvent *services.DriftEvent
+	catalog    *services.Catalog
+	webhook    *services.Webhook
 }
 
 // NewAPIHandler creates a new API handler
-func NewAPIHandler(instance *services.Instance, project *services.Project, task *services.Task, user *services.User) *APIHandler {
+func NewAPIHandler(
+	instance *services.Instance,
+	project *services.Project,
+	task *services.Task,
+	user *services.User,
+	driftEvent *services.DriftEvent,
+	catalog *services.Catalog,
+	webhook *services.Webhook,
+) *APIHandler {
 	return &APIHandler{
-		instance: instance,
-		project:  project,
-		task:     task,
-		user:     user,
+		instance:   instance,
+		project:    project,
+		task:       task,
+		user:       user,
+		driftEvent: driftEvent,
+		catalog:    catalog,
+		webhook:    webhook,
 	}
 }
@@ -30,6 +30,9 @@ const (
 	ErrMsgTaskNotFound        = "Task not found"
 	ErrMsgTaskListFailed      = "Failed to list tasks"
 	ErrMsgTaskTerminateFailed = "Failed to terminate task"
+	ErrMsgTaskAlreadyFinished = "Task has already finished"
+	ErrMsgDeadTaskNotFound    = "Task not found or not dead"
+	ErrMsgTaskRequeueFailed   = "Failed to requeue task"
 	ErrMsgTaskStatusFailed    = "Failed to update task status"
 	ErrMsgTaskStatusReqd      = "Status is required"
 	ErrMsgInvalidReqBody      = "Invalid request body"
@@ -44,12 +47,14 @@ const (
 	ErrMsgUsernameRequired       = "Username is required"
 	ErrMsgInvalidUsername        = "Invalid username"
 	ErrMsgInvalidUserEmail       = "Invalid user email format"
+	ErrMsgInvalidWebhookURL      = "Webhook URL must be an http or https URL"
 	ErrMsgUserNotFoundByID       = "User not found with provided id"
 	ErrMsgUserNotFoundByUsername = "User not found with provided username"
 	ErrMsgGetUsersFailed         = "Failed to get users"
 	ErrMsgGetUserFailed          = "Failed to get user"
 	ErrMsgCreateUserFailed       = "Failed to create user"
 	ErrMsgDeleteUserFailed       = "Failed to delete user"
+	ErrMsgSetWebhookFailed       = "Failed to set user webhook"
 	ErrMsgNegativeUserID         = "User ID must be positive"
 	ErrMsgNilUserObject          = "User object is nil"
 )
@@ -2,11 +2,14 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 
 	fiber "github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
 
 	"github.com/celestiaorg/talis/internal/db/models"
+	"github.com/celestiaorg/talis/internal/services"
 	"github.com/celestiaorg/talis/internal/types"
 )
 
@@ -25,7 +28,7 @@ func NewInstanceHandler(api *APIHandler) *InstanceHandler {
 // ListInstances godoc
 // @Summary List all instances
 // @Description Returns a list of all instances with pagination and filtering options.
-// @Description You can filter by status (pending, created, provisioning, ready, terminated) and control pagination with limit and offset.
+// @Description You can filter by status (pending, created, provisioning, ready, stopped, failed, terminated) and control pagination with limit and offset.
 // @Description By default, terminated instances are excluded unless include_deleted=true is specified.
 // @Tags instances
 // @Accept json
@@ -33,7 +36,7 @@ func NewInstanceHandler(api *APIHandler) *InstanceHandler {
 // @Param limit query int false "Number of items to return (default 10)" example(10)
 // @Param offset query int false "Number of items to skip (default 0)" example(0)
 // @Param include_deleted query bool false "Include deleted instances (default false)" example(false)
-// @Param status query string false "Filter by instance status (pending, created, provisioning, ready, terminated)" example(ready)
+// @Param status query string false "Filter by instance status (pending, created, provisioning, ready, stopped, failed, terminated)" example(ready)
 // @Success 200 {object} types.InstanceListResponse "List of instances with pagination information"
 // @Failure 400 {object} types.ErrorResponse "Invalid input - typically an invalid status value"
 // @Failure 500 {object} types.ErrorResponse "Internal server error - database or service errors"
@@ -116,6 +119,38 @@ func (h *InstanceHandler) GetInstance(c *fiber.Ctx) error {
 	return c.JSON(instance)
 }
 
+// GetInstancePayload godoc
+// @Summary Get the payload of an instance
+// @Description Returns the status of the payload of a specific instance as it is copied and executed.
+// @Description Once the payload script ran, the response includes its exit code and the end of its standard output and standard error, up to 64 KiB each.
+// @Tags instances
+// @Accept json
+// @Produce json
+// @Param id path int true "Instance ID" example(123)
+// @Success 200 {object} models.InstancePayload "Payload status, exit code and output of the instance"
+// @Failure 400 {object} types.ErrorResponse "Invalid input - typically a non-numeric or negative instance ID"
+// @Failure 404 {object} types.ErrorResponse "Instance not found"
+// @Failure 500 {object} types.ErrorResponse "Internal server error - database errors"
+// @Router /instances/{id}/payload [get]
+// @OperationId getInstancePayload
+func (h *InstanceHandler) GetInstancePayload(c *fiber.Ctx) error {
+	instanceID, err := c.ParamsInt("id")
+	if err != nil || instanceID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrInvalidInput("Invalid or missing instance id parameter"))
+	}
+
+	// TODO: should check for OwnerID and filter by it
+	payload, err := h.instance.GetPayload(c.Context(), models.AdminID, uint(instanceID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(types.ErrNotFound(fmt.Sprintf("instance %d not found", instanceID)))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrServer(fmt.Sprintf("failed to get instance payload: %v", err)))
+	}
+
+	return c.JSON(payload)
+}
+
 // CreateInstance godoc
 // @Summary Create new instances
 // @Description Creates one or more new cloud instances based on the provided specifications.
@@ -145,20 +180,57 @@ func (h *InstanceHandler) CreateInstance(c *fiber.Ctx) error {
 	// NOTE: in order to update the underlying instanceReqs, we need to iterate over the slice with the index. If you use range, you will get a copy of the slice and not the original.
 	for i := range instanceReqs {
 		instanceReqs[i].Action = "create"
-		if err := instanceReqs[i].Validate(); err != nil {
+	}
+
+	// The requests are validated by the instance service, before anything is queued
+	createdInstances, err := h.instance.CreateInstance(c.Context(), instanceReqs)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidInstanceRequest) {
 			return c.Status(fiber.StatusBadRequest).
 				JSON(types.ErrInvalidInput(err.Error()))
 		}
+		return c.Status(fiber.StatusInternalServerError).
+			JSON(types.ErrServer(err.Error()))
 	}
 
-	createdInstances, err := h.instance.CreateInstance(c.Context(), instanceReqs)
+	return c.Status(fiber.StatusCreated).
+		JSON(types.Success(createdInstances))
+}
+
+// ImportInstances godoc
+// @Summary Import existing instances
+// @Description Adopts machines that already exist on a provider into a project, so they can be managed by Talis.
+// @Description Instances are selected either by their provider instance IDs or by a provider tag; IP, region, size and volumes are read from the provider.
+// @Description Imported instances are not provisioned. When selecting by tag, instances that are already managed are skipped.
+// @Tags instances
+// @Accept json
+// @Produce json
+// @Param request body types.ImportInstancesRequest true "Provider, project and the instances to import"
+// @Success 201 {object} types.SuccessResponse "Successfully imported instances with details of the created records"
+// @Failure 400 {object} types.ErrorResponse "Invalid input - missing required fields or validation errors in the request"
+// @Failure 500 {object} types.ErrorResponse "Internal server error - provider API errors, instances already managed or service failures"
+// @Router /instances/import [post]
+// @OperationId importInstances
+func (h *InstanceHandler) ImportInstances(c *fiber.Ctx) error {
+	var req types.ImportInstancesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(types.ErrInvalidInput(err.Error()))
+	}
+
+	if err := req.Validate(); err != nil {
+		return c.Status(fiber.StatusBadRequest).
+			JSON(types.ErrInvalidInput(err.Error()))
+	}
+
+	importedInstances, err := h.instance.ImportInstances(c.Context(), req)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).
 			JSON(types.ErrServer(err.Error()))
 	}
 
 	return c.Status(fiber.StatusCreated).
-		JSON(types.Success(createdInstances))
+		JSON(types.Success(importedInstances))
 }
 
 // GetPublicIPs godoc
@@ -275,18 +347,100 @@ func (h *InstanceHandler) GetAllMetadata(c *fiber.Ctx) error {
 	})
 }
 
+// ListDriftEvents godoc
+// @Summary List drift events
+// @Description Returns the drift events of all instances, newest first.
+// @Description Drift events are recorded by the reconciler when an instance disappeared from its provider, was stopped or started outside of Talis or changed its public IP.
+// @Tags instances
+// @Accept json
+// @Produce json
+// @Param limit query int false "Number of items to return (default 10)" example(10)
+// @Param offset query int false "Number of items to skip (default 0)" example(0)
+// @Success 200 {object} types.SuccessResponse{data=types.DriftEventListResponse} "List of drift events"
+// @Failure 400 {object} types.ErrorResponse "Invalid input"
+// @Failure 500 {object} types.ErrorResponse "Internal server error"
+// @Router /admin/instances/drift-events [get]
+// @OperationId listDriftEvents
+func (h *InstanceHandler) ListDriftEvents(c *fiber.Ctx) error {
+	return h.listDriftEvents(c, 0)
+}
+
+// ListInstanceDriftEvents godoc
+// @Summary List drift events of an instance
+// @Description Returns the drift events of a specific instance, newest first.
+// @Tags instances
+// @Accept json
+// @Produce json
+// @Param instance_id path int true "Instance ID"
+// @Param limit query int false "Number of items to return (default 10)" example(10)
+// @Param offset query int false "Number of items to skip (default 0)" example(0)
+// @Success 200 {object} types.SuccessResponse{data=types.DriftEventListResponse} "List of drift events"
+// @Failure 400 {object} types.ErrorResponse "Invalid input"
+// @Failure 500 {object} types.ErrorResponse "Internal server error"
+// @Router /instances/{instance_id}/drift-events [get]
+// @OperationId listInstanceDriftEvents
+func (h *InstanceHandler) ListInstanceDriftEvents(c *fiber.Ctx) error {
+	instanceID, err := c.ParamsInt("instance_id")
+	if err != nil || instanceID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrInvalidInput("Invalid or missing instance_id parameter"))
+	}
+	return h.listDriftEvents(c, uint(instanceID))
+}
+
+// listDriftEvents lists the drift events of an instance, or of all instances if instanceID is 0
+func (h *InstanceHandler) listDriftEvents(c *fiber.Ctx, instanceID uint) error {
+	// TODO: should check for OwnerID and filter by it
+	ownerID := models.AdminID
+
+	opts := &models.ListOptions{
+		Limit:  c.QueryInt("limit", DefaultPageSize),
+		Offset: c.QueryInt("offset", 0),
+	}
+	if opts.Limit < 0 || opts.Offset < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrInvalidInput("limit and offset must be non-negative numbers"))
+	}
+
+	var (
+		events []models.DriftEvent
+		err    error
+	)
+	if instanceID == 0 {
+		events, err = h.driftEvent.List(c.Context(), ownerID, opts)
+	} else {
+		events, err = h.driftEvent.ListByInstance(c.Context(), ownerID, instanceID, opts)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrServer("Failed to retrieve drift events"))
+	}
+
+	page := 0
+	if opts.Limit > 0 {
+		page = (opts.Offset / opts.Limit) + 1
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.Success(types.ListResponse[models.DriftEvent]{
+		Rows: events,
+		Pagination: types.PaginationResponse{
+			Total:  len(events),
+			Limit:  opts.Limit,
+			Offset: opts.Offset,
+			Page:   page,
+		},
+	}))
+}
+
 // GetInstances godoc
 // @Summary List instances
 // @Description Returns a list of instances with pagination and optional filtering by status.
 // @Description This endpoint is similar to ListInstances but with a different operation ID for client compatibility.
-// @Description You can filter by status (pending, created, provisioning, ready, terminated) and control pagination with limit and offset.
+// @Description You can filter by status (pending, created, provisioning, ready, stopped, failed, terminated) and control pagination with limit and offset.
 // @Tags instances
 // @Accept json
 // @Produce json
 // @Param limit query int false "Number of items to return (default 10)" example(10)
 // @Param offset query int false "Number of items to skip (default 0)" example(0)
 // @Param include_deleted query bool false "Include deleted instances (default false)" example(false)
-// @Param status query string false "Filter by instance status (pending, created, provisioning, ready, terminated)" example(ready)
+// @Param status query string false "Filter by instance status (pending, created, provisioning, ready, stopped, failed, terminated)" example(ready)
 // @Success 200 {object} types.InstanceListResponse "List of instances with pagination information"
 // @Failure 400 {object} types.ErrorResponse "Invalid input - typically an invalid status value"
 // @Failure 500 {object} types.ErrorResponse "Internal server error - database or service errors"
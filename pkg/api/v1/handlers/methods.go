@@ -21,11 +21,18 @@ const (
 	UserGet     = "user.get"
 	UserGetByID = "user.get.id"
 	UserDelete  = "user.delete"
+	// UserSetWebhook sets the webhook URL of a user and returns its new signing secret
+	UserSetWebhook = "user.setWebhook"
 
 	// SSH Key methods
 	SSHKeyCreate = "sshkey.create"
 	SSHKeyList   = "sshkey.list"
 	SSHKeyDelete = "sshkey.delete"
+
+	// Playbook methods
+	PlaybookCreate = "playbook.create"
+	PlaybookList   = "playbook.list"
+	PlaybookDelete = "playbook.delete"
 )
 
 // IsProjectMethod checks if the given method is a project operation
@@ -51,7 +58,7 @@ func IsTaskMethod(method string) bool {
 // IsUserMethod checks if the given method is a user operation
 func IsUserMethod(method string) bool {
 	switch method {
-	case UserCreate, UserGet, UserGetByID, UserDelete:
+	case UserCreate, UserGet, UserGetByID, UserDelete, UserSetWebhook:
 		return true
 	default:
 		return false
@@ -67,3 +74,13 @@ func IsSSHKeyMethod(method string) bool {
 		return false
 	}
 }
+
+// IsPlaybookMethod checks if the given method is a playbook operation
+func IsPlaybookMethod(method string) bool {
+	switch method {
+	case PlaybookCreate, PlaybookList, PlaybookDelete:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,164 @@
+// Package handlers provides HTTP request handling
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+	"github.com/celestiaorg/talis/internal/services"
+)
+
+// PlaybookHandlers provides HTTP handlers for playbook operations
+type PlaybookHandlers struct {
+	PlaybookService PlaybookService
+}
+
+// PlaybookService defines the interface for playbook-related operations
+// This is an interface to allow for easy mocking in tests
+type PlaybookService interface {
+	CreatePlaybook(ctx context.Context, playbook *models.Playbook) error
+	ListPlaybooks(ctx context.Context, ownerID uint) ([]*models.Playbook, error)
+	DeletePlaybook(ctx context.Context, ownerID uint, name string) error
+}
+
+// PlaybookCreateParams defines the parameters for uploading a playbook
+type PlaybookCreateParams struct {
+	Name        string               `json:"name" validate:"required"`
+	Description string               `json:"description,omitempty"`
+	Files       models.PlaybookFiles `json:"files" validate:"required"` // Files by path relative to the playbook directory, main.yml is required
+	OwnerID     uint                 `json:"owner_id" validate:"required"`
+}
+
+// PlaybookListParams defines the parameters for listing playbooks
+type PlaybookListParams struct {
+	OwnerID uint `json:"owner_id" validate:"required"`
+}
+
+// PlaybookDeleteParams defines the parameters for deleting a playbook
+type PlaybookDeleteParams struct {
+	Name    string `json:"name" validate:"required"`
+	OwnerID uint   `json:"owner_id" validate:"required"`
+}
+
+// Create handles the upload of a new playbook
+// @Summary Upload a playbook
+// @Description Upload a playbook with its roles for the specified owner, instance requests reference it by name.
+// @Description Uploads are refused unless they are enabled on the server with PLAYBOOK_UPLOADS_ENABLED.
+// @Tags playbook
+// @Accept json
+// @Produce json
+// @Param request body RPCRequest true "Playbook creation request"
+// @Success 200 {object} RPCResponse
+// @Failure 400 {object} RPCResponse
+// @Failure 403 {object} RPCResponse
+// @Failure 409 {object} RPCResponse
+// @Failure 500 {object} RPCResponse
+// @Router / [post]
+func (h *PlaybookHandlers) Create(c *fiber.Ctx, req RPCRequest) error {
+	params, err := parseParams[PlaybookCreateParams](req)
+	if err != nil {
+		return respondWithRPCError(c, fiber.StatusBadRequest, "Invalid parameters", err.Error(), req.ID)
+	}
+
+	// Validate required fields
+	if params.Name == "" {
+		return respondWithRPCError(c, fiber.StatusBadRequest, "Name is required", nil, req.ID)
+	}
+	if params.OwnerID == 0 {
+		return respondWithRPCError(c, fiber.StatusBadRequest, "Owner ID is required", nil, req.ID)
+	}
+
+	playbook := &models.Playbook{
+		Name:        params.Name,
+		OwnerID:     params.OwnerID,
+		Description: params.Description,
+		Files:       params.Files,
+	}
+	if err := playbook.Validate(); err != nil {
+		return respondWithRPCError(c, fiber.StatusBadRequest, err.Error(), nil, req.ID)
+	}
+
+	err = h.PlaybookService.CreatePlaybook(c.Context(), playbook)
+	if err != nil {
+		if errors.Is(err, services.ErrPlaybookExists) {
+			return respondWithRPCError(c, fiber.StatusConflict, "Playbook already exists", err.Error(), req.ID)
+		}
+		if errors.Is(err, services.ErrPlaybookUploadsDisabled) {
+			return respondWithRPCError(c, fiber.StatusForbidden, "Uploaded playbooks are disabled", err.Error(), req.ID)
+		}
+		return respondWithRPCError(c, fiber.StatusInternalServerError, "Failed to create playbook", err.Error(), req.ID)
+	}
+
+	// The files were just sent by the client
+	playbook.Files = nil
+	return c.JSON(RPCResponse{
+		Data:    playbook,
+		Success: true,
+		ID:      req.ID,
+	})
+}
+
+// List handles listing playbooks for an owner
+// @Summary List playbooks
+// @Description List the playbooks registered on the server and the playbooks uploaded by the specified owner
+// @Tags playbook
+// @Accept json
+// @Produce json
+// @Param request body RPCRequest true "Playbook list request"
+// @Success 200 {object} RPCResponse
+// @Failure 400 {object} RPCResponse
+// @Failure 500 {object} RPCResponse
+// @Router / [post]
+func (h *PlaybookHandlers) List(c *fiber.Ctx, req RPCRequest) error {
+	params, err := parseParams[PlaybookListParams](req)
+	if err != nil {
+		return respondWithRPCError(c, fiber.StatusBadRequest, "Invalid parameters", err.Error(), req.ID)
+	}
+
+	playbooks, err := h.PlaybookService.ListPlaybooks(c.Context(), params.OwnerID)
+	if err != nil {
+		return respondWithRPCError(c, fiber.StatusInternalServerError, "Failed to list playbooks", err.Error(), req.ID)
+	}
+
+	return c.JSON(RPCResponse{
+		Data:    playbooks,
+		Success: true,
+		ID:      req.ID,
+	})
+}
+
+// Delete handles deleting a playbook
+// @Summary Delete a playbook
+// @Description Delete a playbook uploaded by the specified owner
+// @Tags playbook
+// @Accept json
+// @Produce json
+// @Param request body RPCRequest true "Playbook delete request"
+// @Success 200 {object} RPCResponse
+// @Failure 400 {object} RPCResponse
+// @Failure 404 {object} RPCResponse
+// @Failure 500 {object} RPCResponse
+// @Router / [post]
+func (h *PlaybookHandlers) Delete(c *fiber.Ctx, req RPCRequest) error {
+	params, err := parseParams[PlaybookDeleteParams](req)
+	if err != nil {
+		return respondWithRPCError(c, fiber.StatusBadRequest, "Invalid parameters", err.Error(), req.ID)
+	}
+
+	err = h.PlaybookService.DeletePlaybook(c.Context(), params.OwnerID, params.Name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return respondWithRPCError(c, fiber.StatusNotFound, "Playbook not found", err.Error(), req.ID)
+		}
+		return respondWithRPCError(c, fiber.StatusInternalServerError, "Failed to delete playbook", err.Error(), req.ID)
+	}
+
+	return c.JSON(RPCResponse{
+		Success: true,
+		ID:      req.ID,
+	})
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"fmt"
+
+	fiber "github.com/gofiber/fiber/v2"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+	"github.com/celestiaorg/talis/internal/types"
+)
+
+// ProviderHandler handles HTTP requests for the catalogs of compute providers
+type ProviderHandler struct {
+	*APIHandler
+}
+
+// NewProviderHandler creates a new provider handler instance
+func NewProviderHandler(api *APIHandler) *ProviderHandler {
+	return &ProviderHandler{
+		APIHandler: api,
+	}
+}
+
+// ListRegions godoc
+// @Summary List provider regions
+// @Description Returns the regions instances of the provider can be created in.
+// @Description An empty list means the provider accepts any region.
+// @Tags providers
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider ID (e.g. do, hetzner, linode, vultr, aws, ximera, local)"
+// @Success 200 {object} types.SuccessResponse{data=[]types.ProviderRegion} "List of regions"
+// @Failure 400 {object} types.ErrorResponse "Unknown provider"
+// @Failure 500 {object} types.ErrorResponse "Internal server error - the provider catalog could not be fetched"
+// @Router /providers/{provider}/regions [get]
+// @OperationId listProviderRegions
+func (h *ProviderHandler) ListRegions(c *fiber.Ctx) error {
+	providerID, err := providerParam(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrInvalidInput(err.Error()))
+	}
+
+	regions, err := h.catalog.Regions(c.Context(), providerID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrServer(fmt.Sprintf("failed to list regions: %v", err)))
+	}
+	return c.Status(fiber.StatusOK).JSON(types.Success(regions))
+}
+
+// ListSizes godoc
+// @Summary List provider sizes
+// @Description Returns the instance sizes of the provider, with CPU, memory, disk and the regions they are offered in.
+// @Description An empty list means the provider accepts any size.
+// @Tags providers
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider ID (e.g. do, hetzner, linode, vultr, aws, ximera, local)"
+// @Success 200 {object} types.SuccessResponse{data=[]types.ProviderSize} "List of sizes"
+// @Failure 400 {object} types.ErrorResponse "Unknown provider"
+// @Failure 500 {object} types.ErrorResponse "Internal server error - the provider catalog could not be fetched"
+// @Router /providers/{provider}/sizes [get]
+// @OperationId listProviderSizes
+func (h *ProviderHandler) ListSizes(c *fiber.Ctx) error {
+	providerID, err := providerParam(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrInvalidInput(err.Error()))
+	}
+
+	sizes, err := h.catalog.Sizes(c.Context(), providerID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrServer(fmt.Sprintf("failed to list sizes: %v", err)))
+	}
+	return c.Status(fiber.StatusOK).JSON(types.Success(sizes))
+}
+
+// ListImages godoc
+// @Summary List provider images
+// @Description Returns the images instances of the provider can be created from.
+// @Description For Ximera the image ID is the numeric osID. Some providers accept further images, such as snapshot IDs or AMI name patterns.
+// @Tags providers
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider ID (e.g. do, hetzner, linode, vultr, aws, ximera, local)"
+// @Success 200 {object} types.SuccessResponse{data=[]types.ProviderImage} "List of images"
+// @Failure 400 {object} types.ErrorResponse "Unknown provider"
+// @Failure 500 {object} types.ErrorResponse "Internal server error - the provider catalog could not be fetched"
+// @Router /providers/{provider}/images [get]
+// @OperationId listProviderImages
+func (h *ProviderHandler) ListImages(c *fiber.Ctx) error {
+	providerID, err := providerParam(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrInvalidInput(err.Error()))
+	}
+
+	images, err := h.catalog.Images(c.Context(), providerID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrServer(fmt.Sprintf("failed to list images: %v", err)))
+	}
+	return c.Status(fiber.StatusOK).JSON(types.Success(images))
+}
+
+// providerParam returns the provider path parameter
+func providerParam(c *fiber.Ctx) (models.ProviderID, error) {
+	providerID := models.ProviderID(c.Params("provider"))
+	if !providerID.IsValid() {
+		return "", fmt.Errorf("unknown provider: %q", providerID)
+	}
+	return providerID, nil
+}
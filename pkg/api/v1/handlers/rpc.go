@@ -50,10 +50,11 @@ type RPCError struct {
 
 // RPCHandler handles RPC-style API requests for projects and tasks
 type RPCHandler struct {
-	ProjectHandlers *ProjectHandlers
-	TaskHandlers    *TaskHandlers
-	UserHandlers    *UserHandler
-	SSHKeyHandlers  *SSHKeyHandlers
+	ProjectHandlers  *ProjectHandlers
+	TaskHandlers     *TaskHandlers
+	UserHandlers     *UserHandler
+	SSHKeyHandlers   *SSHKeyHandlers
+	PlaybookHandlers *PlaybookHandlers
 }
 
 // HandleRPC handles all RPC-style API requests for projects, tasks, and users
@@ -76,14 +77,20 @@ type RPCHandler struct {
 // - user.get: Get users or a single user by username
 // - user.get.id: Get a user by ID
 // - user.delete: Delete a user
+// - user.setWebhook: Set the webhook URL of a user
 //
 // SSH Key methods:
 // - sshkey.create: Create a new SSH key
 // - sshkey.list: List SSH keys for an owner
 // - sshkey.delete: Delete an SSH key
 //
+// Playbook methods:
+// - playbook.create: Upload a playbook with its roles
+// - playbook.list: List the registered playbooks and the playbooks of an owner
+// - playbook.delete: Delete an uploaded playbook
+//
 // @Summary Handle RPC requests
-// @Description Process RPC-style API requests for projects, tasks, and users. The RPC endpoint supports the following methods: Project methods: project.create (Create a new project), project.get (Get a project by name), project.list (List all projects), project.delete (Delete a project), project.listInstances (List instances for a project). Task methods: task.get (Get a task by ID), task.list (List tasks for a project), task.terminate (Terminate a running task). User methods: user.create (Create a new user), user.get (Get users or a single user by username), user.get.id (Get a user by ID), user.delete (Delete a user).
+// @Description Process RPC-style API requests for projects, tasks, and users. The RPC endpoint supports the following methods: Project methods: project.create (Create a new project), project.get (Get a project by name), project.list (List all projects), project.delete (Delete a project), project.listInstances (List instances for a project). Task methods: task.get (Get a task by ID), task.list (List tasks for a project), task.terminate (Terminate a running task). User methods: user.create (Create a new user), user.get (Get users or a single user by username), user.get.id (Get a user by ID), user.delete (Delete a user), user.setWebhook (Set the webhook URL of a user). Playbook methods: playbook.create (Upload a playbook with its roles), playbook.list (List the registered playbooks and the playbooks of an owner), playbook.delete (Delete an uploaded playbook).
 // @Tags rpc
 // @Accept json
 // @Produce json
@@ -113,6 +120,8 @@ func (h *RPCHandler) HandleRPC(c *fiber.Ctx) error {
 		return h.handleUserMethod(c, req)
 	case IsSSHKeyMethod(req.Method):
 		return h.handleSSHKeyMethod(c, req)
+	case IsPlaybookMethod(req.Method):
+		return h.handlePlaybookMethod(c, req)
 	default:
 		return respondWithRPCError(c, fiber.StatusBadRequest, "Unknown method", nil, req.ID)
 	}
@@ -175,6 +184,8 @@ func (h *RPCHandler) handleUserMethod(c *fiber.Ctx, req RPCRequest) error {
 		return h.UserHandlers.GetUserByID(c, req)
 	case UserDelete:
 		return h.UserHandlers.DeleteUser(c, req)
+	case UserSetWebhook:
+		return h.UserHandlers.SetWebhook(c, req)
 	default:
 		return respondWithRPCError(c, fiber.StatusBadRequest, "Unknown user method", nil, req.ID)
 	}
@@ -198,6 +209,24 @@ func (h *RPCHandler) handleSSHKeyMethod(c *fiber.Ctx, req RPCRequest) error {
 	}
 }
 
+// handlePlaybookMethod routes playbook methods to their respective handlers
+func (h *RPCHandler) handlePlaybookMethod(c *fiber.Ctx, req RPCRequest) error {
+	if h.PlaybookHandlers == nil {
+		return respondWithRPCError(c, fiber.StatusInternalServerError, "Playbook handlers not configured", nil, req.ID)
+	}
+
+	switch req.Method {
+	case PlaybookCreate:
+		return h.PlaybookHandlers.Create(c, req)
+	case PlaybookList:
+		return h.PlaybookHandlers.List(c, req)
+	case PlaybookDelete:
+		return h.PlaybookHandlers.Delete(c, req)
+	default:
+		return respondWithRPCError(c, fiber.StatusBadRequest, "Unknown playbook method", nil, req.ID)
+	}
+}
+
 // parseParams is a helper function to parse RPC parameters into a specific struct type
 func parseParams[T any](req RPCRequest) (T, error) {
 	var params T
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	fiber "github.com/gofiber/fiber/v2"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+	"github.com/celestiaorg/talis/internal/logger"
+	"github.com/celestiaorg/talis/internal/services"
+)
+
+// EventStreamContentType is the content type of Server-Sent Events streams
+const EventStreamContentType = "text/event-stream"
+
+// sseKeepAliveInterval is how often a comment is sent on idle event streams, so that proxies
+// keep the connection open and disconnected clients are noticed
+const sseKeepAliveInterval = 15 * time.Second
+
+// acceptsEventStream reports whether the client asked for a Server-Sent Events stream
+func acceptsEventStream(c *fiber.Ctx) bool {
+	return strings.Contains(c.Get(fiber.HeaderAccept), EventStreamContentType)
+}
+
+// lastEventID returns the ID sent by a client resuming an event stream, 0 if there is none
+func lastEventID(c *fiber.Ctx) int {
+	id, err := strconv.Atoi(c.Get("Last-Event-ID"))
+	if err != nil || id < 0 {
+		return 0
+	}
+	return id
+}
+
+// streamEvents streams the task messages selected by the filter as Server-Sent Events.
+// The stream runs after the handler returns, it stops once the client disconnects.
+func (h *TaskHandlers) streamEvents(c *fiber.Ctx, filter services.TaskStreamFilter, afterID uint) error {
+	c.Set(fiber.HeaderContentType, EventStreamContentType)
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// Messages and keep-alives are written from different goroutines, a failed write means
+		// that the client is gone
+		var mu sync.Mutex
+		write := func(frame string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if _, err := w.WriteString(frame); err != nil {
+				cancel()
+				return err
+			}
+			if err := w.Flush(); err != nil {
+				cancel()
+				return err
+			}
+			return nil
+		}
+
+		go func() {
+			ticker := time.NewTicker(sseKeepAliveInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					_ = write(": keep-alive\n\n")
+				}
+			}
+		}()
+
+		err := h.task.StreamEvents(ctx, filter, afterID, func(msg models.TaskStreamMessage) error {
+			frame, err := sseFrame(msg)
+			if err != nil {
+				return err
+			}
+			return write(frame)
+		})
+		if err != nil && !errors.Is(err, context.Canceled) && ctx.Err() == nil {
+			logger.Warnf("Task event stream failed: %v", err)
+			if frame, err := sseFrame(models.TaskStreamMessage{Type: models.TaskStreamError, Error: err.Error()}); err == nil {
+				_ = write(frame)
+			}
+		}
+	})
+	return nil
+}
+
+// sseFrame renders a task message as a Server-Sent Event. Task events carry their ID so that
+// clients can resume the stream after them.
+func sseFrame(msg models.TaskStreamMessage) (string, error) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal task stream message: %w", err)
+	}
+
+	var b strings.Builder
+	if msg.Event != nil {
+		fmt.Fprintf(&b, "id: %d\n", msg.Event.ID)
+	}
+	fmt.Fprintf(&b, "event: %s\ndata: %s\n\n", msg.Type, data)
+	return b.String(), nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/celestiaorg/talis/internal/db/models"
+	"github.com/celestiaorg/talis/internal/services"
 	"github.com/celestiaorg/talis/internal/types"
 	"gorm.io/gorm"
 
@@ -25,7 +26,7 @@ func NewTaskHandlers(api *APIHandler) *TaskHandlers {
 
 // Get godoc
 // @Summary Get task by ID
-// @Description Retrieves a task by its ID via RPC
+// @Description Retrieves a task by its ID via RPC. Its logs are rendered from its last 200 events, list all of them from the task events endpoint.
 // @Tags tasks,rpc
 // @Accept json
 // @Produce json
@@ -52,6 +53,9 @@ func (h *TaskHandlers) Get(c *fiber.Ctx, ownerID uint, req RPCRequest) error {
 		}
 		return respondWithRPCError(c, fiber.StatusInternalServerError, ErrMsgTaskGetFailed, err.Error(), req.ID)
 	}
+	if err := h.task.DeriveLogs(c.Context(), task); err != nil {
+		return respondWithRPCError(c, fiber.StatusInternalServerError, ErrMsgTaskGetFailed, err.Error(), req.ID)
+	}
 
 	return c.JSON(RPCResponse{
 		Data:    task,
@@ -62,7 +66,7 @@ func (h *TaskHandlers) Get(c *fiber.Ctx, ownerID uint, req RPCRequest) error {
 
 // List godoc
 // @Summary List tasks for a project
-// @Description Returns a list of tasks for a specific project with pagination via RPC
+// @Description Returns a list of tasks for a specific project with pagination via RPC. Logs are not rendered from task events, list them from the task events endpoint.
 // @Tags tasks,rpc
 // @Accept json
 // @Produce json
@@ -110,13 +114,15 @@ func (h *TaskHandlers) List(c *fiber.Ctx, ownerID uint, req RPCRequest) error {
 
 // Terminate godoc
 // @Summary Terminate a task
-// @Description Terminates a running task via RPC
+// @Description Terminates a pending or running task via RPC. Work in progress is cancelled and a partially created instance is rolled back.
 // @Tags tasks,rpc
 // @Accept json
 // @Produce json
 // @Param request body RPCRequest true "RPC request with TaskTerminateParams"
 // @Success 200 {object} RPCResponse "Task terminated successfully"
 // @Failure 400 {object} RPCResponse "Invalid parameters"
+// @Failure 404 {object} RPCResponse "Task not found"
+// @Failure 409 {object} RPCResponse "Task has already finished"
 // @Failure 500 {object} RPCResponse "Internal server error"
 // @OperationId terminateTask
 func (h *TaskHandlers) Terminate(c *fiber.Ctx, ownerID uint, req RPCRequest) error {
@@ -129,7 +135,13 @@ func (h *TaskHandlers) Terminate(c *fiber.Ctx, ownerID uint, req RPCRequest) err
 		return respondWithRPCError(c, fiber.StatusBadRequest, err.Error(), nil, req.ID)
 	}
 
-	if err := h.task.UpdateStatus(c.Context(), ownerID, params.TaskID, models.TaskStatusTerminated); err != nil {
+	if err := h.task.Terminate(c.Context(), ownerID, params.TaskID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return respondWithRPCError(c, fiber.StatusNotFound, ErrMsgTaskNotFound, err.Error(), req.ID)
+		}
+		if errors.Is(err, services.ErrTaskFinished) {
+			return respondWithRPCError(c, fiber.StatusConflict, ErrMsgTaskAlreadyFinished, err.Error(), req.ID)
+		}
 		return respondWithRPCError(c, fiber.StatusInternalServerError, ErrMsgTaskTerminateFailed, err.Error(), req.ID)
 	}
 
@@ -141,7 +153,7 @@ func (h *TaskHandlers) Terminate(c *fiber.Ctx, ownerID uint, req RPCRequest) err
 
 // ListByInstanceID godoc
 // @Summary List tasks for an instance
-// @Description Returns a list of tasks for a specific instance with optional filtering and pagination
+// @Description Returns a list of tasks for a specific instance with optional filtering and pagination. Logs are not rendered from task events, list them from the task events endpoint.
 // @Tags tasks
 // @Accept json
 // @Produce json
@@ -200,3 +212,219 @@ func (h *TaskHandlers) ListByInstanceID(c *fiber.Ctx) error {
 		},
 	}))
 }
+
+// ListDead godoc
+// @Summary List dead tasks
+// @Description Returns the tasks of all owners that used up the attempts of their retry policy, oldest first.
+// @Description Dead tasks are not processed again unless they are requeued. Logs are not rendered from task events, list them from the task events endpoint.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param limit query int false "Number of items to return (default 10)" example(10)
+// @Param offset query int false "Number of items to skip (default 0)" example(0)
+// @Success 200 {object} types.SuccessResponse{data=types.TaskListResponse} "List of dead tasks"
+// @Failure 400 {object} types.ErrorResponse "Invalid input"
+// @Failure 500 {object} types.ErrorResponse "Internal server error"
+// @Router /admin/tasks/dead [get]
+// @OperationId listDeadTasks
+func (h *TaskHandlers) ListDead(c *fiber.Ctx) error {
+	opts := &models.ListOptions{
+		Limit:  c.QueryInt("limit", DefaultPageSize),
+		Offset: c.QueryInt("offset", 0),
+	}
+	if opts.Limit < 0 || opts.Offset < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrInvalidInput("limit and offset must be non-negative numbers"))
+	}
+
+	tasks, err := h.task.ListDead(c.Context(), opts)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrServer("Failed to retrieve dead tasks"))
+	}
+
+	page := 0
+	if opts.Limit > 0 {
+		page = (opts.Offset / opts.Limit) + 1
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.Success(types.ListResponse[models.Task]{
+		Rows: tasks,
+		Pagination: types.PaginationResponse{
+			Total:  len(tasks),
+			Limit:  opts.Limit,
+			Offset: opts.Offset,
+			Page:   page,
+		},
+	}))
+}
+
+// Requeue godoc
+// @Summary Requeue a dead task
+// @Description Puts a dead task back to pending so that it is processed again with a fresh set of attempts.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param task_id path int true "Task ID"
+// @Success 200 {object} types.SuccessResponse "Task requeued"
+// @Failure 400 {object} types.ErrorResponse "Invalid input"
+// @Failure 404 {object} types.ErrorResponse "Dead task not found"
+// @Failure 500 {object} types.ErrorResponse "Internal server error"
+// @Router /admin/tasks/{task_id}/requeue [post]
+// @OperationId requeueTask
+func (h *TaskHandlers) Requeue(c *fiber.Ctx) error {
+	taskID, err := c.ParamsInt("task_id")
+	if err != nil || taskID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrInvalidInput("Invalid or missing task_id parameter"))
+	}
+
+	if err := h.task.Requeue(c.Context(), uint(taskID)); err != nil {
+		if errors.Is(err, services.ErrTaskNotDead) {
+			return c.Status(fiber.StatusNotFound).JSON(types.ErrNotFound(ErrMsgDeadTaskNotFound))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrServer(ErrMsgTaskRequeueFailed))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.Success(nil))
+}
+
+// ListEvents godoc
+// @Summary List or stream the events of a task
+// @Description Returns the events of a task, oldest first, with pagination.
+// @Description Events are only ever appended: pass the ID of the last event received as after_id to only get the events recorded since.
+// @Description With an "Accept: text/event-stream" header, the events and status changes of the task are streamed as Server-Sent Events instead.
+// @Description The stream sends "event" and "status" events as they happen and ends with an "end" event once the task is finished.
+// @Description The ID of each "event" event is the ID of the task event, the Last-Event-ID header resumes a stream.
+// @Tags tasks
+// @Accept json
+// @Produce json,text/event-stream
+// @Param task_id path int true "Task ID"
+// @Param after_id query int false "Only return events with a greater ID (default 0)" example(0)
+// @Param limit query int false "Number of items to return (default 10), ignored by streams" example(10)
+// @Param offset query int false "Number of items to skip (default 0), ignored by streams" example(0)
+// @Success 200 {object} types.SuccessResponse{data=types.TaskEventListResponse} "List of task events, or a stream of models.TaskStreamMessage"
+// @Failure 400 {object} types.ErrorResponse "Invalid input"
+// @Failure 404 {object} types.ErrorResponse "Task not found"
+// @Failure 500 {object} types.ErrorResponse "Internal server error"
+// @Router /tasks/{task_id}/events [get]
+// @OperationId listTaskEvents
+func (h *TaskHandlers) ListEvents(c *fiber.Ctx) error {
+	if acceptsEventStream(c) {
+		return h.streamTaskEvents(c)
+	}
+
+	// TODO: should check for OwnerID and filter by it
+	ownerID := models.AdminID
+
+	taskID, err := c.ParamsInt("task_id")
+	if err != nil || taskID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrInvalidInput("Invalid or missing task_id parameter"))
+	}
+	afterID := c.QueryInt("after_id", 0)
+	opts := &models.ListOptions{
+		Limit:  c.QueryInt("limit", DefaultPageSize),
+		Offset: c.QueryInt("offset", 0),
+	}
+	if afterID < 0 || opts.Limit < 0 || opts.Offset < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrInvalidInput("after_id, limit and offset must be non-negative numbers"))
+	}
+
+	if _, err := h.task.Get(c.Context(), ownerID, uint(taskID)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(types.ErrNotFound(ErrMsgTaskNotFound))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrServer(ErrMsgTaskGetFailed))
+	}
+
+	events, err := h.task.ListEvents(c.Context(), ownerID, uint(taskID), uint(afterID), opts)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrServer("Failed to retrieve task events"))
+	}
+
+	page := 0
+	if opts.Limit > 0 {
+		page = (opts.Offset / opts.Limit) + 1
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.Success(types.ListResponse[models.TaskEvent]{
+		Rows: events,
+		Pagination: types.PaginationResponse{
+			Total:  len(events),
+			Limit:  opts.Limit,
+			Offset: opts.Offset,
+			Page:   page,
+		},
+	}))
+}
+
+// streamTaskEvents streams the events and status changes of a task as Server-Sent Events,
+// resumed after the after_id query parameter or the Last-Event-ID header
+func (h *TaskHandlers) streamTaskEvents(c *fiber.Ctx) error {
+	// TODO: should check for OwnerID and filter by it
+	ownerID := models.AdminID
+
+	taskID, err := c.ParamsInt("task_id")
+	if err != nil || taskID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrInvalidInput("Invalid or missing task_id parameter"))
+	}
+	afterID := c.QueryInt("after_id", lastEventID(c))
+	if afterID < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrInvalidInput("after_id must be a non-negative number"))
+	}
+
+	if _, err := h.task.Get(c.Context(), ownerID, uint(taskID)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(types.ErrNotFound(ErrMsgTaskNotFound))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrServer(ErrMsgTaskGetFailed))
+	}
+
+	return h.streamEvents(c, services.TaskStreamFilter{OwnerID: ownerID, TaskID: uint(taskID)}, uint(afterID))
+}
+
+// StreamProjectEvents godoc
+// @Summary Stream the events of the tasks of a project
+// @Description Streams the events and status changes of all the tasks of a project as Server-Sent Events, until the client disconnects.
+// @Description Without after_id or a Last-Event-ID header, only the events recorded after the stream started are sent.
+// @Tags tasks
+// @Produce text/event-stream
+// @Param project_name path string true "Project name"
+// @Param owner_id query int true "Owner of the project"
+// @Param after_id query int false "Only send events with a greater ID"
+// @Success 200 {object} models.TaskStreamMessage "Stream of task messages"
+// @Failure 400 {object} types.ErrorResponse "Invalid input"
+// @Failure 404 {object} types.ErrorResponse "Project not found"
+// @Failure 500 {object} types.ErrorResponse "Internal server error"
+// @Router /projects/{project_name}/tasks/events [get]
+// @OperationId streamProjectTaskEvents
+func (h *TaskHandlers) StreamProjectEvents(c *fiber.Ctx) error {
+	projectName := c.Params("project_name")
+	if projectName == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrInvalidInput(ErrMsgProjNameRequired))
+	}
+	ownerID := c.QueryInt("owner_id", 0)
+	if ownerID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrInvalidInput(ErrMsgProjOwnerIDRequired))
+	}
+	afterID := c.QueryInt("after_id", lastEventID(c))
+	if afterID < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrInvalidInput("after_id must be a non-negative number"))
+	}
+
+	project, err := h.project.GetByName(c.Context(), uint(ownerID), projectName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(types.ErrNotFound(ErrMsgProjNotFound))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrServer(ErrMsgProjGetFailed))
+	}
+
+	// Only follow new events unless the client resumes a stream
+	if afterID == 0 {
+		lastID, err := h.task.LastProjectEventID(c.Context(), uint(ownerID), project.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(types.ErrServer("Failed to retrieve task events"))
+		}
+		afterID = int(lastID)
+	}
+
+	return h.streamEvents(c, services.TaskStreamFilter{OwnerID: uint(ownerID), ProjectID: project.ID}, uint(afterID))
+}
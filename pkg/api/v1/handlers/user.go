@@ -7,6 +7,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/celestiaorg/talis/internal/db/models"
+	"github.com/celestiaorg/talis/internal/services"
 	"github.com/celestiaorg/talis/internal/types"
 )
 
@@ -220,3 +221,47 @@ func (h *UserHandler) DeleteUser(c *fiber.Ctx, req RPCRequest) error {
 		ID:      req.ID,
 	})
 }
+
+// SetWebhook godoc
+// @Summary Set the webhook of a user
+// @Description Sets the URL the task and instance status changes of a user are sent to via RPC.
+// @Description A new signing secret is generated and returned, an empty URL disables the webhook.
+// @Tags users,rpc
+// @Accept json
+// @Produce json
+// @Param request body RPCRequest true "RPC request with UserSetWebhookParams"
+// @Success 200 {object} RPCResponse{data=types.SetWebhookResponse} "Webhook signing secret"
+// @Failure 400 {object} RPCResponse "Invalid parameters"
+// @Failure 404 {object} RPCResponse "User not found"
+// @Failure 500 {object} RPCResponse "Internal server error"
+// @OperationId setUserWebhook
+func (h *UserHandler) SetWebhook(c *fiber.Ctx, req RPCRequest) error {
+	params, err := parseParams[UserSetWebhookParams](req)
+	if err != nil {
+		return respondWithRPCError(c, fiber.StatusBadRequest, ErrMsgInvalidParams, err.Error(), req.ID)
+	}
+
+	if err = params.Validate(); err != nil {
+		return respondWithRPCError(c, fiber.StatusBadRequest, err.Error(), nil, req.ID)
+	}
+
+	secret, err := h.user.SetWebhook(c.Context(), params.ID, params.URL)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return respondWithRPCError(c, fiber.StatusNotFound, ErrMsgUserNotFoundByID, nil, req.ID)
+	}
+	if errors.Is(err, services.ErrInvalidWebhook) {
+		return respondWithRPCError(c, fiber.StatusBadRequest, err.Error(), nil, req.ID)
+	}
+	if err != nil {
+		return respondWithRPCError(c, fiber.StatusInternalServerError, ErrMsgSetWebhookFailed, err.Error(), req.ID)
+	}
+
+	return c.JSON(RPCResponse{
+		Data: types.SetWebhookResponse{
+			WebhookURL:    params.URL,
+			WebhookSecret: secret,
+		},
+		Success: true,
+		ID:      req.ID,
+	})
+}
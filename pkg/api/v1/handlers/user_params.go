@@ -4,6 +4,7 @@ package handlers
 import (
 	"fmt"
 	"net/mail"
+	"net/url"
 	"strings"
 
 	"github.com/celestiaorg/talis/internal/db/models"
@@ -70,3 +71,23 @@ func (p DeleteUserParams) Validate() error {
 	}
 	return nil
 }
+
+// UserSetWebhookParams defines the parameters for setting the webhook of a user
+type UserSetWebhookParams struct {
+	ID  uint   `json:"id"`
+	URL string `json:"url"` // Empty to disable the webhook
+}
+
+// Validate validates the parameters for setting the webhook of a user
+func (p UserSetWebhookParams) Validate() error {
+	if p.ID <= 0 {
+		return fmt.Errorf("%s", strings.ToLower(ErrMsgUserIDRequired))
+	}
+	if p.URL != "" {
+		u, err := url.Parse(p.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%s", strings.ToLower(ErrMsgInvalidWebhookURL))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	fiber "github.com/gofiber/fiber/v2"
+
+	"github.com/celestiaorg/talis/internal/db/models"
+	"github.com/celestiaorg/talis/internal/types"
+)
+
+// WebhookHandler handles HTTP requests for webhook deliveries
+type WebhookHandler struct {
+	*APIHandler
+}
+
+// NewWebhookHandler creates a new webhook handler instance
+func NewWebhookHandler(api *APIHandler) *WebhookHandler {
+	return &WebhookHandler{
+		APIHandler: api,
+	}
+}
+
+// ListDeliveries godoc
+// @Summary List webhook deliveries
+// @Description Returns the webhook deliveries of an owner with their attempts, newest first.
+// @Description A delivery is queued for every status change of a task or instance of an owner with a webhook and retried until the webhook acknowledges it with a 2xx response or its attempts are used up.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param owner_id query int true "Owner of the deliveries"
+// @Param task_id query int false "Only list the deliveries of a task"
+// @Param instance_id query int false "Only list the deliveries of an instance"
+// @Param limit query int false "Number of items to return (default 10)" example(10)
+// @Param offset query int false "Number of items to skip (default 0)" example(0)
+// @Success 200 {object} types.SuccessResponse{data=types.WebhookDeliveryListResponse} "List of webhook deliveries"
+// @Failure 400 {object} types.ErrorResponse "Invalid input"
+// @Failure 500 {object} types.ErrorResponse "Internal server error"
+// @Router /webhooks/deliveries [get]
+// @OperationId listWebhookDeliveries
+func (h *WebhookHandler) ListDeliveries(c *fiber.Ctx) error {
+	ownerID := c.QueryInt("owner_id", 0)
+	if ownerID <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrInvalidInput("owner_id is required"))
+	}
+	taskID := c.QueryInt("task_id", 0)
+	instanceID := c.QueryInt("instance_id", 0)
+	if taskID < 0 || instanceID < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrInvalidInput("task_id and instance_id must be non-negative numbers"))
+	}
+
+	opts := &models.ListOptions{
+		Limit:  c.QueryInt("limit", DefaultPageSize),
+		Offset: c.QueryInt("offset", 0),
+	}
+	if opts.Limit < 0 || opts.Offset < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(types.ErrInvalidInput("limit and offset must be non-negative numbers"))
+	}
+
+	deliveries, err := h.webhook.ListDeliveries(c.Context(), uint(ownerID), uint(taskID), uint(instanceID), opts)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(types.ErrServer("Failed to retrieve webhook deliveries"))
+	}
+
+	page := 0
+	if opts.Limit > 0 {
+		page = (opts.Offset / opts.Limit) + 1
+	}
+
+	return c.Status(fiber.StatusOK).JSON(types.Success(types.ListResponse[models.WebhookDelivery]{
+		Rows: deliveries,
+		Pagination: types.PaginationResponse{
+			Total:  len(deliveries),
+			Limit:  opts.Limit,
+			Offset: opts.Offset,
+			Page:   page,
+		},
+	}))
+}
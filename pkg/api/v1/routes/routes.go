@@ -42,18 +42,38 @@ const (
 	// No unique names, consider making Admin a private ownerID.
 	AdminGetInstances         = "AdminGetInstances"
 	AdminGetInstancesMetadata = "AdminGetInstancesMetadata"
+	AdminListDriftEvents      = "AdminListDriftEvents"
+	AdminListDeadTasks        = "AdminListDeadTasks"
+	AdminRequeueTask          = "AdminRequeueTask"
 
 	// Health check
 	HealthCheck = "HealthCheck"
 
 	// Instance routes
-	GetInstances       = "GetInstances"
-	GetMetadata        = "GetMetadata"
-	GetPublicIPs       = "GetPublicIPs"
-	GetInstance        = "GetInstance"
-	CreateInstance     = "CreateInstance"
-	TerminateInstances = "TerminateInstances"
-	ListInstanceTasks  = "ListInstanceTasks"
+	GetInstances            = "GetInstances"
+	GetMetadata             = "GetMetadata"
+	GetPublicIPs            = "GetPublicIPs"
+	GetInstance             = "GetInstance"
+	GetInstancePayload      = "GetInstancePayload"
+	CreateInstance          = "CreateInstance"
+	ImportInstances         = "ImportInstances"
+	TerminateInstances      = "TerminateInstances"
+	ListInstanceTasks       = "ListInstanceTasks"
+	ListInstanceDriftEvents = "ListInstanceDriftEvents"
+
+	// Project routes
+	StreamProjectTaskEvents = "StreamProjectTaskEvents"
+
+	// Task routes
+	ListTaskEvents = "ListTaskEvents"
+
+	// Provider routes
+	GetProviderImages  = "GetProviderImages"
+	GetProviderRegions = "GetProviderRegions"
+	GetProviderSizes   = "GetProviderSizes"
+
+	// Webhook routes
+	ListWebhookDeliveries = "ListWebhookDeliveries"
 
 	// RPC routes
 	RPC = "RPC"
@@ -73,8 +93,10 @@ var (
 func RegisterRoutes(
 	app *fiber.App,
 	instanceHandler *handlers.InstanceHandler,
+	providerHandler *handlers.ProviderHandler,
 	rpcHandler *handlers.RPCHandler,
 	taskHandler *handlers.TaskHandlers,
+	webhookHandler *handlers.WebhookHandler,
 ) {
 	// Register Swagger routes
 	RegisterSwaggerRoutes(app)
@@ -87,6 +109,12 @@ func RegisterRoutes(
 	adminInstances := v1.Group("/admin/instances")
 	adminInstances.Get("/", instanceHandler.ListInstances).Name(AdminGetInstances)
 	adminInstances.Get("/all-metadata", instanceHandler.GetAllMetadata).Name(AdminGetInstancesMetadata)
+	adminInstances.Get("/drift-events", instanceHandler.ListDriftEvents).Name(AdminListDriftEvents)
+
+	// Admin endpoints for tasks of all owners
+	adminTasks := v1.Group("/admin/tasks")
+	adminTasks.Get("/dead", taskHandler.ListDead).Name(AdminListDeadTasks)
+	adminTasks.Post("/:task_id/requeue", taskHandler.Requeue).Name(AdminRequeueTask)
 
 	// Health check
 	app.Get("/health", func(c *fiber.Ctx) error {
@@ -100,12 +128,37 @@ func RegisterRoutes(
 	instances.Get("/all-metadata", instanceHandler.GetAllMetadata).Name(GetMetadata)
 	instances.Get("/public-ips", instanceHandler.GetPublicIPs).Name(GetPublicIPs)
 	instances.Get("/:id", instanceHandler.GetInstance).Name(GetInstance)
+	instances.Get("/:id/payload", instanceHandler.GetInstancePayload).Name(GetInstancePayload)
 	instances.Post("/", instanceHandler.CreateInstance).Name(CreateInstance)
+	instances.Post("/import", instanceHandler.ImportInstances).Name(ImportInstances)
 	instances.Delete("/", instanceHandler.TerminateInstances).Name(TerminateInstances)
 
 	// Tasks for a specific instance
 	instances.Get("/:instance_id/tasks", taskHandler.ListByInstanceID).Name(ListInstanceTasks)
 
+	// Drift events for a specific instance
+	instances.Get("/:instance_id/drift-events", instanceHandler.ListInstanceDriftEvents).Name(ListInstanceDriftEvents)
+
+	// Projects endpoints, other project operations are handled via RPC
+	projects := v1.Group("/projects")
+	projects.Get("/:project_name/tasks/events", taskHandler.StreamProjectEvents).Name(StreamProjectTaskEvents)
+
+	// Tasks endpoints
+	// TODO: These should be filtered by OwnerID
+	tasks := v1.Group("/tasks")
+	// Also streams the events when requested with an Accept: text/event-stream header
+	tasks.Get("/:task_id/events", taskHandler.ListEvents).Name(ListTaskEvents)
+
+	// Provider catalog endpoints
+	providers := v1.Group("/providers")
+	providers.Get("/:provider/images", providerHandler.ListImages).Name(GetProviderImages)
+	providers.Get("/:provider/regions", providerHandler.ListRegions).Name(GetProviderRegions)
+	providers.Get("/:provider/sizes", providerHandler.ListSizes).Name(GetProviderSizes)
+
+	// Webhook endpoints
+	webhooks := v1.Group("/webhooks")
+	webhooks.Get("/deliveries", webhookHandler.ListDeliveries).Name(ListWebhookDeliveries)
+
 	// RPC endpoint as the root handler for all operations
 	v1.Post("/", rpcHandler.HandleRPC).Name(RPC)
 }
@@ -120,11 +173,13 @@ func initRouteCache() {
 
 		// Create empty handlers for route registration
 		mockInstanceHandler := &handlers.InstanceHandler{}
+		mockProviderHandler := &handlers.ProviderHandler{}
 		mockRPCHandler := &handlers.RPCHandler{}
 		mockTaskHandler := &handlers.TaskHandlers{}
+		mockWebhookHandler := &handlers.WebhookHandler{}
 
 		// Register routes with mock handlers - project and task handlers are handled via RPC
-		RegisterRoutes(app, mockInstanceHandler, mockRPCHandler, mockTaskHandler)
+		RegisterRoutes(app, mockInstanceHandler, mockProviderHandler, mockRPCHandler, mockTaskHandler, mockWebhookHandler)
 
 		// Extract routes from the app
 		for _, route := range app.GetRoutes() {
@@ -187,6 +242,21 @@ func AdminInstancesMetadataURL() string {
 	return BuildURL(AdminGetInstancesMetadata, nil, nil)
 }
 
+// AdminListDriftEventsURL returns the URL for listing the drift events of all instances
+func AdminListDriftEventsURL(queryParams url.Values) string {
+	return BuildURL(AdminListDriftEvents, nil, queryParams)
+}
+
+// AdminListDeadTasksURL returns the URL for listing the dead tasks of all owners
+func AdminListDeadTasksURL(queryParams url.Values) string {
+	return BuildURL(AdminListDeadTasks, nil, queryParams)
+}
+
+// AdminRequeueTaskURL returns the URL for requeueing a dead task
+func AdminRequeueTaskURL(taskID string) string {
+	return BuildURL(AdminRequeueTask, map[string]string{"task_id": taskID}, nil)
+}
+
 // Health check route helper
 
 // HealthCheckURL returns the URL for the health check endpoint
@@ -216,11 +286,21 @@ func GetInstanceURL(id string) string {
 	return BuildURL(GetInstance, map[string]string{"id": id}, nil)
 }
 
+// GetInstancePayloadURL returns the URL for getting the payload of an instance by ID
+func GetInstancePayloadURL(id string) string {
+	return BuildURL(GetInstancePayload, map[string]string{"id": id}, nil)
+}
+
 // CreateInstanceURL returns the URL for creating an instance
 func CreateInstanceURL() string {
 	return BuildURL(CreateInstance, nil, nil)
 }
 
+// ImportInstancesURL returns the URL for importing existing provider instances
+func ImportInstancesURL() string {
+	return BuildURL(ImportInstances, nil, nil)
+}
+
 // TerminateInstancesURL returns the URL for terminating instances
 func TerminateInstancesURL() string {
 	return BuildURL(TerminateInstances, nil, nil)
@@ -231,6 +311,55 @@ func ListInstanceTasksURL(instanceID string, queryParams url.Values) string {
 	return BuildURL(ListInstanceTasks, map[string]string{"instance_id": instanceID}, queryParams)
 }
 
+// ListInstanceDriftEventsURL returns the URL for listing drift events for a specific instance.
+func ListInstanceDriftEventsURL(instanceID string, queryParams url.Values) string {
+	return BuildURL(ListInstanceDriftEvents, map[string]string{"instance_id": instanceID}, queryParams)
+}
+
+// Project route helpers
+
+// StreamProjectTaskEventsURL returns the URL for streaming the events of the tasks of a project
+func StreamProjectTaskEventsURL(projectName string, queryParams url.Values) string {
+	return BuildURL(StreamProjectTaskEvents, map[string]string{"project_name": projectName}, queryParams)
+}
+
+// Task route helpers
+
+// ListTaskEventsURL returns the URL for listing the events of a task
+func ListTaskEventsURL(taskID string, queryParams url.Values) string {
+	return BuildURL(ListTaskEvents, map[string]string{"task_id": taskID}, queryParams)
+}
+
+// StreamTaskEventsURL returns the URL for streaming the events of a task, the same as the one
+// listing them: streams are requested with an Accept: text/event-stream header
+func StreamTaskEventsURL(taskID string, queryParams url.Values) string {
+	return BuildURL(ListTaskEvents, map[string]string{"task_id": taskID}, queryParams)
+}
+
+// Provider route helpers
+
+// GetProviderImagesURL returns the URL for listing the images of a provider
+func GetProviderImagesURL(provider string) string {
+	return BuildURL(GetProviderImages, map[string]string{"provider": provider}, nil)
+}
+
+// GetProviderRegionsURL returns the URL for listing the regions of a provider
+func GetProviderRegionsURL(provider string) string {
+	return BuildURL(GetProviderRegions, map[string]string{"provider": provider}, nil)
+}
+
+// GetProviderSizesURL returns the URL for listing the sizes of a provider
+func GetProviderSizesURL(provider string) string {
+	return BuildURL(GetProviderSizes, map[string]string{"provider": provider}, nil)
+}
+
+// Webhook route helpers
+
+// ListWebhookDeliveriesURL returns the URL for listing webhook deliveries
+func ListWebhookDeliveriesURL(queryParams url.Values) string {
+	return BuildURL(ListWebhookDeliveries, nil, queryParams)
+}
+
 // RPC route helper
 
 // RPCURL returns the URL for the RPC endpoint
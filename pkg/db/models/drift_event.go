@@ -0,0 +1,23 @@
+// Package models contains PUBLIC aliases for database models and related types.
+//
+// NOTE: This package uses type aliases to internal definitions
+// as a temporary measure. This should be revisited
+// during a proper refactoring to define stable public types.
+package models
+
+import (
+	internalmodels "github.com/celestiaorg/talis/internal/db/models"
+)
+
+// DriftEventType describes how an instance diverged from its provider state.
+type DriftEventType = internalmodels.DriftEventType
+
+// Drift event type constants.
+const (
+	DriftEventMissing       DriftEventType = internalmodels.DriftEventMissing
+	DriftEventStatusChanged DriftEventType = internalmodels.DriftEventStatusChanged
+	DriftEventIPChanged     DriftEventType = internalmodels.DriftEventIPChanged
+)
+
+// DriftEvent records a difference between an instance and its provider state (public alias).
+type DriftEvent = internalmodels.DriftEvent
@@ -20,6 +20,8 @@ const (
 	InstanceStatusProvisioning InstanceStatus = internalmodels.InstanceStatusProvisioning
 	InstanceStatusReady        InstanceStatus = internalmodels.InstanceStatusReady
 	InstanceStatusTerminated   InstanceStatus = internalmodels.InstanceStatusTerminated
+	InstanceStatusStopped      InstanceStatus = internalmodels.InstanceStatusStopped
+	InstanceStatusFailed       InstanceStatus = internalmodels.InstanceStatusFailed
 )
 
 // PayloadStatus represents the state of a payload operation on an instance
@@ -39,6 +41,12 @@ const (
 // Instance represents a compute instance in the system (public alias)
 type Instance = internalmodels.Instance
 
+// InstancePayload is the outcome of the payload script of an instance (public alias)
+type InstancePayload = internalmodels.InstancePayload
+
+// MaxPayloadOutputSize is how much of each output stream of a payload script is kept
+const MaxPayloadOutputSize = internalmodels.MaxPayloadOutputSize
+
 // NOTE: Methods like String(), ParseInstanceStatus(), MarshalJSON(), UnmarshalJSON()
 // are defined on the original internal types and are used via the aliases.
 // DO NOT REDEFINE METHODS ON ALIAS TYPES HERE.
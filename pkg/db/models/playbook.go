@@ -0,0 +1,17 @@
+package models
+
+import (
+	internalmodels "github.com/celestiaorg/talis/internal/db/models"
+)
+
+// Playbook is an Ansible playbook uploaded to provision instances with (public alias).
+type Playbook = internalmodels.Playbook
+
+// PlaybookFiles holds the files of an uploaded playbook by their relative path (public alias).
+type PlaybookFiles = internalmodels.PlaybookFiles
+
+// Playbook constants.
+const (
+	PlaybookEntrypoint = internalmodels.PlaybookEntrypoint
+	MaxPlaybookSize    = internalmodels.MaxPlaybookSize
+)
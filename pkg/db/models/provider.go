@@ -23,6 +23,7 @@ const (
 	ProviderLinode   ProviderID = internalmodels.ProviderLinode
 	ProviderHetzner  ProviderID = internalmodels.ProviderHetzner
 	ProviderOVH      ProviderID = internalmodels.ProviderOVH
+	ProviderLocal    ProviderID = internalmodels.ProviderLocal
 
 	// Mock Providers
 	ProviderDOMock1 ProviderID = internalmodels.ProviderDOMock1
@@ -30,6 +31,15 @@ const (
 	ProviderMock3   ProviderID = internalmodels.ProviderMock3
 )
 
+// ProvisionerID represents how instances are provisioned
+type ProvisionerID = internalmodels.ProvisionerID
+
+// Provisioner constants
+const (
+	ProvisionerAnsible ProvisionerID = internalmodels.ProvisionerAnsible
+	ProvisionerSSH     ProvisionerID = internalmodels.ProvisionerSSH
+)
+
 // NOTE: Methods like IsValid, MarshalJSON, UnmarshalJSON are defined on the
 // original internal types and are used via the aliases.
 // DO NOT REDEFINE METHODS ON ALIAS TYPES HERE.
@@ -20,6 +20,7 @@ const (
 	TaskStatusCompleted  TaskStatus = internalmodels.TaskStatusCompleted
 	TaskStatusFailed     TaskStatus = internalmodels.TaskStatusFailed
 	TaskStatusTerminated TaskStatus = internalmodels.TaskStatusTerminated
+	TaskStatusDead       TaskStatus = internalmodels.TaskStatusDead
 )
 
 // TaskAction defines the type of action a task performs.
@@ -35,8 +36,30 @@ const (
 // Task represents a background task in the system (public alias).
 type Task = internalmodels.Task
 
-// TaskResult represents the result of a completed task (public alias).
-// type TaskResult = internalmodels.TaskResult // REMOVED - Result is json.RawMessage in internal Task struct
+// TaskResult represents the result of the last attempt of a task (public alias).
+type TaskResult = internalmodels.TaskResult
+
+// TaskStepResult represents the outcome of a step of a task (public alias).
+type TaskStepResult = internalmodels.TaskStepResult
+
+// AnsibleHostRecap represents the PLAY RECAP counts of a host (public alias).
+type AnsibleHostRecap = internalmodels.AnsibleHostRecap
+
+// ProvisionTaskResult represents the outcome of a provisioning task on a host (public alias).
+type ProvisionTaskResult = internalmodels.ProvisionTaskResult
+
+// ProvisionTaskStatus represents the outcome of a provisioning task on a host (public alias).
+type ProvisionTaskStatus = internalmodels.ProvisionTaskStatus
+
+// Provision task status constants (public aliases).
+const (
+	ProvisionTaskOk          ProvisionTaskStatus = internalmodels.ProvisionTaskOk
+	ProvisionTaskChanged     ProvisionTaskStatus = internalmodels.ProvisionTaskChanged
+	ProvisionTaskFailed      ProvisionTaskStatus = internalmodels.ProvisionTaskFailed
+	ProvisionTaskUnreachable ProvisionTaskStatus = internalmodels.ProvisionTaskUnreachable
+	ProvisionTaskSkipped     ProvisionTaskStatus = internalmodels.ProvisionTaskSkipped
+	ProvisionTaskIgnored     ProvisionTaskStatus = internalmodels.ProvisionTaskIgnored
+)
 
 // NOTE: Methods like String(), ParseTaskStatus(), MarshalJSON(), UnmarshalJSON()
 // are defined on the original internal types and are used via the aliases.
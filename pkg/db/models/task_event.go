@@ -0,0 +1,51 @@
+// Package models contains PUBLIC aliases for database models and related types.
+//
+// NOTE: This package uses type aliases to internal definitions
+// as a temporary measure. This should be revisited
+// during a proper refactoring to define stable public types.
+package models
+
+import (
+	internalmodels "github.com/celestiaorg/talis/internal/db/models"
+)
+
+// TaskEventLevel is the severity of a task event.
+type TaskEventLevel = internalmodels.TaskEventLevel
+
+// Task event level constants.
+const (
+	TaskEventInfo  TaskEventLevel = internalmodels.TaskEventInfo
+	TaskEventWarn  TaskEventLevel = internalmodels.TaskEventWarn
+	TaskEventError TaskEventLevel = internalmodels.TaskEventError
+)
+
+// TaskStep is the step of a task an event belongs to.
+type TaskStep = internalmodels.TaskStep
+
+// Task step constants.
+const (
+	TaskStepCreate    TaskStep = internalmodels.TaskStepCreate
+	TaskStepProvision TaskStep = internalmodels.TaskStepProvision
+	TaskStepPayload   TaskStep = internalmodels.TaskStepPayload
+	TaskStepTerminate TaskStep = internalmodels.TaskStepTerminate
+)
+
+// TaskEventFields holds the structured fields of a task event.
+type TaskEventFields = internalmodels.TaskEventFields
+
+// TaskEvent is an entry of the log of a task (public alias).
+type TaskEvent = internalmodels.TaskEvent
+
+// TaskStreamMessageType is the kind of a message of a task event stream.
+type TaskStreamMessageType = internalmodels.TaskStreamMessageType
+
+// Task stream message type constants.
+const (
+	TaskStreamStatus TaskStreamMessageType = internalmodels.TaskStreamStatus
+	TaskStreamEvent  TaskStreamMessageType = internalmodels.TaskStreamEvent
+	TaskStreamEnd    TaskStreamMessageType = internalmodels.TaskStreamEnd
+	TaskStreamError  TaskStreamMessageType = internalmodels.TaskStreamError
+)
+
+// TaskStreamMessage is a message pushed to the clients following the events of tasks (public alias).
+type TaskStreamMessage = internalmodels.TaskStreamMessage
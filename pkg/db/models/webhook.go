@@ -0,0 +1,33 @@
+package models
+
+import (
+	internalmodels "github.com/celestiaorg/talis/internal/db/models"
+)
+
+// WebhookEventType is the type of event a webhook is sent for (public alias).
+type WebhookEventType = internalmodels.WebhookEventType
+
+// Webhook event type constants.
+const (
+	WebhookEventTaskStatusChanged     WebhookEventType = internalmodels.WebhookEventTaskStatusChanged
+	WebhookEventInstanceStatusChanged WebhookEventType = internalmodels.WebhookEventInstanceStatusChanged
+)
+
+// WebhookDeliveryStatus represents the state of a webhook delivery (public alias).
+type WebhookDeliveryStatus = internalmodels.WebhookDeliveryStatus
+
+// Webhook delivery status constants.
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = internalmodels.WebhookDeliveryPending
+	WebhookDeliveryDelivered WebhookDeliveryStatus = internalmodels.WebhookDeliveryDelivered
+	WebhookDeliveryFailed    WebhookDeliveryStatus = internalmodels.WebhookDeliveryFailed
+)
+
+// WebhookDelivery is an event sent to the webhook of a user (public alias).
+type WebhookDelivery = internalmodels.WebhookDelivery
+
+// WebhookAttempt records a single attempt to send a webhook delivery (public alias).
+type WebhookAttempt = internalmodels.WebhookAttempt
+
+// WebhookPayload is the body of a webhook request (public alias).
+type WebhookPayload = internalmodels.WebhookPayload
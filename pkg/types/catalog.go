@@ -0,0 +1,14 @@
+package types
+
+import (
+	internaltypes "github.com/celestiaorg/talis/internal/types"
+)
+
+// ProviderRegion represents a region offered by a compute provider (public alias).
+type ProviderRegion = internaltypes.ProviderRegion
+
+// ProviderSize represents an instance size offered by a compute provider (public alias).
+type ProviderSize = internaltypes.ProviderSize
+
+// ProviderImage represents an image offered by a compute provider (public alias).
+type ProviderImage = internaltypes.ProviderImage
@@ -15,5 +15,8 @@ type InstanceRequest = internaltypes.InstanceRequest
 // DeleteInstancesRequest defines the structure for requesting instance deletion (public alias).
 type DeleteInstancesRequest = internaltypes.DeleteInstancesRequest
 
+// ImportInstancesRequest defines the structure for requesting the import of existing provider instances (public alias).
+type ImportInstancesRequest = internaltypes.ImportInstancesRequest
+
 // PublicIPsResponse defines the structure for the response containing public IPs (public alias).
 type PublicIPsResponse = internaltypes.PublicIPsResponse
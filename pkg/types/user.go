@@ -12,5 +12,8 @@ import (
 // UserResponse defines the structure for the response containing user details (public alias).
 type UserResponse = internaltypes.UserResponse
 
+// SetWebhookResponse defines the structure for the response after setting the webhook of a user (public alias).
+type SetWebhookResponse = internaltypes.SetWebhookResponse
+
 // CreateUserResponse defines the structure for the response after creating a user (public alias).
 type CreateUserResponse = internaltypes.CreateUserResponse
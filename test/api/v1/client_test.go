@@ -8,6 +8,9 @@
 // - Health check functionality
 // - Instance operations (creating, listing, deleting)
 // - Task operations (listing tasks by instance ID)
+// - Drift event operations (listing drift events)
+// - Payload operations (getting the payload status and output of an instance)
+// - Webhook operations (setting a webhook, listing deliveries)
 //
 // These tests use the test.Suite helper to set up a test environment with
 // a running API server and database, allowing for comprehensive testing of
@@ -16,7 +19,10 @@ package api_test
 
 import (
 	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"strconv"
 	"testing"
 	"time"
 
@@ -25,8 +31,10 @@ import (
 
 	"github.com/celestiaorg/talis/internal/constants"
 	"github.com/celestiaorg/talis/internal/db/models"
+	"github.com/celestiaorg/talis/internal/db/repos"
 	"github.com/celestiaorg/talis/internal/types"
 	"github.com/celestiaorg/talis/pkg/api/v1/handlers"
+	"github.com/celestiaorg/talis/pkg/api/v1/routes"
 	"github.com/celestiaorg/talis/test"
 )
 
@@ -414,3 +422,257 @@ func TestClient_ListTasksByInstanceID(t *testing.T) {
 	}
 	assert.True(t, foundTaskCreateInst2, "Manually added taskCreateInst2 not found for instance2")
 }
+
+func TestClient_DriftEvents(t *testing.T) {
+	suite := test.NewSuite(t)
+	defer suite.Cleanup()
+
+	// Create two instances directly, drift events are only recorded by the reconciler
+	var instances []*models.Instance
+	for i := 0; i < 2; i++ {
+		instance, err := suite.InstanceRepo.Create(suite.Context(), &models.Instance{
+			OwnerID:            models.AdminID,
+			ProviderID:         models.ProviderDO,
+			ProviderInstanceID: 100 + i,
+			Status:             models.InstanceStatusReady,
+		})
+		require.NoError(t, err)
+		instances = append(instances, instance)
+	}
+
+	driftRepo := repos.NewDriftEventRepository(suite.DB)
+	events := []*models.DriftEvent{
+		{OwnerID: models.AdminID, InstanceID: instances[0].ID, ProviderID: models.ProviderDO, ProviderInstanceID: 100, Type: models.DriftEventStatusChanged, OldValue: "ready", NewValue: "stopped", CreatedAt: time.Now().Add(-time.Minute)},
+		{OwnerID: models.AdminID, InstanceID: instances[0].ID, ProviderID: models.ProviderDO, ProviderInstanceID: 100, Type: models.DriftEventIPChanged, OldValue: "10.0.0.1", NewValue: "10.0.0.2", CreatedAt: time.Now()},
+		{OwnerID: models.AdminID, InstanceID: instances[1].ID, ProviderID: models.ProviderDO, ProviderInstanceID: 101, Type: models.DriftEventMissing, OldValue: "ready", NewValue: "terminated", CreatedAt: time.Now()},
+	}
+	for _, event := range events {
+		require.NoError(t, driftRepo.Create(suite.Context(), event))
+	}
+
+	all, err := suite.APIClient.AdminListDriftEvents(suite.Context(), nil)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+
+	byInstance, err := suite.APIClient.ListInstanceDriftEvents(suite.Context(), instances[0].ID, nil)
+	require.NoError(t, err)
+	require.Len(t, byInstance, 2)
+	// Newest first
+	assert.Equal(t, models.DriftEventIPChanged, byInstance[0].Type)
+	assert.Equal(t, "10.0.0.2", byInstance[0].NewValue)
+	assert.Equal(t, models.DriftEventStatusChanged, byInstance[1].Type)
+
+	paged, err := suite.APIClient.ListInstanceDriftEvents(suite.Context(), instances[0].ID, &models.ListOptions{Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, paged, 1)
+	assert.Equal(t, byInstance[1].ID, paged[0].ID)
+
+	none, err := suite.APIClient.ListInstanceDriftEvents(suite.Context(), 99999, nil)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func TestClient_InstancePayload(t *testing.T) {
+	suite := test.NewSuite(t)
+	defer suite.Cleanup()
+
+	// Payloads are only run by the workers, the instance and its payload output are stored directly
+	instance, err := suite.InstanceRepo.Create(suite.Context(), &models.Instance{
+		OwnerID:       models.AdminID,
+		ProviderID:    models.ProviderDO,
+		Status:        models.InstanceStatusProvisioning,
+		PayloadStatus: models.PayloadStatusPendingCopy,
+	})
+	require.NoError(t, err)
+
+	payload, err := suite.APIClient.GetInstancePayload(suite.Context(), instance.ID)
+	require.NoError(t, err)
+	assert.Equal(t, instance.ID, payload.InstanceID)
+	assert.Equal(t, models.PayloadStatusPendingCopy, payload.Status)
+	assert.Nil(t, payload.ExitCode)
+
+	exitCode := 1
+	require.NoError(t, suite.InstanceRepo.Update(suite.Context(), models.AdminID, instance.ID, &models.Instance{PayloadStatus: models.PayloadStatusExecutionFailed}))
+	require.NoError(t, suite.InstanceRepo.SavePayload(suite.Context(), &models.InstancePayload{
+		InstanceID: instance.ID, OwnerID: instance.OwnerID, ExitCode: &exitCode, Stdout: "syncing", Stderr: "disk full",
+	}))
+
+	payload, err = suite.APIClient.GetInstancePayload(suite.Context(), instance.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.PayloadStatusExecutionFailed, payload.Status)
+	require.NotNil(t, payload.ExitCode)
+	assert.Equal(t, 1, *payload.ExitCode)
+	assert.Equal(t, "syncing", payload.Stdout)
+	assert.Equal(t, "disk full", payload.Stderr)
+
+	_, err = suite.APIClient.GetInstancePayload(suite.Context(), 99999)
+	require.Error(t, err)
+}
+
+func TestClient_DeadTasks(t *testing.T) {
+	suite := test.NewSuite(t)
+	defer suite.Cleanup()
+
+	// Create the tasks directly, tasks only die once they used up their retries
+	var tasks []*models.Task
+	for _, status := range []models.TaskStatus{models.TaskStatusDead, models.TaskStatusDead, models.TaskStatusFailed} {
+		task := &models.Task{
+			OwnerID:   1,
+			ProjectID: 1,
+			Action:    models.TaskActionTerminateInstances,
+			Status:    status,
+			Attempts:  5,
+			Payload:   []byte(`{}`),
+		}
+		require.NoError(t, suite.TaskRepo.Create(suite.Context(), task))
+		tasks = append(tasks, task)
+	}
+
+	dead, err := suite.APIClient.AdminListDeadTasks(suite.Context(), nil)
+	require.NoError(t, err)
+	require.Len(t, dead, 2)
+	assert.Equal(t, tasks[0].ID, dead[0].ID)
+	assert.Equal(t, tasks[1].ID, dead[1].ID)
+
+	paged, err := suite.APIClient.AdminListDeadTasks(suite.Context(), &models.ListOptions{Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, paged, 1)
+	assert.Equal(t, tasks[1].ID, paged[0].ID)
+
+	require.NoError(t, suite.APIClient.AdminRequeueTask(suite.Context(), tasks[0].ID))
+	requeued, err := suite.TaskRepo.GetByID(suite.Context(), 1, tasks[0].ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, models.TaskStatusDead, requeued.Status)
+
+	events, err := suite.APIClient.ListTaskEvents(suite.Context(), tasks[0].ID, 0, nil)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Contains(t, events[0].Message, "requeued by an admin")
+
+	dead, err = suite.APIClient.AdminListDeadTasks(suite.Context(), nil)
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	assert.Equal(t, tasks[1].ID, dead[0].ID)
+
+	// Only dead tasks can be requeued
+	require.Error(t, suite.APIClient.AdminRequeueTask(suite.Context(), tasks[0].ID))
+	require.Error(t, suite.APIClient.AdminRequeueTask(suite.Context(), tasks[2].ID))
+	require.Error(t, suite.APIClient.AdminRequeueTask(suite.Context(), 99999))
+}
+
+// TestClient_StreamTaskEvents tests streaming the events of a task as Server-Sent Events,
+// through the client and through the raw endpoint resumed with a Last-Event-ID header
+func TestClient_StreamTaskEvents(t *testing.T) {
+	suite := test.NewSuite(t)
+	defer suite.Cleanup()
+
+	// A finished task, so that its stream ends right after its events
+	task := &models.Task{
+		OwnerID:   1,
+		ProjectID: 1,
+		Action:    models.TaskActionTerminateInstances,
+		Status:    models.TaskStatusCompleted,
+		Payload:   []byte(`{}`),
+	}
+	require.NoError(t, suite.TaskRepo.Create(suite.Context(), task))
+	events := []*models.TaskEvent{
+		{TaskID: task.ID, OwnerID: 1, Message: "first event"},
+		{TaskID: task.ID, OwnerID: 1, Message: "second event"},
+	}
+	require.NoError(t, suite.TaskRepo.AddEvents(suite.Context(), events...))
+
+	stream, err := suite.APIClient.StreamTaskEvents(suite.Context(), task.ID, 0)
+	require.NoError(t, err)
+	var messages []models.TaskStreamMessage
+	for msg := range stream {
+		messages = append(messages, msg)
+	}
+	require.Len(t, messages, 4)
+	assert.Equal(t, models.TaskStreamEvent, messages[0].Type)
+	assert.Equal(t, "first event", messages[0].Event.Message)
+	assert.Equal(t, models.TaskStreamEvent, messages[1].Type)
+	assert.Equal(t, "second event", messages[1].Event.Message)
+	assert.Equal(t, models.TaskStreamStatus, messages[2].Type)
+	assert.Equal(t, models.TaskStatusCompleted, messages[2].Status)
+	assert.Equal(t, models.TaskStreamEnd, messages[3].Type)
+
+	// Resuming after the first event only sends the events recorded since
+	endpoint := suite.Server.URL + routes.StreamTaskEventsURL(strconv.FormatUint(uint64(task.ID), 10), nil)
+	req, err := http.NewRequestWithContext(suite.Context(), http.MethodGet, endpoint, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", handlers.EventStreamContentType)
+	req.Header.Set("Last-Event-ID", strconv.FormatUint(uint64(events[0].ID), 10))
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, handlers.EventStreamContentType, resp.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), "first event")
+	assert.Contains(t, string(body), fmt.Sprintf("id: %d\nevent: event\n", events[1].ID))
+	assert.Contains(t, string(body), "second event")
+	assert.Contains(t, string(body), "event: end\n")
+
+	// Without the Accept header, the same URL lists the events
+	listed, err := suite.APIClient.ListTaskEvents(suite.Context(), task.ID, 0, nil)
+	require.NoError(t, err)
+	assert.Len(t, listed, 2)
+
+	// Unknown tasks are rejected before the stream starts
+	_, err = suite.APIClient.StreamTaskEvents(suite.Context(), task.ID+100, 0)
+	assert.Error(t, err)
+}
+
+func TestClient_WebhookDeliveries(t *testing.T) {
+	suite := test.NewSuite(t)
+	defer suite.Cleanup()
+
+	user, err := suite.APIClient.CreateUser(suite.Context(), handlers.CreateUserParams{Username: "webhook-user"})
+	require.NoError(t, err)
+
+	_, err = suite.APIClient.SetUserWebhook(suite.Context(), handlers.UserSetWebhookParams{ID: user.UserID, URL: "ftp://example.com"})
+	require.Error(t, err, "only http and https webhooks are accepted")
+
+	webhook, err := suite.APIClient.SetUserWebhook(suite.Context(), handlers.UserSetWebhookParams{ID: user.UserID, URL: "https://example.com/hook"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/hook", webhook.WebhookURL)
+	assert.NotEmpty(t, webhook.WebhookSecret)
+
+	// Tasks are created directly, status changes through the API are sent to the webhook
+	createTask := func() *models.Task {
+		task := &models.Task{
+			OwnerID:   user.UserID,
+			ProjectID: 1,
+			Action:    models.TaskActionCreateInstances,
+			Status:    models.TaskStatusPending,
+			Payload:   []byte(`{}`),
+		}
+		require.NoError(t, suite.TaskRepo.Create(suite.Context(), task))
+		return task
+	}
+	task := createTask()
+	require.NoError(t, suite.APIClient.TerminateTask(suite.Context(), handlers.TaskTerminateParams{TaskID: task.ID, OwnerID: user.UserID}))
+
+	deliveries, err := suite.APIClient.ListWebhookDeliveries(suite.Context(), user.UserID, task.ID, 0, nil)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, models.WebhookEventTaskStatusChanged, deliveries[0].Event)
+	assert.Equal(t, models.WebhookDeliveryPending, deliveries[0].Status)
+	assert.Equal(t, "https://example.com/hook", deliveries[0].URL)
+
+	// Deliveries of other owners are not listed
+	none, err := suite.APIClient.ListWebhookDeliveries(suite.Context(), user.UserID+1, task.ID, 0, nil)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+
+	// Disabling the webhook stops new deliveries
+	_, err = suite.APIClient.SetUserWebhook(suite.Context(), handlers.UserSetWebhookParams{ID: user.UserID})
+	require.NoError(t, err)
+	other := createTask()
+	require.NoError(t, suite.APIClient.TerminateTask(suite.Context(), handlers.TaskTerminateParams{TaskID: other.ID, OwnerID: user.UserID}))
+	deliveries, err = suite.APIClient.ListWebhookDeliveries(suite.Context(), user.UserID, 0, 0, nil)
+	require.NoError(t, err)
+	assert.Len(t, deliveries, 1)
+}
@@ -162,13 +162,24 @@ func TestTaskRPCMethods(t *testing.T) {
 	require.True(t, foundTask1, "First created task not found in list")
 	require.True(t, foundTask2, "Second created task not found in list")
 
-	// Terminate the first task
+	// The first task has already completed and cannot be terminated
 	terminateParams := handlers.TaskTerminateParams{TaskID: taskToCreate.ID, OwnerID: models.AdminID}
 	err = suite.APIClient.TerminateTask(suite.Context(), terminateParams)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), handlers.ErrMsgTaskAlreadyFinished)
+
+	getParams.TaskID = taskToCreate.ID
+	completedTask, err := suite.APIClient.GetTask(suite.Context(), getParams)
+	require.NoError(t, err)
+	require.Equal(t, models.TaskStatusCompleted, completedTask.Status)
+
+	// Terminate the running task
+	terminateParams.TaskID = secondTaskToCreate.ID
+	err = suite.APIClient.TerminateTask(suite.Context(), terminateParams)
 	require.NoError(t, err)
 
 	// Verify it's terminated
-	getParams.TaskID = taskToCreate.ID
+	getParams.TaskID = secondTaskToCreate.ID
 	terminatedTask, err := suite.APIClient.GetTask(suite.Context(), getParams)
 	require.NoError(t, err)
 	require.Equal(t, models.TaskStatusTerminated, terminatedTask.Status)
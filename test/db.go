@@ -54,6 +54,13 @@ func RunMigrations(db *gorm.DB) error {
 		&models.Project{},
 		&models.Task{},
 		&models.SSHKey{},
+		&models.DriftEvent{},
+		&models.Worker{},
+		&models.TaskEvent{},
+		&models.WebhookDelivery{},
+		&models.WebhookAttempt{},
+		&models.Playbook{},
+		&models.InstancePayload{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
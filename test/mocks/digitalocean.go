@@ -3,8 +3,11 @@ package mocks
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"strconv"
 
 	"github.com/digitalocean/godo"
 
@@ -20,6 +23,7 @@ type MockDOClient struct {
 	MockDropletService *MockDropletService
 	MockKeyService     *MockKeyService
 	MockStorageService *MockStorageService
+	MockCatalogService *MockCatalogService
 	StandardResponses  *StandardResponses
 }
 
@@ -47,6 +51,128 @@ func (c *MockDOClient) DeleteInstance(ctx context.Context, dropletID int) error
 	return err
 }
 
+// GetInstance is a mock implementation of the GetInstance method
+func (c *MockDOClient) GetInstance(ctx context.Context, dropletID int) (*talisTypes.ProviderInstance, error) {
+	droplet, _, err := c.MockDropletService.Get(ctx, dropletID)
+	if err != nil {
+		if errors.Is(err, c.StandardResponses.Droplets.NotFoundError) {
+			return nil, fmt.Errorf("droplet %d: %w", dropletID, talisTypes.ErrProviderInstanceNotFound)
+		}
+		return nil, err
+	}
+	instance := mockDropletToProviderInstance(droplet)
+	return &instance, nil
+}
+
+// ListInstances is a mock implementation of the ListInstances method
+func (c *MockDOClient) ListInstances(ctx context.Context) ([]talisTypes.ProviderInstance, error) {
+	droplets, _, err := c.MockDropletService.List(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	instances := make([]talisTypes.ProviderInstance, 0, len(droplets))
+	for i := range droplets {
+		instances = append(instances, mockDropletToProviderInstance(&droplets[i]))
+	}
+	return instances, nil
+}
+
+// ImportInstance is a mock implementation of the ImportInstance method
+func (c *MockDOClient) ImportInstance(ctx context.Context, nativeID string) (*talisTypes.ProviderInstance, error) {
+	dropletID, err := strconv.Atoi(nativeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid droplet ID %q: %w", nativeID, err)
+	}
+	droplet, _, err := c.MockDropletService.Get(ctx, dropletID)
+	if err != nil {
+		if errors.Is(err, c.StandardResponses.Droplets.NotFoundError) {
+			return nil, fmt.Errorf("droplet %d: %w", dropletID, talisTypes.ErrProviderInstanceNotFound)
+		}
+		return nil, err
+	}
+	instance := mockDropletToProviderInstance(droplet)
+	for _, volumeID := range droplet.VolumeIDs {
+		volume, _, err := c.MockStorageService.GetVolume(ctx, volumeID)
+		if err != nil {
+			return nil, err
+		}
+		instance.Volumes = append(instance.Volumes, talisTypes.VolumeDetails{
+			ID:     volume.ID,
+			Name:   volume.Name,
+			SizeGB: int(volume.SizeGigaBytes),
+		})
+	}
+	return &instance, nil
+}
+
+// ListRegions is a mock implementation of the ListRegions method
+func (c *MockDOClient) ListRegions(ctx context.Context) ([]talisTypes.ProviderRegion, error) {
+	regions, _, err := c.MockCatalogService.ListRegions(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]talisTypes.ProviderRegion, 0, len(regions))
+	for _, region := range regions {
+		result = append(result, talisTypes.ProviderRegion{ID: region.Slug, Name: region.Name})
+	}
+	return result, nil
+}
+
+// ListSizes is a mock implementation of the ListSizes method
+func (c *MockDOClient) ListSizes(ctx context.Context) ([]talisTypes.ProviderSize, error) {
+	sizes, _, err := c.MockCatalogService.ListSizes(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]talisTypes.ProviderSize, 0, len(sizes))
+	for _, size := range sizes {
+		result = append(result, talisTypes.ProviderSize{
+			ID:       size.Slug,
+			CPU:      size.Vcpus,
+			MemoryMB: size.Memory,
+			DiskGB:   size.Disk,
+			Regions:  size.Regions,
+		})
+	}
+	return result, nil
+}
+
+// ListImages is a mock implementation of the ListImages method
+func (c *MockDOClient) ListImages(ctx context.Context) ([]talisTypes.ProviderImage, error) {
+	images, _, err := c.MockCatalogService.ListImages(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]talisTypes.ProviderImage, 0, len(images))
+	for _, image := range images {
+		result = append(result, talisTypes.ProviderImage{ID: image.Slug, Name: image.Name, Distribution: image.Distribution})
+	}
+	return result, nil
+}
+
+// mockDropletToProviderInstance converts a mock droplet into a provider instance.
+// Droplets without a status are considered running.
+func mockDropletToProviderInstance(droplet *godo.Droplet) talisTypes.ProviderInstance {
+	instance := talisTypes.ProviderInstance{
+		ProviderInstanceID: droplet.ID,
+		NativeID:           fmt.Sprint(droplet.ID),
+		Name:               droplet.Name,
+		Status:             talisTypes.ProviderInstanceStatusRunning,
+		Tags:               droplet.Tags,
+	}
+	instance.PublicIP, _ = droplet.PublicIPv4()
+	if droplet.Region != nil {
+		instance.Region = droplet.Region.Slug
+	}
+	if droplet.Size != nil {
+		instance.Size = droplet.Size.Slug
+	}
+	if droplet.Status == "off" {
+		instance.Status = talisTypes.ProviderInstanceStatusStopped
+	}
+	return instance
+}
+
 // GetEnvironmentVars is a no-op to satisfy the ComputeProvider interface
 func (c *MockDOClient) GetEnvironmentVars() map[string]string {
 	return map[string]string{
@@ -89,6 +215,7 @@ func NewMockDOClient() *MockDOClient {
 	client.MockDropletService = NewMockDropletService(client.StandardResponses)
 	client.MockKeyService = NewMockKeyService(client.StandardResponses)
 	client.MockStorageService = NewMockStorageService(client.StandardResponses)
+	client.MockCatalogService = NewMockCatalogService(client.StandardResponses)
 
 	return client
 }
@@ -98,6 +225,7 @@ func (c *MockDOClient) ResetToStandard() {
 	c.MockDropletService.ResetToStandard()
 	c.MockKeyService.ResetToStandard()
 	c.MockStorageService.ResetToStandard()
+	c.MockCatalogService.ResetToStandard()
 }
 
 // Droplets returns the mock droplet service
@@ -115,11 +243,17 @@ func (c *MockDOClient) Storage() computeTypes.StorageService {
 	return c.MockStorageService
 }
 
+// Catalog returns the mock catalog service
+func (c *MockDOClient) Catalog() computeTypes.CatalogService {
+	return c.MockCatalogService
+}
+
 // SimulateAuthenticationFailure configures all services to return authentication errors
 func (c *MockDOClient) SimulateAuthenticationFailure() {
 	c.MockDropletService.SimulateAuthenticationFailure()
 	c.MockKeyService.SimulateAuthenticationFailure()
 	c.MockStorageService.SimulateAuthenticationFailure()
+	c.MockCatalogService.SimulateAuthenticationFailure()
 }
 
 // SimulateNotFound configures all services to return not found errors
@@ -134,6 +268,7 @@ func (c *MockDOClient) SimulateRateLimit() {
 	c.MockDropletService.SimulateRateLimit()
 	c.MockKeyService.SimulateRateLimit()
 	c.MockStorageService.SimulateRateLimit()
+	c.MockCatalogService.SimulateRateLimit()
 }
 
 // MockDropletService implements types.DropletService for testing
@@ -206,13 +341,18 @@ func (s *MockDropletService) List(ctx context.Context, opt *godo.ListOptions) ([
 // SimulateNotFound configures the service to return not found errors
 func (s *MockDropletService) SimulateNotFound() {
 	s.GetFunc = func(_ context.Context, _ int) (*godo.Droplet, *godo.Response, error) {
-		return nil, nil, s.std.Droplets.NotFoundError
+		return nil, notFoundResponse(), s.std.Droplets.NotFoundError
 	}
 	s.DeleteFunc = func(_ context.Context, _ int) (*godo.Response, error) {
-		return nil, s.std.Droplets.NotFoundError
+		return notFoundResponse(), s.std.Droplets.NotFoundError
 	}
 }
 
+// notFoundResponse returns the response the DigitalOcean API sends for missing resources
+func notFoundResponse() *godo.Response {
+	return &godo.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}
+}
+
 // SimulateRateLimit configures the service to return rate limit errors
 func (s *MockDropletService) SimulateRateLimit() {
 	s.CreateFunc = func(_ context.Context, _ *godo.DropletCreateRequest) (*godo.Droplet, *godo.Response, error) {
@@ -354,6 +494,77 @@ func (s *MockKeyService) SimulateAuthenticationFailure() {
 	}
 }
 
+// MockCatalogService implements types.CatalogService for testing
+type MockCatalogService struct {
+	std             *StandardResponses
+	ListRegionsFunc func(_ context.Context, _ *godo.ListOptions) ([]godo.Region, *godo.Response, error)
+	ListSizesFunc   func(_ context.Context, _ *godo.ListOptions) ([]godo.Size, *godo.Response, error)
+	ListImagesFunc  func(_ context.Context, _ *godo.ListOptions) ([]godo.Image, *godo.Response, error)
+}
+
+// setupStandardCatalogResponses configures the standard success responses for catalog service
+func setupStandardCatalogResponses(s *MockCatalogService) {
+	s.ListRegionsFunc = func(_ context.Context, _ *godo.ListOptions) ([]godo.Region, *godo.Response, error) {
+		return s.std.Catalog.Regions, nil, nil
+	}
+	s.ListSizesFunc = func(_ context.Context, _ *godo.ListOptions) ([]godo.Size, *godo.Response, error) {
+		return s.std.Catalog.Sizes, nil, nil
+	}
+	s.ListImagesFunc = func(_ context.Context, _ *godo.ListOptions) ([]godo.Image, *godo.Response, error) {
+		return s.std.Catalog.Images, nil, nil
+	}
+}
+
+// NewMockCatalogService creates a new MockCatalogService with standard responses
+func NewMockCatalogService(std *StandardResponses) *MockCatalogService {
+	s := &MockCatalogService{std: std}
+	setupStandardCatalogResponses(s)
+	return s
+}
+
+// ResetToStandard resets the catalog service back to standard success responses
+func (s *MockCatalogService) ResetToStandard() {
+	setupStandardCatalogResponses(s)
+}
+
+// ListRegions calls the mocked ListRegions function
+func (s *MockCatalogService) ListRegions(ctx context.Context, opt *godo.ListOptions) ([]godo.Region, *godo.Response, error) {
+	return s.ListRegionsFunc(ctx, opt)
+}
+
+// ListSizes calls the mocked ListSizes function
+func (s *MockCatalogService) ListSizes(ctx context.Context, opt *godo.ListOptions) ([]godo.Size, *godo.Response, error) {
+	return s.ListSizesFunc(ctx, opt)
+}
+
+// ListImages calls the mocked ListImages function
+func (s *MockCatalogService) ListImages(ctx context.Context, opt *godo.ListOptions) ([]godo.Image, *godo.Response, error) {
+	return s.ListImagesFunc(ctx, opt)
+}
+
+// simulateError configures all catalog calls to fail with the given error
+func (s *MockCatalogService) simulateError(err error) {
+	s.ListRegionsFunc = func(_ context.Context, _ *godo.ListOptions) ([]godo.Region, *godo.Response, error) {
+		return nil, nil, err
+	}
+	s.ListSizesFunc = func(_ context.Context, _ *godo.ListOptions) ([]godo.Size, *godo.Response, error) {
+		return nil, nil, err
+	}
+	s.ListImagesFunc = func(_ context.Context, _ *godo.ListOptions) ([]godo.Image, *godo.Response, error) {
+		return nil, nil, err
+	}
+}
+
+// SimulateRateLimit configures the service to return rate limit errors
+func (s *MockCatalogService) SimulateRateLimit() {
+	s.simulateError(s.std.Catalog.RateLimitError)
+}
+
+// SimulateAuthenticationFailure configures the service to return authentication errors
+func (s *MockCatalogService) SimulateAuthenticationFailure() {
+	s.simulateError(s.std.Catalog.AuthenticationError)
+}
+
 // MockStorageService implements types.StorageService for testing
 type MockStorageService struct {
 	std                 *StandardResponses
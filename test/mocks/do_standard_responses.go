@@ -48,6 +48,13 @@ var (
 	}
 )
 
+// Default test values for the catalog
+var (
+	DefaultCatalogRegions = []string{"nyc1", "nyc3", "sfo3", "ams3"}
+	DefaultCatalogSizes   = []string{"s-1vcpu-1gb", "s-2vcpu-2gb", "s-4vcpu-8gb"}
+	DefaultCatalogImages  = []string{"ubuntu-20-04-x64", "ubuntu-22-04-x64", "debian-12-x64", "fedora-38-x64"}
+)
+
 // Error messages
 var (
 	ErrDropletNotFound = fmt.Errorf("DO API: droplet not found")
@@ -62,6 +69,7 @@ type StandardResponses struct {
 	Droplets StandardDropletResponses
 	Keys     StandardKeyResponses
 	Volumes  StandardVolumeResponses
+	Catalog  StandardCatalogResponses
 }
 
 // StandardDropletResponses contains all standard mock responses for droplets
@@ -101,6 +109,53 @@ type StandardVolumeResponses struct {
 	AuthenticationError error
 }
 
+// StandardCatalogResponses contains all standard mock responses for regions, sizes and images
+type StandardCatalogResponses struct {
+	// Success responses
+	Regions []godo.Region
+	Sizes   []godo.Size
+	Images  []godo.Image
+
+	// Error responses
+	RateLimitError      error
+	AuthenticationError error
+}
+
+// newStandardCatalogResponses creates the standard catalog with all sizes available in all regions
+func newStandardCatalogResponses() StandardCatalogResponses {
+	catalog := StandardCatalogResponses{
+		RateLimitError:      ErrRateLimit,
+		AuthenticationError: ErrAuthentication,
+	}
+	for _, slug := range DefaultCatalogRegions {
+		catalog.Regions = append(catalog.Regions, godo.Region{
+			Slug:      slug,
+			Name:      "Region " + slug,
+			Sizes:     DefaultCatalogSizes,
+			Available: true,
+		})
+	}
+	for i, slug := range DefaultCatalogSizes {
+		catalog.Sizes = append(catalog.Sizes, godo.Size{
+			Slug:      slug,
+			Vcpus:     1 << i,
+			Memory:    1024 << i,
+			Disk:      25 << i,
+			Regions:   DefaultCatalogRegions,
+			Available: true,
+		})
+	}
+	for i, slug := range DefaultCatalogImages {
+		catalog.Images = append(catalog.Images, godo.Image{
+			ID:     100 + i,
+			Slug:   slug,
+			Name:   slug,
+			Public: true,
+		})
+	}
+	return catalog
+}
+
 // newStandardResponses creates a new set of standard responses
 func newStandardResponses() *StandardResponses {
 	return &StandardResponses{
@@ -207,5 +262,6 @@ func newStandardResponses() *StandardResponses {
 			RateLimitError:      ErrRateLimit,
 			AuthenticationError: ErrAuthentication,
 		},
+		Catalog: newStandardCatalogResponses(),
 	}
 }
@@ -0,0 +1,277 @@
+package mocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	computeTypes "github.com/celestiaorg/talis/internal/compute/types"
+)
+
+// This file contains the mock implementations for the local container engine
+
+// Default test values for local container resources
+var (
+	DefaultDockerContainerIP = "172.30.0.2"
+	DefaultDockerNetwork     = "talis"
+)
+
+// Docker error responses
+var (
+	ErrDockerNotFound    = errors.New("docker: no such container")
+	ErrDockerUnreachable = errors.New("docker: cannot connect to the Docker daemon")
+)
+
+// MockDockerClient implements computeTypes.DockerClient for testing.
+// Every call is routed through an overridable function field, and the
+// standard responses keep track of images, networks, volumes and containers.
+type MockDockerClient struct {
+	VersionFunc          func(ctx context.Context) error
+	ImageExistsFunc      func(ctx context.Context, image string) (bool, error)
+	BuildImageFunc       func(ctx context.Context, tag string, dockerfile []byte) error
+	EnsureNetworkFunc    func(ctx context.Context, name string) error
+	CreateVolumeFunc     func(ctx context.Context, name string, labels map[string]string) error
+	RemoveVolumeFunc     func(ctx context.Context, name string) error
+	RunContainerFunc     func(ctx context.Context, opts *computeTypes.DockerRunOptions) (string, error)
+	InspectContainerFunc func(ctx context.Context, id string) (*computeTypes.DockerContainer, error)
+	ListContainersFunc   func(ctx context.Context, labels map[string]string) ([]string, error)
+	RemoveContainerFunc  func(ctx context.Context, id string) error
+
+	// LastRunOptions records the most recent RunContainer options
+	LastRunOptions *computeTypes.DockerRunOptions
+	// BuiltImages records the tags of all built images
+	BuiltImages []string
+	// RemovedContainers records the IDs of all removed containers
+	RemovedContainers []string
+	// RemovedVolumes records the names of all removed volumes
+	RemovedVolumes []string
+
+	mu         sync.Mutex
+	images     map[string]bool
+	networks   map[string]bool
+	volumes    map[string]map[string]string
+	containers map[string]*computeTypes.DockerContainer
+	nextID     int
+}
+
+// NewMockDockerClient creates a new MockDockerClient with standard responses
+func NewMockDockerClient() *MockDockerClient {
+	c := &MockDockerClient{}
+	c.ResetToStandard()
+	return c
+}
+
+// ResetToStandard resets the mock back to its standard success responses.
+// No image, network, volume or container exists initially.
+func (c *MockDockerClient) ResetToStandard() {
+	c.mu.Lock()
+	c.LastRunOptions = nil
+	c.BuiltImages = nil
+	c.RemovedContainers = nil
+	c.RemovedVolumes = nil
+	c.images = make(map[string]bool)
+	c.networks = make(map[string]bool)
+	c.volumes = make(map[string]map[string]string)
+	c.containers = make(map[string]*computeTypes.DockerContainer)
+	c.nextID = 1
+	c.mu.Unlock()
+
+	c.VersionFunc = func(_ context.Context) error { return nil }
+	c.ImageExistsFunc = func(_ context.Context, image string) (bool, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.images[image], nil
+	}
+	c.BuildImageFunc = func(_ context.Context, tag string, _ []byte) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.images[tag] = true
+		return nil
+	}
+	c.EnsureNetworkFunc = func(_ context.Context, name string) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.networks[name] = true
+		return nil
+	}
+	c.CreateVolumeFunc = func(_ context.Context, name string, labels map[string]string) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if _, ok := c.volumes[name]; ok {
+			return fmt.Errorf("docker: volume %s already exists", name)
+		}
+		c.volumes[name] = labels
+		return nil
+	}
+	c.RemoveVolumeFunc = func(_ context.Context, name string) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.volumes, name)
+		return nil
+	}
+	c.RunContainerFunc = func(_ context.Context, opts *computeTypes.DockerRunOptions) (string, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		container := &computeTypes.DockerContainer{
+			ID:          fmt.Sprintf("%064x", c.nextID),
+			Name:        opts.Name,
+			Running:     true,
+			Labels:      opts.Labels,
+			IPAddresses: map[string]string{opts.Network: DefaultDockerContainerIP},
+		}
+		c.nextID++
+		for _, mount := range opts.Mounts {
+			if _, ok := c.volumes[mount.Volume]; !ok {
+				// Like docker, mounting a missing named volume creates it
+				c.volumes[mount.Volume] = nil
+			}
+			container.Volumes = append(container.Volumes, mount.Volume)
+		}
+		c.containers[container.ID] = container
+		return container.ID, nil
+	}
+	c.InspectContainerFunc = func(_ context.Context, id string) (*computeTypes.DockerContainer, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		container, ok := c.containers[id]
+		if !ok {
+			return nil, ErrDockerNotFound
+		}
+		inspected := *container
+		return &inspected, nil
+	}
+	c.ListContainersFunc = func(_ context.Context, labels map[string]string) ([]string, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		ids := []string{}
+		for id, container := range c.containers {
+			matches := true
+			for k, v := range labels {
+				value, ok := container.Labels[k]
+				matches = matches && ok && (v == "" || value == v)
+			}
+			if matches {
+				ids = append(ids, id)
+			}
+		}
+		return ids, nil
+	}
+	c.RemoveContainerFunc = func(_ context.Context, id string) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if _, ok := c.containers[id]; !ok {
+			return ErrDockerNotFound
+		}
+		delete(c.containers, id)
+		return nil
+	}
+}
+
+// Volumes returns the names of all volumes known to the mock
+func (c *MockDockerClient) Volumes() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	names := make([]string, 0, len(c.volumes))
+	for name := range c.volumes {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Containers returns a snapshot of all containers known to the mock
+func (c *MockDockerClient) Containers() []computeTypes.DockerContainer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	containers := make([]computeTypes.DockerContainer, 0, len(c.containers))
+	for _, container := range c.containers {
+		containers = append(containers, *container)
+	}
+	return containers
+}
+
+// SimulateEngineUnreachable configures all calls to fail as if the daemon was down
+func (c *MockDockerClient) SimulateEngineUnreachable() {
+	err := ErrDockerUnreachable
+	c.VersionFunc = func(_ context.Context) error { return err }
+	c.ImageExistsFunc = func(_ context.Context, _ string) (bool, error) { return false, err }
+	c.BuildImageFunc = func(_ context.Context, _ string, _ []byte) error { return err }
+	c.EnsureNetworkFunc = func(_ context.Context, _ string) error { return err }
+	c.CreateVolumeFunc = func(_ context.Context, _ string, _ map[string]string) error { return err }
+	c.RemoveVolumeFunc = func(_ context.Context, _ string) error { return err }
+	c.RunContainerFunc = func(_ context.Context, _ *computeTypes.DockerRunOptions) (string, error) { return "", err }
+	c.InspectContainerFunc = func(_ context.Context, _ string) (*computeTypes.DockerContainer, error) { return nil, err }
+	c.ListContainersFunc = func(_ context.Context, _ map[string]string) ([]string, error) { return nil, err }
+	c.RemoveContainerFunc = func(_ context.Context, _ string) error { return err }
+}
+
+// Version calls the mocked Version function
+func (c *MockDockerClient) Version(ctx context.Context) error {
+	return c.VersionFunc(ctx)
+}
+
+// ImageExists calls the mocked ImageExists function
+func (c *MockDockerClient) ImageExists(ctx context.Context, image string) (bool, error) {
+	return c.ImageExistsFunc(ctx, image)
+}
+
+// BuildImage calls the mocked BuildImage function and records the built image
+func (c *MockDockerClient) BuildImage(ctx context.Context, tag string, dockerfile []byte) error {
+	if err := c.BuildImageFunc(ctx, tag, dockerfile); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.BuiltImages = append(c.BuiltImages, tag)
+	c.mu.Unlock()
+	return nil
+}
+
+// EnsureNetwork calls the mocked EnsureNetwork function
+func (c *MockDockerClient) EnsureNetwork(ctx context.Context, name string) error {
+	return c.EnsureNetworkFunc(ctx, name)
+}
+
+// CreateVolume calls the mocked CreateVolume function
+func (c *MockDockerClient) CreateVolume(ctx context.Context, name string, labels map[string]string) error {
+	return c.CreateVolumeFunc(ctx, name, labels)
+}
+
+// RemoveVolume calls the mocked RemoveVolume function and records the removed volume
+func (c *MockDockerClient) RemoveVolume(ctx context.Context, name string) error {
+	if err := c.RemoveVolumeFunc(ctx, name); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.RemovedVolumes = append(c.RemovedVolumes, name)
+	c.mu.Unlock()
+	return nil
+}
+
+// RunContainer calls the mocked RunContainer function and records the options
+func (c *MockDockerClient) RunContainer(ctx context.Context, opts *computeTypes.DockerRunOptions) (string, error) {
+	c.mu.Lock()
+	c.LastRunOptions = opts
+	c.mu.Unlock()
+	return c.RunContainerFunc(ctx, opts)
+}
+
+// InspectContainer calls the mocked InspectContainer function
+func (c *MockDockerClient) InspectContainer(ctx context.Context, id string) (*computeTypes.DockerContainer, error) {
+	return c.InspectContainerFunc(ctx, id)
+}
+
+// ListContainers calls the mocked ListContainers function
+func (c *MockDockerClient) ListContainers(ctx context.Context, labels map[string]string) ([]string, error) {
+	return c.ListContainersFunc(ctx, labels)
+}
+
+// RemoveContainer calls the mocked RemoveContainer function and records the removed container
+func (c *MockDockerClient) RemoveContainer(ctx context.Context, id string) error {
+	if err := c.RemoveContainerFunc(ctx, id); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.RemovedContainers = append(c.RemovedContainers, id)
+	c.mu.Unlock()
+	return nil
+}
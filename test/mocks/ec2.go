@@ -0,0 +1,735 @@
+package mocks
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// This file contains a local EC2-compatible stand-in for testing the AWS provider
+// end to end through the AWS SDK and the EC2 Query API it speaks.
+
+// Default test values for EC2 resources
+var (
+	DefaultEC2AccessKeyID     = "AKIDTALISTEST"
+	DefaultEC2SecretAccessKey = "talis-test-secret"
+	DefaultEC2Region          = "us-east-1"
+	DefaultEC2InstanceIP      = "192.0.2.40"
+	DefaultEC2ImageID         = "ami-0a1b2c3d4e5f60718"
+	DefaultEC2KeyName         = "test-key"
+	DefaultEC2InstanceType    = "t3.medium"
+)
+
+// ec2Namespace is the XML namespace of EC2 Query API responses
+const ec2Namespace = "http://ec2.amazonaws.com/doc/2016-11-15/"
+
+// FakeEC2Tag is a key/value tag of a fake EC2 resource
+type FakeEC2Tag struct {
+	Key   string `xml:"key"`
+	Value string `xml:"value"`
+}
+
+// FakeEC2Image is an image known to the fake EC2 server
+type FakeEC2Image struct {
+	ImageID      string `xml:"imageId"`
+	Name         string `xml:"name"`
+	CreationDate string `xml:"creationDate"`
+	OwnerID      string `xml:"imageOwnerId"`
+}
+
+// FakeEC2InstanceType is an instance type offered by the fake EC2 server
+type FakeEC2InstanceType struct {
+	InstanceType string `xml:"instanceType"`
+	VCPUs        int    `xml:"vCpuInfo>defaultVCpus"`
+	MemoryMiB    int    `xml:"memoryInfo>sizeInMiB"`
+	StorageGB    int    `xml:"instanceStorageInfo>totalSizeInGB,omitempty"`
+}
+
+// FakeEC2SecurityGroup is a security group of the fake EC2 server
+type FakeEC2SecurityGroup struct {
+	GroupID   string `xml:"groupId"`
+	GroupName string `xml:"groupName"`
+}
+
+// FakeEC2InstanceBlockDevice is a volume attached to a fake EC2 instance
+type FakeEC2InstanceBlockDevice struct {
+	DeviceName string `xml:"deviceName"`
+	VolumeID   string `xml:"ebs>volumeId"`
+}
+
+// FakeEC2Instance is an instance of the fake EC2 server
+type FakeEC2Instance struct {
+	InstanceID       string                       `xml:"instanceId"`
+	ImageID          string                       `xml:"imageId"`
+	InstanceType     string                       `xml:"instanceType"`
+	State            string                       `xml:"instanceState>name"`
+	AvailabilityZone string                       `xml:"placement>availabilityZone"`
+	PublicIP         string                       `xml:"ipAddress,omitempty"`
+	PrivateIP        string                       `xml:"privateIpAddress"`
+	Tags             []FakeEC2Tag                 `xml:"tagSet>item"`
+	RootDeviceName   string                       `xml:"rootDeviceName,omitempty"`
+	BlockDevices     []FakeEC2InstanceBlockDevice `xml:"blockDeviceMapping>item"`
+}
+
+// Tag returns the value of the tag with the given key
+func (i *FakeEC2Instance) Tag(key string) string {
+	for _, tag := range i.Tags {
+		if tag.Key == key {
+			return tag.Value
+		}
+	}
+	return ""
+}
+
+// fakeEC2KeyPair is a key pair in responses of the fake EC2 server
+type fakeEC2KeyPair struct {
+	KeyPairID string `xml:"keyPairId"`
+	KeyName   string `xml:"keyName"`
+}
+
+// fakeEC2VolumeItem is a volume in responses of the fake EC2 server
+type fakeEC2VolumeItem struct {
+	VolumeID         string `xml:"volumeId"`
+	SizeGB           int    `xml:"size"`
+	AvailabilityZone string `xml:"availabilityZone"`
+	VolumeType       string `xml:"volumeType"`
+}
+
+// fakeEC2Error is an error response of the fake EC2 server
+type fakeEC2Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+// Error implements the error interface
+func (e *fakeEC2Error) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// FakeEC2Volume is an EBS volume created by the fake EC2 server
+type FakeEC2Volume struct {
+	VolumeID   string
+	InstanceID string
+	DeviceName string
+	SizeGB     int
+	VolumeType string
+}
+
+// FakeEC2Server is an in-memory EC2 Query API served over HTTP.
+// Requests must carry a SigV4 Authorization header for the configured access key,
+// the region of the signature selects the region of the resources.
+type FakeEC2Server struct {
+	*httptest.Server
+
+	mu             sync.Mutex
+	accessKeyID    string
+	regions        []string
+	images         []FakeEC2Image
+	instanceTypes  []FakeEC2InstanceType
+	keyPairs       map[string][]string // region -> key names
+	securityGroups map[string][]FakeEC2SecurityGroup
+	ingress        map[string][]string // group ID -> "protocol:port:cidr"
+	instances      map[string][]*FakeEC2Instance
+	volumes        []FakeEC2Volume
+	failures       map[string]*fakeEC2Error
+	nextID         int
+
+	// LastRunInstances holds the parameters of the last RunInstances call
+	LastRunInstances url.Values
+	// LastUserData holds the decoded user data of the last RunInstances call
+	LastUserData string
+	// TerminatedInstances records the IDs of terminated instances
+	TerminatedInstances []string
+	// Actions records every action received, in order
+	Actions []string
+}
+
+// NewFakeEC2Server starts a fake EC2 server with a default key pair and an Ubuntu image
+func NewFakeEC2Server() *FakeEC2Server {
+	f := &FakeEC2Server{
+		accessKeyID:    DefaultEC2AccessKeyID,
+		regions:        []string{DefaultEC2Region, "eu-central-1"},
+		keyPairs:       map[string][]string{DefaultEC2Region: {DefaultEC2KeyName}},
+		securityGroups: make(map[string][]FakeEC2SecurityGroup),
+		ingress:        make(map[string][]string),
+		instances:      make(map[string][]*FakeEC2Instance),
+		failures:       make(map[string]*fakeEC2Error),
+		instanceTypes: []FakeEC2InstanceType{
+			{InstanceType: "t3.micro", VCPUs: 2, MemoryMiB: 1024},
+			{InstanceType: DefaultEC2InstanceType, VCPUs: 2, MemoryMiB: 4096},
+			{InstanceType: "m5d.large", VCPUs: 2, MemoryMiB: 8192, StorageGB: 75},
+		},
+		images: []FakeEC2Image{
+			{
+				ImageID:      "ami-00000000000000001",
+				Name:         "ubuntu/images/hvm-ssd/ubuntu-jammy-22.04-amd64-server-20240101",
+				CreationDate: "2024-01-01T00:00:00.000Z",
+				OwnerID:      "099720109477",
+			},
+			{
+				ImageID:      DefaultEC2ImageID,
+				Name:         "ubuntu/images/hvm-ssd/ubuntu-jammy-22.04-amd64-server-20250101",
+				CreationDate: "2025-01-01T00:00:00.000Z",
+				OwnerID:      "099720109477",
+			},
+		},
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.handle))
+	return f
+}
+
+// Fail makes every subsequent call of the action fail with the given error code
+func (f *FakeEC2Server) Fail(action string, statusCode int, code string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[action] = &fakeEC2Error{StatusCode: statusCode, Code: code, Message: "simulated failure"}
+}
+
+// KeyPairs returns the key pair names of a region
+func (f *FakeEC2Server) KeyPairs(region string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.keyPairs[region]...)
+}
+
+// SecurityGroups returns the security groups of a region
+func (f *FakeEC2Server) SecurityGroups(region string) []FakeEC2SecurityGroup {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]FakeEC2SecurityGroup(nil), f.securityGroups[region]...)
+}
+
+// Ingress returns the ingress rules of a security group formatted as "protocol:port:cidr"
+func (f *FakeEC2Server) Ingress(groupID string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.ingress[groupID]...)
+}
+
+// Instances returns copies of the instances of a region
+func (f *FakeEC2Server) Instances(region string) []FakeEC2Instance {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	instances := make([]FakeEC2Instance, 0, len(f.instances[region]))
+	for _, instance := range f.instances[region] {
+		instances = append(instances, *instance)
+	}
+	return instances
+}
+
+// Volumes returns the EBS volumes that currently exist
+func (f *FakeEC2Server) Volumes() []FakeEC2Volume {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]FakeEC2Volume(nil), f.volumes...)
+}
+
+// AddInstance registers a running instance that was created outside of Talis, together with
+// volumes of the given sizes attached as /dev/sdf, /dev/sdg, ... and returns its ID
+func (f *FakeEC2Server) AddInstance(region string, tags []FakeEC2Tag, volumeSizes ...int) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	instance := &FakeEC2Instance{
+		InstanceID:       f.nextResourceID("i"),
+		ImageID:          DefaultEC2ImageID,
+		InstanceType:     DefaultEC2InstanceType,
+		State:            "running",
+		AvailabilityZone: region + "a",
+		PublicIP:         DefaultEC2InstanceIP,
+		PrivateIP:        "10.0.0.20",
+		Tags:             tags,
+	}
+	for i, size := range volumeSizes {
+		volume := FakeEC2Volume{
+			VolumeID:   f.nextResourceID("vol"),
+			InstanceID: instance.InstanceID,
+			DeviceName: fmt.Sprintf("/dev/sd%c", 'f'+i),
+			SizeGB:     size,
+			VolumeType: "gp3",
+		}
+		f.volumes = append(f.volumes, volume)
+		instance.BlockDevices = append(instance.BlockDevices, FakeEC2InstanceBlockDevice{
+			DeviceName: volume.DeviceName,
+			VolumeID:   volume.VolumeID,
+		})
+	}
+	f.instances[region] = append(f.instances[region], instance)
+	return instance.InstanceID
+}
+
+// handle serves a single EC2 Query API request
+func (f *FakeEC2Server) handle(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		f.writeError(w, &fakeEC2Error{StatusCode: http.StatusBadRequest, Code: "MalformedQueryString", Message: err.Error()})
+		return
+	}
+
+	region, err := f.authorize(r)
+	if err != nil {
+		f.writeError(w, err)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	action := r.Form.Get("Action")
+	f.Actions = append(f.Actions, action)
+	if failure, ok := f.failures[action]; ok {
+		f.writeError(w, failure)
+		return
+	}
+
+	var response interface{}
+	switch action {
+	case "DescribeRegions":
+		response = f.describeRegions()
+	case "DescribeImages":
+		response = f.describeImages(r.Form)
+	case "DescribeInstanceTypes":
+		response = f.describeInstanceTypes()
+	case "DescribeKeyPairs":
+		response = f.describeKeyPairs(region, r.Form)
+	case "ImportKeyPair":
+		response, err = f.importKeyPair(region, r.Form)
+	case "DescribeSecurityGroups":
+		response = f.describeSecurityGroups(region, r.Form)
+	case "CreateSecurityGroup":
+		response, err = f.createSecurityGroup(region, r.Form)
+	case "AuthorizeSecurityGroupIngress":
+		response, err = f.authorizeIngress(r.Form)
+	case "RunInstances":
+		response, err = f.runInstances(region, r.Form)
+	case "DescribeInstances":
+		response = f.describeInstances(region, r.Form)
+	case "TerminateInstances":
+		response, err = f.terminateInstances(region, r.Form)
+	case "CreateTags":
+		response, err = f.createTags(region, r.Form)
+	case "DescribeVolumes":
+		response = f.describeVolumes(region, r.Form)
+	default:
+		err = &fakeEC2Error{StatusCode: http.StatusBadRequest, Code: "InvalidAction", Message: "unsupported action " + action}
+	}
+	if err != nil {
+		f.writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/xml;charset=UTF-8")
+	_ = xml.NewEncoder(w).Encode(response)
+}
+
+// authorize checks the SigV4 Authorization header and returns the region of its scope
+func (f *FakeEC2Server) authorize(r *http.Request) (string, error) {
+	auth := r.Header.Get("Authorization")
+	prefix := "AWS4-HMAC-SHA256 Credential=" + f.accessKeyID + "/"
+	if !strings.HasPrefix(auth, prefix) || r.Header.Get("X-Amz-Date") == "" || !strings.Contains(auth, "Signature=") {
+		return "", &fakeEC2Error{StatusCode: http.StatusUnauthorized, Code: "AuthFailure", Message: "AWS was not able to validate the provided access credentials"}
+	}
+
+	// Credential=<key>/<date>/<region>/<service>/aws4_request
+	scope := strings.SplitN(strings.TrimPrefix(auth, prefix), ",", 2)[0]
+	parts := strings.Split(scope, "/")
+	if len(parts) != 4 || parts[2] != "ec2" {
+		return "", &fakeEC2Error{StatusCode: http.StatusUnauthorized, Code: "AuthFailure", Message: "invalid credential scope " + scope}
+	}
+	return parts[1], nil
+}
+
+// writeError writes an EC2 error response
+func (f *FakeEC2Server) writeError(w http.ResponseWriter, err error) {
+	apiErr, ok := err.(*fakeEC2Error)
+	if !ok {
+		apiErr = &fakeEC2Error{StatusCode: http.StatusInternalServerError, Code: "InternalError", Message: err.Error()}
+	}
+	w.Header().Set("Content-Type", "text/xml;charset=UTF-8")
+	w.WriteHeader(apiErr.StatusCode)
+	_, _ = fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<Response><Errors><Error><Code>%s</Code><Message>%s</Message></Error></Errors><RequestID>fake</RequestID></Response>`,
+		apiErr.Code, apiErr.Message)
+}
+
+// ec2Filters parses the Filter.N.Name / Filter.N.Value.M parameters
+func ec2Filters(form url.Values) map[string][]string {
+	filters := make(map[string][]string)
+	for i := 1; form.Get(fmt.Sprintf("Filter.%d.Name", i)) != ""; i++ {
+		name := form.Get(fmt.Sprintf("Filter.%d.Name", i))
+		for j := 1; form.Get(fmt.Sprintf("Filter.%d.Value.%d", i, j)) != ""; j++ {
+			filters[name] = append(filters[name], form.Get(fmt.Sprintf("Filter.%d.Value.%d", i, j)))
+		}
+	}
+	return filters
+}
+
+// ec2List returns the values of a numbered list parameter, e.g. InstanceId.1, InstanceId.2
+func ec2List(form url.Values, prefix string) []string {
+	var values []string
+	for i := 1; form.Get(fmt.Sprintf("%s.%d", prefix, i)) != ""; i++ {
+		values = append(values, form.Get(fmt.Sprintf("%s.%d", prefix, i)))
+	}
+	return values
+}
+
+// matchesAny reports whether value matches any of the glob patterns
+func matchesAny(value string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// nextResourceID returns a new resource ID with the given prefix, e.g. i-000000000000001
+func (f *FakeEC2Server) nextResourceID(prefix string) string {
+	f.nextID++
+	return fmt.Sprintf("%s-%017x", prefix, f.nextID)
+}
+
+func (f *FakeEC2Server) describeRegions() interface{} {
+	type region struct {
+		RegionName string `xml:"regionName"`
+		Endpoint   string `xml:"regionEndpoint"`
+	}
+	response := struct {
+		XMLName xml.Name `xml:"DescribeRegionsResponse"`
+		Xmlns   string   `xml:"xmlns,attr"`
+		Regions []region `xml:"regionInfo>item"`
+	}{Xmlns: ec2Namespace}
+	for _, name := range f.regions {
+		response.Regions = append(response.Regions, region{RegionName: name, Endpoint: "ec2." + name + ".amazonaws.com"})
+	}
+	return response
+}
+
+func (f *FakeEC2Server) describeImages(form url.Values) interface{} {
+	owners := ec2List(form, "Owner")
+	filters := ec2Filters(form)
+
+	response := struct {
+		XMLName xml.Name       `xml:"DescribeImagesResponse"`
+		Xmlns   string         `xml:"xmlns,attr"`
+		Images  []FakeEC2Image `xml:"imagesSet>item"`
+	}{Xmlns: ec2Namespace}
+	for _, image := range f.images {
+		if len(owners) > 0 && !containsString(owners, image.OwnerID) {
+			continue
+		}
+		if names, ok := filters["name"]; ok && !matchesAny(image.Name, names) {
+			continue
+		}
+		response.Images = append(response.Images, image)
+	}
+	return response
+}
+
+func (f *FakeEC2Server) describeInstanceTypes() interface{} {
+	return struct {
+		XMLName       xml.Name              `xml:"DescribeInstanceTypesResponse"`
+		Xmlns         string                `xml:"xmlns,attr"`
+		InstanceTypes []FakeEC2InstanceType `xml:"instanceTypeSet>item"`
+	}{Xmlns: ec2Namespace, InstanceTypes: f.instanceTypes}
+}
+
+func (f *FakeEC2Server) describeKeyPairs(region string, form url.Values) interface{} {
+	names := ec2Filters(form)["key-name"]
+	response := struct {
+		XMLName  xml.Name         `xml:"DescribeKeyPairsResponse"`
+		Xmlns    string           `xml:"xmlns,attr"`
+		KeyPairs []fakeEC2KeyPair `xml:"keySet>item"`
+	}{Xmlns: ec2Namespace}
+	for i, name := range f.keyPairs[region] {
+		if len(names) > 0 && !containsString(names, name) {
+			continue
+		}
+		response.KeyPairs = append(response.KeyPairs, fakeEC2KeyPair{
+			KeyPairID: fmt.Sprintf("key-%017x", i+1),
+			KeyName:   name,
+		})
+	}
+	return response
+}
+
+func (f *FakeEC2Server) importKeyPair(region string, form url.Values) (interface{}, error) {
+	name := form.Get("KeyName")
+	material, err := base64.StdEncoding.DecodeString(form.Get("PublicKeyMaterial"))
+	if err != nil || !strings.HasPrefix(string(material), "ssh-") {
+		return nil, &fakeEC2Error{StatusCode: http.StatusBadRequest, Code: "InvalidKey.Format", Message: "Key is not in valid OpenSSH public key format"}
+	}
+	if containsString(f.keyPairs[region], name) {
+		return nil, &fakeEC2Error{StatusCode: http.StatusBadRequest, Code: "InvalidKeyPair.Duplicate", Message: "The keypair already exists"}
+	}
+	f.keyPairs[region] = append(f.keyPairs[region], name)
+
+	return struct {
+		XMLName xml.Name `xml:"ImportKeyPairResponse"`
+		Xmlns   string   `xml:"xmlns,attr"`
+		KeyName string   `xml:"keyName"`
+	}{Xmlns: ec2Namespace, KeyName: name}, nil
+}
+
+func (f *FakeEC2Server) describeSecurityGroups(region string, form url.Values) interface{} {
+	names := ec2Filters(form)["group-name"]
+	response := struct {
+		XMLName xml.Name               `xml:"DescribeSecurityGroupsResponse"`
+		Xmlns   string                 `xml:"xmlns,attr"`
+		Groups  []FakeEC2SecurityGroup `xml:"securityGroupInfo>item"`
+	}{Xmlns: ec2Namespace}
+	for _, group := range f.securityGroups[region] {
+		if len(names) > 0 && !containsString(names, group.GroupName) {
+			continue
+		}
+		response.Groups = append(response.Groups, group)
+	}
+	return response
+}
+
+func (f *FakeEC2Server) createSecurityGroup(region string, form url.Values) (interface{}, error) {
+	name := form.Get("GroupName")
+	for _, group := range f.securityGroups[region] {
+		if group.GroupName == name {
+			return nil, &fakeEC2Error{StatusCode: http.StatusBadRequest, Code: "InvalidGroup.Duplicate", Message: "The security group already exists"}
+		}
+	}
+	group := FakeEC2SecurityGroup{GroupID: f.nextResourceID("sg"), GroupName: name}
+	f.securityGroups[region] = append(f.securityGroups[region], group)
+
+	return struct {
+		XMLName xml.Name `xml:"CreateSecurityGroupResponse"`
+		Xmlns   string   `xml:"xmlns,attr"`
+		Return  bool     `xml:"return"`
+		GroupID string   `xml:"groupId"`
+	}{Xmlns: ec2Namespace, Return: true, GroupID: group.GroupID}, nil
+}
+
+func (f *FakeEC2Server) authorizeIngress(form url.Values) (interface{}, error) {
+	groupID := form.Get("GroupId")
+	rule := fmt.Sprintf("%s:%s:%s",
+		form.Get("IpPermissions.1.IpProtocol"),
+		form.Get("IpPermissions.1.FromPort"),
+		form.Get("IpPermissions.1.IpRanges.1.CidrIp"))
+	f.ingress[groupID] = append(f.ingress[groupID], rule)
+
+	return struct {
+		XMLName xml.Name `xml:"AuthorizeSecurityGroupIngressResponse"`
+		Xmlns   string   `xml:"xmlns,attr"`
+		Return  bool     `xml:"return"`
+	}{Xmlns: ec2Namespace, Return: true}, nil
+}
+
+func (f *FakeEC2Server) runInstances(region string, form url.Values) (interface{}, error) {
+	if !containsString(f.keyPairs[region], form.Get("KeyName")) {
+		return nil, &fakeEC2Error{StatusCode: http.StatusBadRequest, Code: "InvalidKeyPair.NotFound", Message: "The key pair does not exist"}
+	}
+	imageFound := false
+	for _, image := range f.images {
+		imageFound = imageFound || image.ImageID == form.Get("ImageId")
+	}
+	if !imageFound {
+		return nil, &fakeEC2Error{StatusCode: http.StatusBadRequest, Code: "InvalidAMIID.NotFound", Message: "The image id does not exist"}
+	}
+
+	f.LastRunInstances = form
+	userData, _ := base64.StdEncoding.DecodeString(form.Get("UserData"))
+	f.LastUserData = string(userData)
+
+	instance := &FakeEC2Instance{
+		InstanceID:       f.nextResourceID("i"),
+		ImageID:          form.Get("ImageId"),
+		InstanceType:     form.Get("InstanceType"),
+		State:            "pending",
+		AvailabilityZone: region + "a",
+		PrivateIP:        "10.0.0.10",
+	}
+	for i := 1; form.Get(fmt.Sprintf("TagSpecification.%d.ResourceType", i)) != ""; i++ {
+		if form.Get(fmt.Sprintf("TagSpecification.%d.ResourceType", i)) != "instance" {
+			continue
+		}
+		for j := 1; form.Get(fmt.Sprintf("TagSpecification.%d.Tag.%d.Key", i, j)) != ""; j++ {
+			instance.Tags = append(instance.Tags, FakeEC2Tag{
+				Key:   form.Get(fmt.Sprintf("TagSpecification.%d.Tag.%d.Key", i, j)),
+				Value: form.Get(fmt.Sprintf("TagSpecification.%d.Tag.%d.Value", i, j)),
+			})
+		}
+	}
+	for i := 1; form.Get(fmt.Sprintf("BlockDeviceMapping.%d.DeviceName", i)) != ""; i++ {
+		prefix := fmt.Sprintf("BlockDeviceMapping.%d.", i)
+		size, _ := strconv.Atoi(form.Get(prefix + "Ebs.VolumeSize"))
+		volume := FakeEC2Volume{
+			VolumeID:   f.nextResourceID("vol"),
+			InstanceID: instance.InstanceID,
+			DeviceName: form.Get(prefix + "DeviceName"),
+			SizeGB:     size,
+			VolumeType: form.Get(prefix + "Ebs.VolumeType"),
+		}
+		f.volumes = append(f.volumes, volume)
+		instance.BlockDevices = append(instance.BlockDevices, FakeEC2InstanceBlockDevice{
+			DeviceName: volume.DeviceName,
+			VolumeID:   volume.VolumeID,
+		})
+	}
+	f.instances[region] = append(f.instances[region], instance)
+
+	return struct {
+		XMLName   xml.Name          `xml:"RunInstancesResponse"`
+		Xmlns     string            `xml:"xmlns,attr"`
+		Instances []FakeEC2Instance `xml:"instancesSet>item"`
+	}{Xmlns: ec2Namespace, Instances: []FakeEC2Instance{*instance}}, nil
+}
+
+func (f *FakeEC2Server) describeInstances(region string, form url.Values) interface{} {
+	filters := ec2Filters(form)
+
+	type reservation struct {
+		ReservationID string            `xml:"reservationId"`
+		Instances     []FakeEC2Instance `xml:"instancesSet>item"`
+	}
+	response := struct {
+		XMLName      xml.Name      `xml:"DescribeInstancesResponse"`
+		Xmlns        string        `xml:"xmlns,attr"`
+		Reservations []reservation `xml:"reservationSet>item"`
+	}{Xmlns: ec2Namespace}
+
+	for _, instance := range f.instances[region] {
+		// Instances boot instantly and get their public IP on the first describe
+		if instance.State == "pending" {
+			instance.State = "running"
+			instance.PublicIP = DefaultEC2InstanceIP
+		}
+		if !instanceMatches(instance, filters) {
+			continue
+		}
+		response.Reservations = append(response.Reservations, reservation{
+			ReservationID: "r-" + strings.TrimPrefix(instance.InstanceID, "i-"),
+			Instances:     []FakeEC2Instance{*instance},
+		})
+	}
+	return response
+}
+
+// instanceMatches reports whether an instance matches all the describe filters
+func instanceMatches(instance *FakeEC2Instance, filters map[string][]string) bool {
+	for name, values := range filters {
+		switch {
+		case name == "instance-id":
+			if !containsString(values, instance.InstanceID) {
+				return false
+			}
+		case name == "instance-state-name":
+			if !containsString(values, instance.State) {
+				return false
+			}
+		case strings.HasPrefix(name, "tag:"):
+			key := strings.TrimPrefix(name, "tag:")
+			found := false
+			for _, tag := range instance.Tags {
+				found = found || (tag.Key == key && containsString(values, tag.Value))
+			}
+			if !found {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (f *FakeEC2Server) terminateInstances(region string, form url.Values) (interface{}, error) {
+	ids := ec2List(form, "InstanceId")
+	for _, id := range ids {
+		var target *FakeEC2Instance
+		for _, instance := range f.instances[region] {
+			if instance.InstanceID == id {
+				target = instance
+			}
+		}
+		if target == nil {
+			return nil, &fakeEC2Error{StatusCode: http.StatusBadRequest, Code: "InvalidInstanceID.NotFound", Message: fmt.Sprintf("The instance ID '%s' does not exist", id)}
+		}
+		target.State = "terminated"
+		f.TerminatedInstances = append(f.TerminatedInstances, id)
+
+		// Volumes are created with DeleteOnTermination
+		remaining := f.volumes[:0]
+		for _, volume := range f.volumes {
+			if volume.InstanceID != id {
+				remaining = append(remaining, volume)
+			}
+		}
+		f.volumes = remaining
+	}
+
+	return struct {
+		XMLName xml.Name `xml:"TerminateInstancesResponse"`
+		Xmlns   string   `xml:"xmlns,attr"`
+	}{Xmlns: ec2Namespace}, nil
+}
+
+func (f *FakeEC2Server) createTags(region string, form url.Values) (interface{}, error) {
+	var tags []FakeEC2Tag
+	for i := 1; form.Get(fmt.Sprintf("Tag.%d.Key", i)) != ""; i++ {
+		tags = append(tags, FakeEC2Tag{
+			Key:   form.Get(fmt.Sprintf("Tag.%d.Key", i)),
+			Value: form.Get(fmt.Sprintf("Tag.%d.Value", i)),
+		})
+	}
+
+	for _, id := range ec2List(form, "ResourceId") {
+		var target *FakeEC2Instance
+		for _, instance := range f.instances[region] {
+			if instance.InstanceID == id {
+				target = instance
+			}
+		}
+		if target == nil {
+			return nil, &fakeEC2Error{StatusCode: http.StatusBadRequest, Code: "InvalidID", Message: fmt.Sprintf("The ID '%s' is not valid", id)}
+		}
+		for _, tag := range tags {
+			replaced := false
+			for i := range target.Tags {
+				if target.Tags[i].Key == tag.Key {
+					target.Tags[i].Value = tag.Value
+					replaced = true
+				}
+			}
+			if !replaced {
+				target.Tags = append(target.Tags, tag)
+			}
+		}
+	}
+
+	return struct {
+		XMLName xml.Name `xml:"CreateTagsResponse"`
+		Xmlns   string   `xml:"xmlns,attr"`
+		Return  bool     `xml:"return"`
+	}{Xmlns: ec2Namespace, Return: true}, nil
+}
+
+func (f *FakeEC2Server) describeVolumes(region string, form url.Values) interface{} {
+	ids := ec2List(form, "VolumeId")
+	response := struct {
+		XMLName xml.Name            `xml:"DescribeVolumesResponse"`
+		Xmlns   string              `xml:"xmlns,attr"`
+		Volumes []fakeEC2VolumeItem `xml:"volumeSet>item"`
+	}{Xmlns: ec2Namespace}
+	for _, volume := range f.volumes {
+		if len(ids) > 0 && !containsString(ids, volume.VolumeID) {
+			continue
+		}
+		response.Volumes = append(response.Volumes, fakeEC2VolumeItem{
+			VolumeID:         volume.VolumeID,
+			SizeGB:           volume.SizeGB,
+			AvailabilityZone: region + "a",
+			VolumeType:       volume.VolumeType,
+		})
+	}
+	return response
+}
@@ -0,0 +1,391 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/hetznercloud/hcloud-go/v2/hcloud"
+
+	computeTypes "github.com/celestiaorg/talis/internal/compute/types"
+)
+
+// This file contains the mock implementations for the Hetzner Cloud API
+
+// Default test values for Hetzner resources
+var (
+	DefaultHetznerServerID   int64 = 42001
+	DefaultHetznerServerIP         = "192.0.2.10"
+	DefaultHetznerKeyID      int64 = 42101
+	DefaultHetznerKeyName          = "test-key"
+	DefaultHetznerVolumeID   int64 = 42201
+	DefaultHetznerLocation         = "fsn1"
+	DefaultHetznerServerType       = "cx22"
+	DefaultHetznerImage            = "ubuntu-22.04"
+)
+
+// Hetzner error responses
+var (
+	ErrHetznerNotFound       = hcloud.Error{Code: hcloud.ErrorCodeNotFound, Message: "Hetzner API: not found"}
+	ErrHetznerRateLimit      = hcloud.Error{Code: hcloud.ErrorCodeRateLimitExceeded, Message: "Hetzner API: rate limit exceeded"}
+	ErrHetznerAuthentication = hcloud.Error{Code: hcloud.ErrorCodeUnauthorized, Message: "Hetzner API: unable to authenticate"}
+)
+
+// MockHetznerClient implements computeTypes.HetznerClient for testing
+type MockHetznerClient struct {
+	MockServerService  *MockHetznerServerService
+	MockSSHKeyService  *MockHetznerSSHKeyService
+	MockVolumeService  *MockHetznerVolumeService
+	MockActionService  *MockHetznerActionService
+	MockCatalogService *MockHetznerCatalogService
+	ValidateFunc       func(_ context.Context) error
+}
+
+// NewMockHetznerClient creates a new MockHetznerClient with standard responses
+func NewMockHetznerClient() *MockHetznerClient {
+	c := &MockHetznerClient{
+		MockServerService:  &MockHetznerServerService{},
+		MockSSHKeyService:  &MockHetznerSSHKeyService{},
+		MockVolumeService:  &MockHetznerVolumeService{},
+		MockActionService:  &MockHetznerActionService{},
+		MockCatalogService: &MockHetznerCatalogService{},
+	}
+	c.ResetToStandard()
+	return c
+}
+
+// ResetToStandard resets all mock services back to their standard success responses
+func (c *MockHetznerClient) ResetToStandard() {
+	c.ValidateFunc = func(_ context.Context) error { return nil }
+	c.MockServerService.ResetToStandard()
+	c.MockSSHKeyService.ResetToStandard()
+	c.MockVolumeService.ResetToStandard()
+	c.MockActionService.ResetToStandard()
+	c.MockCatalogService.ResetToStandard()
+}
+
+// Servers returns the mock server service
+func (c *MockHetznerClient) Servers() computeTypes.HetznerServerService {
+	return c.MockServerService
+}
+
+// SSHKeys returns the mock SSH key service
+func (c *MockHetznerClient) SSHKeys() computeTypes.HetznerSSHKeyService {
+	return c.MockSSHKeyService
+}
+
+// Volumes returns the mock volume service
+func (c *MockHetznerClient) Volumes() computeTypes.HetznerVolumeService {
+	return c.MockVolumeService
+}
+
+// Actions returns the mock action service
+func (c *MockHetznerClient) Actions() computeTypes.HetznerActionService {
+	return c.MockActionService
+}
+
+// Catalog returns the mock catalog service
+func (c *MockHetznerClient) Catalog() computeTypes.HetznerCatalogService {
+	return c.MockCatalogService
+}
+
+// ValidateCredentials calls the mocked ValidateFunc
+func (c *MockHetznerClient) ValidateCredentials(ctx context.Context) error {
+	return c.ValidateFunc(ctx)
+}
+
+// SimulateAuthenticationFailure configures all services to return authentication errors
+func (c *MockHetznerClient) SimulateAuthenticationFailure() {
+	err := ErrHetznerAuthentication
+	c.ValidateFunc = func(_ context.Context) error { return err }
+	c.MockServerService.simulateError(err)
+	c.MockSSHKeyService.GetByNameFunc = func(_ context.Context, _ string) (*hcloud.SSHKey, *hcloud.Response, error) {
+		return nil, nil, err
+	}
+	c.MockVolumeService.simulateError(err)
+	c.MockCatalogService.simulateError(err)
+}
+
+// SimulateNotFound configures the server and SSH key lookups to return no results,
+// which is how hcloud-go reports missing resources
+func (c *MockHetznerClient) SimulateNotFound() {
+	c.MockServerService.GetByIDFunc = func(_ context.Context, _ int64) (*hcloud.Server, *hcloud.Response, error) {
+		return nil, nil, nil
+	}
+	c.MockServerService.DeleteFunc = func(_ context.Context, _ *hcloud.Server) (*hcloud.ServerDeleteResult, *hcloud.Response, error) {
+		return nil, nil, ErrHetznerNotFound
+	}
+	c.MockSSHKeyService.GetByNameFunc = func(_ context.Context, _ string) (*hcloud.SSHKey, *hcloud.Response, error) {
+		return nil, nil, nil
+	}
+}
+
+// SimulateRateLimit configures all services to return rate limit errors
+func (c *MockHetznerClient) SimulateRateLimit() {
+	err := ErrHetznerRateLimit
+	c.MockServerService.simulateError(err)
+	c.MockVolumeService.simulateError(err)
+}
+
+// NewDefaultHetznerServer returns a running server with the default public IP
+func NewDefaultHetznerServer(name string) *hcloud.Server {
+	return &hcloud.Server{
+		ID:     DefaultHetznerServerID,
+		Name:   name,
+		Status: hcloud.ServerStatusRunning,
+		PublicNet: hcloud.ServerPublicNet{
+			IPv4: hcloud.ServerPublicNetIPv4{IP: net.ParseIP(DefaultHetznerServerIP)},
+		},
+		ServerType: &hcloud.ServerType{Name: DefaultHetznerServerType},
+	}
+}
+
+// MockHetznerServerService implements computeTypes.HetznerServerService for testing
+type MockHetznerServerService struct {
+	CreateFunc  func(_ context.Context, _ hcloud.ServerCreateOpts) (hcloud.ServerCreateResult, *hcloud.Response, error)
+	GetByIDFunc func(_ context.Context, _ int64) (*hcloud.Server, *hcloud.Response, error)
+	AllFunc     func(_ context.Context) ([]*hcloud.Server, error)
+	DeleteFunc  func(_ context.Context, _ *hcloud.Server) (*hcloud.ServerDeleteResult, *hcloud.Response, error)
+
+	// LastCreateOpts records the options of the most recent Create call
+	LastCreateOpts hcloud.ServerCreateOpts
+}
+
+// ResetToStandard resets the server service back to standard success responses
+func (s *MockHetznerServerService) ResetToStandard() {
+	s.CreateFunc = func(_ context.Context, opts hcloud.ServerCreateOpts) (hcloud.ServerCreateResult, *hcloud.Response, error) {
+		server := NewDefaultHetznerServer(opts.Name)
+		server.Volumes = opts.Volumes
+		return hcloud.ServerCreateResult{
+			Server: server,
+			Action: &hcloud.Action{ID: 1, Status: hcloud.ActionStatusSuccess},
+		}, nil, nil
+	}
+	s.GetByIDFunc = func(_ context.Context, id int64) (*hcloud.Server, *hcloud.Response, error) {
+		server := NewDefaultHetznerServer(fmt.Sprintf("server-%d", id))
+		server.ID = id
+		return server, nil, nil
+	}
+	s.AllFunc = func(_ context.Context) ([]*hcloud.Server, error) {
+		return []*hcloud.Server{NewDefaultHetznerServer("server")}, nil
+	}
+	s.DeleteFunc = func(_ context.Context, _ *hcloud.Server) (*hcloud.ServerDeleteResult, *hcloud.Response, error) {
+		return &hcloud.ServerDeleteResult{Action: &hcloud.Action{ID: 2, Status: hcloud.ActionStatusSuccess}}, nil, nil
+	}
+}
+
+func (s *MockHetznerServerService) simulateError(err error) {
+	s.CreateFunc = func(_ context.Context, _ hcloud.ServerCreateOpts) (hcloud.ServerCreateResult, *hcloud.Response, error) {
+		return hcloud.ServerCreateResult{}, nil, err
+	}
+	s.GetByIDFunc = func(_ context.Context, _ int64) (*hcloud.Server, *hcloud.Response, error) {
+		return nil, nil, err
+	}
+	s.AllFunc = func(_ context.Context) ([]*hcloud.Server, error) {
+		return nil, err
+	}
+	s.DeleteFunc = func(_ context.Context, _ *hcloud.Server) (*hcloud.ServerDeleteResult, *hcloud.Response, error) {
+		return nil, nil, err
+	}
+}
+
+// Create calls the mocked Create function
+func (s *MockHetznerServerService) Create(ctx context.Context, opts hcloud.ServerCreateOpts) (hcloud.ServerCreateResult, *hcloud.Response, error) {
+	s.LastCreateOpts = opts
+	return s.CreateFunc(ctx, opts)
+}
+
+// GetByID calls the mocked GetByID function
+func (s *MockHetznerServerService) GetByID(ctx context.Context, id int64) (*hcloud.Server, *hcloud.Response, error) {
+	return s.GetByIDFunc(ctx, id)
+}
+
+// All calls the mocked All function
+func (s *MockHetznerServerService) All(ctx context.Context) ([]*hcloud.Server, error) {
+	return s.AllFunc(ctx)
+}
+
+// Delete calls the mocked Delete function
+func (s *MockHetznerServerService) Delete(ctx context.Context, server *hcloud.Server) (*hcloud.ServerDeleteResult, *hcloud.Response, error) {
+	return s.DeleteFunc(ctx, server)
+}
+
+// MockHetznerSSHKeyService implements computeTypes.HetznerSSHKeyService for testing
+type MockHetznerSSHKeyService struct {
+	GetByNameFunc func(_ context.Context, _ string) (*hcloud.SSHKey, *hcloud.Response, error)
+}
+
+// ResetToStandard resets the SSH key service back to standard success responses.
+// Only DefaultHetznerKeyName is known, any other name is reported as missing.
+func (s *MockHetznerSSHKeyService) ResetToStandard() {
+	s.GetByNameFunc = func(_ context.Context, name string) (*hcloud.SSHKey, *hcloud.Response, error) {
+		if name != DefaultHetznerKeyName {
+			return nil, nil, nil
+		}
+		return &hcloud.SSHKey{ID: DefaultHetznerKeyID, Name: name}, nil, nil
+	}
+}
+
+// GetByName calls the mocked GetByName function
+func (s *MockHetznerSSHKeyService) GetByName(ctx context.Context, name string) (*hcloud.SSHKey, *hcloud.Response, error) {
+	return s.GetByNameFunc(ctx, name)
+}
+
+// MockHetznerVolumeService implements computeTypes.HetznerVolumeService for testing
+type MockHetznerVolumeService struct {
+	CreateFunc  func(_ context.Context, _ hcloud.VolumeCreateOpts) (hcloud.VolumeCreateResult, *hcloud.Response, error)
+	GetByIDFunc func(_ context.Context, _ int64) (*hcloud.Volume, *hcloud.Response, error)
+	DetachFunc  func(_ context.Context, _ *hcloud.Volume) (*hcloud.Action, *hcloud.Response, error)
+	DeleteFunc  func(_ context.Context, _ *hcloud.Volume) (*hcloud.Response, error)
+
+	// Deleted records the IDs of all deleted volumes
+	Deleted []int64
+	nextID  int64
+}
+
+// ResetToStandard resets the volume service back to standard success responses
+func (s *MockHetznerVolumeService) ResetToStandard() {
+	s.Deleted = nil
+	s.nextID = DefaultHetznerVolumeID
+	s.CreateFunc = func(_ context.Context, opts hcloud.VolumeCreateOpts) (hcloud.VolumeCreateResult, *hcloud.Response, error) {
+		id := s.nextID
+		s.nextID++
+		return hcloud.VolumeCreateResult{
+			Volume: &hcloud.Volume{
+				ID:       id,
+				Name:     opts.Name,
+				Size:     opts.Size,
+				Location: opts.Location,
+				Status:   hcloud.VolumeStatusAvailable,
+			},
+			Action: &hcloud.Action{ID: id, Status: hcloud.ActionStatusSuccess},
+		}, nil, nil
+	}
+	s.GetByIDFunc = func(_ context.Context, id int64) (*hcloud.Volume, *hcloud.Response, error) {
+		return &hcloud.Volume{
+			ID:       id,
+			Name:     fmt.Sprintf("volume-%d", id),
+			Size:     10,
+			Location: &hcloud.Location{Name: DefaultHetznerLocation},
+			Status:   hcloud.VolumeStatusAvailable,
+		}, nil, nil
+	}
+	s.DetachFunc = func(_ context.Context, volume *hcloud.Volume) (*hcloud.Action, *hcloud.Response, error) {
+		return &hcloud.Action{ID: volume.ID, Status: hcloud.ActionStatusSuccess}, nil, nil
+	}
+	s.DeleteFunc = func(_ context.Context, _ *hcloud.Volume) (*hcloud.Response, error) {
+		return nil, nil
+	}
+}
+
+func (s *MockHetznerVolumeService) simulateError(err error) {
+	s.CreateFunc = func(_ context.Context, _ hcloud.VolumeCreateOpts) (hcloud.VolumeCreateResult, *hcloud.Response, error) {
+		return hcloud.VolumeCreateResult{}, nil, err
+	}
+	s.GetByIDFunc = func(_ context.Context, _ int64) (*hcloud.Volume, *hcloud.Response, error) {
+		return nil, nil, err
+	}
+	s.DetachFunc = func(_ context.Context, _ *hcloud.Volume) (*hcloud.Action, *hcloud.Response, error) {
+		return nil, nil, err
+	}
+	s.DeleteFunc = func(_ context.Context, _ *hcloud.Volume) (*hcloud.Response, error) {
+		return nil, err
+	}
+}
+
+// Create calls the mocked Create function
+func (s *MockHetznerVolumeService) Create(ctx context.Context, opts hcloud.VolumeCreateOpts) (hcloud.VolumeCreateResult, *hcloud.Response, error) {
+	return s.CreateFunc(ctx, opts)
+}
+
+// GetByID calls the mocked GetByID function
+func (s *MockHetznerVolumeService) GetByID(ctx context.Context, id int64) (*hcloud.Volume, *hcloud.Response, error) {
+	return s.GetByIDFunc(ctx, id)
+}
+
+// Detach calls the mocked Detach function
+func (s *MockHetznerVolumeService) Detach(ctx context.Context, volume *hcloud.Volume) (*hcloud.Action, *hcloud.Response, error) {
+	return s.DetachFunc(ctx, volume)
+}
+
+// Delete calls the mocked Delete function and records the deleted volume
+func (s *MockHetznerVolumeService) Delete(ctx context.Context, volume *hcloud.Volume) (*hcloud.Response, error) {
+	resp, err := s.DeleteFunc(ctx, volume)
+	if err == nil {
+		s.Deleted = append(s.Deleted, volume.ID)
+	}
+	return resp, err
+}
+
+// MockHetznerActionService implements computeTypes.HetznerActionService for testing
+type MockHetznerActionService struct {
+	WaitForFunc func(_ context.Context, _ ...*hcloud.Action) error
+}
+
+// ResetToStandard resets the action service so every action completes immediately
+func (s *MockHetznerActionService) ResetToStandard() {
+	s.WaitForFunc = func(_ context.Context, _ ...*hcloud.Action) error {
+		return nil
+	}
+}
+
+// WaitFor calls the mocked WaitFor function
+func (s *MockHetznerActionService) WaitFor(ctx context.Context, actions ...*hcloud.Action) error {
+	return s.WaitForFunc(ctx, actions...)
+}
+
+// MockHetznerCatalogService implements computeTypes.HetznerCatalogService for testing
+type MockHetznerCatalogService struct {
+	LocationsFunc   func(_ context.Context) ([]*hcloud.Location, error)
+	ServerTypesFunc func(_ context.Context) ([]*hcloud.ServerType, error)
+	ImagesFunc      func(_ context.Context) ([]*hcloud.Image, error)
+}
+
+// ResetToStandard resets the catalog service back to standard success responses.
+// The catalog offers DefaultHetznerServerType in DefaultHetznerLocation and "nbg1",
+// and a deprecated server type.
+func (s *MockHetznerCatalogService) ResetToStandard() {
+	fsn1 := &hcloud.Location{Name: DefaultHetznerLocation, Description: "Falkenstein DC Park 1"}
+	nbg1 := &hcloud.Location{Name: "nbg1", Description: "Nuremberg DC Park 1"}
+	s.LocationsFunc = func(_ context.Context) ([]*hcloud.Location, error) {
+		return []*hcloud.Location{fsn1, nbg1}, nil
+	}
+	s.ServerTypesFunc = func(_ context.Context) ([]*hcloud.ServerType, error) {
+		deprecated := &hcloud.ServerType{Name: "cx11", Cores: 1, Memory: 2, Disk: 20}
+		deprecated.Deprecation = &hcloud.DeprecationInfo{}
+		return []*hcloud.ServerType{
+			{
+				Name: DefaultHetznerServerType, Cores: 2, Memory: 4, Disk: 40,
+				Pricings: []hcloud.ServerTypeLocationPricing{{Location: fsn1}, {Location: nbg1}},
+			},
+			deprecated,
+		}, nil
+	}
+	s.ImagesFunc = func(_ context.Context) ([]*hcloud.Image, error) {
+		return []*hcloud.Image{
+			{ID: 1, Name: DefaultHetznerImage, Description: "Ubuntu 22.04", OSFlavor: "ubuntu", Architecture: hcloud.ArchitectureX86},
+			{ID: 2, Name: DefaultHetznerImage, Description: "Ubuntu 22.04", OSFlavor: "ubuntu", Architecture: hcloud.ArchitectureARM},
+		}, nil
+	}
+}
+
+func (s *MockHetznerCatalogService) simulateError(err error) {
+	s.LocationsFunc = func(_ context.Context) ([]*hcloud.Location, error) { return nil, err }
+	s.ServerTypesFunc = func(_ context.Context) ([]*hcloud.ServerType, error) { return nil, err }
+	s.ImagesFunc = func(_ context.Context) ([]*hcloud.Image, error) { return nil, err }
+}
+
+// Locations calls the mocked Locations function
+func (s *MockHetznerCatalogService) Locations(ctx context.Context) ([]*hcloud.Location, error) {
+	return s.LocationsFunc(ctx)
+}
+
+// ServerTypes calls the mocked ServerTypes function
+func (s *MockHetznerCatalogService) ServerTypes(ctx context.Context) ([]*hcloud.ServerType, error) {
+	return s.ServerTypesFunc(ctx)
+}
+
+// Images calls the mocked Images function
+func (s *MockHetznerCatalogService) Images(ctx context.Context) ([]*hcloud.Image, error) {
+	return s.ImagesFunc(ctx)
+}
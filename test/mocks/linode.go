@@ -0,0 +1,361 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/linode/linodego"
+)
+
+// This file contains the mock implementations for the Linode API
+
+// Default test values for Linode resources
+var (
+	DefaultLinodeInstanceID = 53001
+	DefaultLinodeInstanceIP = "192.0.2.20"
+	DefaultLinodeKeyID      = 53101
+	DefaultLinodeKeyName    = "test-key"
+	DefaultLinodePublicKey  = "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIMockLinodeKey talis@test"
+	DefaultLinodeVolumeID   = 53201
+	DefaultLinodeRegion     = "us-east"
+	DefaultLinodeType       = "g6-standard-2"
+	DefaultLinodeImage      = "linode/ubuntu22.04"
+)
+
+// Linode error responses
+var (
+	ErrLinodeNotFound       = &linodego.Error{Code: 404, Message: "Linode API: not found"}
+	ErrLinodeRateLimit      = &linodego.Error{Code: 429, Message: "Linode API: rate limit exceeded"}
+	ErrLinodeAuthentication = &linodego.Error{Code: 401, Message: "Linode API: invalid token"}
+)
+
+// MockLinodeClient implements computeTypes.LinodeClient for testing.
+// Every call is routed through an overridable function field, and the
+// standard responses keep track of the created instances and volumes.
+type MockLinodeClient struct {
+	GetProfileFunc          func(ctx context.Context) (*linodego.Profile, error)
+	ListSSHKeysFunc         func(ctx context.Context, opts *linodego.ListOptions) ([]linodego.SSHKey, error)
+	CreateInstanceFunc      func(ctx context.Context, opts linodego.InstanceCreateOptions) (*linodego.Instance, error)
+	GetInstanceFunc         func(ctx context.Context, linodeID int) (*linodego.Instance, error)
+	ListInstancesFunc       func(ctx context.Context, opts *linodego.ListOptions) ([]linodego.Instance, error)
+	DeleteInstanceFunc      func(ctx context.Context, linodeID int) error
+	ListInstanceVolumesFunc func(ctx context.Context, linodeID int, opts *linodego.ListOptions) ([]linodego.Volume, error)
+	CreateVolumeFunc        func(ctx context.Context, opts linodego.VolumeCreateOptions) (*linodego.Volume, error)
+	GetVolumeFunc           func(ctx context.Context, volumeID int) (*linodego.Volume, error)
+	DeleteVolumeFunc        func(ctx context.Context, volumeID int) error
+	ListRegionsFunc         func(ctx context.Context, opts *linodego.ListOptions) ([]linodego.Region, error)
+	ListTypesFunc           func(ctx context.Context, opts *linodego.ListOptions) ([]linodego.LinodeType, error)
+	ListImagesFunc          func(ctx context.Context, opts *linodego.ListOptions) ([]linodego.Image, error)
+
+	// LastCreateOpts records the options of the most recent CreateInstance call
+	LastCreateOpts linodego.InstanceCreateOptions
+	// DeletedInstances records the IDs of all deleted instances
+	DeletedInstances []int
+	// DeletedVolumes records the IDs of all deleted volumes
+	DeletedVolumes []int
+
+	mu           sync.Mutex
+	instances    map[int]*linodego.Instance
+	volumes      map[int]*linodego.Volume
+	nextID       int
+	nextVolumeID int
+}
+
+// NewMockLinodeClient creates a new MockLinodeClient with standard responses
+func NewMockLinodeClient() *MockLinodeClient {
+	c := &MockLinodeClient{}
+	c.ResetToStandard()
+	return c
+}
+
+// NewDefaultLinodeInstance returns a running instance with the default public IP
+func NewDefaultLinodeInstance(id int, label string) *linodego.Instance {
+	ip := net.ParseIP(DefaultLinodeInstanceIP)
+	return &linodego.Instance{
+		ID:     id,
+		Label:  label,
+		Region: DefaultLinodeRegion,
+		Type:   DefaultLinodeType,
+		Status: linodego.InstanceRunning,
+		IPv4:   []*net.IP{&ip},
+	}
+}
+
+// ResetToStandard resets the mock back to its standard success responses and forgets
+// all recorded resources
+func (c *MockLinodeClient) ResetToStandard() {
+	c.mu.Lock()
+	c.LastCreateOpts = linodego.InstanceCreateOptions{}
+	c.DeletedInstances = nil
+	c.DeletedVolumes = nil
+	c.instances = make(map[int]*linodego.Instance)
+	c.volumes = make(map[int]*linodego.Volume)
+	c.nextID = DefaultLinodeInstanceID
+	c.nextVolumeID = DefaultLinodeVolumeID
+	c.mu.Unlock()
+
+	c.GetProfileFunc = func(_ context.Context) (*linodego.Profile, error) {
+		return &linodego.Profile{Username: "talis"}, nil
+	}
+	c.ListSSHKeysFunc = func(_ context.Context, _ *linodego.ListOptions) ([]linodego.SSHKey, error) {
+		return []linodego.SSHKey{
+			{ID: DefaultLinodeKeyID, Label: DefaultLinodeKeyName, SSHKey: DefaultLinodePublicKey},
+		}, nil
+	}
+	c.CreateInstanceFunc = func(_ context.Context, opts linodego.InstanceCreateOptions) (*linodego.Instance, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		instance := NewDefaultLinodeInstance(c.nextID, opts.Label)
+		instance.Region = opts.Region
+		instance.Type = opts.Type
+		instance.Image = opts.Image
+		instance.Tags = opts.Tags
+		c.instances[instance.ID] = instance
+		c.nextID++
+		return instance, nil
+	}
+	c.GetInstanceFunc = func(_ context.Context, linodeID int) (*linodego.Instance, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if instance, ok := c.instances[linodeID]; ok {
+			return instance, nil
+		}
+		return NewDefaultLinodeInstance(linodeID, fmt.Sprintf("linode-%d", linodeID)), nil
+	}
+	c.ListInstancesFunc = func(_ context.Context, _ *linodego.ListOptions) ([]linodego.Instance, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		instances := []linodego.Instance{}
+		for _, instance := range c.instances {
+			instances = append(instances, *instance)
+		}
+		return instances, nil
+	}
+	c.DeleteInstanceFunc = func(_ context.Context, linodeID int) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.instances, linodeID)
+		// Deleting a Linode detaches its volumes
+		for _, volume := range c.volumes {
+			if volume.LinodeID != nil && *volume.LinodeID == linodeID {
+				volume.LinodeID = nil
+			}
+		}
+		return nil
+	}
+	c.ListInstanceVolumesFunc = func(_ context.Context, linodeID int, _ *linodego.ListOptions) ([]linodego.Volume, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		volumes := []linodego.Volume{}
+		for _, volume := range c.volumes {
+			if volume.LinodeID != nil && *volume.LinodeID == linodeID {
+				volumes = append(volumes, *volume)
+			}
+		}
+		return volumes, nil
+	}
+	c.CreateVolumeFunc = func(_ context.Context, opts linodego.VolumeCreateOptions) (*linodego.Volume, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		linodeID := opts.LinodeID
+		volume := &linodego.Volume{
+			ID:       c.nextVolumeID,
+			Label:    opts.Label,
+			Size:     opts.Size,
+			Region:   DefaultLinodeRegion,
+			Status:   linodego.VolumeActive,
+			LinodeID: &linodeID,
+			Tags:     opts.Tags,
+		}
+		if instance, ok := c.instances[linodeID]; ok {
+			volume.Region = instance.Region
+		}
+		c.volumes[volume.ID] = volume
+		c.nextVolumeID++
+		v := *volume
+		return &v, nil
+	}
+	c.GetVolumeFunc = func(_ context.Context, volumeID int) (*linodego.Volume, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		volume, ok := c.volumes[volumeID]
+		if !ok {
+			return nil, ErrLinodeNotFound
+		}
+		v := *volume
+		return &v, nil
+	}
+	c.DeleteVolumeFunc = func(_ context.Context, volumeID int) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.volumes, volumeID)
+		return nil
+	}
+	c.ListRegionsFunc = func(_ context.Context, _ *linodego.ListOptions) ([]linodego.Region, error) {
+		return []linodego.Region{
+			{ID: DefaultLinodeRegion, Label: "Newark, NJ", Country: "us", Status: "ok"},
+			{ID: "eu-central", Label: "Frankfurt, DE", Country: "de", Status: "ok"},
+			{ID: "ap-south", Label: "Singapore, SG", Country: "sg", Status: "ok"},
+		}, nil
+	}
+	c.ListTypesFunc = func(_ context.Context, _ *linodego.ListOptions) ([]linodego.LinodeType, error) {
+		return []linodego.LinodeType{
+			{ID: "g6-nanode-1", Label: "Nanode 1GB", VCPUs: 1, Memory: 1024, Disk: 25600},
+			{ID: DefaultLinodeType, Label: "Linode 4GB", VCPUs: 2, Memory: 4096, Disk: 81920},
+		}, nil
+	}
+	c.ListImagesFunc = func(_ context.Context, _ *linodego.ListOptions) ([]linodego.Image, error) {
+		return []linodego.Image{
+			{ID: DefaultLinodeImage, Label: "Ubuntu 22.04 LTS", Vendor: "Ubuntu", IsPublic: true, Status: linodego.ImageStatusAvailable},
+			{ID: "linode/ubuntu16.04lts", Label: "Ubuntu 16.04 LTS", Vendor: "Ubuntu", IsPublic: true, Deprecated: true, Status: linodego.ImageStatusAvailable},
+			{ID: "private/12345", Label: "talis-snapshot", IsPublic: false, Status: linodego.ImageStatusAvailable},
+		}, nil
+	}
+}
+
+// SimulateAuthenticationFailure configures all calls to return authentication errors
+func (c *MockLinodeClient) SimulateAuthenticationFailure() {
+	c.simulateError(ErrLinodeAuthentication)
+}
+
+// SimulateNotFound configures instance lookups and deletion to return not found errors
+func (c *MockLinodeClient) SimulateNotFound() {
+	c.GetInstanceFunc = func(_ context.Context, _ int) (*linodego.Instance, error) {
+		return nil, ErrLinodeNotFound
+	}
+	c.DeleteInstanceFunc = func(_ context.Context, _ int) error {
+		return ErrLinodeNotFound
+	}
+	c.ListInstanceVolumesFunc = func(_ context.Context, _ int, _ *linodego.ListOptions) ([]linodego.Volume, error) {
+		return nil, ErrLinodeNotFound
+	}
+}
+
+// SimulateRateLimit configures all calls to return rate limit errors
+func (c *MockLinodeClient) SimulateRateLimit() {
+	c.simulateError(ErrLinodeRateLimit)
+}
+
+func (c *MockLinodeClient) simulateError(err error) {
+	c.GetProfileFunc = func(_ context.Context) (*linodego.Profile, error) {
+		return nil, err
+	}
+	c.ListSSHKeysFunc = func(_ context.Context, _ *linodego.ListOptions) ([]linodego.SSHKey, error) {
+		return nil, err
+	}
+	c.CreateInstanceFunc = func(_ context.Context, _ linodego.InstanceCreateOptions) (*linodego.Instance, error) {
+		return nil, err
+	}
+	c.GetInstanceFunc = func(_ context.Context, _ int) (*linodego.Instance, error) {
+		return nil, err
+	}
+	c.ListInstancesFunc = func(_ context.Context, _ *linodego.ListOptions) ([]linodego.Instance, error) {
+		return nil, err
+	}
+	c.DeleteInstanceFunc = func(_ context.Context, _ int) error {
+		return err
+	}
+	c.ListInstanceVolumesFunc = func(_ context.Context, _ int, _ *linodego.ListOptions) ([]linodego.Volume, error) {
+		return nil, err
+	}
+	c.CreateVolumeFunc = func(_ context.Context, _ linodego.VolumeCreateOptions) (*linodego.Volume, error) {
+		return nil, err
+	}
+	c.GetVolumeFunc = func(_ context.Context, _ int) (*linodego.Volume, error) {
+		return nil, err
+	}
+	c.DeleteVolumeFunc = func(_ context.Context, _ int) error {
+		return err
+	}
+	c.ListRegionsFunc = func(_ context.Context, _ *linodego.ListOptions) ([]linodego.Region, error) {
+		return nil, err
+	}
+	c.ListTypesFunc = func(_ context.Context, _ *linodego.ListOptions) ([]linodego.LinodeType, error) {
+		return nil, err
+	}
+	c.ListImagesFunc = func(_ context.Context, _ *linodego.ListOptions) ([]linodego.Image, error) {
+		return nil, err
+	}
+}
+
+// GetProfile calls the mocked GetProfile function
+func (c *MockLinodeClient) GetProfile(ctx context.Context) (*linodego.Profile, error) {
+	return c.GetProfileFunc(ctx)
+}
+
+// ListSSHKeys calls the mocked ListSSHKeys function
+func (c *MockLinodeClient) ListSSHKeys(ctx context.Context, opts *linodego.ListOptions) ([]linodego.SSHKey, error) {
+	return c.ListSSHKeysFunc(ctx, opts)
+}
+
+// CreateInstance calls the mocked CreateInstance function and records its options
+func (c *MockLinodeClient) CreateInstance(ctx context.Context, opts linodego.InstanceCreateOptions) (*linodego.Instance, error) {
+	c.mu.Lock()
+	c.LastCreateOpts = opts
+	c.mu.Unlock()
+	return c.CreateInstanceFunc(ctx, opts)
+}
+
+// GetInstance calls the mocked GetInstance function
+func (c *MockLinodeClient) GetInstance(ctx context.Context, linodeID int) (*linodego.Instance, error) {
+	return c.GetInstanceFunc(ctx, linodeID)
+}
+
+// ListInstances calls the mocked ListInstances function
+func (c *MockLinodeClient) ListInstances(ctx context.Context, opts *linodego.ListOptions) ([]linodego.Instance, error) {
+	return c.ListInstancesFunc(ctx, opts)
+}
+
+// DeleteInstance calls the mocked DeleteInstance function and records the deleted instance
+func (c *MockLinodeClient) DeleteInstance(ctx context.Context, linodeID int) error {
+	if err := c.DeleteInstanceFunc(ctx, linodeID); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.DeletedInstances = append(c.DeletedInstances, linodeID)
+	c.mu.Unlock()
+	return nil
+}
+
+// ListInstanceVolumes calls the mocked ListInstanceVolumes function
+func (c *MockLinodeClient) ListInstanceVolumes(ctx context.Context, linodeID int, opts *linodego.ListOptions) ([]linodego.Volume, error) {
+	return c.ListInstanceVolumesFunc(ctx, linodeID, opts)
+}
+
+// CreateVolume calls the mocked CreateVolume function
+func (c *MockLinodeClient) CreateVolume(ctx context.Context, opts linodego.VolumeCreateOptions) (*linodego.Volume, error) {
+	return c.CreateVolumeFunc(ctx, opts)
+}
+
+// GetVolume calls the mocked GetVolume function
+func (c *MockLinodeClient) GetVolume(ctx context.Context, volumeID int) (*linodego.Volume, error) {
+	return c.GetVolumeFunc(ctx, volumeID)
+}
+
+// DeleteVolume calls the mocked DeleteVolume function and records the deleted volume
+func (c *MockLinodeClient) DeleteVolume(ctx context.Context, volumeID int) error {
+	if err := c.DeleteVolumeFunc(ctx, volumeID); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.DeletedVolumes = append(c.DeletedVolumes, volumeID)
+	c.mu.Unlock()
+	return nil
+}
+
+// ListRegions calls the mocked ListRegions function
+func (c *MockLinodeClient) ListRegions(ctx context.Context, opts *linodego.ListOptions) ([]linodego.Region, error) {
+	return c.ListRegionsFunc(ctx, opts)
+}
+
+// ListTypes calls the mocked ListTypes function
+func (c *MockLinodeClient) ListTypes(ctx context.Context, opts *linodego.ListOptions) ([]linodego.LinodeType, error) {
+	return c.ListTypesFunc(ctx, opts)
+}
+
+// ListImages calls the mocked ListImages function
+func (c *MockLinodeClient) ListImages(ctx context.Context, opts *linodego.ListOptions) ([]linodego.Image, error) {
+	return c.ListImagesFunc(ctx, opts)
+}
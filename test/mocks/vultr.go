@@ -0,0 +1,415 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	computeTypes "github.com/celestiaorg/talis/internal/compute/types"
+)
+
+// This file contains the mock implementations for the Vultr API
+
+// Default test values for Vultr resources
+var (
+	DefaultVultrInstanceIP = "192.0.2.30"
+	DefaultVultrKeyID      = "vultr-key-0001"
+	DefaultVultrKeyName    = "test-key"
+	DefaultVultrRegion     = "ewr"
+	DefaultVultrPlan       = "vc2-1c-1gb"
+	DefaultVultrOSID       = 1743
+)
+
+// Vultr error responses
+var (
+	ErrVultrNotFound       = &computeTypes.VultrAPIError{StatusCode: 404, Message: "Vultr API: not found"}
+	ErrVultrRateLimit      = &computeTypes.VultrAPIError{StatusCode: 429, Message: "Vultr API: rate limit exceeded"}
+	ErrVultrAuthentication = &computeTypes.VultrAPIError{StatusCode: 401, Message: "Vultr API: invalid API key"}
+)
+
+// MockVultrClient implements computeTypes.VultrClient for testing.
+// Every call is routed through an overridable function field, and the
+// standard responses keep track of the SSH keys, instances and blocks.
+type MockVultrClient struct {
+	ValidateFunc       func(ctx context.Context) error
+	ListSSHKeysFunc    func(ctx context.Context) ([]computeTypes.VultrSSHKey, error)
+	CreateSSHKeyFunc   func(ctx context.Context, req *computeTypes.VultrSSHKeyCreateRequest) (*computeTypes.VultrSSHKey, error)
+	CreateInstanceFunc func(ctx context.Context, req *computeTypes.VultrInstanceCreateRequest) (*computeTypes.VultrInstance, error)
+	GetInstanceFunc    func(ctx context.Context, instanceID string) (*computeTypes.VultrInstance, error)
+	ListInstancesFunc  func(ctx context.Context, tag string) ([]computeTypes.VultrInstance, error)
+	UpdateTagsFunc     func(ctx context.Context, instanceID string, tags []string) error
+	DeleteInstanceFunc func(ctx context.Context, instanceID string) error
+	CreateBlockFunc    func(ctx context.Context, req *computeTypes.VultrBlockCreateRequest) (*computeTypes.VultrBlock, error)
+	GetBlockFunc       func(ctx context.Context, blockID string) (*computeTypes.VultrBlock, error)
+	ListBlocksFunc     func(ctx context.Context) ([]computeTypes.VultrBlock, error)
+	AttachBlockFunc    func(ctx context.Context, blockID, instanceID string) error
+	DetachBlockFunc    func(ctx context.Context, blockID string) error
+	DeleteBlockFunc    func(ctx context.Context, blockID string) error
+	ListRegionsFunc    func(ctx context.Context) ([]computeTypes.VultrRegion, error)
+	ListPlansFunc      func(ctx context.Context) ([]computeTypes.VultrPlan, error)
+	ListOSFunc         func(ctx context.Context) ([]computeTypes.VultrOS, error)
+
+	// LastCreateRequest records the most recent CreateInstance request
+	LastCreateRequest *computeTypes.VultrInstanceCreateRequest
+	// DeletedInstances records the IDs of all deleted instances
+	DeletedInstances []string
+	// DeletedBlocks records the IDs of all deleted blocks
+	DeletedBlocks []string
+
+	mu        sync.Mutex
+	keys      []computeTypes.VultrSSHKey
+	instances map[string]*computeTypes.VultrInstance
+	blocks    map[string]*computeTypes.VultrBlock
+	nextID    int
+}
+
+// NewMockVultrClient creates a new MockVultrClient with standard responses
+func NewMockVultrClient() *MockVultrClient {
+	c := &MockVultrClient{}
+	c.ResetToStandard()
+	return c
+}
+
+// ResetToStandard resets the mock back to its standard success responses.
+// Only DefaultVultrKeyName is registered as SSH key.
+func (c *MockVultrClient) ResetToStandard() {
+	c.mu.Lock()
+	c.LastCreateRequest = nil
+	c.DeletedInstances = nil
+	c.DeletedBlocks = nil
+	c.keys = []computeTypes.VultrSSHKey{{ID: DefaultVultrKeyID, Name: DefaultVultrKeyName}}
+	c.instances = make(map[string]*computeTypes.VultrInstance)
+	c.blocks = make(map[string]*computeTypes.VultrBlock)
+	c.nextID = 1
+	c.mu.Unlock()
+
+	c.ValidateFunc = func(_ context.Context) error { return nil }
+	c.ListSSHKeysFunc = func(_ context.Context) ([]computeTypes.VultrSSHKey, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return append([]computeTypes.VultrSSHKey{}, c.keys...), nil
+	}
+	c.CreateSSHKeyFunc = func(_ context.Context, req *computeTypes.VultrSSHKeyCreateRequest) (*computeTypes.VultrSSHKey, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		key := computeTypes.VultrSSHKey{ID: c.newID("key"), Name: req.Name, SSHKey: req.SSHKey}
+		c.keys = append(c.keys, key)
+		return &key, nil
+	}
+	c.CreateInstanceFunc = func(_ context.Context, req *computeTypes.VultrInstanceCreateRequest) (*computeTypes.VultrInstance, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		instance := &computeTypes.VultrInstance{
+			ID:          c.newID("instance"),
+			Label:       req.Label,
+			Region:      req.Region,
+			Plan:        req.Plan,
+			OSID:        req.OSID,
+			ImageID:     req.ImageID,
+			MainIP:      DefaultVultrInstanceIP,
+			Status:      "active",
+			PowerStatus: "running",
+			Tags:        req.Tags,
+		}
+		c.instances[instance.ID] = instance
+		i := *instance
+		// The API returns the instance before it has an IP
+		i.MainIP = "0.0.0.0"
+		i.Status = "pending"
+		return &i, nil
+	}
+	c.GetInstanceFunc = func(_ context.Context, instanceID string) (*computeTypes.VultrInstance, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		instance, ok := c.instances[instanceID]
+		if !ok {
+			return nil, ErrVultrNotFound
+		}
+		i := *instance
+		return &i, nil
+	}
+	c.ListInstancesFunc = func(_ context.Context, tag string) ([]computeTypes.VultrInstance, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		instances := []computeTypes.VultrInstance{}
+		for _, instance := range c.instances {
+			if tag == "" || containsString(instance.Tags, tag) {
+				instances = append(instances, *instance)
+			}
+		}
+		return instances, nil
+	}
+	c.UpdateTagsFunc = func(_ context.Context, instanceID string, tags []string) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		instance, ok := c.instances[instanceID]
+		if !ok {
+			return ErrVultrNotFound
+		}
+		instance.Tags = append([]string{}, tags...)
+		return nil
+	}
+	c.DeleteInstanceFunc = func(_ context.Context, instanceID string) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if _, ok := c.instances[instanceID]; !ok {
+			return ErrVultrNotFound
+		}
+		delete(c.instances, instanceID)
+		return nil
+	}
+	c.CreateBlockFunc = func(_ context.Context, req *computeTypes.VultrBlockCreateRequest) (*computeTypes.VultrBlock, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		block := &computeTypes.VultrBlock{
+			ID:     c.newID("block"),
+			Label:  req.Label,
+			Region: req.Region,
+			SizeGB: req.SizeGB,
+			Status: "active",
+		}
+		c.blocks[block.ID] = block
+		b := *block
+		return &b, nil
+	}
+	c.GetBlockFunc = func(_ context.Context, blockID string) (*computeTypes.VultrBlock, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		block, ok := c.blocks[blockID]
+		if !ok {
+			return nil, ErrVultrNotFound
+		}
+		b := *block
+		return &b, nil
+	}
+	c.ListBlocksFunc = func(_ context.Context) ([]computeTypes.VultrBlock, error) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		blocks := []computeTypes.VultrBlock{}
+		for _, block := range c.blocks {
+			blocks = append(blocks, *block)
+		}
+		return blocks, nil
+	}
+	c.AttachBlockFunc = func(_ context.Context, blockID, instanceID string) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		block, ok := c.blocks[blockID]
+		if !ok {
+			return ErrVultrNotFound
+		}
+		block.AttachedToInstance = instanceID
+		return nil
+	}
+	c.DetachBlockFunc = func(_ context.Context, blockID string) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		block, ok := c.blocks[blockID]
+		if !ok {
+			return ErrVultrNotFound
+		}
+		block.AttachedToInstance = ""
+		return nil
+	}
+	c.DeleteBlockFunc = func(_ context.Context, blockID string) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.blocks, blockID)
+		return nil
+	}
+	c.ListRegionsFunc = func(_ context.Context) ([]computeTypes.VultrRegion, error) {
+		return []computeTypes.VultrRegion{
+			{ID: DefaultVultrRegion, City: "New Jersey", Country: "US", Continent: "North America"},
+			{ID: "fra", City: "Frankfurt", Country: "DE", Continent: "Europe"},
+		}, nil
+	}
+	c.ListPlansFunc = func(_ context.Context) ([]computeTypes.VultrPlan, error) {
+		return []computeTypes.VultrPlan{
+			{ID: DefaultVultrPlan, VCPUCount: 1, RAM: 1024, Disk: 25, Type: "vc2", Locations: []string{DefaultVultrRegion, "fra"}},
+			{ID: "vc2-2c-4gb", VCPUCount: 2, RAM: 4096, Disk: 80, Type: "vc2", Locations: []string{DefaultVultrRegion}},
+		}, nil
+	}
+	c.ListOSFunc = func(_ context.Context) ([]computeTypes.VultrOS, error) {
+		return []computeTypes.VultrOS{
+			{ID: DefaultVultrOSID, Name: "Ubuntu 22.04 LTS x64", Arch: "x64", Family: "ubuntu"},
+			{ID: 2136, Name: "Debian 12 x64 (bookworm)", Arch: "x64", Family: "debian"},
+		}, nil
+	}
+}
+
+// newID returns a new unique mock resource ID. The caller must hold c.mu.
+func (c *MockVultrClient) newID(kind string) string {
+	id := fmt.Sprintf("%s-%04d", kind, c.nextID)
+	c.nextID++
+	return id
+}
+
+// AddInstance registers an instance that was created outside of Talis and returns its ID
+func (c *MockVultrClient) AddInstance(instance computeTypes.VultrInstance) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if instance.ID == "" {
+		instance.ID = c.newID("instance")
+	}
+	c.instances[instance.ID] = &instance
+	return instance.ID
+}
+
+// Blocks returns a snapshot of all blocks known to the mock
+func (c *MockVultrClient) Blocks() []computeTypes.VultrBlock {
+	blocks, _ := c.ListBlocksFunc(context.Background())
+	return blocks
+}
+
+// SSHKeys returns a snapshot of all SSH keys known to the mock
+func (c *MockVultrClient) SSHKeys() []computeTypes.VultrSSHKey {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]computeTypes.VultrSSHKey{}, c.keys...)
+}
+
+// SimulateAuthenticationFailure configures all calls to return authentication errors
+func (c *MockVultrClient) SimulateAuthenticationFailure() {
+	c.simulateError(ErrVultrAuthentication)
+}
+
+// SimulateRateLimit configures all calls to return rate limit errors
+func (c *MockVultrClient) SimulateRateLimit() {
+	c.simulateError(ErrVultrRateLimit)
+}
+
+func (c *MockVultrClient) simulateError(err error) {
+	c.ValidateFunc = func(_ context.Context) error { return err }
+	c.ListSSHKeysFunc = func(_ context.Context) ([]computeTypes.VultrSSHKey, error) { return nil, err }
+	c.CreateSSHKeyFunc = func(_ context.Context, _ *computeTypes.VultrSSHKeyCreateRequest) (*computeTypes.VultrSSHKey, error) {
+		return nil, err
+	}
+	c.CreateInstanceFunc = func(_ context.Context, _ *computeTypes.VultrInstanceCreateRequest) (*computeTypes.VultrInstance, error) {
+		return nil, err
+	}
+	c.GetInstanceFunc = func(_ context.Context, _ string) (*computeTypes.VultrInstance, error) { return nil, err }
+	c.ListInstancesFunc = func(_ context.Context, _ string) ([]computeTypes.VultrInstance, error) { return nil, err }
+	c.UpdateTagsFunc = func(_ context.Context, _ string, _ []string) error { return err }
+	c.DeleteInstanceFunc = func(_ context.Context, _ string) error { return err }
+	c.CreateBlockFunc = func(_ context.Context, _ *computeTypes.VultrBlockCreateRequest) (*computeTypes.VultrBlock, error) {
+		return nil, err
+	}
+	c.GetBlockFunc = func(_ context.Context, _ string) (*computeTypes.VultrBlock, error) { return nil, err }
+	c.ListBlocksFunc = func(_ context.Context) ([]computeTypes.VultrBlock, error) { return nil, err }
+	c.AttachBlockFunc = func(_ context.Context, _, _ string) error { return err }
+	c.DetachBlockFunc = func(_ context.Context, _ string) error { return err }
+	c.DeleteBlockFunc = func(_ context.Context, _ string) error { return err }
+	c.ListRegionsFunc = func(_ context.Context) ([]computeTypes.VultrRegion, error) { return nil, err }
+	c.ListPlansFunc = func(_ context.Context) ([]computeTypes.VultrPlan, error) { return nil, err }
+	c.ListOSFunc = func(_ context.Context) ([]computeTypes.VultrOS, error) { return nil, err }
+}
+
+// ValidateCredentials calls the mocked ValidateFunc
+func (c *MockVultrClient) ValidateCredentials(ctx context.Context) error {
+	return c.ValidateFunc(ctx)
+}
+
+// ListSSHKeys calls the mocked ListSSHKeys function
+func (c *MockVultrClient) ListSSHKeys(ctx context.Context) ([]computeTypes.VultrSSHKey, error) {
+	return c.ListSSHKeysFunc(ctx)
+}
+
+// CreateSSHKey calls the mocked CreateSSHKey function
+func (c *MockVultrClient) CreateSSHKey(ctx context.Context, req *computeTypes.VultrSSHKeyCreateRequest) (*computeTypes.VultrSSHKey, error) {
+	return c.CreateSSHKeyFunc(ctx, req)
+}
+
+// CreateInstance calls the mocked CreateInstance function and records the request
+func (c *MockVultrClient) CreateInstance(ctx context.Context, req *computeTypes.VultrInstanceCreateRequest) (*computeTypes.VultrInstance, error) {
+	c.mu.Lock()
+	c.LastCreateRequest = req
+	c.mu.Unlock()
+	return c.CreateInstanceFunc(ctx, req)
+}
+
+// GetInstance calls the mocked GetInstance function
+func (c *MockVultrClient) GetInstance(ctx context.Context, instanceID string) (*computeTypes.VultrInstance, error) {
+	return c.GetInstanceFunc(ctx, instanceID)
+}
+
+// ListInstances calls the mocked ListInstances function
+func (c *MockVultrClient) ListInstances(ctx context.Context, tag string) ([]computeTypes.VultrInstance, error) {
+	return c.ListInstancesFunc(ctx, tag)
+}
+
+// UpdateInstanceTags calls the mocked UpdateTags function
+func (c *MockVultrClient) UpdateInstanceTags(ctx context.Context, instanceID string, tags []string) error {
+	return c.UpdateTagsFunc(ctx, instanceID, tags)
+}
+
+// DeleteInstance calls the mocked DeleteInstance function and records the deleted instance
+func (c *MockVultrClient) DeleteInstance(ctx context.Context, instanceID string) error {
+	if err := c.DeleteInstanceFunc(ctx, instanceID); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.DeletedInstances = append(c.DeletedInstances, instanceID)
+	c.mu.Unlock()
+	return nil
+}
+
+// CreateBlock calls the mocked CreateBlock function
+func (c *MockVultrClient) CreateBlock(ctx context.Context, req *computeTypes.VultrBlockCreateRequest) (*computeTypes.VultrBlock, error) {
+	return c.CreateBlockFunc(ctx, req)
+}
+
+// GetBlock calls the mocked GetBlock function
+func (c *MockVultrClient) GetBlock(ctx context.Context, blockID string) (*computeTypes.VultrBlock, error) {
+	return c.GetBlockFunc(ctx, blockID)
+}
+
+// ListBlocks calls the mocked ListBlocks function
+func (c *MockVultrClient) ListBlocks(ctx context.Context) ([]computeTypes.VultrBlock, error) {
+	return c.ListBlocksFunc(ctx)
+}
+
+// AttachBlock calls the mocked AttachBlock function
+func (c *MockVultrClient) AttachBlock(ctx context.Context, blockID, instanceID string) error {
+	return c.AttachBlockFunc(ctx, blockID, instanceID)
+}
+
+// DetachBlock calls the mocked DetachBlock function
+func (c *MockVultrClient) DetachBlock(ctx context.Context, blockID string) error {
+	return c.DetachBlockFunc(ctx, blockID)
+}
+
+// DeleteBlock calls the mocked DeleteBlock function and records the deleted block
+func (c *MockVultrClient) DeleteBlock(ctx context.Context, blockID string) error {
+	if err := c.DeleteBlockFunc(ctx, blockID); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.DeletedBlocks = append(c.DeletedBlocks, blockID)
+	c.mu.Unlock()
+	return nil
+}
+
+// ListRegions calls the mocked ListRegions function
+func (c *MockVultrClient) ListRegions(ctx context.Context) ([]computeTypes.VultrRegion, error) {
+	return c.ListRegionsFunc(ctx)
+}
+
+// ListPlans calls the mocked ListPlans function
+func (c *MockVultrClient) ListPlans(ctx context.Context) ([]computeTypes.VultrPlan, error) {
+	return c.ListPlansFunc(ctx)
+}
+
+// ListOS calls the mocked ListOS function
+func (c *MockVultrClient) ListOS(ctx context.Context) ([]computeTypes.VultrOS, error) {
+	return c.ListOSFunc(ctx)
+}
+
+// containsString reports whether s is in list
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
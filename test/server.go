@@ -8,6 +8,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/adaptor"
 
+	"github.com/celestiaorg/talis/internal/compute"
 	"github.com/celestiaorg/talis/internal/db/repos"
 	"github.com/celestiaorg/talis/internal/logger"
 	"github.com/celestiaorg/talis/internal/services"
@@ -32,28 +33,42 @@ func SetupServer(suite *Suite) {
 	userService := services.NewUserService(suite.UserRepo)
 	projectService := services.NewProjectService(suite.ProjectRepo)
 	taskService := services.NewTaskService(suite.TaskRepo, projectService)
-	instanceService := services.NewInstanceService(suite.InstanceRepo, taskService, projectService)
+	providers := compute.NewProviderRegistry()
+	instanceService := services.NewInstanceService(suite.InstanceRepo, taskService, projectService, providers)
 	sshKeyRepo := repos.NewSSHKeyRepository(suite.DB)
 	sshKeyService := services.NewSSHKeyService(sshKeyRepo)
+	driftEventService := services.NewDriftEventService(repos.NewDriftEventRepository(suite.DB))
+	catalogService := services.NewCatalogService(providers, services.DefaultCatalogTTL)
+	webhookService := services.NewWebhookService(repos.NewWebhookRepository(suite.DB), suite.UserRepo)
+	taskService.WithWebhooks(webhookService)
+	instanceService.WithWebhooks(webhookService)
+	playbookService := services.NewPlaybookService(repos.NewPlaybookRepository(suite.DB))
+	instanceService.WithPlaybooks(playbookService)
 
 	// Create handlers
-	apiHandler := handlers.NewAPIHandler(instanceService, projectService, taskService, userService)
+	apiHandler := handlers.NewAPIHandler(instanceService, projectService, taskService, userService, driftEventService, catalogService, webhookService)
 	instanceHandler := handlers.NewInstanceHandler(apiHandler)
+	providerHandler := handlers.NewProviderHandler(apiHandler)
 	projectHandler := handlers.NewProjectHandlers(apiHandler)
 	taskHandler := handlers.NewTaskHandlers(apiHandler)
 	userHandler := handlers.NewUserHandler(apiHandler)
+	webhookHandler := handlers.NewWebhookHandler(apiHandler)
 	sshKeyHandler := &handlers.SSHKeyHandlers{
 		SSHKeyService: sshKeyService,
 	}
+	playbookHandler := &handlers.PlaybookHandlers{
+		PlaybookService: playbookService,
+	}
 	rpcHandler := &handlers.RPCHandler{
-		ProjectHandlers: projectHandler,
-		TaskHandlers:    taskHandler,
-		UserHandlers:    userHandler,
-		SSHKeyHandlers:  sshKeyHandler,
+		ProjectHandlers:  projectHandler,
+		TaskHandlers:     taskHandler,
+		UserHandlers:     userHandler,
+		SSHKeyHandlers:   sshKeyHandler,
+		PlaybookHandlers: playbookHandler,
 	}
 
 	// Register routes
-	routes.RegisterRoutes(suite.App, instanceHandler, rpcHandler, taskHandler)
+	routes.RegisterRoutes(suite.App, instanceHandler, providerHandler, rpcHandler, taskHandler, webhookHandler)
 
 	// Create test server using adaptor to convert Fiber app to http.Handler
 	suite.Server = httptest.NewServer(adaptor.FiberApp(suite.App))
@@ -70,7 +85,9 @@ func SetupServer(suite *Suite) {
 	var wg sync.WaitGroup
 	wg.Add(1)
 	suite.workerWG = &wg
-	workerPool := services.NewWorkerPool(instanceService, projectService, taskService, userService, sshKeyService, 100*time.Millisecond)
+	fleetService := services.NewFleetService(repos.NewWorkerRepository(suite.DB))
+	workerPool := services.NewWorkerPool(instanceService, projectService, taskService, userService, sshKeyService, 100*time.Millisecond).
+		WithFleet(fleetService)
 	go workerPool.LaunchWorkerPool(suite.ctx, &wg)
 
 	// Update cleanup to close server